@@ -2,10 +2,13 @@
 
 import ast
 from abc import ABC, abstractmethod
-from typing import Any, Optional
+from typing import TYPE_CHECKING, Any, Optional
 
 from .preferences import BackendPreferences
 
+if TYPE_CHECKING:
+    from ..common.module_system import ModuleResolver
+
 
 class LanguageBackend(ABC):
     """Abstract base for all language backends in MGen."""
@@ -38,6 +41,13 @@ class LanguageBackend(ABC):
     def get_container_system(self) -> "AbstractContainerSystem":
         """Get language-specific container library integration."""
 
+    def get_module_filename(self, module_name: str) -> str:
+        """Path, relative to the output directory, of the source a project module converts to.
+
+        By default the module's dots become directories: pkg.shapes -> pkg/shapes.<ext>.
+        """
+        return module_name.replace(".", "/") + self.get_file_extension()
+
 
 class AbstractFactory(ABC):
     """Abstract factory for creating language-specific code elements."""
@@ -82,6 +92,17 @@ class AbstractEmitter(ABC):
     def can_use_simple_emission(self, func_node: ast.FunctionDef, type_context: dict[str, str]) -> bool:
         """Determine if function can use simple emission strategy."""
 
+    def emit_project_module(
+        self, source_code: str, analysis_result: Any, module_name: str, resolver: "ModuleResolver"
+    ) -> str:
+        """Generate one module of a project converted from a directory.
+
+        Modules are emitted after the project modules they import, which
+        resolver has discovered. By default each is a standalone translation
+        unit; backends that link modules together override this.
+        """
+        return self.emit_module(source_code, analysis_result)
+
 
 class AbstractBuilder(ABC):
     """Abstract builder for language-specific build systems."""
@@ -111,6 +132,18 @@ class AbstractBuilder(ABC):
     def get_compile_flags(self) -> list[str]:
         """Get compilation flags for the language."""
 
+    def generate_support_files(self, source_file: str, output_dir: str) -> list[str]:
+        """Write the files the generated source needs to build beside it (e.g. go.mod and the Go runtime).
+
+        Args:
+            source_file: Path to the generated source file
+            output_dir: Directory the source file was written to
+
+        Returns:
+            Paths of the files written; none by default
+        """
+        return []
+
 
 class AbstractContainerSystem(ABC):
     """Abstract container system for language-specific collections."""
@@ -400,6 +400,8 @@ class MGenPythonToCppConverter:
     def _convert_class(self, node: ast.ClassDef) -> str:
         """Convert a Python class to C++ class."""
         class_name = node.name
+        if any(ast.unparse(d).startswith(("dataclass", "dataclasses.dataclass")) for d in node.decorator_list):
+            raise UnsupportedFeatureError(f"Dataclasses are not supported in the C++ backend: {class_name}")
 
         # Extract instance variables and methods
         instance_vars = self._extract_instance_variables(node)
@@ -8,6 +8,7 @@ from .builder import GoBuilder
 from .containers import GoContainerSystem
 from .emitter import GoEmitter
 from .factory import GoFactory
+from .packages import package_filename
 
 
 class GoBackend(LanguageBackend):
@@ -27,6 +28,10 @@ class GoBackend(LanguageBackend):
         """Return Go source file extension."""
         return ".go"
 
+    def get_module_filename(self, module_name: str) -> str:
+        """Place each project module in its own Go package directory: pkg.shapes -> pkg/shapes/shapes.go."""
+        return package_filename(module_name)
+
     def get_factory(self) -> AbstractFactory:
         """Get Go code element factory."""
         return GoFactory()
@@ -37,7 +42,7 @@ class GoBackend(LanguageBackend):
 
     def get_builder(self) -> AbstractBuilder:
         """Get Go build system."""
-        return GoBuilder()
+        return GoBuilder(self.preferences)
 
     def get_container_system(self) -> AbstractContainerSystem:
         """Get Go container system."""
@@ -3,26 +3,42 @@
 import shutil
 import subprocess
 from pathlib import Path
-from typing import Any
+from typing import Any, Optional
 
 from ..base import AbstractBuilder
+from ..preferences import BackendPreferences, GoPreferences
+
+# The mgen runtime package, copied into each module as its mgen/ directory
+RUNTIME_DIR = Path(__file__).parent / "runtime"
 
 
 class GoBuilder(AbstractBuilder):
     """Go build system implementation."""
 
+    def __init__(self, preferences: Optional[BackendPreferences] = None):
+        """Initialize the Go builder with preferences (module_path and go_version go into go.mod)."""
+        self.preferences = preferences or GoPreferences()
+
     def get_build_filename(self) -> str:
         """Return go.mod as the build file name."""
         return "go.mod"
 
     def generate_build_file(self, source_files: list[str], target_name: str) -> str:
         """Generate go.mod for Go project."""
-        # Use a standard module name for all mgen projects
-        go_mod_content = """module mgenproject
+        module_path = self.preferences.get("module_path", "mgenproject")
+        go_version = self.preferences.get("go_version", "1.21")
+        return f"module {module_path}\n\ngo {go_version}\n"
 
-go 1.21
-"""
-        return go_mod_content
+    def generate_support_files(self, source_file: str, output_dir: str) -> list[str]:
+        """Make output_dir a Go module: write its go.mod and copy the mgen runtime into its mgen/ directory.
+
+        The generated code imports the runtime as <module_path>/mgen, so go build ./...
+        in output_dir then builds the program with no further setup.
+        """
+        out_dir = Path(output_dir)
+        go_mod_path = out_dir / "go.mod"
+        go_mod_path.write_text(self.generate_build_file([source_file], Path(source_file).stem))
+        return [str(go_mod_path), *self._copy_runtime(out_dir)]
 
     def compile_direct(self, source_file: str, output_dir: str, **kwargs: Any) -> bool:
         """Compile Go source directly using go build."""
@@ -51,14 +67,8 @@ go 1.21
             go_mod_content = self.generate_build_file([str(source_path)], executable_name)
             go_mod_path.write_text(go_mod_content)
 
-            # Copy runtime package if it exists
-            runtime_src = Path(__file__).parent / "runtime" / "mgen_go_runtime.go"
-            if runtime_src.exists():
-                # Create mgen package directory in Go build directory
-                mgen_pkg_dir = go_build_dir / "mgen"
-                mgen_pkg_dir.mkdir(exist_ok=True)
-                runtime_dst = mgen_pkg_dir / "mgen.go"
-                shutil.copy2(runtime_src, runtime_dst)
+            # Copy runtime package files
+            self._copy_runtime(go_build_dir)
 
             # Build go build command
             # Build the module (current directory) which includes our renamed source and runtime
@@ -82,6 +92,22 @@ go 1.21
             print(f"Go compilation exception: {e}")
             return False
 
+    def _copy_runtime(self, module_dir: Path) -> list[str]:
+        """Copy the mgen runtime (split across several .go files) into module_dir/mgen, returning the copies.
+
+        Go files left there by an earlier copy are removed first, so a runtime
+        file since renamed or dropped cannot break the build.
+        """
+        mgen_pkg_dir = module_dir / "mgen"
+        mgen_pkg_dir.mkdir(parents=True, exist_ok=True)
+        for stale in mgen_pkg_dir.glob("*.go"):
+            stale.unlink()
+        copies = []
+        for runtime_src in sorted(RUNTIME_DIR.glob("*.go")):
+            if not runtime_src.name.endswith("_test.go"):
+                copies.append(str(shutil.copy2(runtime_src, mgen_pkg_dir / runtime_src.name)))
+        return copies
+
     def get_compile_flags(self) -> list[str]:
         """Get Go compilation flags."""
         return ["-ldflags", "-s -w"]  # Strip debug info for smaller binaries
@@ -11,12 +11,12 @@ class GoContainerSystem(AbstractContainerSystem):
         return f"[]{element_type}"
 
     def get_dict_type(self, key_type: str, value_type: str) -> str:
-        """Get Go map type for key-value storage."""
-        return f"map[{key_type}]{value_type}"
+        """Get the insertion-ordered mgen.Dict type for key-value storage."""
+        return f"*mgen.Dict[{key_type}, {value_type}]"
 
     def get_set_type(self, element_type: str) -> str:
-        """Get Go map type for set storage (using map[T]bool pattern)."""
-        return f"map[{element_type}]bool"
+        """Get the insertion-ordered mgen.Set type for set storage."""
+        return f"*mgen.Set[{element_type}]"
 
     def generate_container_operations(self, container_type: str, operations: list[str]) -> str:
         """Generate Go container operations."""
@@ -25,8 +25,12 @@ class GoContainerSystem(AbstractContainerSystem):
         for op in operations:
             if op == "append" and "[]" in container_type:
                 operations_code.append("// slice = append(slice, item)")
+            elif op == "insert" and "mgen.Dict[" in container_type:
+                operations_code.append("// d.Set(key, value)")
             elif op == "insert" and "map[" in container_type:
                 operations_code.append("// m[key] = value")
+            elif op == "remove" and "mgen.Dict[" in container_type:
+                operations_code.append("// d.Delete(key)")
             elif op == "remove":
                 operations_code.append("// delete(m, key)")
 
@@ -1,38 +1,296 @@
 """Enhanced Go code emitter for MGen with comprehensive Python language support."""
 
 import ast
-from typing import Any, Optional
+import builtins
+import copy
+import json
+import re
+from typing import Any, Callable, Optional, Union
 
+from ...frontend.type_vars import TypeVarInfo, collect_type_vars, function_type_vars
 from ..converter_utils import (
     get_augmented_assignment_operator,
     get_standard_binary_operator,
     get_standard_comparison_operator,
 )
 from ..errors import TypeMappingError, UnsupportedFeatureError
+from ..preferences import BackendPreferences
 from ..type_inference_strategies import InferenceContext
+from .decorators import specialize_decorator
+from .int_precision import IntRanges, analyze_int_ranges, constant_int_value, fits_int64, outgrows_int64
+from .packages import guarded_main
+from .py2compat import rewrite_print_statements
+from .type_inference import (
+    BIG_INT_TYPE,
+    BYTES_TYPES,
+    DEFAULT_FACTORY_TYPES,
+    SET_MEMBER_TYPES,
+    SET_RESULT_METHODS,
+    bind_type_params,
+    codec_function,
+    constant_index,
+    deque_type_arg,
+    dict_type_args,
+    func_result_type,
+    go_dict_type,
+    go_set_type,
+    go_tuple_type,
+    hash_constructor,
+    is_type_name,
+    iterated_slice_type,
+    iterator_type_arg,
+    numeric_builtin_type,
+    set_type_arg,
+    substitute_type_params,
+    tuple_key_type,
+    tuple_type_args,
+)
+
+# Generated Go types satisfying mgen.Ordered, usable as sort keys by mgen.SortedBy
+ORDERED_TYPES = ("int", "float64", "string")
+
+# Slice element types whose Go == agrees with Python's, so membership can use mgen.SliceContains
+SCALAR_ITEM_TYPES = ("int", "float64", "string", "bool")
+
+# Go methods of mgen.Dict and mgen.PyDict by Python dict method and argument count
+DICT_METHODS = {
+    ("get", 2): "GetOr",
+    ("setdefault", 2): "SetDefault",
+    ("pop", 1): "Pop",
+    ("pop", 2): "PopOr",
+    ("popitem", 0): "PopItem",
+    ("items", 0): "Items",
+    ("keys", 0): "Keys",
+    ("values", 0): "Values",
+    ("update", 1): "Update",
+    ("clear", 0): "Clear",
+    ("copy", 0): "Copy",
+}
+
+# Calls whose module-level assignment declares a type rather than a variable (T = TypeVar("T"))
+TYPE_FACTORIES = ("TypeVar", "NewType", "ParamSpec", "TypeVarTuple", "namedtuple", "NamedTuple", "TypedDict")
+
+# List methods that change a list's length, which a Go slice only does by rebinding its header
+LIST_MUTATORS = frozenset({"append", "extend", "insert", "pop", "remove", "clear"})
+
+# Field names of mgen.Tuple2/Tuple3, in item order
+TUPLE_FIELDS = ("First", "Second", "Third")
+
+# The variable holding each record a comprehension iterates, by the record type's prefix
+RECORD_PARAMS = {"mgen.Pair[": "pair", "mgen.Triple[": "triple", "mgen.Tuple2[": "tuple", "mgen.KV[": "kv"}
+
+# Builtins that read their whole iterable argument before returning, so a generator expression passed to one
+# can be built as a list (see _consume_generator_arguments)
+GENERATOR_CONSUMERS = ("sum", "min", "max", "any", "all", "sorted", "list", "set", "tuple", "enumerate", "zip")
+
+# mgen.Set methods implementing the set operators (s | t -> s.Union(t)); s |= t uses the "Update" forms
+SET_OPERATOR_METHODS: dict[type, str] = {
+    ast.BitOr: "Union",
+    ast.BitAnd: "Intersection",
+    ast.Sub: "Difference",
+    ast.BitXor: "SymmetricDifference",
+}
+
+# mgen.PyInt methods implementing the int operators on ints that may outgrow int64 (a * b -> a.Mul(b))
+BIG_INT_METHODS: dict[type, str] = {
+    ast.Add: "Add",
+    ast.Sub: "Sub",
+    ast.Mult: "Mul",
+    ast.Div: "TrueDiv",
+    ast.FloorDiv: "FloorDiv",
+    ast.Mod: "Mod",
+    ast.Pow: "Pow",
+    ast.LShift: "Lsh",
+    ast.RShift: "Rsh",
+    ast.BitAnd: "And",
+    ast.BitOr: "Or",
+    ast.BitXor: "Xor",
+}
+
+# Dunder methods of user classes implementing the ordering operators, and the reflection each falls back to
+COMPARE_DUNDERS: dict[type, tuple[str, str]] = {
+    ast.Lt: ("__lt__", "__gt__"),
+    ast.LtE: ("__le__", "__ge__"),
+    ast.Gt: ("__gt__", "__lt__"),
+    ast.GtE: ("__ge__", "__le__"),
+}
+
+# Go result types of the dunder methods whose result Python fixes, for methods without an annotation
+DUNDER_RESULT_TYPES = {
+    "__str__": "string",
+    "__repr__": "string",
+    "__len__": "int",
+    "__contains__": "bool",
+    "__eq__": "bool",
+    "__hash__": "int",
+}
+
+# Statements a method converts itself (see _convert_method_statement); others go through _convert_statement
+METHOD_STATEMENTS = (
+    ast.Assign,
+    ast.AnnAssign,
+    ast.AugAssign,
+    ast.Return,
+    ast.If,
+    ast.Delete,
+    ast.Raise,
+    ast.Expr,
+    ast.Try,
+    ast.With,
+    ast.Match,
+)
+
+# Dunder methods of user classes implementing the arithmetic operators (a + b -> a.PyAdd(b))
+OPERATOR_DUNDERS: dict[type, str] = {
+    ast.Add: "__add__",
+    ast.Sub: "__sub__",
+    ast.Mult: "__mul__",
+}
 
 
 class MGenPythonToGoConverter:
     """Sophisticated Python-to-Go converter with comprehensive language support."""
 
-    def __init__(self) -> None:
-        """Initialize the converter."""
+    def __init__(self, preferences: Optional[BackendPreferences] = None) -> None:
+        """Initialize the converter.
+
+        The python_version preference selects the source dialect: 3 (the
+        default) or 2, which accepts print statements and makes / between two
+        ints floor division. The int_precision preference selects how ints
+        are represented: "native" (the default) keeps every int a Go int, which
+        wraps around at 64 bits, and "auto" makes the ints that may outgrow
+        int64 arbitrary-precision mgen.PyInt values (see int_precision.py).
+        The string_semantics preference selects what len(), indexing, slicing
+        and iteration count in a str: "codepoint" (the default) counts code
+        points as Python does, and "bytes" counts bytes, which is faster and
+        agrees with Python for projects whose strings are ASCII. With the
+        use_generics preference off, functions over TypeVars take and return
+        interface{} rather than becoming Go generic functions. The module_path
+        preference is the path of the Go module the code is built in (the
+        runtime is imported as <module_path>/mgen), and package_name the
+        package it declares: "main" (the default) for a program, whose main
+        function runs the if __name__ == "__main__" block (see
+        packages.guarded_main), or any other Go identifier for a library
+        package, which gets no main function. The generators preference
+        selects how generator functions and expressions run: "goroutine" (the
+        default) runs each body lazily on its own goroutine, and "eager" runs
+        the whole body on the first next() and buffers its values, which
+        starts no goroutine but never finishes an infinite generator.
+        """
+        self.python_version = preferences.get("python_version", 3) if preferences else 3
+        if self.python_version not in (2, 3):
+            raise ValueError(f"python_version must be 2 or 3, not {self.python_version!r}")
+        self.int_precision = preferences.get("int_precision", "native") if preferences else "native"
+        if self.int_precision not in ("native", "auto"):
+            raise ValueError(f"int_precision must be 'native' or 'auto', not {self.int_precision!r}")
+        self.string_semantics = preferences.get("string_semantics", "codepoint") if preferences else "codepoint"
+        if self.string_semantics not in ("codepoint", "bytes"):
+            raise ValueError(f"string_semantics must be 'codepoint' or 'bytes', not {self.string_semantics!r}")
+        self.use_generics = preferences.get("use_generics", True) if preferences else True
+        self.module_path = preferences.get("module_path", "mgenproject") if preferences else "mgenproject"
+        if not isinstance(self.module_path, str) or not re.fullmatch(r"[\w.~-]+(/[\w.~-]+)*", self.module_path):
+            raise ValueError(f"module_path must be a Go module path such as example.com/app, not {self.module_path!r}")
+        self.package_name = preferences.get("package_name", "main") if preferences else "main"
+        if not isinstance(self.package_name, str) or not re.fullmatch(r"[A-Za-z_]\w*", self.package_name):
+            raise ValueError(f"package_name must be a Go identifier, not {self.package_name!r}")
+        self.generators = preferences.get("generators", "goroutine") if preferences else "goroutine"
+        if self.generators not in ("goroutine", "eager"):
+            raise ValueError(f"generators must be 'goroutine' or 'eager', not {self.generators!r}")
         self.type_map = {
             "int": "int",
             "float": "float64",
             "bool": "bool",
             "str": "string",
+            "bytes": "mgen.PyBytes",
+            "bytearray": "*mgen.PyByteArray",
             "list": "[]int",  # Default to int elements for unsubscripted list
-            "dict": "map[int]int",  # Default to int keys/values for unsubscripted dict
-            "set": "map[int]bool",  # Default to int keys for unsubscripted set
+            "dict": "*mgen.Dict[int, int]",  # Default to int keys/values for unsubscripted dict
+            "set": "*mgen.Set[int]",  # Default to int members for unsubscripted set
+            "Template": "mgen.Template",  # string.Template
             "void": "",
             "None": "",
         }
         self.struct_info: dict[str, dict[str, Any]] = {}  # Track struct definitions for classes
+        self.namedtuples: dict[str, tuple[str, list[str]]] = {}  # namedtuple class -> (typename, field names)
+        self.class_names: set[str] = set()  # Classes the module defines, converted or not
+        self.class_interfaces: dict[str, str] = {}  # Subclassed class -> Go interface its values are stored as
+        self.interface_lists: dict[int, str] = {}  # id(list display) -> class interface its elements are stored as
+        self.super_methods: dict[str, dict[tuple[str, str], ast.FunctionDef]] = {}  # class -> super() targets copied
         self.current_function: Optional[str] = None  # Track current function context
         self.declared_vars: set[str] = set()  # Track declared variables in current function
         self.function_return_types: dict[str, str] = {}  # Track function return types
+        self.function_param_types: dict[str, list[str]] = {}  # Track function parameter types
         self.variable_types: dict[str, str] = {}  # Track variable types in current function scope
+        self.nested_vars: set[str] = set()  # Variables of the current function used with nested subscripts
+        self.append_map: dict[str, str] = {}  # List of the current function -> variable appended to it
+        self.loop_break_flags: list[Optional[str]] = []  # Break flag of each enclosing loop (for loop-else)
+        self.loop_counter = 0  # Numbers loop-else flags and string accumulators
+        self.string_accumulators: dict[str, str] = {}  # String variable -> accumulator of the enclosing loop
+        self.class_aliases: dict[str, str] = {}  # cls -> its class while converting a classmethod
+        self.method_owner: Optional[str] = None  # Class defining the method being converted (for super())
+        self.method_class: Optional[str] = None  # Receiver class of the method being converted
+        self.try_contexts: list[dict[str, Any]] = []  # Enclosing try statements (see _convert_try)
+        self.generator_item_type: Optional[str] = None  # Go type a generator function yields (see _convert_yield)
+        self.lambda_types: dict[int, str] = {}  # id(lambda) -> Go func type its context expects
+        self.cached_functions: dict[str, str] = {}  # @lru_cache function -> mgen.NewLRUCache call of its cache
+        self.decorators: dict[str, ast.FunctionDef] = {}  # Module functions applied as @decorators
+        self.function_nodes: dict[str, ast.FunctionDef] = {}  # Undecorated module functions (see _binds_arguments)
+        self.deque_values: dict[int, str] = {}  # id(list display or deque()) -> element type of the Deque it builds
+        self.dict_values: dict[int, str] = {}  # id(defaultdict(), Counter() or OrderedDict()) -> its variable's type
+        self.argument_types: dict[str, str] = {}  # argparse dest -> Go type of args.dest (see _collect_argument_types)
+        self.int_ranges = IntRanges()  # Ints that may outgrow int64 when int_precision is "auto"
+        self.type_vars: dict[str, TypeVarInfo] = {}  # Module-level TypeVars by name
+        self.generic_functions: dict[str, list[TypeVarInfo]] = {}  # Function -> the TypeVars it is generic over
+        self.type_params: dict[str, TypeVarInfo] = {}  # Type parameters of the function being converted
+        self.package_imports: dict[str, str] = {}  # Project packages the module calls: name -> import path
+        self.stdlib_imports: set[str] = set()  # Standard library packages the generated code calls (math.Pow)
+        self.global_types: dict[str, str] = {}  # Module-level variable -> Go type of its package-level var
+        self.global_vars: set[str] = set()  # Names the function being converted uses as module-level variables
+        self.reference_params: dict[str, set[str]] = {}  # Module function -> list parameters it takes by pointer
+        self.reference_lists: set[str] = set()  # List variables of the function being converted held by pointer
+        self.special_method_names = {
+            "__format__": "PyFormat",  # mgen.PyFormattable
+            "__str__": "PyStr",  # called by the String method of fmt.Stringer
+            "__repr__": "PyRepr",  # mgen.PyRepresentable
+            "__len__": "PyLen",  # mgen.PySized
+            "__add__": "PyAdd",
+            "__sub__": "PySub",
+            "__mul__": "PyMul",
+            "__lt__": "PyLt",
+            "__le__": "PyLe",
+            "__gt__": "PyGt",
+            "__ge__": "PyGe",
+            "__getitem__": "PyGetItem",
+            "__setitem__": "PySetItem",
+            "__contains__": "PyContains",
+            "__eq__": "PyEq",  # called by the Equals method of mgen.PyEquatable
+            "__hash__": "Hash",  # mgen.PyHashable
+            "__enter__": "PyEnter",  # called by with statements (see _convert_with)
+            "__exit__": "PyExit",
+            "readline": "ReadLine",  # mgen.PyFile
+            "readlines": "ReadLines",  # mgen.PyFile
+        }
+        # Keyword arguments accepted by the builtins lowered in call codegen; any other
+        # keyword is rejected at transpile time rather than silently dropped
+        self.builtin_keywords: dict[str, set[str]] = {
+            "print": {"sep", "end", "file", "flush"},
+            "min": {"key"},
+            "max": {"key"},
+            "open": {"mode"},
+            "int": {"base"},
+            "sorted": {"key", "reverse"},
+            "enumerate": {"start"},
+            "bytes": {"source", "encoding", "errors"},
+            "bytearray": {"source", "encoding", "errors"},
+            **{
+                name: set()
+                for name in (
+                    "len", "abs", "sum", "any", "all", "bool", "float", "str", "repr", "ascii", "ord", "chr",
+                    "format", "range", "list", "tuple", "set", "dict", "reversed", "zip", "getattr", "setattr",
+                    "map", "filter", "deque", "input", "hash",
+                )
+            },
+        }
         self._type_inference_engine: Optional[Any] = None  # Lazy-initialized type inference engine
 
     @property
@@ -62,76 +320,320 @@ class MGenPythonToGoConverter:
 
     def _to_go_method_name(self, method_name: str) -> str:
         """Convert Python method name to Go method name (proper CamelCase)."""
+        # Special methods implement runtime protocol interfaces (e.g. mgen.PyFormattable)
+        if method_name in self.special_method_names:
+            return self.special_method_names[method_name]
         # Handle special cases like get_increment -> GetIncrement
         return self._to_camel_case(method_name)
 
     def convert_code(self, python_code: str) -> str:
         """Convert Python code to Go."""
         try:
-            tree = ast.parse(python_code)
+            tree = self.parse_code(python_code)
+        except Exception as e:
+            raise TypeMappingError(f"Failed to convert Python code: {e}") from e
+        return self.convert_tree(tree)
+
+    def parse_code(self, python_code: str) -> ast.Module:
+        """Parse Python code in the dialect the python_version preference selects."""
+        if self.python_version == 2:
+            python_code = rewrite_print_statements(python_code)
+        return ast.parse(python_code)
+
+    def convert_tree(self, tree: ast.Module) -> str:
+        """Convert a parsed Python module to Go."""
+        try:
             return self._convert_module(tree)
         except Exception as e:
             raise TypeMappingError(f"Failed to convert Python code: {e}") from e
 
+    def declare_function(self, name: str, param_types: list[str], return_type: str, type_vars: list[Any]) -> None:
+        """Declare a function of another package (shapes.Area) that the module calls.
+
+        type_vars are the TypeVarInfos of a generic function, whose calls bind
+        them like calls to the module's own generic functions.
+        """
+        self.function_param_types[name] = param_types
+        self.function_return_types[name] = return_type
+        if type_vars:
+            self.generic_functions[name] = type_vars
+
     def _convert_module(self, node: ast.Module) -> str:
         """Convert a Python module to Go."""
         parts = []
+        if self.package_name == "main":
+            # A program's main function runs its if __name__ == "__main__" block
+            guarded_main(node)
 
         # Package declaration
-        parts.append("package main")
+        parts.append(f"package {self.package_name}")
         parts.append("")
 
-        # Imports
+        # Imports; the standard library packages the converted code calls are added once it is converted
         imports = self._collect_required_imports(node)
         for imp in imports:
             parts.append(f'import "{imp}"')
+        self.stdlib_imports = set()
+        stdlib_index = len(parts)
+        for ref, path in sorted(self.package_imports.items(), key=lambda item: item[1]):
+            # A package is named after the last element of its path unless imported under another name
+            parts.append(f'import "{path}"' if path.rsplit("/", 1)[-1] == ref else f'import {ref} "{path}"')
         parts.append("")
+        globals_index = len(parts)
 
-        # Convert classes first (they become struct definitions)
+        self._collect_argument_types(node)
+        self._consume_generator_arguments(node)
+        self.type_vars = collect_type_vars(node)
+        if self.int_precision == "auto":
+            self.int_ranges = analyze_int_ranges(node)
+
+        # Methods call module functions too, so their parameters are known before classes are converted
+        self.function_nodes = {
+            item.name: item for item in node.body if isinstance(item, ast.FunctionDef) and not item.decorator_list
+        }
+        # A list a function grows in place is passed by pointer, so the caller's list grows too
+        self._collect_reference_params()
+
+        # Methods and functions read module-level variables, so their types are known first
+        module_variables = self._module_variable_statements(node)
+        self._collect_global_types(module_variables)
+
+        # Convert classes first (they become struct definitions), namedtuples among them
+        self._collect_class_interfaces(node)
         for item in node.body:
+            item = self._namedtuple_class(item, node) or self._dataclass_class(item) or item
             if isinstance(item, ast.ClassDef):
                 struct_def = self._convert_class(item)
                 parts.append(struct_def)
                 parts.append("")
 
         # First pass: collect function return types
+        self.decorators = self._collect_decorators(node)
         for item in node.body:
-            if isinstance(item, ast.FunctionDef):
+            if isinstance(item, ast.FunctionDef) and item.name not in self.decorators:
+                # A function using TypeVars in its signature becomes a Go generic function
+                generic = self.use_generics and item.name != "main"
+                type_vars = function_type_vars(item, self.type_vars) if generic else []
+                if type_vars:
+                    self.generic_functions[item.name] = type_vars
+                self.type_params = {type_var.name: type_var for type_var in type_vars}
                 # Extract return type without converting the whole function
                 if item.name == "main":
                     self.function_return_types[item.name] = ""
+                elif self._is_generator(item):
+                    self.function_return_types[item.name] = f"mgen.Iterator[{self._generator_item_type(item)}]"
                 elif item.returns:
-                    mapped_type = self._map_type_annotation(item.returns)
+                    mapped_type = self._big_int_result(item.name, self._map_return_annotation(item.returns))
                     self.function_return_types[item.name] = mapped_type if mapped_type else ""
                 else:
                     # Default to int if no annotation
-                    self.function_return_types[item.name] = "int"
+                    self.function_return_types[item.name] = self._big_int_result(item.name, "int")
+                self.function_param_types[item.name] = [
+                    self._infer_parameter_type(arg, item) for arg in [*item.args.posonlyargs, *item.args.args]
+                ]
+                cache = self._cache_decorator(item)
+                if cache is not None:
+                    self.cached_functions[item.name] = cache
+                self.type_params = {}
+
+        # Variables assigned a module function's result take its return type
+        self._collect_global_types(module_variables)
+        module_vars = self._convert_module_variables(module_variables)
+        if module_vars:
+            parts[globals_index:globals_index] = [module_vars, ""]
 
         # Convert functions
         functions = []
         has_main = False
         for item in node.body:
             if isinstance(item, ast.FunctionDef):
+                if item.name in self.decorators:
+                    # Specialized for each function it decorates
+                    continue
                 if item.name == "main":
                     has_main = True
-                func_code = self._convert_function(item)
+                if any(isinstance(d, ast.Name) and d.id in self.decorators for d in item.decorator_list):
+                    func_code = self._convert_decorated_function(item)
+                else:
+                    func_code = self._convert_function(item)
                 functions.append(func_code)
 
         # Add functions to parts
         parts.extend(functions)
 
-        # Add main function if not present
-        if not has_main:
+        # Add main function if a program has none
+        if not has_main and self.package_name == "main":
             main_func = 'func main() {\n    mgen.Print("Generated Go code executed successfully")\n}'
             parts.append("")
             parts.append(main_func)
 
+        parts[stdlib_index:stdlib_index] = [f'import "{imp}"' for imp in sorted(self.stdlib_imports)]
         return "\n".join(parts)
 
+    def _module_variable_statements(self, node: ast.Module) -> list[ast.stmt]:
+        """Return the module-level statements binding or updating module variables, in order.
+
+        Assignments declaring types (TypeVars, namedtuples) and dunder names
+        such as __all__ are not variables. Method calls on module variables
+        (NAMES.append(x)) update them; other module-level statements are not
+        run.
+        """
+        statements: list[ast.stmt] = []
+        bound: set[str] = set()
+        for stmt in node.body:
+            if isinstance(stmt, ast.Expr) and isinstance(stmt.value, ast.Call):
+                owner = stmt.value.func
+                while isinstance(owner, (ast.Attribute, ast.Subscript)):
+                    owner = owner.value
+                if isinstance(stmt.value.func, ast.Attribute) and isinstance(owner, ast.Name) and owner.id in bound:
+                    statements.append(stmt)
+                continue
+            if isinstance(stmt, ast.AnnAssign):
+                if not isinstance(stmt.target, ast.Name) or stmt.value is None:
+                    continue
+                targets: list[ast.expr] = [stmt.target]
+            elif isinstance(stmt, ast.Assign):
+                if self._namedtuple_class(stmt, node) is not None:
+                    continue
+                func = stmt.value.func if isinstance(stmt.value, ast.Call) else None
+                if isinstance(func, ast.Name) and func.id in TYPE_FACTORIES:
+                    continue
+                if isinstance(func, ast.Attribute) and func.attr in TYPE_FACTORIES:
+                    continue
+                targets = stmt.targets
+            elif isinstance(stmt, ast.AugAssign):
+                targets = [stmt.target]
+            else:
+                continue
+            names = [n.id for target in targets for n in ast.walk(target) if isinstance(n, ast.Name)]
+            if any(name.startswith("__") and name.endswith("__") for name in names):
+                continue
+            bound.update(names)
+            statements.append(stmt)
+        return statements
+
+    def _collect_global_types(self, statements: list[ast.stmt]) -> None:
+        """Type the module-level variables: by annotation, else by the value first assigned."""
+        outer_types = self.variable_types
+        self.global_types = {}
+        self.variable_types = self.global_types
+        try:
+            for stmt in statements:
+                if isinstance(stmt, ast.AnnAssign) and isinstance(stmt.target, ast.Name):
+                    self.global_types.setdefault(stmt.target.id, self._map_type_annotation(stmt.annotation))
+                elif isinstance(stmt, ast.Assign):
+                    for target in stmt.targets:
+                        if isinstance(target, ast.Name):
+                            self.global_types.setdefault(target.id, self._global_value_type(stmt.value))
+                        elif isinstance(target, (ast.Tuple, ast.List)):
+                            for name, var_type in self._unpacked_types(target, stmt.value).items():
+                                self.global_types.setdefault(name, var_type)
+        finally:
+            self.variable_types = outer_types
+
+    def _global_value_type(self, value: ast.expr) -> str:
+        """Infer the type of a module-level variable's value.
+
+        Locals of arithmetic values are declared with := and take Go's type,
+        but a package-level var assigned in func init() is declared with its
+        type first, so operators are typed from their operands as well.
+        """
+        value_type = self._infer_type_from_value(value)
+        if value_type == "interface{}" and isinstance(value, (ast.BinOp, ast.UnaryOp, ast.Compare)):
+            return self._infer_comprehension_element_type(value, {})
+        return value_type
+
+    def _convert_module_variables(self, statements: list[ast.stmt]) -> str:
+        """Convert module-level variables to package-level vars, run in the order Python runs them.
+
+        Go initializes package-level vars before any init function, each after
+        the vars its initializer reads. A variable first assigned before any
+        module statement updates one becomes a var with that initializer; the
+        statements from the first update on (counter += 1, table[k] = v) run in
+        order in func init(), and variables they first assign are declared
+        with their zero value.
+
+        Example:
+            LIMIT = 10            →  var LIMIT int = 10
+            LIMIT += 5               var DOUBLE int
+            DOUBLE = LIMIT * 2       func init() {
+                                         LIMIT += 5
+                                         DOUBLE = (LIMIT * 2)
+                                     }
+        """
+        declarations: list[str] = []
+        init_statements: list[str] = []
+        self.declared_vars = set()
+        self.variable_types = dict(self.global_types)
+        self.global_vars = set(self.global_types)
+        try:
+            for stmt in statements:
+                target = stmt.targets[0] if isinstance(stmt, ast.Assign) and len(stmt.targets) == 1 else None
+                target = stmt.target if isinstance(stmt, ast.AnnAssign) else target
+                if not init_statements and isinstance(target, ast.Name) and target.id not in self.declared_vars:
+                    declaration = self._convert_statement(stmt).strip()
+                    # A package-level var cannot be declared with :=
+                    declaration = re.sub(r"^(\w+) := ", r"var \1 = ", declaration)
+                    if "\n" not in declaration and declaration.startswith("var "):
+                        declarations.append(declaration)
+                        continue
+                    self.declared_vars.discard(target.id)
+                if isinstance(stmt, ast.Assign):
+                    targets = stmt.targets
+                elif isinstance(stmt, (ast.AnnAssign, ast.AugAssign)):
+                    targets = [stmt.target]
+                else:
+                    targets = []  # A method call such as NAMES.append(x)
+                for name in [n.id for t in targets for n in ast.walk(t) if isinstance(n, ast.Name)]:
+                    if name in self.global_types and name not in self.declared_vars:
+                        declarations.append(f"var {name} {self.global_types[name] or 'interface{}'}")
+                        self.declared_vars.add(name)
+                init_statements.append(self._convert_statement(stmt))
+        finally:
+            self.declared_vars = set()
+            self.variable_types = {}
+            self.global_vars = set()
+        code = "\n".join(declarations)
+        if init_statements:
+            code += "\n\nfunc init() {\n" + "\n".join(init_statements) + "\n}"
+        return code
+
+    def _global_scope_types(self, node: ast.FunctionDef) -> dict[str, str]:
+        """Return the types of the module-level variables a function uses.
+
+        Those are the variables it declares global and those it reads
+        without binding a local of the same name.
+        """
+        declared = {name for n in ast.walk(node) if isinstance(n, ast.Global) for name in n.names}
+        bound = {arg.arg for arg in [*node.args.posonlyargs, *node.args.args, *node.args.kwonlyargs]}
+        bound |= {n.id for n in ast.walk(node) if isinstance(n, ast.Name) and isinstance(n.ctx, ast.Store)}
+        return {
+            name: var_type
+            for name, var_type in self.global_types.items()
+            if name in declared or name not in bound
+        }
+
+    def _enter_global_scope(self, node: ast.FunctionDef, top_level: bool) -> None:
+        """Give the function being converted the module-level variables it uses.
+
+        Names it declares global are declared already, so assigning them
+        assigns the package-level var rather than declaring a local. A
+        closure sees the enclosing function's module-level variables among
+        those it captures.
+        """
+        if top_level:
+            scope_types = self._global_scope_types(node)
+            self.variable_types.update(scope_types)
+            self.global_vars = set(scope_types)
+        for name in {name for n in ast.walk(node) if isinstance(n, ast.Global) for name in n.names}:
+            if name in self.global_types:
+                self.declared_vars.add(name)
+                self.variable_types[name] = self.global_types[name]
+
     def _collect_required_imports(self, node: ast.Module) -> list[str]:
         """Collect required imports based on code features."""
-        imports = ["mgenproject/mgen"]  # Always import our runtime
-        # All required functionality is in the mgen runtime package
+        imports = [f"{self.module_path}/mgen"]  # Always import our runtime
+        # Standard library packages are recorded in stdlib_imports as code calling them is generated
         return imports
 
     def _convert_class(self, node: ast.ClassDef) -> str:
@@ -142,15 +644,53 @@ class MGenPythonToGoConverter:
         init_method = None
         other_methods = []
 
+        class_functions: list[ast.FunctionDef] = []
+
         for item in node.body:
             if isinstance(item, ast.FunctionDef):
                 if item.name == "__init__":
                     init_method = item
+                elif self._class_function_kind(item) is not None:
+                    class_functions.append(item)
                 else:
                     other_methods.append(item)
 
+        # Single inheritance embeds the base struct, promoting its fields and methods
+        base_name = self._base_class(node)
+        base_info: dict[str, Any] = self.struct_info[base_name] if base_name else {}
+        match_args = self._class_match_args(node, base_info)
+        # Subclasses of built-in exceptions embed mgen.PyError instead
+        exception_base = self._builtin_exception_base(node)
+        if base_name and exception_base:
+            raise UnsupportedFeatureError(
+                f"Multiple inheritance is not supported: class {class_name}({base_name}, {exception_base})"
+            )
+
         # Build struct definition
         struct_lines = [f"type {class_name} struct {{"]
+        if base_name:
+            struct_lines.append(f"    {base_name}")
+        elif exception_base:
+            struct_lines.append("    mgen.PyError")
+        field_types: dict[str, str] = dict(base_info.get("field_types", {}))
+        inherited_fields = set(field_types)
+
+        # @property getters/setters become Get<Name>/Set<Name> methods, not fields
+        properties: dict[str, dict[str, Any]] = {
+            name: dict(prop) for name, prop in base_info.get("properties", {}).items()
+        }
+        for method in other_methods:
+            kind = self._property_kind(method)
+            if kind == "getter":
+                return_type = self._map_type_annotation(method.returns) if method.returns else "interface{}"
+                properties.setdefault(method.name, {"setter": False})["type"] = return_type
+            elif kind == "setter":
+                properties.setdefault(method.name, {"type": "interface{}"})["setter"] = True
+
+        # self.x = x takes the type of the __init__ parameter x
+        saved_variable_types = self.variable_types
+        if init_method:
+            self.variable_types = {arg.arg: param_type for arg, param_type in self._init_parameters(init_method)}
 
         if init_method:
             # Extract instance variables from __init__
@@ -161,34 +701,129 @@ class MGenPythonToGoConverter:
                             isinstance(target, ast.Attribute)
                             and isinstance(target.value, ast.Name)
                             and target.value.id == "self"
+                            and target.attr not in properties
                         ):
                             field_name = self._to_camel_case(target.attr)  # Capitalize for Go visibility
                             field_type = self._infer_type_from_assignment(stmt)
-                            struct_lines.append(f"    {field_name} {field_type}")
+                            field_types[target.attr] = field_type
+                            if target.attr not in inherited_fields:
+                                struct_lines.append(f"    {field_name} {field_type}")
                 elif isinstance(stmt, ast.AnnAssign):
                     if (
                         isinstance(stmt.target, ast.Attribute)
                         and isinstance(stmt.target.value, ast.Name)
                         and stmt.target.value.id == "self"
+                        and stmt.target.attr not in properties
                     ):
                         field_name = self._to_camel_case(stmt.target.attr)
                         field_type = self._map_type_annotation(stmt.annotation)
-                        struct_lines.append(f"    {field_name} {field_type}")
+                        field_types[stmt.target.attr] = field_type
+                        if stmt.target.attr not in inherited_fields:
+                            struct_lines.append(f"    {field_name} {field_type}")
+
+        # Property setters may create their backing field (self._width = value)
+        for method in other_methods:
+            if self._property_kind(method) != "setter":
+                continue
+            self.variable_types = {arg.arg: self._infer_parameter_type(arg, method) for arg in method.args.args[1:]}
+            for node in ast.walk(method):
+                if isinstance(node, ast.Assign):
+                    for target in node.targets:
+                        if (
+                            isinstance(target, ast.Attribute)
+                            and isinstance(target.value, ast.Name)
+                            and target.value.id == "self"
+                            and target.attr not in properties
+                            and target.attr not in field_types
+                        ):
+                            field_types[target.attr] = self._infer_type_from_assignment(node)
+                            struct_lines.append(f"    {self._to_camel_case(target.attr)} {field_types[target.attr]}")
+        if init_method:
+            self.variable_types = {arg.arg: param_type for arg, param_type in self._init_parameters(init_method)}
 
         struct_lines.append("}")
 
-        # Store struct info for method generation
-        self.struct_info[class_name] = {"fields": self._extract_struct_fields(init_method) if init_method else []}
+        # Store struct info for method generation; a class without __init__ inherits its base's
+        self.struct_info[class_name] = {
+            "fields": self._extract_struct_fields(init_method) if init_method else [],
+            "field_types": field_types,
+            "base": base_name,
+            "exception": exception_base is not None or base_info.get("exception", False),
+            "init": init_method or base_info.get("init"),
+            "init_owner": class_name if init_method else base_info.get("init_owner"),
+            "properties": properties,
+            "match_args": match_args,
+            "methods": {
+                **base_info.get("methods", {}),
+                **{
+                    method.name: {"owner": class_name, "node": method}
+                    for method in other_methods
+                    if self._property_kind(method) is None
+                },
+            },
+            "class_functions": {},
+        }
+        # Recorded once the class is known, so -> Date maps to the struct. Inherited
+        # staticmethods are shared with the base; inherited classmethods are generated
+        # again for this class so that cls(...) constructs it.
+        class_function_table = self.struct_info[class_name]["class_functions"]
+        for name, entry in base_info.get("class_functions", {}).items():
+            if entry["kind"] == "classmethod":
+                return_type = entry["return_type"]
+                if return_type in (entry["defining"], self.class_interfaces.get(entry["defining"])):
+                    return_type = self.class_interfaces.get(class_name, class_name)
+                entry = {**entry, "owner": class_name, "return_type": return_type}
+            class_function_table[name] = entry
+        for function in class_functions:
+            class_function_table[function.name] = {
+                "kind": self._class_function_kind(function),
+                "return_type": self._map_type_annotation(function.returns) if function.returns else "interface{}",
+                "owner": class_name,
+                "defining": class_name,
+                "node": function,
+            }
 
         # Generate constructor
         constructor_lines = []
         if init_method:
+            if init_method.args.kwonlyargs:
+                constructor_lines.append(self._convert_constructor_options(class_name, init_method))
             constructor_lines.append(self._convert_constructor(class_name, init_method))
+        else:
+            constructor_lines.extend(self._convert_inherited_constructor(class_name))
+        self.variable_types = saved_variable_types
 
-        # Generate methods
+        if base_name in self.class_interfaces:
+            self._check_overrides(class_name, other_methods)
+
+        # Generate methods, regenerating inherited ones whose self calls reach an override
         method_lines = []
         for method in other_methods:
             method_lines.append(self._convert_method(class_name, method))
+        for owner, method in self._redispatched_methods(class_name):
+            method_lines.append(self._convert_method(class_name, method, owner))
+        # super().speak() calls reaching an override go to a copy of the base method (see _convert_super_call)
+        super_methods = self.super_methods.setdefault(class_name, {})
+        generated: set[tuple[str, str]] = set()
+        while len(generated) < len(super_methods):
+            for owner, name in [key for key in super_methods if key not in generated]:
+                generated.add((owner, name))
+                go_name = self._super_method_name(owner, name)
+                method_lines.append(self._convert_method(class_name, super_methods[(owner, name)], owner, go_name))
+
+        # Generate classmethods/staticmethods as package-level functions
+        function_lines = []
+        own_functions = [entry for entry in class_function_table.values() if entry["owner"] == class_name]
+        for entry in own_functions:
+            try:
+                function_lines.append(self._convert_class_function(class_name, entry["node"], entry["defining"]))
+            except TypeMappingError as e:
+                if entry["defining"] == class_name:
+                    raise
+                # e.g. cls(name) missing a subclass __init__ argument: Python fails only when it is called
+                entry["error"] = str(e)
+        if any(entry["kind"] == "classmethod" and "error" not in entry for entry in own_functions):
+            function_lines.insert(0, f'var {class_name}Class = mgen.ClassRef{{Name: "{class_name}"}}')
 
         # Combine all parts
         result_parts = ["\n".join(struct_lines)]
@@ -196,32 +831,1012 @@ class MGenPythonToGoConverter:
             result_parts.extend(constructor_lines)
         if method_lines:
             result_parts.extend(method_lines)
+        if function_lines:
+            result_parts.extend(function_lines)
+        if class_name in self.class_interfaces:
+            result_parts.append(self._convert_class_interface(class_name))
+        if properties:
+            result_parts.append(self._convert_property_registration(class_name, properties))
+        if self.struct_info[class_name]["exception"]:
+            result_parts.extend(self._convert_exception_registration(class_name, exception_base or base_name or ""))
+        methods = self.struct_info[class_name]["methods"]
+        if "__eq__" in methods:
+            result_parts.append(self._convert_equals(class_name))
+        if "__str__" in methods or "__repr__" in methods:
+            result_parts.append(self._convert_stringer(class_name))
+        elif class_name in self.namedtuples:
+            result_parts.append(self._convert_namedtuple_string(class_name))
 
         return "\n\n".join(result_parts)
 
+    def _collect_class_interfaces(self, node: ast.Module) -> None:
+        """Name the module's classes, and the interfaces of those other classes derive from.
+
+        Known before any class is converted, so a method can refer to classes
+        defined after its own (isinstance(self, Square) in Shape). Exception
+        classes are matched by the runtime's exception hierarchy instead.
+        """
+        classes = [item for item in node.body if isinstance(item, ast.ClassDef)]
+        self.class_names = {item.name for item in classes}
+        exceptions: set[str] = set()
+        for item in classes:
+            bases = {base.id for base in item.bases if isinstance(base, ast.Name)}
+            if self._builtin_exception_base(item) or bases & exceptions:
+                exceptions.add(item.name)
+        subclassed = {base.id for item in classes for base in item.bases if isinstance(base, ast.Name)}
+        self.class_interfaces = {
+            name: f"{name}Interface" for name in sorted(subclassed & self.class_names - exceptions)
+        }
+
+    def _namedtuple_class(self, node: ast.stmt, module: ast.Module) -> Optional[ast.ClassDef]:
+        """Return the class a namedtuple definition stands for, or None for other statements.
+
+        Both spellings become a class whose __init__ takes the fields in order
+        (with their defaults) and assigns them, so a namedtuple converts to a
+        struct like any other class:
+
+            Point = namedtuple("Point", "x y")
+
+            class Point(NamedTuple):
+                x: int
+                y: int = 0
+
+        collections.namedtuple declares no field types: a field takes the type
+        of the constants every Point(...) call passes it, else interface{}.
+        """
+        if isinstance(node, ast.ClassDef) and any(
+            (isinstance(base, ast.Name) and base.id == "NamedTuple")
+            or (isinstance(base, ast.Attribute) and base.attr == "NamedTuple")
+            for base in node.bases
+        ):
+            fields = [
+                stmt for stmt in node.body if isinstance(stmt, ast.AnnAssign) and isinstance(stmt.target, ast.Name)
+            ]
+            names = [field.target.id for field in fields if isinstance(field.target, ast.Name)]
+            annotations: list[Optional[ast.expr]] = [field.annotation for field in fields]
+            defaults = [field.value for field in fields if field.value is not None]
+            methods = [stmt for stmt in node.body if isinstance(stmt, ast.FunctionDef)]
+            return self._record_class(node, node.name, node.name, names, annotations, defaults, methods)
+
+        if not (
+            isinstance(node, ast.Assign)
+            and len(node.targets) == 1
+            and isinstance(node.targets[0], ast.Name)
+            and isinstance(node.value, ast.Call)
+            and (
+                (isinstance(node.value.func, ast.Name) and node.value.func.id == "namedtuple")
+                or (isinstance(node.value.func, ast.Attribute) and node.value.func.attr == "namedtuple")
+            )
+        ):
+            return None
+        call = node.value
+        class_name = node.targets[0].id
+        typename, spec = (call.args + [None, None])[:2]
+        if not (isinstance(typename, ast.Constant) and isinstance(typename.value, str)):
+            raise UnsupportedFeatureError(f"namedtuple() needs a constant type name: {ast.unparse(call)}")
+        if isinstance(spec, ast.Constant) and isinstance(spec.value, str):
+            names = spec.value.replace(",", " ").split()
+        elif isinstance(spec, (ast.List, ast.Tuple)) and all(
+            isinstance(elt, ast.Constant) and isinstance(elt.value, str) for elt in spec.elts
+        ):
+            names = [elt.value for elt in spec.elts if isinstance(elt, ast.Constant)]
+        else:
+            raise UnsupportedFeatureError(f"namedtuple() needs constant field names: {ast.unparse(call)}")
+        defaults = []
+        for keyword in call.keywords:
+            if keyword.arg != "defaults" or not isinstance(keyword.value, (ast.List, ast.Tuple)):
+                raise UnsupportedFeatureError(f"Unsupported namedtuple() argument: {ast.unparse(keyword)}")
+            defaults = keyword.value.elts
+        annotations = self._namedtuple_field_annotations(class_name, names, module)
+        return self._record_class(node, class_name, typename.value, names, annotations, defaults, [])
+
+    def _namedtuple_field_annotations(
+        self, class_name: str, names: list[str], module: ast.Module
+    ) -> list[Optional[ast.expr]]:
+        """Annotate each field of a collections.namedtuple with the type of the constants passed for it.
+
+        Fields given ints and floats are float; fields given anything else,
+        or constants of different types, stay unannotated.
+        """
+        passed: dict[str, set[str]] = {name: set() for name in names}
+        for call in ast.walk(module):
+            if not (isinstance(call, ast.Call) and isinstance(call.func, ast.Name) and call.func.id == class_name):
+                continue
+            arguments = list(zip(names, call.args)) + [(kw.arg, kw.value) for kw in call.keywords if kw.arg in passed]
+            for name, arg in arguments:
+                value = arg.value if isinstance(arg, ast.Constant) else None
+                if isinstance(arg, ast.Constant) and type(value) in (int, float, str, bool):
+                    passed[name].add(type(value).__name__)
+                else:
+                    passed[name].add("")
+        annotations: list[Optional[ast.expr]] = []
+        for name in names:
+            types = passed[name] - {"int"} if passed[name] == {"int", "float"} else passed[name]
+            known = len(types) == 1 and "" not in types
+            annotations.append(ast.Name(id=types.pop(), ctx=ast.Load()) if known else None)
+        return annotations
+
+    def _record_class(
+        self,
+        node: ast.stmt,
+        class_name: str,
+        typename: str,
+        names: list[str],
+        annotations: list[Optional[ast.expr]],
+        defaults: list[ast.expr],
+        methods: list[ast.FunctionDef],
+    ) -> ast.ClassDef:
+        """Build the class of a namedtuple: an __init__ assigning each field, and the given methods."""
+        self.namedtuples[class_name] = (typename, names)
+        init = ast.FunctionDef(
+            name="__init__",
+            args=ast.arguments(
+                posonlyargs=[],
+                args=[ast.arg(arg="self")]
+                + [ast.arg(arg=name, annotation=annotation) for name, annotation in zip(names, annotations)],
+                kwonlyargs=[],
+                kw_defaults=[],
+                defaults=defaults,
+            ),
+            body=[
+                ast.Assign(
+                    targets=[ast.Attribute(value=ast.Name(id="self", ctx=ast.Load()), attr=name, ctx=ast.Store())],
+                    value=ast.Name(id=name, ctx=ast.Load()),
+                )
+                for name in names
+            ],
+            decorator_list=[],
+            returns=None,
+        )
+        body = [self._match_args_assignment(names), init, *methods]
+        class_def = ast.ClassDef(name=class_name, bases=[], keywords=[], body=body, decorator_list=[])
+        return ast.fix_missing_locations(ast.copy_location(class_def, node))
+
+    def _match_args_assignment(self, names: list[str]) -> ast.Assign:
+        """Build __match_args__ = (names...), which namedtuples and dataclasses define for class patterns."""
+        elts: list[ast.expr] = [ast.Constant(value=name) for name in names]
+        return ast.Assign(
+            targets=[ast.Name(id="__match_args__", ctx=ast.Store())], value=ast.Tuple(elts=elts, ctx=ast.Load())
+        )
+
+    def _dataclass_class(self, node: ast.stmt) -> Optional[ast.ClassDef]:
+        """Return the class a @dataclass stands for, with its generated methods, or None for other statements.
+
+        The fields are the annotated class attributes, ClassVars aside. The
+        generated __init__ takes the init fields in order with their defaults,
+        which constructors bind at the call site (see _bind_init_arguments), so
+        a default_factory is called for each instance as in Python; it ends by
+        calling __post_init__. __eq__ compares the compare fields as tuples,
+        order=True adds __lt__, __le__, __gt__ and __ge__ comparing them the
+        same way, and __repr__ prints the repr fields like Point(x=1, y=2).
+        Methods the class defines itself take the place of generated ones.
+
+        Example:
+            @dataclass(order=True)          class Point:
+            class Point:                        def __init__(self, x: int, y: int = 0):
+                x: int                  →           self.x: int = x
+                y: int = 0                          self.y: int = y
+                                                def __eq__(self, other: "Point") -> bool:
+                                                    return (self.x, self.y) == (other.x, other.y)
+                                                ...
+        """
+        if not isinstance(node, ast.ClassDef):
+            return None
+        decorator = next(
+            (
+                d
+                for d in node.decorator_list
+                if ast.unparse(d.func if isinstance(d, ast.Call) else d) in ("dataclass", "dataclasses.dataclass")
+            ),
+            None,
+        )
+        if decorator is None:
+            return None
+        options = {"init": True, "repr": True, "eq": True, "order": False}
+        keywords = decorator.keywords if isinstance(decorator, ast.Call) else []
+        for keyword in keywords:
+            value = keyword.value
+            if keyword.arg not in options or not (isinstance(value, ast.Constant) and isinstance(value.value, bool)):
+                raise UnsupportedFeatureError(f"Unsupported dataclass() argument: {ast.unparse(keyword)}")
+            options[keyword.arg] = value.value
+        if options["order"] and not options["eq"]:
+            raise TypeMappingError("ValueError: eq must be true if order is true")
+        if node.bases or len(node.decorator_list) > 1:
+            raise UnsupportedFeatureError(
+                f"Dataclasses with base classes or other decorators are not supported: {node.name}"
+            )
+
+        fields: list[dict[str, Any]] = []
+        body: list[ast.stmt] = []
+        for stmt in node.body:
+            if not isinstance(stmt, ast.AnnAssign) or not isinstance(stmt.target, ast.Name):
+                body.append(stmt)
+                continue
+            if "ClassVar" in ast.unparse(stmt.annotation):
+                raise UnsupportedFeatureError(f"ClassVar fields are not supported: {node.name}.{stmt.target.id}")
+            fields.append(self._dataclass_field(stmt))
+        defined = {stmt.name for stmt in body if isinstance(stmt, ast.FunctionDef)}
+
+        def self_field(name: str, ctx: ast.expr_context = ast.Load()) -> ast.Attribute:
+            return ast.Attribute(value=ast.Name(id="self", ctx=ast.Load()), attr=name, ctx=ctx)
+
+        def method(
+            name: str, params: list[ast.arg], returns: Optional[ast.expr], stmts: list[ast.stmt]
+        ) -> ast.FunctionDef:
+            arguments = ast.arguments(
+                posonlyargs=[], args=[ast.arg(arg="self"), *params], kwonlyargs=[], kw_defaults=[], defaults=[]
+            )
+            return ast.FunctionDef(name=name, args=arguments, body=stmts, decorator_list=[], returns=returns)
+
+        generated: list[ast.FunctionDef] = []
+        if options["init"] and "__init__" not in defined:
+            params = [field for field in fields if field["init"]]
+            for previous, field in zip(params, params[1:]):
+                if previous["default"] is not None and field["default"] is None:
+                    raise TypeMappingError(
+                        f"TypeError: non-default argument '{field['name']}' follows default argument"
+                    )
+            init = method(
+                "__init__",
+                [ast.arg(arg=field["name"], annotation=field["annotation"]) for field in params],
+                None,
+                [
+                    ast.AnnAssign(
+                        target=self_field(field["name"], ast.Store()),
+                        annotation=field["annotation"],
+                        value=ast.Name(id=field["name"], ctx=ast.Load()) if field["init"] else field["default"],
+                        simple=0,
+                    )
+                    for field in fields
+                    if field["init"] or field["default"] is not None
+                ],
+            )
+            init.args.defaults = [field["default"] for field in params if field["default"] is not None]
+            if "__post_init__" in defined:
+                call = ast.Call(func=self_field("__post_init__"), args=[], keywords=[])
+                init.body.append(ast.Expr(value=call))
+            generated.append(init)
+        if options["repr"] and "__repr__" not in defined:
+            shown = [field["name"] for field in fields if field["repr"]]
+            label = f"{node.name}("
+            parts: list[ast.expr] = []
+            for i, name in enumerate(shown):
+                label += f"{', ' if i else ''}{name}="
+                value = ast.Call(func=ast.Name(id="repr", ctx=ast.Load()), args=[self_field(name)], keywords=[])
+                parts += [ast.Constant(value=label), value]
+                label = ""
+            parts.append(ast.Constant(value=label + ")"))
+            text = parts[0]
+            for part in parts[1:]:
+                text = ast.BinOp(left=text, op=ast.Add(), right=part)
+            generated.append(method("__repr__", [], ast.Name(id="str", ctx=ast.Load()), [ast.Return(value=text)]))
+
+        compared = [field["name"] for field in fields if field["compare"]]
+
+        def compare(name: str, op: ast.cmpop) -> ast.FunctionDef:
+            """Compare the fields of self and other as tuples, as dataclasses does."""
+            sides = [
+                ast.Tuple(
+                    elts=[
+                        ast.Attribute(value=ast.Name(id=obj, ctx=ast.Load()), attr=attr, ctx=ast.Load())
+                        for attr in compared
+                    ],
+                    ctx=ast.Load(),
+                )
+                for obj in ("self", "other")
+            ]
+            other = ast.arg(arg="other", annotation=ast.Constant(value=node.name))
+            result = ast.Compare(left=sides[0], ops=[op], comparators=[sides[1]])
+            return method(name, [other], ast.Name(id="bool", ctx=ast.Load()), [ast.Return(value=result)])
+
+        if options["eq"] and "__eq__" not in defined:
+            generated.append(compare("__eq__", ast.Eq()))
+        if options["order"]:
+            for name, op in (("__lt__", ast.Lt()), ("__le__", ast.LtE()), ("__gt__", ast.Gt()), ("__ge__", ast.GtE())):
+                if name in defined:
+                    raise TypeMappingError(
+                        f"TypeError: Cannot overwrite attribute {name} in class {node.name}. "
+                        "Consider using functools.total_ordering"
+                    )
+                generated.append(compare(name, op))
+
+        # A __match_args__ the class assigns itself comes later and replaces this one
+        body.insert(0, self._match_args_assignment([field["name"] for field in fields if field["init"]]))
+        class_def = ast.ClassDef(name=node.name, bases=[], keywords=[], body=[*generated, *body], decorator_list=[])
+        return ast.fix_missing_locations(ast.copy_location(class_def, node))
+
+    def _dataclass_field(self, stmt: ast.AnnAssign) -> dict[str, Any]:
+        """Return the name, annotation, default and init/repr/compare flags of a dataclass field.
+
+        A default_factory becomes a call of the factory, the default each
+        instance is constructed with.
+        """
+        assert isinstance(stmt.target, ast.Name)
+        name = stmt.target.id
+        field: dict[str, Any] = {
+            "name": name,
+            "annotation": stmt.annotation,
+            "default": stmt.value,
+            "init": True,
+            "repr": True,
+            "compare": True,
+        }
+        value = stmt.value
+        if isinstance(value, (ast.List, ast.Dict, ast.Set)):
+            kind = type(value).__name__.lower()
+            raise TypeMappingError(
+                f"ValueError: mutable default <class '{kind}'> for field {name} is not allowed: use default_factory"
+            )
+        if not (isinstance(value, ast.Call) and ast.unparse(value.func) in ("field", "dataclasses.field")):
+            return field
+        field["default"] = None
+        options = {keyword.arg: keyword.value for keyword in value.keywords}
+        if value.args or not set(options) <= {"default", "default_factory", "init", "repr", "compare"}:
+            raise UnsupportedFeatureError(f"Unsupported field() arguments: {ast.unparse(value)}")
+        if "default" in options and "default_factory" in options:
+            raise TypeMappingError("ValueError: cannot specify both default and default_factory")
+        if "default" in options:
+            field["default"] = options.pop("default")
+        if "default_factory" in options:
+            field["default"] = ast.Call(func=options.pop("default_factory"), args=[], keywords=[])
+        for flag, flag_value in options.items():
+            if not (isinstance(flag_value, ast.Constant) and isinstance(flag_value.value, bool)):
+                raise UnsupportedFeatureError(f"field() {flag} must be True or False: {ast.unparse(value)}")
+            field[flag] = flag_value.value
+        return field
+
+    def _class_match_args(self, node: ast.ClassDef, base_info: dict[str, Any]) -> list[str]:
+        """Return the attributes positional class patterns match: the class's __match_args__, or its base's."""
+        match_args = base_info.get("match_args", [])
+        for stmt in node.body:
+            if not isinstance(stmt, ast.Assign) or [ast.unparse(t) for t in stmt.targets] != ["__match_args__"]:
+                continue
+            value = stmt.value
+            if not (
+                isinstance(value, ast.Tuple)
+                and all(isinstance(elt, ast.Constant) and isinstance(elt.value, str) for elt in value.elts)
+            ):
+                raise UnsupportedFeatureError(
+                    f"__match_args__ must be a tuple of attribute names: {ast.unparse(value)}"
+                )
+            match_args = [elt.value for elt in value.elts if isinstance(elt, ast.Constant)]
+        return match_args
+
+    def _convert_stringer(self, class_name: str) -> str:
+        """Generate the String method of a class defining __str__ or __repr__, for fmt.Stringer.
+
+        It has a value receiver, so that print(p) and str(p) find it on a
+        struct value as well as on a pointer. Without __str__, str() uses
+        __repr__ as in Python; repr() itself finds PyRepr (mgen.PyRepresentable).
+        Subclasses get their own, since a promoted String would call the
+        base's PyStr rather than an override.
+
+        Example:
+            func (obj Point) String() string {
+                return obj.PyRepr()
+            }
+        """
+        method = "__str__" if "__str__" in self.struct_info[class_name]["methods"] else "__repr__"
+        return f"func (obj {class_name}) String() string {{\n    return obj.{self._to_go_method_name(method)}()\n}}"
+
+    def _convert_equals(self, class_name: str) -> str:
+        """Generate the Equals method of a class defining __eq__, for mgen.PyEquatable.
+
+        Dicts, sets and mgen.Eq compare values of any type with it, so it
+        calls __eq__ only for an instance of the type __eq__ accepts and is
+        false for anything else, as Python's NotImplemented fallback would be.
+
+        Example:
+            func (obj *Point) Equals(other interface{}) bool {
+                if o, ok := mgen.AsInstance[Point](other); ok {
+                    return obj.PyEq(o)
+                }
+                return false
+            }
+        """
+        method = self.struct_info[class_name]["methods"]["__eq__"]["node"]
+        param_types = self._method_types(method)[0]
+        if len(param_types) != 1:
+            raise TypeMappingError(f"TypeError: __eq__() takes 2 positional arguments but {len(param_types) + 1} were given")
+        go_name = self._to_go_method_name("__eq__")
+        if param_types[0] == "interface{}":
+            body = f"    return obj.{go_name}(other)"
+        else:
+            body = (
+                f"    if o, ok := mgen.AsInstance[{param_types[0]}](other); ok {{\n"
+                f"        return obj.{go_name}(o)\n"
+                "    }\n"
+                "    return false"
+            )
+        return f"func (obj *{class_name}) Equals(other interface{{}}) bool {{\n{body}\n}}"
+
+    def _convert_namedtuple_string(self, class_name: str) -> str:
+        """Generate the String method printing a namedtuple like Python: Point(x=1, y=2)."""
+        typename, names = self.namedtuples[class_name]
+        body = f'"{typename}('
+        for i, name in enumerate(names):
+            body += f'{", " if i else ""}{name}=" + mgen.Repr(obj.{self._to_camel_case(name)}) + "'
+        body += ')"'
+        return f"func (obj {class_name}) String() string {{\n    return {body}\n}}"
+
+    def _record_field_types(self, go_type: str) -> Optional[list[str]]:
+        """Field types of a tuple record or namedtuple struct, in order, or None for other types."""
+        fields = self._record_fields(go_type)
+        return list(fields[1]) if fields is not None else None
+
+    def _namedtuple_fields(self, go_type: str) -> Optional[tuple[tuple[str, ...], tuple[str, ...]]]:
+        """Field names and types of a namedtuple struct, or None for other types."""
+        if go_type not in self.namedtuples:
+            return None
+        names = self.namedtuples[go_type][1]
+        field_types = self.struct_info[go_type]["field_types"]
+        return tuple(self._to_camel_case(name) for name in names), tuple(field_types[name] for name in names)
+
+    def _base_class(self, node: ast.ClassDef) -> Optional[str]:
+        """Return the generated class a class inherits from, or None.
+
+        Bases that are not classes of this module (object, NamedTuple, ...) are
+        not embedded. Multiple inheritance needs Python's MRO, which struct
+        embedding cannot express, so more than one generated base is rejected.
+        """
+        bases = [base.id for base in node.bases if isinstance(base, ast.Name) and base.id in self.struct_info]
+        if len(bases) > 1:
+            raise UnsupportedFeatureError(
+                f"Multiple inheritance is not supported: class {node.name}({', '.join(bases)})"
+            )
+        return bases[0] if bases else None
+
+    def _builtin_exception_base(self, node: ast.ClassDef) -> Optional[str]:
+        """Return the built-in exception class (Exception, ValueError, ...) a class derives from, or None."""
+        for base in node.bases:
+            if isinstance(base, ast.Name) and base.id not in self.struct_info:
+                builtin = getattr(builtins, base.id, None)
+                if isinstance(builtin, type) and issubclass(builtin, BaseException):
+                    return base.id
+        return None
+
+    def _is_exception_class(self, name: str) -> bool:
+        """Check whether name is a generated exception class."""
+        return bool(self.struct_info.get(name, {}).get("exception"))
+
+    def _convert_exception_registration(self, class_name: str, parent: str) -> list[str]:
+        """Register an exception class in the runtime hierarchy and add its handler accessor.
+
+        except clauses recover a pointer to the raised (possibly derived)
+        class; As{class_name} reaches the embedded {class_name} inside it.
+        """
+        return [
+            f"func (obj *{class_name}) As{class_name}() *{class_name} {{\n    return obj\n}}",
+            f'func init() {{\n    mgen.RegisterException("{class_name}", "{parent}")\n}}',
+        ]
+
+    def _embedded_path(self, class_name: str, ancestor: str) -> list[str]:
+        """Return the embedded struct names leading from class_name to ancestor."""
+        path = []
+        while class_name != ancestor:
+            class_name = self.struct_info[class_name]["base"]
+            path.append(class_name)
+        return path
+
+    def _redispatched_methods(self, class_name: str) -> list[tuple[str, ast.FunctionDef]]:
+        """Return the inherited (owner, method) pairs to generate again for class_name.
+
+        Promoted methods run with the embedded base as receiver, so a self.speak()
+        inside them would call the base's speak even on a subclass that overrides
+        it. Inherited methods calling a method the subclass resolves differently
+        (directly or through another such method), or passing self on as a
+        value, get a copy with the subclass receiver.
+        """
+        methods = self.struct_info[class_name]["methods"]
+        inherited = {name: info for name, info in methods.items() if info["owner"] != class_name}
+        redispatched: set[str] = set()
+        changed = True
+        while changed:
+            changed = False
+            for name, info in inherited.items():
+                if name not in redispatched and self._dispatches_differently(class_name, info, redispatched):
+                    redispatched.add(name)
+                    changed = True
+        return [(inherited[name]["owner"], inherited[name]["node"]) for name in inherited if name in redispatched]
+
+    def _dispatches_differently(self, class_name: str, info: dict[str, Any], redispatched: set[str]) -> bool:
+        """Check whether a method of info["owner"] behaves differently with a class_name receiver.
+
+        It does when it passes self on as a value, or calls a method class_name
+        resolves differently or one already in redispatched.
+        """
+        if self._passes_self(info["node"]):
+            return True
+        methods = self.struct_info[class_name]["methods"]
+        owner_methods = self.struct_info[info["owner"]]["methods"]
+        for node in ast.walk(info["node"]):
+            if (
+                isinstance(node, ast.Call)
+                and isinstance(node.func, ast.Attribute)
+                and isinstance(node.func.value, ast.Name)
+                and node.func.value.id == "self"
+                and node.func.attr in methods
+            ):
+                called = node.func.attr
+                if called in redispatched or owner_methods.get(called, {}).get("owner") != methods[called]["owner"]:
+                    return True
+        return False
+
+    def _method_signature(self, method: ast.FunctionDef, go_name: Optional[str] = None) -> str:
+        """Return the Go signature of an instance method, without its receiver: Speak(times int) string."""
+        params = ", ".join(f"{arg.arg} {self._infer_parameter_type(arg, method)}" for arg in method.args.args[1:])
+        return_type = self._method_types(method)[1]
+        return f"{go_name or self._to_go_method_name(method.name)}({params}) {return_type}".rstrip()
+
+    def _check_overrides(self, class_name: str, methods: list[ast.FunctionDef]) -> None:
+        """Reject overrides whose Go signature differs from the overridden method's.
+
+        The subclass would not implement its base class's interface, which
+        has one signature per method.
+        """
+        base_methods = self.struct_info[self.struct_info[class_name]["base"]]["methods"]
+        for method in methods:
+            overridden = base_methods.get(method.name)
+            if overridden is None or self._property_kind(method) is not None:
+                continue
+            if self._method_types(method) != self._method_types(overridden["node"]):
+                raise UnsupportedFeatureError(
+                    f"{class_name}.{method.name} cannot override {overridden['owner']}.{method.name} with another "
+                    f"signature: {self._method_signature(method)} "
+                    f"instead of {self._method_signature(overridden['node'])}"
+                )
+
+    def _method_types(self, method: ast.FunctionDef) -> tuple[list[str], str]:
+        """Return the Go parameter types and result type of an instance method."""
+        params = [self._infer_parameter_type(arg, method) for arg in method.args.args[1:]]
+        if method.returns is None:
+            return params, DUNDER_RESULT_TYPES.get(method.name, "")
+        return params, self._map_type_annotation(method.returns)
+
+    def _convert_class_interface(self, class_name: str) -> str:
+        """Generate the interface of a class other classes derive from, and its As{class_name} method.
+
+        Pointers to the class and to its subclasses implement the interface:
+        their methods are promoted or overridden, and As{class_name} reaches
+        the embedded base struct, whose fields are accessed through it.
+
+        Example:
+            type AnimalInterface interface {
+                AsAnimal() *Animal
+                Speak() string
+            }
+        """
+        lines = [f"type {self.class_interfaces[class_name]} interface {{", f"    As{class_name}() *{class_name}"]
+        for info in self.struct_info[class_name]["methods"].values():
+            lines.append(f"    {self._method_signature(info['node'])}")
+        lines.append("}")
+        accessor = f"func (obj *{class_name}) As{class_name}() *{class_name} {{\n    return obj\n}}"
+        return "\n".join(lines) + "\n\n" + accessor
+
+    def _interface_class(self, go_type: str) -> Optional[str]:
+        """Return the class whose interface go_type is, or None."""
+        for class_name, interface in self.class_interfaces.items():
+            if go_type == interface:
+                return class_name
+        return None
+
+    def _instance_class(self, go_type: str) -> Optional[str]:
+        """Return the class of values typed go_type: its struct (Dog) or its interface (AnimalInterface)."""
+        return go_type if go_type in self.struct_info else self._interface_class(go_type)
+
+    def _is_class_instance(self, value: ast.expr) -> bool:
+        """Check whether value is an instance of a user class, typed by its struct or interface."""
+        return self._instance_class(self._infer_type_from_value(value).lstrip("*")) is not None
+
+    def _convert_class_value(self, value: ast.expr, value_expr: str, target_type: str) -> str:
+        """Adapt a converted class instance to the type the context expects.
+
+        A class interface holds pointers: self is one already, a variable is
+        shared by taking its address, and other struct values are copied with
+        mgen.Ref. Where the struct itself is expected, self is dereferenced.
+
+        Example:
+            introduce(d)           →  introduce(&d)                       (a: Animal)
+            introduce(Dog("Rex"))  →  introduce(mgen.Ref(NewDog("Rex")))
+        """
+        if target_type in self.struct_info:
+            return "*obj" if self._is_self(value) else value_expr
+        if self._interface_class(target_type) is None:
+            return value_expr
+        return self._class_pointer(value, value_expr)
+
+    def _is_self(self, value: ast.expr) -> bool:
+        """Check whether value is the receiver of the method being converted."""
+        return isinstance(value, ast.Name) and value.id == "self" and self.method_owner is not None
+
+    def _class_pointer(self, value: ast.expr, value_expr: str) -> str:
+        """Return a pointer to a converted class instance; other values are returned unchanged."""
+        if self._is_self(value):
+            return "obj"
+        if self._infer_type_from_value(value) not in self.struct_info:
+            return value_expr
+        return f"&{value_expr}" if isinstance(value, ast.Name) else f"mgen.Ref({value_expr})"
+
+    def _isinstance_classes(self, expr: ast.Call) -> Optional[list[str]]:
+        """Return the classes isinstance(x, C) or isinstance(x, (C, D)) tests for, or None.
+
+        They are generated classes or built-in exception classes (ValueError, ...).
+        """
+        if len(expr.args) != 2 or expr.keywords:
+            return None
+        spec = expr.args[1]
+        names = spec.elts if isinstance(spec, ast.Tuple) else [spec]
+        if not names or not all(
+            isinstance(name, ast.Name) and (name.id in self.class_names or self._is_builtin_exception(name.id))
+            for name in names
+        ):
+            return None
+        return [name.id for name in names if isinstance(name, ast.Name)]
+
+    def _is_builtin_exception(self, name: str) -> bool:
+        """Check whether name is a built-in exception class not shadowed by a variable."""
+        builtin = getattr(builtins, name, None)
+        return (
+            isinstance(builtin, type) and issubclass(builtin, BaseException) and name not in self.variable_types
+        )
+
+    def _convert_isinstance(self, expr: ast.Call, value_expr: str) -> str:
+        """Convert isinstance(x, C) for generated classes to a type assertion.
+
+        Instances are tested through pointers: a *C is an instance of C, and
+        pointers to its subclasses' structs implement C's interface. Built-in
+        exception classes are looked up in the runtime's exception hierarchy.
+
+        Example:
+            isinstance(a, Dog)         →  mgen.InstanceOf[*Dog](a)
+            isinstance(d, Animal)      →  mgen.InstanceOf[AnimalInterface](&d)
+            isinstance(e, LookupError) →  mgen.IsInstance(e, "LookupError")
+        """
+        classes = self._isinstance_classes(expr)
+        assert classes is not None
+        pointer_expr = self._class_pointer(expr.args[0], value_expr)
+        tests = [
+            f"mgen.InstanceOf[{self.class_interfaces.get(name, '*' + name)}]({pointer_expr})"
+            if name in self.class_names
+            else f'mgen.IsInstance({value_expr}, "{name}")'
+            for name in classes
+        ]
+        return tests[0] if len(tests) == 1 else f"({' || '.join(tests)})"
+
+    def _convert_instance(self, owner: ast.expr, owner_expr: str) -> str:
+        """Return the struct whose fields an attribute of owner reads: As{Class}() of a class interface.
+
+        Example:
+            a.name  →  a.AsAnimal().Name  (a: Animal, subclassed)
+        """
+        interface_class = self._interface_class(self._infer_type_from_value(owner))
+        return f"{owner_expr}.As{interface_class}()" if interface_class else owner_expr
+
+    def _called_method(self, expr: ast.Call, class_name: Optional[str] = None) -> Optional[ast.FunctionDef]:
+        """Return the method of a generated class that obj.method(...) calls, or None."""
+        assert isinstance(expr.func, ast.Attribute)
+        receiver = expr.func.value
+        if isinstance(receiver, ast.Name) and receiver.id == "self" and class_name is not None:
+            owner: Optional[str] = class_name
+        else:
+            owner = self._instance_class(self._infer_type_from_value(receiver))
+        method = self.struct_info[owner]["methods"].get(expr.func.attr) if owner is not None else None
+        return method["node"] if method is not None else None
+
+    def _expect_method_func_types(self, expr: ast.Call, class_name: Optional[str] = None) -> None:
+        """Record the func types of a called method's Callable parameters for the lambdas passed to them."""
+        method = self._called_method(expr, class_name)
+        if method is not None:
+            for arg, param_type in zip(expr.args, self._method_types(method)[0]):
+                self._expect_func_type(arg, param_type)
+
+    def _convert_method_arguments(self, expr: ast.Call, args: list[str], class_name: Optional[str] = None) -> list[str]:
+        """Adapt the converted arguments of a call to a method of a generated class to its parameters.
+
+        Keyword arguments and omitted parameters are bound like a module
+        function's (see _convert_function_call).
+        """
+        method = self._called_method(expr, class_name)
+        if method is None:
+            return args
+        param_types = self._method_types(method)[0]
+        if expr.keywords or len(expr.args) != len(param_types):
+            return self._bind_method_arguments(method, expr, param_types, class_name)
+        return [
+            self._convert_class_value(arg, arg_expr, param_type)
+            for arg, arg_expr, param_type in zip(expr.args, args, param_types)
+        ] + args[len(param_types) :]
+
+    def _bind_method_arguments(
+        self, method: ast.FunctionDef, expr: ast.Call, param_types: list[str], class_name: Optional[str]
+    ) -> list[str]:
+        """Bind the arguments of a method call passing keywords or leaving out parameters with defaults."""
+        assert isinstance(expr.func, ast.Attribute)
+        receiver = expr.func.value
+        if isinstance(receiver, ast.Name) and receiver.id == "self" and class_name is not None:
+            receiver_class: Optional[str] = class_name
+        else:
+            receiver_class = self._instance_class(self._infer_type_from_value(receiver))
+        assert receiver_class is not None
+        # Python names the class defining the method
+        func_name = f"{self.struct_info[receiver_class]['methods'][method.name]['owner']}.{method.name}()"
+        bound = self._bind_arguments(func_name, method, expr, is_method=True)[0]
+
+        def convert(value: ast.expr) -> str:
+            if class_name is None:
+                return self._convert_expression(value)
+            return self._convert_method_expression(value, class_name)
+
+        args = []
+        for arg, param_type in zip([*method.args.posonlyargs, *method.args.args][1:], param_types):
+            default = self._parameter_default(arg, method)
+            if bound[arg.arg] is default and not self._is_static_default(default):
+                raise UnsupportedFeatureError(
+                    f"The default value of parameter {arg.arg} of {func_name} is not an immutable literal, so it "
+                    f"must be passed: {ast.unparse(expr)}"
+                )
+            args.append(self._convert_argument(bound[arg.arg], param_type, convert))
+        return args
+
+    def _expect_interface_list(self, value: ast.expr, target_type: str) -> None:
+        """Record the class interface a list display's elements are stored as, before it is converted."""
+        if isinstance(value, ast.List) and target_type.startswith("[]") and self._interface_class(target_type[2:]):
+            self.interface_lists[id(value)] = target_type[2:]
+
+    def _passes_self(self, method: ast.FunctionDef) -> bool:
+        """Check whether a method uses self as a value (f(self), return self), not only to reach attributes."""
+        owners = {id(node.value) for node in ast.walk(method) if isinstance(node, ast.Attribute)}
+        return any(
+            isinstance(node, ast.Name) and node.id == "self" and id(node) not in owners for node in ast.walk(method)
+        )
+
+    def _operator_method(self, value: ast.expr, dunder: str) -> Optional[ast.FunctionDef]:
+        """Return the dunder method the class of value defines (__add__ for value + x), or None."""
+        owner = self.method_owner if self._is_self(value) else self._instance_class(self._infer_type_from_value(value))
+        if owner is None:
+            return None
+        info = self.struct_info[owner]["methods"].get(dunder)
+        return info["node"] if info is not None else None
+
+    def _operator_method_type(self, expr: ast.expr) -> Optional[str]:
+        """Return the type of an operator a dunder method of a class implements (a + b, v[i]), or None."""
+        if isinstance(expr, ast.BinOp) and type(expr.op) in OPERATOR_DUNDERS:
+            method = self._operator_method(expr.left, OPERATOR_DUNDERS[type(expr.op)])
+        elif isinstance(expr, ast.Subscript) and not isinstance(expr.slice, ast.Slice):
+            method = self._operator_method(expr.value, "__getitem__")
+        else:
+            return None
+        if method is None:
+            return None
+        return self._method_types(method)[1] or "interface{}"
+
+    def _convert_operator_call(
+        self, value: ast.expr, value_expr: str, dunder: str, args: list[tuple[ast.expr, str]]
+    ) -> Optional[str]:
+        """Convert an operator on a class instance to a call of its dunder method, or None.
+
+        Example:
+            a + b     →  a.PyAdd(b)
+            len(bag)  →  bag.PyLen()
+            bag[i]    →  bag.PyGetItem(i)
+        """
+        method = self._operator_method(value, dunder)
+        if method is None:
+            return None
+        param_types = self._method_types(method)[0]
+        if len(args) != len(param_types):
+            takes = len(param_types) + 1
+            raise TypeMappingError(
+                f"TypeError: {method.name}() takes {takes} positional argument{'' if takes == 1 else 's'} "
+                f"but {len(args) + 1} were given"
+            )
+        converted = [
+            self._convert_class_value(arg, arg_expr, type_name) for (arg, arg_expr), type_name in zip(args, param_types)
+        ]
+        return f"{self._method_receiver(value, value_expr)}.{self._to_go_method_name(dunder)}({', '.join(converted)})"
+
+    def _convert_hash_call(self, value: ast.expr, value_expr: str) -> str:
+        """Convert hash(x): a class instance's __hash__ directly, any other value through mgen.HashValue."""
+        return self._convert_operator_call(value, value_expr, "__hash__", []) or f"mgen.HashValue({value_expr})"
+
+    def _method_receiver(self, value: ast.expr, value_expr: str) -> str:
+        """Return a class instance as the receiver of one of its pointer methods.
+
+        Variables, fields and items are addressable, so Go takes their address
+        itself; other struct values, such as the result of a + b, are copied
+        with mgen.Ref.
+        """
+        if self._is_self(value):
+            return "obj"
+        if isinstance(value, (ast.Name, ast.Attribute, ast.Subscript)):
+            return value_expr
+        if self._interface_class(self._infer_type_from_value(value)):
+            return value_expr
+        return f"mgen.Ref({value_expr})"
+
+    def _convert_operator_binop(self, expr: ast.BinOp, left: str, right: str) -> Optional[str]:
+        """Convert a + b, a - b or a * b on a class defining the operator's dunder method, or None."""
+        dunder = OPERATOR_DUNDERS.get(type(expr.op))
+        if dunder is None:
+            return None
+        return self._convert_operator_call(expr.left, left, dunder, [(expr.right, right)])
+
+    def _convert_operator_compare(
+        self, expr: ast.Compare, left_expr: str, convert: Callable[[ast.expr], str]
+    ) -> Optional[str]:
+        """Convert a < b on class instances to a call of __lt__, and a > b to b.__lt__(a) without __gt__, or None.
+
+        a == b calls __eq__, and a != b negates it as Python's default __ne__ does.
+        """
+        if len(expr.ops) != 1 or not isinstance(expr.ops[0], (ast.Eq, ast.NotEq, *COMPARE_DUNDERS)):
+            return None
+        left, right = expr.left, expr.comparators[0]
+        if isinstance(expr.ops[0], (ast.Eq, ast.NotEq)):
+            equal = self._convert_operator_call(left, left_expr, "__eq__", [(right, convert(right))])
+            if equal is None or isinstance(expr.ops[0], ast.Eq):
+                return equal
+            return f"!{equal}"
+        dunder, reflected = COMPARE_DUNDERS[type(expr.ops[0])]
+        if self._operator_method(left, dunder) is not None:
+            return self._convert_operator_call(left, left_expr, dunder, [(right, convert(right))])
+        # Python falls back to the reflected method: a > b is b.__lt__(a)
+        if self._operator_method(right, reflected) is None:
+            return None
+        return self._convert_operator_call(right, convert(right), reflected, [(left, left_expr)])
+
+    def _is_super_call(self, expr: ast.expr, method: Optional[str] = None) -> bool:
+        """Check for super().method(...) (or any super() method call when method is None)."""
+        return (
+            isinstance(expr, ast.Call)
+            and isinstance(expr.func, ast.Attribute)
+            and (method is None or expr.func.attr == method)
+            and isinstance(expr.func.value, ast.Call)
+            and isinstance(expr.func.value.func, ast.Name)
+            and expr.func.value.func.id == "super"
+        )
+
+    def _convert_super_call(self, expr: ast.Call, class_name: str, convert: Callable[[ast.expr], str]) -> str:
+        """Convert super().method(...) to a call on the embedded base struct.
+
+        Example:
+            super().speak()  (in Dog(Animal))  →  obj.Animal.Speak()
+        """
+        assert isinstance(expr.func, ast.Attribute)
+        method = expr.func.attr
+        base = self.struct_info[self.method_owner or class_name]["base"]
+        if base is None or method not in self.struct_info[base]["methods"]:
+            raise TypeMappingError(f"AttributeError: 'super' object has no attribute '{method}'")
+        args = ", ".join(convert(arg) for arg in expr.args)
+        info = self.struct_info[base]["methods"][method]
+        redispatched = {name for _, node in self._redispatched_methods(class_name) for name in [node.name]}
+        if self._dispatches_differently(class_name, info, redispatched):
+            # The base method run on the embedded struct would miss class_name's overrides
+            self.super_methods.setdefault(class_name, {})[(info["owner"], method)] = info["node"]
+            return f"obj.{self._super_method_name(info['owner'], method)}({args})"
+        target = ".".join(["obj", *self._embedded_path(class_name, base)])
+        return f"{target}.{self._to_go_method_name(method)}({args})"
+
+    def _super_method_name(self, owner: str, method: str) -> str:
+        """Return the name of owner's method generated for a subclass receiver, for super() calls."""
+        return f"super{owner}{self._to_go_method_name(method)}"
+
+    def _convert_inherited_constructor(self, class_name: str) -> list[str]:
+        """Generate the constructor of a class without __init__.
+
+        A subclass forwards its arguments to the base constructor (sharing the
+        base's options struct through a type alias); a class with no __init__
+        anywhere gets a zero-value constructor.
+        """
+        info = self.struct_info[class_name]
+        base = info["base"]
+        if base is None:
+            if info["exception"]:
+                # Like BaseException, accept any positional arguments as the exception's args
+                return [
+                    f"func New{class_name}(args ...interface{{}}) {class_name} {{\n"
+                    f"    obj := {class_name}{{}}\n"
+                    f'    obj.PyError = mgen.ExceptionArgs("{class_name}", args...)\n'
+                    "    return obj\n}"
+                ]
+            return [f"func New{class_name}() {class_name} {{\n    obj := {class_name}{{}}\n    return obj\n}}"]
+
+        lines = []
+        params = []
+        args = []
+        kwonly = set()
+        if info["init"] is not None:
+            kwonly = {arg.arg for arg in info["init"].args.kwonlyargs}
+            for arg, param_type in self._init_parameters(info["init"]):
+                if arg.arg not in kwonly:
+                    params.append(f"{arg.arg} {param_type}")
+                    args.append(arg.arg)
+        elif info["exception"]:
+            params.append("args ...interface{}")
+            args.append("args...")
+        if kwonly:
+            lines.append(f"type {class_name}Options = {base}Options")
+            params.append(f"opts {class_name}Options")
+            args.append("opts")
+        body = [f"    obj := {class_name}{{}}", f"    obj.{base} = New{base}({', '.join(args)})"]
+        if info["exception"]:
+            body.append(f'    obj.Type = "{class_name}"')
+        lines.append(f"func New{class_name}({', '.join(params)}) {class_name} {{\n" + "\n".join(body) + "\n    return obj\n}")
+        return lines
+
+    def _init_parameters(self, init_method: ast.FunctionDef) -> list[tuple[ast.arg, str]]:
+        """Return the (parameter, Go type) pairs of __init__, skipping self."""
+        args = init_method.args
+        if args.vararg or args.kwarg:
+            raise UnsupportedFeatureError("*args and **kwargs parameters are not supported in __init__")
+        params = [*args.posonlyargs, *args.args][1:] + args.kwonlyargs
+        return [(arg, self._infer_parameter_type(arg, init_method)) for arg in params]
+
+    def _convert_constructor_options(self, class_name: str, init_method: ast.FunctionDef) -> str:
+        """Generate the options struct holding __init__'s keyword-only arguments.
+
+        Like mgen.PrintOptions for print(), call sites fill in every field, using
+        the parameter's default when the keyword is not passed.
+        """
+        kwonly = {arg.arg for arg in init_method.args.kwonlyargs}
+        lines = [f"type {class_name}Options struct {{"]
+        for arg, param_type in self._init_parameters(init_method):
+            if arg.arg in kwonly:
+                lines.append(f"    {self._to_camel_case(arg.arg)} {param_type}")
+        lines.append("}")
+        return "\n".join(lines)
+
     def _convert_constructor(self, class_name: str, init_method: ast.FunctionDef) -> str:
-        """Generate Go constructor function from Python __init__."""
+        """Generate Go constructor function from Python __init__.
+
+        Positional parameters become Go parameters; keyword-only parameters
+        arrive in a {class_name}Options struct and are unpacked into locals.
+        """
+        kwonly = {arg.arg for arg in init_method.args.kwonlyargs}
+        used = {node.id for stmt in init_method.body for node in ast.walk(stmt) if isinstance(node, ast.Name)}
+
         # Build parameter list (skip 'self')
         params = []
-        for arg in init_method.args.args[1:]:  # Skip self
-            param_type = self._infer_parameter_type(arg, init_method)
-            params.append(f"{arg.arg} {param_type}")
+        option_names = []
+        for arg, param_type in self._init_parameters(init_method):
+            if arg.arg not in kwonly:
+                params.append(f"{arg.arg} {param_type}")
+            elif arg.arg in used:
+                option_names.append(arg.arg)
+        if kwonly:
+            params.append(f"opts {class_name}Options")
 
         params_str = ", ".join(params)
 
         # Generate constructor body
         body_lines = [f"    obj := {class_name}{{}}"]
-
+        if option_names:
+            fields = ", ".join(f"opts.{self._to_camel_case(name)}" for name in option_names)
+            body_lines.append(f"    {', '.join(option_names)} := {fields}")
+        exception = self._is_exception_class(class_name)
+        if exception:
+            # As in BaseException.__new__, args are the call's positional arguments until __init__ says otherwise
+            positional = [arg.arg for arg, _ in self._init_parameters(init_method) if arg.arg not in kwonly]
+            body_lines.append(f'    obj.PyError = mgen.ExceptionArgs({", ".join([json.dumps(class_name), *positional])})')
+
+        field_types = self.struct_info[class_name]["field_types"]
         for stmt in init_method.body:
             if isinstance(stmt, ast.Assign):
+                fields = [t for t in stmt.targets if isinstance(t, ast.Attribute) and ast.unparse(t.value) == "self"]
+                if fields and self._needs_chained_value(stmt):
+                    temp_decl, stmt = self._chained_value(stmt, self._convert_expression)
+                    body_lines.append(temp_decl)
                 for target in stmt.targets:
                     if (
                         isinstance(target, ast.Attribute)
                         and isinstance(target.value, ast.Name)
                         and target.value.id == "self"
                     ):
+                        value_expr = self._convert_field_value(stmt.value, field_types.get(target.attr, ""))
+                        setter = self._property_setter(class_name, "obj", target.attr, value_expr)
+                        if setter is not None:
+                            body_lines.append(setter)
+                            continue
                         field_name = self._to_camel_case(target.attr)
-                        value_expr = self._convert_expression(stmt.value)
                         body_lines.append(f"    obj.{field_name} = {value_expr}")
             elif isinstance(stmt, ast.AnnAssign):
                 if (
@@ -231,8 +1846,36 @@ class MGenPythonToGoConverter:
                 ):
                     field_name = self._to_camel_case(stmt.target.attr)
                     if stmt.value:
-                        value_expr = self._convert_expression(stmt.value)
+                        value_expr = self._convert_field_value(stmt.value, field_types.get(stmt.target.attr, ""))
                         body_lines.append(f"    obj.{field_name} = {value_expr}")
+            elif (
+                isinstance(stmt, ast.Expr)
+                and isinstance(stmt.value, ast.Call)
+                and isinstance(stmt.value.func, ast.Attribute)
+                and ast.unparse(stmt.value.func.value) == "self"
+                and stmt.value.func.attr in self.struct_info[class_name]["methods"]
+            ):
+                # self.method(...), such as a dataclass's __post_init__(); obj is addressable, so it takes &obj
+                body_lines.append(f"    {self._convert_method_expression(stmt.value, class_name)}")
+            elif isinstance(stmt, ast.Expr) and self._is_super_call(stmt.value, "__init__"):
+                # super().__init__(...) initializes the embedded base struct
+                assert isinstance(stmt.value, ast.Call)
+                base = self.struct_info[class_name]["base"]
+                if base is None and exception:
+                    # Exception.__init__(*args) replaces the exception's args
+                    args = [json.dumps(class_name), *(self._convert_expression(arg) for arg in stmt.value.args)]
+                    body_lines.append(f"    obj.PyError = mgen.ExceptionArgs({', '.join(args)})")
+                    continue
+                if base is None:
+                    if stmt.value.args or stmt.value.keywords:
+                        raise TypeMappingError(
+                            "TypeError: object.__init__() takes exactly one argument (the instance to initialize)"
+                        )
+                    continue
+                base_call = self._convert_constructor_call(base, stmt.value, self._convert_expression)
+                body_lines.append(f"    obj.{base} = {base_call}")
+                if exception:
+                    body_lines.append(f'    obj.Type = "{class_name}"')
 
         body_lines.append("    return obj")
 
@@ -241,34 +1884,456 @@ class MGenPythonToGoConverter:
 
         return f"{func_signature} {{\n" + "\n".join(body_lines) + "\n}"
 
-    def _convert_method(self, class_name: str, method: ast.FunctionDef) -> str:
-        """Convert Python instance method to Go method."""
-        # Build parameter list (convert 'self' to receiver)
-        params = []
-        for arg in method.args.args[1:]:  # Skip self
-            param_type = self._infer_parameter_type(arg, method)
-            params.append(f"{arg.arg} {param_type}")
+    def _convert_field_value(self, value: ast.expr, field_type: str) -> str:
+        """Convert a value stored in a struct field; Optional fields keep the *T and [] and {} take the field's type."""
+        if self._is_optional_type(field_type):
+            return self._convert_optional_value(value, field_type)
+        if self._is_empty_container(value) and field_type.startswith(("*mgen.Dict[", "*mgen.Set[")):
+            return self._get_default_value(field_type)
+        if ast.unparse(value) == "[]" and field_type.startswith("[]"):
+            return self._get_default_value(field_type)
+        self._expect_func_type(value, field_type)
+        return self._convert_expression(value)
 
-        params_str = ", ".join(params)
+    def _convert_constructor_call(self, class_name: str, expr: ast.Call, convert: Callable[[ast.expr], str]) -> str:
+        """Convert ClassName(...) to New{class_name}(...), binding arguments like Python.
 
-        # Get return type
-        return_type = ""
-        if method.returns:
-            mapped_type = self._map_type_annotation(method.returns)
-            if mapped_type:  # Only add space if type is not empty
-                return_type = " " + mapped_type
+        Keyword arguments are matched to __init__'s parameters and omitted ones
+        take their defaults, so every call passes the full parameter list.
+        Keyword-only parameters are passed as a {class_name}Options literal.
+
+        Example:
+            Point(1, c=3)  (def __init__(self, a, b=0, *, c=None))
+                →  NewPoint(1, 0, PointOptions{C: mgen.Some[int](3)})
+        """
+        init_method = self.struct_info[class_name].get("init")
+        if init_method is None:
+            return f"New{class_name}({', '.join(convert(arg) for arg in expr.args)})"
+
+        bound = self._bind_init_arguments(class_name, init_method, expr)
+        kwonly = {arg.arg for arg in init_method.args.kwonlyargs}
+        args = []
+        options = []
+        for arg, param_type in self._init_parameters(init_method):
+            value = bound[arg.arg]
+            self._expect_func_type(value, param_type)
+            empty = self._is_empty_container(value) or ast.unparse(value) in ("[]", "list()")
+            if self._is_optional_type(param_type):
+                value_expr = self._convert_optional_value(value, param_type)
+            elif empty and param_type.startswith(("[]", "*mgen.Dict[", "*mgen.Set[")):
+                # [] and {} (a dataclass default_factory's list() and dict() too) take the parameter's type
+                value_expr = self._get_default_value(param_type)
+            else:
+                value_expr = self._convert_class_value(value, convert(value), param_type)
+            if arg.arg in kwonly:
+                options.append(f"{self._to_camel_case(arg.arg)}: {value_expr}")
+            else:
+                args.append(value_expr)
+        if kwonly:
+            args.append(f"{class_name}Options{{{', '.join(options)}}}")
+        return f"New{class_name}({', '.join(args)})"
+
+    def _binds_arguments(self, func_name: str, expr: ast.Call) -> bool:
+        """Check whether a call to a module function needs its arguments bound (see _convert_function_call).
+
+        Calls passing every positional parameter in order, and nothing else,
+        convert argument by argument, unless the function takes a list by pointer.
+        """
+        func = self.function_nodes.get(func_name)
+        if func is None or func_name in self.decorators:
+            return False
+        args = func.args
+        return bool(
+            self.reference_params.get(func_name)
+            or expr.keywords
+            or args.vararg
+            or args.kwonlyargs
+            or args.kwarg
+            or len(expr.args) != len(args.posonlyargs) + len(args.args)
+            or any(isinstance(arg, ast.Starred) for arg in expr.args)
+        )
+
+    def _convert_function_call(self, func_name: str, expr: ast.Call, convert: Callable[[ast.expr], str]) -> str:
+        """Convert a call to a module function, binding its arguments like Python.
+
+        Omitted parameters take their defaults, which must be immutable
+        literals: Python evaluates a default once, when the function is
+        defined, and only then is evaluating it at each call site the same.
+        The arguments *args collects are passed as a slice, keyword-only
+        arguments as a {func}Options literal and the keyword arguments
+        **kwargs collects as a dict.
+
+        Example:
+            scale(3, 1, 2, clamp=True)  (def scale(factor, *extra: int, offset=0, clamp=False))
+                →  scale(3, []int{1, 2}, scaleOptions{Offset: 0, Clamp: true})
+        """
+        func = self.function_nodes[func_name]
+        bound, extra_args, extra_keywords = self._bind_arguments(f"{func_name}()", func, expr)
+        bindings = self._type_arguments(func_name, expr) if func_name in self.generic_functions else {}
+
+        def param_type(arg: ast.arg) -> str:
+            return substitute_type_params(self._infer_parameter_type(arg, func), bindings)
+
+        for arg in [*func.args.posonlyargs, *func.args.args, *func.args.kwonlyargs]:
+            default = self._parameter_default(arg, func)
+            if bound[arg.arg] is default and not self._is_static_default(default):
+                raise UnsupportedFeatureError(
+                    f"The default value of parameter {arg.arg} of {func_name}() is not an immutable literal, so it "
+                    f"must be passed: {ast.unparse(expr)}"
+                )
+        args = [
+            self._convert_argument(bound[arg.arg], param_type(arg), convert)
+            for arg in [*func.args.posonlyargs, *func.args.args]
+        ]
+        for index, arg in enumerate([*func.args.posonlyargs, *func.args.args]):
+            if arg.arg in self.reference_params.get(func_name, ()):
+                args[index] = self._convert_reference_argument(bound[arg.arg], args[index])
+        if func.args.vararg is not None:
+            item_type = substitute_type_params(self._star_parameter_type(func.args.vararg), bindings)
+            args.append(self._convert_star_arguments(extra_args, item_type, convert))
+        if func.args.kwonlyargs:
+            fields = [
+                f"{self._to_camel_case(arg.arg)}: {self._convert_argument(bound[arg.arg], param_type(arg), convert)}"
+                for arg in func.args.kwonlyargs
+            ]
+            args.append(f"{self._function_options_name(func_name)}{{{', '.join(fields)}}}")
+        if func.args.kwarg is not None:
+            item_type = substitute_type_params(self._star_parameter_type(func.args.kwarg), bindings)
+            args.append(self._convert_keyword_arguments(extra_keywords, item_type, convert))
+        return f"{func_name}({', '.join(args)})"
+
+    def _is_static_default(self, value: ast.expr) -> bool:
+        """Check whether a default value is an immutable literal: a constant, -1, or a tuple of them."""
+        if isinstance(value, ast.UnaryOp) and isinstance(value.op, (ast.USub, ast.UAdd)):
+            value = value.operand
+        if isinstance(value, ast.Tuple):
+            return all(self._is_static_default(elt) for elt in value.elts)
+        return isinstance(value, ast.Constant)
+
+    def _convert_argument(self, value: ast.expr, param_type: str, convert: Callable[[ast.expr], str]) -> str:
+        """Convert an argument bound to a parameter of param_type, as a call passing it positionally does."""
+        self._expect_func_type(value, param_type)
+        if self._is_optional_type(param_type):
+            return self._convert_optional_value(value, param_type)
+        empty = self._is_empty_container(value) or ast.unparse(value) in ("[]", "list()")
+        if empty and param_type.startswith(("[]", "*mgen.Dict[", "*mgen.Set[")):
+            return self._get_default_value(param_type)
+        value_expr = self._coerce_int_precision(param_type, value, convert(value))
+        return self._convert_class_value(value, value_expr, param_type)
+
+    def _convert_star_arguments(
+        self, values: list[ast.expr], item_type: str, convert: Callable[[ast.expr], str]
+    ) -> str:
+        """Convert the positional arguments *args collects to a new slice, appending the items of *xs arguments.
+
+        Example:
+            total(1, *xs)  →  total(append([]int{1}, xs...))
+        """
+        items: list[str] = []
+        unpacked: list[str] = []
+        for value in values:
+            if not isinstance(value, ast.Starred):
+                # Items after a *xs are appended to it
+                (unpacked if unpacked else items).append(self._convert_argument(value, item_type, convert))
+                continue
+            if self._infer_type_from_value(value.value) != f"[]{item_type}":
+                raise UnsupportedFeatureError(
+                    f"*{ast.unparse(value.value)} must be a list of the *args item type {item_type} to be unpacked"
+                )
+            unpacked.append(f"{convert(value.value)}...")
+        result = f"[]{item_type}{{{', '.join(items)}}}"
+        for value_expr in unpacked:
+            result = f"append({result}, {value_expr})"
+        return result
+
+    def _convert_keyword_arguments(
+        self, keywords: list[ast.keyword], item_type: str, convert: Callable[[ast.expr], str]
+    ) -> str:
+        """Convert the keyword arguments **kwargs collects to a new dict in call order."""
+        if not keywords:
+            return f"mgen.NewDict[string, {item_type}]()"
+        entries = []
+        for kw in keywords:
+            value_expr = self._convert_argument(kw.value, item_type, convert)
+            entries.append(f"mgen.KV[string, {item_type}]{{Key: {json.dumps(kw.arg)}, Value: {value_expr}}}")
+        return f"mgen.NewDict({', '.join(entries)})"
+
+    def _bind_init_arguments(self, class_name: str, init_method: ast.FunctionDef, expr: ast.Call) -> dict[str, ast.expr]:
+        """Bind a constructor call's arguments to __init__'s parameters (see _bind_arguments)."""
+        if any(isinstance(arg, ast.Starred) for arg in expr.args) or any(kw.arg is None for kw in expr.keywords):
+            raise UnsupportedFeatureError(f"*args/**kwargs unpacking not supported in {class_name}() calls")
+        func_name = f"{self.struct_info[class_name].get('init_owner') or class_name}.__init__()"
+        return self._bind_arguments(func_name, init_method, expr, is_method=True)[0]
+
+    def _bind_arguments(
+        self, func_name: str, func: ast.FunctionDef, expr: ast.Call, is_method: bool = False
+    ) -> tuple[dict[str, ast.expr], list[ast.expr], list[ast.keyword]]:
+        """Bind a call's arguments to the parameters of func like Python.
+
+        Returns each named parameter's value, omitted parameters taking their
+        default expressions, with the positional arguments *args collects and
+        the keyword arguments **kwargs collects. Unpacking binds at run time,
+        so *xs may only fill *args and **mapping is not supported. Calls
+        Python would reject raise TypeMappingError with Python's TypeError
+        message, naming the function as func_name ("f()").
+        """
+        args = func.args
+        skip = 1 if is_method else 0
+        positional = [arg.arg for arg in [*args.posonlyargs, *args.args][skip:]]
+        posonly = {arg.arg for arg in args.posonlyargs}
+        kwonly = [arg.arg for arg in args.kwonlyargs]
+        params = [*args.posonlyargs, *args.args][skip:] + args.kwonlyargs
+        defaults = {arg.arg: self._parameter_default(arg, func) for arg in params}
+
+        def type_error(message: str) -> TypeMappingError:
+            return TypeMappingError(f"TypeError: {func_name} {message}")
+
+        if any(kw.arg is None for kw in expr.keywords):
+            raise UnsupportedFeatureError(f"**mapping unpacking is not supported in {func_name} calls")
+        starred = [index for index, arg in enumerate(expr.args) if isinstance(arg, ast.Starred)]
+        if starred and (args.vararg is None or starred[0] < len(positional)):
+            raise UnsupportedFeatureError(f"*iterable unpacking is only supported into *args in {func_name} calls")
+
+        bound: dict[str, ast.expr] = dict(zip(positional, expr.args))
+        extra_args = list(expr.args[len(positional) :])
+        extra_keywords = []
+        for kw in expr.keywords:
+            assert kw.arg is not None
+            if kw.arg in posonly or (kw.arg not in positional and kw.arg not in kwonly):
+                if args.kwarg is not None:
+                    extra_keywords.append(kw)
+                    continue
+                if kw.arg in posonly:
+                    raise type_error(f"got some positional-only arguments passed as keyword arguments: '{kw.arg}'")
+                raise type_error(f"got an unexpected keyword argument '{kw.arg}'")
+            if kw.arg in bound:
+                raise type_error(f"got multiple values for argument '{kw.arg}'")
+            bound[kw.arg] = kw.value
+
+        if extra_args and args.vararg is None:
+            # Counts include self, as in Python's message
+            required = sum(1 for name in positional if defaults[name] is None) + skip
+            maximum = len(positional) + skip
+            given = len(expr.args) + skip
+            takes = f"from {required} to {maximum}" if required < maximum else str(maximum)
+            plural = "" if takes == "1" else "s"
+            kwonly_given = sum(1 for kw in expr.keywords if kw.arg in kwonly)
+            given_text = str(given)
+            if kwonly_given:
+                kwonly_plural = "" if kwonly_given == 1 else "s"
+                given_text += (
+                    f" positional argument{'' if given == 1 else 's'}"
+                    f" (and {kwonly_given} keyword-only argument{kwonly_plural})"
+                )
+            verb = "was" if given == 1 and not kwonly_given else "were"
+            raise type_error(f"takes {takes} positional argument{plural} but {given_text} {verb} given")
+
+        for kind, names in (("positional", positional), ("keyword-only", kwonly)):
+            missing = [name for name in names if name not in bound and defaults[name] is None]
+            if missing:
+                quoted = [f"'{name}'" for name in missing]
+                if len(quoted) == 1:
+                    listed = quoted[0]
+                elif len(quoted) == 2:
+                    listed = f"{quoted[0]} and {quoted[1]}"
+                else:
+                    listed = ", ".join(quoted[:-1]) + f", and {quoted[-1]}"
+                plural = "" if len(missing) == 1 else "s"
+                raise type_error(f"missing {len(missing)} required {kind} argument{plural}: {listed}")
+
+        for name, default in defaults.items():
+            if name not in bound:
+                assert default is not None
+                bound[name] = default
+        return bound, extra_args, extra_keywords
+
+    def _property_kind(self, method: ast.FunctionDef) -> Optional[str]:
+        """Return "getter" for @property and "setter" for @<name>.setter methods."""
+        for decorator in method.decorator_list:
+            if isinstance(decorator, ast.Name) and decorator.id == "property":
+                return "getter"
+            if (
+                isinstance(decorator, ast.Attribute)
+                and isinstance(decorator.value, ast.Name)
+                and decorator.value.id == method.name
+            ):
+                if decorator.attr == "setter":
+                    return "setter"
+                raise UnsupportedFeatureError(f"@{method.name}.{decorator.attr} properties are not supported")
+        return None
+
+    def _class_function_kind(self, method: ast.FunctionDef) -> Optional[str]:
+        """Return "classmethod" or "staticmethod" for methods with those decorators."""
+        for decorator in method.decorator_list:
+            if isinstance(decorator, ast.Name) and decorator.id in ("classmethod", "staticmethod"):
+                return decorator.id
+        return None
+
+    def _convert_class_function(self, class_name: str, method: ast.FunctionDef, defining: Optional[str] = None) -> str:
+        """Convert a classmethod/staticmethod to a package-level Go function.
+
+        Date.parse becomes DateParse. A staticmethod keeps its parameters; a
+        classmethod replaces its first parameter with cls mgen.ClassRef, and
+        cls(...) inside it constructs the class. A classmethod inherited from
+        defining returns this class where it was annotated to return defining.
+        """
+        kind = self._class_function_kind(method)
+        go_name = f"{class_name}{self._to_camel_case(method.name)}"
+        args = copy.copy(method.args)
+        if kind == "classmethod":
+            if not args.args:
+                raise TypeMappingError(f"TypeError: {class_name}.{method.name}() takes 0 positional arguments but 1 was given")
+            args.args = args.args[1:]
+        function = copy.copy(method)
+        function.name = go_name
+        function.args = args
+        function.decorator_list = []
+        if defining not in (None, class_name) and method.returns is not None:
+            if self._map_type_annotation(method.returns) in (defining, self.class_interfaces.get(defining)):
+                function.returns = ast.Name(id=class_name, ctx=ast.Load())
+        if kind == "staticmethod":
+            return self._convert_function(function)
+
+        cls_name = method.args.args[0].arg
+        self.class_aliases[cls_name] = class_name
+        try:
+            code = self._convert_function(function)
+        finally:
+            del self.class_aliases[cls_name]
+        separator = ", " if args.args else ""
+        return code.replace(f"func {go_name}(", f"func {go_name}({cls_name} mgen.ClassRef{separator}", 1)
+
+    def _class_function_call(
+        self, expr: ast.Call, convert: Callable[[ast.expr], str], class_name: Optional[str] = None
+    ) -> Optional[str]:
+        """Convert Class.f(...), obj.f(...) and cls.f(...) for classmethods/staticmethods, else None.
+
+        Called through the class or an instance, a classmethod receives the
+        class descriptor {Class}Class; called through cls it passes cls on.
+        """
+        assert isinstance(expr.func, ast.Attribute)
+        owner = expr.func.value
+        if not isinstance(owner, ast.Name):
+            return None
+        cls_expr = None
+        if owner.id in self.class_aliases:
+            owner_class: Optional[str] = self.class_aliases[owner.id]
+            cls_expr = owner.id
+        elif owner.id in self.struct_info:
+            owner_class = owner.id
+        else:
+            owner_class = self._property_class(owner, class_name)
+        if owner_class is None:
+            return None
+        function = self.struct_info[owner_class].get("class_functions", {}).get(expr.func.attr)
+        if function is None:
+            return None
+        if "error" in function:
+            raise TypeMappingError(function["error"])
+        args = [convert(arg) for arg in expr.args]
+        if function["kind"] == "classmethod":
+            args.insert(0, cls_expr or f"{owner_class}Class")
+        return f"{function['owner']}{self._to_camel_case(expr.func.attr)}({', '.join(args)})"
+
+    def _property_class(self, owner: ast.expr, class_name: Optional[str] = None) -> Optional[str]:
+        """Return the class of an attribute's owner when it is a generated struct."""
+        if not isinstance(owner, ast.Name):
+            return None
+        if owner.id == "self" and class_name is not None:
+            return class_name
+        return self._instance_class(self.variable_types.get(owner.id, "").lstrip("*"))
+
+    def _field_type(self, expr: ast.Attribute) -> Optional[str]:
+        """Return the declared type of the field or property of a generated class expr reads, or None."""
+        owner_class = self._property_class(expr.value, self.method_class)
+        info = self.struct_info.get(owner_class or "")
+        if info is None:
+            return None
+        prop = info.get("properties", {}).get(expr.attr)
+        if prop is not None:
+            return str(prop["type"])
+        return info.get("field_types", {}).get(expr.attr)
+
+    def _property_getter(self, owner: ast.expr, attr: str, obj_expr: str, class_name: Optional[str] = None) -> Optional[str]:
+        """Convert obj.prop to obj.GetProp() when prop is a property, else None."""
+        owner_class = self._property_class(owner, class_name)
+        if owner_class is None or attr not in self.struct_info[owner_class].get("properties", {}):
+            return None
+        return f"{obj_expr}.Get{self._to_camel_case(attr)}()"
+
+    def _property_setter(self, owner_class: Optional[str], obj_expr: str, attr: str, value_expr: str) -> Optional[str]:
+        """Convert obj.prop = value to obj.SetProp(value) when prop is a property, else None.
+
+        Assigning to a property without a setter is rejected with Python's AttributeError.
+        """
+        if owner_class is None:
+            return None
+        prop = self.struct_info[owner_class].get("properties", {}).get(attr)
+        if prop is None:
+            return None
+        if not prop["setter"]:
+            raise TypeMappingError(f"AttributeError: property '{attr}' of '{owner_class}' object has no setter")
+        return f"    {obj_expr}.Set{self._to_camel_case(attr)}({value_expr})"
+
+    def _convert_property_registration(self, class_name: str, properties: dict[str, dict[str, Any]]) -> str:
+        """Register a class's properties so mgen.GetAttr/SetAttr route through them."""
+        entries = []
+        for name, prop in properties.items():
+            camel = self._to_camel_case(name)
+            accessors = [f"Get: func(obj interface{{}}) interface{{}} {{ return obj.(*{class_name}).Get{camel}() }}"]
+            if prop["setter"]:
+                # Whole float constants are emitted as Go ints (5.0 -> 5), so float setters accept ints too
+                value = "mgen.ToFloat(value)" if prop["type"] == "float64" else f"value.({prop['type']})"
+                accessors.append(
+                    f"Set: func(obj interface{{}}, value interface{{}}) {{ obj.(*{class_name}).Set{camel}({value}) }}"
+                )
+            entries.append(f'        "{name}": {{{", ".join(accessors)}}},')
+        body = "\n".join(entries)
+        return (
+            "func init() {\n"
+            f"    mgen.RegisterProperties(&{class_name}{{}}, map[string]mgen.Property{{\n{body}\n    }})\n"
+            "}"
+        )
+
+    def _convert_method(
+        self, class_name: str, method: ast.FunctionDef, owner: Optional[str] = None, go_name: Optional[str] = None
+    ) -> str:
+        """Convert Python instance method to Go method.
 
+        owner is the class defining the method when it is generated again for
+        a subclass (see _redispatched_methods); super() resolves from there.
+        go_name names such a copy made for super() calls.
+        """
+        if method.args.vararg or method.args.kwonlyargs or method.args.kwarg:
+            raise UnsupportedFeatureError(
+                "*args, keyword-only and **kwargs parameters are not supported in methods: "
+                f"{class_name}.{method.name}()"
+            )
         # Build method signature with receiver
         receiver = f"obj *{class_name}"
-        if params_str:
-            func_signature = f"func ({receiver}) {self._to_go_method_name(method.name)}({params_str}){return_type}"
-        else:
-            func_signature = f"func ({receiver}) {self._to_go_method_name(method.name)}(){return_type}"
+        go_name = go_name or self._to_go_method_name(method.name)
+        kind = self._property_kind(method)
+        if kind is not None:
+            go_name = ("Get" if kind == "getter" else "Set") + self._to_camel_case(method.name)
+        func_signature = f"func ({receiver}) {self._method_signature(method, go_name)}"
 
-        # Convert method body
+        # Convert method body; statements nested in loops come back to the method converters (see _convert_statement)
         self.current_function = method.name
-        body = self._convert_method_statements(method.body, class_name)
-        self.current_function = None
+        self.method_owner = owner or class_name
+        self.method_class = class_name
+        self.declared_vars = {arg.arg for arg in method.args.args[1:]}
+        self.variable_types = {arg.arg: self._infer_parameter_type(arg, method) for arg in method.args.args[1:]}
+        self._enter_global_scope(method, True)
+        try:
+            body = self._convert_method_statements(method.body, class_name)
+        finally:
+            self.current_function = None
+            self.method_owner = None
+            self.method_class = None
+            self.global_vars = set()
+        if self._method_types(method)[1] and isinstance(method.body[-1], (ast.Try, ast.With)):
+            # Go cannot see that a try or with whose clauses all return leaves no path to the end
+            body += '\n    panic("unreachable")'
 
         return func_signature + " {\n" + body + "\n}"
 
@@ -291,38 +2356,92 @@ class MGenPythonToGoConverter:
             return self._convert_method_return(stmt, class_name)
         elif isinstance(stmt, ast.If):
             return self._convert_method_if(stmt, class_name)
+        elif isinstance(stmt, ast.Delete):
+            return self._convert_delete(stmt, class_name)
+        elif isinstance(stmt, ast.Raise):
+            return self._convert_raise(stmt, lambda e: self._convert_method_expression(e, class_name))
         elif isinstance(stmt, ast.Expr):
+            call = stmt.value
+            if isinstance(call, ast.Call) and isinstance(call.func, ast.Attribute) and call.func.attr == "append":
+                # Go's append returns the grown slice, which the statement stores back
+                return self._convert_expression_statement(stmt)
             expr = self._convert_method_expression(stmt.value, class_name)
             return f"    {expr}"
+        elif isinstance(stmt, ast.Try):
+            return self._convert_try(stmt)
+        elif isinstance(stmt, ast.With):
+            return self._convert_with(stmt)
+        elif isinstance(stmt, ast.Match):
+            return self._convert_match(stmt)
         else:
             return self._convert_statement(stmt)
 
     def _convert_method_assignment(self, stmt: ast.Assign, class_name: str) -> str:
         """Convert method assignment with proper obj handling."""
+        if self._needs_chained_value(stmt):
+            temp_decl, chained = self._chained_value(stmt, lambda e: self._convert_method_expression(e, class_name))
+            return temp_decl + "\n" + self._convert_method_assignment(chained, class_name)
+        if len(stmt.targets) == 1 and isinstance(stmt.targets[0], (ast.Tuple, ast.List)):
+            return self._convert_unpacking(
+                stmt.targets[0],
+                stmt.value,
+                lambda e: self._convert_method_expression(e, class_name),
+                lambda assignment: self._convert_method_assignment(assignment, class_name),
+            )
         value_expr = self._convert_method_expression(stmt.value, class_name)
         statements = []
 
         for target in stmt.targets:
             if isinstance(target, ast.Name):
                 # Local variable assignment
-                self._infer_type_from_value(stmt.value)
-                statements.append(f"    {target.id} := {value_expr}")
+                if target.id in self.declared_vars:
+                    statements.append(f"    {target.id} = {value_expr}")
+                else:
+                    self.declared_vars.add(target.id)
+                    statements.append(f"    {target.id} := {value_expr}")
             elif isinstance(target, ast.Attribute):
                 if isinstance(target.value, ast.Name) and target.value.id == "self":
-                    # Instance variable assignment: self.attr = value -> obj.Attr = value
-                    field_name = self._to_camel_case(target.attr)
-                    statements.append(f"    obj.{field_name} = {value_expr}")
+                    obj_expr = "obj"
                 else:
-                    # Regular attribute assignment
                     obj_expr = self._convert_method_expression(target.value, class_name)
+                setter = self._property_setter(
+                    self._property_class(target.value, class_name), obj_expr, target.attr, value_expr
+                )
+                if setter is not None:
+                    statements.append(setter)
+                else:
+                    # Instance variable assignment: self.attr = value -> obj.Attr = value
                     statements.append(f"    {obj_expr}.{self._to_camel_case(target.attr)} = {value_expr}")
+            elif isinstance(target, ast.Subscript):
+                statements.append(
+                    self._convert_subscript_assignment(
+                        target, stmt.value, value_expr, lambda e: self._convert_method_expression(e, class_name)
+                    )
+                )
+            elif isinstance(target, (ast.Tuple, ast.List)):
+                statements.append(
+                    self._convert_unpacking(
+                        target,
+                        stmt.value,
+                        lambda e: self._convert_method_expression(e, class_name),
+                        lambda assignment: self._convert_method_assignment(assignment, class_name),
+                    )
+                )
 
         return "\n".join(statements)
 
     def _convert_method_annotated_assignment(self, stmt: ast.AnnAssign, class_name: str) -> str:
         """Convert method annotated assignment with proper obj handling."""
-        if stmt.value:
+        empty_list = ast.unparse(stmt.value) == "[]" if stmt.value else False
+        if stmt.value and (self._is_empty_container(stmt.value) or empty_list):
+            # [], {} and set() take their types from the annotation
+            value_expr = self._get_default_value(self._map_type_annotation(stmt.annotation))
+        elif stmt.value:
+            var_type = self._map_type_annotation(stmt.annotation)
+            self._expect_func_type(stmt.value, var_type)
+            self._expect_interface_list(stmt.value, var_type)
             value_expr = self._convert_method_expression(stmt.value, class_name)
+            value_expr = self._convert_class_value(stmt.value, value_expr, var_type)
         else:
             # Default value based on type
             type_name = self._map_type_annotation(stmt.annotation)
@@ -330,6 +2449,9 @@ class MGenPythonToGoConverter:
 
         if isinstance(stmt.target, ast.Name):
             # Local variable with type annotation
+            if stmt.target.id in self.declared_vars:
+                return f"    {stmt.target.id} = {value_expr}"
+            self.declared_vars.add(stmt.target.id)
             var_type = self._map_type_annotation(stmt.annotation)
             return f"    var {stmt.target.id} {var_type} = {value_expr}"
         elif isinstance(stmt.target, ast.Attribute):
@@ -345,20 +2467,37 @@ class MGenPythonToGoConverter:
         value_expr = self._convert_method_expression(stmt.value, class_name)
 
         # Get augmented assignment operator from converter_utils
-        op = get_augmented_assignment_operator(stmt.op)
-        if op is None:
-            # Handle Go-specific operators
-            if isinstance(stmt.op, ast.FloorDiv):
-                op = "/="  # Go integer division is already floor division
-            else:
-                op = "/*UNKNOWN_OP*/"
+        op = get_augmented_assignment_operator(stmt.op) or "/*UNKNOWN_OP*/"
 
         if isinstance(stmt.target, ast.Name):
-            return f"    {stmt.target.id} {op} {value_expr}"
+            target_type = self.variable_types.get(stmt.target.id, "")
+            special = self._convert_container_aug_assignment(stmt.target.id, target_type, stmt.op, value_expr)
+            special = special or self._convert_floored_aug_assignment(stmt, stmt.target.id, value_expr)
+            if special is None and isinstance(stmt.op, ast.Pow):
+                # Go has no **= operator, so x **= y rebinds x to x ** y
+                updated = ast.BinOp(left=stmt.target, op=stmt.op, right=stmt.value)
+                special = f"    {stmt.target.id} = {self._convert_method_expression(updated, class_name)}"
+            return special or f"    {stmt.target.id} {op} {value_expr}"
         elif isinstance(stmt.target, ast.Attribute):
             if isinstance(stmt.target.value, ast.Name) and stmt.target.value.id == "self":
+                current = self._property_getter(stmt.target.value, stmt.target.attr, "obj", class_name)
+                if current is not None:
+                    # self.prop += v reads through the getter and writes through the setter
+                    new_value = f"({current} {op[:-1]} {value_expr})"
+                    if isinstance(stmt.op, (ast.FloorDiv, ast.Mod)):
+                        updated = ast.BinOp(left=stmt.target, op=stmt.op, right=stmt.value)
+                        new_value = self._convert_floored_operator(updated, current, value_expr)
+                    setter = self._property_setter(class_name, "obj", stmt.target.attr, new_value)
+                    assert setter is not None
+                    return setter
                 field_name = self._to_camel_case(stmt.target.attr)
-                return f"    obj.{field_name} {op} {value_expr}"
+                field_type = self.struct_info.get(class_name, {}).get("field_types", {}).get(stmt.target.attr, "")
+                special = self._convert_container_aug_assignment(f"obj.{field_name}", field_type, stmt.op, value_expr)
+                special = special or self._convert_floored_aug_assignment(stmt, f"obj.{field_name}", value_expr)
+                return special or f"    obj.{field_name} {op} {value_expr}"
+        dict_update = self._convert_dict_aug_assignment(stmt, lambda e: self._convert_method_expression(e, class_name))
+        if dict_update is not None:
+            return dict_update
 
         raise UnsupportedFeatureError(f"Complex augmented assignment not supported: {ast.unparse(stmt)}")
 
@@ -366,13 +2505,21 @@ class MGenPythonToGoConverter:
         """Convert method return statement."""
         if stmt.value:
             value_expr = self._convert_method_expression(stmt.value, class_name)
-            return f"    return {value_expr}"
-        return "    return"
+            method = self.struct_info[self.method_owner or class_name]["methods"].get(self.current_function or "")
+            if method is not None:
+                value_expr = self._convert_class_value(stmt.value, value_expr, self._method_types(method["node"])[1])
+            return self._return_statement(value_expr)
+        return self._return_statement(None)
 
     def _convert_method_if(self, stmt: ast.If, class_name: str) -> str:
-        """Convert if statement in method context."""
+        """Convert if statement in method context.
+
+        Names first assigned in a branch are declared in that branch's Go block.
+        """
         condition = self._convert_method_expression(stmt.test, class_name)
+        declared = set(self.declared_vars)
         then_body = self._convert_method_statements(stmt.body, class_name)
+        self.declared_vars = set(declared)
         if_part = f"    if {condition} {{\n{then_body}\n    }}"
 
         if stmt.orelse:
@@ -383,6 +2530,7 @@ class MGenPythonToGoConverter:
             else:
                 # regular else
                 else_body = self._convert_method_statements(stmt.orelse, class_name)
+                self.declared_vars = declared
                 return if_part + " else {\n" + else_body + "\n    }"
         else:
             return if_part
@@ -390,26 +2538,46 @@ class MGenPythonToGoConverter:
     def _convert_method_expression(self, expr: ast.expr, class_name: str) -> str:
         """Convert method expression with class context."""
         if isinstance(expr, ast.Attribute):
+            if is_type_name(expr):
+                return self._convert_type_name(expr, lambda e: self._convert_method_expression(e, class_name))
             if isinstance(expr.value, ast.Name) and expr.value.id == "self":
                 # self.attr -> obj.Attr
-                return f"obj.{self._to_camel_case(expr.attr)}"
+                obj_expr = "obj"
             else:
                 # obj.attr or obj.method()
-                obj_expr = self._convert_method_expression(expr.value, class_name)
-                return f"{obj_expr}.{self._to_camel_case(expr.attr)}"
+                obj_expr = self._convert_instance(expr.value, self._convert_method_expression(expr.value, class_name))
+            getter = self._property_getter(expr.value, expr.attr, obj_expr, class_name)
+            return getter or f"{obj_expr}.{self._to_camel_case(expr.attr)}"
         elif isinstance(expr, ast.Call):
-            return self._convert_method_call(expr, class_name)
+            return self._convert_stdin_read(expr) or self._convert_method_call(expr, class_name)
         elif isinstance(expr, ast.BinOp):
             # Handle binary operations with proper obj conversion
-            left = self._convert_method_expression(expr.left, class_name)
-            right = self._convert_method_expression(expr.right, class_name)
+            left = self._coerce_bool_operand(expr.left, self._convert_method_expression(expr.left, class_name), expr.op)
+            right = self._coerce_bool_operand(
+                expr.right, self._convert_method_expression(expr.right, class_name), expr.op
+            )
 
             # Handle Go-specific operators
-            if isinstance(expr.op, ast.Pow):
-                return f"math.Pow({left}, {right})"
-            elif isinstance(expr.op, ast.FloorDiv):
-                # Go integer division is already floor division
-                return f"({left} / {right})"
+            operator_call = self._convert_operator_binop(expr, left, right)
+            if operator_call is not None:
+                return operator_call
+            big_int = self._convert_big_int_binop(expr, left, right)
+            if big_int is not None:
+                return big_int
+            elif isinstance(expr.op, ast.Pow):
+                return self._convert_pow(expr, left, right)
+            elif self._is_py2_int_division(expr):
+                return f"mgen.FloorDivInt({left}, {right})"
+            elif self._is_int_true_division(expr):
+                return f"mgen.TrueDivInt({left}, {right})"
+            elif self._is_percent_format(expr):
+                return f"mgen.PercentFormat({left}, {self._percent_format_args(expr.right, right)})"
+            elif isinstance(expr.op, (ast.FloorDiv, ast.Mod)):
+                return self._convert_floored_operator(expr, left, right)
+            elif self._bytes_operand(expr) is not None:
+                return self._convert_bytes_operator(expr, left, right)
+            elif self._list_operand(expr) is not None:
+                return self._convert_list_operator(expr, left, right)
 
             # Use standard operator mapping from converter_utils
             op = get_standard_binary_operator(expr.op)
@@ -418,8 +2586,12 @@ class MGenPythonToGoConverter:
             return f"({left} {op} {right})"
         elif isinstance(expr, ast.Compare):
             return self._convert_method_compare(expr, class_name)
+        elif isinstance(expr, ast.Subscript):
+            return self._convert_subscript(expr, lambda e: self._convert_method_expression(e, class_name))
+        elif isinstance(expr, ast.JoinedStr):
+            return self._convert_f_string(expr, lambda e: self._convert_method_expression(e, class_name))
         elif isinstance(expr, ast.Name):
-            return expr.id
+            return "obj" if expr.id == "self" else expr.id
         elif isinstance(expr, ast.Constant):
             return self._convert_constant(expr)
         else:
@@ -428,96 +2600,139 @@ class MGenPythonToGoConverter:
     def _convert_method_call(self, expr: ast.Call, class_name: str) -> str:
         """Convert method calls with class context."""
         if isinstance(expr.func, ast.Attribute):
+            if self._is_super_call(expr):
+                return self._convert_super_call(expr, class_name, lambda e: self._convert_method_expression(e, class_name))
+            class_function_call = self._class_function_call(
+                expr, lambda e: self._convert_method_expression(e, class_name), class_name
+            )
+            if class_function_call is not None:
+                return class_function_call
             if isinstance(expr.func.value, ast.Name) and expr.func.value.id == "self":
                 # self.method() -> obj.Method()
                 method_name = self._to_go_method_name(expr.func.attr)
+                self._expect_method_func_types(expr, class_name)
                 args = [self._convert_method_expression(arg, class_name) for arg in expr.args]
-                args_str = ", ".join(args)
+                args_str = ", ".join(self._convert_method_arguments(expr, args, class_name))
                 return f"obj.{method_name}({args_str})"
             else:
                 # Handle string methods and other attribute calls
                 obj_expr = self._convert_method_expression(expr.func.value, class_name)
                 method_name = expr.func.attr
+                self._expect_method_func_types(expr, class_name)
                 args = [self._convert_method_expression(arg, class_name) for arg in expr.args]
-
-                # Handle string methods
-                if method_name in ["upper", "lower", "strip", "find", "replace", "split"]:
-                    if method_name == "upper":
-                        return f"mgen.StrOps.Upper({obj_expr})"
-                    elif method_name == "lower":
-                        return f"mgen.StrOps.Lower({obj_expr})"
-                    elif method_name == "strip":
-                        if args:
-                            return f"mgen.StrOps.StripChars({obj_expr}, {args[0]})"
-                        else:
-                            return f"mgen.StrOps.Strip({obj_expr})"
-                    elif method_name == "find":
-                        return f"mgen.StrOps.Find({obj_expr}, {args[0]})"
-                    elif method_name == "replace":
-                        return f"mgen.StrOps.Replace({obj_expr}, {args[0]}, {args[1]})"
-                    elif method_name == "split":
-                        if args:
-                            return f"mgen.StrOps.SplitSep({obj_expr}, {args[0]})"
-                        else:
-                            return f"mgen.StrOps.Split({obj_expr})"
+                if self._infer_type_from_value(expr.func.value) in BYTES_TYPES:
+                    return self._convert_bytes_method(obj_expr, method_name, expr, args)
+
+                # Handle string methods, unless the receiver is a user class that defines its own
+                if not self._is_class_instance(expr.func.value):
+                    string_call = self._convert_string_method(obj_expr, method_name, args, expr)
+                    if string_call is not None:
+                        return string_call
+                # Fields holding containers (self.items.pop()) get the lowering locals do
+                container_call = self._convert_container_method(obj_expr, method_name, expr, args)
+                if container_call is not None:
+                    return container_call
+                if method_name == "sort":
+                    list_sort = self._convert_list_sort(
+                        expr, obj_expr, lambda e: self._convert_method_expression(e, class_name), class_name
+                    )
+                    if list_sort is not None:
+                        return list_sort
 
                 # Regular method call
-                args_str = ", ".join(args)
+                args_str = ", ".join(self._convert_method_arguments(expr, args, class_name))
                 return f"{obj_expr}.{self._to_go_method_name(method_name)}({args_str})"
         else:
             # Handle regular function calls like len() with method context
             if isinstance(expr.func, ast.Name):
                 func_name = expr.func.id
+                if func_name == "print":
+                    # print() converts its own arguments, which may unpack iterables (*xs)
+                    self._check_builtin_keywords(func_name, expr)
+                    return self._convert_print_call(expr, lambda e: self._convert_method_expression(e, class_name))
+                if self._binds_arguments(func_name, expr):
+                    return self._convert_function_call(
+                        func_name, expr, lambda e: self._convert_method_expression(e, class_name)
+                    )
                 args = [self._convert_method_expression(arg, class_name) for arg in expr.args]
+                self._check_builtin_keywords(func_name, expr)
+                if func_name in ("bytes", "bytearray") and not self._is_user_callable(func_name):
+                    return self._convert_bytes_constructor(
+                        func_name, expr, args, lambda e: self._convert_method_expression(e, class_name)
+                    )
 
                 # Handle built-in functions with generics
                 if func_name == "len":
                     arg_type = self._infer_type_from_value(expr.args[0])
-                    if arg_type.startswith("[]"):
+                    sized = self._convert_operator_call(expr.args[0], args[0], "__len__", [])
+                    if sized is not None:
+                        return sized
+                    elif arg_type.startswith("[]"):
                         elem_type = arg_type[2:]
                         return f"mgen.Len[{elem_type}]({args[0]})"
-                    elif arg_type.startswith("map["):
-                        return f"mgen.LenMap({args[0]})"
                     elif arg_type == "string":
-                        return f"mgen.LenString({args[0]})"
+                        return self._convert_string_len(expr.args[0], args[0])
+                    elif self._is_sized_container(arg_type):
+                        return f"{args[0]}.Len()"
                     else:
-                        # Default to generic slice
-                        return f"mgen.Len({args[0]})"
+                        return f"mgen.LenValue({args[0]})"
                 elif func_name == "abs":
-                    arg_type = self._infer_type_from_value(expr.args[0])
-                    if arg_type == "float64" or arg_type == "float32":
-                        return f"mgen.AbsFloat({args[0]})"
-                    else:
-                        return f"mgen.AbsInt({args[0]})"
-                elif func_name == "min":
-                    arg_type = self._infer_type_from_value(expr.args[0])
-                    elem_type = arg_type[2:] if arg_type.startswith("[]") else "int"
-                    return f"mgen.Min[{elem_type}]({args[0]})"
-                elif func_name == "max":
-                    arg_type = self._infer_type_from_value(expr.args[0])
-                    elem_type = arg_type[2:] if arg_type.startswith("[]") else "int"
-                    return f"mgen.Max[{elem_type}]({args[0]})"
+                    return self._convert_abs_call(expr.args[0], args[0])
+                elif func_name in ("min", "max"):
+                    return self._convert_min_max_call(func_name, expr, args)
                 elif func_name == "sum":
-                    arg_type = self._infer_type_from_value(expr.args[0])
-                    elem_type = arg_type[2:] if arg_type.startswith("[]") else "int"
-                    return f"mgen.Sum[{elem_type}]({args[0]})"
+                    return self._convert_sum_call(expr, args)
                 elif func_name == "bool":
                     return f"mgen.ToBool({args[0]})"
-                elif func_name == "int":
-                    return f"mgen.ToInt({args[0]})"
-                elif func_name == "float":
-                    return f"mgen.ToFloat({args[0]})"
+                elif func_name in ("int", "float"):
+                    return self._convert_number_constructor(
+                        func_name, expr, args, lambda e: self._convert_method_expression(e, class_name)
+                    )
                 elif func_name == "str":
                     return f"mgen.ToStr({args[0]})"
+                elif func_name == "repr":
+                    return f"mgen.Repr({args[0]})"
+                elif func_name == "ascii":
+                    return f"mgen.Ascii({args[0]})"
+                elif func_name == "hash" and len(args) == 1 and not self._is_user_callable(func_name):
+                    return self._convert_hash_call(expr.args[0], args[0])
+                elif func_name in ("ord", "chr") and len(args) == 1:
+                    return f"mgen.{func_name.capitalize()}({args[0]})"
+                elif func_name == "format":
+                    spec = args[1] if len(args) > 1 else '""'
+                    return f"mgen.Format({args[0]}, {spec})"
+                elif func_name == "open":
+                    mode = self._open_mode(expr, args, lambda e: self._convert_method_expression(e, class_name))
+                    return f"mgen.Open({args[0]}, {mode})"
                 elif func_name == "range":
                     range_args = ", ".join(args)
                     return f"mgen.NewRange({range_args})"
+                elif func_name in ("getattr", "setattr") and func_name not in self.function_return_types:
+                    return self._convert_attr_builtin(func_name, expr, args, class_name)
+                elif func_name in ("sorted", "reversed", "enumerate", "zip") and func_name not in self.function_return_types:
+                    return self._convert_iteration_builtin(
+                        func_name, expr, args, lambda e: self._convert_method_expression(e, class_name)
+                    )
+                elif func_name in ("next", "iter") and func_name not in self.function_return_types:
+                    return self._convert_iterator_builtin(func_name, expr, args)
+                elif (
+                    func_name == "isinstance"
+                    and self._isinstance_classes(expr)
+                    and not self._is_user_callable(func_name)
+                ):
+                    return self._convert_isinstance(expr, args[0])
                 else:
                     # Check if this is a class constructor
                     if func_name in self.struct_info:
-                        args_str = ", ".join(args)
-                        return f"New{func_name}({args_str})"
+                        return self._convert_constructor_call(
+                            func_name, expr, lambda e: self._convert_method_expression(e, class_name)
+                        )
                     else:
+                        param_types = self.function_param_types.get(func_name, [])
+                        args = [
+                            self._convert_class_value(arg, arg_expr, param_type)
+                            for arg, arg_expr, param_type in zip(expr.args, args, param_types)
+                        ] + args[len(param_types) :]
                         args_str = ", ".join(args)
                         return f"{func_name}({args_str})"
             else:
@@ -526,7 +2741,13 @@ class MGenPythonToGoConverter:
     def _convert_method_compare(self, expr: ast.Compare, class_name: str) -> str:
         """Convert comparison expressions in method context."""
         left = self._convert_method_expression(expr.left, class_name)
+        operator_call = self._convert_operator_compare(
+            expr, left, lambda e: self._convert_method_expression(e, class_name)
+        )
+        if operator_call is not None:
+            return operator_call
         result = left
+        left_node = expr.left
 
         for op, comp in zip(expr.ops, expr.comparators):
             # Use standard comparison operator mapping from converter_utils
@@ -538,30 +2759,249 @@ class MGenPythonToGoConverter:
                     op_str = "=="
                 elif isinstance(op, ast.IsNot):
                     op_str = "!="
+                elif isinstance(op, (ast.In, ast.NotIn)):
+                    result = self._convert_membership(
+                        op,
+                        expr.left,
+                        result,
+                        comp,
+                        lambda e: self._convert_method_expression(e, class_name),
+                        comp is expr.comparators[0],
+                    )
+                    continue
                 else:
                     op_str = "/*UNKNOWN_OP*/"
+            elif self._mixes_bool_and_number(left_node, comp) or self._compares_sequences(left_node, comp):
+                # Compared by their items at runtime, as in _convert_compare
+                comp_expr = self._convert_method_expression(comp, class_name)
+                result = f'mgen.Compare("{op_str}", {result}, {comp_expr})'
+                left_node = comp
+                continue
 
             comp_expr = self._convert_method_expression(comp, class_name)
             result = f"({result} {op_str} {comp_expr})"
+            left_node = comp
 
         return result
 
-    def _convert_function(self, node: ast.FunctionDef) -> str:
-        """Convert Python function to Go function."""
+    def _collect_reference_params(self) -> None:
+        """Find the list parameters each undecorated module function takes by pointer (see _reference_lists).
+
+        A parameter passed on to a function taking it by pointer is grown in
+        place too, so the sets grow until no function's changes.
+        """
+        functions = [
+            func for name, func in self.function_nodes.items() if name != "main" and not self._is_generator(func)
+        ]
+        changed = True
+        while changed:
+            changed = False
+            for func in functions:
+                param_types = {
+                    arg.arg: self._infer_parameter_type(arg, func) for arg in [*func.args.posonlyargs, *func.args.args]
+                }
+                grown = self._grown_lists(func.body)
+                params = {
+                    name
+                    for name, param_type in param_types.items()
+                    if param_type.startswith("[]") and name in grown and name not in self._unshareable_names(func)
+                }
+                if params != self.reference_params.get(func.name, set()):
+                    self.reference_params[func.name] = params
+                    changed = True
+
+    def _grown_lists(self, body: list[ast.stmt]) -> set[str]:
+        """Return the names a function body grows or shrinks in place, directly or through a b = a alias."""
+        grown: set[str] = set()
+        for node in self._local_nodes(body):
+            if isinstance(node, ast.AugAssign) and isinstance(node.target, ast.Name):
+                grown.add(node.target.id)
+            elif isinstance(node, ast.Delete):
+                targets = [target.value for target in node.targets if isinstance(target, ast.Subscript)]
+                grown.update(target.id for target in targets if isinstance(target, ast.Name))
+            elif isinstance(node, ast.Call) and isinstance(node.func, ast.Attribute):
+                if node.func.attr in LIST_MUTATORS and isinstance(node.func.value, ast.Name):
+                    grown.add(node.func.value.id)
+            elif isinstance(node, ast.Call) and isinstance(node.func, ast.Name) and node.func.id in self.function_nodes:
+                params = self.reference_params.get(node.func.id)
+                if params:
+                    callee = self.function_nodes[node.func.id]
+                    positional = [arg.arg for arg in [*callee.args.posonlyargs, *callee.args.args]]
+                    passed = [*zip(positional, node.args), *((kw.arg, kw.value) for kw in node.keywords)]
+                    grown.update(value.id for name, value in passed if name in params and isinstance(value, ast.Name))
+        for group in self._alias_groups(body):
+            if group & grown:
+                grown |= group
+        return grown
+
+    def _alias_groups(self, body: list[ast.stmt]) -> list[set[str]]:
+        """Return the sets of names a function body binds to one another (b = a, or a = b = value),
+        which may share a list."""
+        groups: dict[str, set[str]] = {}
+        for node in self._local_nodes(body):
+            if isinstance(node, ast.Assign) and all(isinstance(target, ast.Name) for target in node.targets):
+                bound = [target.id for target in node.targets if isinstance(target, ast.Name)]
+                if isinstance(node.value, ast.Name):
+                    bound.append(node.value.id)
+                if len(bound) < 2:
+                    continue
+                group = set().union(*(groups.get(name, {name}) for name in bound))
+                for name in group:
+                    groups[name] = group
+        return list({id(group): group for group in groups.values()}.values())
+
+    def _unshareable_names(self, func: ast.FunctionDef) -> set[str]:
+        """Return the names of a function that cannot be held by pointer.
+
+        Those are the names bound other than by assigning names (by for
+        loops, unpacking, with, except, walrus, match or del), declared global
+        or nonlocal, or used by a nested function or lambda.
+        """
+        names: set[str] = set()
+        plain: set[int] = set()
+        nested: list[ast.AST] = []
+        for node in self._local_nodes(func.body):
+            if isinstance(node, ast.Assign) and all(isinstance(target, ast.Name) for target in node.targets):
+                plain.update(id(target) for target in node.targets)
+            elif isinstance(node, (ast.AnnAssign, ast.AugAssign)) and isinstance(node.target, ast.Name):
+                plain.add(id(node.target))
+            elif isinstance(node, ast.comprehension):
+                plain.update(id(n) for n in ast.walk(node.target))
+            elif isinstance(node, (ast.Global, ast.Nonlocal)):
+                names.update(node.names)
+            elif isinstance(node, ast.ExceptHandler) and node.name:
+                names.add(node.name)
+            elif isinstance(node, (ast.MatchAs, ast.MatchStar)) and node.name:
+                names.add(node.name)
+            elif isinstance(node, ast.MatchMapping) and node.rest:
+                names.add(node.rest)
+            elif isinstance(node, (ast.FunctionDef, ast.Lambda)) and node is not func:
+                nested.append(node)
+        for node in self._local_nodes(func.body):
+            if isinstance(node, ast.Name) and not isinstance(node.ctx, ast.Load) and id(node) not in plain:
+                names.add(node.id)
+        names.update(n.id for node in nested for n in ast.walk(node) if isinstance(n, ast.Name))
+        return names
+
+    def _reference_lists(self, node: ast.FunctionDef) -> set[str]:
+        """Return the list variables of a function that are held by pointer.
+
+        A Go slice variable is a header of its own: growing it rebinds only
+        that variable, while a Python list is one object every name for it
+        sees grow. So a list parameter the function grows or shrinks in place
+        is a *[]T its callers pass their list's address as, and local lists
+        that share a list (b = a) one of them grows are *[]T variables
+        pointing to the same slice. Reads dereference them, assigning a new list points the name
+        to it, and b = a copies the pointer.
+
+        Example:
+            def grow(items: list[int]) -> None:  →  func grow(items *[]int) {
+                items += [9]                              mgen.ExtendSlice(items, []int{9})
+            b = a                                 →  var b *[]int = a
+            grow(b); grow(c)                      →  grow(b); grow(&c)
+        """
+        refs = set(self.reference_params.get(node.name, set()))
+        groups = self._alias_groups(node.body)
+        if not groups:
+            return refs
+        grown = self._grown_lists(node.body)
+        unshareable = self._unshareable_names(node)
+        params = {arg.arg for arg in [*node.args.posonlyargs, *node.args.args]}
+        top_level = {
+            target.id: stmt.lineno
+            for stmt in reversed(node.body)
+            for target in (stmt.targets if isinstance(stmt, ast.Assign) else [getattr(stmt, "target", None)])
+            if isinstance(stmt, (ast.Assign, ast.AnnAssign)) and isinstance(target, ast.Name)
+        }
+        first_bound: dict[str, int] = {}
+        for child in self._local_nodes(node.body):
+            if isinstance(child, ast.Name) and isinstance(child.ctx, ast.Store):
+                first_bound[child.id] = min(first_bound.get(child.id, child.lineno), child.lineno)
+
+        for group in groups:
+            if not group & grown:
+                # Lists only read or written item by item share their backing array anyway
+                continue
+            list_types = {self.variable_types.get(name, "") for name in group} - {"", "interface{}"}
+            if len(list_types) != 1 or not next(iter(list_types)).startswith("[]"):
+                continue
+            if any(
+                name in unshareable
+                or (name in params and name not in refs)
+                or (name not in params and top_level.get(name) != first_bound.get(name))
+                for name in group
+            ):
+                continue
+            list_type = next(iter(list_types))
+            for name in group:
+                self.variable_types[name] = list_type
+            refs |= group
+        return refs
+
+    @staticmethod
+    def _address_of(value_expr: str) -> str:
+        """Return a pointer to an addressable value: &xs, or p itself for a list held by pointer, (*p)."""
+        pointer = re.fullmatch(r"\(\*(\w+)\)", value_expr)
+        return pointer.group(1) if pointer else f"&{value_expr}"
+
+    def _convert_reference_assignment(self, stmt: Union[ast.Assign, ast.AnnAssign]) -> Optional[str]:
+        """Convert an assignment to a list variable held by pointer (see _reference_lists), else return None."""
+        targets = stmt.targets if isinstance(stmt, ast.Assign) else [stmt.target]
+        target = targets[0]
+        if not all(isinstance(name, ast.Name) and name.id in self.reference_lists for name in targets):
+            return None
+        if len(targets) > 1:
+            # a = b = [1]: a is assigned the list, and b = a then shares it
+            shared = [ast.Assign(targets=[name], value=ast.Name(id=target.id, ctx=ast.Load())) for name in targets[1:]]
+            assignments = [ast.Assign(targets=[target], value=stmt.value), *shared]
+            return "\n".join(self._convert_assignment(assignment) for assignment in assignments)
+        assert isinstance(target, ast.Name)
+        if stmt.value is None:
+            raise UnsupportedFeatureError(f"Shared list {target.id} must be assigned where it is declared")
+        list_type = self.variable_types[target.id]
+        if isinstance(stmt.value, ast.Name) and stmt.value.id in self.reference_lists:
+            # b = a: both names refer to one list
+            value_expr = stmt.value.id
+        else:
+            value_expr = f"mgen.Ref({self._convert_argument(stmt.value, list_type, self._convert_expression)})"
+        if target.id in self.declared_vars:
+            return f"    {target.id} = {value_expr}"
+        self.declared_vars.add(target.id)
+        return f"    var {target.id} *{list_type} = {value_expr}"
+
+    def _convert_reference_argument(self, value: ast.expr, value_expr: str) -> str:
+        """Convert the argument of a list parameter taken by pointer: the address of the caller's list."""
+        if isinstance(value, (ast.Name, ast.Attribute)) and re.fullmatch(r"\(\*\w+\)|\w+(\.\w+)*", value_expr):
+            return self._address_of(value_expr)
+        return f"mgen.Ref({value_expr})"
+
+    def _convert_function(self, node: ast.FunctionDef, captured: Optional[dict[str, str]] = None) -> str:
+        """Convert Python function to Go function.
+
+        captured holds the enclosing function's variables (name -> Go type) that a
+        nested function refers to rather than redeclares.
+        """
         # Pre-pass: Analyze nested subscripts to detect 2D arrays
         nested_vars = self._analyze_nested_subscripts(node.body)
         append_map = self._analyze_append_operations(node.body)
+        if captured is None:
+            # A nested function is a closure, which sees the type parameters of the function it is in
+            self.type_params = {type_var.name: type_var for type_var in self.generic_functions.get(node.name, [])}
 
         # Build parameter list
         params = []
-        for arg in node.args.args:
+        for arg in [*node.args.posonlyargs, *node.args.args]:
             param_type = self._infer_parameter_type(arg, node)
 
             # If parameter is used with nested subscripting and is bare slice, make it 2D
             if arg.arg in nested_vars and param_type == "[]int":
                 param_type = "[][]int"
+            if captured is None and arg.arg in self.reference_params.get(node.name, ()):
+                param_type = f"*{param_type}"
 
             params.append(f"{arg.arg} {param_type}")
+        extra_params = self._extra_parameters(node)
+        params.extend(f"{name} {param_type}" for name, param_type in extra_params)
 
         params_str = ", ".join(params)
 
@@ -570,7 +3010,7 @@ class MGenPythonToGoConverter:
         return_type = ""
         if node.name != "main":
             if node.returns:
-                mapped_type = self._map_type_annotation(node.returns)
+                mapped_type = self._big_int_result(node.name, self._map_return_annotation(node.returns))
                 # Check if return type should be nested based on usage
                 if mapped_type == "[]int":
                     # Check if any variable in body that could be returned is nested
@@ -588,28 +3028,52 @@ class MGenPythonToGoConverter:
                     return_type = " " + inferred_type
 
         # Build function signature
+        type_params = ""
         func_signature = f"func {node.name}({params_str}){return_type}"
 
         # Convert function body
         self.current_function = node.name
-        self.declared_vars = set()  # Reset for new function
-        self.variable_types = {}  # Reset variable type tracking for new function
+        self.declared_vars = set(captured or {})  # Reset for new function
+        self.variable_types = dict(captured or {})  # Reset variable type tracking for new function
+        self._enter_global_scope(node, captured is None)
         self.nested_vars = nested_vars  # Store for use in type inference
         self.append_map = append_map
 
         # Add parameters to variable types first
-        for arg in node.args.args:
+        for arg in [*node.args.posonlyargs, *node.args.args]:
             param_type = self._infer_parameter_type(arg, node)
             # Apply nested upgrade to parameter types
             if arg.arg in nested_vars and param_type == "[]int":
                 param_type = "[][]int"
             self.variable_types[arg.arg] = param_type
+        self.variable_types.update(extra_params)
+        for arg in node.args.kwonlyargs if extra_params else []:
+            self.variable_types[arg.arg] = self._infer_parameter_type(arg, node)
 
         # Pre-pass: infer all variable types including nested container upgrades
         self._pre_infer_variable_types(node.body)
+        self._lower_list_queues(node)
+        self.reference_lists = self._reference_lists(node) if captured is None else set()
+        for name in self.int_ranges.variables.get(node.name, ()):
+            # A local the pre-pass left untyped is an int derived from the unbounded ones (c = a + b)
+            local_type = self.variable_types.get(name, "int")
+            if local_type == "interface{}":
+                local_type = "int"
+            self.variable_types[name] = self._big_int_variable(node.name, name, local_type)
+        if self.type_params and captured is None:
+            type_params = self._convert_type_params(node)
+            func_signature = f"func {node.name}{type_params}({params_str}){return_type}"
+
+        # A generator function returns the lazy iterator its body feeds (see _convert_yield)
+        self.generator_item_type = self._generator_item_type(node) if self._is_generator(node) else None
+        if self.generator_item_type is not None:
+            return_type = f" mgen.Iterator[{self.generator_item_type}]"
+            func_signature = f"func {node.name}{type_params}({params_str}){return_type}"
 
         # After pre-pass, check if return type needs upgrade based on inferred variable types
-        if return_type and ("map[int]" in return_type or "[]int" == return_type.strip()):
+        if return_type and (
+            "*mgen.Dict[int, " in return_type or return_type.strip() in ("[]int", go_set_type("int"))
+        ):
             for stmt in node.body:
                 if isinstance(stmt, ast.Return) and stmt.value and isinstance(stmt.value, ast.Name):
                     returned_var = stmt.value.id
@@ -618,7 +3082,7 @@ class MGenPythonToGoConverter:
                         # Upgrade return type if variable was upgraded
                         if var_type != return_type.strip():
                             return_type = " " + var_type
-                            func_signature = f"func {node.name}({params_str}){return_type}"
+                            func_signature = f"func {node.name}{type_params}({params_str}){return_type}"
                             break
 
         # Update function_return_types with the final return type
@@ -626,13 +3090,27 @@ class MGenPythonToGoConverter:
             self.function_return_types[node.name] = return_type.strip()
 
         # Add parameters to declared variables
-        for arg in node.args.args:
+        for arg in [*node.args.posonlyargs, *node.args.args]:
             self.declared_vars.add(arg.arg)
-
-        body = self._convert_statements(node.body)
+        self.declared_vars.update(name for name, _ in extra_params)
+        options = self._unpack_function_options(node) if extra_params else []
+
+        converted = options + [self._convert_statement(stmt) for stmt in node.body]
+        body = "\n".join(converted)
+        returns_value = return_type and self.generator_item_type is None
+        if returns_value and node.body and isinstance(node.body[-1], (ast.Try, ast.With)):
+            # Go cannot see that a try or with whose clauses all return leaves no path to the end
+            body += '\n    panic("unreachable")'
+        elif returns_value and node.body and isinstance(node.body[-1], ast.Match):
+            last_case = node.body[-1].cases[-1]
+            if self._match_needs_flag(node.body[-1]) or last_case.guard is not None or not (
+                isinstance(last_case.pattern, ast.MatchAs) and last_case.pattern.pattern is None
+            ):
+                # Without a final else branch Go cannot see that every case returns
+                body += '\n    panic("unreachable")'
 
         # Detect unused variables and mark them with _ = variable
-        unused_vars = self._detect_unused_variables(node.body)
+        unused_vars = self._detect_unused_variables(node.body) - self.global_vars
         if unused_vars:
             # Add _ = var statements at the end of the function body before return
             unused_statements = []
@@ -645,22 +3123,517 @@ class MGenPythonToGoConverter:
                 body_lines = body.rstrip().split("\n")
                 insert_pos = len(body_lines)
 
-                # Find the last return statement
-                for i in range(len(body_lines) - 1, -1, -1):
-                    if "return" in body_lines[i]:
-                        insert_pos = i
-                        break
+                if isinstance(node.body[-1], ast.Return):
+                    # Before the final return, which may itself hold a comprehension's closure
+                    insert_pos -= len(converted[-1].rstrip().split("\n"))
+                elif returns_value:
+                    # Find the last return statement, in the branches of a final if
+                    for i in range(len(body_lines) - 1, -1, -1):
+                        if "return" in body_lines[i]:
+                            insert_pos = i
+                            break
 
                 # Insert the unused markers
                 body_lines[insert_pos:insert_pos] = unused_statements
                 body = "\n".join(body_lines) + "\n"
 
+        if self.generator_item_type is not None:
+            constructor = self._generator_constructor()
+            body = f"    return {constructor}(func(yield func({self.generator_item_type})) {{\n{body}\n    }})"
+        self.reference_lists = set()
+        if node.name in self.cached_functions and captured is None:
+            return self._convert_cached_function(node, func_signature, return_type.strip(), body)
+
         self.current_function = None
+        self.generator_item_type = None
+        if captured is None:
+            self.type_params = {}
         self.nested_vars = set()  # Clear
         self.append_map = {}
 
+        if node.args.kwonlyargs:
+            return self._convert_function_options(node) + "\n\n" + func_signature + " {\n" + body + "\n}"
         return func_signature + " {\n" + body + "\n}"
 
+    def _extra_parameters(self, node: ast.FunctionDef) -> list[tuple[str, str]]:
+        """Return the Go (name, type) parameters a module function's *args, keyword-only and **kwargs become.
+
+        *args is a slice and **kwargs a string-keyed dict of the annotated
+        item type. Keyword-only parameters arrive in an options struct (see
+        _convert_function_options), like a constructor's.
+
+        Example:
+            def f(*nums: int, sep: str = " ", **extra: str)
+                →  nums []int, opts fOptions, extra *mgen.Dict[string, string]
+        """
+        args = node.args
+        if not (args.vararg or args.kwonlyargs or args.kwarg):
+            return []
+        if self.function_nodes.get(node.name) is not node:
+            raise UnsupportedFeatureError(
+                f"*args, keyword-only and **kwargs parameters are only supported in undecorated module functions: "
+                f"{node.name}()"
+            )
+        params = []
+        if args.vararg is not None:
+            params.append((args.vararg.arg, f"[]{self._star_parameter_type(args.vararg)}"))
+        if args.kwonlyargs:
+            params.append((self._options_parameter(node), self._function_options_name(node.name)))
+        if args.kwarg is not None:
+            params.append((args.kwarg.arg, f"*mgen.Dict[string, {self._star_parameter_type(args.kwarg)}]"))
+        return params
+
+    def _star_parameter_type(self, arg: ast.arg) -> str:
+        """Return the Go type of the items of *args or **kwargs, which is what their annotation names."""
+        return self._map_type_annotation(arg.annotation) if arg.annotation else "interface{}"
+
+    def _function_options_name(self, func_name: str) -> str:
+        """Return the name of the struct holding a module function's keyword-only arguments: scale -> scaleOptions."""
+        camel = self._to_camel_case(func_name)
+        return camel[:1].lower() + camel[1:] + "Options"
+
+    def _options_parameter(self, node: ast.FunctionDef) -> str:
+        """Return the name of the parameter holding the options struct, one the function does not use otherwise."""
+        used = {name.id for name in ast.walk(node) if isinstance(name, ast.Name)}
+        used.update(arg.arg for arg in ast.walk(node.args) if isinstance(arg, ast.arg))
+        name = "opts"
+        while name in used:
+            name += "_"
+        return name
+
+    def _convert_function_options(self, node: ast.FunctionDef) -> str:
+        """Generate the options struct holding a module function's keyword-only arguments.
+
+        Like a constructor's {class_name}Options, call sites fill in every
+        field, using the parameter's default when the keyword is not passed.
+        """
+        lines = [f"type {self._function_options_name(node.name)} struct {{"]
+        for arg in node.args.kwonlyargs:
+            lines.append(f"    {self._to_camel_case(arg.arg)} {self._infer_parameter_type(arg, node)}")
+        lines.append("}")
+        return "\n".join(lines)
+
+    def _unpack_function_options(self, node: ast.FunctionDef) -> list[str]:
+        """Unpack the keyword-only arguments the function body uses from its options struct into locals."""
+        used = {name.id for stmt in node.body for name in ast.walk(stmt) if isinstance(name, ast.Name)}
+        names = [arg.arg for arg in node.args.kwonlyargs if arg.arg in used]
+        if not names:
+            return []
+        self.declared_vars.update(names)
+        options = self._options_parameter(node)
+        fields = ", ".join(f"{options}.{self._to_camel_case(name)}" for name in names)
+        return [f"    {', '.join(names)} := {fields}"]
+
+    def _convert_type_params(self, node: ast.FunctionDef) -> str:
+        """Return the type parameter list of a function generic over TypeVars.
+
+        A constrained TypeVar allows the union of its types and a bound one
+        its bound's type. Any other is any, unless the body orders its values
+        (mgen.Ordered) or compares or hashes them (comparable), which Go only
+        allows of type parameters constrained to support it.
+
+        Example:
+            T = TypeVar("T")              →  T any
+            N = TypeVar("N", int, float)  →  N int | float64
+            T, with best < x in the body  →  T mgen.Ordered
+        """
+        used_constraints = self._type_param_usage(node)
+        params = []
+        for type_var in self.generic_functions[node.name]:
+            bound = self._map_type_annotation(type_var.bound) if type_var.bound is not None else "interface{}"
+            if type_var.constraints:
+                constraint = " | ".join(self._map_type_annotation(item) for item in type_var.constraints)
+            elif bound != "interface{}":
+                constraint = bound
+            else:
+                constraint = used_constraints.get(type_var.name, "any")
+            params.append(f"{type_var.name} {constraint}")
+        return f"[{', '.join(params)}]"
+
+    def _type_param_usage(self, node: ast.FunctionDef) -> dict[str, str]:
+        """Return the constraint each type parameter needs for what the function body does with its values."""
+        ordered: set[str] = set()
+        compared: set[str] = set()
+
+        def type_param(expr: ast.expr) -> Optional[str]:
+            go_type = self._infer_type_from_value(expr)
+            return go_type if go_type in self.type_params else None
+
+        for child in ast.walk(node):
+            if isinstance(child, ast.Compare):
+                operands = [child.left, *child.comparators]
+                for op, left, right in zip(child.ops, operands, operands[1:]):
+                    used = {name for name in (type_param(left), type_param(right)) if name is not None}
+                    if isinstance(op, (ast.Lt, ast.LtE, ast.Gt, ast.GtE)):
+                        ordered.update(used)
+                    elif isinstance(op, (ast.Eq, ast.NotEq)):
+                        compared.update(used)
+            elif isinstance(child, ast.Call) and isinstance(child.func, ast.Name) and child.args:
+                if child.func.id in ("min", "max", "sorted") and not child.keywords:
+                    items = child.args if len(child.args) > 1 else []
+                    element_type = self._infer_type_from_value(child.args[0])
+                    if element_type.startswith("[]") and element_type[2:] in self.type_params:
+                        ordered.add(element_type[2:])
+                    ordered.update(name for name in map(type_param, items) if name is not None)
+        signature = [*self.function_param_types.get(node.name, []), self.function_return_types.get(node.name, "")]
+        for go_type in [*self.variable_types.values(), *signature]:
+            # Dict keys and set members are hashed
+            for name in self.type_params:
+                if re.search(rf"mgen\.(Dict|Set)\[{re.escape(name)}[,\]]", go_type):
+                    compared.add(name)
+        return {name: "mgen.Ordered" if name in ordered else "comparable" for name in ordered | compared}
+
+    def _generic_call_type(self, call: ast.Call) -> Optional[str]:
+        """Return the result type of a call to a generic function for the type arguments it passes, or None.
+
+        Example:
+            first([1, 2])  →  int   (def first(xs: list[T]) -> T)
+        """
+        if not (isinstance(call.func, ast.Name) and call.func.id in self.generic_functions):
+            return None
+        bindings = self._type_arguments(call.func.id, call)
+        return substitute_type_params(self.function_return_types.get(call.func.id, ""), bindings)
+
+    def _type_arguments(self, func_name: str, call: ast.Call) -> dict[str, str]:
+        """Return the types a call of a generic function binds its type parameters to, as Go infers them.
+
+        Lambdas are typed last, from the parameters the other arguments bind:
+        apply(lambda x: x * 2, 3) binds T to int, then U to the lambda's int
+        result (def apply(f: Callable[[T], U], x: T) -> U).
+        """
+        names = [type_var.name for type_var in self.generic_functions[func_name]]
+        param_types = self.function_param_types.get(func_name, [])
+        bindings: dict[str, str] = {}
+        arguments = sorted(zip(call.args, param_types), key=lambda pair: isinstance(pair[0], ast.Lambda))
+        for arg, param_type in arguments:
+            if isinstance(arg, ast.Lambda):
+                signature = self._split_func_type(substitute_type_params(param_type, bindings))
+                if signature is None:
+                    continue
+                lambda_params = signature[0]
+                result_type = self._lambda_result_type(arg, lambda_params)
+                arg_type = f"func({', '.join(lambda_params)}) {result_type}".rstrip()
+            elif isinstance(arg, ast.Starred):
+                break
+            else:
+                arg_type = self._infer_type_from_value(arg)
+            trial = dict(bindings)
+            if bind_type_params(param_type, arg_type, names, trial):
+                bindings = trial
+        return bindings
+
+    def _collect_decorators(self, node: ast.Module) -> dict[str, ast.FunctionDef]:
+        """Collect the module's functions that decorate its other functions.
+
+        Such a decorator is specialized to each function it decorates (see
+        _convert_decorated_function), so it can only be applied with @. Other
+        decorators than functools.lru_cache and cache are rejected.
+        """
+        functions = {item.name: item for item in node.body if isinstance(item, ast.FunctionDef)}
+        decorators: dict[str, ast.FunctionDef] = {}
+        applied: set[int] = set()
+        for function in functions.values():
+            for decorator in function.decorator_list:
+                target = decorator.func if isinstance(decorator, ast.Call) else decorator
+                if isinstance(target, ast.Name) and target.id in functions:
+                    if target is not decorator:
+                        raise UnsupportedFeatureError(
+                            f"Decorator factories are not supported: @{ast.unparse(decorator)}"
+                        )
+                    decorators[target.id] = functions[target.id]
+                    applied.add(id(decorator))
+                elif ast.unparse(target).rsplit(".", 1)[-1] not in ("lru_cache", "cache"):
+                    raise UnsupportedFeatureError(f"Unsupported decorator: @{ast.unparse(decorator)}")
+        for item in node.body:
+            methods = item.body if isinstance(item, ast.ClassDef) else []
+            for method in methods:
+                for decorator in getattr(method, "decorator_list", []):
+                    target = decorator.func if isinstance(decorator, ast.Call) else decorator
+                    if isinstance(target, ast.Name) and target.id in functions:
+                        applied_to = f"{item.name}.{method.name}"
+                        raise UnsupportedFeatureError(
+                            f"Decorators on methods are not supported: @{ast.unparse(decorator)} on {applied_to}"
+                        )
+        for name, decorator in decorators.items():
+            args = decorator.args
+            if len(args.args) != 1 or args.posonlyargs or args.kwonlyargs or args.vararg or args.kwarg:
+                raise UnsupportedFeatureError(f"Decorator {name} must take just the function it decorates")
+            if decorator.decorator_list:
+                raise UnsupportedFeatureError(f"Decorated decorators are not supported: {name}")
+        for child in ast.walk(node):
+            if isinstance(child, ast.Name) and child.id in decorators and id(child) not in applied:
+                raise UnsupportedFeatureError(f"Decorator {child.id} can only be applied with @")
+        return decorators
+
+    def _convert_decorated_function(self, node: ast.FunctionDef) -> str:
+        """Convert a function with decorators defined in the module to a func variable set by init().
+
+        The function itself becomes <name>Wrapped and each decorator a copy
+        typed for it (see decorators.specialize_decorator). The decorators are
+        applied bottom-up before main runs, as Python applies them at definition
+        time, and every call, recursive ones too, goes through the variable.
+
+        Example:
+            @logged                              func addWrapped(a int, b int) int {...}
+            def add(a: int, b: int) -> int:  →   func loggedAdd(func_ func(int, int) int) func(int, int) int {...}
+                ...                              var add func(int, int) int
+                                                 func init() {
+                                                     add = loggedAdd(addWrapped)
+                                                 }
+        """
+        args = node.args
+        annotated = node.returns is not None and all(arg.annotation is not None for arg in args.args)
+        if args.posonlyargs or args.kwonlyargs or args.vararg or args.kwarg or args.defaults or not annotated:
+            raise UnsupportedFeatureError(
+                f"Decorated function {node.name} needs annotated positional parameters without defaults "
+                "and an annotated return type"
+            )
+        if node.name == "main" or self._is_generator(node) or node.name in self.cached_functions:
+            raise UnsupportedFeatureError(f"Unsupported decorators on {node.name}")
+        code = self._convert_function(node)
+        parts = [f"func {node.name}Wrapped" + code[len(f"func {node.name}") :]]
+        param_types = ", ".join(self.function_param_types[node.name])
+        func_type = f"func({param_types}) {self.function_return_types[node.name]}".rstrip()
+        value = f"{node.name}Wrapped"
+        for decorator in reversed(node.decorator_list):
+            assert isinstance(decorator, ast.Name)
+            name = f"{decorator.id}{self._to_camel_case(node.name)}"
+            if name not in self.function_return_types:
+                # A decorator applied twice is specialized once
+                self.function_param_types[name] = [func_type]
+                self.function_return_types[name] = func_type
+                parts.append(self._convert_function(specialize_decorator(self.decorators[decorator.id], node, name)))
+            value = f"{name}({value})"
+        parts.append(f"var {node.name} {func_type}")
+        parts.append(f"func init() {{\n    {node.name} = {value}\n}}")
+        return "\n\n".join(parts)
+
+    def _cache_decorator(self, node: ast.FunctionDef) -> Optional[str]:
+        """Return the mgen.NewLRUCache call for a function decorated with functools.lru_cache or cache, else None.
+
+        maxsize=None (and @cache) is -1, an unbounded cache; Python treats a
+        negative maxsize as 0, which caches nothing.
+
+        Example:
+            @lru_cache(maxsize=32)  →  mgen.NewLRUCache(32, false)
+            @functools.cache        →  mgen.NewLRUCache(-1, false)
+        """
+        for decorator in node.decorator_list:
+            call = decorator if isinstance(decorator, ast.Call) else None
+            target = call.func if call is not None else decorator
+            name = ast.unparse(target).rsplit(".", 1)[-1]
+            if name not in ("lru_cache", "cache") or name in self.function_return_types:
+                continue
+            options: dict[str, ast.expr] = {}
+            if call is not None:
+                options = dict(zip(("maxsize", "typed"), call.args))
+                options.update((kw.arg or "**", kw.value) for kw in call.keywords)
+            maxsize = options.pop("maxsize", None) if name == "lru_cache" else ast.Constant(value=None)
+            typed = options.pop("typed", ast.Constant(value=False))
+            if options or (name == "cache" and call is not None):
+                raise UnsupportedFeatureError(f"Unsupported arguments to {name}: {ast.unparse(decorator)}")
+            if maxsize is None:
+                maxsize = ast.Constant(value=128)
+            unbounded = isinstance(maxsize, ast.Constant) and maxsize.value is None
+            size = -1 if unbounded else constant_int_value(maxsize)
+            if size is None:
+                raise UnsupportedFeatureError(f"lru_cache maxsize must be an int or None: {ast.unparse(maxsize)}")
+            if not (isinstance(typed, ast.Constant) and isinstance(typed.value, bool)):
+                raise UnsupportedFeatureError(f"lru_cache typed must be True or False: {ast.unparse(typed)}")
+            if self._is_generator(node):
+                raise UnsupportedFeatureError(f"{name} on a generator function is not supported: {node.name}")
+            return f"mgen.NewLRUCache({size if unbounded else max(size, 0)}, {'true' if typed.value else 'false'})"
+        return None
+
+    def _convert_cached_function(self, node: ast.FunctionDef, signature: str, return_type: str, body: str) -> str:
+        """Wrap the converted body of an @lru_cache function in mgen.Memoize over its package-level cache.
+
+        Recursive calls go through the wrapper too, so a memoized fib is linear.
+
+        Example:
+            @lru_cache(maxsize=None)     var fibCache = mgen.NewLRUCache(-1, false)
+            def fib(n: int) -> int:  →   func fib(n int) int {
+                ...                          return mgen.Memoize(fibCache, fibCache.Key(n), func() int {
+                                                 ...
+                                             })
+                                         }
+        """
+        self.current_function = None
+        self.nested_vars = set()
+        self.append_map = {}
+        if not return_type:
+            raise UnsupportedFeatureError(f"lru_cache on a function without a return value: {node.name}")
+        cache = f"{node.name}Cache"
+        key = f"{cache}.Key({', '.join(arg.arg for arg in node.args.args)})"
+        return (
+            f"var {cache} = {self.cached_functions[node.name]}\n\n"
+            f"{signature} {{\n    return mgen.Memoize({cache}, {key}, func() {return_type} {{\n{body}\n    }})\n}}"
+        )
+
+    def _generator_yields(self, node: ast.FunctionDef) -> list[Union[ast.Yield, ast.YieldFrom]]:
+        """Find the yield expressions of a function's own body, not of the functions nested in it."""
+        found: list[Union[ast.Yield, ast.YieldFrom]] = []
+        pending: list[ast.AST] = list(node.body)
+        while pending:
+            current = pending.pop()
+            if isinstance(current, (ast.Yield, ast.YieldFrom)):
+                found.append(current)
+            if not isinstance(current, (ast.FunctionDef, ast.Lambda, ast.ClassDef)):
+                pending.extend(ast.iter_child_nodes(current))
+        return found
+
+    def _is_generator(self, node: ast.FunctionDef) -> bool:
+        """Check whether a function is a generator function (its body yields)."""
+        return bool(self._generator_yields(node))
+
+    def _generator_constructor(self) -> str:
+        """Return the runtime function that builds a generator's Iterator, as the generators preference selects."""
+        return "mgen.NewEagerGenerator" if self.generators == "eager" else "mgen.NewGenerator"
+
+    def _generator_item_type(self, node: ast.FunctionDef) -> str:
+        """Return the Go type of the values a generator function yields.
+
+        An Iterator[T], Iterable[T] or Generator[T, ...] annotation gives T;
+        otherwise the yielded values must share one type, else interface{}.
+        """
+        annotation = node.returns
+        if isinstance(annotation, ast.Subscript):
+            owner = annotation.value
+            name = owner.attr if isinstance(owner, ast.Attribute) else owner.id if isinstance(owner, ast.Name) else ""
+            if name in ("Iterator", "Iterable", "Generator"):
+                item = annotation.slice.elts[0] if isinstance(annotation.slice, ast.Tuple) else annotation.slice
+                return self._map_type_annotation(item)
+        outer_types = self.variable_types
+        parameter_types = {arg.arg: self._infer_parameter_type(arg, node) for arg in node.args.args}
+        self.variable_types = {**parameter_types, **outer_types}
+        try:
+            # Loop variables are often what a generator yields: for i in range(n): yield i * 2
+            for loop in ast.walk(node):
+                if isinstance(loop, ast.For) and isinstance(loop.target, ast.Name):
+                    if isinstance(loop.iter, ast.Call) and ast.unparse(loop.iter.func) == "range":
+                        loop_type = "[]int"
+                    else:
+                        loop_type = iterated_slice_type(self._infer_type_from_value(loop.iter))
+                    if loop_type.startswith("[]"):
+                        self.variable_types.setdefault(loop.target.id, loop_type[2:])
+            item_types = set()
+            for expr in self._generator_yields(node):
+                if isinstance(expr, ast.YieldFrom):
+                    source_type = iterated_slice_type(self._infer_type_from_value(expr.value))
+                    item_types.add(source_type[2:] if source_type.startswith("[]") else "interface{}")
+                elif expr.value is not None:
+                    item_types.add(self._infer_comprehension_element_type(expr.value, {}))
+        finally:
+            self.variable_types = outer_types
+        return item_types.pop() if len(item_types) == 1 else "interface{}"
+
+    def _function_type(self, node: ast.FunctionDef) -> str:
+        """Return the Go func type of a nested function: func(n int) string."""
+        params = ", ".join(f"{arg.arg} {self._infer_parameter_type(arg, node)}" for arg in node.args.args)
+        if self._is_generator(node):
+            return_type = f"mgen.Iterator[{self._generator_item_type(node)}]"
+        elif node.returns:
+            return_type = self._map_return_annotation(node.returns)
+        else:
+            return_type = self._infer_return_type(node)
+        return f"func({params}) {return_type}".rstrip()
+
+    def _convert_nested_function(self, node: ast.FunctionDef) -> str:
+        """Convert a function defined inside another function to a Go closure.
+
+        Go closures capture enclosing variables by reference. A name declared
+        nonlocal is assigned with = rather than redeclared with :=, so the
+        enclosing function sees the closure's mutations after each call; other
+        names the nested function assigns are its own locals, as in Python.
+        """
+        if node.decorator_list:
+            raise UnsupportedFeatureError(f"Decorators on nested functions are not supported: {node.name}")
+
+        def own_statements(stmts: list[ast.stmt]) -> list[ast.AST]:
+            """Walk the nested function's body without entering functions nested in it."""
+            nodes: list[ast.AST] = []
+            pending: list[ast.AST] = list(stmts)
+            while pending:
+                current = pending.pop()
+                nodes.append(current)
+                if not isinstance(current, (ast.FunctionDef, ast.Lambda)):
+                    pending.extend(ast.iter_child_nodes(current))
+            return nodes
+
+        nodes = own_statements(node.body)
+        visible = dict(self.variable_types)
+        if self.method_class is not None and self.current_function is not None:
+            # A closure in a method captures the method's parameters and locals
+            visible.update(self._method_scope_types())
+        nonlocal_names = {name for n in nodes if isinstance(n, ast.Nonlocal) for name in n.names}
+        for name in sorted(nonlocal_names):
+            if name not in visible:
+                raise TypeMappingError(f"SyntaxError: no binding for nonlocal '{name}' found")
+        local_names = {arg.arg for arg in node.args.args}
+        local_names |= {n.id for n in nodes if isinstance(n, ast.Name) and isinstance(n.ctx, ast.Store)}
+        local_names |= {n.name for n in nodes if isinstance(n, ast.FunctionDef)}
+        captured = {
+            name: var_type for name, var_type in visible.items() if name in nonlocal_names or name not in local_names
+        }
+        if node.name in captured and node.name not in nonlocal_names:
+            # The closure may call itself through its own variable
+            captured[node.name] = self._function_type(node)
+        # Python closures may use variables the enclosing function binds after defining them, which Go
+        # declares first: def inc(): nonlocal n ... followed by n = 0
+        referenced = {n.id for n in ast.walk(node) if isinstance(n, ast.Name)}
+        predeclared = [
+            name
+            for name in sorted(captured)
+            if name in referenced
+            and name != node.name
+            and name not in self.declared_vars
+            and name not in self.global_vars
+        ]
+
+        saved_state = (
+            self.current_function,
+            self.declared_vars,
+            self.variable_types,
+            self.nested_vars,
+            self.append_map,
+            self.try_contexts,
+            self.generator_item_type,
+            self.reference_lists,
+        )
+        shadowed_return_type = self.function_return_types.get(node.name)
+        self.try_contexts = []
+        try:
+            func_code = self._convert_function(node, captured)
+        finally:
+            (
+                self.current_function,
+                self.declared_vars,
+                self.variable_types,
+                self.nested_vars,
+                self.append_map,
+                self.try_contexts,
+                self.generator_item_type,
+                self.reference_lists,
+            ) = saved_state
+            if shadowed_return_type is None:
+                self.function_return_types.pop(node.name, None)
+            else:
+                self.function_return_types[node.name] = shadowed_return_type
+
+        func_type = self._function_type(node)
+        closure = "func" + func_code[len(f"func {node.name}") : -1] + "    }"
+        self.variable_types[node.name] = func_type
+        declarations = "".join(f"    var {name} {captured[name] or 'interface{}'}\n" for name in predeclared)
+        self.declared_vars.update(predeclared)
+        recursive = any(isinstance(n, ast.Name) and n.id == node.name for n in ast.walk(node))
+        if node.name in self.declared_vars:
+            return f"{declarations}    {node.name} = {closure}"
+        self.declared_vars.add(node.name)
+        if recursive:
+            # A closure cannot refer to a variable in its own := declaration
+            return f"{declarations}    var {node.name} {func_type}\n    {node.name} = {closure}"
+        return f"{declarations}    {node.name} := {closure}"
+
     def _analyze_nested_subscripts(self, stmts: list[ast.stmt]) -> set[str]:
         """Detect variables used with nested subscripts like a[i][j]."""
         nested_vars: set[str] = set()
@@ -762,6 +3735,13 @@ class MGenPythonToGoConverter:
                 for target in stmt.targets:
                     if isinstance(target, ast.Name):
                         declared.add(target.id)
+                    elif isinstance(target, (ast.Tuple, ast.List)):
+                        # Names bound by unpacking: a, (b, *c) = value
+                        declared.update(
+                            node.id
+                            for node in ast.walk(target)
+                            if isinstance(node, ast.Name) and isinstance(node.ctx, ast.Store) and node.id != "_"
+                        )
             elif isinstance(stmt, (ast.For, ast.While)):
                 for s in stmt.body:
                     collect_declared(s)
@@ -773,6 +3753,20 @@ class MGenPythonToGoConverter:
                     collect_declared(s)
                 for s in stmt.orelse:
                     collect_declared(s)
+            elif isinstance(stmt, ast.Try):
+                handler_bodies = [s for handler in stmt.handlers for s in handler.body]
+                for s in [*stmt.body, *handler_bodies, *stmt.orelse, *stmt.finalbody]:
+                    collect_declared(s)
+            elif isinstance(stmt, ast.With):
+                for item in stmt.items:
+                    if isinstance(item.optional_vars, ast.Name):
+                        declared.add(item.optional_vars.id)
+                for s in stmt.body:
+                    collect_declared(s)
+            elif isinstance(stmt, ast.Match):
+                for case in stmt.cases:
+                    for s in case.body:
+                        collect_declared(s)
 
         def collect_used(node: ast.AST) -> None:
             """Collect variable uses (but not in assignment targets)."""
@@ -786,11 +3780,16 @@ class MGenPythonToGoConverter:
         def traverse_stmt(stmt: ast.stmt) -> None:
             """Traverse statements to find uses."""
             if isinstance(stmt, ast.Assign):
-                # Check the value being assigned (RHS)
+                # Check the value being assigned (RHS), and indexes read by targets such as xs[i]
                 collect_used(stmt.value)
+                for target in stmt.targets:
+                    collect_used(target)
             elif isinstance(stmt, ast.AnnAssign):
                 if stmt.value:
                     collect_used(stmt.value)
+            elif isinstance(stmt, ast.AugAssign):
+                collect_used(stmt.target)
+                collect_used(stmt.value)
             elif isinstance(stmt, ast.Expr):
                 collect_used(stmt.value)
             elif isinstance(stmt, ast.Return):
@@ -802,6 +3801,33 @@ class MGenPythonToGoConverter:
                     traverse_stmt(s)
                 for s in stmt.orelse:
                     traverse_stmt(s)
+            elif isinstance(stmt, ast.Raise):
+                if stmt.exc:
+                    collect_used(stmt.exc)
+            elif isinstance(stmt, ast.Try):
+                handler_bodies = [s for handler in stmt.handlers for s in handler.body]
+                for s in [*stmt.body, *handler_bodies, *stmt.orelse, *stmt.finalbody]:
+                    traverse_stmt(s)
+            elif isinstance(stmt, ast.With):
+                for item in stmt.items:
+                    collect_used(item.context_expr)
+                for s in stmt.body:
+                    traverse_stmt(s)
+            elif isinstance(stmt, ast.Match):
+                collect_used(stmt.subject)
+                for case in stmt.cases:
+                    for node in ast.walk(case.pattern):
+                        if isinstance(node, ast.MatchValue):
+                            collect_used(node.value)
+                    if case.guard is not None:
+                        collect_used(case.guard)
+                    for s in case.body:
+                        traverse_stmt(s)
+            elif isinstance(stmt, ast.FunctionDef):
+                # A closure's reads of enclosing variables are uses in this function
+                for child in ast.walk(stmt):
+                    if isinstance(child, ast.Name) and isinstance(child.ctx, ast.Load):
+                        used.add(child.id)
             elif isinstance(stmt, (ast.For, ast.While)):
                 if hasattr(stmt, "iter"):
                     collect_used(stmt.iter)
@@ -821,22 +3847,34 @@ class MGenPythonToGoConverter:
         # Return variables that are declared but never used
         return declared - used
 
-    def _analyze_map_key_types(self, stmts: list[ast.stmt]) -> set[str]:
-        """Detect maps that are accessed with string keys."""
-        string_keyed_maps: set[str] = set()
+    def _analyze_map_key_types(self, stmts: list[ast.stmt]) -> dict[str, str]:
+        """Detect maps accessed with string or tuple keys and sets given such members.
+
+        Returns the key type of each: "string", or the Tuple2/Tuple3 of a
+        tuple display such as (x, y).
+        """
+        key_types: dict[str, str] = {}
+
+        def key_type(key: ast.expr) -> Optional[str]:
+            # A string literal or string variable, or a tuple of scalars
+            if isinstance(key, ast.Constant):
+                return "string" if isinstance(key.value, str) else None
+            if isinstance(key, ast.Name) and self.variable_types.get(key.id) == "string":
+                return "string"
+            if isinstance(key, ast.Tuple):
+                return tuple_key_type([key], self._infer_type_from_value)
+            return None
+
+        def record(name: str, key: ast.expr) -> None:
+            found = key_type(key)
+            if found is not None:
+                key_types.setdefault(name, found)
 
         def check_expr(expr: ast.expr) -> None:
             if isinstance(expr, ast.Subscript):
-                # Check if the subscripted value is a map and the key is a string
-                if isinstance(expr.value, ast.Name):
-                    map_name = expr.value.id
-                    # Check if key is a string literal or string variable
-                    if isinstance(expr.slice, ast.Constant) and isinstance(expr.slice.value, str):
-                        string_keyed_maps.add(map_name)
-                    elif isinstance(expr.slice, ast.Name):
-                        # Check if the key variable is a string
-                        if expr.slice.id in self.variable_types and self.variable_types[expr.slice.id] == "string":
-                            string_keyed_maps.add(map_name)
+                # Check if the subscripted value is a map and the key is a string or tuple
+                if isinstance(expr.value, ast.Name) and not isinstance(expr.slice, ast.Slice):
+                    record(expr.value.id, expr.slice)
                 # Recursively check
                 check_expr(expr.value)
                 if not isinstance(expr.slice, ast.Slice):
@@ -846,6 +3884,9 @@ class MGenPythonToGoConverter:
                 check_expr(expr.right)
             elif isinstance(expr, ast.Call):
                 if isinstance(expr.func, ast.Attribute):
+                    # s.add("x") on a set
+                    if expr.func.attr == "add" and isinstance(expr.func.value, ast.Name) and len(expr.args) == 1:
+                        record(expr.func.value.id, expr.args[0])
                     check_expr(expr.func.value)
                 for arg in expr.args:
                     check_expr(arg)
@@ -862,6 +3903,9 @@ class MGenPythonToGoConverter:
             elif isinstance(stmt, ast.AnnAssign):
                 if stmt.value:
                     check_expr(stmt.value)
+            elif isinstance(stmt, ast.AugAssign):
+                check_expr(stmt.target)
+                check_expr(stmt.value)
             elif isinstance(stmt, ast.Expr):
                 check_expr(stmt.value)
             elif isinstance(stmt, (ast.For, ast.While)):
@@ -882,7 +3926,7 @@ class MGenPythonToGoConverter:
         for stmt in stmts:
             check_stmt(stmt)
 
-        return string_keyed_maps
+        return key_types
 
     def _analyze_map_value_types(self, stmts: list[ast.stmt]) -> dict[str, str]:
         """Detect map value types from subscript assignments."""
@@ -917,6 +3961,7 @@ class MGenPythonToGoConverter:
 
     def _pre_infer_variable_types(self, stmts: list[ast.stmt]) -> None:
         """Pre-pass to infer all variable types before code generation."""
+        body = stmts
 
         # First pass: collect base types from annotations and initializations
         def collect_types(stmts: list[ast.stmt]) -> None:
@@ -929,15 +3974,44 @@ class MGenPythonToGoConverter:
                 elif isinstance(stmt, ast.Assign):
                     for target in stmt.targets:
                         if isinstance(target, ast.Name) and target.id not in self.variable_types:
-                            var_type = self._infer_type_from_value(stmt.value)
+                            if isinstance(stmt.value, ast.Lambda):
+                                var_type = self._lambda_type_from_calls(target.id, stmt.value, body)
+                            else:
+                                var_type = self._infer_type_from_value(stmt.value)
                             self.variable_types[target.id] = var_type
+                        elif isinstance(target, (ast.Tuple, ast.List)):
+                            for name, var_type in self._unpacked_types(target, stmt.value).items():
+                                self.variable_types.setdefault(name, var_type)
                 elif isinstance(stmt, (ast.For, ast.While)):
+                    if isinstance(stmt, ast.For) and isinstance(stmt.target, ast.Name):
+                        # Typed loop variables let the later passes see seen.add(word) add a string
+                        generator = ast.comprehension(target=stmt.target, iter=stmt.iter, ifs=[], is_async=0)
+                        loop_type = self._infer_loop_variable_type(generator).get(stmt.target.id, "interface{}")
+                        if loop_type != "interface{}":
+                            self.variable_types.setdefault(stmt.target.id, loop_type)
                     collect_types(stmt.body)
                     if hasattr(stmt, "orelse"):
                         collect_types(stmt.orelse)
                 elif isinstance(stmt, ast.If):
                     collect_types(stmt.body)
                     collect_types(stmt.orelse)
+                elif isinstance(stmt, ast.With):
+                    for item in stmt.items:
+                        if isinstance(item.optional_vars, ast.Name):
+                            target_type = self._with_target_type(item.context_expr)
+                            self.variable_types.setdefault(item.optional_vars.id, target_type)
+                    collect_types(stmt.body)
+                elif isinstance(stmt, ast.Try):
+                    collect_types(stmt.body)
+                    for handler in stmt.handlers:
+                        collect_types(handler.body)
+                    collect_types(stmt.orelse)
+                    collect_types(stmt.finalbody)
+                elif isinstance(stmt, ast.Match):
+                    for case in stmt.cases:
+                        collect_types(case.body)
+                elif isinstance(stmt, ast.FunctionDef):
+                    self.variable_types[stmt.name] = self._function_type(stmt)
 
         collect_types(stmts)
 
@@ -953,27 +4027,83 @@ class MGenPythonToGoConverter:
             if var_name in self.variable_types and self.variable_types[var_name] == "[]int":
                 self.variable_types[var_name] = "[][]int"
 
-        # Fourth pass: detect string-keyed maps
-        string_keyed_maps = self._analyze_map_key_types(stmts)
-        for var_name in string_keyed_maps:
+        # Fourth pass: detect string- and tuple-keyed dicts and sets
+        map_key_types = self._analyze_map_key_types(stmts)
+        for var_name, key_type in map_key_types.items():
             if var_name in self.variable_types:
                 current_type = self.variable_types[var_name]
-                # Upgrade map[int]int to map[string]int, map[int]bool to map[string]bool
-                if current_type == "map[int]int":
-                    self.variable_types[var_name] = "map[string]int"
-                elif current_type == "map[int]bool":
-                    self.variable_types[var_name] = "map[string]bool"
-
-        # Fifth pass: detect map value types from subscript assignments
+                dict_types = dict_type_args(current_type)
+                # Upgrade the int-keyed defaults of {}, set() and defaultdict(list) to the key type
+                if dict_types is not None and dict_types[0] == "int":
+                    self.variable_types[var_name] = go_dict_type(key_type, dict_types[1])
+                elif current_type == go_set_type("int"):
+                    self.variable_types[var_name] = go_set_type(key_type)
+
+        # Fifth pass: detect dict value types from subscript assignments
         map_value_types = self._analyze_map_value_types(stmts)
         for var_name, value_type in map_value_types.items():
             if var_name in self.variable_types:
-                current_type = self.variable_types[var_name]
+                dict_types = dict_type_args(self.variable_types[var_name])
                 # Only upgrade if the new value type is more specific (not interface{})
-                if value_type != "interface{}" and current_type.startswith("map[") and current_type.endswith("]int"):
-                    # Extract key type and update value type
-                    key_part = current_type[4:-4]  # Remove "map[" and "]int"
-                    self.variable_types[var_name] = f"map[{key_part}]{value_type}"
+                if value_type != "interface{}" and dict_types is not None and dict_types[1] == "int":
+                    self.variable_types[var_name] = go_dict_type(dict_types[0], value_type)
+
+    def _lower_list_queues(self, node: ast.FunctionDef) -> None:
+        """Lower local lists used only as FIFO queues to mgen.Deque.
+
+        xs.pop(0) shifts every remaining element, so a BFS loop over a list
+        queue is quadratic. A local list whose every use is xs.append(x),
+        xs.pop(0), len(xs) or a truth test (while xs:, if not xs:) and that
+        is only ever assigned list displays is retyped as a Deque, making each
+        operation O(1):
+
+            queue = [start]          →  var queue *mgen.Deque[int] = mgen.NewDeque[int](start)
+            node = queue.pop(0)      →  node := queue.PopLeft()
+        """
+        parents = {child: parent for parent in ast.walk(node) for child in ast.iter_child_nodes(parent)}
+        params = {arg.arg for arg in ast.walk(node.args) if isinstance(arg, ast.arg)}
+        candidates: dict[str, list[ast.List]] = {}
+        rejected: set[str] = set(params)
+        popped: set[str] = set()
+
+        for name_node in ast.walk(node):
+            if not isinstance(name_node, ast.Name) or name_node.id in rejected:
+                continue
+            name = name_node.id
+            parent = parents.get(name_node)
+            if isinstance(name_node.ctx, ast.Store):
+                # Only plain `xs = [...]` / `xs: list[T] = [...]` bindings
+                if isinstance(parent, (ast.Assign, ast.AnnAssign)) and isinstance(parent.value, ast.List):
+                    candidates.setdefault(name, []).append(parent.value)
+                    continue
+                rejected.add(name)
+                continue
+            call = parents.get(parent) if isinstance(parent, ast.Attribute) else None
+            if isinstance(call, ast.Call) and call.func is parent:
+                if parent.attr == "append" and len(call.args) == 1:
+                    continue
+                if parent.attr == "pop" and len(call.args) == 1:
+                    index = call.args[0]
+                    if isinstance(index, ast.Constant) and index.value == 0:
+                        popped.add(name)
+                        continue
+            elif isinstance(parent, ast.Call) and isinstance(parent.func, ast.Name) and parent.func.id == "len":
+                continue
+            elif isinstance(parent, (ast.While, ast.If)) and parent.test is name_node:
+                continue
+            elif isinstance(parent, ast.UnaryOp) and isinstance(parent.op, ast.Not):
+                grandparent = parents.get(parent)
+                if isinstance(grandparent, (ast.While, ast.If)) and grandparent.test is parent:
+                    continue
+            rejected.add(name)
+
+        for name in popped - rejected:
+            var_type = self.variable_types.get(name, "")
+            if name not in candidates or not var_type.startswith("[]"):
+                continue
+            self.variable_types[name] = f"*mgen.Deque[{var_type[2:]}]"
+            for initializer in candidates[name]:
+                self.deque_values[id(initializer)] = var_type[2:]
 
     def _convert_statements(self, statements: list[ast.stmt]) -> str:
         """Convert a list of statements."""
@@ -984,6 +4114,9 @@ class MGenPythonToGoConverter:
 
     def _convert_statement(self, stmt: ast.stmt) -> str:
         """Convert a Python statement to Go."""
+        if self.method_class is not None and isinstance(stmt, METHOD_STATEMENTS):
+            # e.g. the body of a loop in a method, where self.attr and super() need the class
+            return self._convert_method_statement(stmt, self.method_class)
         if isinstance(stmt, ast.Return):
             return self._convert_return(stmt)
         elif isinstance(stmt, ast.Assign):
@@ -994,74 +4127,841 @@ class MGenPythonToGoConverter:
             return self._convert_aug_assignment(stmt)
         elif isinstance(stmt, ast.If):
             return self._convert_if(stmt)
-        elif isinstance(stmt, ast.While):
-            return self._convert_while(stmt)
-        elif isinstance(stmt, ast.For):
-            return self._convert_for(stmt)
+        elif isinstance(stmt, (ast.While, ast.For)):
+            return self._convert_loop(stmt)
+        elif isinstance(stmt, ast.Break):
+            return self._convert_break()
+        elif isinstance(stmt, ast.Continue):
+            return "    continue"
         elif isinstance(stmt, ast.Expr):
             return self._convert_expression_statement(stmt)
         elif isinstance(stmt, ast.Pass):
             return "    // pass"
         elif isinstance(stmt, ast.Assert):
             return self._convert_assert(stmt)
+        elif isinstance(stmt, ast.Delete):
+            return self._convert_delete(stmt)
+        elif isinstance(stmt, ast.Raise):
+            return self._convert_raise(stmt, self._convert_expression)
+        elif isinstance(stmt, ast.Try):
+            return self._convert_try(stmt)
+        elif isinstance(stmt, ast.With):
+            return self._convert_with(stmt)
+        elif isinstance(stmt, ast.Match):
+            return self._convert_match(stmt)
+        elif isinstance(stmt, ast.FunctionDef):
+            return self._convert_nested_function(stmt)
+        elif isinstance(stmt, ast.Nonlocal):
+            # Closures capture by reference; _convert_nested_function keeps these names bound
+            return f"    // nonlocal {', '.join(stmt.names)}"
+        elif isinstance(stmt, ast.Global):
+            for name in stmt.names:
+                if name not in self.global_types:
+                    raise UnsupportedFeatureError(f"global {name} is not assigned at module level")
+            # The function assigns the package-level vars (see _global_scope_types)
+            return f"    // global {', '.join(stmt.names)}"
         else:
             raise UnsupportedFeatureError(f"Unsupported statement type: {type(stmt).__name__}")
 
     def _convert_assert(self, stmt: ast.Assert) -> str:
-        """Convert Python assert statement to Go panic on failure.
-
-        Args:
-            stmt: Python assert statement node
+        """Convert Python assert statement to a check that raises AssertionError.
 
-        Returns:
-            Go if statement that panics on assertion failure
+        The check is guarded by mgen.Debug (Python's __debug__), so with
+        mgen.SetOptimized(true) or the mgen_optimize build tag neither the test
+        nor the message runs, as under python -O. The message is converted
+        inside the failure branch, so it is only evaluated when the assert fails.
 
         Example:
-            assert x > 0  →  if !(x > 0) { panic("assertion failed") }
-            assert result == 1, "Test failed"  →  if !(result == 1) { panic("Test failed") }
+            assert x > 0  →  if mgen.Debug && !(x > 0) { mgen.Raise("AssertionError", "") }
+            assert n == 1, f"got {n}"  →  if mgen.Debug && !(n == 1) { mgen.Raise("AssertionError", "%s", ...) }
         """
-        # Convert the test expression
         test_expr = self._convert_expression(stmt.test)
+        if stmt.msg is None:
+            failure = 'mgen.Raise("AssertionError", "")'
+        else:
+            message = self._convert_expression(stmt.msg)
+            if self._infer_type_from_value(stmt.msg) != "string":
+                message = f"mgen.ToStr({message})"
+            failure = f'mgen.Raise("AssertionError", "%s", {message})'
+        return f"    if mgen.Debug && !({test_expr}) {{ {failure} }}"
 
-        # Handle optional message
-        if stmt.msg:
-            # Convert message to string
-            if isinstance(stmt.msg, ast.Constant) and isinstance(stmt.msg.value, str):
-                msg = stmt.msg.value
-                return f'    if !({test_expr}) {{ panic("{msg}") }}'
-            else:
-                # Complex message expression - just add default panic
-                return f'    if !({test_expr}) {{ panic("assertion failed") }}'
+    def _convert_raise(self, stmt: ast.Raise, convert: Callable[[ast.expr], str]) -> str:
+        """Convert raise to a panic with a *mgen.PyError.
+
+        Generated exception classes are raised as a pointer (see
+        mgen.NewException), and a bare raise in an except clause re-panics
+        with the recovered exception. A panic ends its block, so a function
+        whose last statement is a raise needs no return after it.
+
+        Example:
+            raise ValueError("bad")  →  panic(mgen.NewPyError("ValueError", "%s", "bad"))
+            raise KeyError           →  panic(mgen.NewPyError("KeyError", ""))
+            raise ParseError("bad")  →  panic(mgen.NewException(NewParseError("bad")))
+            raise                    →  panic(_exc)
+        """
+        exc = stmt.exc
+        if exc is None:
+            if not any(context["in_handler"] for context in self.try_contexts):
+                raise TypeMappingError("RuntimeError: No active exception to reraise")
+            return "    panic(_exc)"
+        if isinstance(exc, ast.Name) and self._is_exception_class(exc.id):
+            exc = ast.Call(func=exc, args=[], keywords=[])
+        if isinstance(exc, ast.Call) and isinstance(exc.func, ast.Name) and self._is_exception_class(exc.func.id):
+            return f"    panic(mgen.NewException({self._convert_constructor_call(exc.func.id, exc, convert)}))"
+        if isinstance(exc, ast.Name) and exc.id in self.variable_types:
+            # Re-raising a bound exception: except ParseError as e: ... raise e
+            if self._is_exception_class(self.variable_types[exc.id]):
+                return f"    panic(&{exc.id})"
+            return f"    panic({exc.id})"
+        if isinstance(exc, ast.Name):
+            return f'    panic(mgen.NewPyError("{exc.id}", ""))'
+        if isinstance(exc, ast.Call) and isinstance(exc.func, ast.Name) and not exc.keywords:
+            if not exc.args:
+                return f'    panic(mgen.NewPyError("{exc.func.id}", ""))'
+            if len(exc.args) == 1:
+                message = convert(exc.args[0])
+                if self._infer_type_from_value(exc.args[0]) != "string":
+                    message = f"mgen.ToStr({message})"
+                return f'    panic(mgen.NewPyError("{exc.func.id}", "%s", {message}))'
+        raise UnsupportedFeatureError(f"Unsupported raise statement: {ast.unparse(stmt)}")
+
+    def _convert_try(self, stmt: ast.Try) -> str:
+        """Convert try/except to a closure whose deferred recover runs the matching handler.
+
+        Handlers match with mgen.ExceptionMatches, so a class also catches its
+        subclasses; anything unmatched is re-panicked. Variables first assigned
+        inside the statement are declared before the closure so they stay in
+        scope after it. When the statement contains a return, the closure
+        reports it through named results and the enclosing function returns.
+
+        An else clause runs at the end of the closure once the body has
+        finished, after setting a tryOk flag that makes the handlers leave its
+        exceptions alone. A finally clause is deferred first, so it runs last:
+        after the handlers, on return, and while an exception propagates.
+
+        Example:
+            try:                         func() {
+                n = parse(s)                 defer func() {
+            except ValueError as e:              if _exc := recover(); _exc != nil {
+                print(e)                             if pyErr := mgen.ExceptionMatches(_exc, "ValueError"); pyErr != nil {
+                                                         e := pyErr
+                                                         mgen.Print(e)
+                                                     } else {
+                                                         panic(_exc)
+                                                     }
+                                                 }
+                                             }()
+                                             n = parse(s)
+                                         }()
+        """
+        if any(isinstance(node, ast.Return) for node in self._local_nodes(stmt.finalbody)):
+            raise UnsupportedFeatureError("return inside a finally clause is not supported")
+
+        def convert_closure(context: dict[str, Any]) -> list[str]:
+            ok = f"tryOk{self.loop_counter}"
+            body = self._convert_statements(stmt.body)
+            orelse = self._convert_statements(stmt.orelse) if stmt.orelse else None
+            context["in_handler"] = True
+            handlers = self._convert_except_handlers(stmt.handlers) if stmt.handlers else None
+            finalbody = self._convert_statements(stmt.finalbody) if stmt.finalbody else None
+
+            closure = []
+            if orelse is not None:
+                closure.append(f"    {ok} := false")
+            if finalbody is not None:
+                closure.extend(["    defer func() {", finalbody, "    }()"])
+            if handlers is not None:
+                closure.append("    defer func() {")
+                if orelse is not None:
+                    # Exceptions raised by the else clause are not the handlers' to catch
+                    closure.extend([f"    if {ok} {{", "    return", "    }"])
+                closure.extend(["    if _exc := recover(); _exc != nil {", handlers, "    }", "    }()"])
+            closure.append(body)
+            if orelse is not None:
+                closure.extend([f"    {ok} = true", orelse])
+            return closure
+
+        return self._convert_closure_statement(stmt, "try", (stmt.orelse or stmt.body)[-1], convert_closure)
+
+    @staticmethod
+    def _local_nodes(nodes: list[ast.stmt], loops: bool = True) -> list[ast.AST]:
+        """Walk statements without entering nested functions (or, unless loops, nested loops)."""
+        found: list[ast.AST] = []
+        pending: list[ast.AST] = list(nodes)
+        while pending:
+            node = pending.pop()
+            found.append(node)
+            if isinstance(node, (ast.FunctionDef, ast.Lambda)) or (
+                not loops and isinstance(node, (ast.For, ast.While))
+            ):
+                continue
+            pending.extend(ast.iter_child_nodes(node))
+        return found
+
+    def _convert_closure_statement(
+        self, stmt: ast.stmt, kind: str, last: ast.stmt, convert_closure: Callable[[dict[str, Any]], list[str]]
+    ) -> str:
+        """Convert a try or with statement, whose Go form runs in a closure so that its defers run at its end.
+
+        Variables first assigned inside the statement are declared before the
+        closure so they stay in scope after it. When the statement contains a
+        return, the closure reports it through named results and the enclosing
+        function returns. convert_closure builds the lines of the closure while
+        the statement's context is on try_contexts; last is the statement the
+        closure's body ends with when it completes.
+        """
+        if any(isinstance(node, (ast.Break, ast.Continue)) for node in self._local_nodes([stmt], loops=False)):
+            raise UnsupportedFeatureError(f"break and continue inside {kind} statements are not supported")
+
+        # Names first assigned inside the statement outlive the closure
+        lines = []
+        for node in self._local_nodes([stmt]):
+            bindings: list[tuple[ast.expr, Callable[[], str]]] = []
+            if isinstance(node, ast.Assign):
+                bindings = [(target, lambda node=node: self._infer_type_from_value(node.value)) for target in node.targets]
+            elif isinstance(node, ast.AnnAssign):
+                bindings = [(node.target, lambda node=node: self._map_type_annotation(node.annotation))]
+            elif isinstance(node, ast.With):
+                bindings = [
+                    (item.optional_vars, lambda item=item: self._with_target_type(item.context_expr))
+                    for item in node.items
+                    if item.optional_vars is not None
+                ]
+            for target, binding_type in bindings:
+                if isinstance(target, ast.Name) and target.id not in self.declared_vars:
+                    # Methods skip the pre-pass that types function locals, so fall back to the bound value
+                    var_type = self.variable_types.get(target.id) or binding_type()
+                    self.variable_types[target.id] = var_type
+                    lines.append(f"    var {target.id} {var_type}")
+                    self.declared_vars.add(target.id)
+
+        returns = self.current_function != "main" and any(
+            isinstance(node, ast.Return) for node in self._local_nodes([stmt])
+        )
+        result_type = self._current_result_type()
+        if self.generator_item_type is not None:
+            result_type = ""
+        self.loop_counter += 1
+        context: dict[str, Any] = {
+            "done": f"tryDone{self.loop_counter}",
+            "result": f"tryResult{self.loop_counter}" if result_type else None,
+            "in_handler": False,
+        }
+        self.try_contexts.append(context)
+        try:
+            closure = convert_closure(context)
+        finally:
+            self.try_contexts.pop()
+
+        if not returns:
+            return "\n".join([*lines, "    func() {", *closure, "    }()"])
+
+        results = [f"{context['done']} bool"]
+        if context["result"]:
+            results.append(f"{context['result']} {result_type}")
+        if not isinstance(last, ast.Return):
+            closure.append("    return")
+        signature = f"func() ({', '.join(results)})"
+        if context["result"]:
+            opening = f"    if {context['done']}, {context['result']} := {signature} {{"
+            closing = f"    }}(); {context['done']} {{"
+        else:
+            opening = f"    if {signature} {{"
+            closing = "    }() {"
+        return "\n".join([*lines, opening, *closure, closing, self._return_statement(context["result"]), "    }"])
+
+    def _current_result_type(self) -> str:
+        """Return the Go result type of the function or method being converted, or "" for none."""
+        if self.method_owner is not None:
+            method = self.struct_info[self.method_owner]["methods"].get(self.current_function or "")
+            return self._method_types(method["node"])[1] if method is not None else ""
+        return self.function_return_types.get(self.current_function or "", "")
+
+    def _convert_with(self, stmt: ast.With) -> str:
+        """Convert a with statement to a closure that defers the exit of each context manager.
+
+        A file from open() is closed by a deferred Close. For a class defining
+        __enter__ and __exit__, the deferred function recovers any exception
+        the body raised and passes it to __exit__ (see mgen.ExitArgs); unless
+        __exit__ returns true, which suppresses it, the exception is re-panicked.
+        Defers run last in first out, so several managers exit in reverse
+        order, as in Python.
+
+        Example:
+            with Timer("load") as t:     func() {
+                t.ticks += 1                 t = NewTimer("load")
+                                             t.PyEnter()
+                                             defer func() {
+                                                 _exc := recover()
+                                                 if !t.PyExit(mgen.ExitArgs(_exc)) && _exc != nil {
+                                                     panic(_exc)
+                                                 }
+                                             }()
+                                             t.Ticks += 1
+                                         }()
+        """
+
+        def convert_closure(context: dict[str, Any]) -> list[str]:
+            closure = []
+            for item in stmt.items:
+                self.loop_counter += 1
+                closure.extend(self._convert_with_item(item, f"withManager{self.loop_counter}"))
+            closure.append(self._convert_statements(stmt.body))
+            return closure
+
+        return self._convert_closure_statement(stmt, "with", stmt.body[-1], convert_closure)
+
+    def _convert_with_item(self, item: ast.withitem, manager: str) -> list[str]:
+        """Enter the context manager of one with item and defer its exit.
+
+        The manager is kept in a variable, named manager unless the item's
+        value is a variable already or __enter__ returns self, in which case
+        the as target is the manager itself.
+        """
+        value, target = item.context_expr, item.optional_vars
+        if target is not None and not isinstance(target, ast.Name):
+            raise UnsupportedFeatureError(f"Unsupported with target: {ast.unparse(target)}")
+        value_expr = self._convert_expression(value)
+        kind = self._context_manager(value)
+        if kind is None:
+            raise UnsupportedFeatureError(f"Unsupported context manager: {ast.unparse(value)}")
+        enter = None if kind == "file" else self.struct_info[kind]["methods"]["__enter__"]["node"]
+
+        lines = []
+        if target is not None and (enter is None or self._returns_self(enter)):
+            manager = target.id
+            value_expr = self._convert_class_value(value, value_expr, self.variable_types.get(manager, ""))
+            lines.append(f"    {manager} = {value_expr}")
+            target = None
+        elif isinstance(value, ast.Name):
+            manager = value.id
         else:
-            return f'    if !({test_expr}) {{ panic("assertion failed") }}'
+            lines.append(f"    {manager} := {value_expr}")
+        if enter is None:
+            lines.append(f"    defer {manager}.Close()")
+            return lines
+
+        enter_call = f"{manager}.{self._to_go_method_name('__enter__')}()"
+        if target is None:
+            lines.append(f"    {enter_call}")
+        elif not self._method_types(enter)[1]:
+            raise UnsupportedFeatureError(f"{kind}.__enter__ returns no value to bind to {target.id}")
+        else:
+            lines.append(f"    {target.id} = {enter_call}")
+
+        exit_method = self.struct_info[kind]["methods"]["__exit__"]["node"]
+        param_types, result_type = self._method_types(exit_method)
+        if len(param_types) != 3:
+            raise TypeMappingError(
+                f"TypeError: __exit__() takes {len(param_types) + 1} positional "
+                f"argument{'' if not param_types else 's'} but 4 were given"
+            )
+        if any(param_type != "interface{}" for param_type in param_types):
+            raise UnsupportedFeatureError(
+                f"{kind}.__exit__ parameters must be unannotated or Optional: {self._method_signature(exit_method)}"
+            )
+        exit_call = f"{manager}.{self._to_go_method_name('__exit__')}(mgen.ExitArgs(_exc))"
+        lines.extend(["    defer func() {", "    _exc := recover()"])
+        if not result_type:
+            lines.extend([f"    {exit_call}", "    if _exc != nil {"])
+        elif result_type == "bool":
+            lines.append(f"    if !{exit_call} && _exc != nil {{")
+        else:
+            lines.append(f"    if !mgen.ToBool({exit_call}) && _exc != nil {{")
+        lines.extend(["    panic(_exc)", "    }", "    }()"])
+        return lines
+
+    def _context_manager(self, value: ast.expr) -> Optional[str]:
+        """Return "file" for a file object, the class of an instance defining __enter__ and __exit__, or None."""
+        value_type = self._infer_type_from_value(value)
+        if value_type == "*mgen.PyFile":
+            return "file"
+        owner = self._instance_class(value_type)
+        if owner is None:
+            return None
+        methods = self.struct_info[owner]["methods"]
+        return owner if "__enter__" in methods and "__exit__" in methods else None
+
+    def _with_target_type(self, value: ast.expr) -> str:
+        """Return the type of the as target of a with item: the result of the manager's __enter__."""
+        kind = self._context_manager(value)
+        if kind is None or kind == "file":
+            return self._infer_type_from_value(value)
+        enter = self.struct_info[kind]["methods"]["__enter__"]["node"]
+        if self._returns_self(enter):
+            return self._infer_type_from_value(value)
+        return self._method_types(enter)[1] or "interface{}"
+
+    def _returns_self(self, method: ast.FunctionDef) -> bool:
+        """Check whether every return of a method returns self (as __enter__ usually does)."""
+        returns = [node for node in self._local_nodes(method.body) if isinstance(node, ast.Return)]
+        return bool(returns) and all(
+            isinstance(node.value, ast.Name) and node.value.id == "self" for node in returns
+        )
+
+    def _convert_except_handlers(self, handlers: list[ast.ExceptHandler]) -> str:
+        """Convert except clauses to an if/else-if chain over the recovered value r.
+
+        A clause naming one generated exception class binds a copy of the
+        raised instance (through its As<Class> accessor); other clauses bind
+        the *mgen.PyError record.
+        """
+        branches = []
+        for handler in handlers:
+            if handler.type is None:
+                names = ["BaseException"]
+            elif isinstance(handler.type, ast.Name):
+                names = [handler.type.id]
+            elif isinstance(handler.type, ast.Tuple) and all(isinstance(elt, ast.Name) for elt in handler.type.elts):
+                names = [elt.id for elt in handler.type.elts if isinstance(elt, ast.Name)]
+            else:
+                raise UnsupportedFeatureError(f"Unsupported except clause: {ast.unparse(handler.type)}")
+            match = f"mgen.ExceptionMatches(_exc, {', '.join(json.dumps(name) for name in names)})"
+
+            bound = handler.name is not None and any(
+                isinstance(node, ast.Name) and node.id == handler.name for s in handler.body for node in ast.walk(s)
+            )
+            if not bound:
+                condition, binding = f"{match} != nil", None
+            elif len(names) == 1 and self._is_exception_class(names[0]):
+                exc_class = names[0]
+                condition = f"{match} != nil"
+                binding = (
+                    f"    {handler.name} := *_exc.(interface{{ As{exc_class}() *{exc_class} }}).As{exc_class}()",
+                    exc_class,
+                )
+            else:
+                condition = f"pyErr := {match}; pyErr != nil"
+                binding = (f"    {handler.name} := pyErr", "*mgen.PyError")
+
+            saved_type = self.variable_types.get(handler.name or "")
+            body_lines = []
+            if binding is not None and handler.name is not None:
+                body_lines.append(binding[0])
+                self.variable_types[handler.name] = binding[1]
+                self.declared_vars.add(handler.name)
+            body_lines.append(self._convert_statements(handler.body))
+            if handler.name is not None and binding is not None:
+                # The name is unbound after the except clause, as in Python
+                self.declared_vars.discard(handler.name)
+                if saved_type is None:
+                    del self.variable_types[handler.name]
+                else:
+                    self.variable_types[handler.name] = saved_type
+            branches.append((condition, "\n".join(body_lines)))
+
+        chain = " else ".join(f"if {condition} {{\n{body}\n    }}" for condition, body in branches)
+        return f"    {chain} else {{\n    panic(_exc)\n    }}"
+
+    def _return_statement(self, value: Optional[str]) -> str:
+        """Return from the function, or from the closure of an enclosing try statement.
+
+        In a try body the closure returns (true, value); in an except clause,
+        which runs in a deferred function, the closure's named results are set.
+        """
+        if not self.try_contexts:
+            return f"    return {value}" if value is not None else "    return"
+        context = self.try_contexts[-1]
+        names = [context["done"]] + ([context["result"]] if value is not None else [])
+        values = ["true"] + ([value] if value is not None else [])
+        if context["in_handler"]:
+            return f"    {', '.join(names)} = {', '.join(values)}\n    return"
+        return f"    return {', '.join(values)}"
+
+    def _convert_delete(self, stmt: ast.Delete, class_name: Optional[str] = None) -> str:
+        """Convert Python del statement to runtime deletion helpers.
+
+        Example:
+            del items[0]    →  mgen.DelItem(&items, 0)
+            del counts["a"] →  counts.Delete("a")
+            del items[1:3]  →  mgen.DelSlice(&items, mgen.NewSlice(1, 3, nil))
+            del obj.attr    →  mgen.DelAttr(&obj, "attr")
+        """
+
+        def convert(expr: ast.expr) -> str:
+            if class_name is not None:
+                return self._convert_method_expression(expr, class_name)
+            return self._convert_expression(expr)
+
+        def addressable(expr: ast.expr) -> str:
+            # Slices and structs must be passed by pointer so the deletion is visible
+            if class_name is not None and isinstance(expr, ast.Name) and expr.id == "self":
+                return "obj"
+            if isinstance(expr, ast.Name) and self.variable_types.get(expr.id, "").startswith("*mgen."):
+                return convert(expr)
+            if isinstance(expr, ast.Name) and self.variable_types.get(expr.id, "interface{}") == "interface{}":
+                return convert(expr)
+            return self._address_of(convert(expr))
+
+        statements = []
+        for target in stmt.targets:
+            if isinstance(target, ast.Subscript):
+                if isinstance(target.slice, ast.Slice):
+                    bounds = [
+                        convert(bound) if bound is not None else "nil"
+                        for bound in (target.slice.lower, target.slice.upper, target.slice.step)
+                    ]
+                    statements.append(
+                        f"    mgen.DelSlice({addressable(target.value)}, mgen.NewSlice({', '.join(bounds)}))"
+                    )
+                else:
+                    dict_types = dict_type_args(self._infer_type_from_value(target.value))
+                    if dict_types is not None:
+                        key_expr = self._convert_key(target.slice, dict_types[0], convert)
+                        statements.append(f"    {convert(target.value)}.Delete({key_expr})")
+                    else:
+                        statements.append(f"    mgen.DelItem({addressable(target.value)}, {convert(target.slice)})")
+            elif isinstance(target, ast.Attribute):
+                statements.append(f'    mgen.DelAttr({addressable(target.value)}, "{target.attr}")')
+            elif isinstance(target, ast.Tuple):
+                statements.append(self._convert_delete(ast.Delete(targets=target.elts), class_name))
+            else:
+                raise UnsupportedFeatureError(f"Unsupported del target: {ast.unparse(target)}")
+
+        return "\n".join(statements)
 
     def _convert_return(self, stmt: ast.Return) -> str:
         """Convert return statement."""
         # Special case: in main(), ignore return statements
         if self.current_function == "main":
             return ""
+        if self.generator_item_type is not None:
+            # A generator's return ends its body; the value would become StopIteration.value
+            if stmt.value is not None and not (isinstance(stmt.value, ast.Constant) and stmt.value.value is None):
+                raise UnsupportedFeatureError("return with a value inside a generator is not supported")
+            return self._return_statement(None)
 
         if stmt.value:
-            value_expr = self._convert_expression(stmt.value)
-            return f"    return {value_expr}"
-        return "    return"
+            return_type = self.function_return_types.get(self.current_function or "", "")
+            self._expect_func_type(stmt.value, return_type)
+            if self._is_optional_type(return_type):
+                return self._return_statement(self._convert_optional_value(stmt.value, return_type))
+            if tuple_type_args(return_type) is not None:
+                # return a, b from a function returning tuple[A, B] builds its Tuple2
+                return self._return_statement(self._convert_key(stmt.value, return_type))
+            value_expr = self._convert_class_value(stmt.value, self._convert_expression(stmt.value), return_type)
+            return self._return_statement(self._coerce_int_precision(return_type, stmt.value, value_expr))
+        return self._return_statement(None)
+
+    def _is_optional_type(self, go_type: str) -> bool:
+        """Check whether a Go type is the *T representation of Python's Optional[T]."""
+        # Runtime reference types such as *mgen.PyFile are plain pointers, not optionals
+        return go_type.startswith("*") and not go_type.startswith("*mgen.")
+
+    def _convert_optional_value(self, value: ast.expr, target_type: str = "") -> str:
+        """Convert an expression for use where an Optional[T] (*T) is expected.
+
+        None becomes nil, Optional variables and calls returning Optional pass
+        through unchanged, and plain values are wrapped with mgen.Some.
+
+        Example:
+            None   →  nil
+            maybe  →  maybe             (maybe: Optional[int])
+            5      →  mgen.Some[int](5)
+        """
+        if isinstance(value, ast.Constant) and value.value is None:
+            return "nil"
+        if isinstance(value, ast.Name) and self._is_optional_type(self.variable_types.get(value.id, "")):
+            return value.id
+        if (
+            isinstance(value, ast.Call)
+            and isinstance(value.func, ast.Name)
+            and self._is_optional_type(self.function_return_types.get(value.func.id, ""))
+        ):
+            return self._convert_expression(value)
+        value_expr = self._convert_expression(value)
+        if not self._is_optional_type(target_type):
+            return value_expr
+        return f"mgen.Some[{target_type[1:]}]({value_expr})"
+
+    def _needs_chained_value(self, stmt: ast.Assign) -> bool:
+        """Whether a = b = value must evaluate value once before assigning each target."""
+        return len(stmt.targets) > 1 and not isinstance(stmt.value, (ast.Constant, ast.Name))
+
+    def _chained_value(self, stmt: ast.Assign, convert: Callable[[ast.expr], str]) -> tuple[str, ast.Assign]:
+        """Evaluate the value of a = b = value into a temporary, as Python evaluates it once.
+
+        Returns the temporary's declaration and the assignment rewritten to
+        assign the temporary, which then stores into each target left to
+        right (so xs[i] = ys[j] = f() calls f() once, before indexing).
+        """
+        self.loop_counter += 1
+        temp = f"chainValue{self.loop_counter}"
+        value_type = self._infer_type_from_value(stmt.value)
+        value_expr = convert(stmt.value)
+        if isinstance(stmt.value, ast.Call) or value_type == "interface{}":
+            declaration = f"    {temp} := {value_expr}"
+        else:
+            declaration = f"    var {temp} {value_type} = {value_expr}"
+        self.variable_types[temp] = value_type
+        self.declared_vars.add(temp)
+        return declaration, ast.Assign(targets=stmt.targets, value=ast.Name(id=temp, ctx=ast.Load()))
+
+    def _convert_unpacking(
+        self,
+        target: ast.expr,
+        value: ast.expr,
+        convert: Callable[[ast.expr], str],
+        assign: Callable[[ast.Assign], str],
+    ) -> str:
+        """Convert an assignment to a tuple or list target: a, b = b, a or first, *rest = xs.
+
+        A display of values for plain names becomes one Go parallel assignment,
+        which evaluates every value before storing any; for other targets the
+        values go through temporaries first. Any other value is evaluated once
+        and unpacked like a for loop target (_unpack_items). assign converts
+        the assignment of one item to a subscript or attribute target.
+
+        Example:
+            a, b = b, a        →  a, b = b, a
+            q, r = divmod2(x)  →  unpack1 := divmod2(x)
+                                  q := unpack1.First
+                                  r := unpack1.Second
+            first, *rest = xs  →  unpack2 := mgen.UnpackSliceStar(xs, 1, 0)
+                                  first := unpack2[0]
+                                  rest := append([]int{}, unpack2[1:]...)
+        """
+        lines: list[str] = []
+        pairs = self._display_pairs(target, value)
+        if pairs is not None:
+            names = [elt.id for elt, _ in pairs if isinstance(elt, ast.Name)]
+            if len(names) == len(pairs) and len(set(names)) == len(names):
+                return self._parallel_assignment(pairs, convert)
+            # Python evaluates every value before storing the first item
+            stored = []
+            for elt, elt_value in pairs:
+                temp = self._unpack_temp(convert(elt_value), self._infer_type_from_value(elt_value), lines)
+                stored.append((elt, temp))
+            for elt, temp in stored:
+                lines.append(assign(ast.Assign(targets=[elt], value=ast.Name(id=temp, ctx=ast.Load()))))
+            return "\n".join(lines)
+        value_type = self._infer_type_from_value(value)
+        source = convert(value)
+        if not isinstance(value, (ast.Name, ast.Constant)):
+            source = self._unpack_temp(source, value_type, lines)
+        self._unpack_items(target, source, value_type, assign, lines)
+        return "\n".join(lines)
+
+    def _display_pairs(self, target: ast.expr, value: ast.expr) -> Optional[list[tuple[ast.expr, ast.expr]]]:
+        """Pair the items of a tuple target with the items of a tuple display of the same length.
+
+        Nested targets matching nested displays are flattened: (a, b), c = (1, 2), 3
+        pairs a with 1, b with 2 and c with 3. Returns None when the value is not
+        such a display or either side is starred.
+        """
+        if not (isinstance(target, (ast.Tuple, ast.List)) and isinstance(value, (ast.Tuple, ast.List))):
+            return None
+        if len(target.elts) != len(value.elts):
+            return None
+        if any(isinstance(elt, ast.Starred) for elt in [*target.elts, *value.elts]):
+            return None
+        pairs: list[tuple[ast.expr, ast.expr]] = []
+        for elt, elt_value in zip(target.elts, value.elts):
+            nested = self._display_pairs(elt, elt_value)
+            pairs.extend(nested if nested is not None else [(elt, elt_value)])
+        return pairs
+
+    def _parallel_assignment(self, pairs: list[tuple[ast.expr, ast.expr]], convert: Callable[[ast.expr], str]) -> str:
+        """Assign each value of a display to a distinct name in one Go statement: a, b = b, a.
+
+        Names not yet declared are declared with := when all of them are new,
+        otherwise with var before the assignment.
+        """
+        names = [elt.id for elt, _ in pairs if isinstance(elt, ast.Name)]
+        values = []
+        new_names = [name for name in names if name not in self.declared_vars and name != "_"]
+        for name, value in zip(names, (elt_value for _, elt_value in pairs)):
+            var_type = self.variable_types.get(name) or self._infer_type_from_value(value)
+            self._expect_func_type(value, var_type)
+            value_expr = self._coerce_int_precision(var_type, value, convert(value))
+            values.append(self._convert_class_value(value, value_expr, var_type))
+            if name in new_names:
+                self.variable_types[name] = var_type
+        declarations = []
+        if len(new_names) < len([name for name in names if name != "_"]) or any(
+            self.variable_types[name] == BIG_INT_TYPE for name in new_names
+        ):
+            declarations = [f"    var {name} {self.variable_types[name]}" for name in new_names]
+            operator = "="
+        else:
+            operator = ":=" if new_names else "="
+        self.declared_vars.update(new_names)
+        return "\n".join([*declarations, f"    {', '.join(names)} {operator} {', '.join(values)}"])
+
+    def _unpack_temp(self, value_expr: str, value_type: str, lines: list[str]) -> str:
+        """Evaluate a value being unpacked into a temporary, so it is evaluated once."""
+        self.loop_counter += 1
+        temp = f"unpack{self.loop_counter}"
+        lines.append(f"    {temp} := {value_expr}")
+        self.variable_types[temp] = value_type
+        self.declared_vars.add(temp)
+        return temp
+
+    def _unpacked_item_types(self, target: ast.expr, value_type: str) -> tuple[Optional[str], list[str]]:
+        """Describe how a value of value_type unpacks into the items of a tuple target.
+
+        Returns the runtime call checking the item count, with {} standing for
+        the value (None when the value is a record of the right size, unpacked
+        through its fields), and the type of each target item. Slices keep their item type; anything else
+        unpacks into interface{} items.
+        """
+        assert isinstance(target, (ast.Tuple, ast.List))
+        starred = [i for i, elt in enumerate(target.elts) if isinstance(elt, ast.Starred)]
+        if len(starred) > 1:
+            raise UnsupportedFeatureError(f"multiple starred expressions in assignment: {ast.unparse(target)}")
+        count = len(target.elts)
+        fields = self._record_fields(value_type)
+        if fields is not None and count == len(fields[0]) and not starred:
+            return None, list(fields[1])
+        if value_type.startswith("[]"):
+            item_types = [value_type if i in starred else value_type[2:] for i in range(count)]
+            if starred:
+                return f"mgen.UnpackSliceStar({{}}, {starred[0]}, {count - starred[0] - 1})", item_types
+            return f"mgen.UnpackSlice({{}}, {count})", item_types
+        item_types = ["[]interface{}" if i in starred else "interface{}" for i in range(count)]
+        if starred:
+            return f"mgen.UnpackStar({{}}, {starred[0]}, {count - starred[0] - 1})", item_types
+        return f"mgen.UnpackN({{}}, {count})", item_types
+
+    def _unpacked_types(self, target: ast.expr, value: ast.expr) -> dict[str, str]:
+        """Types of the names an assignment to a tuple target binds, for the variable pre-pass."""
+        pairs = self._display_pairs(target, value)
+        if pairs is not None:
+            types: dict[str, str] = {}
+            for elt, elt_value in pairs:
+                if isinstance(elt, ast.Name):
+                    types[elt.id] = self._infer_type_from_value(elt_value)
+                else:
+                    types.update(self._unpacked_target_types(elt, self._infer_type_from_value(elt_value)))
+            return types
+        return self._unpacked_target_types(target, self._infer_type_from_value(value))
+
+    def _unpacked_target_types(self, target: ast.expr, value_type: str) -> dict[str, str]:
+        """Types of the names a (possibly nested) tuple target binds when unpacking a value of value_type."""
+        if isinstance(target, ast.Starred):
+            target = target.value
+        if isinstance(target, ast.Name):
+            return {target.id: value_type}
+        if not isinstance(target, (ast.Tuple, ast.List)):
+            return {}
+        types: dict[str, str] = {}
+        try:
+            _, item_types = self._unpacked_item_types(target, value_type)
+        except UnsupportedFeatureError:
+            return {}
+        for elt, item_type in zip(target.elts, item_types):
+            types.update(self._unpacked_target_types(elt, item_type))
+        return types
+
+    def _unpack_items(
+        self, target: ast.expr, source: str, value_type: str, assign: Callable[[ast.Assign], str], lines: list[str]
+    ) -> None:
+        """Emit the statements unpacking source, a value of value_type, into a (possibly nested) tuple target.
+
+        Records unpack through their typed fields and slices through
+        mgen.UnpackSlice/UnpackSliceStar, whose items keep the slice's item
+        type; anything else goes through mgen.UnpackN/UnpackStar. All of them
+        raise Python's ValueError when the number of items does not match.
+        """
+        assert isinstance(target, (ast.Tuple, ast.List))
+        call, item_types = self._unpacked_item_types(target, value_type)
+        count = len(target.elts)
+        starred = next((i for i, elt in enumerate(target.elts) if isinstance(elt, ast.Starred)), None)
+        if call is None:
+            fields = self._record_fields(value_type)
+            assert fields is not None
+            items = [f"{source}.{field}" for field in fields[0]]
+        else:
+            unpacked_type = value_type if value_type.startswith("[]") else "[]interface{}"
+            unpacked = self._unpack_temp(call.format(source), unpacked_type, lines)
+            items = [f"{unpacked}[{i}]" for i in range(count)]
+            if starred is not None and value_type.startswith("[]"):
+                # The starred name takes a copy of the middle of the slice; the items after it index from its end
+                after = count - starred - 1
+                middle = f"{unpacked}[{starred}:len({unpacked})-{after}]" if after else f"{unpacked}[{starred}:]"
+                items[starred] = f"append({value_type}{{}}, {middle}...)"
+                items[starred + 1 :] = [f"{unpacked}[len({unpacked})-{count - i}]" for i in range(starred + 1, count)]
+            elif starred is not None:
+                items[starred] = f"{unpacked}[{starred}].([]interface{{}})"
+        for elt, item, item_type in zip(target.elts, items, item_types):
+            if isinstance(elt, ast.Starred):
+                elt = elt.value
+            if isinstance(elt, (ast.Tuple, ast.List)):
+                self._unpack_items(elt, item, item_type, assign, lines)
+            elif isinstance(elt, ast.Name):
+                lines.append(self._assign_unpacked(elt.id, item, item_type))
+            else:
+                # Subscript and attribute targets store the item like a plain assignment would
+                temp = self._unpack_temp(item, item_type, lines)
+                lines.append(assign(ast.Assign(targets=[elt], value=ast.Name(id=temp, ctx=ast.Load()))))
+
+    def _assign_unpacked(self, name: str, item: str, item_type: str) -> str:
+        """Bind a name to an unpacked item, asserting interface{} items to the name's declared type."""
+        if name == "_":
+            return f"    _ = {item}"
+        var_type = self.variable_types.get(name, item_type)
+        if item_type == "interface{}" and var_type not in ("", "interface{}"):
+            item = f"{item}.({var_type})"
+        if name in self.declared_vars:
+            return f"    {name} = {item}"
+        self.declared_vars.add(name)
+        if var_type != item_type and item_type != "interface{}":
+            return f"    var {name} {var_type} = {item}"
+        self.variable_types[name] = var_type
+        return f"    {name} := {item}"
 
     def _convert_assignment(self, stmt: ast.Assign) -> str:
         """Convert assignment statement."""
+        reference = self._convert_reference_assignment(stmt)
+        if reference is not None:
+            return reference
+        if self._needs_chained_value(stmt):
+            temp_decl, chained = self._chained_value(stmt, self._convert_expression)
+            return temp_decl + "\n" + self._convert_assignment(chained)
+        if len(stmt.targets) == 1 and isinstance(stmt.targets[0], (ast.Tuple, ast.List)):
+            target = stmt.targets[0]
+            return self._convert_unpacking(target, stmt.value, self._convert_expression, self._convert_assignment)
+        if len(stmt.targets) == 1 and isinstance(stmt.targets[0], ast.Name):
+            self._expect_func_type(stmt.value, self.variable_types.get(stmt.targets[0].id, ""))
+            self._expect_dict_type(stmt.value, self.variable_types.get(stmt.targets[0].id, ""))
+            self._expect_interface_list(stmt.value, self.variable_types.get(stmt.targets[0].id, ""))
         value_expr = self._convert_expression(stmt.value)
         statements = []
 
         for target in stmt.targets:
             if isinstance(target, ast.Name):
+                if self.variable_types.get(target.id) == BIG_INT_TYPE:
+                    # An int that may outgrow int64 holds every value it is assigned as an mgen.PyInt
+                    big_value = self._coerce_int_precision(BIG_INT_TYPE, stmt.value, value_expr)
+                    if target.id in self.declared_vars:
+                        statements.append(f"    {target.id} = {big_value}")
+                    else:
+                        self.declared_vars.add(target.id)
+                        statements.append(f"    var {target.id} {BIG_INT_TYPE} = {big_value}")
+                    continue
                 if target.id in self.declared_vars:
                     # Variable already declared, use assignment
-                    statements.append(f"    {target.id} = {value_expr}")
+                    target_type = self.variable_types.get(target.id, "")
+                    if self._is_optional_type(target_type):
+                        statements.append(f"    {target.id} = {self._convert_optional_value(stmt.value, target_type)}")
+                        continue
+                    coerced_expr = self._coerce_int_precision(target_type, stmt.value, value_expr)
+                    coerced_expr = self._convert_class_value(stmt.value, coerced_expr, target_type)
+                    statements.append(f"    {target.id} = {coerced_expr}")
                 else:
                     # First declaration of variable
                     self.declared_vars.add(target.id)
 
+                    target_type = self.variable_types.get(target.id, "")
+                    if self._interface_class(target_type):
+                        # A variable of a subclassed class's type holds its interface
+                        class_value = self._convert_class_value(stmt.value, value_expr, target_type)
+                        statements.append(f"    var {target.id} {target_type} = {class_value}")
+                    elif self._is_empty_container(stmt.value) and target_type.startswith(("*mgen.Dict[", "*mgen.Set[")):
+                        # {}, dict() and set() take the type the container's later use gave it
+                        statements.append(f"    var {target.id} {target_type} = {self._get_default_value(target_type)}")
                     # For function calls, always use := to let Go infer the correct type
-                    if isinstance(stmt.value, ast.Call):
+                    elif isinstance(stmt.value, ast.Call):
                         # Update variable_types if we have a pre-computed type
                         if target.id in self.variable_types:
                             # Already pre-computed, just use :=
@@ -1089,20 +4989,59 @@ class MGenPythonToGoConverter:
                             self.variable_types[target.id] = var_type
 
                         # Use := for constructor calls and interface{} for cleaner code
-                        if var_type == "interface{}" or self._is_constructor_call(stmt.value):
+                        if self._is_optional_type(var_type):
+                            optional_expr = self._convert_optional_value(stmt.value, var_type)
+                            statements.append(f"    var {target.id} {var_type} = {optional_expr}")
+                        elif var_type == "interface{}" or self._is_constructor_call(stmt.value):
                             statements.append(f"    {target.id} := {value_expr}")
                         else:
                             statements.append(f"    var {target.id} {var_type} = {value_expr}")
             elif isinstance(target, ast.Subscript):
-                # Handle subscript assignment: container[index] = value
-                container_expr = self._convert_expression(target.value)
-                index_expr = self._convert_expression(target.slice)
-                statements.append(f"    {container_expr}[{index_expr}] = {value_expr}")
+                statements.append(self._convert_subscript_assignment(target, stmt.value, value_expr))
+            elif isinstance(target, (ast.Tuple, ast.List)):
+                statements.append(
+                    self._convert_unpacking(target, stmt.value, self._convert_expression, self._convert_assignment)
+                )
+            elif isinstance(target, ast.Attribute):
+                obj_expr = self._convert_instance(target.value, self._convert_expression(target.value))
+                setter = self._property_setter(self._property_class(target.value), obj_expr, target.attr, value_expr)
+                statements.append(setter or f"    {obj_expr}.{self._to_camel_case(target.attr)} = {value_expr}")
 
         return "\n".join(statements)
 
+    def _convert_subscript_assignment(
+        self,
+        target: ast.Subscript,
+        value: ast.expr,
+        value_expr: str,
+        convert: Optional[Callable[[ast.expr], str]] = None,
+    ) -> str:
+        """Convert container[index] = value; convert converts the operands (_convert_expression by default)."""
+        convert = convert or self._convert_expression
+        container_expr = convert(target.value)
+        index_expr = convert(target.slice)
+        operator_call = self._convert_operator_call(
+            target.value, container_expr, "__setitem__", [(target.slice, index_expr), (value, value_expr)]
+        )
+        if operator_call is not None:
+            # A class defining __setitem__ stores the item itself: bag[i] = x -> bag.PySetItem(i, x)
+            return f"    {operator_call}"
+        container_type = self._infer_type_from_value(target.value)
+        dict_types = dict_type_args(container_type)
+        if dict_types is not None:
+            index_expr = self._convert_key(target.slice, dict_types[0], convert)
+            return f"    {container_expr}.Set({index_expr}, {value_expr})"
+        elif container_type == "*mgen.PyDict":
+            return f"    {container_expr}.Set({index_expr}, {value_expr})"
+        elif container_type == "*mgen.PyByteArray":
+            return f"    {container_expr}.SetItem({index_expr}, {value_expr})"
+        return f"    {container_expr}[{index_expr}] = {value_expr}"
+
     def _convert_annotated_assignment(self, stmt: ast.AnnAssign) -> str:
         """Convert annotated assignment."""
+        reference = self._convert_reference_assignment(stmt)
+        if reference is not None:
+            return reference
         # Use pre-computed type if available, otherwise map from annotation
         if isinstance(stmt.target, ast.Name) and stmt.target.id in self.variable_types:
             var_type = self.variable_types[stmt.target.id]
@@ -1121,9 +5060,32 @@ class MGenPythonToGoConverter:
                         var_type = f"[]{self.variable_types[appended_var]}"
 
         if stmt.value:
-            # For empty dict, use the upgraded type
-            if isinstance(stmt.value, ast.Dict) and not stmt.value.keys:
-                value_expr = f"make({var_type})"
+            self._expect_func_type(stmt.value, var_type)
+            if var_type.startswith("*mgen.Deque[") and isinstance(stmt.value, (ast.List, ast.Call)):
+                self.deque_values.setdefault(id(stmt.value), var_type[len("*mgen.Deque[") : -1])
+            self._expect_dict_type(stmt.value, var_type)
+            self._expect_interface_list(stmt.value, var_type)
+        if stmt.value and isinstance(stmt.target, ast.Name) and self._interface_class(var_type):
+            # A variable of a subclassed class's type holds its interface
+            self.declared_vars.add(stmt.target.id)
+            self.variable_types[stmt.target.id] = var_type
+            class_value = self._convert_class_value(stmt.value, self._convert_expression(stmt.value), var_type)
+            return f"    var {stmt.target.id} {var_type} = {class_value}"
+        if stmt.value and isinstance(stmt.target, ast.Name) and var_type == BIG_INT_TYPE:
+            self.declared_vars.add(stmt.target.id)
+            self.variable_types[stmt.target.id] = var_type
+            big_value = self._coerce_int_precision(var_type, stmt.value, self._convert_expression(stmt.value))
+            return f"    var {stmt.target.id} {var_type} = {big_value}"
+        if stmt.value and isinstance(stmt.target, ast.Name) and self._is_optional_type(var_type):
+            # Optional[T] variable: None -> nil, plain values wrapped with mgen.Some
+            self.declared_vars.add(stmt.target.id)
+            self.variable_types[stmt.target.id] = var_type
+            return f"    var {stmt.target.id} {var_type} = {self._convert_optional_value(stmt.value, var_type)}"
+
+        if stmt.value:
+            # For empty dict or set, use the upgraded type
+            if self._is_empty_container(stmt.value):
+                value_expr = self._get_default_value(var_type)
             # For function calls, use := to let Go infer the type correctly (except make())
             elif isinstance(stmt.value, ast.Call):
                 # Special case: make() should use the annotated/upgraded type
@@ -1142,7 +5104,7 @@ class MGenPythonToGoConverter:
                             self.variable_types[stmt.target.id] = var_type
                     return f"    {target_id} := {value_expr}"
             # For empty lists, use the correct type
-            elif isinstance(stmt.value, ast.List) and not stmt.value.elts:
+            elif isinstance(stmt.value, ast.List) and not stmt.value.elts and id(stmt.value) not in self.deque_values:
                 value_expr = f"{var_type}{{}}"
             else:
                 value_expr = self._convert_expression(stmt.value)
@@ -1170,22 +5132,123 @@ class MGenPythonToGoConverter:
         value_expr = self._convert_expression(stmt.value)
 
         # Get augmented assignment operator from converter_utils
-        op = get_augmented_assignment_operator(stmt.op)
-        if op is None:
-            # Handle Go-specific operators
-            if isinstance(stmt.op, ast.FloorDiv):
-                op = "/="  # Go integer division is already floor division
-            else:
-                op = "/*UNKNOWN_OP*/"
+        op = get_augmented_assignment_operator(stmt.op) or "/*UNKNOWN_OP*/"
 
         if isinstance(stmt.target, ast.Name):
-            return f"    {stmt.target.id} {op} {value_expr}"
+            target_type = self.variable_types.get(stmt.target.id, "")
+            updated = ast.BinOp(left=ast.Name(id=stmt.target.id, ctx=ast.Load()), op=stmt.op, right=stmt.value)
+            if BIG_INT_TYPE in (target_type, self._infer_type_from_value(updated)):
+                # x op= y on an int that may outgrow int64 rebinds x to the mgen.PyInt result
+                updated_expr = self._coerce_int_precision(target_type, updated, self._convert_expression(updated))
+                return f"    {stmt.target.id} = {updated_expr}"
+            # A list held by pointer (see _reference_lists) is grown through it
+            target_expr = f"(*{stmt.target.id})" if stmt.target.id in self.reference_lists else stmt.target.id
+            special = self._convert_container_aug_assignment(target_expr, target_type, stmt.op, value_expr)
+            if special is None and self._is_py2_int_division(ast.BinOp(left=stmt.target, op=stmt.op, right=stmt.value)):
+                special = f"    {stmt.target.id} = mgen.FloorDivInt({stmt.target.id}, {value_expr})"
+            special = special or self._convert_floored_aug_assignment(stmt, stmt.target.id, value_expr)
+            if special is None and isinstance(stmt.op, ast.Pow):
+                # Go has no **= operator, so x **= y rebinds x to x ** y
+                special = f"    {stmt.target.id} = {self._convert_expression(updated)}"
+            return special or f"    {stmt.target.id} {op} {value_expr}"
+        dict_update = self._convert_dict_aug_assignment(stmt, self._convert_expression)
+        if dict_update is not None:
+            return dict_update
 
         raise UnsupportedFeatureError(f"Complex augmented assignment target not supported: {ast.unparse(stmt.target)}")
 
+    def _convert_dict_aug_assignment(self, stmt: ast.AugAssign, convert: Callable[[ast.expr], str]) -> Optional[str]:
+        """Convert d[k] op= v on a dict, which reads and stores the entry: d.Set(k, d.Get(k) op v).
+
+        convert converts the operands (_convert_expression, or the method
+        context's converter). Returns None for other targets.
+        """
+        if not isinstance(stmt.target, ast.Subscript) or isinstance(stmt.target.slice, ast.Slice):
+            return None
+        container_type = self._infer_type_from_value(stmt.target.value)
+        if container_type != "*mgen.PyDict" and not container_type.startswith("*mgen.Dict["):
+            return None
+        current = ast.Subscript(value=stmt.target.value, slice=stmt.target.slice, ctx=ast.Load())
+        updated = convert(ast.BinOp(left=current, op=stmt.op, right=stmt.value))
+        container_expr = convert(stmt.target.value)
+        dict_types = dict_type_args(container_type)
+        if dict_types is not None:
+            index_expr = self._convert_key(stmt.target.slice, dict_types[0], convert)
+        else:
+            index_expr = convert(stmt.target.slice)
+        return f"    {container_expr}.Set({index_expr}, {updated})"
+
+    def _convert_container_aug_assignment(
+        self, target_expr: str, target_type: str, op: ast.operator, value_expr: str
+    ) -> Optional[str]:
+        """Convert augmented assignment whose Python semantics differ from Go's operator.
+
+        Lists and sets are mutated in place (``xs += ys`` extends), while dynamically typed
+        targets go through the runtime dispatcher, which extends shared lists in
+        place and rebinds immutable values. Returns None when Go's native
+        operator already matches Python (numbers and strings).
+
+        Example:
+            xs += ys  (list[int])  →  mgen.ExtendSlice(&xs, ys)
+            xs *= 2   (list[int])  →  xs = mgen.RepeatSlice(xs, 2)
+            s |= t    (set[int])   →  s.Update(t)
+            buf += b  (bytearray)  →  buf.Extend(b)
+            x += y    (untyped)    →  x = mgen.AugAssign("+=", x, y)
+        """
+        if target_expr in self.string_accumulators and isinstance(op, ast.Add):
+            return f"    {self.string_accumulators[target_expr]}.Add({value_expr})"
+        if target_type.startswith("[]"):
+            if isinstance(op, ast.Add):
+                return f"    mgen.ExtendSlice({self._address_of(target_expr)}, {value_expr})"
+            if isinstance(op, ast.Mult):
+                return f"    {target_expr} = mgen.RepeatSlice({target_expr}, {value_expr})"
+        elif target_type in BYTES_TYPES and isinstance(op, (ast.Add, ast.Mult)):
+            if target_type == "*mgen.PyByteArray" and isinstance(op, ast.Add):
+                return f"    {target_expr}.Extend({value_expr})"
+            go_name = "Concat" if isinstance(op, ast.Add) else "Repeat"
+            return f"    {target_expr} = {target_expr}.{go_name}({value_expr})"
+        elif set_type_arg(target_type) is not None and type(op) in SET_OPERATOR_METHODS:
+            update = "Update" if isinstance(op, ast.BitOr) else f"{SET_OPERATOR_METHODS[type(op)]}Update"
+            return f"    {target_expr}.{update}({value_expr})"
+        elif target_type == "interface{}":
+            py_op = get_augmented_assignment_operator(op) or ("//=" if isinstance(op, ast.FloorDiv) else "**=")
+            return f'    {target_expr} = mgen.AugAssign("{py_op}", {target_expr}, {value_expr})'
+        return None
+
+    def _convert_floored_aug_assignment(self, stmt: ast.AugAssign, target_expr: str, value_expr: str) -> Optional[str]:
+        """Convert x //= y and x %= y, which have no Go operator with Python's semantics.
+
+        Numbers go through the flooring runtime helpers and a string target
+        through printf-style formatting; returns None for the other operators.
+
+        Example:
+            n //= 2   →  n = mgen.FloorDiv(n, 2)
+            s %= x    →  s = mgen.PercentFormat(s, x)   (s: str)
+        """
+        if not isinstance(stmt.op, (ast.FloorDiv, ast.Mod)):
+            return None
+        expr = ast.BinOp(left=stmt.target, op=stmt.op, right=stmt.value)
+        if self._is_percent_format(expr):
+            updated = f"mgen.PercentFormat({target_expr}, {self._percent_format_args(stmt.value, value_expr)})"
+        else:
+            updated = self._convert_floored_operator(expr, target_expr, value_expr)
+        return f"    {target_expr} = {updated}"
+
+    def _convert_condition(self, test: ast.expr) -> str:
+        """Convert the test of an if or while; deques, dicts and sets are true when non-empty."""
+        if self._is_sized_container(self._infer_type_from_value(test)):
+            return f"{self._convert_expression(test)}.Len() > 0"
+        return self._convert_expression(test)
+
+    def _is_sized_container(self, go_type: str) -> bool:
+        """Report whether go_type is a runtime container whose truth value is its Len() being non-zero."""
+        return go_type in ("*mgen.PyDict", "*mgen.PySet", "*mgen.PyByteArray") or go_type.startswith(
+            ("*mgen.Deque[", "*mgen.Dict[", "*mgen.Set[")
+        )
+
     def _convert_if(self, stmt: ast.If) -> str:
         """Convert if statement."""
-        condition = self._convert_expression(stmt.test)
+        condition = self._convert_condition(stmt.test)
         then_body = self._convert_statements(stmt.body)
         if_part = f"    if {condition} {{\n{then_body}\n    }}"
 
@@ -1201,52 +5264,845 @@ class MGenPythonToGoConverter:
         else:
             return if_part
 
-    def _convert_while(self, stmt: ast.While) -> str:
-        """Convert while loop."""
-        condition = self._convert_expression(stmt.test)
-        body = self._convert_statements(stmt.body)
-        return "    for " + condition + " {\n" + body + "\n    }"
+    def _convert_match(self, stmt: ast.Match) -> str:
+        """Convert a match statement to a chain of pattern checks.
 
-    def _convert_for(self, stmt: ast.For) -> str:
-        """Convert for loop."""
-        if isinstance(stmt.iter, ast.Call) and isinstance(stmt.iter.func, ast.Name) and stmt.iter.func.id == "range":
-            # Range-based for loop
-            range_args = [self._convert_expression(arg) for arg in stmt.iter.args]
-            target_name = stmt.target.id if isinstance(stmt.target, ast.Name) else "i"
+        Each case becomes an if whose condition tests the pattern's shape
+        (see _match_pattern); its captures are bound at the top of the block.
+        A guard that reads captures has to run after they are bound, inside
+        the block, so when such a guard can fail over to a later case every
+        case is a separate if that records the match in a matchedN flag.
+        Captures are scoped to their case, and names the case never reads are
+        not bound. Or-patterns that capture names are not supported.
 
-            if len(range_args) == 1:
-                # range(n)
-                stop = range_args[0]
-                body = self._convert_statements(stmt.body)
-                return f"    for {target_name} := 0; {target_name} < {stop}; {target_name}++ {{\n{body}\n    }}"
-            elif len(range_args) == 2:
-                # range(start, stop)
-                start, stop = range_args
-                body = self._convert_statements(stmt.body)
-                return f"    for {target_name} := {start}; {target_name} < {stop}; {target_name}++ {{\n{body}\n    }}"
-            elif len(range_args) == 3:
-                # range(start, stop, step)
-                start, stop, step = range_args
-                body = self._convert_statements(stmt.body)
-                return f"    for {target_name} := {start}; {target_name} < {stop}; {target_name} += {step} {{\n{body}\n    }}"
+        Example:
+            match point:               if mgen.MatchSequence(point, 2, false) && mgen.Eq(mgen.UnpackN(point, 2)[1], 0) {
+                case [x, 0]:               x := mgen.UnpackN(point, 2)[0]
+                    print(x)               mgen.Print(x)
+                case _:                } else {
+                    print("off")           mgen.Print("off")
+                                       }
+        """
+        subject = self._convert_expression(stmt.subject)
+        subject_type = self._infer_type_from_value(stmt.subject)
+        if self.method_class is not None and isinstance(stmt.subject, ast.Name) and subject_type == "interface{}":
+            # A method's parameter types are not among variable_types (see _convert_method)
+            subject_type = self._method_parameter_types().get(stmt.subject.id, subject_type)
+        lines = []
+        if not isinstance(stmt.subject, ast.Name):
+            # The subject is evaluated once, as in Python
+            self.loop_counter += 1
+            lines.append(f"    match{self.loop_counter} := {subject}")
+            subject = f"match{self.loop_counter}"
+
+        flag = None
+        if self._match_needs_flag(stmt):
+            self.loop_counter += 1
+            flag = f"matched{self.loop_counter}"
+            lines.append(f"    {flag} := false")
+
+        branches = []
+        for i, case in enumerate(stmt.cases):
+            used = {
+                node.id
+                for part in [case.guard, *case.body]
+                if part is not None
+                for node in ast.walk(part)
+                if isinstance(node, ast.Name) and isinstance(node.ctx, ast.Load)
+            }
+            conditions: list[str] = []
+            bindings: list[tuple[str, str, str]] = []
+            self._match_pattern(case.pattern, subject, subject_type, conditions, bindings, used)
+
+            # Captures shadow any outer variable of the same name inside the case
+            captured = {name for name, _, _ in bindings}
+            saved_types = {name: self.variable_types[name] for name in captured if name in self.variable_types}
+            undeclared = captured - self.declared_vars
+            block = []
+            for name, value, value_type in bindings:
+                self.variable_types[name] = value_type
+                self.declared_vars.add(name)
+                block.append(f"    {name} := {value}")
+            guard = self._convert_condition(case.guard) if case.guard is not None else None
+            body = self._convert_statements(case.body)
+            for name in captured:
+                if name in saved_types:
+                    self.variable_types[name] = saved_types[name]
+                else:
+                    self.variable_types.pop(name, None)
+            self.declared_vars -= undeclared
+
+            reads_captures = case.guard is not None and any(
+                isinstance(node, ast.Name) and node.id in captured for node in ast.walk(case.guard)
+            )
+            if flag is not None and i < len(stmt.cases) - 1:
+                # Record the match before the body runs, since it may return or break
+                body = f"    {flag} = true\n{body}"
+            if reads_captures:
+                block.append(f"    if {guard} {{\n{body}\n    }}")
             else:
-                # Invalid range arguments
-                body = self._convert_statements(stmt.body)
-                return (
-                    f"    for {target_name} := 0; {target_name} < 0; {target_name}++ {{\n{body}\n    }}"  # Empty loop
-                )
-        else:
-            # Iteration over container
-            container_expr = self._convert_expression(stmt.iter)
-            target_name = stmt.target.id if isinstance(stmt.target, ast.Name) else "item"
+                if guard is not None:
+                    conditions.append(f"({guard})")
+                block.append(body)
+            block_text = "\n".join(block)
+
+            if flag is not None:
+                condition = " && ".join([f"!{flag}"] * (i > 0) + conditions) or "true"
+                branches.append(f"    if {condition} {{\n{block_text}\n    }}")
+                continue
+            condition = " && ".join(conditions)
+            if not condition:
+                # An irrefutable pattern can only be the last case
+                branches.append(("    {" if i == 0 else " else {") + f"\n{block_text}\n    }}")
+                break
+            keyword = "    if" if i == 0 else " else if"
+            branches.append(f"{keyword} {condition} {{\n{block_text}\n    }}")
+        lines.append(("\n" if flag is not None else "").join(branches))
+        return "\n".join(lines)
+
+    def _current_method(self) -> ast.FunctionDef:
+        """Return the method being converted."""
+        assert self.method_class is not None
+        info = self.struct_info[self.method_class]
+        if self.current_function == "__init__":
+            return info["init"]
+        return info["methods"][self.current_function]["node"]
+
+    def _method_parameter_types(self) -> dict[str, str]:
+        """Return the Go types of the parameters of the method being converted."""
+        method = self._current_method()
+        return dict(zip([arg.arg for arg in method.args.args[1:]], self._method_types(method)[0]))
+
+    def _method_scope_types(self) -> dict[str, str]:
+        """Return the Go types of the parameters and locals of the method being converted.
+
+        Methods do not record their locals' types as they convert them (see
+        _convert_method), so a closure defined in one types the variables it
+        captures from here: a local takes the type of its annotation or of
+        the value first assigned to it.
+        """
+        types = self._method_parameter_types()
+        outer_types = self.variable_types
+        try:
+            for node in self._local_nodes(self._current_method().body):
+                self.variable_types = {**types, **outer_types}
+                if isinstance(node, ast.AnnAssign) and isinstance(node.target, ast.Name):
+                    types.setdefault(node.target.id, self._map_type_annotation(node.annotation))
+                elif isinstance(node, ast.Assign):
+                    for target in node.targets:
+                        if isinstance(target, ast.Name):
+                            types.setdefault(target.id, self._infer_type_from_value(node.value))
+        finally:
+            self.variable_types = outer_types
+        return types
+
+    def _match_needs_flag(self, stmt: ast.Match) -> bool:
+        """Whether a case before the last has a guard that reads a name its pattern captures."""
+        for case in stmt.cases[:-1]:
+            if case.guard is None:
+                continue
+            captured = {
+                node.name
+                for node in ast.walk(case.pattern)
+                if isinstance(node, (ast.MatchAs, ast.MatchStar)) and node.name is not None
+            }
+            if any(isinstance(node, ast.Name) and node.id in captured for node in ast.walk(case.guard)):
+                return True
+        return False
+
+    def _match_pattern(
+        self,
+        pattern: ast.pattern,
+        value: str,
+        value_type: str,
+        conditions: list[str],
+        bindings: list[tuple[str, str, str]],
+        used: set[str],
+    ) -> None:
+        """Add the checks that value matches pattern, and the captures it binds.
+
+        Conditions are listed so that each one only runs once the earlier ones
+        hold: a sequence's length is checked before its items are looked at.
+        """
+        if isinstance(pattern, ast.MatchAs):
+            if pattern.pattern is not None:
+                self._match_pattern(pattern.pattern, value, value_type, conditions, bindings, used)
+            if pattern.name is not None and pattern.name in used:
+                if isinstance(pattern.pattern, ast.MatchClass) and isinstance(pattern.pattern.cls, ast.Name):
+                    # str() as s binds s as the str the class pattern checked for
+                    value, value_type = self._narrowed_match_value(pattern.pattern.cls.id, value, value_type)
+                bindings.append((pattern.name, value, value_type))
+        elif isinstance(pattern, ast.MatchValue):
+            literal = self._convert_expression(pattern.value)
+            literal_type = self._infer_type_from_value(pattern.value)
+            if value_type == literal_type and value_type in ("int", "float64", "string", "bool"):
+                conditions.append(f"{value} == {literal}")
+            else:
+                conditions.append(f"mgen.Eq({value}, {literal})")
+        elif isinstance(pattern, ast.MatchSingleton):
+            if value_type == "interface{}":
+                conditions.append(f"{value} == {'nil' if pattern.value is None else str(pattern.value).lower()}")
+            elif pattern.value is None and value_type.startswith(("*", "[]")):
+                conditions.append(f"{value} == nil")
+            elif value_type == "bool" and pattern.value is not None:
+                conditions.append(value if pattern.value else f"!{value}")
+            else:
+                conditions.append("false")
+        elif isinstance(pattern, ast.MatchSequence):
+            self._match_sequence(pattern, value, value_type, conditions, bindings, used)
+        elif isinstance(pattern, ast.MatchOr):
+            if any(
+                isinstance(node, (ast.MatchAs, ast.MatchStar)) and node.name is not None
+                for node in ast.walk(pattern)
+            ):
+                raise UnsupportedFeatureError(
+                    f"or-patterns that capture names are not supported: {ast.unparse(pattern)}"
+                )
+            alternatives = []
+            for alternative in pattern.patterns:
+                checks: list[str] = []
+                self._match_pattern(alternative, value, value_type, checks, bindings, used)
+                alternatives.append(" && ".join(checks) or "true")
+            conditions.append("(" + " || ".join(alternatives) + ")")
+        elif isinstance(pattern, ast.MatchClass) and ast.unparse(pattern.cls) in self.struct_info:
+            self._match_class(pattern, value, value_type, conditions, bindings, used)
+        elif isinstance(pattern, ast.MatchClass):
+            builtin_types = ("int", "float", "str", "bool", "bytes", "list", "tuple", "dict", "set", "object")
+            if not (isinstance(pattern.cls, ast.Name) and pattern.cls.id in builtin_types):
+                raise UnsupportedFeatureError(
+                    f"Class patterns need a builtin type or a generated class: {ast.unparse(pattern)}"
+                )
+            if pattern.kwd_patterns or len(pattern.patterns) > 1:
+                raise UnsupportedFeatureError(f"Unsupported class pattern arguments: {ast.unparse(pattern)}")
+            conditions.append(f'mgen.MatchClass({value}, "{pattern.cls.id}")')
+            if pattern.patterns:
+                # int(x) matches the subject itself against x, now known to be an int
+                value, value_type = self._narrowed_match_value(pattern.cls.id, value, value_type)
+                self._match_pattern(pattern.patterns[0], value, value_type, conditions, bindings, used)
+        elif isinstance(pattern, ast.MatchMapping):
+            self._match_mapping(pattern, value, value_type, conditions, bindings, used)
+        else:
+            raise UnsupportedFeatureError(f"Unsupported match pattern: {ast.unparse(pattern)}")
+
+    def _narrowed_match_value(self, class_name: str, value: str, value_type: str) -> tuple[str, str]:
+        """Return an untyped value a builtin class pattern has matched as that class, and its Go type.
+
+        A bool matched by int() is converted, since it is no Go int, and a list
+        is read into a new []interface{} whatever the slice it holds.
+        """
+        narrowed = {"int": "int", "float": "float64", "str": "string", "bool": "bool", "list": "[]interface{}"}
+        go_type = narrowed.get(class_name)
+        if value_type != "interface{}" or go_type is None:
+            return value, value_type
+        if go_type == "int":
+            return f"mgen.ToInt({value})", go_type
+        if go_type == "[]interface{}":
+            return f"mgen.ToList({value})", go_type
+        return f"{value}.({go_type})", go_type
+
+    def _match_sequence(
+        self,
+        pattern: ast.MatchSequence,
+        value: str,
+        value_type: str,
+        conditions: list[str],
+        bindings: list[tuple[str, str, str]],
+        used: set[str],
+    ) -> None:
+        """Add the checks and captures of a sequence pattern such as [a, *rest, 0].
+
+        Typed slices are indexed directly and Pair/KV records through their
+        fields; other values are checked with mgen.MatchSequence and read with
+        mgen.UnpackN or mgen.UnpackStar.
+        """
+        items = pattern.patterns
+        starred = [i for i, item in enumerate(items) if isinstance(item, ast.MatchStar)]
+        fixed = len(items) - len(starred)
+        after = fixed - starred[0] if starred else 0
+        fields = self._record_fields(value_type)
+        if fields is not None:
+            if starred or len(items) != len(fields[0]):
+                conditions.append("false")
+                return
+            for item, field, field_type in zip(items, *fields):
+                self._match_pattern(item, f"{value}.{field}", field_type, conditions, bindings, used)
+            return
+
+        if value_type.startswith("[]"):
+            elem_type = value_type[2:]
+            conditions.append(f"len({value}) {'>=' if starred else '=='} {fixed}")
+            for i, item in enumerate(items):
+                if isinstance(item, ast.MatchStar):
+                    if item.name is not None and item.name in used:
+                        end = f"len({value})-{after}" if after else ""
+                        bindings.append((item.name, f"{value}[{i}:{end}]", value_type))
+                elif starred and i > starred[0]:
+                    item_value = f"{value}[len({value})-{len(items) - i}]"
+                    self._match_pattern(item, item_value, elem_type, conditions, bindings, used)
+                else:
+                    self._match_pattern(item, f"{value}[{i}]", elem_type, conditions, bindings, used)
+            return
+
+        conditions.append(f"mgen.MatchSequence({value}, {fixed}, {'true' if starred else 'false'})")
+        if starred:
+            unpacked = f"mgen.UnpackStar({value}, {starred[0]}, {after})"
+        else:
+            unpacked = f"mgen.UnpackN({value}, {fixed})"
+        for i, item in enumerate(items):
+            if isinstance(item, ast.MatchStar):
+                if item.name is not None and item.name in used:
+                    bindings.append((item.name, f"{unpacked}[{i}].([]interface{{}})", "[]interface{}"))
+            else:
+                self._match_pattern(item, f"{unpacked}[{i}]", "interface{}", conditions, bindings, used)
+
+    def _match_mapping(
+        self,
+        pattern: ast.MatchMapping,
+        value: str,
+        value_type: str,
+        conditions: list[str],
+        bindings: list[tuple[str, str, str]],
+        used: set[str],
+    ) -> None:
+        """Add the checks and captures of a mapping pattern such as {"op": op, **rest}.
+
+        Typed dicts with keys of the pattern's key type are looked up
+        directly; other values are checked with mgen.MatchMapping and read
+        with mgen.MappingValue. As in Python, entries the pattern does not
+        name are ignored, and **rest captures them as a new dict.
+        """
+        if value_type in ("int", "float64", "string", "bool") or value_type.startswith("[]"):
+            conditions.append("false")
+            return
+        dict_types = dict_type_args(value_type) if value_type.startswith("*mgen.Dict[") else None
+        if dict_types is not None and all(self._infer_type_from_value(key) == dict_types[0] for key in pattern.keys):
+            keys = [self._convert_key(key, dict_types[0]) for key in pattern.keys]
+            conditions.extend(f"{value}.Contains({key})" for key in keys)
+            for key, item in zip(keys, pattern.patterns):
+                self._match_pattern(item, f"{value}.Get({key})", dict_types[1], conditions, bindings, used)
+            if pattern.rest is not None and pattern.rest in used:
+                bindings.append((pattern.rest, f"mgen.DictWithout({', '.join([value, *keys])})", value_type))
+            return
+        keys = [self._convert_expression(key) for key in pattern.keys]
+        conditions.append(f"mgen.MatchMapping({', '.join([value, *keys])})")
+        for key, item in zip(keys, pattern.patterns):
+            self._match_pattern(item, f"mgen.MappingValue({value}, {key})", "interface{}", conditions, bindings, used)
+        if pattern.rest is not None and pattern.rest in used:
+            bindings.append((pattern.rest, f"mgen.MappingRest({', '.join([value, *keys])})", "*mgen.PyDict"))
+
+    def _match_class(
+        self,
+        pattern: ast.MatchClass,
+        value: str,
+        value_type: str,
+        conditions: list[str],
+        bindings: list[tuple[str, str, str]],
+        used: set[str],
+    ) -> None:
+        """Add the checks and captures of a generated class's pattern such as Point(x, y=0).
+
+        Positional subpatterns match the attributes __match_args__ lists, as
+        in Python (a dataclass lists its fields there). Instances of the class
+        or its subclasses always match; other values are tested with
+        mgen.MatchInstance, or against the class's interface when it has
+        subclasses, and their attributes are read through the pointer that
+        mgen.Instance or As{Class}() returns.
+        """
+        class_name = ast.unparse(pattern.cls)
+        info = self.struct_info[class_name]
+        match_args = info["match_args"]
+        if len(pattern.patterns) > len(match_args):
+            raise TypeMappingError(
+                f"TypeError: {class_name}() accepts {len(match_args)} positional "
+                f"sub-pattern{'' if len(match_args) == 1 else 's'} ({len(pattern.patterns)} given)"
+            )
+        ancestor = value_type if value_type in self.struct_info else None
+        while ancestor is not None and ancestor != class_name:
+            ancestor = self.struct_info[ancestor]["base"]
+        interface = self.class_interfaces.get(class_name)
+        if ancestor is not None:
+            instance = value
+        elif value_type in self.struct_info:
+            conditions.append("false")
+            return
+        elif self._interface_class(value_type) == class_name:
+            instance = f"{value}.As{class_name}()"
+        elif interface is not None:
+            conditions.append(f"mgen.InstanceOf[{interface}]({value})")
+            instance = f"{value}.({interface}).As{class_name}()"
+        else:
+            conditions.append(f"mgen.MatchInstance[{class_name}]({value})")
+            instance = f"mgen.Instance[{class_name}]({value})"
+
+        attributes = [*zip(match_args, pattern.patterns), *zip(pattern.kwd_attrs, pattern.kwd_patterns)]
+        for attr, item in attributes:
+            if attr in info["field_types"]:
+                attr_value, attr_type = f"{instance}.{self._to_camel_case(attr)}", info["field_types"][attr]
+            elif attr in info["properties"]:
+                attr_value = f"{instance}.Get{self._to_camel_case(attr)}()"
+                attr_type = info["properties"][attr]["type"]
+            else:
+                # An instance without the attribute does not match
+                conditions.append("false")
+                return
+            self._match_pattern(item, attr_value, attr_type, conditions, bindings, used)
+
+    def _convert_loop(self, stmt: Union[ast.For, ast.While]) -> str:
+        """Convert a for/while loop, including Python's loop else clause.
+
+        The else block runs only when the loop finishes without break, so a
+        loop with else gets a flag that its own break statements set.
+
+        Example:
+            for x in xs:          loopBroke1 := false
+                if x == t:        for _, x := range xs {
+                    break             if (x == t) {
+            else:                     loopBroke1 = true
+                print("none")         break
+                                      }
+                                  }
+                                  if !loopBroke1 {
+                                      mgen.Print("none")
+                                  }
+        """
+        flag = None
+        if stmt.orelse:
+            self.loop_counter += 1
+            flag = f"loopBroke{self.loop_counter}"
+
+        accumulators = {}
+        for name in self._string_accumulator_targets(stmt):
+            self.loop_counter += 1
+            accumulators[name] = f"{name}Acc{self.loop_counter}"
+
+        self.loop_break_flags.append(flag)
+        self.string_accumulators.update(accumulators)
+        try:
+            loop = self._convert_for(stmt) if isinstance(stmt, ast.For) else self._convert_while(stmt)
+        finally:
+            self.loop_break_flags.pop()
+            for name in accumulators:
+                del self.string_accumulators[name]
+
+        if flag is not None:
+            loop = f"    {flag} := false\n{loop}"
+        if accumulators:
+            # Seed each accumulator before the loop and flush it back once the loop exits
+            seeds = "".join(f"    {acc} := mgen.NewStringAccumulator({name})\n" for name, acc in accumulators.items())
+            flushes = "".join(f"\n    {name} = {acc}.String()" for name, acc in accumulators.items())
+            loop = f"{seeds}{loop}{flushes}"
+        if flag is None:
+            return loop
+        else_body = self._convert_statements(stmt.orelse)
+        return f"{loop}\n    if !{flag} {{\n{else_body}\n    }}"
+
+    def _string_accumulator_targets(self, stmt: Union[ast.For, ast.While]) -> list[str]:
+        """Find string variables the loop only extends with `s += piece`.
+
+        Repeated Go string concatenation copies the whole string each time, so
+        these loops use a mgen.StringAccumulator instead. A variable qualifies
+        only if the loop never reads or rebinds it other than through `+=`, and
+        the loop cannot leave the function before the accumulator is flushed.
+        """
+        roots = [*stmt.body, stmt.target if isinstance(stmt, ast.For) else stmt.test]
+        nodes = [node for root in roots for node in ast.walk(root)]
+        if any(isinstance(node, (ast.Return, ast.Yield, ast.YieldFrom, ast.FunctionDef, ast.Lambda)) for node in nodes):
+            return []
+
+        appends = [
+            node.target
+            for node in nodes
+            if isinstance(node, ast.AugAssign)
+            and isinstance(node.op, ast.Add)
+            and isinstance(node.target, ast.Name)
+            and self.variable_types.get(node.target.id) == "string"
+            and node.target.id not in self.string_accumulators
+        ]
+        other_uses = {node.id for node in nodes if isinstance(node, ast.Name) and not any(node is t for t in appends)}
+        targets: list[str] = []
+        for target in appends:
+            if target.id not in other_uses and target.id not in targets:
+                targets.append(target.id)
+        return targets
+
+    def _convert_break(self) -> str:
+        """Convert break, recording it for the enclosing loop's else clause."""
+        flag = self.loop_break_flags[-1] if self.loop_break_flags else None
+        if flag is not None:
+            return f"    {flag} = true\n    break"
+        return "    break"
+
+    def _convert_while(self, stmt: ast.While) -> str:
+        """Convert while loop."""
+        condition = self._convert_condition(stmt.test)
+        body = self._convert_statements(stmt.body)
+        return "    for " + condition + " {\n" + body + "\n    }"
+
+    def _convert_for(self, stmt: ast.For) -> str:
+        """Convert for loop."""
+        if isinstance(stmt.iter, ast.Call) and isinstance(stmt.iter.func, ast.Name) and stmt.iter.func.id == "range":
+            # Range-based for loop
+            range_args = [self._convert_expression(arg) for arg in stmt.iter.args]
+            target_name = stmt.target.id if isinstance(stmt.target, ast.Name) else "i"
+
+            if len(range_args) == 1:
+                # range(n)
+                stop = range_args[0]
+                body = self._convert_statements(stmt.body)
+                return f"    for {target_name} := 0; {target_name} < {stop}; {target_name}++ {{\n{body}\n    }}"
+            elif len(range_args) == 2:
+                # range(start, stop)
+                start, stop = range_args
+                body = self._convert_statements(stmt.body)
+                return f"    for {target_name} := {start}; {target_name} < {stop}; {target_name}++ {{\n{body}\n    }}"
+            elif len(range_args) == 3:
+                # range(start, stop, step)
+                start, stop, step = range_args
+                body = self._convert_statements(stmt.body)
+                return f"    for {target_name} := {start}; {target_name} < {stop}; {target_name} += {step} {{\n{body}\n    }}"
+            else:
+                # Invalid range arguments
+                body = self._convert_statements(stmt.body)
+                return (
+                    f"    for {target_name} := 0; {target_name} < 0; {target_name}++ {{\n{body}\n    }}"  # Empty loop
+                )
+        else:
+            # Iteration over container
+            container_expr = self._convert_expression(stmt.iter)
+            iter_type = self._infer_type_from_value(stmt.iter)
+            if iter_type == "*mgen.PyFile":
+                # for line in f: iterates the file's remaining lines
+                container_expr = f"{container_expr}.Lines()"
+            elif iter_type == "*mgen.PyDict" or iter_type.startswith("*mgen.Dict["):
+                # Iterating a dict yields its keys in insertion order
+                container_expr = f"{container_expr}.Keys()"
+            elif iter_type == "*mgen.PySet" or iter_type.startswith(("*mgen.Set[", "*mgen.Deque[")):
+                container_expr = f"{container_expr}.Items()"
+            elif iter_type in BYTES_TYPES:
+                # Iterating bytes yields the byte values as ints
+                container_expr = f"{container_expr}.Ints()"
+            elif iter_type == "string":
+                # Iterating a str yields one-character strs
+                container_expr = self._iterated_slice(container_expr, iter_type)
+            # Go rejects unused loop variables, so targets the body never reads become _
+            used = {node.id for body_stmt in stmt.body for node in ast.walk(body_stmt) if isinstance(node, ast.Name)}
+            if iterator_type_arg(iter_type) is not None:
+                return self._convert_iterator_loop(stmt, container_expr, iter_type, used)
+            lazy_loop = self._convert_lazy_iteration_loop(stmt, used)
+            if lazy_loop is not None:
+                return lazy_loop
+            pair_fields = self._pair_element_fields(stmt.iter, iter_type)
+            if isinstance(stmt.target, (ast.Tuple, ast.List)) and not (
+                pair_fields is not None
+                and len(stmt.target.elts) == 2
+                and all(isinstance(elt, ast.Name) for elt in stmt.target.elts)
+            ):
+                return self._convert_for_unpacking(stmt, container_expr, iter_type, pair_fields, used)
+            if pair_fields is not None and isinstance(stmt.target, ast.Tuple) and len(stmt.target.elts) == 2:
+                # for i, x in enumerate(xs) / for k, v in d.items(): unpack each pair element
+                item_var, first, second, field_types = pair_fields
+                names = [elt.id if isinstance(elt, ast.Name) and elt.id in used else "_" for elt in stmt.target.elts]
+                for elt, elt_type in zip(stmt.target.elts, field_types):
+                    if isinstance(elt, ast.Name) and elt_type:
+                        self.variable_types[elt.id] = elt_type
+                body = self._convert_statements(stmt.body)
+                unpack = f"    {names[0]}, {names[1]} := {item_var}.{first}, {item_var}.{second}\n"
+                if names == ["_", "_"]:
+                    return f"    for range {container_expr} {{\n{body}\n    }}"
+                return f"    for _, {item_var} := range {container_expr} {{\n{unpack}{body}\n    }}"
             body = self._convert_statements(stmt.body)
+            if isinstance(stmt.target, ast.Name) and stmt.target.id not in used:
+                return f"    for range {container_expr} {{\n{body}\n    }}"
+            target_name = stmt.target.id if isinstance(stmt.target, ast.Name) else "item"
             return f"    for _, {target_name} := range {container_expr} {{\n{body}\n    }}"
 
+    def _convert_iterator_loop(self, stmt: ast.For, container_expr: str, iter_type: str, used: set[str]) -> str:
+        """Convert a for loop over an mgen.Iterator (a generator, iter(xs)), calling Next until it reports false.
+
+        A loop over a generator nothing else holds (a generator expression or
+        a call of a generator function) closes it when the loop can stop
+        early, so a generator it leaves unfinished ends its goroutine: after
+        the loop when it breaks, and with defer when it returns.
+
+        Example:
+            for n in countdown(3):  →  iterator1 := countdown(3)
+                                       for n, ok1 := iterator1.Next(); ok1; n, ok1 = iterator1.Next() {
+        """
+        self.loop_counter += 1
+        iterator, ok = f"iterator{self.loop_counter}", f"ok{self.loop_counter}"
+        item_type = iterator_type_arg(iter_type) or "interface{}"
+        lines: list[str] = []
+        if isinstance(stmt.target, ast.Name):
+            item = stmt.target.id if stmt.target.id in used else "_"
+            self.variable_types[stmt.target.id] = item_type
+        else:
+            item = "item"
+            self._unpack_target(stmt.target, item, item_type, used, lines)
+        body = self._convert_statements(stmt.body)
+        header = f"for {item}, {ok} := {iterator}.Next(); {ok}; {item}, {ok} = {iterator}.Next()"
+        loop = f"    {header} {{\n{''.join(lines)}{body}\n    }}"
+        start = f"    {iterator} := {container_expr}"
+        if not self._is_fresh_generator(stmt.iter):
+            return f"{start}\n{loop}"
+        if any(isinstance(node, ast.Return) for node in self._local_nodes(stmt.body)):
+            return f"{start}\n    defer mgen.CloseIterator({iterator})\n{loop}"
+        if any(isinstance(node, ast.Break) for node in self._local_nodes(stmt.body, loops=False)):
+            return f"{start}\n{loop}\n    mgen.CloseIterator({iterator})"
+        return f"{start}\n{loop}"
+
+    def _is_fresh_generator(self, expr: ast.expr) -> bool:
+        """Report whether expr makes a new generator: a generator expression or a call of a generator function."""
+        if isinstance(expr, ast.GeneratorExp):
+            return True
+        if not (isinstance(expr, ast.Call) and isinstance(expr.func, ast.Name)):
+            return False
+        func = self.function_nodes.get(expr.func.id)
+        return func is not None and self._is_generator(func)
+
+    def _convert_lazy_iteration_loop(self, stmt: ast.For, used: set[str]) -> Optional[str]:
+        """Convert a for loop directly over enumerate() or zip() without building the Pair slice.
+
+        enumerate(xs) ranges over xs itself and zip(xs, ys) walks the sources by
+        index up to the shorter one. zip() sources must be list names the body
+        does not rebind, since the lengths are read on each pass like Python's
+        list iterators. Returns None for other loops, which keep the slice helpers.
+
+        Example:
+            for i, x in enumerate(xs, 1):  →  for i, x := range xs {
+                                                  i += 1
+            for a, b in zip(xs, ys):       →  for index := 0; index < min(len(xs), len(ys)); index++ {
+                                                  a, b := xs[index], ys[index]
+        """
+        iterable = stmt.iter
+        if not (
+            isinstance(iterable, ast.Call)
+            and isinstance(iterable.func, ast.Name)
+            and iterable.func.id in ("enumerate", "zip")
+            and iterable.func.id not in self.function_return_types
+            and iterable.args
+            and not any(isinstance(arg, ast.Starred) for arg in iterable.args)
+            and isinstance(stmt.target, ast.Tuple)
+            and all(isinstance(elt, ast.Name) for elt in stmt.target.elts)
+        ):
+            return None
+        names = [elt.id for elt in stmt.target.elts if isinstance(elt, ast.Name)]
+        source_types = [self._infer_type_from_value(arg) for arg in iterable.args]
+        if iterable.func.id == "enumerate":
+            keywords = {kw.arg: kw.value for kw in iterable.keywords}
+            start_expr = iterable.args[1] if len(iterable.args) > 1 else keywords.get("start")
+            if len(names) != 2 or not source_types[0].startswith("[]"):
+                return None
+            if start_expr is not None and not isinstance(start_expr, (ast.Constant, ast.Name)):
+                # start is evaluated once in Python; only literals and names are safe to repeat
+                return None
+            self.variable_types[names[0]] = "int"
+            self.variable_types[names[1]] = source_types[0][2:]
+            body = self._convert_statements(stmt.body)
+            source = self._convert_expression(iterable.args[0])
+            index, item = (name if name in used else "_" for name in names)
+            if index == "_":
+                header = f"for _, {item} := range {source}" if item != "_" else f"for range {source}"
+                return f"    {header} {{\n{body}\n    }}"
+            start = self._convert_expression(start_expr) if start_expr is not None else "0"
+            header = f"for {index}, {item} := range {source}" if item != "_" else f"for {index} := range {source}"
+            offset = f"    {index} += {start}\n" if start != "0" else ""
+            return f"    {header} {{\n{offset}{body}\n    }}"
+        rebound = {
+            node.id
+            for body_stmt in stmt.body
+            for node in ast.walk(body_stmt)
+            if isinstance(node, ast.Name) and isinstance(node.ctx, ast.Store)
+        }
+        if (
+            len(names) != len(iterable.args)
+            or len(names) not in (2, 3)
+            or not all(isinstance(arg, ast.Name) and arg.id not in rebound for arg in iterable.args)
+            or not all(source_type.startswith("[]") for source_type in source_types)
+        ):
+            return None
+        sources = [arg.id for arg in iterable.args if isinstance(arg, ast.Name)]
+        index = "index"
+        while index in used or index in self.variable_types:
+            self.loop_counter += 1
+            index = f"index{self.loop_counter}"
+        for name, source_type in zip(names, source_types):
+            self.variable_types[name] = source_type[2:]
+        body = self._convert_statements(stmt.body)
+        bound = [(name, f"{source}[{index}]") for name, source in zip(names, sources) if name in used]
+        unpack = ""
+        if bound:
+            unpack = f"    {', '.join(name for name, _ in bound)} := {', '.join(value for _, value in bound)}\n"
+        lengths = ", ".join(f"len({source})" for source in sources)
+        return f"    for {index} := 0; {index} < min({lengths}); {index}++ {{\n{unpack}{body}\n    }}"
+
+    def _convert_for_unpacking(
+        self,
+        stmt: ast.For,
+        container_expr: str,
+        iter_type: str,
+        pair_fields: Optional[tuple[str, str, str, tuple[str, str]]],
+        used: set[str],
+    ) -> str:
+        """Convert a for loop whose target is a nested or starred tuple.
+
+        Each item is unpacked at the top of the body (see _unpack_target):
+            for (a, b), c in rows:  →  for _, item := range rows {
+                                           unpack1 := mgen.UnpackN(item, 2)
+                                           unpack2 := mgen.UnpackN(unpack1[0], 2)
+                                           a := unpack2[0] ...
+        """
+        iterated_type = iterated_slice_type(iter_type)
+        if pair_fields is not None:
+            item_type = {"pair": iterated_type[2:], "tuple": iterated_type[2:], "entry": "mgen.PyDictEntry"}.get(
+                pair_fields[0], f"mgen.KV[{pair_fields[3][0]}, {pair_fields[3][1]}]"
+            )
+        elif iterated_type.startswith("[]"):
+            item_type = iterated_type[2:]
+        elif iter_type.startswith("*mgen.Deque["):
+            item_type = iter_type[len("*mgen.Deque[") : -1]
+        else:
+            item_type = "interface{}"
+        lines: list[str] = []
+        self._unpack_target(stmt.target, "item", item_type, used, lines)
+        body = self._convert_statements(stmt.body)
+        return f"    for _, item := range {container_expr} {{\n{''.join(lines)}{body}\n    }}"
+
+    def _unpack_target(self, target: ast.expr, value: str, value_type: str, used: set[str], lines: list[str]) -> None:
+        """Emit the statements binding a (possibly nested) for target to value.
+
+        Pair, KV, PyDictEntry and Tuple2/Tuple3 values unpack through their typed fields;
+        anything else goes through mgen.UnpackN, or mgen.UnpackStar when one
+        name is starred, which check the item count like Python. Names the
+        loop body never reads are not bound.
+        """
+        if isinstance(target, ast.Name):
+            if target.id in used:
+                self.variable_types[target.id] = value_type
+                lines.append(f"    {target.id} := {value}\n")
+            return
+        if not isinstance(target, (ast.Tuple, ast.List)):
+            raise UnsupportedFeatureError(f"Unsupported for loop target: {ast.unparse(target)}")
+        starred = [i for i, elt in enumerate(target.elts) if isinstance(elt, ast.Starred)]
+        if len(starred) > 1:
+            raise UnsupportedFeatureError(f"multiple starred expressions in assignment: {ast.unparse(target)}")
+        fields = self._record_fields(value_type)
+        if fields is not None and len(target.elts) == len(fields[0]) and not starred:
+            for elt, field, field_type in zip(target.elts, *fields):
+                self._unpack_target(elt, f"{value}.{field}", field_type, used, lines)
+            return
+        if starred:
+            call = f"mgen.UnpackStar({value}, {starred[0]}, {len(target.elts) - starred[0] - 1})"
+        else:
+            call = f"mgen.UnpackN({value}, {len(target.elts)})"
+        if not any(isinstance(node, ast.Name) and node.id in used for node in ast.walk(target)):
+            # Nothing is bound, but the item count is still checked
+            lines.append(f"    {call}\n")
+            return
+        self.loop_counter += 1
+        items = f"unpack{self.loop_counter}"
+        lines.append(f"    {items} := {call}\n")
+        for i, elt in enumerate(target.elts):
+            if isinstance(elt, ast.Starred):
+                self._unpack_target(elt.value, f"{items}[{i}].([]interface{{}})", "[]interface{}", used, lines)
+            else:
+                self._unpack_target(elt, f"{items}[{i}]", "interface{}", used, lines)
+
+    def _record_fields(self, go_type: str) -> Optional[tuple[tuple[str, ...], tuple[str, ...]]]:
+        """Field names and types of the runtime's tuple records and namedtuples, or None for other types."""
+        if go_type in self.namedtuples:
+            return self._namedtuple_fields(go_type)
+        for prefix, first, second in (("mgen.Pair[", "First", "Second"), ("mgen.KV[", "Key", "Value")):
+            if go_type.startswith(prefix) and go_type.endswith("]"):
+                field_types = self._split_type_args(go_type[len(prefix) : -1])
+                if field_types is not None:
+                    return (first, second), field_types
+        if go_type == "mgen.PyDictEntry":
+            return ("Key", "Value"), ("interface{}", "interface{}")
+        if go_type.startswith("mgen.Triple[") and go_type.endswith("]"):
+            return TUPLE_FIELDS, tuple(self._split_type_list(go_type[len("mgen.Triple[") : -1]))
+        item_types = tuple_type_args(go_type)
+        if item_types is not None:
+            return TUPLE_FIELDS[: len(item_types)], tuple(item_types)
+        return None
+
+    def _pair_element_fields(
+        self, iter_expr: ast.expr, iter_type: str
+    ) -> Optional[tuple[str, str, str, tuple[str, str]]]:
+        """Describe the two-element records a for loop iterates, for tuple targets.
+
+        Returns (loop variable, first field, second field, field types):
+        enumerate()/zip() yield mgen.Pair, PyDict.Items() yields mgen.PyDictEntry
+        and Dict.Items() (dict.items()) yields mgen.KV; dicts and sets keyed by
+        pairs yield mgen.Tuple2. Unknown field types are "".
+        """
+        if iter_type.startswith("[]mgen.Pair[") and iter_type.endswith("]"):
+            field_types = self._split_type_args(iter_type[len("[]mgen.Pair[") : -1])
+            return "pair", "First", "Second", field_types or ("", "")
+        item_types = tuple_type_args(iterated_slice_type(iter_type)[2:])
+        if item_types is not None and len(item_types) == 2:
+            return "tuple", "First", "Second", (item_types[0], item_types[1])
+        if iter_type.startswith("[]mgen.KV[") and iter_type.endswith("]"):
+            field_types = self._split_type_args(iter_type[len("[]mgen.KV[") : -1])
+            return "kv", "Key", "Value", field_types or ("", "")
+        if isinstance(iter_expr, ast.Call) and isinstance(iter_expr.func, ast.Attribute) and iter_expr.func.attr == "items":
+            if self._infer_type_from_value(iter_expr.func.value) == "*mgen.PyDict":
+                return "entry", "Key", "Value", ("interface{}", "interface{}")
+        return None
+
+    def _split_type_args(self, type_args: str) -> Optional[tuple[str, str]]:
+        """Split two Go type arguments "A, B" at the top-level comma."""
+        depth = 0
+        for i, ch in enumerate(type_args):
+            if ch in "[{":
+                depth += 1
+            elif ch in "]}":
+                depth -= 1
+            elif ch == "," and depth == 0:
+                return type_args[:i].strip(), type_args[i + 1 :].strip()
+        return None
+
+    def _split_type_list(self, type_args: str) -> list[str]:
+        """Split any number of Go type arguments "A, B, C" at their top-level commas."""
+        items = []
+        split = self._split_type_args(type_args)
+        while split is not None:
+            items.append(split[0])
+            type_args = split[1]
+            split = self._split_type_args(type_args)
+        return [*items, type_args.strip()]
+
+    def _convert_yield(self, expr: Union[ast.Yield, ast.YieldFrom]) -> str:
+        """Convert a yield statement to a call of the yield callback the generator's body receives.
+
+        Example:
+            yield n * 2    →  yield((n * 2))
+            yield from xs  →  for _, item := range xs {
+                                  yield(item)
+                              }
+        """
+        if self.generator_item_type is None:
+            raise UnsupportedFeatureError("yield is only supported in functions, not in methods")
+        if isinstance(expr, ast.YieldFrom):
+            # yield from it: a for loop yielding each item
+            item = ast.Name(id="item", ctx=ast.Load())
+            body: list[ast.stmt] = [ast.Expr(value=ast.Yield(value=item))]
+            return self._convert_for(
+                ast.For(target=ast.Name(id="item", ctx=ast.Store()), iter=expr.value, body=body, orelse=[])
+            )
+        if expr.value is None:
+            raise UnsupportedFeatureError("yield without a value is not supported")
+        return f"    yield({self._convert_expression(expr.value)})"
+
     def _convert_expression_statement(self, stmt: ast.Expr) -> str:
         """Convert expression statement."""
         # Skip docstrings (string constants used as statements)
         if isinstance(stmt.value, ast.Constant) and isinstance(stmt.value.value, str):
             return f"    // {stmt.value.value}"
+        if isinstance(stmt.value, (ast.Yield, ast.YieldFrom)):
+            return self._convert_yield(stmt.value)
+        item_append = self._convert_dict_item_append(stmt.value)
+        if item_append is not None:
+            return item_append
         expr = self._convert_expression(stmt.value)
 
         # Handle append operations - convert to assignment
@@ -1260,11 +6116,55 @@ class MGenPythonToGoConverter:
 
         return f"    {expr}"
 
+    def _expect_dict_type(self, value: ast.expr, target_type: str) -> None:
+        """Record the dict type a defaultdict(), Counter() or OrderedDict() call initializes, before it is converted."""
+        if (
+            isinstance(value, ast.Call)
+            and (
+                isinstance(value.func, ast.Name) and value.func.id in ("defaultdict", "Counter")
+                or self._is_ordered_dict_call(value)
+            )
+            and dict_type_args(target_type) is not None
+        ):
+            self.dict_values[id(value)] = target_type
+
+    def _convert_dict_item_append(self, expr: ast.expr) -> Optional[str]:
+        """Convert d[k].append(x) on a typed dict of lists, storing the grown slice back.
+
+        Example:
+            groups[k].append(x)  →  groups.Set(k, append(groups.Get(k), x))
+        """
+        if not (
+            isinstance(expr, ast.Call)
+            and isinstance(expr.func, ast.Attribute)
+            and expr.func.attr == "append"
+            and len(expr.args) == 1
+            and isinstance(expr.func.value, ast.Subscript)
+            and not isinstance(expr.func.value.slice, ast.Slice)
+        ):
+            return None
+        item = expr.func.value
+        dict_types = dict_type_args(self._infer_type_from_value(item.value))
+        if dict_types is None or not dict_types[1].startswith("[]"):
+            return None
+        container = self._convert_expression(item.value)
+        key = self._convert_key(item.slice, dict_types[0])
+        value = self._convert_expression(expr.args[0])
+        return f"    {container}.Set({key}, append({container}.Get({key}), {value}))"
+
     def _convert_expression(self, expr: ast.expr) -> str:
         """Convert Python expression to Go."""
         if isinstance(expr, ast.Constant):
             return self._convert_constant(expr)
         elif isinstance(expr, ast.Name):
+            if self._is_self(expr):
+                return "obj"
+            if self._is_optional_type(self.variable_types.get(expr.id, "")):
+                # Optional[T] values are *T; using one as a value unwraps it (None raises TypeError)
+                return f"mgen.Unwrap({expr.id})"
+            if expr.id in self.reference_lists:
+                # A list held by pointer (see _reference_lists)
+                return f"(*{expr.id})"
             return expr.id
         elif isinstance(expr, ast.BinOp):
             return self._convert_binop(expr)
@@ -1273,7 +6173,7 @@ class MGenPythonToGoConverter:
         elif isinstance(expr, ast.Compare):
             return self._convert_compare(expr)
         elif isinstance(expr, ast.Call):
-            return self._convert_call(expr)
+            return self._convert_stdin_read(expr) or self._convert_call(expr)
         elif isinstance(expr, ast.Attribute):
             return self._convert_attribute(expr)
         elif isinstance(expr, ast.List):
@@ -1283,45 +6183,81 @@ class MGenPythonToGoConverter:
         elif isinstance(expr, ast.Set):
             return self._convert_set_literal(expr)
         elif isinstance(expr, ast.ListComp):
-            return self._convert_list_comprehension(expr)
+            return self._convert_stdin_read(expr) or self._convert_list_comprehension(expr)
         elif isinstance(expr, ast.DictComp):
             return self._convert_dict_comprehension(expr)
         elif isinstance(expr, ast.SetComp):
             return self._convert_set_comprehension(expr)
+        elif isinstance(expr, ast.GeneratorExp):
+            return self._convert_comprehension_loops(expr)
         elif isinstance(expr, ast.Subscript):
             return self._convert_subscript(expr)
         elif isinstance(expr, ast.JoinedStr):
             return self._convert_f_string(expr)
+        elif isinstance(expr, ast.Tuple):
+            # Tuples are heterogeneous, so they hold their items as interface{} values
+            elements = ", ".join(self._convert_expression(elt) for elt in expr.elts)
+            return f"mgen.PyTuple{{{elements}}}"
+        elif isinstance(expr, ast.Lambda):
+            expected = self._split_func_type(self.lambda_types.get(id(expr), ""))
+            if expected is None:
+                return self._convert_lambda(expr)
+            return self._convert_lambda(expr, *expected)
         else:
             raise UnsupportedFeatureError(f"Unsupported expression type: {type(expr).__name__}")
 
     def _convert_constant(self, expr: ast.Constant) -> str:
         """Convert constant values."""
         if isinstance(expr.value, str):
-            return f'"{expr.value}"'
+            # JSON string escapes (\n, \", \\, \uXXXX) are valid Go string literal escapes
+            return json.dumps(expr.value, ensure_ascii=False)
+        elif isinstance(expr.value, bytes):
+            # b"..." keeps printable ASCII and spells every other byte as a \x escape
+            chars = "".join(
+                chr(byte) if 0x20 <= byte < 0x7F and chr(byte) not in '"\\' else f"\\x{byte:02x}"
+                for byte in expr.value
+            )
+            return f'mgen.PyBytes("{chars}")'
         elif isinstance(expr.value, bool):
             return "true" if expr.value else "false"
         elif expr.value is None:
             return "nil"
+        elif self._is_big_int_expr(expr):
+            return f'mgen.ParsePyInt("{expr.value}")'
         elif isinstance(expr.value, float):
-            # Convert whole floats to ints for cleaner code (1.0 -> 1)
-            if expr.value.is_integer():
-                return str(int(expr.value))
-            return str(expr.value)
+            # Python's float repr is a Go float literal (2.0, 1e+22), which keeps a whole float a float64
+            return repr(expr.value)
         else:
             return str(expr.value)
 
     def _convert_binop(self, expr: ast.BinOp) -> str:
         """Convert binary operations."""
-        left = self._convert_expression(expr.left)
-        right = self._convert_expression(expr.right)
+        left = self._coerce_bool_operand(expr.left, self._convert_expression(expr.left), expr.op)
+        right = self._coerce_bool_operand(expr.right, self._convert_expression(expr.right), expr.op)
 
         # Handle Go-specific operators
-        if isinstance(expr.op, ast.Pow):
-            return f"math.Pow({left}, {right})"
-        elif isinstance(expr.op, ast.FloorDiv):
-            # Go integer division is already floor division
-            return f"({left} / {right})"
+        operator_call = self._convert_operator_binop(expr, left, right)
+        if operator_call is not None:
+            return operator_call
+        big_int = self._convert_big_int_binop(expr, left, right)
+        if big_int is not None:
+            return big_int
+        elif isinstance(expr.op, ast.Pow):
+            return self._convert_pow(expr, left, right)
+        elif self._is_py2_int_division(expr):
+            return f"mgen.FloorDivInt({left}, {right})"
+        elif self._is_int_true_division(expr):
+            return f"mgen.TrueDivInt({left}, {right})"
+        elif self._is_percent_format(expr):
+            return f"mgen.PercentFormat({left}, {self._percent_format_args(expr.right, right)})"
+        elif isinstance(expr.op, (ast.FloorDiv, ast.Mod)):
+            return self._convert_floored_operator(expr, left, right)
+        elif self._bytes_operand(expr) is not None:
+            return self._convert_bytes_operator(expr, left, right)
+        elif self._list_operand(expr) is not None:
+            return self._convert_list_operator(expr, left, right)
+        elif type(expr.op) in SET_OPERATOR_METHODS and set_type_arg(self._infer_type_from_value(expr.left)) is not None:
+            return f"{left}.{SET_OPERATOR_METHODS[type(expr.op)]}({right})"
 
         # Use standard operator mapping from converter_utils
         op = get_standard_binary_operator(expr.op)
@@ -1329,9 +6265,252 @@ class MGenPythonToGoConverter:
             op = "/*UNKNOWN_OP*/"
         return f"({left} {op} {right})"
 
+    def _bytes_operand(self, expr: ast.BinOp) -> Optional[str]:
+        """Return "left" or "right" for the bytes operand of b + other, b * n or n * b, else None."""
+        if not isinstance(expr.op, (ast.Add, ast.Mult)):
+            return None
+        if self._infer_type_from_value(expr.left) in BYTES_TYPES:
+            return "left"
+        if isinstance(expr.op, ast.Mult) and self._infer_type_from_value(expr.right) in BYTES_TYPES:
+            return "right"
+        return None
+
+    def _convert_bytes_operator(self, expr: ast.BinOp, left: str, right: str) -> str:
+        """Convert + and * on bytes or a bytearray, which Go slices lack; the result has the bytes operand's type.
+
+        Example:
+            data + b"!"  →  data.Concat(mgen.PyBytes("!"))
+            3 * buf      →  buf.Repeat(3)
+        """
+        if isinstance(expr.op, ast.Add):
+            return f"{left}.Concat({right})"
+        if self._bytes_operand(expr) == "right":
+            left, right = right, left
+        return f"{left}.Repeat({right})"
+
+    def _list_operand(self, expr: ast.BinOp) -> Optional[str]:
+        """Return "left" or "right" for the list operand of xs + ys, xs * n or n * xs, else None.
+
+        Concatenation needs both lists to have one Go type; anything else keeps
+        the dynamic mgen.BinOp.
+        """
+        left_type, right_type = (self._infer_type_from_value(side) for side in (expr.left, expr.right))
+        if isinstance(expr.op, ast.Add) and left_type.startswith("[]") and left_type == right_type:
+            return "left"
+        if isinstance(expr.op, ast.Mult) and left_type.startswith("[]") and right_type in ("int", "bool"):
+            return "left"
+        if isinstance(expr.op, ast.Mult) and right_type.startswith("[]") and left_type in ("int", "bool"):
+            return "right"
+        return None
+
+    def _convert_list_operator(self, expr: ast.BinOp, left: str, right: str) -> str:
+        """Convert + and * on lists, which Go slices lack, to new slices of the list's type.
+
+        Example:
+            xs + ys  →  mgen.ConcatSlices(xs, ys)
+            3 * xs   →  mgen.RepeatSlice(xs, 3)
+        """
+        if isinstance(expr.op, ast.Add):
+            return f"mgen.ConcatSlices({left}, {right})"
+        if self._list_operand(expr) == "right":
+            left, right = right, left
+        return f"mgen.RepeatSlice({left}, {right})"
+
+    def _convert_floored_operator(self, expr: ast.BinOp, left: str, right: str) -> str:
+        """Convert // or % on numbers to the runtime helpers that floor as Python does.
+
+        Go's / truncates toward zero and its % takes the sign of the dividend
+        (-7 / 2 == -3, -7 % 2 == -1), where Python floors (-7 // 2 == -4,
+        -7 % 2 == 1). The helpers take two operands of one type, so an int
+        beside a float is widened to float64 as Python does.
+
+        Example:
+            a // b  →  mgen.FloorDiv(a, b)
+            x % n   →  mgen.PyMod(x, float64(n))   (x: float, n: int)
+        """
+        operand_types = [self._infer_type_from_value(operand) for operand in (expr.left, expr.right)]
+        if "float64" in operand_types:
+            if operand_types[0] in ("int", "bool"):
+                left = f"float64({left})"
+            if operand_types[1] in ("int", "bool"):
+                right = f"float64({right})"
+        helper = "FloorDiv" if isinstance(expr.op, ast.FloorDiv) else "PyMod"
+        return f"mgen.{helper}({left}, {right})"
+
+    def _is_big_int_expr(self, expr: ast.expr) -> bool:
+        """Report whether expr is an int that may outgrow int64, an mgen.PyInt when int_precision is "auto".
+
+        That is an int literal beyond int64, an int power or shift that may
+        leave int64 (see outgrows_int64), and arithmetic or int() on such a
+        value.
+        """
+        if self.int_precision != "auto":
+            return False
+        if isinstance(expr, ast.Constant):
+            return isinstance(expr.value, int) and not isinstance(expr.value, bool) and not fits_int64(expr.value)
+        if isinstance(expr, ast.UnaryOp):
+            return isinstance(expr.op, (ast.USub, ast.UAdd, ast.Invert)) and self._int_operand_type(
+                expr.operand
+            ) == BIG_INT_TYPE
+        if isinstance(expr, ast.Call):
+            return (
+                isinstance(expr.func, ast.Name)
+                and expr.func.id == "int"
+                and len(expr.args) == 1
+                and self._infer_type_from_value(expr.args[0]) == BIG_INT_TYPE
+            )
+        if not isinstance(expr, ast.BinOp) or isinstance(expr.op, ast.Div):
+            return False
+        operand_types = {self._int_operand_type(expr.left), self._int_operand_type(expr.right)}
+        if not operand_types <= {"int", "bool", BIG_INT_TYPE}:
+            return False
+        return BIG_INT_TYPE in operand_types or outgrows_int64(expr)
+
+    def _int_operand_type(self, expr: ast.expr) -> str:
+        """Infer the type of an arithmetic operand, seeing -1 and other constant expressions as ints."""
+        if constant_int_value(expr) is not None and not self._is_big_int_expr(expr):
+            return "int"
+        return self._infer_type_from_value(expr)
+
+    def _convert_pow(self, expr: ast.BinOp, left: str, right: str) -> str:
+        """Convert a ** b, which Go has no operator for, by operand type.
+
+        Example:
+            n ** 2    →  mgen.PowInt(n, 2)
+            x ** n    →  math.Pow(x, float64(n))   (x: float)
+            v ** 2    →  mgen.BinOp("**", v, 2)    (v: untyped)
+        """
+        operand_types = [self._arithmetic_type(expr.left), self._arithmetic_type(expr.right)]
+        if set(operand_types) <= {"int", "bool"}:
+            return f"mgen.PowInt({left}, {right})"
+        if set(operand_types) <= {"int", "bool", "float64"}:
+            left, right = (
+                code if operand_type == "float64" or isinstance(operand, ast.Constant) else f"float64({code})"
+                for operand, code, operand_type in zip((expr.left, expr.right), (left, right), operand_types)
+            )
+            self.stdlib_imports.add("math")
+            return f"math.Pow({left}, {right})"
+        return f'mgen.BinOp("**", {left}, {right})'
+
+    def _convert_big_int_binop(self, expr: ast.BinOp, left: str, right: str) -> Optional[str]:
+        """Convert arithmetic on ints that may outgrow int64 to mgen.PyInt methods.
+
+        Returns None unless int_precision is "auto" and expr is such an int
+        (or true division of one). Native int operands are converted with
+        mgen.NewPyInt, and an int power of constants that fits in int64 is
+        folded, as Go has no int power operator.
+
+        Example:
+            result * i  →  result.Mul(mgen.NewPyInt(i))   (result: may outgrow int64)
+            2 ** n      →  mgen.NewPyInt(2).Pow(mgen.NewPyInt(n))
+            x << 3      →  x.Lsh(3)
+            2 ** 10     →  1024
+        """
+        if self.int_precision != "auto":
+            return None
+        value = constant_int_value(expr)
+        if isinstance(expr.op, ast.Pow) and value is not None and fits_int64(value):
+            return str(value)
+        operand_types = [self._int_operand_type(expr.left), self._int_operand_type(expr.right)]
+        big_division = (
+            isinstance(expr.op, ast.Div)
+            and BIG_INT_TYPE in operand_types
+            and set(operand_types) <= {"int", "bool", BIG_INT_TYPE}
+        )
+        if not (big_division or self._is_big_int_expr(expr)):
+            return None
+        if operand_types[0] != BIG_INT_TYPE:
+            left = f"mgen.NewPyInt({left})"
+        if isinstance(expr.op, (ast.LShift, ast.RShift)):
+            count = f"{right}.Int()" if operand_types[1] == BIG_INT_TYPE else right
+            return f"{left}.{BIG_INT_METHODS[type(expr.op)]}({count})"
+        if operand_types[1] != BIG_INT_TYPE:
+            right = f"mgen.NewPyInt({right})"
+        return f"{left}.{BIG_INT_METHODS[type(expr.op)]}({right})"
+
+    def _coerce_int_precision(self, target_type: str, node: ast.expr, code: str) -> str:
+        """Convert an int to the representation target_type expects when int_precision is "auto".
+
+        A native int stored where an mgen.PyInt is expected is converted with
+        mgen.NewPyInt, and an mgen.PyInt stored where an int is expected with
+        its Int method, which raises OverflowError when it does not fit.
+        """
+        if self.int_precision != "auto" or target_type not in ("int", BIG_INT_TYPE):
+            return code
+        value_type = self._int_operand_type(node)
+        if target_type == BIG_INT_TYPE and value_type == "bool":
+            return f"mgen.NewPyInt(mgen.BoolToInt({code}))"
+        if target_type == BIG_INT_TYPE and value_type != BIG_INT_TYPE:
+            return f"mgen.NewPyInt({code})"
+        if target_type == "int" and value_type == BIG_INT_TYPE:
+            return f"{code}.Int()"
+        return code
+
+    def _is_py2_int_division(self, expr: ast.BinOp) -> bool:
+        """Report whether expr is a / b on two ints in Python 2 mode, where it floors.
+
+        Python 2 rounds the quotient toward negative infinity (-7 / 2 == -4),
+        so it is lowered to mgen.FloorDivInt rather than Go's truncating /.
+        """
+        return (
+            self.python_version == 2
+            and isinstance(expr.op, ast.Div)
+            and all(self._int_operand(operand) for operand in (expr.left, expr.right))
+        )
+
+    def _is_int_true_division(self, expr: ast.BinOp) -> bool:
+        """Report whether expr is a / b on two ints in Python 3 mode, which gives a float.
+
+        Go's / on ints truncates (1 / 3 == 0), so it is lowered to
+        mgen.TrueDivInt, which divides as float64 (1 / 3 == 0.3333333333333333).
+        """
+        return (
+            self.python_version == 3
+            and isinstance(expr.op, ast.Div)
+            and all(self._int_operand(operand) for operand in (expr.left, expr.right))
+        )
+
+    def _is_percent_format(self, expr: ast.BinOp) -> bool:
+        """Report whether expr is printf-style formatting (str % args)."""
+        return isinstance(expr.op, ast.Mod) and self._infer_type_from_value(expr.left) == "string"
+
+    def _percent_format_args(self, node: ast.expr, code: str) -> str:
+        """Return the argument operand of str % args for mgen.PercentFormat.
+
+        The runtime treats []interface{} as a tuple of arguments, so a list
+        display, which may share that representation, is wrapped as the single
+        value it is in Python ("%s" % [1, 2] prints the list).
+        """
+        if isinstance(node, (ast.List, ast.ListComp)):
+            return f"[]interface{{}}{{{code}}}"
+        return code
+
+    def _int_operand(self, expr: ast.expr) -> bool:
+        """Report whether an arithmetic operand is an int (or bool), seeing through -x and +x."""
+        while isinstance(expr, ast.UnaryOp) and isinstance(expr.op, (ast.USub, ast.UAdd)):
+            expr = expr.operand
+        return self._infer_type_from_value(expr) in ("int", "bool")
+
+    def _coerce_bool_operand(self, node: ast.expr, code: str, op: ast.operator) -> str:
+        """Convert a bool operand of an arithmetic operator to int (True + True == 2)."""
+        if isinstance(op, (ast.BitAnd, ast.BitOr, ast.BitXor)):
+            return code
+        if self._infer_type_from_value(node) == "bool":
+            return f"mgen.BoolToInt({code})"
+        return code
+
     def _convert_unaryop(self, expr: ast.UnaryOp) -> str:
         """Convert unary operations."""
         operand = self._convert_expression(expr.operand)
+        if isinstance(expr.op, ast.Not) and self._is_sized_container(self._infer_type_from_value(expr.operand)):
+            return f"({operand}.Len() == 0)"
+        if self._is_big_int_expr(expr):
+            big_int_methods = {ast.USub: f"{operand}.Neg()", ast.UAdd: operand, ast.Invert: f"{operand}.Invert()"}
+            return big_int_methods[type(expr.op)]
+
+        if isinstance(expr.op, ast.USub) and self._is_dynamic_value(expr.operand):
+            # -x on an untyped value follows Python's rules for whatever it holds
+            return f'mgen.BinOp("-", 0, {operand})'
 
         op_map = {ast.UAdd: "+", ast.USub: "-", ast.Not: "!", ast.Invert: "^"}
 
@@ -1340,8 +6519,16 @@ class MGenPythonToGoConverter:
 
     def _convert_compare(self, expr: ast.Compare) -> str:
         """Convert comparison operations."""
-        left = self._convert_expression(expr.left)
+        if any(isinstance(op, (ast.Is, ast.IsNot)) for op in expr.ops):
+            # Identity checks compare Optional[T] pointers against nil without unwrapping
+            left = self._convert_optional_value(expr.left)
+        else:
+            left = self._convert_expression(expr.left)
+        operator_call = self._convert_operator_compare(expr, left, self._convert_expression)
+        if operator_call is not None:
+            return operator_call
         result = left
+        left_node = expr.left
 
         for op, comp in zip(expr.ops, expr.comparators):
             # Use standard comparison operator mapping from converter_utils
@@ -1357,162 +6544,2004 @@ class MGenPythonToGoConverter:
                     op_str = "!="
                     comp_expr = self._convert_expression(comp)
                     result = f"({result} {op_str} {comp_expr})"
-                elif isinstance(op, ast.In):
-                    # Use map membership check with comma-ok idiom
-                    comp_expr = self._convert_expression(comp)
-                    result = f"func() bool {{ _, ok := {comp_expr}[{result}]; return ok }}()"
-                elif isinstance(op, ast.NotIn):
-                    comp_expr = self._convert_expression(comp)
-                    result = f"func() bool {{ _, ok := {comp_expr}[{result}]; return !ok }}()"
+                elif isinstance(op, (ast.In, ast.NotIn)):
+                    result = self._convert_membership(
+                        op, left_node, result, comp, self._convert_expression, left_node is expr.left
+                    )
                 else:
                     op_str = "/*UNKNOWN_OP*/"
                     comp_expr = self._convert_expression(comp)
                     result = f"({result} {op_str} {comp_expr})"
+            elif all(self._infer_type_from_value(side) in BYTES_TYPES for side in (left_node, comp)):
+                # bytes and bytearray order by their contents: a < b -> a.Cmp(b) < 0
+                result = f"({result}.Cmp({self._convert_expression(comp)}) {op_str} 0)"
+            elif self._mixes_bool_and_number(left_node, comp) or self._compares_sequences(left_node, comp):
+                # Go has no bool/number comparison (Python compares True as 1), slices have
+                # no operators at all and == on dicts would compare pointers, so tuples,
+                # lists, dicts and sets compare by their items at runtime
+                comp_expr = self._convert_expression(comp)
+                result = f'mgen.Compare("{op_str}", {result}, {comp_expr})'
+            elif self._compares_big_ints(left_node, comp):
+                # mgen.PyInt values order through Cmp: a < b -> a.Cmp(b) < 0
+                left_code = self._coerce_int_precision(BIG_INT_TYPE, left_node, result)
+                comp_expr = self._coerce_int_precision(BIG_INT_TYPE, comp, self._convert_expression(comp))
+                result = f"({left_code}.Cmp({comp_expr}) {op_str} 0)"
             else:
                 comp_expr = self._convert_expression(comp)
                 result = f"({result} {op_str} {comp_expr})"
+            left_node = comp
 
         return result
 
+    def _convert_membership(
+        self,
+        op: ast.cmpop,
+        item: ast.expr,
+        item_code: str,
+        container: ast.expr,
+        convert: Callable[[ast.expr], str],
+        convert_key: bool = True,
+    ) -> str:
+        """Convert item in container (or not in) to the membership test for the container's type.
+
+        convert_key is False when item_code is the result of an earlier link of a
+        chained comparison rather than item itself.
+        """
+        negate = "!" if isinstance(op, ast.NotIn) else ""
+        container_code = convert(container)
+        if convert_key:
+            # A class defining __contains__ answers for itself: x in bag -> bag.PyContains(x)
+            operator_call = self._convert_operator_call(container, container_code, "__contains__", [(item, item_code)])
+            if operator_call is not None:
+                return f"{negate}{operator_call}"
+        container_type = self._infer_type_from_value(container)
+        item_type = self._infer_type_from_value(item)
+        if container_type in ("*mgen.PyDict", "*mgen.PySet") or self._is_key_of(item, container):
+            # Value-hashed containers (tuple keys) look members up by HashKey, and
+            # typed dicts and sets look up a key of their key type directly
+            key_type = self._container_key_type(container)
+            if key_type is not None and convert_key:
+                item_code = self._convert_key(item, key_type, convert)
+            return f"{negate}{container_code}.Contains({item_code})"
+        if container_type == "string" and item_type == "string":
+            return f"{negate}mgen.StrOps.Contains({container_code}, {item_code})"
+        if self._is_range_call(container) and item_type == "int":
+            # range() membership is arithmetic rather than a scan of its values
+            return f"{negate}{container_code}.Contains({item_code})"
+        if container_type.startswith("[]") and container_type[2:] == item_type and item_type in SCALAR_ITEM_TYPES:
+            # Slices of the item's own scalar type compare with == without boxing each element
+            return f"{negate}mgen.SliceContains({container_code}, {item_code})"
+        # Anything else searches its items with Python's ==, so 1 in [True] holds
+        return f"{negate}mgen.Contains({container_code}, {item_code})"
+
+    def _is_range_call(self, expr: ast.expr) -> bool:
+        """Report whether expr is a call of the range() builtin."""
+        return (
+            isinstance(expr, ast.Call)
+            and isinstance(expr.func, ast.Name)
+            and expr.func.id == "range"
+            and "range" not in self.function_return_types
+        )
+
+    def _compares_big_ints(self, left: ast.expr, right: ast.expr) -> bool:
+        """Report whether a comparison has an mgen.PyInt on one side and an int on the other, or on both."""
+        if self.int_precision != "auto":
+            return False
+        types = {self._int_operand_type(left), self._int_operand_type(right)}
+        return BIG_INT_TYPE in types and types <= {"int", "bool", BIG_INT_TYPE}
+
+    def _mixes_bool_and_number(self, left: ast.expr, right: ast.expr) -> bool:
+        """Report whether a comparison has a bool on one side and an int or float on the other."""
+        types = {self._infer_type_from_value(left), self._infer_type_from_value(right)}
+        return "bool" in types and bool(types & {"int", "float64"})
+
+    def _compares_sequences(self, left: ast.expr, right: ast.expr) -> bool:
+        """Report whether a comparison has a tuple, list (a Go slice), dict, set or bytes on either side."""
+        return any(
+            isinstance(side, ast.Tuple)
+            or self._infer_type_from_value(side).startswith(("[]", "*mgen.Dict[", "*mgen.Set["))
+            or self._infer_type_from_value(side) in BYTES_TYPES
+            for side in (left, right)
+        )
+
+    def _is_key_of(self, key: ast.expr, container: ast.expr) -> bool:
+        """Report whether container is a typed dict or set that can look key up.
+
+        The key must have the key type, or be a tuple that _convert_key turns
+        into the container's Tuple2/Tuple3.
+        """
+        key_type = self._container_key_type(container)
+        if key_type is None:
+            return False
+        inferred = self._infer_type_from_value(key)
+        if tuple_type_args(key_type) is not None and inferred in ("[]interface{}", "interface{}"):
+            return True
+        return key_type in (inferred, "interface{}")
+
+    def _container_key_type(self, container: ast.expr) -> Optional[str]:
+        """Return the key type of a typed dict or the member type of a typed set, or None."""
+        container_type = self._infer_type_from_value(container)
+        dict_types = dict_type_args(container_type)
+        return dict_types[0] if dict_types is not None else set_type_arg(container_type)
+
+    def _convert_key(
+        self, key: ast.expr, key_type: str, convert: Optional[Callable[[ast.expr], str]] = None
+    ) -> str:
+        """Convert a dict key or set member of a container keyed by key_type.
+
+        Tuple displays become the container's Tuple2/Tuple3 struct, and other
+        tuple values are converted with mgen.ToTuple2/ToTuple3, which checks
+        their items; keys of any other type are converted as they are.
+
+        Example:
+            d[(x, 1)]  →  d.Get(mgen.Tuple2[int, int]{First: x, Second: 1})
+            d[pos]     →  d.Get(mgen.ToTuple2[int, int](pos))
+        """
+        convert = convert or self._convert_expression
+        item_types = tuple_type_args(key_type)
+        if item_types is None or self._infer_type_from_value(key) == key_type:
+            return convert(key)
+        if isinstance(key, ast.Tuple) and len(key.elts) == len(item_types):
+            fields = []
+            for field, item, item_type in zip(TUPLE_FIELDS, key.elts, item_types):
+                item_expr = convert(item)
+                if item_type == "float64" and self._infer_type_from_value(item) == "int":
+                    # An int variable is widened; 1 and 1.0 are the same key in Python
+                    item_expr = f"float64({item_expr})"
+                fields.append(f"{field}: {item_expr}")
+            return f"{key_type}{{{', '.join(fields)}}}"
+        return f"mgen.ToTuple{len(item_types)}[{', '.join(item_types)}]({convert(key)})"
+
     def _convert_call(self, expr: ast.Call) -> str:
         """Convert function calls."""
+        if self._is_ordered_dict_call(expr):
+            return self._convert_ordered_dict_call(expr)
         if isinstance(expr.func, ast.Name):
             func_name = expr.func.id
+            if func_name == "print":
+                # print() converts its own arguments, which may unpack iterables (*xs)
+                self._check_builtin_keywords(func_name, expr)
+                return self._convert_print_call(expr, self._convert_expression)
+            if self._binds_arguments(func_name, expr):
+                return self._convert_function_call(func_name, expr, self._convert_expression)
+            # Lambdas passed to a Callable parameter take the parameter's func type
+            expected_types = self.function_param_types.get(func_name) or (
+                self._split_func_type(self.variable_types.get(func_name, "")) or ([], "")
+            )[0]
+            if func_name in self.generic_functions:
+                # A lambda passed to a generic function takes the types the other arguments bind
+                bindings = self._type_arguments(func_name, expr)
+                expected_types = [substitute_type_params(param_type, bindings) for param_type in expected_types]
+            for arg, expected_type in zip(expr.args, expected_types):
+                self._expect_func_type(arg, expected_type)
             args = [self._convert_expression(arg) for arg in expr.args]
+            for index, (arg, expected_type) in enumerate(zip(expr.args, expected_types)):
+                args[index] = self._coerce_int_precision(expected_type, arg, args[index])
+
+            self._check_builtin_keywords(func_name, expr)
 
             # Handle empty container constructors
             if func_name == "list" and len(args) == 0:
                 # list() with no args -> []int{} (default to int)
                 return "[]int{}"
             elif func_name == "dict" and len(args) == 0:
-                # dict() with no args -> make(map[int]int) (default to int keys/values)
-                return "make(map[int]int)"
+                # dict() with no args -> an empty dict with the default int keys/values
+                return self._get_default_value(go_dict_type("int", "int"))
             elif func_name == "set" and len(args) == 0:
-                # set() with no args -> make(map[int]bool) (default to int keys)
-                return "make(map[int]bool)"
+                # set() with no args -> an empty set with the default int members
+                return self._get_default_value(go_set_type("int"))
+            elif func_name in ("list", "tuple", "set", "dict") and len(args) == 1:
+                return self._convert_container_constructor(func_name, expr.args[0], args[0])
+            elif func_name == "deque" and func_name not in self.function_return_types:
+                return self._convert_deque_call(expr, args)
+            elif func_name == "defaultdict" and not self._is_user_callable(func_name):
+                return self._convert_defaultdict_call(expr)
+            elif func_name == "Counter" and not self._is_user_callable(func_name):
+                return self._convert_counter_call(expr, args)
+            elif func_name in ("bytes", "bytearray") and not self._is_user_callable(func_name):
+                return self._convert_bytes_constructor(func_name, expr, args, self._convert_expression)
+            elif self._is_argument_parser_call(expr):
+                return self._convert_argument_parser_call(expr)
+            elif self._is_template_call(expr):
+                return self._convert_template_call(expr)
+            elif codec_function(expr.func, self._is_user_callable) is not None:
+                return self._convert_codec_call(expr, args)
+            elif hash_constructor(expr.func, self._is_user_callable) is not None:
+                return self._convert_hash_constructor(expr, args)
+            elif self._is_reduce_call(expr):
+                return self._convert_reduce_call(expr)[0]
+            elif self._pprint_function(expr) is not None:
+                return self._convert_pprint_call(expr)
 
             # Handle built-in functions
-            if func_name == "print":
-                args_str = ", ".join(args)
-                return f"mgen.Print({args_str})"
-            elif func_name == "len":
+            if func_name == "len":
                 arg_type = self._infer_type_from_value(expr.args[0])
-                if arg_type.startswith("[]"):
+                sized = self._convert_operator_call(expr.args[0], args[0], "__len__", [])
+                if sized is not None:
+                    return sized
+                elif arg_type.startswith("[]"):
                     elem_type = arg_type[2:]
                     return f"mgen.Len[{elem_type}]({args[0]})"
-                elif arg_type.startswith("map["):
-                    return f"mgen.LenMap({args[0]})"
                 elif arg_type == "string":
-                    return f"mgen.LenString({args[0]})"
+                    return self._convert_string_len(expr.args[0], args[0])
+                elif self._is_sized_container(arg_type):
+                    return f"{args[0]}.Len()"
                 else:
-                    return f"mgen.Len({args[0]})"
+                    return f"mgen.LenValue({args[0]})"
             elif func_name == "abs":
-                arg_type = self._infer_type_from_value(expr.args[0])
-                if arg_type == "float64" or arg_type == "float32":
-                    return f"mgen.AbsFloat({args[0]})"
-                else:
-                    return f"mgen.AbsInt({args[0]})"
-            elif func_name == "min":
-                arg_type = self._infer_type_from_value(expr.args[0])
-                elem_type = arg_type[2:] if arg_type.startswith("[]") else "int"
-                return f"mgen.Min[{elem_type}]({args[0]})"
-            elif func_name == "max":
-                arg_type = self._infer_type_from_value(expr.args[0])
-                elem_type = arg_type[2:] if arg_type.startswith("[]") else "int"
-                return f"mgen.Max[{elem_type}]({args[0]})"
+                return self._convert_abs_call(expr.args[0], args[0])
+            elif func_name in ("min", "max"):
+                return self._convert_min_max_call(func_name, expr, args)
             elif func_name == "sum":
-                arg_type = self._infer_type_from_value(expr.args[0])
-                elem_type = arg_type[2:] if arg_type.startswith("[]") else "int"
-                return f"mgen.Sum[{elem_type}]({args[0]})"
+                return self._convert_sum_call(expr, args)
             elif func_name == "any":
                 return f"mgen.Any({args[0]})"
             elif func_name == "all":
                 return f"mgen.All({args[0]})"
             elif func_name == "bool":
-                # BoolValue still needs to use interface{} since it handles many types
+                # Truthiness depends on the dynamic type of the value
                 return f"mgen.ToBool({args[0]})"
+            elif func_name in ("int", "float") and func_name not in self.function_return_types:
+                return self._convert_number_constructor(func_name, expr, args, self._convert_expression)
             elif func_name == "str":
                 return f"mgen.ToStr({args[0]})"
+            elif func_name == "repr":
+                return f"mgen.Repr({args[0]})"
+            elif func_name == "ascii":
+                return f"mgen.Ascii({args[0]})"
+            elif func_name == "hash" and len(args) == 1 and not self._is_user_callable(func_name):
+                return self._convert_hash_call(expr.args[0], args[0])
+            elif func_name in ("ord", "chr") and len(args) == 1 and func_name not in self.function_return_types:
+                # ord() and chr() convert between a one-character str and its code point
+                return f"mgen.{func_name.capitalize()}({args[0]})"
+            elif func_name == "format":
+                spec = args[1] if len(args) > 1 else '""'
+                return f"mgen.Format({args[0]}, {spec})"
+            elif func_name == "open":
+                return f"mgen.Open({args[0]}, {self._open_mode(expr, args, self._convert_expression)})"
+            elif func_name == "input" and func_name not in self.function_return_types:
+                return f"mgen.Input({args[0]})" if args else "mgen.ReadLine()"
             elif func_name == "range":
                 range_args = ", ".join(args)
                 return f"mgen.NewRange({range_args})"
+            elif func_name in ("getattr", "setattr") and func_name not in self.function_return_types:
+                return self._convert_attr_builtin(func_name, expr, args)
+            elif func_name in ("sorted", "reversed", "enumerate", "zip") and func_name not in self.function_return_types:
+                return self._convert_iteration_builtin(func_name, expr, args, self._convert_expression)
+            elif func_name in ("next", "iter") and func_name not in self.function_return_types:
+                return self._convert_iterator_builtin(func_name, expr, args)
+            elif func_name in ("map", "filter") and func_name not in self.function_return_types:
+                return self._convert_map_filter(func_name, expr, args)
+            elif (
+                func_name == "isinstance"
+                and self._isinstance_classes(expr)
+                and func_name not in self.function_return_types
+            ):
+                return self._convert_isinstance(expr, args[0])
             else:
-                # Check if this is a class constructor
-                if func_name in self.struct_info:
-                    args_str = ", ".join(args)
-                    return f"New{func_name}({args_str})"
+                # Check if this is a class constructor (or cls(...) in a classmethod)
+                constructed = self.class_aliases.get(func_name, func_name)
+                if constructed in self.struct_info:
+                    return self._convert_constructor_call(constructed, expr, self._convert_expression)
                 else:
-                    args_str = ", ".join(args)
+                    # Optional[T] parameters take *T: pass None as nil and wrap plain values
+                    param_types = self.function_param_types.get(func_name, [])
+                    call_args = [
+                        self._convert_optional_value(arg, param_types[i])
+                        if i < len(param_types) and self._is_optional_type(param_types[i])
+                        else self._convert_class_value(arg, args[i], param_types[i] if i < len(param_types) else "")
+                        for i, arg in enumerate(expr.args)
+                    ]
+                    args_str = ", ".join(call_args)
                     return f"{func_name}({args_str})"
 
         elif isinstance(expr.func, ast.Attribute):
             return self._convert_method_call_expression(expr)
         else:
-            return "/* Complex function call */"
+            return self._convert_value_call(expr)
+
+    def _convert_value_call(self, expr: ast.Call) -> str:
+        """Call a func value that is not a plain name, like ops["add"](2, 3) or (lambda x: x + 1)(2).
+
+        An immediately called lambda takes its parameter types from the arguments.
+        """
+        if isinstance(expr.func, ast.Lambda) and id(expr.func) not in self.lambda_types:
+            arg_types = [self._infer_type_from_value(arg) for arg in expr.args]
+            result_type = self._lambda_result_type(expr.func, arg_types)
+            self.lambda_types[id(expr.func)] = f"func({', '.join(arg_types)}) {result_type}".rstrip()
+        callee_type = self._infer_type_from_value(expr.func)
+        if isinstance(expr.func, ast.Subscript):
+            # A dict of funcs: the inferred type of d[key] is interface{}
+            dict_types = dict_type_args(self._infer_type_from_value(expr.func.value))
+            callee_type = dict_types[1] if dict_types is not None else callee_type
+        func_type = self._split_func_type(callee_type)
+        if func_type is None:
+            raise UnsupportedFeatureError(f"Calling a value that is not a function is not supported: {ast.unparse(expr)}")
+        for arg, param_type in zip(expr.args, func_type[0]):
+            self._expect_func_type(arg, param_type)
+        args = ", ".join(self._convert_expression(arg) for arg in expr.args)
+        return f"{self._convert_expression(expr.func)}({args})"
+
+    def _convert_stdin_read(self, expr: ast.expr) -> Optional[str]:
+        """Convert the usual ways of reading a line of stdin to the typed mgen readers.
+
+        input() -> mgen.ReadLine(), input().split() -> mgen.ReadWords(), and
+        list(map(int, input().split())), map(int, input().split()) or
+        [int(w) for w in input().split()] -> mgen.ReadInts() (ReadFloats for
+        float), which parse the words without boxing each one.
+        """
+
+        def is_builtin_call(node: ast.expr, name: str) -> bool:
+            return (
+                isinstance(node, ast.Call)
+                and isinstance(node.func, ast.Name)
+                and node.func.id == name
+                and name not in self.function_return_types
+                and not node.keywords
+            )
+
+        def is_words(node: ast.expr) -> bool:
+            return (
+                isinstance(node, ast.Call)
+                and isinstance(node.func, ast.Attribute)
+                and node.func.attr == "split"
+                and not node.args
+                and not node.keywords
+                and is_builtin_call(node.func.value, "input")
+                and not node.func.value.args  # type: ignore[attr-defined]
+            )
+
+        if is_builtin_call(expr, "input") and not expr.args:  # type: ignore[attr-defined]
+            return "mgen.ReadLine()"
+        if is_words(expr):
+            return "mgen.ReadWords()"
+
+        parser: Optional[ast.expr] = None
+        if is_builtin_call(expr, "list") and len(expr.args) == 1:  # type: ignore[attr-defined]
+            expr = expr.args[0]  # type: ignore[attr-defined]
+        if is_builtin_call(expr, "map") and len(expr.args) == 2 and is_words(expr.args[1]):  # type: ignore[attr-defined]
+            parser = expr.args[0]  # type: ignore[attr-defined]
+        elif isinstance(expr, ast.ListComp) and len(expr.generators) == 1:
+            generator = expr.generators[0]
+            if (
+                not generator.ifs
+                and isinstance(generator.target, ast.Name)
+                and is_words(generator.iter)
+                and isinstance(expr.elt, ast.Call)
+                and len(expr.elt.args) == 1
+                and isinstance(expr.elt.args[0], ast.Name)
+                and expr.elt.args[0].id == generator.target.id
+            ):
+                parser = expr.elt.func
+        readers = {"int": "mgen.ReadInts()", "float": "mgen.ReadFloats()"}
+        if isinstance(parser, ast.Name) and parser.id in readers and parser.id not in self.function_return_types:
+            return readers[parser.id]
+        return None
+
+    def _convert_container_constructor(self, func_name: str, arg: ast.expr, arg_expr: str) -> str:
+        """Convert list(x)/tuple(x)/set(x)/dict(x) with Python's constructor semantics.
+
+        Copying a typed slice, dict or set stays typed; other sources use the
+        dynamic runtime conversions, which accept any iterable (strings yield
+        characters and dicts yield keys).
+        """
+        arg_type = self._infer_type_from_value(arg)
+        if func_name == "list" and arg_type.startswith("[]"):
+            return f"append({arg_type}{{}}, {arg_expr}...)"
+        if func_name == "list" and iterated_slice_type(arg_type) != arg_type:
+            # list(d) copies the keys, list(s) the members
+            return self._iterated_slice(arg_expr, arg_type)
+        if func_name == "dict" and dict_type_args(arg_type) is not None:
+            # dict(dd) is a plain dict, without a defaultdict's factory or Counter's 0
+            return f"mgen.NewDict({arg_expr}.Items()...)"
+        if func_name == "set" and set_type_arg(arg_type) is not None:
+            return f"{arg_expr}.Copy()"
+        iterated_type = iterated_slice_type(arg_type)
+        if func_name == "set" and iterated_type.startswith("[]") and (
+            iterated_type[2:] in SET_MEMBER_TYPES or iterated_type[2:] in self.type_params
+        ):
+            # set(xs) over a typed slice or dict keeps the member type
+            return f"mgen.NewSet({self._iterated_slice(arg_expr, arg_type)}...)"
+        go_name = {"list": "ToList", "tuple": "ToTuple", "set": "ToSet", "dict": "ToDict"}[func_name]
+        return f"mgen.{go_name}({arg_expr})"
+
+    def _convert_attr_builtin(
+        self, func_name: str, expr: ast.Call, args: list[str], class_name: Optional[str] = None
+    ) -> str:
+        """Convert getattr(obj, name)/setattr(obj, name, value) to the reflective runtime.
+
+        Struct values are passed by pointer so properties and field writes see the object.
+        """
+        expected = 2 if func_name == "getattr" else 3
+        if len(args) != expected:
+            raise UnsupportedFeatureError(f"{func_name}() with {len(args)} arguments is not supported")
+        owner = expr.args[0]
+        obj_expr = args[0]
+        if isinstance(owner, ast.Name) and owner.id == "self" and class_name is not None:
+            obj_expr = "obj"
+        elif isinstance(owner, ast.Name) and self.variable_types.get(owner.id, "") in self.struct_info:
+            obj_expr = f"&{args[0]}"
+        go_name = "GetAttr" if func_name == "getattr" else "SetAttr"
+        return f"mgen.{go_name}({', '.join([obj_expr, *args[1:]])})"
+
+    def _convert_iteration_builtin(
+        self, func_name: str, expr: ast.Call, args: list[str], convert: Callable[[ast.expr], str]
+    ) -> str:
+        """Convert sorted()/reversed()/enumerate()/zip() to the slice-based runtime helpers.
+
+        Each returns a new slice, so the calls nest like Python's builtins:
+        enumerate(sorted(xs)) -> mgen.Enumerate(mgen.Sorted(xs, false), 0). Dicts
+        iterate their keys and sets their members: sorted(d) -> mgen.Sorted(d.Keys(), false).
+        """
+        keywords = {kw.arg: kw.value for kw in expr.keywords}
+        args = list(args)
+        arg_types = [self._infer_type_from_value(arg) for arg in expr.args]
+        for i, arg_type in enumerate(arg_types):
+            args[i] = self._iterated_slice(args[i], arg_type)
+            arg_types[i] = iterated_slice_type(arg_type)
+        if func_name == "enumerate":
+            start = args[1] if len(args) > 1 else convert(keywords["start"]) if "start" in keywords else "0"
+            return f"mgen.Enumerate({args[0]}, {start})"
+        if func_name == "zip":
+            if len(args) not in (2, 3):
+                raise UnsupportedFeatureError(f"zip() of {len(args)} iterables not supported (only 2 or 3)")
+            return f"mgen.{'Zip' if len(args) == 2 else 'Zip3'}({', '.join(args)})"
+        if func_name == "reversed":
+            return f"mgen.Reversed({args[0]})"
+
+        # sorted(xs, key=..., reverse=...)
+        source_type = arg_types[0]
+        elem_type = source_type[2:] if source_type.startswith("[]") else "interface{}"
+        return self._convert_sort("Sorted", args[0], elem_type, keywords, convert)
+
+    def _convert_iterator_builtin(self, func_name: str, expr: ast.Call, args: list[str]) -> str:
+        """Convert iter(xs) to mgen.Iter and next(it) / next(it, default) to mgen.Next / mgen.NextOr.
+
+        next() raises StopIteration once the iterator is exhausted unless it has a default.
+        """
+        if len(args) not in (1, 2) or (func_name == "iter" and len(args) != 1):
+            raise UnsupportedFeatureError(f"{func_name}() with {len(args)} arguments is not supported")
+        source_type = self._infer_type_from_value(expr.args[0])
+        if func_name == "iter":
+            if iterator_type_arg(source_type) is not None:
+                # iter() of an iterator is the iterator itself
+                return args[0]
+            if not iterated_slice_type(source_type).startswith("[]"):
+                raise UnsupportedFeatureError(f"iter() of a {source_type} value is not supported")
+            return f"mgen.Iter({self._iterated_slice(args[0], source_type)})"
+        if iterator_type_arg(source_type) is None:
+            raise UnsupportedFeatureError(
+                f"next() needs an iterator such as a generator or iter(xs): {ast.unparse(expr)}"
+            )
+        if len(args) == 2:
+            return f"mgen.NextOr({args[0]}, {args[1]})"
+        return f"mgen.Next({args[0]})"
+
+    def _convert_sort(
+        self,
+        helper: str,
+        source: str,
+        elem_type: str,
+        keywords: dict[str, ast.expr],
+        convert: Callable[[ast.expr], str],
+    ) -> str:
+        """Convert sorted(xs, ...) (helper "Sorted") or xs.sort(...) (helper "Sort").
+
+        Both sort stably, like Python's Timsort, in either direction. Keys of
+        one ordered type use the By form; tuple and dynamically typed keys use
+        ByValue and compare with Python's rules, as do tuple elements.
+
+        Example:
+            sorted(ws, key=len)        →  mgen.SortedBy(ws, func(item string) int { ... }, false)
+            ws.sort(reverse=True)      →  mgen.Sort(ws, true)
+        """
+        reverse = convert(keywords["reverse"]) if "reverse" in keywords else "false"
+        if "key" in keywords and not (isinstance(keywords["key"], ast.Constant) and keywords["key"].value is None):
+            key_func, key_type = self._convert_key_function(keywords["key"], elem_type)
+            if key_type not in ORDERED_TYPES:
+                # Tuple or dynamically typed keys compare with Python's rules
+                key_func, _ = self._convert_key_function(keywords["key"], elem_type, "interface{}")
+                return f"mgen.{helper}ByValue({source}, {key_func}, {reverse})"
+            return f"mgen.{helper}By({source}, {key_func}, {reverse})"
+        if elem_type == "interface{}":
+            return f"mgen.{helper}Values({source}, {reverse})"
+        if elem_type not in ORDERED_TYPES:
+            # Tuples and bools have no Go ordering; they compare with Python's rules
+            return f"mgen.{helper}ByValue({source}, func(item {elem_type}) interface{{}} {{ return item }}, {reverse})"
+        return f"mgen.{helper}({source}, {reverse})"
+
+    def _convert_list_sort(
+        self, expr: ast.Call, obj_expr: str, convert: Callable[[ast.expr], str], class_name: Optional[str] = None
+    ) -> Optional[str]:
+        """Convert xs.sort(key=..., reverse=...) on a list to an in-place mgen.Sort helper.
+
+        Returns None when the receiver is not a list, so other sort methods are called as they are.
+        """
+        if not isinstance(expr.func, ast.Attribute):
+            return None
+        receiver = expr.func.value
+        receiver_type = self._infer_type_from_value(receiver)
+        owner_class = self._property_class(receiver.value, class_name) if isinstance(receiver, ast.Attribute) else None
+        if isinstance(receiver, ast.Attribute) and owner_class is not None:
+            # self.items.sort() in a method: the field's declared type
+            receiver_type = self.struct_info[owner_class]["field_types"].get(receiver.attr, receiver_type)
+        if receiver_type == "*mgen.PyList":
+            obj_expr, receiver_type = f"{obj_expr}.Items", "[]interface{}"
+        if not receiver_type.startswith("[]"):
+            return None
+        keywords = {kw.arg: kw.value for kw in expr.keywords}
+        if expr.args or not set(keywords) <= {"key", "reverse"}:
+            raise UnsupportedFeatureError(f"list.sort() takes only the key and reverse keywords: {ast.unparse(expr)}")
+        return self._convert_sort("Sort", obj_expr, receiver_type[2:], keywords, convert)
+
+    def _convert_key_function(
+        self, key: ast.expr, elem_type: str, result_type: Optional[str] = None
+    ) -> tuple[str, str]:
+        """Convert a key= argument to a Go func taking elem_type, with its result type.
+
+        User functions are passed as is; builtins such as len or abs are
+        wrapped in a closure so they get the same lowering as a direct call,
+        and lambdas take elem_type as their parameter type. result_type forces
+        the func's result type (interface{} for the dynamically keyed helpers).
+        """
+        if isinstance(key, ast.Lambda):
+            return self._convert_lambda(key, [elem_type], result_type), result_type or self._lambda_result_type(
+                key, [elem_type]
+            )
+        if isinstance(key, ast.Name) and key.id in self.function_return_types and result_type is None:
+            return key.id, self.function_return_types[key.id]
+        dict_types = dict_type_args(self._infer_type_from_value(key.value)) if isinstance(key, ast.Attribute) else None
+        if dict_types is not None and isinstance(key, ast.Attribute) and key.attr == "get":
+            # max(d, key=d.get) looks each key up through the method value d.Get
+            lookup = f"{self._convert_expression(key.value)}.Get"
+            if result_type is None:
+                return lookup, dict_types[1]
+            return f"func(item {elem_type}) {result_type} {{ return {lookup}(item) }}", result_type
+        item = ast.Name(id="item", ctx=ast.Load())
+        call = None
+        if isinstance(key, ast.Name):
+            call = ast.Call(func=key, args=[item], keywords=[])
+        elif (
+            isinstance(key, ast.Attribute)
+            and isinstance(key.value, ast.Name)
+            and key.value.id in ("str", "bytes")
+            and key.value.id not in self.variable_types
+        ):
+            # key=str.lower calls the method on each item: item.lower()
+            call = ast.Call(func=ast.Attribute(value=item, attr=key.attr, ctx=ast.Load()), args=[], keywords=[])
+        if call is not None:
+            outer_types = self.variable_types
+            self.variable_types = {**outer_types, "item": elem_type}
+            try:
+                body = self._convert_expression(call)
+                inferred_type = self._infer_type_from_value(call)
+            finally:
+                self.variable_types = outer_types
+            if inferred_type == "interface{}" and elem_type != "interface{}":
+                inferred_type = self._infer_comprehension_element_type(call, {"item": elem_type})
+            result_type = result_type or inferred_type
+            return f"func(item {elem_type}) {result_type} {{ return {body} }}", result_type
+        return self._convert_expression(key), "interface{}"
+
+    def _convert_dynamic_key(self, key: ast.expr, elem_type: str) -> str:
+        """Convert a key= argument to the func(interface{}) interface{} form of
+        reflective helpers such as MinBy, asserting each item to elem_type."""
+        key_func, _ = self._convert_key_function(key, elem_type, "interface{}")
+        if elem_type == "interface{}":
+            return key_func
+        return f"func(item interface{{}}) interface{{}} {{ return ({key_func})(item.({elem_type})) }}"
+
+    def _convert_lambda(
+        self, expr: ast.Lambda, param_types: Optional[list[str]] = None, result_type: Optional[str] = None
+    ) -> str:
+        """Convert a lambda to a Go func literal.
+
+        Python lambdas carry no annotations, so parameter types come from the
+        context: the element type a key function receives, or the func type of
+        the Callable parameter, variable or return value the lambda is bound
+        to; without one they are interface{}. The body reads enclosing
+        variables through the closure, like a nested function.
+
+        Example:
+            sorted(words, key=lambda w: len(w))  →  func(w string) int { return mgen.LenString(w) }
+        """
+        args = expr.args
+        if args.vararg or args.kwarg or args.kwonlyargs or args.posonlyargs or args.defaults:
+            raise UnsupportedFeatureError("lambda default, keyword-only and variadic parameters are not supported")
+        names = [arg.arg for arg in args.args]
+        types = (list(param_types or []) + ["interface{}"] * len(names))[: len(names)]
+        outer_types = self.variable_types
+        self.variable_types = {**outer_types, **dict(zip(names, types))}
+        try:
+            body = self._convert_expression(expr.body)
+        finally:
+            self.variable_types = outer_types
+        if result_type is None:
+            result_type = self._lambda_result_type(expr, types)
+        params = ", ".join(f"{name} {param_type}" for name, param_type in zip(names, types))
+        if not result_type:
+            return f"func({params}) {{ {body} }}"
+        return f"func({params}) {result_type} {{ return {body} }}"
+
+    def _lambda_result_type(self, expr: ast.Lambda, param_types: list[str]) -> str:
+        """Infer the result type of a lambda whose parameters have param_types.
+
+        The body is typed like a comprehension element with the parameters as
+        its loop variables; a body that reads an untyped parameter stays
+        interface{}.
+        """
+        params = dict(zip((arg.arg for arg in expr.args.args), param_types))
+        outer_types = self.variable_types
+        self.variable_types = {**outer_types, **params}
+        try:
+            result_type = self._infer_type_from_value(expr.body)
+        finally:
+            self.variable_types = outer_types
+        untyped = any(
+            isinstance(node, ast.Name) and params.get(node.id) == "interface{}" for node in ast.walk(expr.body)
+        )
+        if result_type == "interface{}" and not untyped:
+            result_type = self._infer_comprehension_element_type(expr.body, params)
+        return result_type
+
+    def _lambda_type_from_calls(self, name: str, expr: ast.Lambda, stmts: list[ast.stmt]) -> str:
+        """Return the func type of a lambda assigned to name, taking its parameter
+        types from the first call name(...) in stmts (interface{} when never called)."""
+        param_types = ["interface{}"] * len(expr.args.args)
+        for node in ast.walk(ast.Module(body=stmts, type_ignores=[])):
+            if (
+                isinstance(node, ast.Call)
+                and isinstance(node.func, ast.Name)
+                and node.func.id == name
+                and len(node.args) == len(param_types)
+            ):
+                param_types = [self._infer_type_from_value(arg) for arg in node.args]
+                break
+        params = ", ".join(f"{arg.arg} {param_type}" for arg, param_type in zip(expr.args.args, param_types))
+        return f"func({params}) {self._lambda_result_type(expr, param_types)}".rstrip()
+
+    def _expect_func_type(self, expr: ast.expr, go_type: str) -> None:
+        """Record the func type a lambda is bound to, so it converts with typed parameters.
+
+        The items of a list display and the values of a dict display bound to
+        a list or dict of funcs take its item type.
+        """
+        if isinstance(expr, ast.Lambda) and go_type.startswith("func("):
+            self.lambda_types[id(expr)] = go_type
+        elif isinstance(expr, ast.List) and go_type.startswith("[]"):
+            for elt in expr.elts:
+                self._expect_func_type(elt, go_type[2:])
+        elif isinstance(expr, ast.Dict) and dict_type_args(go_type) is not None:
+            dict_types = dict_type_args(go_type)
+            assert dict_types is not None
+            for value in expr.values:
+                self._expect_func_type(value, dict_types[1])
+
+    def _split_func_type(self, go_type: str) -> Optional[tuple[list[str], str]]:
+        """Split a Go func type "func(a int, string) bool" into (["int", "string"], "bool")."""
+        if not go_type.startswith("func("):
+            return None
+        params: list[str] = []
+        depth = 0
+        start = 5
+        for i in range(4, len(go_type)):
+            char = go_type[i]
+            if char in "([{":
+                depth += 1
+            elif char in ")]}":
+                depth -= 1
+                if depth == 0:
+                    if go_type[start:i].strip():
+                        params.append(go_type[start:i].strip())
+                    result = go_type[i + 1 :].strip()
+                    break
+            elif char == "," and depth == 1:
+                params.append(go_type[start:i].strip())
+                start = i + 1
+        else:
+            return None
+        # Drop parameter names: "a int" -> "int"
+        param_types = [re.sub(r"^[A-Za-z_]\w* (?=\S)", "", param) for param in params]
+        return param_types, result
+
+    def _convert_map_filter(self, func_name: str, expr: ast.Call, args: list[str]) -> str:
+        """Convert map(f, xs)/filter(f, xs) to MapSlice/FilterSlice over a typed slice.
+
+        Like Python 3's lazy map and filter the result is consumed by list() or a
+        for loop; here it is computed eagerly. filter(None, xs) keeps truthy items.
+        """
+        if len(expr.args) != 2:
+            raise UnsupportedFeatureError(f"{func_name}() with {len(expr.args)} arguments is not supported")
+        function, iterable = expr.args
+        source_type = self._infer_type_from_value(iterable)
+        source = args[1]
+        if not source_type.startswith("[]"):
+            source_type = "[]interface{}"
+            source = f"mgen.ToList({args[1]})"
+        elem_type = source_type[2:]
+        if func_name == "map":
+            key_func, _ = self._convert_key_function(function, elem_type)
+            return f"mgen.MapSlice({key_func}, {source})"
+        if isinstance(function, ast.Constant) and function.value is None:
+            return f"mgen.FilterSlice(func(item {elem_type}) bool {{ return mgen.ToBool(item) }}, {source})"
+        key_func, key_type = self._convert_key_function(function, elem_type)
+        if key_type != "bool":
+            # filter() keeps items whose result is truthy
+            key_func = f"func(item {elem_type}) bool {{ return mgen.ToBool(({key_func})(item)) }}"
+        return f"mgen.FilterSlice({key_func}, {source})"
+
+    def _is_reduce_call(self, expr: ast.Call) -> bool:
+        """Check for functools.reduce(...), or reduce(...) imported from functools."""
+        func = expr.func
+        if isinstance(func, ast.Attribute):
+            return func.attr == "reduce" and isinstance(func.value, ast.Name) and func.value.id == "functools"
+        return isinstance(func, ast.Name) and func.id == "reduce" and not self._is_user_callable("reduce")
+
+    def _convert_reduce_call(self, expr: ast.Call) -> tuple[str, str]:
+        """Convert functools.reduce(f, xs[, initial]) to a typed fold, with the type of its result.
+
+        Over a typed slice, f becomes a func of the accumulator and element
+        types: ReduceSlice starts from the first element and Fold from the
+        initial value. Other iterables, and functions whose result is not the
+        accumulator's type, go through the dynamic mgen.Reduce.
+
+        Example:
+            reduce(lambda a, b: a * b, nums)  →  mgen.ReduceSlice(func(a int, b int) int { return (a * b) }, nums)
+            reduce(lambda n, w: n + len(w), words, 0)
+                →  mgen.Fold(words, 0, func(n int, w string) int { return (n + mgen.LenString(w)) })
+        """
+        if expr.keywords or len(expr.args) not in (2, 3):
+            raise UnsupportedFeatureError("reduce() takes a function, an iterable and an optional initial value")
+        function, iterable = expr.args[:2]
+        initial = expr.args[2] if len(expr.args) == 3 else None
+        iterable_type = self._infer_type_from_value(iterable)
+        source = self._iterated_slice(self._convert_expression(iterable), iterable_type)
+        source_type = iterated_slice_type(iterable_type)
+        elem_type = source_type[2:] if source_type.startswith("[]") else "interface{}"
+        if elem_type != "interface{}":
+            acc_type = elem_type if initial is None else self._infer_type_from_value(initial)
+            if initial is None or acc_type != "interface{}":
+                combine = self._reduce_function(function, acc_type, elem_type)
+                if combine is not None and initial is None:
+                    return f"mgen.ReduceSlice({combine}, {source})", acc_type
+                if combine is not None:
+                    return f"mgen.Fold({source}, {self._convert_expression(initial)}, {combine})", acc_type
+        combine = self._reduce_function(function, "interface{}", "interface{}")
+        if combine is None:
+            raise UnsupportedFeatureError(f"reduce() needs a lambda or function of two arguments: {ast.unparse(expr)}")
+        initial_arg = "" if initial is None else f", {self._convert_expression(initial)}"
+        return f"mgen.Reduce({combine}, {source}{initial_arg})", "interface{}"
+
+    def _reduce_result_type(self, function: ast.expr, acc_type: str, elem_type: str) -> str:
+        """Return the type f(acc, item) has for a reduce() function, or "" when it is not known."""
+        if isinstance(function, ast.Lambda) and len(function.args.args) == 2:
+            return self._lambda_result_type(function, [acc_type, elem_type])
+        if isinstance(function, ast.Name) and self.function_param_types.get(function.id) == [acc_type, elem_type]:
+            return self.function_return_types.get(function.id, "")
+        return ""
+
+    def _reduce_function(self, function: ast.expr, acc_type: str, elem_type: str) -> Optional[str]:
+        """Convert a reduce() function to a func(acc_type, elem_type) acc_type, or None when its types differ.
+
+        With interface{} types (the dynamic mgen.Reduce) a user function is
+        called with its arguments asserted to its parameter types.
+        """
+        dynamic = acc_type == elem_type == "interface{}"
+        if isinstance(function, ast.Lambda) and len(function.args.args) == 2:
+            if not dynamic and self._reduce_result_type(function, acc_type, elem_type) != acc_type:
+                return None
+            return self._convert_lambda(function, [acc_type, elem_type], acc_type)
+        if not (isinstance(function, ast.Name) and len(self.function_param_types.get(function.id, [])) == 2):
+            return None
+        if not dynamic:
+            return function.id if self._reduce_result_type(function, acc_type, elem_type) == acc_type else None
+        first, second = (
+            f"{name}.({param_type})" if param_type != "interface{}" else name
+            for name, param_type in zip(("acc", "item"), self.function_param_types[function.id])
+        )
+        return f"func(acc, item interface{{}}) interface{{}} {{ return {function.id}({first}, {second}) }}"
+
+    def _convert_cache_method(self, expr: ast.Call) -> str:
+        """Convert cache_info() and cache_clear() on an @lru_cache function to its cache's Info and Clear.
+
+        Example:
+            fib.cache_info()  →  fibCache.Info()
+        """
+        assert isinstance(expr.func, ast.Attribute) and isinstance(expr.func.value, ast.Name)
+        methods = {"cache_info": "Info", "cache_clear": "Clear"}
+        if expr.func.attr not in methods or expr.args or expr.keywords:
+            raise UnsupportedFeatureError(f"Unsupported method of a cached function: {ast.unparse(expr)}")
+        return f"{expr.func.value.id}Cache.{methods[expr.func.attr]}()"
+
+    def _functools_type(self, expr: ast.Call) -> Optional[str]:
+        """Return the type of a reduce() call or a cached function's cache_info(), or None for other calls."""
+        if self._is_reduce_call(expr):
+            return self._convert_reduce_call(expr)[1]
+        func = expr.func
+        if isinstance(func, ast.Attribute) and ast.unparse(func.value) in self.cached_functions:
+            return "mgen.CacheInfo" if func.attr == "cache_info" else ""
+        return None
+
+    def _map_filter_type(self, expr: ast.Call) -> str:
+        """Return the slice type map()/filter() produces (see _convert_map_filter)."""
+        source_type = self._infer_type_from_value(expr.args[1]) if len(expr.args) == 2 else ""
+        if not source_type.startswith("[]"):
+            source_type = "[]interface{}"
+        if expr.func.id == "filter" or len(expr.args) != 2:  # type: ignore[attr-defined]
+            return source_type
+        _, result_type = self._convert_key_function(expr.args[0], source_type[2:])
+        return f"[]{result_type}"
+
+    def _check_builtin_keywords(self, func_name: str, expr: ast.Call) -> None:
+        """Reject keyword arguments a lowered builtin does not accept."""
+        if func_name not in self.builtin_keywords or func_name in self.struct_info:
+            return
+        for kw in expr.keywords:
+            if kw.arg is None:
+                raise UnsupportedFeatureError(f"**kwargs unpacking not supported in {func_name}() calls")
+            if kw.arg not in self.builtin_keywords[func_name]:
+                raise UnsupportedFeatureError(f"{func_name}() got an unexpected keyword argument '{kw.arg}'")
+
+    def _convert_print_call(self, expr: ast.Call, convert: Callable[[ast.expr], str]) -> str:
+        """Convert print(); sep/end/file/flush keywords select mgen.PrintOpts.
+
+        sep=None and end=None mean the defaults, as in Python, and flush=False
+        is the default. Unpacked iterables (*xs) expand through mgen.ToList.
+
+        Example:
+            print(*xs, sep=", ")      →  mgen.PrintOpts(mgen.PrintOptions{Sep: ", ", End: "\\n"}, mgen.ToList(xs)...)
+            print(a, *xs, flush=True) →  mgen.PrintOpts(mgen.PrintOptions{..., Flush: true},
+                                             mgen.Concat([]interface{}{a}, mgen.ToList(xs))...)
+        """
+        options = {"Sep": '" "', "End": '"\\n"'}
+        customized = False
+        for kw in expr.keywords:
+            if isinstance(kw.value, ast.Constant) and kw.value.value in (None, False):
+                continue
+            customized = True
+            if kw.arg in ("sep", "end"):
+                options[kw.arg.capitalize()] = convert(kw.value)
+            elif kw.arg == "flush":
+                flush = convert(kw.value)
+                options["Flush"] = flush if self._infer_type_from_value(kw.value) == "bool" else f"mgen.ToBool({flush})"
+            elif kw.arg == "file":
+                writer = self._convert_print_file(kw.value, convert)
+                if writer is not None:
+                    options["File"] = writer
+
+        args_str = self._print_arguments(expr.args, convert)
+        if not customized:
+            return f"mgen.Print({args_str})"
+        fields = ", ".join(f"{name}: {value}" for name, value in options.items())
+        return f"mgen.PrintOpts(mgen.PrintOptions{{{fields}}}{', ' + args_str if args_str else ''})"
+
+    def _print_arguments(self, args: list[ast.expr], convert: Callable[[ast.expr], str]) -> str:
+        """Convert the positional arguments of print(), spreading unpacked iterables (*xs) with ...."""
+        parts: list[str] = []
+        plain: list[str] = []
+        for arg in args:
+            if isinstance(arg, ast.Starred):
+                if plain:
+                    parts.append(f"[]interface{{}}{{{', '.join(plain)}}}")
+                    plain = []
+                parts.append(f"mgen.ToList({convert(arg.value)})")
+            elif isinstance(arg, ast.Name) and self._is_optional_type(self.variable_types.get(arg.id, "")):
+                # Optional[T] arguments print as None when nil instead of being unwrapped
+                plain.append(f"mgen.NoneIfNil({arg.id})")
+            else:
+                plain.append(convert(arg))
+        if not parts:
+            return ", ".join(plain)
+        if plain:
+            parts.append(f"[]interface{{}}{{{', '.join(plain)}}}")
+        return (parts[0] if len(parts) == 1 else f"mgen.Concat({', '.join(parts)})") + "..."
+
+    def _convert_print_file(self, file_arg: ast.expr, convert: Callable[[ast.expr], str]) -> Optional[str]:
+        """Return the io.Writer for print(file=...), or None for the current stdout."""
+        if isinstance(file_arg, ast.Attribute) and isinstance(file_arg.value, ast.Name) and file_arg.value.id == "sys":
+            if file_arg.attr == "stdout":
+                return None
+            if file_arg.attr == "stderr":
+                return "mgen.GetStderr()"
+        file_expr = convert(file_arg)
+        if self._infer_type_from_value(file_arg) == "*mgen.PyFile":
+            return f"{file_expr}.Writer()"
+        return file_expr
+
+    def _open_mode(self, expr: ast.Call, args: list[str], convert: Callable[[ast.expr], str]) -> str:
+        """Return the mode of open(path[, mode]), given positionally or as mode=."""
+        if len(args) > 1:
+            return args[1]
+        mode_arg = next((kw.value for kw in expr.keywords if kw.arg == "mode"), None)
+        return convert(mode_arg) if mode_arg is not None else '"r"'
+
+    def _convert_number_constructor(
+        self, func_name: str, expr: ast.Call, args: list[str], convert: Callable[[ast.expr], str]
+    ) -> str:
+        """Convert int()/float() with Python's parsing and truncation rules.
+
+        Conversions Go can do exactly stay as Go conversions; strings, floats
+        passed to int() and dynamic values go through mgen.ToInt/ToFloat, which
+        raise ValueError for text Python rejects and for inf/nan.
+        """
+        base_arg = next((kw.value for kw in expr.keywords if kw.arg == "base"), None)
+        if func_name == "int" and (len(args) > 1 or base_arg is not None):
+            base = args[1] if len(args) > 1 else convert(base_arg)
+            return f"mgen.ToIntBase({args[0]}, {base})"
+        if not args:
+            return "0" if func_name == "int" else "0.0"
+
+        arg_type = self._infer_type_from_value(expr.args[0])
+        if func_name == "int":
+            if arg_type in ("int", BIG_INT_TYPE):
+                return args[0]
+            return f"mgen.ToInt({args[0]})"
+        if arg_type == BIG_INT_TYPE:
+            return f"{args[0]}.Float()"
+        if arg_type == "float64":
+            return args[0]
+        if arg_type == "int":
+            return f"float64({args[0]})"
+        return f"mgen.ToFloat({args[0]})"
+
+    def _operand_type(self, expr: ast.expr) -> Optional[str]:
+        """Inferred type of a builtin's argument, or None for untyped arithmetic.
+
+        Inference does not follow arithmetic (a - b, -1), which reports
+        interface{} without the value being dynamic.
+        """
+        arg_type = self._infer_type_from_value(expr)
+        if arg_type == "interface{}" and isinstance(expr, (ast.BinOp, ast.UnaryOp)):
+            return None
+        return arg_type
+
+    def _arithmetic_type(self, expr: ast.expr) -> str:
+        """Infer the type of a number, following -x, +x and arithmetic that _operand_type does not.
+
+        Ints (and bools) stay int, a float operand or / makes the result
+        float64, and anything else is interface{}.
+
+        Example:
+            -y       →  float64   (y: float)
+            n - 10   →  int       (n: int)
+            n / 2    →  float64
+        """
+        if isinstance(expr, ast.UnaryOp) and isinstance(expr.op, (ast.USub, ast.UAdd)):
+            return self._arithmetic_type(expr.operand)
+        if isinstance(expr, ast.BinOp) and isinstance(
+            expr.op, (ast.Add, ast.Sub, ast.Mult, ast.Div, ast.FloorDiv, ast.Mod, ast.Pow)
+        ):
+            operand_types = {self._arithmetic_type(expr.left), self._arithmetic_type(expr.right)}
+            if operand_types <= {"int", "bool"}:
+                return "float64" if isinstance(expr.op, ast.Div) and self.python_version == 3 else "int"
+            if operand_types <= {"int", "bool", "float64"}:
+                return "float64"
+            return "interface{}"
+        return self._infer_type_from_value(expr)
+
+    def _convert_abs_call(self, arg: ast.expr, arg_expr: str) -> str:
+        """Convert abs() by argument type, falling back to mgen.AbsValue for dynamic values."""
+        arg_type = self._arithmetic_type(arg)
+        if arg_type in ("float64", "float32"):
+            return f"mgen.AbsFloat({arg_expr})"
+        elif arg_type in ("int", "bool"):
+            return f"mgen.AbsInt({arg_expr})"
+        return f"mgen.AbsValue({arg_expr})"
+
+    def _convert_min_max_call(self, func_name: str, expr: ast.Call, args: list[str]) -> str:
+        """Convert min()/max(); like Python, a dict or set argument iterates its keys or members.
+
+        Several arguments of one ordered type use Go's builtin min/max; other
+        argument lists are compared as a slice, like a single iterable.
+        """
+        go_name = "Min" if func_name == "min" else "Max"
+        key_arg = next((kw.value for kw in expr.keywords if kw.arg == "key"), None)
+        if len(expr.args) > 1:
+            operand_types = [self._operand_type(arg) for arg in expr.args]
+            known = {arg_type for arg_type in operand_types if arg_type is not None} or {"int"}
+            arg_types = [next(iter(known)) if len(known) == 1 else "interface{}"] * len(args)
+            if key_arg is None and numeric_builtin_type(func_name, arg_types) != "interface{}":
+                return f"{func_name}({', '.join(args)})"
+            elem_type = arg_types[0]
+            args = [f"[]{elem_type}{{{', '.join(args)}}}"]
+            arg_type = f"[]{elem_type}"
+        else:
+            arg_type = self._infer_type_from_value(expr.args[0])
+        args = [self._iterated_slice(args[0], arg_type)]
+        arg_type = iterated_slice_type(arg_type)
+
+        elem_type = arg_type[2:] if arg_type.startswith("[]") else "int"
+        if key_arg is None and elem_type == "interface{}":
+            # Mixed items ([True, 0, 2]) compare with Python's rules
+            return f"mgen.{go_name}By({args[0]}, nil)"
+        if key_arg is None and self._record_fields(elem_type) is not None:
+            # So do tuples, item by item
+            return f"mgen.{go_name}By({args[0]}, nil).({elem_type})"
+        if key_arg is not None:
+            # min(xs, key=f) compares f(x) with Python's rules through the reflective MinBy
+            found = f"mgen.{go_name}By({args[0]}, {self._convert_dynamic_key(key_arg, elem_type)})"
+            return found if elem_type == "interface{}" else f"{found}.({elem_type})"
+        return f"mgen.{go_name}[{elem_type}]({args[0]})"
+
+    def _convert_sum_call(self, expr: ast.Call, args: list[str]) -> str:
+        """Convert sum() by element type; bools count as 0/1 like Python ints.
+
+        A start value is added in front of the total, through mgen.BinOp when
+        its type differs from the elements'. Dicts and sets sum their keys and members.
+        """
+        arg_type = self._infer_type_from_value(expr.args[0])
+        args = [self._iterated_slice(args[0], arg_type), *args[1:]]
+        arg_type = iterated_slice_type(arg_type)
+        elem_type = arg_type[2:] if arg_type.startswith("[]") else "int"
+        if elem_type == "bool":
+            total = f"mgen.SumBool({args[0]})"
+        elif elem_type == "interface{}":
+            total = f"mgen.SumValues({args[0]})"
+        else:
+            total = f"mgen.Sum[{elem_type}]({args[0]})"
+        if len(args) < 2:
+            return total
+        arg_types = [self._infer_type_from_value(arg) for arg in expr.args]
+        if numeric_builtin_type("sum", arg_types) == "interface{}":
+            return f'mgen.BinOp("+", {args[1]}, {total})'
+        return f"({args[1]} + {total})"
 
     def _convert_method_call_expression(self, expr: ast.Call) -> str:
         """Convert method calls on objects."""
         if isinstance(expr.func, ast.Attribute):
+            class_function_call = self._class_function_call(expr, self._convert_expression)
+            if class_function_call is not None:
+                return class_function_call
+            if self._is_argument_parser_call(expr):
+                return self._convert_argument_parser_call(expr)
+            if self._is_template_call(expr):
+                return self._convert_template_call(expr)
+            if self._pprint_function(expr) is not None:
+                return self._convert_pprint_call(expr)
+            if self._is_reduce_call(expr):
+                return self._convert_reduce_call(expr)[0]
+            if isinstance(expr.func.value, ast.Name) and expr.func.value.id in self.cached_functions:
+                return self._convert_cache_method(expr)
+            if self._is_str_format_call(expr):
+                return self._convert_str_format_call(expr, self._convert_expression)
+            if ast.unparse(expr.func) == "bytes.fromhex" and len(expr.args) == 1:
+                return f"mgen.BytesFromHex({self._convert_expression(expr.args[0])})"
+            if codec_function(expr.func, self._is_user_callable) is not None:
+                return self._convert_codec_call(expr, [self._convert_expression(arg) for arg in expr.args])
+            if hash_constructor(expr.func, self._is_user_callable) is not None:
+                return self._convert_hash_constructor(expr, [self._convert_expression(arg) for arg in expr.args])
             obj_expr = self._convert_expression(expr.func.value)
             method_name = expr.func.attr
+            self._expect_method_func_types(expr)
             args = [self._convert_expression(arg) for arg in expr.args]
-
-            # Handle string methods
-            if method_name in ["upper", "lower", "strip", "find", "replace", "split"]:
-                if method_name == "upper":
-                    return f"mgen.StrOps.Upper({obj_expr})"
-                elif method_name == "lower":
-                    return f"mgen.StrOps.Lower({obj_expr})"
-                elif method_name == "strip":
-                    if args:
-                        return f"mgen.StrOps.StripChars({obj_expr}, {args[0]})"
-                    else:
-                        return f"mgen.StrOps.Strip({obj_expr})"
-                elif method_name == "find":
-                    return f"mgen.StrOps.Find({obj_expr}, {args[0]})"
-                elif method_name == "replace":
-                    return f"mgen.StrOps.Replace({obj_expr}, {args[0]}, {args[1]})"
-                elif method_name == "split":
-                    if args:
-                        return f"mgen.StrOps.SplitSep({obj_expr}, {args[0]})"
-                    else:
-                        return f"mgen.StrOps.Split({obj_expr})"
+            if self._infer_type_from_value(expr.func.value) in BYTES_TYPES:
+                return self._convert_bytes_method(obj_expr, method_name, expr, args)
+
+            # Handle string methods, unless the receiver is a user class that defines its own
+            if not self._is_class_instance(expr.func.value):
+                string_call = self._convert_string_method(obj_expr, method_name, args, expr)
+                if string_call is not None:
+                    return string_call
+
+            container_call = self._convert_container_method(obj_expr, method_name, expr, args)
+            if container_call is not None:
+                return container_call
+            if self._infer_type_from_value(expr.func.value) == "*mgen.ArgumentParser":
+                return self._convert_argument_parser_method(obj_expr, method_name, expr, args)
+            if self._infer_type_from_value(expr.func.value) == "mgen.Template":
+                return self._convert_template_method(obj_expr, method_name, expr, args)
+            if self._infer_type_from_value(expr.func.value) == "*mgen.Hash":
+                return self._convert_hash_method(obj_expr, method_name, expr, args)
+
+            if method_name == "sort":
+                list_sort = self._convert_list_sort(expr, obj_expr, self._convert_expression)
+                if list_sort is not None:
+                    return list_sort
 
             # Handle container methods - convert append to Go's builtin
             # Note: This generates an expression that should be used in assignment
             if method_name == "append":
                 # Python's list.append() -> Go's append() builtin
                 # This needs special handling because append returns the new slice
+                list_type = self._infer_type_from_value(expr.func.value)
+                if len(args) == 1 and list_type.startswith("[]"):
+                    args = [self._convert_class_value(expr.args[0], args[0], list_type[2:])]
                 args_str = ", ".join(args)
                 # Return a marker that the statement converter can detect
                 return f"__APPEND__{obj_expr}__ARGS__{args_str}__END__"
 
-            # Handle dict methods - translate to Go map iteration
-            elif method_name == "items":
-                # Python's dict.items() - Convert to slice of key-value pairs for comprehensions
-                # mgen.MapItems() returns []mgen.KV[K, V]
-                return f"mgen.MapItems({obj_expr})"
-            elif method_name == "values":
-                # Python's dict.values() - need to extract values from map
-                # This would need runtime support, for now use map directly
-                return f"mgen.MapValues({obj_expr})"
-            elif method_name == "keys":
-                # Python's dict.keys() - need to extract keys from map
-                return f"mgen.MapKeys({obj_expr})"
-
             # Regular method call
-            args_str = ", ".join(args)
+            args_str = ", ".join(self._convert_method_arguments(expr, args))
             return f"{obj_expr}.{self._to_go_method_name(method_name)}({args_str})"
 
         return "/* Complex method call */"
 
+    def _convert_container_method(
+        self, obj_expr: str, method_name: str, expr: ast.Call, args: list[str]
+    ) -> Optional[str]:
+        """Convert a method call on a deque, list, dict or set, whether held by a local or a field.
+
+        Returns None for other receivers, and for list append() and sort(),
+        which the callers lower themselves.
+        """
+        assert isinstance(expr.func, ast.Attribute)
+        receiver_type = self._infer_type_from_value(expr.func.value)
+        if receiver_type.startswith("*mgen.Deque["):
+            return self._convert_deque_method(obj_expr, method_name, expr, args)
+        if method_name in ("append", "sort"):
+            return None
+        if self._list_receiver_type(expr.func.value) is not None:
+            return self._convert_list_method(obj_expr, method_name, expr, args)
+        if receiver_type == "*mgen.PyDict":
+            # Value-hashed dicts (tuple keys) implement the dict methods themselves
+            return self._convert_py_dict_method(obj_expr, method_name, expr, args)
+        if dict_type_args(receiver_type) is not None:
+            counter_call = self._convert_counter_method(obj_expr, method_name, expr, args)
+            return counter_call or self._convert_dict_method(obj_expr, method_name, expr, args)
+        if set_type_arg(receiver_type) is not None:
+            return self._convert_set_method(obj_expr, method_name, expr, args)
+        return None
+
+    def _convert_list_method(self, obj_expr: str, method_name: str, expr: ast.Call, args: list[str]) -> str:
+        """Convert a list (or tuple) method call on a Go slice to the mgen.List* helpers.
+
+        The methods that change the list's length take it by pointer so the
+        variable sees the new length; extend() accepts any iterable whose
+        items have the list's item type.
+
+        Example:
+            xs.insert(0, x)  →  mgen.ListInsert(&xs, 0, x)
+            xs.pop()         →  mgen.ListPop(&xs)
+            xs.index(x)      →  mgen.ListIndex(xs, x)
+            xs.copy()        →  append([]int{}, xs...)
+        """
+        list_type = self._list_receiver_type(expr.func.value)
+        assert list_type is not None
+        arities = {
+            "insert": (2, 2),
+            "remove": (1, 1),
+            "pop": (0, 1),
+            "index": (1, 3),
+            "count": (1, 1),
+            "reverse": (0, 0),
+            "clear": (0, 0),
+            "copy": (0, 0),
+            "extend": (1, 1),
+        }
+        min_args, max_args = arities.get(method_name, (-1, -1))
+        if expr.keywords or not min_args <= len(args) <= max_args:
+            raise UnsupportedFeatureError(f"Unsupported list method call: {ast.unparse(expr)}")
+        if method_name == "copy":
+            return f"append({list_type}{{}}, {obj_expr}...)"
+        if method_name == "extend":
+            items_expr = self._list_items(expr.args[0], args[0], list_type, expr)
+            return f"mgen.ExtendSlice({self._address_of(obj_expr)}, {items_expr})"
+        go_name = f"List{method_name.capitalize()}"
+        receiver = self._address_of(obj_expr) if method_name in ("insert", "remove", "pop", "clear") else obj_expr
+        return f"mgen.{go_name}({', '.join([receiver, *args])})"
+
+    def _list_receiver_type(self, receiver: ast.expr) -> Optional[str]:
+        """Return the slice type of a list method's receiver, including a row of a nested list, else None."""
+        receiver_type = self._infer_type_from_value(receiver)
+        if receiver_type.startswith("[]"):
+            return receiver_type
+        if receiver_type == "mgen.PyTuple":
+            # A tuple's count() and index() are the list methods
+            return "[]interface{}"
+        if isinstance(receiver, ast.Subscript) and not isinstance(receiver.slice, ast.Slice):
+            outer_type = self._infer_type_from_value(receiver.value)
+            if outer_type.startswith("[][]"):
+                return outer_type[2:]
+        return None
+
+    def _list_items(self, source: ast.expr, code: str, list_type: str, call: ast.Call) -> str:
+        """Return code for the items of source as a slice of list_type, for xs.extend(source)."""
+        source_type = self._infer_type_from_value(source)
+        if self._is_range_call(source):
+            source_type, code = "[]int", f"{code}.ToSlice()"
+        else:
+            code = self._iterated_slice(code, source_type)
+            source_type = iterated_slice_type(source_type)
+        if source_type == list_type:
+            return code
+        if list_type == "[]interface{}":
+            return f"mgen.ToList({code})"
+        raise UnsupportedFeatureError(f"Cannot extend a {list_type} with {source_type} items: {ast.unparse(call)}")
+
+    def _convert_dict_method(self, obj_expr: str, method_name: str, expr: ast.Call, args: list[str]) -> str:
+        """Convert a dict method call on a typed *mgen.Dict.
+
+        A missing key can only yield None when the value type can hold it, so
+        d.get(k) and d.setdefault(k) need an explicit default otherwise.
+
+        Example:
+            d.get(k, 0)        →  d.GetOr(k, 0)
+            d.pop(k)           →  d.Pop(k)
+            d.items()          →  d.Items()
+        """
+        dict_types = dict_type_args(self._infer_type_from_value(expr.func.value))
+        assert dict_types is not None
+        value_type = dict_types[1]
+        if method_name in ("get", "setdefault", "pop") and expr.args:
+            args = [self._convert_key(expr.args[0], dict_types[0]), *args[1:]]
+        if method_name in ("get", "setdefault") and len(args) == 1:
+            if value_type != "interface{}":
+                raise UnsupportedFeatureError(
+                    f"dict.{method_name}() without a default can return None, which {value_type} cannot hold: "
+                    f"{ast.unparse(expr)}"
+                )
+            args = [args[0], "nil"]
+        if method_name == "update":
+            return self._convert_dict_update(obj_expr, expr, dict_types)
+        if method_name == "move_to_end" or (method_name == "popitem" and (expr.args or expr.keywords)):
+            return self._convert_ordered_dict_method(obj_expr, method_name, expr, dict_types[0])
+        go_name = DICT_METHODS.get((method_name, len(args)))
+        if go_name is None or expr.keywords:
+            raise UnsupportedFeatureError(f"Unsupported dict method call: {ast.unparse(expr)}")
+        return f"{obj_expr}.{go_name}({', '.join(args)})"
+
+    def _convert_ordered_dict_method(self, obj_expr: str, method_name: str, expr: ast.Call, key_type: str) -> str:
+        """Convert OrderedDict's move_to_end() and popitem(), whose last argument picks the end to use.
+
+        Example:
+            od.move_to_end(k)         →  od.MoveToEnd(k, true)
+            od.popitem(last=False)    →  od.PopItemEnd(false)
+        """
+        params = ("key", "last") if method_name == "move_to_end" else ("last",)
+        bound = dict(zip(params, expr.args))
+        for kw in expr.keywords:
+            if kw.arg not in params or kw.arg in bound:
+                raise UnsupportedFeatureError(f"Unsupported OrderedDict method call: {ast.unparse(expr)}")
+            bound[kw.arg] = kw.value
+        if len(expr.args) > len(params) or (method_name == "move_to_end" and "key" not in bound):
+            raise UnsupportedFeatureError(f"Unsupported OrderedDict method call: {ast.unparse(expr)}")
+        last = "true"
+        if "last" in bound:
+            last = self._convert_expression(bound["last"])
+            if self._infer_type_from_value(bound["last"]) != "bool":
+                last = f"mgen.ToBool({last})"
+        if method_name == "popitem":
+            return f"{obj_expr}.PopItemEnd({last})"
+        return f"{obj_expr}.MoveToEnd({self._convert_key(bound['key'], key_type)}, {last})"
+
+    def _convert_dict_update(self, obj_expr: str, expr: ast.Call, dict_types: tuple[str, str]) -> str:
+        """Convert d.update(...) on a typed *mgen.Dict.
+
+        A dict of d's own type is merged directly and any other mapping or
+        iterable of pairs entry by entry; keyword arguments update a dict with
+        string keys.
+
+        Example:
+            d.update(other)       →  d.Update(other)
+            d.update([("a", 1)])  →  d.UpdateFrom([]interface{}{[]interface{}{"a", 1}})
+            d.update(a=1)         →  d.Update(mgen.NewDict(mgen.KV[string, int]{Key: "a", Value: 1}))
+        """
+        if len(expr.args) + bool(expr.keywords) != 1 or any(kw.arg is None for kw in expr.keywords):
+            raise UnsupportedFeatureError(f"Unsupported dict method call: {ast.unparse(expr)}")
+        if expr.keywords:
+            if dict_types[0] != "string":
+                raise UnsupportedFeatureError(
+                    f"dict.update() keywords need string keys, not {dict_types[0]}: {ast.unparse(expr)}"
+                )
+            kv_type = f"mgen.KV[{dict_types[0]}, {dict_types[1]}]"
+            entries = ", ".join(
+                f'{kv_type}{{Key: "{kw.arg}", Value: {self._convert_expression(kw.value)}}}' for kw in expr.keywords
+            )
+            return f"{obj_expr}.Update(mgen.NewDict({entries}))"
+        source = expr.args[0]
+        if self._infer_type_from_value(source) == go_dict_type(*dict_types):
+            return f"{obj_expr}.Update({self._convert_expression(source)})"
+        return f"{obj_expr}.UpdateFrom({self._convert_expression(source)})"
+
+    def _convert_py_dict_method(self, obj_expr: str, method_name: str, expr: ast.Call, args: list[str]) -> str:
+        """Convert a dict method call on a value-hashed *mgen.PyDict (tuple or bytes keys).
+
+        Its values are interface{}, so a missing key can always yield None.
+
+        Example:
+            d.get(k)       →  d.GetOr(k, nil)
+            d.popitem()    →  d.PopItem()
+            d.update(a=1)  →  d.Update(mgen.NewPyDict(mgen.PyDictEntry{Key: "a", Value: 1}))
+        """
+        if method_name in ("get", "setdefault") and len(args) == 1:
+            args = [args[0], "nil"]
+        if method_name == "update" and expr.keywords and not expr.args and all(kw.arg for kw in expr.keywords):
+            entries = ", ".join(
+                f'mgen.PyDictEntry{{Key: "{kw.arg}", Value: {self._convert_expression(kw.value)}}}'
+                for kw in expr.keywords
+            )
+            args = [f"mgen.NewPyDict({entries})"]
+        elif expr.keywords:
+            raise UnsupportedFeatureError(f"Unsupported dict method call: {ast.unparse(expr)}")
+        go_name = DICT_METHODS.get((method_name, len(args)))
+        if go_name is None:
+            raise UnsupportedFeatureError(f"Unsupported dict method call: {ast.unparse(expr)}")
+        return f"{obj_expr}.{go_name}({', '.join(args)})"
+
+    def _convert_set_method(self, obj_expr: str, method_name: str, expr: ast.Call, args: list[str]) -> str:
+        """Convert a set method call on a typed *mgen.Set.
+
+        The operands of the set algebra may be any iterable of the member
+        type, as in Python; lists and dicts are turned into sets first. The
+        methods returning a new set take several operands and chain.
+
+        Example:
+            s.add(x)              →  s.Add(x)
+            s.union(xs, t)        →  s.Union(mgen.NewSet(xs...)).Union(t)
+            s.issubset(t)         →  s.IsSubset(t)
+        """
+        set_type = self._infer_type_from_value(expr.func.value)
+        go_names = {
+            "add": "Add",
+            "remove": "Remove",
+            "discard": "Discard",
+            "pop": "Pop",
+            "clear": "Clear",
+            "copy": "Copy",
+            "union": "Union",
+            "intersection": "Intersection",
+            "difference": "Difference",
+            "symmetric_difference": "SymmetricDifference",
+            "update": "Update",
+            "intersection_update": "IntersectionUpdate",
+            "difference_update": "DifferenceUpdate",
+            "symmetric_difference_update": "SymmetricDifferenceUpdate",
+            "issubset": "IsSubset",
+            "issuperset": "IsSuperset",
+            "isdisjoint": "IsDisjoint",
+        }
+        go_name = go_names.get(method_name)
+        if go_name is None or expr.keywords:
+            raise UnsupportedFeatureError(f"Unsupported set method call: {ast.unparse(expr)}")
+        if method_name in ("add", "remove", "discard", "pop", "clear", "copy"):
+            arity = 1 if method_name in ("add", "remove", "discard") else 0
+            if len(args) != arity:
+                raise UnsupportedFeatureError(f"set.{method_name}() takes {arity} arguments: {ast.unparse(expr)}")
+            member_type = set_type_arg(set_type)
+            assert member_type is not None
+            args = [self._convert_key(arg, member_type) for arg in expr.args]
+            return f"{obj_expr}.{go_name}({', '.join(args)})"
+        operands = [self._set_operand(arg, code, set_type) for arg, code in zip(expr.args, args)]
+        if method_name in SET_RESULT_METHODS:
+            # s.union(a, b) chains; s.union() with no operands is a copy
+            result = obj_expr if operands else f"{obj_expr}.Copy()"
+            for operand in operands:
+                result = f"{result}.{go_name}({operand})"
+            return result
+        if len(operands) != 1:
+            raise UnsupportedFeatureError(f"set.{method_name}() takes one argument: {ast.unparse(expr)}")
+        return f"{obj_expr}.{go_name}({operands[0]})"
+
+    def _set_operand(self, arg: ast.expr, code: str, set_type: str) -> str:
+        """Return an operand of the set algebra as a set of set_type, converting a typed list or dict."""
+        arg_type = self._infer_type_from_value(arg)
+        if arg_type == set_type:
+            return code
+        if iterated_slice_type(arg_type) == f"[]{set_type_arg(set_type)}":
+            return f"mgen.NewSet({self._iterated_slice(code, arg_type)}...)"
+        raise UnsupportedFeatureError(f"Set operand must be a {set_type} or a list of its members: {ast.unparse(arg)}")
+
+    def _convert_deque_call(self, expr: ast.Call, args: list[str]) -> str:
+        """Convert deque() / deque(iterable) to mgen.NewDeque.
+
+        The element type comes from the annotation of the assigned variable
+        when there is one, otherwise from the iterable.
+
+        Example:
+            q: deque[int] = deque()  →  q := mgen.NewDeque[int]()
+            deque([1, 2])            →  mgen.NewDeque[int](1, 2)
+        """
+        element_type = self.deque_values.get(id(expr))
+        if element_type is None:
+            element_type = self._infer_type_from_value(expr)[len("*mgen.Deque[") : -1]
+        if not expr.args:
+            return f"mgen.NewDeque[{element_type}]()"
+        if isinstance(expr.args[0], ast.List):
+            elements = ", ".join(self._convert_expression(elt) for elt in expr.args[0].elts)
+            return f"mgen.NewDeque[{element_type}]({elements})"
+        if self._infer_type_from_value(expr.args[0]) == f"[]{element_type}":
+            return f"mgen.NewDeque[{element_type}]({args[0]}...)"
+        raise UnsupportedFeatureError(f"deque() needs a list argument: {ast.unparse(expr)}")
+
+    def _is_user_callable(self, name: str) -> bool:
+        """Check whether name is a function or class of this module, shadowing a library name."""
+        return name in self.function_return_types or name in self.struct_info
+
+    def _convert_defaultdict_call(self, expr: ast.Call) -> str:
+        """Convert defaultdict(factory) to mgen.NewDefaultDict.
+
+        The key and value types come from the variable it initializes (its
+        annotation or later use), else from the factory. Builtin factories
+        make the zero value of the value type, and a lambda or function
+        without parameters is called for each missing key.
+
+        Example:
+            groups: defaultdict[str, list[int]] = defaultdict(list)
+                →  mgen.NewDefaultDict[string, []int](func() []int { return []int{} }, "<class 'list'>")
+            defaultdict(lambda: -1)  →  mgen.NewDefaultDict[int, int](func() int { return -1 }, "<function <lambda>>")
+        """
+        dict_types = dict_type_args(self.dict_values.get(id(expr)) or self._infer_type_from_value(expr))
+        assert dict_types is not None
+        key_type, value_type = dict_types
+        if len(expr.args) > 1:
+            raise UnsupportedFeatureError(f"defaultdict() takes only a factory: {ast.unparse(expr)}")
+        factory = expr.args[0] if expr.args else None
+        if factory is None or (isinstance(factory, ast.Constant) and factory.value is None):
+            factory_code, name = "nil", "None"
+        elif isinstance(factory, ast.Name) and factory.id in DEFAULT_FACTORY_TYPES:
+            factory_code = f"func() {value_type} {{ return {self._get_default_value(value_type)} }}"
+            name = f"<class '{factory.id}'>"
+        elif isinstance(factory, ast.Lambda) and not factory.args.args:
+            factory_code = f"func() {value_type} {{ return {self._convert_expression(factory.body)} }}"
+            name = "<function <lambda>>"
+        elif isinstance(factory, ast.Name) and self.function_param_types.get(factory.id) == []:
+            factory_code, name = factory.id, f"<function {factory.id}>"
+        else:
+            raise UnsupportedFeatureError(f"Unsupported defaultdict factory: {ast.unparse(factory)}")
+        return f'mgen.NewDefaultDict[{key_type}, {value_type}]({factory_code}, "{name}")'
+
+    def _is_ordered_dict_call(self, expr: ast.expr) -> bool:
+        """Check whether expr calls OrderedDict() or collections.OrderedDict()."""
+        if not isinstance(expr, ast.Call):
+            return False
+        if isinstance(expr.func, ast.Name):
+            return expr.func.id == "OrderedDict" and not self._is_user_callable(expr.func.id)
+        return ast.unparse(expr.func) == "collections.OrderedDict"
+
+    def _convert_ordered_dict_call(self, expr: ast.Call) -> str:
+        """Convert OrderedDict(...) to mgen.NewOrderedDict.
+
+        The key and value types come from the variable it initializes, as for
+        defaultdict(), else from the entries: a dict of those types or a list
+        of (key, value) tuples.
+
+        Example:
+            cache: OrderedDict[str, int] = OrderedDict()  →  mgen.NewOrderedDict[string, int]()
+            OrderedDict(counts)                           →  mgen.NewOrderedDict[string, int](counts.Items()...)
+            OrderedDict([("a", 1)])  →  mgen.NewOrderedDict[string, int](mgen.KV[string, int]{Key: "a", Value: 1})
+        """
+        dict_type = self.dict_values.get(id(expr)) or self._infer_type_from_value(expr)
+        dict_types = dict_type_args(dict_type)
+        assert dict_types is not None
+        key_type, value_type = dict_types
+        constructor = f"mgen.NewOrderedDict[{key_type}, {value_type}]"
+        if not expr.args and not expr.keywords:
+            return f"{constructor}()"
+        source = expr.args[0] if len(expr.args) == 1 and not expr.keywords else None
+        if source is not None and self._infer_type_from_value(source) == dict_type:
+            return f"{constructor}({self._convert_expression(source)}.Items()...)"
+        if isinstance(source, ast.List):
+            pairs = [item.elts for item in source.elts if isinstance(item, ast.Tuple) and len(item.elts) == 2]
+            if len(pairs) == len(source.elts):
+                kv_type = f"mgen.KV[{key_type}, {value_type}]"
+                entries = ", ".join(
+                    f"{kv_type}{{Key: {self._convert_key(key, key_type)}, Value: {self._convert_expression(value)}}}"
+                    for key, value in pairs
+                )
+                return f"{constructor}({entries})"
+        raise UnsupportedFeatureError(
+            f"OrderedDict() needs a {dict_type} or a list of (key, value) tuples: {ast.unparse(expr)}"
+        )
+
+    def _convert_counter_call(self, expr: ast.Call, args: list[str]) -> str:
+        """Convert Counter(iterable) to mgen.NewCounter, counting the characters of a string.
+
+        Example:
+            Counter(words)  →  mgen.NewCounter[string](words)
+            Counter("abc")  →  mgen.NewCounter[string](mgen.StrOps.Chars("abc"))
+        """
+        dict_types = dict_type_args(self.dict_values.get(id(expr)) or self._infer_type_from_value(expr))
+        assert dict_types is not None
+        key_type = dict_types[0]
+        if not expr.args:
+            return f"mgen.NewCounter[{key_type}](nil)"
+        source_type = self._infer_type_from_value(expr.args[0])
+        if len(expr.args) > 1 or expr.keywords or iterated_slice_type(source_type) != f"[]{key_type}":
+            raise UnsupportedFeatureError(f"Counter() needs a list or string of its keys: {ast.unparse(expr)}")
+        # Counter(text) counts the characters of a str, Counter(d) the keys of a dict and Counter(s) set members
+        return f"mgen.NewCounter[{key_type}]({self._iterated_slice(args[0], source_type)})"
+
+    def _convert_counter_method(
+        self, obj_expr: str, method_name: str, expr: ast.Call, args: list[str]
+    ) -> Optional[str]:
+        """Convert a Counter method, or return None for a method Counter shares with dict.
+
+        c.update() and c.subtract() take a list or string of keys to count;
+        updating from another Counter is the dict method, which adds counts,
+        as is updating from a list of anything but keys (a dict's key/value pairs).
+
+        Example:
+            c.most_common(2)  →  mgen.MostCommon(c, 2)
+            c.update(words)   →  mgen.CounterUpdate(c, words)
+        """
+        if method_name == "most_common":
+            return f"mgen.MostCommon({obj_expr}, {args[0] if args else -1})"
+        if method_name in ("total", "elements") and not args:
+            return f"mgen.Counter{method_name.capitalize()}({obj_expr})"
+        if method_name in ("update", "subtract") and len(args) == 1:
+            items_type = self._infer_type_from_value(expr.args[0])
+            dict_types = dict_type_args(self._infer_type_from_value(expr.func.value))
+            key_type = dict_types[0] if dict_types is not None else "interface{}"
+            if items_type == "string":
+                chars = self._iterated_slice(args[0], items_type)
+                return f"mgen.Counter{method_name.capitalize()}({obj_expr}, {chars})"
+            if items_type.startswith("[]") and (items_type[2:] == key_type or method_name == "subtract"):
+                return f"mgen.Counter{method_name.capitalize()}({obj_expr}, {args[0]})"
+        return None
+
+    def _convert_deque_method(self, obj_expr: str, method_name: str, expr: ast.Call, args: list[str]) -> str:
+        """Convert a collections.deque method call on a *mgen.Deque.
+
+        Lists lowered to deques keep their list spelling: xs.pop(0) is PopLeft.
+        """
+        if method_name == "pop" and len(expr.args) == 1:
+            if not (isinstance(expr.args[0], ast.Constant) and expr.args[0].value == 0):
+                raise UnsupportedFeatureError(f"deque.pop() takes no index: {ast.unparse(expr)}")
+            method_name, args = "popleft", []
+        go_names = {
+            "append": "Append",
+            "appendleft": "AppendLeft",
+            "pop": "Pop",
+            "popleft": "PopLeft",
+            "extend": "Extend",
+            "clear": "Clear",
+        }
+        if method_name not in go_names:
+            raise UnsupportedFeatureError(f"Unsupported deque method: {method_name}")
+        return f"{obj_expr}.{go_names[method_name]}({', '.join(args)})"
+
+    def _is_argument_parser_call(self, expr: ast.Call) -> bool:
+        """Whether expr constructs an argparse.ArgumentParser (or an imported ArgumentParser)."""
+        func = expr.func
+        if isinstance(func, ast.Attribute):
+            return func.attr == "ArgumentParser" and isinstance(func.value, ast.Name) and func.value.id == "argparse"
+        return (
+            isinstance(func, ast.Name)
+            and func.id == "ArgumentParser"
+            and func.id not in self.function_return_types
+            and func.id not in self.struct_info
+        )
+
+    def _convert_argument_parser_call(self, expr: ast.Call) -> str:
+        """Convert argparse.ArgumentParser(prog=..., description=...) to mgen.NewArgumentParser.
+
+        Example:
+            argparse.ArgumentParser(description="Sum numbers.")  →  mgen.NewArgumentParser("", "Sum numbers.")
+        """
+        if len(expr.args) > 1:
+            raise UnsupportedFeatureError("ArgumentParser() takes prog and description as keyword arguments")
+        options = {"prog": '""', "description": '""'}
+        if expr.args:
+            options["prog"] = self._convert_expression(expr.args[0])
+        for kw in expr.keywords:
+            if kw.arg not in options:
+                raise UnsupportedFeatureError(f"ArgumentParser() got an unsupported keyword argument '{kw.arg}'")
+            options[kw.arg] = self._convert_expression(kw.value)
+        return f"mgen.NewArgumentParser({options['prog']}, {options['description']})"
+
+    def _convert_argument_parser_method(self, obj_expr: str, method_name: str, expr: ast.Call, args: list[str]) -> str:
+        """Convert a method call on an argparse.ArgumentParser.
+
+        add_argument keywords become the fields of mgen.ArgOpts, and
+        parse_args() with no argument parses the command line:
+            parser.add_argument("-n", "--count", type=int, default=1)
+                →  parser.AddArgument("-n", mgen.ArgOpts{Aliases: []string{"--count"}, Type: "int", Default: 1})
+            parser.parse_args()  →  parser.ParseArgs(nil)
+        """
+        if method_name == "add_argument":
+            names = [arg.value for arg in expr.args if isinstance(arg, ast.Constant) and isinstance(arg.value, str)]
+            if not names or len(names) != len(expr.args):
+                raise UnsupportedFeatureError(f"add_argument() needs string names: {ast.unparse(expr)}")
+            fields = []
+            if len(names) > 1:
+                fields.append(f"Aliases: []string{{{', '.join(args[1:])}}}")
+            go_fields = {
+                "action": "Action",
+                "default": "Default",
+                "required": "Required",
+                "help": "Help",
+                "dest": "Dest",
+            }
+            for kw in expr.keywords:
+                if kw.arg == "type":
+                    if not (isinstance(kw.value, ast.Name) and kw.value.id in ("int", "float", "str")):
+                        raise UnsupportedFeatureError(
+                            f"add_argument() supports type=int, float or str: {ast.unparse(expr)}"
+                        )
+                    fields.append(f'Type: "{kw.value.id}"')
+                elif kw.arg in go_fields:
+                    fields.append(f"{go_fields[kw.arg]}: {self._convert_expression(kw.value)}")
+                else:
+                    raise UnsupportedFeatureError(f"add_argument() got an unsupported keyword argument '{kw.arg}'")
+            return f"{obj_expr}.AddArgument({args[0]}, mgen.ArgOpts{{{', '.join(fields)}}})"
+        elif method_name == "parse_args":
+            return f"{obj_expr}.ParseArgs({args[0] if args else 'nil'})"
+        elif method_name in ("print_help", "print_usage", "error"):
+            return f"{obj_expr}.{self._to_go_method_name(method_name)}({', '.join(args)})"
+        raise UnsupportedFeatureError(f"Unsupported ArgumentParser method: {method_name}")
+
+    def _is_template_call(self, expr: ast.Call) -> bool:
+        """Whether expr constructs a string.Template (or an imported Template)."""
+        func = expr.func
+        if isinstance(func, ast.Attribute):
+            return func.attr == "Template" and isinstance(func.value, ast.Name) and func.value.id == "string"
+        return isinstance(func, ast.Name) and func.id == "Template" and not self._is_user_callable(func.id)
+
+    def _convert_template_call(self, expr: ast.Call) -> str:
+        """Convert string.Template(text) to mgen.NewTemplate.
+
+        Example:
+            Template("Hello, $name")  →  mgen.NewTemplate("Hello, $name")
+        """
+        if len(expr.args) != 1 or expr.keywords:
+            raise UnsupportedFeatureError(f"Template() takes the template text: {ast.unparse(expr)}")
+        return f"mgen.NewTemplate({self._convert_expression(expr.args[0])})"
+
+    def _convert_template_method(self, obj_expr: str, method_name: str, expr: ast.Call, args: list[str]) -> str:
+        """Convert substitute() and safe_substitute() on a string.Template.
+
+        The mapping is passed as it is; keyword arguments become a PyDict of
+        their names.
+
+        Example:
+            t.substitute(values)        →  t.Substitute(values)
+            t.safe_substitute(name=x)   →  t.SafeSubstitute(mgen.NewPyDict(mgen.PyDictEntry{Key: "name", Value: x}))
+        """
+        if method_name not in ("substitute", "safe_substitute"):
+            raise UnsupportedFeatureError(f"Unsupported Template method: {method_name}")
+        go_name = self._to_go_method_name(method_name)
+        if len(args) == 1 and not expr.keywords:
+            return f"{obj_expr}.{go_name}({args[0]})"
+        if args or any(kw.arg is None for kw in expr.keywords):
+            raise UnsupportedFeatureError(
+                f"{method_name}() takes a mapping or keyword arguments, not both: {ast.unparse(expr)}"
+            )
+        entries = ", ".join(
+            f'mgen.PyDictEntry{{Key: "{kw.arg}", Value: {self._convert_expression(kw.value)}}}' for kw in expr.keywords
+        )
+        return f"{obj_expr}.{go_name}(mgen.NewPyDict({entries}))"
+
+    def _convert_codec_call(self, expr: ast.Call, args: list[str]) -> str:
+        """Convert a base64 or binascii encoding call, which takes the data alone.
+
+        Example:
+            base64.b64encode(data)  →  mgen.Base64Encode(data)
+            unhexlify("00ff")       →  mgen.Unhexlify("00ff")
+        """
+        if len(args) != 1 or expr.keywords:
+            raise UnsupportedFeatureError(f"Only the data argument is supported: {ast.unparse(expr)}")
+        return f"{codec_function(expr.func, self._is_user_callable)}({args[0]})"
+
+    def _convert_hash_constructor(self, expr: ast.Call, args: list[str]) -> str:
+        """Convert a hashlib constructor, which takes the initial data, to the runtime *mgen.Hash.
+
+        Example:
+            hashlib.sha256(data)     →  mgen.NewSHA256(data)
+            hashlib.new("md5", data) →  mgen.NewHash("md5", data)
+        """
+        constructor = hash_constructor(expr.func, self._is_user_callable)
+        if expr.keywords or len(args) > (2 if constructor == "mgen.NewHash" else 1):
+            raise UnsupportedFeatureError(f"Only the algorithm and data arguments are supported: {ast.unparse(expr)}")
+        return f"{constructor}({', '.join(args)})"
+
+    def _convert_hash_method(self, obj_expr: str, method_name: str, expr: ast.Call, args: list[str]) -> str:
+        """Convert update(), digest() and hexdigest() on a hashlib hash object.
+
+        Example:
+            h.update(chunk)  →  h.Update(chunk)
+            h.hexdigest()    →  h.HexDigest()
+        """
+        go_names = {("update", 1): "Update", ("digest", 0): "Digest", ("hexdigest", 0): "HexDigest"}
+        go_name = go_names.get((method_name, len(args)))
+        if go_name is None or expr.keywords:
+            raise UnsupportedFeatureError(f"Unsupported hash method call: {ast.unparse(expr)}")
+        return f"{obj_expr}.{go_name}({', '.join(args)})"
+
+    def _pprint_function(self, expr: ast.Call) -> Optional[str]:
+        """The pprint function expr calls (pprint.pprint, or pformat imported from pprint), or None."""
+        func = expr.func
+        if isinstance(func, ast.Attribute) and isinstance(func.value, ast.Name) and func.value.id == "pprint":
+            name = func.attr
+        elif isinstance(func, ast.Name) and func.id not in self.function_return_types:
+            name = func.id
+        else:
+            return None
+        return name if name in ("pprint", "pformat") else None
+
+    def _convert_pprint_call(self, expr: ast.Call) -> str:
+        """Convert pprint.pprint(x, indent=..., width=...) and pprint.pformat to the mgen.PPrint runtime.
+
+        Example:
+            pprint.pformat(data, width=40)  →  mgen.PFormat(data, mgen.PPrintOptions{Width: 40})
+        """
+        function = self._pprint_function(expr)
+        if len(expr.args) != 1:
+            raise UnsupportedFeatureError(f"{function}() takes one positional argument: {ast.unparse(expr)}")
+        options = []
+        for kw in expr.keywords:
+            if kw.arg not in ("indent", "width"):
+                raise UnsupportedFeatureError(f"{function}() got an unsupported keyword argument '{kw.arg}'")
+            options.append(f"{kw.arg.capitalize()}: {self._convert_expression(kw.value)}")
+        go_name = "PPrint" if function == "pprint" else "PFormat"
+        return f"mgen.{go_name}({self._convert_expression(expr.args[0])}, mgen.PPrintOptions{{{', '.join(options)}}})"
+
+    def _consume_generator_arguments(self, node: ast.Module) -> None:
+        """Turn generator expressions a builtin consumes at once into list comprehensions.
+
+        sum(x * x for x in xs) reads every item before it returns, so the items
+        can be built as a slice; generator expressions that outlive the call,
+        such as one assigned to a variable or passed to next(), stay lazy. any()
+        and all() then see every item, which only shows if producing one has
+        side effects.
+        """
+        user_functions = {item.name for item in ast.walk(node) if isinstance(item, ast.FunctionDef)}
+        for call in ast.walk(node):
+            if not isinstance(call, ast.Call):
+                continue
+            consumer = call.func.id if isinstance(call.func, ast.Name) else None
+            if not (
+                (consumer in GENERATOR_CONSUMERS and consumer not in user_functions)
+                or (isinstance(call.func, ast.Attribute) and call.func.attr == "join")
+            ):
+                continue
+            call.args = [
+                ast.copy_location(ast.ListComp(elt=arg.elt, generators=arg.generators), arg)
+                if isinstance(arg, ast.GeneratorExp)
+                else arg
+                for arg in call.args
+            ]
+
+    def _collect_argument_types(self, node: ast.Module) -> None:
+        """Record the Go type of each argparse argument so args.name reads a typed value.
+
+        Flags are bool and type=int/float/str arguments take that type. An
+        option that may be left out with no default is None then, and a
+        default of another type than the parsed value can hold either, so
+        both stay interface{}. Two parsers giving one dest different types
+        make it interface{} too.
+        """
+        for call in ast.walk(node):
+            if not (
+                isinstance(call, ast.Call)
+                and isinstance(call.func, ast.Attribute)
+                and call.func.attr == "add_argument"
+                and call.args
+                and all(isinstance(arg, ast.Constant) and isinstance(arg.value, str) for arg in call.args)
+            ):
+                continue
+            names = [arg.value for arg in call.args]
+            keywords = {kw.arg: kw.value for kw in call.keywords}
+            positional = not names[0].startswith("-")
+            dest = next((name for name in names if name.startswith("--")), names[0]).lstrip("-").replace("-", "_")
+            if isinstance(keywords.get("dest"), ast.Constant):
+                dest = keywords["dest"].value
+            action = keywords.get("action")
+            type_node = keywords.get("type")
+            default = keywords.get("default")
+            required = keywords.get("required")
+            if isinstance(action, ast.Constant) and action.value in ("store_true", "store_false"):
+                go_type = "bool"
+            else:
+                go_type = self._map_type(type_node.id) if isinstance(type_node, ast.Name) else "string"
+                if default is not None and not (isinstance(default, ast.Constant) and default.value is None):
+                    if self._infer_type_from_value(default) != go_type:
+                        go_type = "interface{}"
+                elif not positional and not (isinstance(required, ast.Constant) and required.value is True):
+                    go_type = "interface{}"
+            if self.argument_types.get(dest, go_type) != go_type:
+                go_type = "interface{}"
+            self.argument_types[dest] = go_type
+
+    def _is_str_format_call(self, expr: ast.Call) -> bool:
+        """Return whether expr is s.format(...) or s.format_map(m) on a str."""
+        return (
+            isinstance(expr.func, ast.Attribute)
+            and expr.func.attr in ("format", "format_map")
+            and self._infer_type_from_value(expr.func.value) == "string"
+        )
+
+    def _convert_str_format_call(self, expr: ast.Call, convert: Callable[[ast.expr], str]) -> str:
+        """Convert str.format() and str.format_map() to mgen.StrFormat.
+
+        Keyword arguments become a map of the named fields; a lone **mapping
+        is passed through as it is, like format_map.
+
+        Example:
+            "{} {n}".format(a, n=b)  →  mgen.StrFormat("{} {n}", map[string]interface{}{"n": b}, a)
+            s.format(*xs)            →  mgen.StrFormat(s, nil, mgen.ToList(xs)...)
+            s.format_map(d)          →  mgen.StrFormat(s, d)
+        """
+        assert isinstance(expr.func, ast.Attribute)
+        format_expr = convert(expr.func.value)
+        if expr.func.attr == "format_map":
+            if len(expr.args) != 1 or expr.keywords:
+                raise UnsupportedFeatureError(f"str.format_map() takes exactly one argument: {ast.unparse(expr)}")
+            return f"mgen.StrFormat({format_expr}, {convert(expr.args[0])})"
+        kwargs = "nil"
+        if any(keyword.arg is None for keyword in expr.keywords):
+            if len(expr.keywords) != 1:
+                raise UnsupportedFeatureError(
+                    f"str.format() with **mapping and other keyword arguments: {ast.unparse(expr)}"
+                )
+            kwargs = convert(expr.keywords[0].value)
+        elif expr.keywords:
+            fields = ", ".join(f'"{keyword.arg}": {convert(keyword.value)}' for keyword in expr.keywords)
+            kwargs = f"map[string]interface{{}}{{{fields}}}"
+        args = self._print_arguments(expr.args, convert)
+        return f"mgen.StrFormat({', '.join(part for part in (format_expr, kwargs, args) if part)})"
+
+    def _convert_string_method(
+        self, obj_expr: str, method_name: str, args: list[str], call: Optional[ast.Call] = None
+    ) -> Optional[str]:
+        """Convert a Python str method call to the mgen.StrOps runtime.
+
+        The call, when given, lets list methods with str names (count, index)
+        keep their list meaning and picks the join for the item type. Returns
+        None when the method is not a supported string method.
+        """
+        receiver_type = self._infer_type_from_value(call.func.value) if isinstance(call, ast.Call) else ""
+        list_like = receiver_type.startswith("[]") or receiver_type in ("*mgen.PyList", "mgen.PyTuple")
+        if method_name in ("count", "index") and list_like:
+            return None
+        predicates = {
+            "isdigit": "IsDigit",
+            "isalpha": "IsAlpha",
+            "isalnum": "IsAlnum",
+            "isspace": "IsSpace",
+            "isupper": "IsUpper",
+            "islower": "IsLower",
+        }
+        if method_name in predicates and not args:
+            return f"mgen.StrOps.{predicates[method_name]}({obj_expr})"
+        elif method_name in ("title", "capitalize") and not args:
+            return f"mgen.StrOps.{method_name.capitalize()}({obj_expr})"
+        elif method_name in ("startswith", "endswith") and len(args) == 1:
+            go_name = "StartsWith" if method_name == "startswith" else "EndsWith"
+            if call is not None and isinstance(call.args[0], ast.Tuple):
+                # A tuple of alternatives becomes the variadic arguments
+                args = [self._convert_expression(elt) for elt in call.args[0].elts]
+            return f"mgen.StrOps.{go_name}({obj_expr}, {', '.join(args)})"
+        elif method_name == "join" and len(args) == 1:
+            items_type = self._infer_type_from_value(call.args[0]) if call is not None else "[]string"
+            if items_type == "[]string":
+                return f"mgen.StrOps.Join({obj_expr}, {args[0]})"
+            return f"mgen.StrOps.JoinValues({obj_expr}, {args[0]})"
+        elif method_name == "count" and args:
+            return f"mgen.StrOps.Count({obj_expr}, {', '.join(args)})"
+        elif method_name in ("partition", "rpartition") and len(args) == 1:
+            go_name = "Partition" if method_name == "partition" else "RPartition"
+            return f"mgen.StrOps.{go_name}({obj_expr}, {args[0]})"
+        elif method_name == "zfill" and len(args) == 1:
+            return f"mgen.StrOps.Zfill({obj_expr}, {args[0]})"
+        elif method_name == "upper":
+            return f"mgen.StrOps.Upper({obj_expr})"
+        elif method_name == "lower":
+            return f"mgen.StrOps.Lower({obj_expr})"
+        elif method_name == "strip":
+            if args:
+                return f"mgen.StrOps.StripChars({obj_expr}, {args[0]})"
+            return f"mgen.StrOps.Strip({obj_expr})"
+        elif method_name == "find":
+            if len(args) > 1:
+                return f"mgen.StrOps.FindRange({obj_expr}, {', '.join(args)})"
+            return f"mgen.StrOps.Find({obj_expr}, {args[0]})"
+        elif method_name in ("rfind", "index", "rindex") and args:
+            # Optional start/end pass through as trailing arguments
+            go_name = {"rfind": "RFind", "index": "Index", "rindex": "RIndex"}[method_name]
+            return f"mgen.StrOps.{go_name}({obj_expr}, {', '.join(args)})"
+        elif method_name == "replace":
+            return f"mgen.StrOps.Replace({obj_expr}, {args[0]}, {args[1]})"
+        elif method_name == "split":
+            if args:
+                return f"mgen.StrOps.SplitSep({obj_expr}, {args[0]})"
+            return f"mgen.StrOps.Split({obj_expr})"
+        elif method_name in ("ljust", "rjust", "center") and args:
+            # Padding width is measured in code points, like Python; fill defaults to a space
+            fill = args[1] if len(args) > 1 else '" "'
+            go_name = {"ljust": "Ljust", "rjust": "Rjust", "center": "Center"}[method_name]
+            return f"mgen.StrOps.{go_name}({obj_expr}, {args[0]}, {fill})"
+        elif method_name == "splitlines":
+            keepends = args[0] if args else "false"
+            return f"mgen.StrOps.Splitlines({obj_expr}, {keepends})"
+        elif method_name == "expandtabs":
+            tabsize = args[0] if args else "8"
+            return f"mgen.StrOps.ExpandTabs({obj_expr}, {tabsize})"
+        elif method_name == "encode" and call is not None:
+            return f"mgen.StrOps.Encode({obj_expr}, {self._codec_arguments(call, args)})"
+        return None
+
+    def _codec_arguments(self, call: ast.Call, args: list[str]) -> str:
+        """Return the encoding and errors arguments of str.encode() or bytes.decode(), given
+        positionally or by keyword, with Python's defaults ("utf-8", "strict")."""
+        codec = dict(zip(("encoding", "errors"), args))
+        for keyword in call.keywords:
+            if keyword.arg not in ("encoding", "errors") or keyword.arg in codec:
+                raise UnsupportedFeatureError(f"Unsupported codec arguments: {ast.unparse(call)}")
+            codec[keyword.arg] = self._convert_expression(keyword.value)
+        encoding, errors = codec.get("encoding", '"utf-8"'), codec.get("errors", '"strict"')
+        return f"{encoding}, {errors}"
+
+    def _convert_bytes_method(self, obj_expr: str, method_name: str, expr: ast.Call, args: list[str]) -> str:
+        """Convert a method call on bytes or a bytearray; only a bytearray has the mutating methods.
+
+        Example:
+            data.decode()    →  data.Decode("utf-8", "strict")
+            data.hex()       →  data.Hex()
+            buf.append(x)    →  buf.Append(x)
+        """
+        if method_name == "decode":
+            return f"{obj_expr}.Decode({self._codec_arguments(expr, args)})"
+        go_names = {"hex": "Hex"}
+        if self._infer_type_from_value(expr.func.value) == "*mgen.PyByteArray":
+            go_names.update({"append": "Append", "extend": "Extend", "pop": "Pop", "clear": "Clear"})
+        if method_name not in go_names or expr.keywords:
+            raise UnsupportedFeatureError(f"Unsupported bytes method call: {ast.unparse(expr)}")
+        return f"{obj_expr}.{go_names[method_name]}({', '.join(args)})"
+
+    def _convert_bytes_constructor(
+        self, func_name: str, expr: ast.Call, args: list[str], convert: Callable[[ast.expr], str]
+    ) -> str:
+        """Convert bytes(...) or bytearray(...); encoding and errors follow the source positionally.
+
+        Example:
+            bytes(3)                  →  mgen.NewBytes(3)
+            bytearray(s, "utf-8")     →  mgen.NewByteArray(s, "utf-8")
+            bytes()                   →  mgen.PyBytes{}
+        """
+        keywords = {keyword.arg: convert(keyword.value) for keyword in expr.keywords}
+        names = ("source", "encoding", "errors")
+        values = dict(zip(names, args))
+        if set(values) & set(keywords):
+            raise UnsupportedFeatureError(f"{func_name}() got multiple values for an argument: {ast.unparse(expr)}")
+        values.update(keywords)
+        if "errors" in values and "encoding" not in values:
+            raise UnsupportedFeatureError(f"{func_name}() with errors but no encoding: {ast.unparse(expr)}")
+        if not values:
+            return "mgen.PyBytes{}" if func_name == "bytes" else "mgen.NewByteArray(nil)"
+        values.setdefault("source", "nil")
+        go_name = "NewBytes" if func_name == "bytes" else "NewByteArray"
+        return f"mgen.{go_name}({', '.join(values[name] for name in names if name in values)})"
+
     def _convert_attribute(self, expr: ast.Attribute) -> str:
-        """Convert attribute access."""
+        """Convert attribute access; properties call their getter."""
+        if is_type_name(expr):
+            return self._convert_type_name(expr, self._convert_expression)
         obj_expr = self._convert_expression(expr.value)
-        return f"{obj_expr}.{self._to_camel_case(expr.attr)}"
+        if self._infer_type_from_value(expr.value) == "*mgen.Namespace":
+            # args.count of a parsed command line, read with the getter for its type
+            getters = {"int": "GetInt", "float64": "GetFloat", "string": "GetStr", "bool": "GetBool"}
+            getter = getters.get(self.argument_types.get(expr.attr, ""), "Get")
+            return f'{obj_expr}.{getter}("{expr.attr}")'
+        hash_attributes = ("name", "digest_size", "block_size")
+        if self._infer_type_from_value(expr.value) == "*mgen.Hash" and expr.attr in hash_attributes:
+            # A hash object's attributes are read through methods: h.digest_size -> h.DigestSize()
+            return f"{obj_expr}.{self._to_camel_case(expr.attr)}()"
+        obj_expr = self._convert_instance(expr.value, obj_expr)
+        getter = self._property_getter(expr.value, expr.attr, obj_expr)
+        return getter or f"{obj_expr}.{self._to_camel_case(expr.attr)}"
+
+    def _convert_type_name(self, expr: ast.Attribute, convert: Callable[[ast.expr], str]) -> str:
+        """Convert type(x).__name__ to mgen.TypeName, which names exceptions by their class.
+
+        Example:
+            type(e).__name__  →  mgen.TypeName(e)
+        """
+        assert isinstance(expr.value, ast.Call)
+        return f"mgen.TypeName({convert(expr.value.args[0])})"
 
     def _convert_list_literal(self, expr: ast.List) -> str:
         """Convert list literal to Go slice literal."""
+        if id(expr) in self.deque_values:
+            # Initializer of a list lowered to a Deque (see _lower_list_queues)
+            elements = ", ".join(self._convert_expression(elt) for elt in expr.elts)
+            return f"mgen.NewDeque[{self.deque_values[id(expr)]}]({elements})"
+        if id(expr) in self.interface_lists:
+            # Instances of a subclassed class and its subclasses, stored as its interface
+            element_type = self.interface_lists[id(expr)]
+            elements = (
+                self._convert_class_value(elt, self._convert_expression(elt), element_type) for elt in expr.elts
+            )
+            return f"[]{element_type}{{{', '.join(elements)}}}"
         if not expr.elts:
             # Empty list - default to []int{}
             return "[]int{}"
@@ -1529,75 +8558,328 @@ class MGenPythonToGoConverter:
             # Mixed types or interface{}, use interface{}
             elements = [self._convert_expression(elt) for elt in expr.elts]
             elements_str = ", ".join(elements)
-            return f"[]interface{{{elements_str}}}"
+            return f"[]interface{{}}{{{elements_str}}}"
 
     def _convert_dict_literal(self, expr: ast.Dict) -> str:
-        """Convert dict literal to Go map literal."""
+        """Convert dict literal to an insertion-ordered mgen.Dict.
+
+        Example:
+            {"a": 1}  →  mgen.NewDict(mgen.KV[string, int]{Key: "a", Value: 1})
+        """
         if not expr.keys:
-            # Empty dict - default to map[int]int
-            return "make(map[int]int)"
-
-        # Check for None keys (dictionary unpacking with **)
-        has_unpacking = any(key is None for key in expr.keys)
-
-        if has_unpacking:
-            # Dictionary unpacking present, use interface{} for safety
-            pairs = []
-            for key, value in zip(expr.keys, expr.values):
-                if key is not None:
-                    key_str = self._convert_expression(key)
-                    value_str = self._convert_expression(value)
-                    pairs.append(f"{key_str}: {value_str}")
-                # Note: actual unpacking (**dict) would need runtime handling
-            pairs_str = ", ".join(pairs)
-            return f"map[interface{{}}]interface{{}}{{{{{pairs_str}}}}}"
-
-        # Try to infer common types for keys and values (all keys are non-None)
-        key_types = [self._infer_type_from_value(key) for key in expr.keys if key is not None]
-        value_types = [self._infer_type_from_value(value) for value in expr.values]
+            # Empty dict - default to int keys/values
+            return self._get_default_value(go_dict_type("int", "int"))
+
+        if self._infer_type_from_value(expr) == "*mgen.PyDict":
+            # Mixed tuple keys and mixed numeric keys are hashed by value, as Python does
+            entries = ", ".join(
+                f"mgen.PyDictEntry{{Key: {self._convert_expression(key)}, Value: {self._convert_expression(value)}}}"
+                for key, value in zip(expr.keys, expr.values)
+                if key is not None
+            )
+            return f"mgen.NewPyDict({entries})"
+
+        # Keys and values share a type each or are interface{}; entries with
+        # ** unpacking (a None key) are not supported and are left out
+        dict_types = dict_type_args(self._infer_type_from_value(expr))
+        assert dict_types is not None
+        kv_type = f"mgen.KV[{dict_types[0]}, {dict_types[1]}]"
+        entries = ", ".join(
+            f"{kv_type}{{Key: {self._convert_key(key, dict_types[0])}, Value: {self._convert_expression(value)}}}"
+            for key, value in zip(expr.keys, expr.values)
+            if key is not None
+        )
+        return f"mgen.NewDict({entries})"
 
-        if (
-            key_types
-            and all(t == key_types[0] and t != "interface{}" for t in key_types)
-            and value_types
-            and all(t == value_types[0] and t != "interface{}" for t in value_types)
-        ):
-            # All keys and values have specific types
-            key_type = key_types[0]
-            value_type = value_types[0]
-            pairs = []
-            for key, value in zip(expr.keys, expr.values):
-                if key is not None:
-                    key_str = self._convert_expression(key)
-                    value_str = self._convert_expression(value)
-                    pairs.append(f"{key_str}: {value_str}")
-            pairs_str = ", ".join(pairs)
-            return f"map[{key_type}]{value_type}{{{{{pairs_str}}}}}"
+    def _convert_set_literal(self, expr: ast.Set) -> str:
+        """Convert set literal to an insertion-ordered mgen.Set.
+
+        Example:
+            {1, 2}  →  mgen.NewSet[int](1, 2)
+        """
+        if self._infer_type_from_value(expr) == "*mgen.PySet":
+            # Mixed tuple members and mixed numeric members are hashed by value, as Python does
+            return f"mgen.NewPySet({', '.join(self._convert_expression(elt) for elt in expr.elts)})"
+        element_type = set_type_arg(self._infer_type_from_value(expr))
+        assert element_type is not None
+        elements = ", ".join(self._convert_key(elt, element_type) for elt in expr.elts)
+        return f"mgen.NewSet[{element_type}]({elements})"
+
+    def _convert_comprehension_expr(
+        self, expr: ast.expr, generator: ast.comprehension, loop_var_types: dict[str, str]
+    ) -> str:
+        """Convert an expression evaluated inside a comprehension's own scope.
+
+        Python 3 comprehensions bind their loop variables in a new scope, and the
+        generated Go closures take them as parameters, so while converting the
+        element and conditions the loop variables shadow any outer variable of the
+        same name (an outer Optional or bool `x` must not affect `x` in the loop).
+        """
+        bound_names = {node.id for node in ast.walk(generator.target) if isinstance(node, ast.Name)}
+        outer_types = self.variable_types
+        self.variable_types = {name: t for name, t in outer_types.items() if name not in bound_names}
+        self.variable_types.update(loop_var_types)
+        try:
+            return self._convert_expression(expr)
+        finally:
+            self.variable_types = outer_types
+
+    def _pair_source_types(self, iter_expr: ast.expr) -> Optional[tuple[str, ...]]:
+        """Return the Go field types when iter_expr yields mgen.Pair (enumerate(), zip()),
+        mgen.Triple (zip() of three), mgen.KV (dict.items()) or mgen.Tuple2 (the keys of a dict keyed by pairs)."""
+        source_type = iterated_slice_type(self._infer_type_from_value(iter_expr))
+        if source_type.startswith(("[]mgen.Pair[", "[]mgen.Triple[", "[]mgen.KV[", "[]mgen.Tuple2[")):
+            fields = self._record_fields(source_type[2:])
+            return fields[1] if fields is not None else None
+        return None
+
+    def _comprehension_source(self, iter_expr: ast.expr) -> tuple[str, str]:
+        """Return the Go slice a comprehension iterates and its element type.
+
+        enumerate() and zip() already lower to slices of mgen.Pair, and
+        mgen.Iterator sources are collected first and dicts and sets iterate
+        their keys and members, so the comprehension ops always receive a slice whatever the
+        Python iterable was.
+        """
+        source_type = self._infer_type_from_value(iter_expr)
+        container_expr = self._convert_expression(iter_expr)
+        if source_type == "*mgen.PyDict":
+            return f"{container_expr}.Keys()", "interface{}"
+        if source_type == "*mgen.PySet":
+            return f"{container_expr}.Items()", "interface{}"
+        container_expr = self._iterated_slice(container_expr, source_type)
+        source_type = iterated_slice_type(source_type)
+        return container_expr, source_type[2:] if source_type.startswith("[]") else "interface{}"
+
+    def _iterated_slice(self, code: str, go_type: str) -> str:
+        """Return the slice a loop over code walks: the keys of a typed dict, the items of a typed set or
+        deque, the collected items of an iterator, the byte values of bytes, the characters of a str."""
+        if dict_type_args(go_type) is not None:
+            return f"{code}.Keys()"
+        if set_type_arg(go_type) is not None or deque_type_arg(go_type) is not None:
+            return f"{code}.Items()"
+        if iterator_type_arg(go_type) is not None:
+            return f"mgen.Collect({code})"
+        if go_type in BYTES_TYPES:
+            return f"{code}.Ints()"
+        if go_type == "string":
+            chars = "ByteChars" if self.string_semantics == "bytes" else "Chars"
+            return f"mgen.StrOps.{chars}({code})"
+        return code
+
+    def _string_by_byte(self, expr: ast.expr) -> bool:
+        """Report whether len(), indexing and slicing may count expr's bytes rather than its code points.
+
+        They do under the "bytes" string_semantics, and for an ASCII literal,
+        whose bytes are its code points.
+        """
+        if self.string_semantics == "bytes":
+            return True
+        return isinstance(expr, ast.Constant) and isinstance(expr.value, str) and expr.value.isascii()
+
+    def _convert_string_len(self, arg: ast.expr, code: str) -> str:
+        """Convert len() of a str, which counts code points unless _string_by_byte allows Go's byte length.
+
+        Example:
+            len(name)   →  mgen.LenString(name)
+            len("abc")  →  len("abc")
+        """
+        if self._string_by_byte(arg):
+            return f"len({code})"
+        return f"mgen.LenString({code})"
+
+    def _comprehension_param(self, target: ast.expr, element_type: str, body: list[ast.expr]) -> tuple[str, str]:
+        """Return a comprehension closure's parameter and the statement unpacking it.
+
+        A tuple target over mgen.Pair, mgen.Triple or mgen.Tuple2 elements
+        unpacks First/Second(/Third), and over mgen.KV elements Key/Value; names
+        the closure body does not use are bound to _ so the Go code compiles.
+        """
+        prefix = next((prefix for prefix in RECORD_PARAMS if element_type.startswith(prefix)), None)
+        fields = self._record_fields(element_type) if prefix is not None else None
+        if isinstance(target, ast.Tuple) and prefix is not None and fields and len(target.elts) == len(fields[0]):
+            param = RECORD_PARAMS[prefix]
+            used = {node.id for expr in body for node in ast.walk(expr) if isinstance(node, ast.Name)}
+            names = [elt.id if isinstance(elt, ast.Name) and elt.id in used else "_" for elt in target.elts]
+            if all(name == "_" for name in names):
+                return f"{param} {element_type}", ""
+            values = ", ".join(f"{param}.{field}" for field in fields[0])
+            return f"{param} {element_type}", f"{', '.join(names)} := {values}; "
+        target_name = target.id if isinstance(target, ast.Name) else "x"
+        return f"{target_name} {element_type}", ""
+
+    def _comprehension_condition(self, generator: ast.comprehension, loop_var_types: dict[str, str]) -> str:
+        """Convert a for clause's if clauses to one Go condition; several ifs must all hold."""
+        return " && ".join(self._convert_comprehension_expr(cond, generator, loop_var_types) for cond in generator.ifs)
+
+    def _convert_comprehension_loops(
+        self, expr: Union[ast.ListComp, ast.SetComp, ast.DictComp, ast.GeneratorExp]
+    ) -> str:
+        """Lower a comprehension to Go loops in a closure.
+
+        Comprehensions with several for clauses and those whose types are all
+        known (see _is_typed_comprehension) run this way, as do generator
+        expressions. The clauses nest in source order and each clause's ifs are tested in
+        its own loop, so a clause can use the variables bound before it, as in
+        Python. Loop variables shadow outer variables of the same name. A
+        generator expression runs the same loops in an mgen.NewGenerator body,
+        yielding each element when it is asked for.
+
+        Example:
+            [x * y for x in xs for y in ys if x != y]  →  func() []int {
+                                                               comprehension := []int{}
+                                                               for _, x := range xs {
+                                                               for _, y := range ys {
+                                                               if !((x != y)) { continue }
+                                                               comprehension = append(comprehension, (x * y))
+                                                               }
+                                                               }
+                                                               return comprehension
+                                                           }()
+        """
+        loop_var_types = self._comprehension_loop_types(expr.generators)
+        if isinstance(expr, ast.DictComp):
+            key_type = self._comprehension_key_type(expr.key, loop_var_types)
+            value_type = self._infer_comprehension_element_type(expr.value, loop_var_types)
+            result_type = go_dict_type(key_type, value_type)
+            results: list[ast.expr] = [expr.key, expr.value]
+        elif isinstance(expr, (ast.ListComp, ast.GeneratorExp)):
+            element_type = self._infer_comprehension_element_type(expr.elt, loop_var_types)
+            result_type = f"[]{element_type}"
+            results = [expr.elt]
         else:
-            # Mixed types or interface{}, use interface{}
-            pairs = []
-            for key, value in zip(expr.keys, expr.values):
-                if key is not None:
-                    key_str = self._convert_expression(key)
-                    value_str = self._convert_expression(value)
-                    pairs.append(f"{key_str}: {value_str}")
-            pairs_str = ", ".join(pairs)
-            return f"map[interface{{}}]interface{{}}{{{{{pairs_str}}}}}"
+            element_type = self._comprehension_key_type(expr.elt, loop_var_types)
+            result_type = go_set_type(element_type)
+            results = [expr.elt]
 
-    def _convert_set_literal(self, expr: ast.Set) -> str:
-        """Convert set literal to Go map literal (sets as map[T]bool)."""
-        if not expr.elts:
-            # Empty set - default to map[int]bool
-            return "make(map[int]bool)"
-        elements = []
-        for elt in expr.elts:
-            elt_str = self._convert_expression(elt)
-            elements.append(f"{elt_str}: true")
-        elements_str = ", ".join(elements)
-        return f"map[interface{{}}]bool{{{{{elements_str}}}}}"
+        outer_types = self.variable_types
+        if isinstance(expr, ast.GeneratorExp):
+            lines = [f"{self._generator_constructor()}(func(yield func({element_type})) {{"]
+        else:
+            lines = [f"func() {result_type} {{", f"    comprehension := {self._get_default_value(result_type)}"]
+        try:
+            for i, generator in enumerate(expr.generators):
+                # Names read by this clause's ifs, the later clauses and the result
+                later = [*generator.ifs, *results]
+                for following in expr.generators[i + 1 :]:
+                    later.extend([following.iter, *following.ifs])
+                used = {node.id for part in later for node in ast.walk(part) if isinstance(node, ast.Name)}
+                lines.extend(self._comprehension_loop_header(generator, used))
+                bound = {node.id for node in ast.walk(generator.target) if isinstance(node, ast.Name)}
+                self.variable_types = {name: t for name, t in self.variable_types.items() if name not in bound}
+                self.variable_types.update({name: loop_var_types[name] for name in bound if name in loop_var_types})
+                for cond in generator.ifs:
+                    lines.append(f"    if !({self._convert_expression(cond)}) {{ continue }}")
+            if isinstance(expr, ast.DictComp):
+                key, value = self._convert_key(expr.key, key_type), self._convert_expression(expr.value)
+                lines.append(f"    comprehension.Set({key}, {value})")
+            elif isinstance(expr, ast.ListComp):
+                lines.append(f"    comprehension = append(comprehension, {self._convert_expression(expr.elt)})")
+            elif isinstance(expr, ast.GeneratorExp):
+                lines.append(f"    yield({self._convert_expression(expr.elt)})")
+            else:
+                lines.append(f"    comprehension.Add({self._convert_key(expr.elt, element_type)})")
+        finally:
+            self.variable_types = outer_types
+        lines.extend("    }" for _ in expr.generators)
+        lines.extend(["})"] if isinstance(expr, ast.GeneratorExp) else ["    return comprehension", "}()"])
+        return "\n".join(lines)
+
+    def _comprehension_loop_header(self, generator: ast.comprehension, used: set[str]) -> list[str]:
+        """Return the Go for statement (and unpacking) of one comprehension for clause."""
+        iter_expr = generator.iter
+        target = generator.target
+        if self._is_range_call(iter_expr):
+            assert isinstance(iter_expr, ast.Call)
+            counted = self._counted_range_header(iter_expr, target)
+            if counted is not None:
+                return [counted]
+            range_args = ", ".join(self._convert_expression(arg) for arg in iter_expr.args)
+            container_expr, element_type = f"mgen.NewRange({range_args}).ToSlice()", "int"
+        else:
+            container_expr, element_type = self._comprehension_source(iter_expr)
+
+        if isinstance(target, ast.Name):
+            name = target.id if target.id in used else "_"
+            if name == "_":
+                return [f"    for range {container_expr} {{"]
+            return [f"    for _, {name} := range {container_expr} {{"]
+
+        fields = self._record_fields(element_type)
+        if fields is None or not (isinstance(target, ast.Tuple) and len(target.elts) == len(fields[0])):
+            raise UnsupportedFeatureError(f"Unsupported comprehension target: {ast.unparse(target)}")
+        item_var = next((param for prefix, param in RECORD_PARAMS.items() if element_type.startswith(prefix)), "item")
+        names = [elt.id if isinstance(elt, ast.Name) and elt.id in used else "_" for elt in target.elts]
+        if all(name == "_" for name in names):
+            return [f"    for range {container_expr} {{"]
+        values = ", ".join(f"{item_var}.{field}" for field in fields[0])
+        return [f"    for _, {item_var} := range {container_expr} {{", f"    {', '.join(names)} := {values}"]
+
+    def _counted_range_header(self, range_call: ast.Call, target: ast.expr) -> Optional[str]:
+        """Return a counting Go for statement for a comprehension's range() clause, or None.
+
+        Go re-reads the stop bound on every pass, so the bounds must be names
+        or literals and the step a literal; other ranges iterate the
+        materialized slice instead.
+
+        Example:
+            for i in range(n)          →  for i := 0; i < n; i++ {
+            for i in range(9, 0, -3)   →  for i := 9; i > 0; i -= 3 {
+        """
+        args = range_call.args
+        step = constant_index(args[2]) if len(args) == 3 else 1
+        if not (
+            isinstance(target, ast.Name)
+            and 1 <= len(args) <= 3
+            and not range_call.keywords
+            and step
+            and all(
+                constant_index(arg) is not None or (isinstance(arg, ast.Name) and arg.id != target.id) for arg in args
+            )
+        ):
+            return None
+        bounds = [self._convert_expression(arg) for arg in args[:2]]
+        start, stop = ("0", bounds[0]) if len(args) == 1 else bounds
+        name = target.id
+        if name == "_":
+            self.loop_counter += 1
+            name = f"index{self.loop_counter}"
+        if step > 0:
+            update = f"{name}++" if step == 1 else f"{name} += {step}"
+            return f"    for {name} := {start}; {name} < {stop}; {update} {{"
+        update = f"{name}--" if step == -1 else f"{name} -= {-step}"
+        return f"    for {name} := {start}; {name} > {stop}; {update} {{"
+
+    def _is_typed_comprehension(self, expr: Union[ast.ListComp, ast.SetComp, ast.DictComp]) -> bool:
+        """Report whether a comprehension's loop variables and results all have concrete Go types.
+
+        Such comprehensions run as inline typed loops, which call no transform
+        or filter closure per element; the generic mgen.*Comprehension helpers
+        remain for comprehensions over dynamically typed values.
+        """
+        target = expr.generators[0].target
+        if not isinstance(target, ast.Name) and not (
+            isinstance(target, ast.Tuple) and all(isinstance(elt, ast.Name) for elt in target.elts)
+        ):
+            return False
+        loop_var_types = self._comprehension_loop_types(expr.generators)
+        if isinstance(expr, ast.DictComp):
+            result_types = [
+                self._comprehension_key_type(expr.key, loop_var_types),
+                self._infer_comprehension_element_type(expr.value, loop_var_types),
+            ]
+        elif isinstance(expr, ast.SetComp):
+            result_types = [self._comprehension_key_type(expr.elt, loop_var_types)]
+        else:
+            result_types = [self._infer_comprehension_element_type(expr.elt, loop_var_types)]
+        names = [node.id for node in ast.walk(target) if isinstance(node, ast.Name)]
+        types = [loop_var_types.get(name, "") for name in names] + result_types
+        return all(go_type and "interface{}" not in go_type for go_type in types)
 
     def _convert_list_comprehension(self, expr: ast.ListComp) -> str:
         """Convert list comprehensions using Go 1.18+ generics."""
+        if len(expr.generators) > 1 or self._is_typed_comprehension(expr):
+            return self._convert_comprehension_loops(expr)
         # Extract comprehension components
         element_expr = expr.elt
         target = expr.generators[0].target
@@ -1615,37 +8897,52 @@ class MGenPythonToGoConverter:
 
             # Create transform function with proper types
             target_name = target.id if isinstance(target, ast.Name) else "x"
-            transform_expr = self._convert_expression(element_expr)
+            transform_expr = self._convert_comprehension_expr(element_expr, expr.generators[0], loop_var_types)
             transform_lambda = f"func({target_name} int) {result_type} {{ return {transform_expr} }}"
 
             if conditions:
                 # With condition
-                condition_expr = self._convert_expression(conditions[0])
+                condition_expr = self._comprehension_condition(expr.generators[0], loop_var_types)
                 condition_lambda = f"func({target_name} int) bool {{ return {condition_expr} }}"
                 return f"mgen.ListComprehensionFromRangeWithFilter[{result_type}]({range_call}, {transform_lambda}, {condition_lambda})"
             else:
                 # No condition
                 return f"mgen.ListComprehensionFromRange[{result_type}]({range_call}, {transform_lambda})"
         else:
-            # Container iteration - need to infer source type
-            source_type = self._infer_type_from_value(iter_expr)
-            # Extract element type from slice type (e.g., []int -> int)
-            element_type = source_type[2:] if source_type.startswith("[]") else "interface{}"
-
-            container_expr = self._convert_expression(iter_expr)
-            target_name = target.id if isinstance(target, ast.Name) else "x"
-            transform_expr = self._convert_expression(element_expr)
-            transform_lambda = f"func({target_name} {element_type}) {result_type} {{ return {transform_expr} }}"
+            # Container iteration (slices, enumerate/zip pairs, iterators)
+            container_expr, element_type = self._comprehension_source(iter_expr)
+            param, unpack = self._comprehension_param(target, element_type, [element_expr])
+            transform_expr = self._convert_comprehension_expr(element_expr, expr.generators[0], loop_var_types)
+            transform_lambda = f"func({param}) {result_type} {{ {unpack}return {transform_expr} }}"
 
             if conditions:
-                condition_expr = self._convert_expression(conditions[0])
-                condition_lambda = f"func({target_name} {element_type}) bool {{ return {condition_expr} }}"
+                param, unpack = self._comprehension_param(target, element_type, conditions)
+                condition_expr = self._comprehension_condition(expr.generators[0], loop_var_types)
+                condition_lambda = f"func({param}) bool {{ {unpack}return {condition_expr} }}"
                 return f"mgen.ListComprehensionWithFilter[{element_type}, {result_type}]({container_expr}, {transform_lambda}, {condition_lambda})"
             else:
                 return f"mgen.ListComprehension[{element_type}, {result_type}]({container_expr}, {transform_lambda})"
 
+    def _comprehension_key_type(self, key: ast.expr, loop_var_types: dict[str, str]) -> str:
+        """Infer the type of a dict comprehension key or set comprehension member; (x, y) may be a Tuple2."""
+
+        def infer(item: ast.expr) -> str:
+            return self._infer_comprehension_element_type(item, loop_var_types)
+
+        return tuple_key_type([key], infer) or infer(key)
+
+    def _convert_comprehension_key(
+        self, key: ast.expr, key_type: str, generator: ast.comprehension, loop_var_types: dict[str, str]
+    ) -> str:
+        """Convert a dict comprehension key or set comprehension member to key_type (see _convert_key)."""
+        return self._convert_key(
+            key, key_type, lambda item: self._convert_comprehension_expr(item, generator, loop_var_types)
+        )
+
     def _convert_dict_comprehension(self, expr: ast.DictComp) -> str:
         """Convert dictionary comprehensions using Go 1.18+ generics."""
+        if len(expr.generators) > 1 or self._is_typed_comprehension(expr):
+            return self._convert_comprehension_loops(expr)
         # Extract comprehension components
         key_expr = expr.key
         value_expr = expr.value
@@ -1654,7 +8951,7 @@ class MGenPythonToGoConverter:
 
         # Infer key and value types with loop variable context
         loop_var_types = self._infer_loop_variable_type(expr.generators[0])
-        key_type = self._infer_comprehension_element_type(key_expr, loop_var_types)
+        key_type = self._comprehension_key_type(key_expr, loop_var_types)
         value_type = self._infer_comprehension_element_type(value_expr, loop_var_types)
 
         if isinstance(iter_expr, ast.Call) and isinstance(iter_expr.func, ast.Name) and iter_expr.func.id == "range":
@@ -1662,57 +8959,52 @@ class MGenPythonToGoConverter:
             range_call = f"mgen.NewRange({', '.join(range_args)})"
             target_name = target.id if isinstance(target, ast.Name) else "x"
 
-            key_transform = self._convert_expression(key_expr)
-            value_transform = self._convert_expression(value_expr)
+            key_transform = self._convert_comprehension_key(key_expr, key_type, expr.generators[0], loop_var_types)
+            value_transform = self._convert_comprehension_expr(value_expr, expr.generators[0], loop_var_types)
             transform_lambda = (
                 f"func({target_name} int) ({key_type}, {value_type}) {{ return {key_transform}, {value_transform} }}"
             )
 
+            if expr.generators[0].ifs:
+                condition_expr = self._comprehension_condition(expr.generators[0], loop_var_types)
+                filter_lambda = f"func({target_name} int) bool {{ return {condition_expr} }}"
+                return f"mgen.DictComprehensionFromRangeWithFilter[{key_type}, {value_type}]({range_call}, {transform_lambda}, {filter_lambda})"
             return f"mgen.DictComprehensionFromRange[{key_type}, {value_type}]({range_call}, {transform_lambda})"
+        elif self._pair_source_types(iter_expr) is not None:
+            # enumerate()/zip()/dict.items() sources: {i: x for i, x in enumerate(xs)}
+            container_expr, element_type = self._comprehension_source(iter_expr)
+            param, unpack = self._comprehension_param(target, element_type, [key_expr, value_expr])
+            key_transform = self._convert_comprehension_key(key_expr, key_type, expr.generators[0], loop_var_types)
+            value_transform = self._convert_comprehension_expr(value_expr, expr.generators[0], loop_var_types)
+            transform_lambda = (
+                f"func({param}) ({key_type}, {value_type}) {{ {unpack}return {key_transform}, {value_transform} }}"
+            )
+            conditions = expr.generators[0].ifs
+            if conditions:
+                param, unpack = self._comprehension_param(target, element_type, conditions)
+                condition_expr = self._comprehension_condition(expr.generators[0], loop_var_types)
+                filter_lambda = f"func({param}) bool {{ {unpack}return {condition_expr} }}"
+                return f"mgen.DictComprehensionWithFilter[{element_type}, {key_type}, {value_type}]({container_expr}, {transform_lambda}, {filter_lambda})"
+            return f"mgen.DictComprehension[{element_type}, {key_type}, {value_type}]({container_expr}, {transform_lambda})"
         else:
-            # Handle tuple unpacking for dict iteration: {k: v for k, v in dict.items()}
-            if isinstance(target, ast.Tuple) and len(target.elts) == 2:
-                # Tuple unpacking from .items()
-                key_var = target.elts[0].id if isinstance(target.elts[0], ast.Name) else "k"
-                value_var = target.elts[1].id if isinstance(target.elts[1], ast.Name) else "v"
-
-                # Convert dict.items() to mgen.MapItems() call that returns []KV struct
-                container_expr = self._convert_expression(iter_expr)
-                key_transform = self._convert_expression(key_expr)
-                value_transform = self._convert_expression(value_expr)
-
-                # For Go, we need to convert map to slice of key-value pairs
-                # The mgen.MapItems() function will handle this
-                transform_lambda = f"func(kv mgen.KV[{key_type}, {value_type}]) ({key_type}, {value_type}) {{ {key_var}, {value_var} := kv.Key, kv.Value; return {key_transform}, {value_transform} }}"
-
-                # Check if we need to handle filtering
-                conditions = expr.generators[0].ifs
-                if conditions:
-                    condition_expr = self._convert_expression(conditions[0])
-                    # Detect which variables are used in the condition
-                    key_used = key_var in condition_expr
-                    value_used = value_var in condition_expr
-                    key_assign = key_var if key_used else "_"
-                    value_assign = value_var if value_used else "_"
-                    filter_lambda = f"func(kv mgen.KV[{key_type}, {value_type}]) bool {{ {key_assign}, {value_assign} := kv.Key, kv.Value; return {condition_expr} }}"
-                    return f"mgen.DictComprehensionWithFilter[mgen.KV[{key_type}, {value_type}], {key_type}, {value_type}]({container_expr}, {transform_lambda}, {filter_lambda})"
-                else:
-                    return f"mgen.DictComprehension[mgen.KV[{key_type}, {value_type}], {key_type}, {value_type}]({container_expr}, {transform_lambda})"
-            else:
-                # Non-tuple unpacking case
-                source_type = self._infer_type_from_value(iter_expr)
-                element_type = source_type[2:] if source_type.startswith("[]") else "interface{}"
-
-                container_expr = self._convert_expression(iter_expr)
-                target_name = target.id if isinstance(target, ast.Name) else "x"
-                key_transform = self._convert_expression(key_expr)
-                value_transform = self._convert_expression(value_expr)
-                transform_lambda = f"func({target_name} {element_type}) ({key_type}, {value_type}) {{ return {key_transform}, {value_transform} }}"
+            if isinstance(target, ast.Tuple):
+                raise UnsupportedFeatureError(f"Unsupported comprehension target: {ast.unparse(target)}")
+            container_expr, element_type = self._comprehension_source(iter_expr)
+            target_name = target.id if isinstance(target, ast.Name) else "x"
+            key_transform = self._convert_comprehension_key(key_expr, key_type, expr.generators[0], loop_var_types)
+            value_transform = self._convert_comprehension_expr(value_expr, expr.generators[0], loop_var_types)
+            transform_lambda = f"func({target_name} {element_type}) ({key_type}, {value_type}) {{ return {key_transform}, {value_transform} }}"
 
-                return f"mgen.DictComprehension[{element_type}, {key_type}, {value_type}]({container_expr}, {transform_lambda})"
+            if expr.generators[0].ifs:
+                condition_expr = self._comprehension_condition(expr.generators[0], loop_var_types)
+                filter_lambda = f"func({target_name} {element_type}) bool {{ return {condition_expr} }}"
+                return f"mgen.DictComprehensionWithFilter[{element_type}, {key_type}, {value_type}]({container_expr}, {transform_lambda}, {filter_lambda})"
+            return f"mgen.DictComprehension[{element_type}, {key_type}, {value_type}]({container_expr}, {transform_lambda})"
 
     def _convert_set_comprehension(self, expr: ast.SetComp) -> str:
         """Convert set comprehensions using Go 1.18+ generics."""
+        if len(expr.generators) > 1 or self._is_typed_comprehension(expr):
+            return self._convert_comprehension_loops(expr)
         # Extract comprehension components
         element_expr = expr.elt
         target = expr.generators[0].target
@@ -1720,105 +9012,215 @@ class MGenPythonToGoConverter:
 
         # Infer element type for the set with loop variable context
         loop_var_types = self._infer_loop_variable_type(expr.generators[0])
-        element_type = self._infer_comprehension_element_type(element_expr, loop_var_types)
+        element_type = self._comprehension_key_type(element_expr, loop_var_types)
 
         if isinstance(iter_expr, ast.Call) and isinstance(iter_expr.func, ast.Name) and iter_expr.func.id == "range":
             range_args = [self._convert_expression(arg) for arg in iter_expr.args]
             range_call = f"mgen.NewRange({', '.join(range_args)})"
             target_name = target.id if isinstance(target, ast.Name) else "x"
-            transform_expr = self._convert_expression(element_expr)
+            transform_expr = self._convert_comprehension_key(
+                element_expr, element_type, expr.generators[0], loop_var_types
+            )
             transform_lambda = f"func({target_name} int) {element_type} {{ return {transform_expr} }}"
 
+            if expr.generators[0].ifs:
+                condition_expr = self._comprehension_condition(expr.generators[0], loop_var_types)
+                filter_lambda = f"func({target_name} int) bool {{ return {condition_expr} }}"
+                return f"mgen.SetComprehensionFromRangeWithFilter[{element_type}]({range_call}, {transform_lambda}, {filter_lambda})"
             return f"mgen.SetComprehensionFromRange[{element_type}]({range_call}, {transform_lambda})"
+        elif self._pair_source_types(iter_expr) is not None:
+            # enumerate()/zip() sources: {x * i for i, x in enumerate(xs)}
+            container_expr, source_element_type = self._comprehension_source(iter_expr)
+            param, unpack = self._comprehension_param(target, source_element_type, [element_expr])
+            transform_expr = self._convert_comprehension_key(
+                element_expr, element_type, expr.generators[0], loop_var_types
+            )
+            transform_lambda = f"func({param}) {element_type} {{ {unpack}return {transform_expr} }}"
+            if expr.generators[0].ifs:
+                param, unpack = self._comprehension_param(target, source_element_type, expr.generators[0].ifs)
+                condition_expr = self._comprehension_condition(expr.generators[0], loop_var_types)
+                filter_lambda = f"func({param}) bool {{ {unpack}return {condition_expr} }}"
+                return f"mgen.SetComprehensionWithFilter[{source_element_type}, {element_type}]({container_expr}, {transform_lambda}, {filter_lambda})"
+            return f"mgen.SetComprehension[{source_element_type}, {element_type}]({container_expr}, {transform_lambda})"
         else:
             source_type = self._infer_type_from_value(iter_expr)
             container_expr = self._convert_expression(iter_expr)
             target_name = target.id if isinstance(target, ast.Name) else "x"
-            transform_expr = self._convert_expression(element_expr)
+            transform_expr = self._convert_comprehension_key(
+                element_expr, element_type, expr.generators[0], loop_var_types
+            )
 
-            # Extract element type from source (handle both slices and sets)
+            # Extract element type from source
             if source_type.startswith("[]"):
                 # Slice type: []int → int
                 source_element_type = source_type[2:]
                 transform_lambda = (
                     f"func({target_name} {source_element_type}) {element_type} {{ return {transform_expr} }}"
                 )
-                return f"mgen.SetComprehension[{source_element_type}, {element_type}]({container_expr}, {transform_lambda})"
-            elif source_type.startswith("map[") and source_type.endswith("]bool"):
-                # Set type: map[int]bool → int - use SetComprehensionFromSet
-                source_element_type = source_type[4:-5]  # Remove "map[" prefix and "]bool" suffix
-                transform_lambda = (
-                    f"func({target_name} {source_element_type}) {element_type} {{ return {transform_expr} }}"
-                )
-
-                # Check if there's a filter condition
                 if expr.generators[0].ifs:
-                    # Has filter - use SetComprehensionFromSetWithFilter
-                    filter_conditions = " && ".join(
-                        [self._convert_expression(if_expr) for if_expr in expr.generators[0].ifs]
-                    )
-                    filter_lambda = f"func({target_name} {source_element_type}) bool {{ return {filter_conditions} }}"
-                    return f"mgen.SetComprehensionFromSetWithFilter[{source_element_type}, {element_type}]({container_expr}, {filter_lambda}, {transform_lambda})"
-                else:
-                    # No filter - use SetComprehensionFromSet
-                    return f"mgen.SetComprehensionFromSet[{source_element_type}, {element_type}]({container_expr}, {transform_lambda})"
+                    condition_expr = self._comprehension_condition(expr.generators[0], loop_var_types)
+                    filter_lambda = f"func({target_name} {source_element_type}) bool {{ return {condition_expr} }}"
+                    return f"mgen.SetComprehensionWithFilter[{source_element_type}, {element_type}]({container_expr}, {transform_lambda}, {filter_lambda})"
+                return f"mgen.SetComprehension[{source_element_type}, {element_type}]({container_expr}, {transform_lambda})"
             else:
-                source_element_type = "interface{}"
+                container_expr, source_element_type = self._comprehension_source(iter_expr)
                 transform_lambda = (
                     f"func({target_name} {source_element_type}) {element_type} {{ return {transform_expr} }}"
                 )
+                if expr.generators[0].ifs:
+                    condition_expr = self._comprehension_condition(expr.generators[0], loop_var_types)
+                    filter_lambda = f"func({target_name} {source_element_type}) bool {{ return {condition_expr} }}"
+                    return f"mgen.SetComprehensionWithFilter[{source_element_type}, {element_type}]({container_expr}, {transform_lambda}, {filter_lambda})"
                 return f"mgen.SetComprehension[{source_element_type}, {element_type}]({container_expr}, {transform_lambda})"
 
-    def _convert_subscript(self, expr: ast.Subscript) -> str:
-        """Convert subscript operation to Go array/map access."""
-        value_expr = self._convert_expression(expr.value)
+    def _convert_subscript(self, expr: ast.Subscript, convert: Optional[Callable[[ast.expr], str]] = None) -> str:
+        """Convert subscript operation to Go array/map access.
+
+        convert converts the operands (_convert_expression by default, or the
+        method context's converter).
+        """
+        convert = convert or self._convert_expression
+        value_expr = convert(expr.value)
 
         if isinstance(expr.slice, ast.Slice):
-            # Handle slicing
-            raise UnsupportedFeatureError("Slice operations not supported in Go backend")
+            return self._convert_slice(expr, value_expr, convert)
         else:
             # Simple subscript
-            index_expr = self._convert_expression(expr.slice)
+            index_expr = convert(expr.slice)
+            operator_call = self._convert_operator_call(
+                expr.value, value_expr, "__getitem__", [(expr.slice, index_expr)]
+            )
+            if operator_call is not None:
+                return operator_call
+            value_type = self._infer_type_from_value(expr.value)
+            dict_types = dict_type_args(value_type)
+            if dict_types is not None:
+                return f"{value_expr}.Get({self._convert_key(expr.slice, dict_types[0], convert)})"
+            record_fields = self._record_fields(value_type)
+            if record_fields is not None:
+                # A record indexed like the tuple it stands for: a dict.items() pair kv[1] -> kv.Value,
+                # a Tuple2/Tuple3 key read back from a dict or set t[0] -> t.First, a namedtuple p[0] -> p.X
+                index = constant_index(expr.slice)
+                names = record_fields[0]
+                if index is None or not -len(names) <= index < len(names):
+                    raise UnsupportedFeatureError(f"Tuple index must be a constant in range: {ast.unparse(expr)}")
+                return f"{value_expr}.{names[index % len(names)]}"
+            if value_type == "*mgen.PyDict" or value_type.startswith("*mgen.Deque["):
+                return f"{value_expr}.Get({index_expr})"
+            if value_type in BYTES_TYPES:
+                # Indexing bytes gives an int and counts negative indices from the end
+                return f"{value_expr}.At({index_expr})"
+            if value_type == "string":
+                # Indexing a str gives a one-character str
+                at = "StrByteAt" if self._string_by_byte(expr.value) else "StrAt"
+                return f"mgen.{at}({value_expr}, {index_expr})"
+            if self._is_dynamic_value(expr.value):
+                # An untyped value, such as a tuple item of a list, is indexed by the runtime
+                return f"mgen.GetItem({value_expr}, {index_expr})"
             return f"{value_expr}[{index_expr}]"
 
-    def _convert_f_string(self, expr: ast.JoinedStr) -> str:
-        """Convert f-string to Go fmt.Sprintf.
+    def _is_dynamic_value(self, expr: ast.expr) -> bool:
+        """Report whether expr is held as interface{} in Go: an untyped variable or an item read from one.
+
+        Other expressions whose type is not inferred keep their Go type, so
+        their operators stay native.
+        """
+        if isinstance(expr, ast.Name):
+            return self.variable_types.get(expr.id) == "interface{}" and expr.id not in self.reference_lists
+        if isinstance(expr, ast.Subscript) and not isinstance(expr.slice, ast.Slice):
+            return self._is_dynamic_value(expr.value) and self._infer_type_from_value(expr) == "interface{}"
+        return False
+
+    def _convert_slice(self, expr: ast.Subscript, value_expr: str, convert: Callable[[ast.expr], str]) -> str:
+        """Convert xs[start:stop:step] to a runtime helper returning a copy.
+
+        A Go reslice xs[a:b] would share the source's backing array, so
+        writes through the result would change the original; Python slicing
+        returns a new list.
 
         Example:
-            f"Result: {x}" -> fmt.Sprintf("Result: %v", x)
-            f"Count: {len(items)} items" -> fmt.Sprintf("Count: %v items", len(items))
+            items[1:]         →  mgen.SliceSlice(items, mgen.NewSlice(1, nil, nil))
+            name[::-1]        →  mgen.SliceString(name, mgen.NewSlice(nil, nil, -1))
         """
-        # Build format string and arguments list
-        format_parts: list[str] = []
-        args: list[str] = []
+        assert isinstance(expr.slice, ast.Slice)
+        bounds = [
+            convert(bound) if bound is not None else "nil"
+            for bound in (expr.slice.lower, expr.slice.upper, expr.slice.step)
+        ]
+        py_slice = f"mgen.NewSlice({', '.join(bounds)})"
+        value_type = self._infer_type_from_value(expr.value)
+        if value_type == "string":
+            slice_string = "SliceStringBytes" if self._string_by_byte(expr.value) else "SliceString"
+            return f"mgen.{slice_string}({value_expr}, {py_slice})"
+        if value_type.startswith("[]"):
+            return f"mgen.SliceSlice({value_expr}, {py_slice})"
+        if value_type == "mgen.PyTuple":
+            # Slicing a tuple gives a tuple
+            return f"mgen.PyTuple(mgen.SliceSlice({value_expr}, {py_slice}))"
+        if value_type == "*mgen.PyList" or value_type in BYTES_TYPES:
+            return f"{value_expr}.Slice({py_slice})"
+        raise UnsupportedFeatureError(f"Cannot slice a value of unknown type: {ast.unparse(expr)}")
+
+    def _convert_f_string(self, expr: ast.JoinedStr, convert: Optional[Callable[[ast.expr], str]] = None) -> str:
+        """Convert f-string to a concatenation of literals and mgen.Format calls.
+
+        Each field is formatted like Python's format(value, spec), so classes
+        defining __format__ control their own output.
+
+        Example:
+            f"Result: {x}" -> "Result: " + mgen.Format(x, "")
+            f"Total: {price:>8.2f}" -> "Total: " + mgen.Format(price, ">8.2f")
+            f"{name!r:>10}" -> mgen.Format(mgen.Repr(name), ">10")
+        """
+        convert = convert or self._convert_expression
+        parts: list[str] = []
 
         for value in expr.values:
             if isinstance(value, ast.Constant):
-                # Literal string part - escape % signs
                 if isinstance(value.value, str):
-                    literal = value.value.replace("%", "%%")
-                    format_parts.append(literal)
+                    parts.append(self._convert_constant(value))
             elif isinstance(value, ast.FormattedValue):
-                # Expression to be formatted - use %v (value in default format)
-                format_parts.append("%v")
-                expr_code = self._convert_expression(value.value)
-                args.append(expr_code)
-
-        format_string = "".join(format_parts)
+                expr_code = convert(value.value)
+                if value.conversion != -1:
+                    # !s, !r and !a apply before the spec, as in Python
+                    conversion = {"s": "ToStr", "r": "Repr", "a": "Ascii"}[chr(value.conversion)]
+                    expr_code = f"mgen.{conversion}({expr_code})"
+                if value.format_spec is None:
+                    spec = '""'
+                elif isinstance(value.format_spec, ast.JoinedStr):
+                    # Nested fields in the spec (f"{x:{width}}") are formatted first
+                    spec = self._convert_f_string(value.format_spec, convert)
+                else:
+                    spec = convert(value.format_spec)
+                parts.append(f"mgen.Format({expr_code}, {spec})")
 
-        if len(args) == 0:
-            # No expressions, just return string literal
-            return f'"{format_string}"'
-        else:
-            # Use fmt.Sprintf
-            args_str = ", ".join(args)
-            return f'fmt.Sprintf("{format_string}", {args_str})'
+        if not parts:
+            return '""'
+        return " + ".join(parts)
 
     # Helper methods for type inference and mapping
 
     def _map_type_annotation(self, annotation: ast.expr) -> str:
         """Map Python type annotation to Go type."""
+        if isinstance(annotation, ast.Constant) and isinstance(annotation.value, str):
+            # A forward reference ("Shape") maps like the annotation it quotes
+            try:
+                annotation = ast.parse(annotation.value, mode="eval").body
+            except SyntaxError:
+                return annotation.value
+        optional_inner = self._optional_inner_annotation(annotation)
+        if optional_inner is not None:
+            # Optional[T] / T | None -> *T (nil represents None)
+            inner_type = self._map_type_annotation(optional_inner)
+            if self._interface_class(inner_type):
+                return inner_type  # A nil interface represents None
+            return f"*{inner_type}" if inner_type and inner_type != "interface{}" else "interface{}"
         if isinstance(annotation, ast.Name):
+            if annotation.id in self.class_interfaces:
+                # Values of a class other classes derive from may be instances of those classes
+                return self.class_interfaces[annotation.id]
+            if any(annotation.id in names for names in (self.struct_info, self.class_names, self.type_params)):
+                return annotation.id
             return self.type_map.get(annotation.id, "interface{}")
         elif isinstance(annotation, ast.Subscript):
             # Handle subscripted types like list[int], dict[str, int], etc.
@@ -1828,25 +9230,60 @@ class MGenPythonToGoConverter:
                     # list[int] -> []int, list[list[int]] -> [][]int
                     if isinstance(annotation.slice, ast.Name):
                         element_type = self.type_map.get(annotation.slice.id, annotation.slice.id)
+                        element_type = self.class_interfaces.get(annotation.slice.id, element_type)
+                        if annotation.slice.id in self.type_vars and annotation.slice.id not in self.type_params:
+                            element_type = "interface{}"
                         return f"[]{element_type}"
-                    elif isinstance(annotation.slice, ast.Subscript):
-                        # Recursively handle nested lists like list[list[int]]
+                    elif isinstance(annotation.slice, (ast.Subscript, ast.Constant)):
+                        # Recursively handle nested lists like list[list[int]], and list["Shape"]
                         element_type = self._map_type_annotation(annotation.slice)
                         return f"[]{element_type}"
                     return "[]interface{}"
-                elif container_type == "dict":
-                    # dict[str, int] -> map[string]int
+                elif container_type in ("dict", "defaultdict", "DefaultDict", "OrderedDict"):
+                    # dict[str, int] -> *mgen.Dict[string, int], which keeps insertion order; a
+                    # defaultdict is a Dict that fills in missing keys and an OrderedDict one
+                    # that can reorder them
+                    if isinstance(annotation.slice, ast.Tuple) and self._is_tuple_annotation(annotation.slice.elts[0]):
+                        # dict[tuple[int, int], V] -> *mgen.Dict[mgen.Tuple2[int, int], V]; other
+                        # tuple keys (tuple[int, ...]) need the value-hashed *mgen.PyDict
+                        key_type = self._tuple_annotation_type(annotation.slice.elts[0])
+                        if key_type is None or len(annotation.slice.elts) != 2:
+                            return "*mgen.PyDict"
+                        return go_dict_type(key_type, self._map_type_annotation(annotation.slice.elts[1]))
                     if isinstance(annotation.slice, ast.Tuple) and len(annotation.slice.elts) == 2:
                         key_type = self._map_type_annotation(annotation.slice.elts[0])
                         value_type = self._map_type_annotation(annotation.slice.elts[1])
-                        return f"map[{key_type}]{value_type}"
-                    return "map[interface{}]interface{}"
+                        if key_type in BYTES_TYPES:
+                            # bytes are Go slices, which only a value-hashed dict can use as keys
+                            return "*mgen.PyDict"
+                        return go_dict_type(key_type, value_type)
+                    return go_dict_type("interface{}", "interface{}")
                 elif container_type == "set":
-                    # set[int] -> map[int]bool
+                    # set[int] -> *mgen.Set[int], set[tuple[int, int]] -> *mgen.Set[mgen.Tuple2[int, int]]
+                    if self._is_tuple_annotation(annotation.slice):
+                        member_type = self._tuple_annotation_type(annotation.slice)
+                        return go_set_type(member_type) if member_type is not None else "*mgen.PySet"
                     if isinstance(annotation.slice, ast.Name):
                         element_type = self.type_map.get(annotation.slice.id, annotation.slice.id)
-                        return f"map[{element_type}]bool"
-                    return "map[interface{}]bool"
+                        element_type = self.class_interfaces.get(annotation.slice.id, element_type)
+                        if annotation.slice.id in self.type_vars and annotation.slice.id not in self.type_params:
+                            element_type = "interface{}"
+                        return "*mgen.PySet" if element_type in BYTES_TYPES else go_set_type(element_type)
+                    return go_set_type("interface{}")
+                elif container_type == "Counter":
+                    # Counter[str] -> *mgen.Dict[string, int], whose missing keys count 0
+                    return go_dict_type(self._map_type_annotation(annotation.slice), "int")
+                elif container_type in ("deque", "Deque"):
+                    # deque[int] -> *mgen.Deque[int]
+                    return f"*mgen.Deque[{self._map_type_annotation(annotation.slice)}]"
+                elif container_type == "Callable":
+                    # Callable[[int, str], bool] -> func(int, string) bool
+                    if isinstance(annotation.slice, ast.Tuple) and len(annotation.slice.elts) == 2:
+                        arg_list, result = annotation.slice.elts
+                        if isinstance(arg_list, ast.List):
+                            arg_types = ", ".join(self._map_type_annotation(arg) for arg in arg_list.elts)
+                            result_type = self._map_type_annotation(result)
+                            return f"func({arg_types}) {result_type}".rstrip()
             return "interface{}"
         elif isinstance(annotation, ast.Constant):
             if annotation.value is None:
@@ -1855,6 +9292,48 @@ class MGenPythonToGoConverter:
         else:
             return "interface{}"
 
+    def _is_tuple_annotation(self, annotation: ast.expr) -> bool:
+        """Check for tuple / tuple[...] / Tuple[...] annotations."""
+        if isinstance(annotation, ast.Subscript):
+            annotation = annotation.value
+        return isinstance(annotation, ast.Name) and annotation.id in ("tuple", "Tuple")
+
+    def _map_return_annotation(self, annotation: ast.expr) -> str:
+        """Map a return annotation; tuple[A, B] of scalars returns a Tuple2 its callers unpack through its fields."""
+        tuple_type = self._tuple_annotation_type(annotation) if self._is_tuple_annotation(annotation) else None
+        return tuple_type or self._map_type_annotation(annotation)
+
+    def _tuple_annotation_type(self, annotation: ast.expr) -> Optional[str]:
+        """Return the Tuple2/Tuple3 type of a tuple[A, B] or tuple[A, B, C] annotation of scalars, or None."""
+        if not (isinstance(annotation, ast.Subscript) and isinstance(annotation.slice, ast.Tuple)):
+            return None
+        item_types = [self._map_type_annotation(item) for item in annotation.slice.elts]
+        return go_tuple_type(item_types)
+
+    def _optional_inner_annotation(self, annotation: ast.expr) -> Optional[ast.expr]:
+        """Return T for Optional[T], typing.Optional[T], Union[T, None] or T | None annotations."""
+
+        def is_none(node: ast.expr) -> bool:
+            return isinstance(node, ast.Constant) and node.value is None
+
+        if isinstance(annotation, ast.BinOp) and isinstance(annotation.op, ast.BitOr):
+            if is_none(annotation.right):
+                return annotation.left
+            if is_none(annotation.left):
+                return annotation.right
+        elif isinstance(annotation, ast.Subscript):
+            name = annotation.value
+            type_name = name.id if isinstance(name, ast.Name) else name.attr if isinstance(name, ast.Attribute) else ""
+            if type_name == "Optional":
+                return annotation.slice
+            if type_name == "Union" and isinstance(annotation.slice, ast.Tuple) and len(annotation.slice.elts) == 2:
+                first, second = annotation.slice.elts
+                if is_none(second):
+                    return first
+                if is_none(first):
+                    return second
+        return None
+
     def _infer_type_from_value(self, value: ast.expr) -> str:
         """Infer Go type from Python value using Strategy pattern.
 
@@ -1868,11 +9347,29 @@ class MGenPythonToGoConverter:
         context = InferenceContext(
             type_mapper=self._map_type,
             variable_types=self.variable_types,
+            infer_recursively=self._infer_type_from_value,
         )
 
         # Delegate to type inference engine
         return self.type_inference_engine.infer_type(value, context)
 
+    def _comprehension_loop_types(self, generators: list[ast.comprehension]) -> dict[str, str]:
+        """Infer the loop variable types of every for clause, each seeing the variables bound before it."""
+        outer_types = self.variable_types
+        loop_var_types: dict[str, str] = {}
+        try:
+            for generator in generators:
+                self.variable_types = {**outer_types, **loop_var_types}
+                loop_var_types.update(self._infer_loop_variable_type(generator))
+                pair_fields = self._pair_element_fields(generator.iter, self._infer_type_from_value(generator.iter))
+                if isinstance(generator.target, ast.Tuple) and pair_fields is not None:
+                    for elt, elt_type in zip(generator.target.elts, pair_fields[3]):
+                        if isinstance(elt, ast.Name):
+                            loop_var_types.setdefault(elt.id, elt_type)
+        finally:
+            self.variable_types = outer_types
+        return loop_var_types
+
     def _infer_loop_variable_type(self, generator: ast.comprehension) -> dict[str, str]:
         """Infer the type of the loop variable in a comprehension."""
         target = generator.target
@@ -1890,17 +9387,20 @@ class MGenPythonToGoConverter:
             else:
                 # Iterating over a container
                 iter_type = self._infer_type_from_value(iter_expr)
-                # Extract element type from slice or set
+                # Extract element type from slice; dicts iterate their keys, sets their members
+                iter_type = iterated_slice_type(iter_type)
                 if iter_type.startswith("[]"):
                     # Slice type: []int → int
-                    element_type = iter_type[2:]
-                    loop_var_types[target.id] = element_type
-                elif iter_type.startswith("map[") and iter_type.endswith("]bool"):
-                    # Set type: map[int]bool → int
-                    element_type = iter_type[4:-5]
-                    loop_var_types[target.id] = element_type
+                    loop_var_types[target.id] = iter_type[2:]
                 else:
                     loop_var_types[target.id] = "interface{}"
+        elif isinstance(target, ast.Tuple) and len(target.elts) in (2, 3):
+            # for i, x in enumerate(xs) / for a, b in zip(xs, ys) / for k, v in d.items()
+            pair_types = self._pair_source_types(iter_expr)
+            if pair_types is not None and len(pair_types) == len(target.elts):
+                for elt, elt_type in zip(target.elts, pair_types):
+                    if isinstance(elt, ast.Name):
+                        loop_var_types[elt.id] = elt_type
         return loop_var_types
 
     def _infer_comprehension_element_type(self, expr: ast.expr, loop_var_types: dict[str, str]) -> str:
@@ -1923,7 +9423,13 @@ class MGenPythonToGoConverter:
                 return self.variable_types[expr.id]
             # Default to int for range-based comprehensions
             return "int"
+        elif isinstance(expr, ast.List):
+            return self._infer_with_loop_variables(expr, loop_var_types)
         elif isinstance(expr, ast.BinOp):
+            # List arithmetic such as [0] * n gives a list
+            list_type = self._infer_with_loop_variables(expr, loop_var_types)
+            if list_type.startswith("[]"):
+                return list_type
             # For binary operations, try to infer from operands
             left_type = self._infer_comprehension_element_type(expr.left, loop_var_types)
             right_type = self._infer_comprehension_element_type(expr.right, loop_var_types)
@@ -1934,26 +9440,142 @@ class MGenPythonToGoConverter:
             if {left_type, right_type} == {"int", "float64"}:
                 return "float64"
             return "int"  # Default to int for arithmetic
+        elif isinstance(expr, ast.Compare) or (isinstance(expr, ast.UnaryOp) and isinstance(expr.op, ast.Not)):
+            # Comparisons and `not` produce bools
+            return "bool"
+        elif isinstance(expr, ast.UnaryOp):
+            return self._infer_comprehension_element_type(expr.operand, loop_var_types)
+        elif isinstance(expr, ast.Subscript) and not isinstance(expr.slice, ast.Slice):
+            container_type = self._infer_comprehension_element_type(expr.value, loop_var_types)
+            dict_types = dict_type_args(container_type)
+            if container_type.startswith("[]"):
+                return container_type[2:]
+            if dict_types is not None:
+                return dict_types[1]
+            if container_type == "string":
+                return "string"
+        elif isinstance(expr, ast.Attribute) and isinstance(expr.value, ast.Name):
+            # Field of a generated class
+            owner_type = loop_var_types.get(expr.value.id, self.variable_types.get(expr.value.id, ""))
+            field_type = self.struct_info.get(owner_type, {}).get("field_types", {}).get(expr.attr)
+            if field_type:
+                return field_type
+        elif isinstance(expr, ast.Call) and isinstance(expr.func, ast.Name):
+            func_name = expr.func.id
+            func_type = loop_var_types.get(func_name, self.variable_types.get(func_name, ""))
+            if func_name in self.function_return_types and self.function_return_types[func_name]:
+                return self.function_return_types[func_name]
+            if func_type.startswith("func("):
+                return func_result_type(func_type)
+            if func_name in ("str", "repr", "ascii", "format", "chr"):
+                return "string"
+            if func_name == "float":
+                return "float64"
+            if func_name == "abs" and expr.args:
+                return self._infer_comprehension_element_type(expr.args[0], loop_var_types)
+            return "int"  # len(), int() and other counts
         elif isinstance(expr, ast.Call):
             # Handle function calls in comprehensions
             if isinstance(expr.func, ast.Attribute):
                 # Method calls like str.upper() return string
                 attr_name = expr.func.attr
-                if attr_name in ("upper", "lower", "strip", "replace"):
+                if attr_name in ("upper", "lower", "strip", "replace", "ljust", "rjust", "center", "expandtabs"):
                     return "string"
             return "int"  # Default
+        elif isinstance(expr, ast.JoinedStr):
+            return "string"
+        elif isinstance(expr, ast.Tuple):
+            return "mgen.PyTuple"
+        elif isinstance(expr, ast.ListComp):
+            # Nested comprehension: infer the inner loop variables with the outer ones in scope
+            outer_types = self.variable_types
+            self.variable_types = {**outer_types, **loop_var_types}
+            try:
+                inner_types = self._infer_loop_variable_type(expr.generators[0])
+                return "[]" + self._infer_comprehension_element_type(expr.elt, {**loop_var_types, **inner_types})
+            finally:
+                self.variable_types = outer_types
 
         return "int"  # Default to int
 
+    def _infer_with_loop_variables(self, expr: ast.expr, loop_var_types: dict[str, str]) -> str:
+        """Infer the type of expr with a comprehension's loop variables in scope."""
+        outer_types = self.variable_types
+        self.variable_types = {**outer_types, **loop_var_types}
+        try:
+            return self._infer_type_from_value(expr)
+        finally:
+            self.variable_types = outer_types
+
     def _infer_type_from_assignment(self, stmt: ast.Assign) -> str:
         """Infer type from assignment statement."""
         return self._infer_type_from_value(stmt.value)
 
     def _infer_parameter_type(self, arg: ast.arg, func: ast.FunctionDef) -> str:
         """Infer parameter type from annotation or context."""
+        param_type = "interface{}"
         if arg.annotation:
-            return self._map_type_annotation(arg.annotation)
-        return "interface{}"
+            param_type = self._map_type_annotation(arg.annotation)
+        else:
+            # An unannotated parameter with a default takes the default's type: b=0 -> int
+            default = self._parameter_default(arg, func)
+            if default is not None and not (isinstance(default, ast.Constant) and default.value is None):
+                param_type = self._infer_type_from_value(default)
+        if param_type == "interface{}" and func.name == "__eq__":
+            param_type = self._eq_guard_class(func, arg.arg) or param_type
+        return self._big_int_variable(func.name, arg.arg, param_type)
+
+    def _eq_guard_class(self, method: ast.FunctionDef, param: str) -> Optional[str]:
+        """Return the class an untyped __eq__ operand is narrowed to by isinstance() guards, or None.
+
+        When every isinstance() test of the operand names the same generated
+        class, __eq__ only reads it as that class, so it is generated taking
+        one; Equals is false for other values, as the guard would be.
+
+        Example:
+            def __eq__(self, other: object) -> bool:
+                if not isinstance(other, Key):   →  func (obj *Key) PyEq(other Key) bool
+                    return False
+                return self.name == other.name
+        """
+        specs = [
+            node.args[1]
+            for node in ast.walk(method)
+            if isinstance(node, ast.Call)
+            and isinstance(node.func, ast.Name)
+            and node.func.id == "isinstance"
+            and len(node.args) == 2
+            and isinstance(node.args[0], ast.Name)
+            and node.args[0].id == param
+        ]
+        names = {spec.id for spec in specs if isinstance(spec, ast.Name)}
+        if not specs or len(names) != 1 or not all(isinstance(spec, ast.Name) for spec in specs):
+            return None
+        (name,) = names
+        return name if name in self.class_names else None
+
+    def _big_int_variable(self, function: str, name: str, go_type: str) -> str:
+        """Return the type of a variable or parameter typed go_type, mgen.PyInt for an int that may outgrow int64."""
+        if go_type == "int" and self.int_ranges.is_unbounded(function, name):
+            return BIG_INT_TYPE
+        return go_type
+
+    def _big_int_result(self, function: str, go_type: str) -> str:
+        """Return the result type of a function returning go_type, mgen.PyInt for an int that may outgrow int64."""
+        if go_type == "int" and function in self.int_ranges.functions:
+            return BIG_INT_TYPE
+        return go_type
+
+    def _parameter_default(self, arg: ast.arg, func: ast.FunctionDef) -> Optional[ast.expr]:
+        """Return the default value expression of a parameter, or None."""
+        args = func.args
+        if arg in args.kwonlyargs:
+            return args.kw_defaults[args.kwonlyargs.index(arg)]
+        positional = [*args.posonlyargs, *args.args]
+        if arg not in positional:
+            return None
+        index = positional.index(arg) - (len(positional) - len(args.defaults))
+        return args.defaults[index] if index >= 0 else None
 
     def _infer_return_type(self, func: ast.FunctionDef) -> str:
         """Infer return type from function body."""
@@ -1986,12 +9608,13 @@ class MGenPythonToGoConverter:
         """Get default value for Go type."""
         defaults = {
             "int": "0",
+            BIG_INT_TYPE: "mgen.NewPyInt(0)",
             "float64": "0.0",
             "bool": "false",
             "string": '""',
             "[]interface{}": "[]interface{}{}",
-            "map[interface{}]interface{}": "make(map[interface{}]interface{})",
-            "map[interface{}]bool": "make(map[interface{}]bool)",
+            "*mgen.PyDict": "mgen.NewPyDict()",
+            "*mgen.PySet": "mgen.NewPySet()",
         }
         # Handle specific slice types like []int, []string, etc.
         if go_type.startswith("[]") and go_type != "[]interface{}":
@@ -1999,4 +9622,22 @@ class MGenPythonToGoConverter:
         # Handle specific map types
         if go_type.startswith("map["):
             return f"make({go_type})"
+        dict_types = dict_type_args(go_type)
+        if dict_types is not None:
+            return f"mgen.NewDict[{dict_types[0]}, {dict_types[1]}]()"
+        element_type = set_type_arg(go_type)
+        if element_type is not None:
+            return f"mgen.NewSet[{element_type}]()"
         return defaults.get(go_type, "nil")
+
+    def _is_empty_container(self, expr: ast.expr) -> bool:
+        """Report whether expr is {}, or dict() or set() with no arguments."""
+        if isinstance(expr, ast.Dict):
+            return not expr.keys
+        return (
+            isinstance(expr, ast.Call)
+            and isinstance(expr.func, ast.Name)
+            and expr.func.id in ("dict", "set")
+            and not expr.args
+            and not expr.keywords
+        )
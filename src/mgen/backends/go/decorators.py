@@ -0,0 +1,140 @@
+"""Specializing user-defined decorators to the functions they decorate.
+
+A decorator takes a function and returns its replacement, usually a wrapper
+closing over it:
+
+    def logged(func):
+        @functools.wraps(func)
+        def wrapper(*args, **kwargs):
+            print("calling", func.__name__)
+            return func(*args, **kwargs)
+        return wrapper
+
+Go has no untyped functions, so the decorator is copied for each function it
+decorates and typed with that function's signature: func becomes a
+Callable[[int, int], int] parameter (func_ in Go, where func is a keyword),
+the wrapper takes the decorated function's parameters in place of *args and
+**kwargs, func(*args, **kwargs) passes them on, args is the tuple of them,
+and func.__name__ is the decorated function's name. functools.wraps only
+copies such metadata onto the wrapper, so it is dropped.
+"""
+
+import ast
+import copy
+from typing import Optional
+
+from ..errors import UnsupportedFeatureError
+from .packages import GO_KEYWORDS
+
+
+def is_wraps(decorator: ast.expr) -> bool:
+    """Return whether decorator is @functools.wraps(...) or @wraps(...)."""
+    return isinstance(decorator, ast.Call) and ast.unparse(decorator.func) in ("wraps", "functools.wraps")
+
+
+def specialize_decorator(decorator: ast.FunctionDef, function: ast.FunctionDef, name: str) -> ast.FunctionDef:
+    """Return a copy of decorator, named name, taking and returning a function typed like function."""
+    specialized = copy.deepcopy(decorator)
+    specialized.name = name
+    signature = ast.Subscript(
+        value=ast.Name(id="Callable", ctx=ast.Load()),
+        slice=ast.Tuple(
+            elts=[
+                ast.List(elts=[copy.deepcopy(arg.annotation) for arg in function.args.args], ctx=ast.Load()),
+                copy.deepcopy(function.returns),
+            ],
+            ctx=ast.Load(),
+        ),
+        ctx=ast.Load(),
+    )
+    param = specialized.args.args[0]
+    param.annotation = signature
+    specialized.returns = copy.deepcopy(signature)
+    wrapped = param.arg
+    if wrapped in GO_KEYWORDS:
+        param.arg = f"{wrapped}_"
+    specialized = _DecoratorSpecializer(wrapped, param.arg, function).visit(specialized)
+    return ast.fix_missing_locations(specialized)
+
+
+class _DecoratorSpecializer(ast.NodeTransformer):
+    """Rewrite a copied decorator's body for one decorated function (see the module docstring)."""
+
+    def __init__(self, wrapped: str, renamed: str, function: ast.FunctionDef) -> None:
+        self.wrapped = wrapped  # The decorator's parameter
+        self.renamed = renamed
+        self.function = function
+        self.varargs: dict[str, str] = {}  # *args and **kwargs names of the wrapper being visited -> "*" or "**"
+        self.statement: Optional[ast.expr] = None  # Value of the expression statement being visited
+
+    def visit_FunctionDef(self, node: ast.FunctionDef) -> ast.AST:
+        node.decorator_list = [decorator for decorator in node.decorator_list if not is_wraps(decorator)]
+        args = node.args
+        if args.vararg is None and args.kwarg is None:
+            self.generic_visit(node)
+            return node
+        if args.posonlyargs or args.args or args.kwonlyargs:
+            raise UnsupportedFeatureError(f"Wrapper {node.name} must take only *args and **kwargs")
+        saved = self.varargs
+        self.varargs = {arg.arg: stars for arg, stars in ((args.vararg, "*"), (args.kwarg, "**")) if arg is not None}
+        node.args = ast.arguments(
+            posonlyargs=[],
+            args=[ast.arg(arg=arg.arg, annotation=copy.deepcopy(arg.annotation)) for arg in self.function.args.args],
+            kwonlyargs=[],
+            kw_defaults=[],
+            defaults=[],
+        )
+        node.returns = copy.deepcopy(self.function.returns)
+        self.generic_visit(node)
+        self.varargs = saved
+        return node
+
+    def visit_Expr(self, node: ast.Expr) -> ast.AST:
+        self.statement = node.value
+        self.generic_visit(node)
+        return node
+
+    def visit_Call(self, node: ast.Call) -> ast.AST:
+        spread = [arg.value for arg in node.args if isinstance(arg, ast.Starred)]
+        spread += [keyword.value for keyword in node.keywords if keyword.arg is None]
+        if not any(isinstance(value, ast.Name) and value.id in self.varargs for value in spread):
+            self.generic_visit(node)
+            return node
+        starred = node.args[0].value if len(node.args) == 1 and isinstance(node.args[0], ast.Starred) else None
+        double = [keyword.value for keyword in node.keywords if keyword.arg is None]
+        forwards = (
+            isinstance(starred, ast.Name)
+            and self.varargs.get(starred.id) == "*"
+            and len(node.keywords) == len(double) <= 1
+            and all(isinstance(value, ast.Name) and self.varargs.get(value.id) == "**" for value in double)
+        )
+        if not forwards:
+            raise UnsupportedFeatureError(
+                f"A wrapper can pass on its arguments only as f(*args, **kwargs): {ast.unparse(node)}"
+            )
+        returns_none = isinstance(self.function.returns, ast.Constant) and self.function.returns.value is None
+        if returns_none and node is not self.statement:
+            raise UnsupportedFeatureError(
+                f"{self.function.name} returns None, so its result cannot be used: {ast.unparse(node)}"
+            )
+        node.func = self.visit(node.func)
+        node.args = [ast.Name(id=arg.arg, ctx=ast.Load()) for arg in self.function.args.args]
+        node.keywords = []
+        return node
+
+    def visit_Attribute(self, node: ast.Attribute) -> ast.AST:
+        named = isinstance(node.value, ast.Name) and node.value.id == self.wrapped
+        if named and node.attr in ("__name__", "__qualname__"):
+            return ast.copy_location(ast.Constant(value=self.function.name), node)
+        self.generic_visit(node)
+        return node
+
+    def visit_Name(self, node: ast.Name) -> ast.AST:
+        if node.id == self.wrapped:
+            node.id = self.renamed
+        elif self.varargs.get(node.id) == "*":
+            names = [ast.Name(id=arg.arg, ctx=ast.Load()) for arg in self.function.args.args]
+            return ast.copy_location(ast.Tuple(elts=names, ctx=ast.Load()), node)
+        elif node.id in self.varargs:
+            raise UnsupportedFeatureError(f"A wrapper's **{node.id} can only be passed on as f(*args, **{node.id})")
+        return node
@@ -3,9 +3,13 @@
 import ast
 from typing import Any, Optional, Union
 
+from ...common.module_system import ModuleResolver
 from ..base import AbstractEmitter
+from ..errors import UnsupportedFeatureError
 from ..preferences import BackendPreferences
 from .converter import MGenPythonToGoConverter
+from .formatter import format_go_code
+from .packages import GoFunction, GoPackage, export_functions, is_program, link_imports, package_directory
 
 
 class GoEmitter(AbstractEmitter):
@@ -14,7 +18,8 @@ class GoEmitter(AbstractEmitter):
     def __init__(self, preferences: Optional[BackendPreferences] = None) -> None:
         """Initialize Go emitter."""
         super().__init__(preferences)
-        self.converter = MGenPythonToGoConverter()
+        self.converter = MGenPythonToGoConverter(preferences)
+        self.packages: dict[str, GoPackage] = {}  # Project modules emitted so far, by module name
 
     def map_python_type(self, python_type: str) -> str:
         """Map Python type to Go type."""
@@ -25,8 +30,68 @@ class GoEmitter(AbstractEmitter):
         return self.converter._convert_function(func_node)
 
     def emit_module(self, source_code: str, analysis_result: Any) -> str:
-        """Generate complete Go module using the advanced converter."""
-        return self.converter.convert_code(source_code)
+        """Generate complete Go module using the advanced converter.
+
+        A library (a package_name other than main) exports its functions, so
+        other Go code can import it. Unless the format_code preference is off,
+        the module's unused imports are dropped and it is formatted with
+        goimports or gofmt (see formatter.py).
+        """
+        if self.converter.package_name == "main":
+            return self._format(self.converter.convert_code(source_code))
+        tree = self.converter.parse_code(source_code)
+        export_functions(tree)
+        return self._format(self.converter.convert_tree(tree))
+
+    def emit_project_module(
+        self, source_code: str, analysis_result: Any, module_name: str, resolver: ModuleResolver
+    ) -> str:
+        """Generate a module of a project as a Go package of the project's Go module (see packages.py).
+
+        Programs become package main; libraries export their functions, whose
+        signatures are kept for the modules importing them, emitted later.
+        """
+        directory = package_directory(module_name)
+        package = GoPackage(
+            module_name,
+            "main" if is_program(module_name, resolver) else directory.rsplit("/", 1)[-1],
+            f"{self.converter.module_path}/{directory}",
+        )
+        taken = [other.module for other in self.packages.values() if other.import_path == package.import_path]
+        if taken or directory.split("/")[0] == "mgen":
+            owner = f"module {taken[0]}" if taken else "the mgen runtime"
+            raise UnsupportedFeatureError(
+                f"Module {module_name} cannot become Go package {directory}: {owner} already uses that directory"
+            )
+
+        converter = MGenPythonToGoConverter(self.preferences)
+        converter.package_name = package.name
+        tree = converter.parse_code(source_code)
+        exports = export_functions(tree) if package.name != "main" else {}
+        imports = link_imports(tree, resolver.discovered_modules[module_name], resolver, self.packages)
+        for ref, imported in imports.items():
+            converter.package_imports[ref] = imported.import_path
+            for function in imported.functions.values():
+                converter.declare_function(
+                    f"{ref}.{function.name}", function.param_types, function.return_type, function.type_vars
+                )
+        go_code = converter.convert_tree(tree)
+
+        for name, go_name in exports.items():
+            package.functions[name] = GoFunction(
+                go_name,
+                converter.function_param_types.get(go_name, []),
+                converter.function_return_types.get(go_name, ""),
+                converter.generic_functions.get(go_name, []),
+            )
+        self.packages[module_name] = package
+        return self._format(go_code)
+
+    def _format(self, go_code: str) -> str:
+        """Format go_code (see formatter.py) unless the format_code preference is off."""
+        if self.preferences is None or self.preferences.get("format_code", True):
+            go_code = format_go_code(go_code)
+        return go_code
 
     def can_use_simple_emission(self, func_node: ast.FunctionDef, type_context: dict[str, str]) -> bool:
         """Check if function can use simple emission strategy."""
@@ -0,0 +1,88 @@
+"""Post-emit formatting of generated Go code.
+
+The converter writes valid Go, though not laid out as gofmt would, and it
+imports the mgen runtime whether or not the program uses it, which go build
+rejects as an unused import. This pass drops the imports the code never
+refers to, then runs the Go toolchain's formatter over it: goimports when it
+is installed, which also adds missing standard library imports, else gofmt,
+which ships with Go. Without either, or on code they cannot parse (left for
+go build to report), the code is returned with only its imports pruned.
+"""
+
+import re
+import shutil
+import subprocess
+
+# Formatters to try, in order of preference
+FORMATTERS = ("goimports", "gofmt")
+
+# Seconds to wait for a formatter before leaving the code unformatted
+FORMAT_TIMEOUT = 30
+
+IMPORT_LINE = re.compile(r'^import\s+(?:(\w+|\.)\s+)?"([^"]+)"\s*$')
+IMPORT_SPEC = re.compile(r'^\s*(?:(\w+|\.)\s+)?"([^"]+)"\s*$')
+IMPORT_BLOCK = re.compile(r"^import\s*\(\s*$")
+
+# String, raw string and rune literals and comments, which may mention a package without using it
+LITERALS_AND_COMMENTS = re.compile(r'"(?:\\.|[^"\\\n])*"|`[^`]*`|\'(?:\\.|[^\'\\\n])*\'|//[^\n]*|/\*.*?\*/', re.DOTALL)
+
+
+def format_go_code(go_code: str) -> str:
+    """Prune unused imports from go_code and format it with goimports or gofmt."""
+    go_code = prune_imports(go_code)
+    for formatter in FORMATTERS:
+        path = shutil.which(formatter)
+        if path is None:
+            continue
+        try:
+            result = subprocess.run([path], input=go_code, capture_output=True, text=True, timeout=FORMAT_TIMEOUT)
+        except (OSError, subprocess.TimeoutExpired):
+            continue
+        return result.stdout if result.returncode == 0 else go_code
+    return go_code
+
+
+def prune_imports(go_code: str) -> str:
+    """Drop the imports go_code never refers to.
+
+    An import is used when its package name (the alias, or the last element
+    of the path) qualifies an identifier outside literals and comments. Blank
+    (_) and dot imports are kept, as their use cannot be seen this way; an
+    import block left empty is removed.
+
+    Example:
+        import "mgenproject/mgen"  (no mgen.X in the code)  →  removed
+    """
+    lines = go_code.split("\n")
+    imports: dict[int, str] = {}  # line index -> package name the import binds
+    in_block = False
+    for index, line in enumerate(lines):
+        match = IMPORT_SPEC.match(line) if in_block else IMPORT_LINE.match(line)
+        if in_block and line.strip() == ")":
+            in_block = False
+        elif match:
+            imports[index] = match.group(1) or _package_name(match.group(2))
+        elif IMPORT_BLOCK.match(line):
+            in_block = True
+        elif line.startswith(("func ", "type ", "var ", "const ")):
+            break  # Imports precede the declarations
+
+    code = LITERALS_AND_COMMENTS.sub('""', "\n".join(line for index, line in enumerate(lines) if index not in imports))
+    unused = {index for index, name in imports.items() if name not in ("_", ".") and not _refers_to(code, name)}
+    if not unused:
+        return go_code
+    pruned = "\n".join(line for index, line in enumerate(lines) if index not in unused)
+    return re.sub(r"^import\s*\(\s*\)[ \t]*\n", "", pruned, flags=re.MULTILINE)
+
+
+def _package_name(path: str) -> str:
+    """Return the name a package is imported as by default: rand for math/rand, chi for .../go-chi/chi/v5."""
+    elements = path.split("/")
+    if len(elements) > 1 and re.fullmatch(r"v\d+", elements[-1]):
+        return elements[-2]
+    return elements[-1]
+
+
+def _refers_to(code: str, package: str) -> bool:
+    """Report whether code qualifies an identifier with package (package.Name)."""
+    return re.search(rf"(?<![\w.]){re.escape(package)}\.\w", code) is not None
@@ -0,0 +1,312 @@
+"""Integer range analysis for the Go backend's arbitrary-precision mode.
+
+Python ints never overflow, while Go's int wraps around at 64 bits. With the
+int_precision preference set to "auto", the converter types the ints this
+analysis cannot show to stay within int64 as *mgen.PyInt (a math/big.Int)
+and leaves every other int native.
+
+An int is unbounded when it grows geometrically, or is computed from such a
+value:
+
+    result *= i            (in a loop: a product involving itself)
+    a, b = b, a + b        (in a loop: a sum of two values it feeds)
+    n * fact(n - 1)        (a result that is a product involving itself)
+    2 ** n, 1 << n         (a power, or a shift by an unknown count)
+    10 ** 20               (a literal or constant power beyond int64)
+
+Linear updates (a counter, total += x) are taken to stay within int64. The
+unbounded values spread through arithmetic, to the variables they are
+assigned to, the results of the functions returning them and the parameters
+they are passed to, until nothing changes. Only module-level functions are
+analysed; the converter applies the result to the names it types int.
+"""
+
+import ast
+from dataclasses import dataclass, field
+from typing import Optional
+
+INT64_MIN, INT64_MAX = -(2**63), 2**63 - 1
+
+# Builtins whose int result is as large as their argument
+PASS_THROUGH_CALLS = ("int",)
+
+# Exponents beyond which a constant power is not evaluated while analysing
+MAX_FOLDED_EXPONENT = 64
+
+
+@dataclass
+class IntRanges:
+    """The ints of a module that may outgrow int64."""
+
+    variables: dict[str, set[str]] = field(default_factory=dict)  # function -> unbounded locals and parameters
+    functions: set[str] = field(default_factory=set)  # functions whose result is unbounded
+
+    def is_unbounded(self, function: str, name: str) -> bool:
+        """Report whether the variable or parameter name of function may outgrow int64."""
+        return name in self.variables.get(function, set())
+
+
+def analyze_int_ranges(module: ast.Module) -> IntRanges:
+    """Find the variables and function results of a module that may outgrow int64."""
+    functions = {node.name: node for node in module.body if isinstance(node, ast.FunctionDef)}
+    facts = {name: _FunctionFacts(node) for name, node in functions.items()}
+    ranges = IntRanges(variables={name: fact.growing_names() for name, fact in facts.items()})
+    ranges.functions = {name for name, fact in facts.items() if fact.grows_result()}
+
+    changed = True
+    while changed:
+        changed = False
+        for name, fact in facts.items():
+            unbounded = ranges.variables[name]
+            for target, value, _ in fact.assignments:
+                if target not in unbounded and derives_unbounded(value, unbounded, ranges.functions):
+                    unbounded.add(target)
+                    changed = True
+            if name not in ranges.functions and any(
+                derives_unbounded(value, unbounded, ranges.functions) for value in fact.returns
+            ):
+                ranges.functions.add(name)
+                changed = True
+            for call in fact.calls:
+                assert isinstance(call.func, ast.Name)
+                callee = functions.get(call.func.id)
+                if callee is None:
+                    continue
+                for param, arg in _bound_arguments(callee, call):
+                    callee_unbounded = ranges.variables[callee.name]
+                    if param not in callee_unbounded and derives_unbounded(arg, unbounded, ranges.functions):
+                        callee_unbounded.add(param)
+                        changed = True
+    return ranges
+
+
+def derives_unbounded(expr: ast.expr, unbounded: set[str], functions: set[str]) -> bool:
+    """Report whether the int value of expr is computed from an unbounded one.
+
+    unbounded holds the unbounded variables in scope and functions the
+    functions returning unbounded ints. Comparisons, true division and calls
+    other than those functions and the PASS_THROUGH_CALLS builtins end the
+    derivation, as the converter does not type their results as big ints.
+    """
+    if isinstance(expr, ast.Name):
+        return expr.id in unbounded
+    if isinstance(expr, ast.Constant):
+        return isinstance(expr.value, int) and not isinstance(expr.value, bool) and not fits_int64(expr.value)
+    if isinstance(expr, ast.BinOp):
+        if isinstance(expr.op, ast.Div):
+            return False
+        if isinstance(expr.right, ast.Constant) and isinstance(expr.right.value, float):
+            return False
+        if outgrows_int64(expr):
+            return True
+        if constant_int_value(expr) is not None:
+            return False
+        return derives_unbounded(expr.left, unbounded, functions) or derives_unbounded(
+            expr.right, unbounded, functions
+        )
+    if isinstance(expr, ast.UnaryOp):
+        return not isinstance(expr.op, ast.Not) and derives_unbounded(expr.operand, unbounded, functions)
+    if isinstance(expr, ast.IfExp):
+        return derives_unbounded(expr.body, unbounded, functions) or derives_unbounded(
+            expr.orelse, unbounded, functions
+        )
+    if isinstance(expr, ast.BoolOp):
+        return any(derives_unbounded(value, unbounded, functions) for value in expr.values)
+    if isinstance(expr, ast.Call) and isinstance(expr.func, ast.Name):
+        if expr.func.id in functions:
+            return True
+        return expr.func.id in PASS_THROUGH_CALLS and any(
+            derives_unbounded(arg, unbounded, functions) for arg in expr.args
+        )
+    return False
+
+
+def constant_int_value(expr: ast.expr) -> Optional[int]:
+    """Return the value of an int expression of literals (2 ** 10), or None.
+
+    Powers with a large or negative exponent are not evaluated and give None.
+    """
+    if isinstance(expr, ast.Constant):
+        if isinstance(expr.value, int) and not isinstance(expr.value, bool):
+            return expr.value
+        return None
+    if isinstance(expr, ast.UnaryOp) and isinstance(expr.op, (ast.USub, ast.UAdd)):
+        operand = constant_int_value(expr.operand)
+        if operand is None:
+            return None
+        return -operand if isinstance(expr.op, ast.USub) else operand
+    if isinstance(expr, ast.BinOp) and isinstance(expr.op, (ast.Add, ast.Sub, ast.Mult, ast.Pow, ast.LShift)):
+        left, right = constant_int_value(expr.left), constant_int_value(expr.right)
+        if left is None or right is None:
+            return None
+        if isinstance(expr.op, ast.Add):
+            return left + right
+        if isinstance(expr.op, ast.Sub):
+            return left - right
+        if isinstance(expr.op, ast.Mult):
+            return left * right
+        if not 0 <= right <= MAX_FOLDED_EXPONENT:
+            return None
+        return left**right if isinstance(expr.op, ast.Pow) else left << right
+    return None
+
+
+def outgrows_int64(expr: ast.BinOp) -> bool:
+    """Report whether an int operation may leave int64 whatever the size of its operands.
+
+    That is a constant expression beyond int64, a power with a non-negative
+    or unknown exponent (a negative one gives a float) and a left shift by an
+    unknown or large count.
+    """
+    value = constant_int_value(expr)
+    if value is not None:
+        return not fits_int64(value)
+    count = constant_int_value(expr.right)
+    if isinstance(expr.op, ast.Pow):
+        return count is None or count >= 0
+    if isinstance(expr.op, ast.LShift):
+        return count is None or count > MAX_FOLDED_EXPONENT
+    return False
+
+
+def fits_int64(value: int) -> bool:
+    """Report whether an int is within Go's int64."""
+    return INT64_MIN <= value <= INT64_MAX
+
+
+class _FunctionFacts:
+    """The assignments, results and calls of a function's own body (not of functions nested in it)."""
+
+    def __init__(self, node: ast.FunctionDef) -> None:
+        self.node = node
+        self.assignments: list[tuple[str, ast.expr, bool]] = []  # (target, value, assigned in a loop)
+        self.returns: list[ast.expr] = []
+        self.calls: list[ast.Call] = []
+        self._collect(node.body, False)
+        for call in self.calls:
+            if isinstance(call.func, ast.Name) and call.func.id == node.name:
+                # A recursive call rebinds the parameters like another pass of a loop
+                for param, arg in _bound_arguments(node, call):
+                    self.assignments.append((param, arg, True))
+
+    def growing_names(self) -> set[str]:
+        """Return the variables a loop updates with a geometric step."""
+        loop_assignments = [(target, value) for target, value, in_loop in self.assignments if in_loop]
+        feeds: dict[str, set[str]] = {}
+        for target, value in loop_assignments:
+            feeds.setdefault(target, set()).update(
+                node.id for node in ast.walk(value) if isinstance(node, ast.Name) and isinstance(node.ctx, ast.Load)
+            )
+        grown = set()
+        for target, value in loop_assignments:
+            # The names whose values feed back into target, target itself included when it does
+            cycle = {name for name in _reachable(feeds, target) if target in _reachable(feeds, name)}
+            if _grows(value, cycle):
+                grown.add(target)
+        return grown
+
+    def grows_result(self) -> bool:
+        """Report whether a result is a product involving, or a sum of several, recursive calls."""
+        return any(_grows(value, {self.node.name}) for value in self.returns)
+
+    def _collect(self, stmts: list[ast.stmt], in_loop: bool) -> None:
+        for stmt in stmts:
+            if isinstance(stmt, (ast.FunctionDef, ast.AsyncFunctionDef, ast.ClassDef)):
+                continue
+            if isinstance(stmt, ast.Assign):
+                for target in stmt.targets:
+                    self._collect_target(target, stmt.value, in_loop)
+            elif isinstance(stmt, ast.AnnAssign) and stmt.value is not None:
+                self._collect_target(stmt.target, stmt.value, in_loop)
+            elif isinstance(stmt, ast.AugAssign) and isinstance(stmt.target, ast.Name):
+                current = ast.Name(id=stmt.target.id, ctx=ast.Load())
+                updated = ast.BinOp(left=current, op=stmt.op, right=stmt.value)
+                self.assignments.append((stmt.target.id, updated, in_loop))
+            elif isinstance(stmt, ast.Return) and stmt.value is not None:
+                self.returns.append(stmt.value)
+            for node in _own_expressions(stmt):
+                if isinstance(node, ast.Call) and isinstance(node.func, ast.Name):
+                    self.calls.append(node)
+            if isinstance(stmt, (ast.For, ast.While)):
+                self._collect(stmt.body, True)
+                self._collect(stmt.orelse, in_loop)
+                continue
+            for name in ("body", "orelse", "finalbody"):
+                self._collect(getattr(stmt, name, []), in_loop)
+            for handler in getattr(stmt, "handlers", []):
+                self._collect(handler.body, in_loop)
+            for case in getattr(stmt, "cases", []):
+                self._collect(case.body, in_loop)
+
+    def _collect_target(self, target: ast.expr, value: ast.expr, in_loop: bool) -> None:
+        if isinstance(target, ast.Name):
+            self.assignments.append((target.id, value, in_loop))
+        elif isinstance(target, ast.Tuple) and isinstance(value, ast.Tuple) and len(target.elts) == len(value.elts):
+            for item_target, item_value in zip(target.elts, value.elts):
+                self._collect_target(item_target, item_value, in_loop)
+
+
+def _own_expressions(stmt: ast.stmt) -> list[ast.AST]:
+    """Return the expression nodes of a statement itself, not of the statements or functions it contains."""
+    found: list[ast.AST] = []
+    pending: list[ast.AST] = [
+        child for child in ast.iter_child_nodes(stmt) if not isinstance(child, (ast.stmt, ast.excepthandler))
+    ]
+    while pending:
+        node = pending.pop()
+        found.append(node)
+        if not isinstance(node, ast.Lambda):
+            pending.extend(ast.iter_child_nodes(node))
+    return found
+
+
+def _bound_arguments(func: ast.FunctionDef, call: ast.Call) -> list[tuple[str, ast.expr]]:
+    """Pair the parameters of func with the arguments call passes them."""
+    params = [arg.arg for arg in func.args.args]
+    bound = list(zip(params, call.args))
+    bound.extend((keyword.arg, keyword.value) for keyword in call.keywords if keyword.arg in params)
+    return bound
+
+
+def _reachable(feeds: dict[str, set[str]], start: str) -> set[str]:
+    """Return the names whose values flow into start's, through any number of assignments."""
+    seen: set[str] = set()
+    pending = list(feeds.get(start, ()))
+    while pending:
+        name = pending.pop()
+        if name not in seen:
+            seen.add(name)
+            pending.extend(feeds.get(name, ()))
+    return seen
+
+
+def _grows(expr: ast.expr, cycle: set[str]) -> bool:
+    """Report whether expr multiplies a value of cycle, or adds up two of them."""
+    return _multiplies(expr, cycle) or _summed_terms(expr, cycle) >= 2
+
+
+def _multiplies(expr: ast.expr, cycle: set[str]) -> bool:
+    if isinstance(expr, ast.BinOp):
+        if isinstance(expr.op, (ast.Mult, ast.Pow, ast.LShift)):
+            return _summed_terms(expr.left, cycle) + _summed_terms(expr.right, cycle) > 0
+        if isinstance(expr.op, (ast.Add, ast.Sub)):
+            return _multiplies(expr.left, cycle) or _multiplies(expr.right, cycle)
+    if isinstance(expr, ast.UnaryOp) and isinstance(expr.op, (ast.USub, ast.UAdd)):
+        return _multiplies(expr.operand, cycle)
+    return False
+
+
+def _summed_terms(expr: ast.expr, cycle: set[str]) -> int:
+    """Count the values of cycle (names, or calls of the functions in it) that expr adds up."""
+    if isinstance(expr, ast.Name):
+        return 1 if expr.id in cycle else 0
+    if isinstance(expr, ast.Call) and isinstance(expr.func, ast.Name):
+        return 1 if expr.func.id in cycle else 0
+    if isinstance(expr, ast.BinOp):
+        if isinstance(expr.op, (ast.Add, ast.Mult, ast.Pow, ast.LShift)):
+            return _summed_terms(expr.left, cycle) + _summed_terms(expr.right, cycle)
+        if isinstance(expr.op, ast.Sub):
+            return _summed_terms(expr.left, cycle)
+    if isinstance(expr, ast.UnaryOp) and isinstance(expr.op, (ast.USub, ast.UAdd)):
+        return _summed_terms(expr.operand, cycle)
+    return 0
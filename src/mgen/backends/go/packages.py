@@ -0,0 +1,310 @@
+"""Mapping the modules of a Python project onto Go packages.
+
+Each module becomes a package in its own directory of the Go module
+(pkg.shapes -> pkg/shapes/shapes.go, imported as <module_path>/pkg/shapes).
+A module no other project module imports is a program (package main); the
+others are libraries, named after the module, whose functions are exported
+(area -> Area, compute_area -> ComputeArea) so other packages can call them.
+In an importing module, references to imported functions become qualified
+names: area after from pkg.shapes import area, and shapes.area after
+from pkg import shapes, both become shapes.Area.
+
+Only functions can be imported from another module; importing a class or a
+variable raises UnsupportedFeatureError.
+"""
+
+import ast
+from collections.abc import Callable
+from dataclasses import dataclass, field
+from typing import Optional
+
+from ...common.module_system import ModuleInfo, ModuleResolver
+from ...frontend.type_vars import TypeVarInfo
+from ..errors import UnsupportedFeatureError
+
+GO_KEYWORDS = frozenset(
+    {
+        "break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func",
+        "go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct",
+        "switch", "type", "var",
+    }
+)  # fmt: skip
+
+
+@dataclass
+class GoFunction:
+    """An exported function of a library package, as the packages importing it call it."""
+
+    name: str  # Exported Go name (Area)
+    param_types: list[str]
+    return_type: str
+    type_vars: list[TypeVarInfo] = field(default_factory=list)  # Non-empty for generic functions
+
+
+@dataclass
+class GoPackage:
+    """The Go package a project module becomes."""
+
+    module: str  # Python module name (pkg.shapes)
+    name: str  # Package clause: main for programs, else the module's last element (shapes)
+    import_path: str  # <module_path>/pkg/shapes
+    functions: dict[str, GoFunction] = field(default_factory=dict)  # Python name -> export (none for _private)
+
+
+def package_directory(module_name: str) -> str:
+    """Return the directory, relative to the module root, of the package a Python module becomes.
+
+    Each element must name a directory the go tool builds: pkg.__main__ -> pkg/main
+    (the go tool ignores names starting with _), and a Go keyword takes a pkg
+    suffix (select -> selectpkg).
+    """
+    elements = []
+    for element in module_name.split("."):
+        element = "main" if element == "__main__" else element.lstrip("_")
+        if element in GO_KEYWORDS:
+            element += "pkg"
+        elements.append(element)
+    return "/".join(elements)
+
+
+def package_filename(module_name: str) -> str:
+    """Return the path of the Go source a Python module becomes: pkg/shapes/shapes.go."""
+    directory = package_directory(module_name)
+    return f"{directory}/{directory.rsplit('/', 1)[-1]}.go"
+
+
+def is_program(module_name: str, resolver: ModuleResolver) -> bool:
+    """Report whether a project module is a program rather than a library.
+
+    A program is an entry point no other project module imports: a __main__
+    module, or one defining main() or running code under if __name__ == "__main__".
+    """
+    if any(module_name in module.dependencies for module in resolver.discovered_modules.values()):
+        return False
+    module = resolver.discovered_modules[module_name]
+    return module_name.rsplit(".", 1)[-1] == "__main__" or "main" in module.functions or _has_main_guard(module)
+
+
+def _has_main_guard(module: ModuleInfo) -> bool:
+    """Report whether a module runs code under if __name__ == "__main__"."""
+    return module.ast_module is not None and main_guard(module.ast_module) is not None
+
+
+def main_guard(tree: ast.Module) -> Optional[ast.If]:
+    """Return the module's if __name__ == "__main__" statement, or None."""
+    for stmt in tree.body:
+        test = stmt.test if isinstance(stmt, ast.If) else None
+        if (
+            isinstance(test, ast.Compare)
+            and isinstance(test.left, ast.Name)
+            and test.left.id == "__name__"
+            and len(test.comparators) == 1
+            and isinstance(test.comparators[0], ast.Constant)
+            and test.comparators[0].value == "__main__"
+        ):
+            return stmt
+    return None
+
+
+def guarded_main(tree: ast.Module) -> None:
+    """Make a program's if __name__ == "__main__" block its main function.
+
+    The rest of the module is converted as a library would be, and the Go
+    main function runs the guarded block. A block that only calls main()
+    keeps the module's main() as the entry point; otherwise the module's
+    main() is renamed pyMain, so the block can call it.
+    """
+    guard = main_guard(tree)
+    if guard is None:
+        return
+    index = tree.body.index(guard)
+    functions = {stmt.name for stmt in tree.body if isinstance(stmt, (ast.FunctionDef, ast.ClassDef))}
+    if "main" in functions:
+        call = guard.body[0].value if len(guard.body) == 1 and isinstance(guard.body[0], ast.Expr) else None
+        if isinstance(call, ast.Call) and _dotted_name(call.func) == "main" and not (call.args or call.keywords):
+            del tree.body[index]
+            return
+        if "pyMain" in functions:
+            raise UnsupportedFeatureError("main() cannot be renamed pyMain: the module already defines pyMain")
+        for stmt in tree.body:
+            if isinstance(stmt, ast.FunctionDef) and stmt.name == "main":
+                stmt.name = "pyMain"
+    entry = ast.FunctionDef(
+        name="main",
+        args=ast.arguments(posonlyargs=[], args=[], kwonlyargs=[], kw_defaults=[], defaults=[]),
+        body=guard.body,
+        decorator_list=[],
+        returns=ast.Constant(value=None),
+    )
+    tree.body[index] = ast.copy_location(entry, guard)
+    if "main" in functions:
+        _rewrite_references(tree, {"main": "pyMain"}.get)
+    ast.fix_missing_locations(tree)
+
+
+def exported_name(name: str) -> str:
+    """Return the exported Go name of a library function: area -> Area, compute_area -> ComputeArea.
+
+    Names starting with an underscore are private to the module and stay unexported.
+    """
+    if name.startswith("_"):
+        return name
+    return "".join(word[:1].upper() + word[1:] for word in name.split("_"))
+
+
+def export_functions(tree: ast.Module) -> dict[str, str]:
+    """Rename a library module's functions, and the references to them, to their exported names.
+
+    Returns:
+        The exported functions (Python name -> exported Go name); _private ones are not
+    """
+    top_level = {stmt.name for stmt in tree.body if isinstance(stmt, (ast.FunctionDef, ast.ClassDef))}
+    exports: dict[str, str] = {}
+    renames: dict[str, str] = {}
+    for stmt in tree.body:
+        if isinstance(stmt, ast.FunctionDef) and not stmt.name.startswith("_"):
+            go_name = exported_name(stmt.name)
+            if go_name != stmt.name and (go_name in top_level or go_name in renames.values()):
+                raise UnsupportedFeatureError(
+                    f"Function {stmt.name} cannot be exported as {go_name}: the module already defines {go_name}"
+                )
+            exports[stmt.name] = go_name
+            if go_name != stmt.name:
+                renames[stmt.name] = go_name
+    for stmt in tree.body:
+        if isinstance(stmt, ast.FunctionDef) and stmt.name in renames:
+            stmt.name = renames[stmt.name]
+    _rewrite_references(tree, renames.get)
+    return exports
+
+
+def link_imports(
+    tree: ast.Module, module: ModuleInfo, resolver: ModuleResolver, packages: dict[str, GoPackage]
+) -> dict[str, GoPackage]:
+    """Point a module's references to functions of other project modules at their Go packages.
+
+    packages holds the project modules converted so far, which include every
+    module this one imports. References become qualified names (shapes.Area)
+    of the imported packages, which are returned by the name each is referred
+    to by: its package name, or its directory (pkg_shapes) when that name is
+    taken. Packages the module imports but never refers to are left out.
+    """
+    functions: dict[str, tuple[GoPackage, str]] = {}  # Local name -> (package, Python function name)
+    modules: dict[str, GoPackage] = {}  # Local name, dotted for import pkg.shapes -> package
+    for stmt in tree.body:
+        if isinstance(stmt, ast.Import):
+            for alias in stmt.names:
+                if alias.name in packages:
+                    modules[alias.asname or alias.name] = packages[alias.name]
+        elif isinstance(stmt, ast.ImportFrom):
+            base = resolver.resolve_from_module(module, stmt)
+            for alias in stmt.names if base is not None else []:
+                if f"{base}.{alias.name}" in packages:
+                    modules[alias.asname or alias.name] = packages[f"{base}.{alias.name}"]
+                elif base not in packages:
+                    continue  # Not a project module
+                elif alias.name == "*":
+                    functions.update((name, (packages[base], name)) for name in packages[base].functions)
+                elif alias.name in packages[base].functions:
+                    functions[alias.asname or alias.name] = (packages[base], alias.name)
+                else:
+                    raise _unsupported_import(base, alias.name)
+
+    used: dict[str, GoPackage] = {}
+    refs: dict[str, str] = {}  # Module name -> name its package is referred to by
+
+    def qualified(package: GoPackage, function: str) -> str:
+        if package.module not in refs:
+            ref = package.name
+            if ref in used or ref == "mgen":
+                ref = package_directory(package.module).replace("/", "_")
+            refs[package.module] = ref
+            used[ref] = package
+        return f"{refs[package.module]}.{package.functions[function].name}"
+
+    def rename(name: str) -> Optional[str]:
+        if name in functions:
+            return qualified(*functions[name])
+        owner, _, attr = name.rpartition(".")
+        if owner not in modules:
+            return None
+        if attr not in modules[owner].functions:
+            raise _unsupported_import(modules[owner].module, attr)
+        return qualified(modules[owner], attr)
+
+    _rewrite_references(tree, rename)
+    return used
+
+
+def _unsupported_import(module_name: str, name: str) -> UnsupportedFeatureError:
+    """Return the error for importing a name other than a function from a project module."""
+    return UnsupportedFeatureError(
+        f"Cannot import {name} from {module_name}: the Go backend imports only functions from other modules"
+    )
+
+
+class _ReferenceRewriter(ast.NodeTransformer):
+    """Replace the names (area) and dotted names (shapes.area) rename maps, where no local shadows them."""
+
+    def __init__(self, rename: Callable[[str], Optional[str]]) -> None:
+        self.rename = rename
+        self.shadowed: set[str] = set()
+
+    def visit_FunctionDef(self, node: ast.FunctionDef) -> ast.AST:
+        saved = self.shadowed
+        self.shadowed = saved | _local_names(node)
+        self.generic_visit(node)
+        self.shadowed = saved
+        return node
+
+    def visit_Name(self, node: ast.Name) -> ast.AST:
+        if isinstance(node.ctx, ast.Load) and node.id not in self.shadowed:
+            return self._replace(node, node.id)
+        return node
+
+    def visit_Attribute(self, node: ast.Attribute) -> ast.AST:
+        dotted = _dotted_name(node)
+        if isinstance(node.ctx, ast.Load) and dotted is not None and dotted.split(".")[0] not in self.shadowed:
+            replaced = self._replace(node, dotted)
+            if replaced is not node:
+                return replaced
+        self.generic_visit(node)
+        return node
+
+    def _replace(self, node: ast.expr, name: str) -> ast.expr:
+        """Return the Name replacing node, spelled as rename maps name (a Go qualified name may have a dot)."""
+        new_name = self.rename(name)
+        if new_name is None:
+            return node
+        return ast.copy_location(ast.Name(id=new_name, ctx=ast.Load()), node)
+
+
+def _rewrite_references(tree: ast.Module, rename: Callable[[str], Optional[str]]) -> None:
+    """Rewrite the references in the module's functions and methods (see _ReferenceRewriter)."""
+    rewriter = _ReferenceRewriter(rename)
+    for stmt in tree.body:
+        if isinstance(stmt, (ast.FunctionDef, ast.ClassDef)):
+            rewriter.visit(stmt)
+
+
+def _local_names(node: ast.FunctionDef) -> set[str]:
+    """Return the names a function binds locally: its parameters, assignment targets and nested definitions."""
+    args = node.args
+    names = {arg.arg for arg in [*args.posonlyargs, *args.args, *args.kwonlyargs]}
+    names.update(arg.arg for arg in (args.vararg, args.kwarg) if arg is not None)
+    for child in ast.walk(node):
+        if isinstance(child, ast.Name) and isinstance(child.ctx, ast.Store):
+            names.add(child.id)
+        elif isinstance(child, (ast.FunctionDef, ast.ClassDef)) and child is not node:
+            names.add(child.name)
+    return names
+
+
+def _dotted_name(node: ast.expr) -> Optional[str]:
+    """Return the dotted name an attribute chain spells (pkg.shapes.area), or None for other expressions."""
+    if isinstance(node, ast.Name):
+        return node.id
+    if isinstance(node, ast.Attribute):
+        owner = _dotted_name(node.value)
+        return f"{owner}.{node.attr}" if owner is not None else None
+    return None
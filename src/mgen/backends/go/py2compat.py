@@ -0,0 +1,129 @@
+"""Python 2 source compatibility for the Go backend.
+
+Python 2 print statements are not valid Python 3 syntax, so ast.parse cannot
+read them. rewrite_print_statements turns each one into the equivalent
+print() call before parsing:
+
+    print                →  print()
+    print x, y           →  print(x, y)
+    print x,             →  print(x, end=" ")
+    print >>f, x         →  print(x, file=f)
+
+A print followed by "(" is left alone: print(x) means the same in both
+versions. Division semantics are handled by the converter (see
+MGenPythonToGoConverter.python_version).
+"""
+
+import io
+import tokenize
+
+from ..errors import UnsupportedFeatureError
+
+# Tokens after which a new statement begins
+_STATEMENT_STARTS = (tokenize.NEWLINE, tokenize.INDENT, tokenize.DEDENT)
+
+# Keywords whose ":" may be followed by a statement on the same line
+_COMPOUND_KEYWORDS = ("if", "elif", "else", "for", "while", "try", "except", "finally", "with", "def", "class")
+
+
+def rewrite_print_statements(source: str) -> str:
+    """Rewrite Python 2 print statements in source to print() calls."""
+    lines = source.splitlines(keepends=True)
+    tokens = list(tokenize.generate_tokens(io.StringIO(source).readline))
+    # Rewrite from the end so earlier token positions stay valid
+    for start, end in reversed(_print_statements(tokens)):
+        args = _print_arguments(lines, tokens[start + 1 : end])
+        (first_row, first_col), (last_row, last_col) = tokens[start].start, tokens[end - 1].end
+        call = "print(" + ", ".join(args) + ")"
+        lines[first_row - 1 : last_row] = [lines[first_row - 1][:first_col] + call + lines[last_row - 1][last_col:]]
+    return "".join(lines)
+
+
+def _print_statements(tokens: list[tokenize.TokenInfo]) -> list[tuple[int, int]]:
+    """Return the token range [start, end) of each print statement."""
+    statements = []
+    depth = 0
+    line_keyword = ""  # First token of the current logical line
+    at_statement_start = True
+    for i, token in enumerate(tokens):
+        if token.type in (tokenize.NL, tokenize.COMMENT):
+            continue
+        if at_statement_start and token.type not in (tokenize.INDENT, tokenize.DEDENT):
+            line_keyword = token.string
+        if at_statement_start and token.string == "print" and token.type == tokenize.NAME:
+            following = tokens[i + 1]
+            # print(...) is the same in both versions; print = ..., print.x and
+            # print[i] use it as a name, while print [1, 2] prints a list
+            subscript = following.string == "[" and following.start == token.end
+            assignment = following.type == tokenize.OP and following.string.endswith("=")
+            as_name = following.string in ("(", ".") or assignment
+            if not (subscript or as_name):
+                statements.append((i, _statement_end(tokens, i)))
+        if token.type == tokenize.OP and token.string in "([{":
+            depth += 1
+        elif token.type == tokenize.OP and token.string in ")]}":
+            depth -= 1
+        at_statement_start = token.type in _STATEMENT_STARTS or (
+            depth == 0
+            and token.type == tokenize.OP
+            and (token.string == ";" or (token.string == ":" and line_keyword in _COMPOUND_KEYWORDS))
+        )
+    return statements
+
+
+def _statement_end(tokens: list[tokenize.TokenInfo], start: int) -> int:
+    """Return the index just past the last token of the statement at start."""
+    depth = 0
+    end = start + 1
+    while tokens[end].type not in (tokenize.NEWLINE, tokenize.ENDMARKER, tokenize.COMMENT):
+        if tokens[end].type == tokenize.OP:
+            if tokens[end].string in "([{":
+                depth += 1
+            elif tokens[end].string in ")]}":
+                depth -= 1
+            elif tokens[end].string == ";" and depth == 0:
+                break
+        end += 1
+    while tokens[end - 1].type == tokenize.NL:
+        end -= 1
+    return end
+
+
+def _print_arguments(lines: list[str], tokens: list[tokenize.TokenInfo]) -> list[str]:
+    """Convert the operand tokens of a print statement to print() arguments."""
+    groups: list[list[tokenize.TokenInfo]] = [[]]
+    depth = 0
+    for token in tokens:
+        if token.type == tokenize.OP and token.string in "([{":
+            depth += 1
+        elif token.type == tokenize.OP and token.string in ")]}":
+            depth -= 1
+        if depth == 0 and token.type == tokenize.OP and token.string == ",":
+            groups.append([])
+        elif token.type not in (tokenize.NL, tokenize.COMMENT):
+            groups[-1].append(token)
+    if groups == [[]]:
+        return []
+    args = [_source_text(lines, group) for group in groups]
+    keywords = []
+    if args[0].startswith(">>"):
+        # print >>f, x writes to f
+        keywords.append(f"file={args.pop(0)[2:].strip()}")
+    if args and not args[-1]:
+        # A trailing comma suppresses the newline
+        args.pop()
+        keywords.insert(0, 'end=" "')
+    if not all(args):
+        raise UnsupportedFeatureError("invalid syntax in print statement")
+    return args + keywords
+
+
+def _source_text(lines: list[str], tokens: list[tokenize.TokenInfo]) -> str:
+    """Return the source text spanned by tokens, joined onto one line."""
+    if not tokens:
+        return ""
+    (first_row, first_col), (last_row, last_col) = tokens[0].start, tokens[-1].end
+    if first_row == last_row:
+        return lines[first_row - 1][first_col:last_col]
+    text = [lines[first_row - 1][first_col:]] + lines[first_row : last_row - 1] + [lines[last_row - 1][:last_col]]
+    return " ".join(part.strip() for part in text)
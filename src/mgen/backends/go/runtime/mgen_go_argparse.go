@@ -0,0 +1,418 @@
+package mgen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Command-line parsing
+//
+// ArgumentParser covers the argparse subset most scripts use: positional
+// arguments, options with one value, store_true/store_false flags, type=int,
+// float or str, default=, required= and help=. Options may be abbreviated to
+// a unique prefix and given as --name=value, and "--" ends option parsing.
+// Like argparse, a parse error prints the usage line and the error to stderr
+// and exits with status 2, and -h/--help prints the help and exits; with
+// ExitOnError false, errors raise argparse.ArgumentError instead.
+
+// ArgOpts holds the keyword arguments of parser.add_argument()
+type ArgOpts struct {
+	Aliases  []string    // further option strings, such as "--verbose" for "-v"
+	Action   string      // "store" (the default), "store_true" or "store_false"
+	Type     string      // "int", "float", or "str" (the default)
+	Default  interface{} // value when the argument is absent; nil is None
+	Required bool        // options only; positionals are always required
+	Help     string
+	Dest     string // attribute name; derived from the option strings when empty
+}
+
+// argSpec is one argument added to a parser
+type argSpec struct {
+	ArgOpts
+	flags []string // option strings; empty for a positional
+}
+
+// positional reports whether the argument is positional
+func (a *argSpec) positional() bool {
+	return len(a.flags) == 0
+}
+
+// takesValue reports whether the option consumes the next command-line word
+func (a *argSpec) takesValue() bool {
+	return a.Action == "store"
+}
+
+// metavar is the placeholder for the argument's value in usage and help
+func (a *argSpec) metavar() string {
+	if a.positional() {
+		return a.Dest
+	}
+	return strings.ToUpper(a.Dest)
+}
+
+// name identifies the argument in error messages: n or -c/--count
+func (a *argSpec) name() string {
+	if a.positional() {
+		return a.Dest
+	}
+	return strings.Join(a.flags, "/")
+}
+
+// invocation renders the argument in the help listing: -c COUNT, --count COUNT
+func (a *argSpec) invocation() string {
+	if a.positional() {
+		return a.Dest
+	}
+	parts := make([]string, len(a.flags))
+	for i, flag := range a.flags {
+		parts[i] = flag
+		if a.takesValue() {
+			parts[i] += " " + a.metavar()
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ArgumentParser is argparse.ArgumentParser
+type ArgumentParser struct {
+	Prog        string
+	Description string
+	ExitOnError bool
+	args        []*argSpec
+}
+
+// NewArgumentParser creates a parser with the -h/--help option
+// (argparse.ArgumentParser(prog=..., description=...)); an empty prog
+// uses the program's file name
+func NewArgumentParser(prog string, description string) *ArgumentParser {
+	if prog == "" {
+		prog = filepath.Base(os.Args[0])
+	}
+	p := &ArgumentParser{Prog: prog, Description: description, ExitOnError: true}
+	p.AddArgument("-h", ArgOpts{Aliases: []string{"--help"}, Action: "help", Help: "show this help message and exit"})
+	return p
+}
+
+// AddArgument implements parser.add_argument(name, *aliases, **opts). A name
+// starting with "-" is an option; anything else is a positional argument.
+func (p *ArgumentParser) AddArgument(name string, opts ArgOpts) {
+	spec := &argSpec{ArgOpts: opts}
+	if strings.HasPrefix(name, "-") {
+		spec.flags = append([]string{name}, opts.Aliases...)
+	} else if len(opts.Aliases) > 0 {
+		Raise("ValueError", "invalid option string %s: must start with a character '-'", pyQuote(opts.Aliases[0]))
+	}
+	switch spec.Action {
+	case "":
+		spec.Action = "store"
+	case "store_true":
+		if spec.Default == nil {
+			spec.Default = false
+		}
+	case "store_false":
+		if spec.Default == nil {
+			spec.Default = true
+		}
+	case "store", "help":
+	default:
+		Raise("ValueError", "unknown action %s", pyQuote(spec.Action))
+	}
+	if spec.Dest == "" {
+		spec.Dest = name
+		for _, flag := range spec.flags {
+			if strings.HasPrefix(flag, "--") {
+				spec.Dest = flag
+				break
+			}
+		}
+		spec.Dest = strings.ReplaceAll(strings.TrimLeft(spec.Dest, "-"), "-", "_")
+	}
+	p.args = append(p.args, spec)
+}
+
+// FormatUsage returns the usage line: usage: prog [-h] [--count COUNT] n
+func (p *ArgumentParser) FormatUsage() string {
+	parts := []string{"usage: " + p.Prog}
+	for _, positional := range []bool{false, true} {
+		for _, a := range p.args {
+			if a.positional() != positional {
+				continue
+			}
+			part := a.metavar()
+			if !a.positional() {
+				part = a.flags[0]
+				if a.takesValue() {
+					part += " " + a.metavar()
+				}
+				if !a.Required {
+					part = "[" + part + "]"
+				}
+			}
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " ") + "\n"
+}
+
+// FormatHelp returns the full help text printed by -h
+func (p *ArgumentParser) FormatHelp() string {
+	var b strings.Builder
+	b.WriteString(p.FormatUsage())
+	if p.Description != "" {
+		b.WriteString("\n" + p.Description + "\n")
+	}
+	// argparse aligns the help texts in one column, at most 24 characters in
+	helpPosition := 0
+	for _, a := range p.args {
+		helpPosition = max(helpPosition, min(len(a.invocation())+4, 24))
+	}
+	for _, section := range []struct {
+		title      string
+		positional bool
+	}{{"positional arguments", true}, {"options", false}} {
+		var lines []string
+		for _, a := range p.args {
+			if a.positional() != section.positional {
+				continue
+			}
+			line := "  " + a.invocation()
+			if a.Help != "" {
+				if len(line)+2 <= helpPosition {
+					line += strings.Repeat(" ", helpPosition-len(line)) + a.Help
+				} else {
+					line += "\n" + strings.Repeat(" ", helpPosition) + a.Help
+				}
+			}
+			lines = append(lines, line+"\n")
+		}
+		if len(lines) > 0 {
+			b.WriteString("\n" + section.title + ":\n" + strings.Join(lines, ""))
+		}
+	}
+	return b.String()
+}
+
+// PrintUsage writes the usage line to stdout (parser.print_usage())
+func (p *ArgumentParser) PrintUsage() {
+	PrintOpts(PrintOptions{End: ""}, p.FormatUsage())
+}
+
+// PrintHelp writes the help text to stdout (parser.print_help())
+func (p *ArgumentParser) PrintHelp() {
+	PrintOpts(PrintOptions{End: ""}, p.FormatHelp())
+}
+
+// Error reports a parse error the way argparse does (parser.error(message)):
+// usage and message on stderr and exit status 2, or argparse.ArgumentError
+// when ExitOnError is false
+func (p *ArgumentParser) Error(message string) {
+	if !p.ExitOnError {
+		Raise("argparse.ArgumentError", "%s", message)
+	}
+	fmt.Fprintf(GetStderr(), "%s%s: error: %s\n", p.FormatUsage(), p.Prog, message)
+	os.Exit(2)
+}
+
+// lookupOption finds the option for a command-line word, accepting a unique
+// prefix of a long option as argparse does
+func (p *ArgumentParser) lookupOption(flag string) *argSpec {
+	var matches []*argSpec
+	var names []string
+	for _, a := range p.args {
+		for _, f := range a.flags {
+			if f == flag {
+				return a
+			}
+			if strings.HasPrefix(flag, "--") && strings.HasPrefix(f, flag) {
+				matches = append(matches, a)
+				names = append(names, f)
+			}
+		}
+	}
+	if len(matches) > 1 {
+		p.Error(fmt.Sprintf("ambiguous option: %s could match %s", flag, strings.Join(names, ", ")))
+	}
+	if len(matches) == 1 {
+		return matches[0]
+	}
+	return nil
+}
+
+// convert parses a command-line word with the argument's type
+func (p *ArgumentParser) convert(a *argSpec, word string) (value interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			if exc, ok := asException(r); !ok || exc.PyErr().Type != "ValueError" {
+				panic(r)
+			}
+			p.Error(fmt.Sprintf("argument %s: invalid %s value: %s", a.name(), a.Type, pyQuote(word)))
+		}
+	}()
+	switch a.Type {
+	case "int":
+		return ToIntBase(word, 10)
+	case "float":
+		return parseFloat(word)
+	case "", "str":
+		return word
+	}
+	Raise("ValueError", "unknown type %s", pyQuote(a.Type))
+	return nil
+}
+
+// looksLikeOption reports whether a word is an option string rather than a
+// value; negative numbers such as -5 are values
+func looksLikeOption(word string) bool {
+	if len(word) < 2 || word[0] != '-' {
+		return false
+	}
+	return !strings.ContainsAny(word[1:2], "0123456789.")
+}
+
+// ParseArgs implements parser.parse_args(argv), returning the parsed values;
+// a nil argv parses the command line (sys.argv[1:]), as parse_args() does
+func (p *ArgumentParser) ParseArgs(argv []string) *Namespace {
+	if argv == nil {
+		argv = os.Args[1:]
+	}
+	ns := &Namespace{values: map[string]interface{}{}}
+	for _, a := range p.args {
+		if a.Action != "help" {
+			ns.set(a.Dest, a.Default)
+		}
+	}
+	seen := map[*argSpec]bool{}
+	var positionals, unrecognized []int // indices into argv
+	optionsDone := false
+	for i := 0; i < len(argv); i++ {
+		word := argv[i]
+		if optionsDone || !looksLikeOption(word) {
+			positionals = append(positionals, i)
+			continue
+		}
+		if word == "--" {
+			optionsDone = true
+			continue
+		}
+		flag, value, hasValue := strings.Cut(word, "=")
+		a := p.lookupOption(flag)
+		if a == nil && !strings.HasPrefix(word, "--") && len(word) > 2 {
+			// -c3 attaches the value to a short option
+			flag, value, hasValue = word[:2], word[2:], true
+			if a = p.lookupOption(flag); a != nil && !a.takesValue() {
+				a = nil
+			}
+		}
+		if a == nil {
+			unrecognized = append(unrecognized, i)
+			continue
+		}
+		seen[a] = true
+		switch a.Action {
+		case "help":
+			p.PrintHelp()
+			os.Exit(0)
+		case "store_true", "store_false":
+			if hasValue {
+				p.Error(fmt.Sprintf("argument %s: ignored explicit argument %s", a.name(), pyQuote(value)))
+			}
+			ns.set(a.Dest, a.Action == "store_true")
+		default:
+			if !hasValue {
+				if i+1 >= len(argv) || looksLikeOption(argv[i+1]) {
+					p.Error(fmt.Sprintf("argument %s: expected one argument", a.name()))
+				}
+				i++
+				value = argv[i]
+			}
+			ns.set(a.Dest, p.convert(a, value))
+		}
+	}
+
+	var missing []string
+	for _, a := range p.args {
+		if !a.positional() {
+			if a.Required && !seen[a] {
+				missing = append(missing, a.name())
+			}
+			continue
+		}
+		if len(positionals) == 0 {
+			missing = append(missing, a.name())
+			continue
+		}
+		ns.set(a.Dest, p.convert(a, argv[positionals[0]]))
+		positionals = positionals[1:]
+	}
+	if len(missing) > 0 {
+		p.Error(fmt.Sprintf("the following arguments are required: %s", strings.Join(missing, ", ")))
+	}
+	unrecognized = append(unrecognized, positionals...)
+	if len(unrecognized) > 0 {
+		sort.Ints(unrecognized)
+		words := make([]string, len(unrecognized))
+		for i, index := range unrecognized {
+			words[i] = argv[index]
+		}
+		p.Error(fmt.Sprintf("unrecognized arguments: %s", strings.Join(words, " ")))
+	}
+	return ns
+}
+
+// Namespace is the object parse_args() returns; args.name reads a value
+type Namespace struct {
+	values map[string]interface{}
+	names  []string // in the order the arguments were added
+}
+
+// set stores the value of an argument
+func (ns *Namespace) set(name string, value interface{}) {
+	if _, ok := ns.values[name]; !ok {
+		ns.names = append(ns.names, name)
+	}
+	ns.values[name] = value
+}
+
+// Get returns the value of an argument, raising AttributeError for an unknown name
+func (ns *Namespace) Get(name string) interface{} {
+	value, ok := ns.values[name]
+	if !ok {
+		Raise("AttributeError", "'Namespace' object has no attribute %s", pyQuote(name))
+	}
+	return value
+}
+
+// GetInt returns an int argument (type=int)
+func (ns *Namespace) GetInt(name string) int {
+	value, _ := ns.Get(name).(int)
+	return value
+}
+
+// GetFloat returns a float argument (type=float); an int default is widened
+func (ns *Namespace) GetFloat(name string) float64 {
+	value, _ := asFloat(ns.Get(name))
+	return value
+}
+
+// GetStr returns a str argument
+func (ns *Namespace) GetStr(name string) string {
+	value, _ := ns.Get(name).(string)
+	return value
+}
+
+// GetBool returns a store_true or store_false flag
+func (ns *Namespace) GetBool(name string) bool {
+	return ToBool(ns.Get(name))
+}
+
+// String renders the namespace like Python's repr: Namespace(n=5, count=3)
+func (ns *Namespace) String() string {
+	parts := make([]string, len(ns.names))
+	for i, name := range ns.names {
+		parts[i] = name + "=" + Repr(ns.values[name])
+	}
+	return "Namespace(" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,165 @@
+package mgen
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Attribute name mapping between Python attribute names and Go struct fields.
+// Generated structs use CamelCase field names (self.x_pos -> XPos), which the
+// runtime derives automatically; RegisterAttrs records explicit overrides.
+// RegisterProperties marks names that are computed by a class's @property
+// getter and setter methods rather than stored in a field.
+var (
+	attrNamesMu sync.RWMutex
+	attrNames   = map[reflect.Type]map[string]string{}
+	properties  = map[reflect.Type]map[string]Property{}
+)
+
+// Property is a computed attribute (Python @property). Get and Set receive the
+// object pointer; Set is nil for a read-only property.
+type Property struct {
+	Get func(obj interface{}) interface{}
+	Set func(obj interface{}, value interface{})
+}
+
+// RegisterAttrs records the Python-name -> Go-field mapping for a generated struct type
+func RegisterAttrs(obj interface{}, names map[string]string) {
+	t := structType(reflect.TypeOf(obj))
+	attrNamesMu.Lock()
+	defer attrNamesMu.Unlock()
+	if attrNames[t] == nil {
+		attrNames[t] = map[string]string{}
+	}
+	for pyName, goName := range names {
+		attrNames[t][pyName] = goName
+	}
+}
+
+// RegisterProperties records the properties of a generated struct type
+func RegisterProperties(obj interface{}, props map[string]Property) {
+	t := structType(reflect.TypeOf(obj))
+	attrNamesMu.Lock()
+	defer attrNamesMu.Unlock()
+	if properties[t] == nil {
+		properties[t] = map[string]Property{}
+	}
+	for name, prop := range props {
+		properties[t][name] = prop
+	}
+}
+
+// lookupProperty returns the property registered for obj's type under name
+func lookupProperty(obj interface{}, name string) (Property, bool) {
+	attrNamesMu.RLock()
+	defer attrNamesMu.RUnlock()
+	prop, ok := properties[structType(reflect.TypeOf(obj))][name]
+	return prop, ok
+}
+
+// goFieldName maps a Python attribute name to the Go field name of a struct type
+func goFieldName(t reflect.Type, name string) string {
+	attrNamesMu.RLock()
+	goName, ok := attrNames[t][name]
+	attrNamesMu.RUnlock()
+	if ok {
+		return goName
+	}
+
+	parts := strings.Split(name, "_")
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// structType strips pointer indirections from a type
+func structType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// attrField resolves the settable struct field for a Python attribute name.
+// The object must be a pointer to a struct; an unknown attribute raises AttributeError.
+func attrField(obj interface{}, name string) reflect.Value {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		Raise("AttributeError", "'%s' object has no attribute '%s'", pyTypeName(obj), name)
+	}
+	elem := v.Elem()
+	field := elem.FieldByName(goFieldName(elem.Type(), name))
+	if !field.IsValid() || !field.CanSet() {
+		Raise("AttributeError", "'%s' object has no attribute '%s'", elem.Type().Name(), name)
+	}
+	return field
+}
+
+// GetAttr implements getattr(obj, name) for generated structs: properties call
+// their getter and other names read the struct field
+func GetAttr(obj interface{}, name string) interface{} {
+	if ns, ok := obj.(*Namespace); ok {
+		return ns.Get(name)
+	}
+	if prop, ok := lookupProperty(obj, name); ok {
+		return prop.Get(obj)
+	}
+	return attrField(obj, name).Interface()
+}
+
+// SetAttr implements setattr(obj, name, value) for generated structs. Properties
+// call their setter, raising AttributeError when they are read-only; fields
+// raise TypeError for values of the wrong type.
+func SetAttr(obj interface{}, name string, value interface{}) {
+	if prop, ok := lookupProperty(obj, name); ok {
+		if prop.Set == nil {
+			Raise("AttributeError", "property '%s' of '%s' object has no setter", name, structType(reflect.TypeOf(obj)).Name())
+		}
+		prop.Set(obj, value)
+		return
+	}
+	field := attrField(obj, name)
+	v := reflect.ValueOf(value)
+	if value == nil {
+		switch field.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			v = reflect.Zero(field.Type())
+		}
+	}
+	if v.IsValid() && v.Kind() == reflect.Int && field.Kind() == reflect.Float64 {
+		// An int stored in a float attribute keeps its value, as in Python
+		v = v.Convert(field.Type())
+	}
+	if !v.IsValid() || !v.Type().AssignableTo(field.Type()) {
+		Raise("TypeError", "cannot assign '%s' to attribute '%s'", pyTypeName(value), name)
+	}
+	field.Set(v)
+}
+
+// DelAttr implements Python's `del obj.name` for generated structs.
+// Go structs have a fixed layout, so the field is reset to its zero value
+// rather than removed; unknown attributes raise AttributeError, and so do
+// properties, which have no deleter.
+func DelAttr(obj interface{}, name string) {
+	if _, ok := lookupProperty(obj, name); ok {
+		Raise("AttributeError", "property '%s' of '%s' object has no deleter", name, structType(reflect.TypeOf(obj)).Name())
+	}
+	field := attrField(obj, name)
+	field.Set(reflect.Zero(field.Type()))
+}
+
+// ClassRef is the class object a classmethod receives as cls. Generated code
+// declares one per class with classmethods ({Class}Class) and passes it
+// whether the method is called through the class or through an instance.
+type ClassRef struct {
+	Name string
+}
+
+// String renders the class like Python's repr of a class object
+func (c ClassRef) String() string {
+	return "<class '__main__." + c.Name + "'>"
+}
@@ -0,0 +1,102 @@
+package mgen
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// Binary-to-text encodings
+//
+// The base64 and binascii modules. Encoders take bytes and return bytes;
+// decoders also accept an ASCII str. Malformed input raises binascii.Error,
+// a subclass of ValueError. Like Python's default (non-validating) base64
+// decoding, characters outside the alphabet are skipped and decoding stops
+// at the padding that completes a group.
+
+// Base64Encode implements base64.b64encode(data)
+func Base64Encode(data interface{}) PyBytes {
+	return PyBytes(base64.StdEncoding.EncodeToString(bytesLike(data)))
+}
+
+// Base64Decode implements base64.b64decode(data)
+func Base64Decode(data interface{}) PyBytes {
+	return decodeBase64(asciiBytes(data))
+}
+
+// URLSafeBase64Encode implements base64.urlsafe_b64encode(data), which uses
+// '-' and '_' in place of '+' and '/'
+func URLSafeBase64Encode(data interface{}) PyBytes {
+	return PyBytes(base64.URLEncoding.EncodeToString(bytesLike(data)))
+}
+
+// URLSafeBase64Decode implements base64.urlsafe_b64decode(data)
+func URLSafeBase64Decode(data interface{}) PyBytes {
+	urlsafe := strings.NewReplacer("-", "+", "_", "/").Replace(string(asciiBytes(data)))
+	return decodeBase64(PyBytes(urlsafe))
+}
+
+// decodeBase64 decodes like binascii.a2b_base64 in non-strict mode
+func decodeBase64(data PyBytes) PyBytes {
+	out := PyBytes{}
+	quadPos, pads := 0, 0
+	var left byte
+	for _, c := range data {
+		if c == '=' {
+			pads++
+			if quadPos >= 2 && quadPos+pads >= 4 {
+				return out
+			}
+			continue
+		}
+		value := strings.IndexByte(base64Alphabet, c)
+		if value < 0 {
+			continue
+		}
+		v := byte(value)
+		pads = 0
+		switch quadPos {
+		case 0:
+			left = v
+		case 1:
+			out = append(out, left<<2|v>>4)
+			left = v & 0x0f
+		case 2:
+			out = append(out, left<<4|v>>2)
+			left = v & 0x03
+		case 3:
+			out = append(out, left<<6|v)
+		}
+		quadPos = (quadPos + 1) % 4
+	}
+	switch quadPos {
+	case 0:
+		return out
+	case 1:
+		Raise("binascii.Error", "Invalid base64-encoded string: number of data characters (%d) cannot be 1 more than a multiple of 4",
+			len(out)/3*4+1)
+	default:
+		Raise("binascii.Error", "Incorrect padding")
+	}
+	return nil
+}
+
+const base64Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// Hexlify implements binascii.hexlify(data): two lowercase hex digits per byte
+func Hexlify(data interface{}) PyBytes {
+	return PyBytes(hex.EncodeToString(bytesLike(data)))
+}
+
+// Unhexlify implements binascii.unhexlify(data), the inverse of Hexlify
+func Unhexlify(data interface{}) PyBytes {
+	digits := asciiBytes(data)
+	if len(digits)%2 != 0 {
+		Raise("binascii.Error", "Odd-length string")
+	}
+	out, err := hex.DecodeString(string(digits))
+	if err != nil {
+		Raise("binascii.Error", "Non-hexadecimal digit found")
+	}
+	return PyBytes(out)
+}
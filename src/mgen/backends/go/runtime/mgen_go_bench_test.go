@@ -0,0 +1,191 @@
+package mgen
+
+// Benchmarks of the runtime's fast paths against the slower code they
+// replace. The builder leaves _test.go files out of generated projects; run
+// them with make benchmark-go-runtime.
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// benchSink keeps the results of benchmarked work, so it is not optimized away
+var benchSink interface{}
+
+// dynamicValues returns n ints boxed as interface{} and a 1000-int haystack
+func dynamicValues(n int) ([]interface{}, []int) {
+	values := make([]interface{}, n)
+	for i := range values {
+		values[i] = i
+	}
+	haystack := make([]int, 1000)
+	for i := range haystack {
+		haystack[i] = i
+	}
+	return values, haystack
+}
+
+// BenchmarkDynamicOps sums, prints and searches interface{} values through
+// the type switches of BinOp, ToStr and Contains
+func BenchmarkDynamicOps(b *testing.B) {
+	values, haystack := dynamicValues(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var sum interface{} = 0
+		chars, found := 0, 0
+		for _, v := range values {
+			sum = BinOp("+", sum, v)
+			chars += len(ToStr(v))
+			if Contains(haystack, v.(int)%2000) {
+				found++
+			}
+		}
+		benchSink = []interface{}{sum, chars, found}
+	}
+}
+
+// BenchmarkDynamicOpsReflection does the work of BenchmarkDynamicOps through
+// reflection and fmt, as the runtime did before the type switches
+func BenchmarkDynamicOpsReflection(b *testing.B) {
+	values, haystack := dynamicValues(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sum, chars, found := int64(0), 0, 0
+		for _, v := range values {
+			sum += reflect.ValueOf(v).Int()
+			chars += len(fmt.Sprintf("%v", v))
+			items := reflect.ValueOf(haystack)
+			for j := 0; j < items.Len(); j++ {
+				if items.Index(j).Interface() == interface{}(v.(int)%2000) {
+					found++
+					break
+				}
+			}
+		}
+		benchSink = []interface{}{sum, chars, found}
+	}
+}
+
+// pyListValue returns a PyList of 1000 ints boxed as interface{}
+func pyListValue() interface{} {
+	list := NewPyList()
+	for i := 0; i < 1000; i++ {
+		list.Append(i)
+	}
+	return list
+}
+
+// BenchmarkLenValue checks truthiness and len() of a boxed PyList through
+// the type switches of ToBool and LenValue
+func BenchmarkLenValue(b *testing.B) {
+	x := pyListValue()
+	total := 0
+	for i := 0; i < b.N; i++ {
+		if ToBool(x) {
+			total += LenValue(x)
+		}
+	}
+	benchSink = total
+}
+
+// BenchmarkLenValueReflection does the work of BenchmarkLenValue through
+// reflection, as the runtime did before the type switches
+func BenchmarkLenValueReflection(b *testing.B) {
+	x := pyListValue()
+	total := 0
+	for i := 0; i < b.N; i++ {
+		if v := reflect.ValueOf(x).Elem().FieldByName("Items"); v.Len() > 0 {
+			total += v.Len()
+		}
+	}
+	benchSink = total
+}
+
+// BenchmarkDequePopLeft drains a 10000-item queue with Deque.PopLeft
+func BenchmarkDequePopLeft(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		d := NewDeque[int]()
+		for j := 0; j < 10000; j++ {
+			d.Append(j)
+		}
+		total := 0
+		for d.Len() > 0 {
+			total += d.PopLeft()
+		}
+		benchSink = total
+	}
+}
+
+// BenchmarkSlicePopFront drains the same queue from a slice as list.pop(0)
+// does, shifting the remaining items down each time
+func BenchmarkSlicePopFront(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		xs := make([]int, 0, 10000)
+		for j := 0; j < 10000; j++ {
+			xs = append(xs, j)
+		}
+		total := 0
+		for len(xs) > 0 {
+			total += xs[0]
+			xs = slices.Delete(xs, 0, 1)
+		}
+		benchSink = total
+	}
+}
+
+// BenchmarkDictInsertion counts 10000 inserts into 500 keys of a Dict[int, int]
+// and iterates its items in insertion order
+func BenchmarkDictInsertion(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		typed := NewDict[int, int]()
+		for j := 0; j < 10000; j++ {
+			typed.Set(j%500, typed.GetOr(j%500, 0)+j)
+		}
+		total := 0
+		for _, kv := range typed.Items() {
+			total += kv.Key * kv.Value
+		}
+		benchSink = total
+	}
+}
+
+// BenchmarkPyDictInsertion does the work of BenchmarkDictInsertion on the
+// boxed PyDict
+func BenchmarkPyDictInsertion(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		boxed := NewPyDict()
+		for j := 0; j < 10000; j++ {
+			boxed.Set(j%500, boxed.GetOr(j%500, 0).(int)+j)
+		}
+		total := 0
+		for _, e := range boxed.Items() {
+			total += e.Key.(int) * e.Value.(int)
+		}
+		benchSink = total
+	}
+}
+
+// BenchmarkStringAccumulator appends 10000 short strings through a StringAccumulator
+func BenchmarkStringAccumulator(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		acc := NewStringAccumulator("")
+		for j := 0; j < 10000; j++ {
+			acc.Add("xy")
+		}
+		benchSink = acc.String()
+	}
+}
+
+// BenchmarkStringConcatenation appends the same strings with +=, copying the
+// whole string each time
+func BenchmarkStringConcatenation(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		s := ""
+		for j := 0; j < 10000; j++ {
+			s += "xy"
+		}
+		benchSink = s
+	}
+}
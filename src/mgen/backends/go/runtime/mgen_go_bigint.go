@@ -0,0 +1,243 @@
+package mgen
+
+import "math/big"
+
+// Arbitrary-precision integers
+//
+// Python ints never overflow, while Go's int wraps around at 64 bits. With the
+// int_precision preference set to "auto", the ints the converter cannot show
+// to stay within int64 (a running product, a Fibonacci pair, 2 ** n) are typed
+// *PyInt, a math/big.Int, and every other int stays a native int. PyInt
+// values are immutable: each operation returns a new value, so they can be
+// shared and reassigned like Python ints.
+
+// PyInt is an int that never overflows
+type PyInt struct {
+	value big.Int
+}
+
+// NewPyInt returns n as a PyInt
+func NewPyInt(n int) *PyInt {
+	x := &PyInt{}
+	x.value.SetInt64(int64(n))
+	return x
+}
+
+// ParsePyInt returns the base-10 literal digits as a PyInt (an int literal beyond int64)
+func ParsePyInt(digits string) *PyInt {
+	x := &PyInt{}
+	if _, ok := x.value.SetString(digits, 10); !ok {
+		Raise("ValueError", "invalid literal for int() with base 10: %s", pyQuote(digits))
+	}
+	return x
+}
+
+// Add returns x + y
+func (x *PyInt) Add(y *PyInt) *PyInt {
+	z := &PyInt{}
+	z.value.Add(&x.value, &y.value)
+	return z
+}
+
+// Sub returns x - y
+func (x *PyInt) Sub(y *PyInt) *PyInt {
+	z := &PyInt{}
+	z.value.Sub(&x.value, &y.value)
+	return z
+}
+
+// Mul returns x * y
+func (x *PyInt) Mul(y *PyInt) *PyInt {
+	z := &PyInt{}
+	z.value.Mul(&x.value, &y.value)
+	return z
+}
+
+// FloorDiv returns x // y, rounding toward negative infinity
+func (x *PyInt) FloorDiv(y *PyInt) *PyInt {
+	q, _ := x.divMod(y)
+	return q
+}
+
+// Mod returns x % y, which takes the sign of y
+func (x *PyInt) Mod(y *PyInt) *PyInt {
+	_, m := x.divMod(y)
+	return m
+}
+
+// divMod returns the floored quotient and remainder of x and y (divmod(x, y))
+func (x *PyInt) divMod(y *PyInt) (*PyInt, *PyInt) {
+	if y.value.Sign() == 0 {
+		Raise("ZeroDivisionError", "integer division or modulo by zero")
+	}
+	q, m := &PyInt{}, &PyInt{}
+	// QuoRem truncates toward zero; Python floors, so a remainder whose sign
+	// differs from the divisor's moves the quotient down by one
+	q.value.QuoRem(&x.value, &y.value, &m.value)
+	if m.value.Sign() != 0 && m.value.Sign() != y.value.Sign() {
+		q.value.Sub(&q.value, big.NewInt(1))
+		m.value.Add(&m.value, &y.value)
+	}
+	return q, m
+}
+
+// TrueDiv returns x / y as a float
+func (x *PyInt) TrueDiv(y *PyInt) float64 {
+	if y.value.Sign() == 0 {
+		Raise("ZeroDivisionError", "division by zero")
+	}
+	quotient, _ := new(big.Rat).SetFrac(&x.value, &y.value).Float64()
+	return quotient
+}
+
+// Pow returns x ** y; a negative exponent, whose result Python makes a
+// float, raises ValueError
+func (x *PyInt) Pow(y *PyInt) *PyInt {
+	if y.value.Sign() < 0 {
+		Raise("ValueError", "negative exponent %s for an arbitrary-precision int", y.String())
+	}
+	z := &PyInt{}
+	z.value.Exp(&x.value, &y.value, nil)
+	return z
+}
+
+// Lsh returns x << n
+func (x *PyInt) Lsh(n int) *PyInt {
+	if n < 0 {
+		Raise("ValueError", "negative shift count")
+	}
+	z := &PyInt{}
+	z.value.Lsh(&x.value, uint(n))
+	return z
+}
+
+// Rsh returns x >> n, rounding toward negative infinity like Python
+func (x *PyInt) Rsh(n int) *PyInt {
+	if n < 0 {
+		Raise("ValueError", "negative shift count")
+	}
+	z := &PyInt{}
+	z.value.Rsh(&x.value, uint(n))
+	return z
+}
+
+// And returns x & y
+func (x *PyInt) And(y *PyInt) *PyInt {
+	z := &PyInt{}
+	z.value.And(&x.value, &y.value)
+	return z
+}
+
+// Or returns x | y
+func (x *PyInt) Or(y *PyInt) *PyInt {
+	z := &PyInt{}
+	z.value.Or(&x.value, &y.value)
+	return z
+}
+
+// Xor returns x ^ y
+func (x *PyInt) Xor(y *PyInt) *PyInt {
+	z := &PyInt{}
+	z.value.Xor(&x.value, &y.value)
+	return z
+}
+
+// Neg returns -x
+func (x *PyInt) Neg() *PyInt {
+	z := &PyInt{}
+	z.value.Neg(&x.value)
+	return z
+}
+
+// Invert returns ~x
+func (x *PyInt) Invert() *PyInt {
+	z := &PyInt{}
+	z.value.Not(&x.value)
+	return z
+}
+
+// Cmp compares x and y, returning -1, 0 or +1
+func (x *PyInt) Cmp(y *PyInt) int {
+	return x.value.Cmp(&y.value)
+}
+
+// Int returns x as an int, raising OverflowError when it does not fit
+func (x *PyInt) Int() int {
+	if !x.value.IsInt64() {
+		Raise("OverflowError", "Python int too large to convert to C long")
+	}
+	return int(x.value.Int64())
+}
+
+// Float returns x as a float (float(x))
+func (x *PyInt) Float() float64 {
+	f, _ := new(big.Float).SetInt(&x.value).Float64()
+	return f
+}
+
+// String returns the decimal digits of x (str(x))
+func (x *PyInt) String() string {
+	return x.value.String()
+}
+
+// asPyInt returns x as a PyInt when it is one or a Go integer (or bool)
+func asPyInt(x interface{}) (*PyInt, bool) {
+	if v, ok := x.(*PyInt); ok {
+		return v, true
+	}
+	if n, ok := asInt(x); ok {
+		return NewPyInt(int(n)), true
+	}
+	return nil, false
+}
+
+// isPyInt reports whether x is a PyInt
+func isPyInt(x interface{}) bool {
+	_, ok := x.(*PyInt)
+	return ok
+}
+
+// bigOperands returns a and b as PyInts when one is a PyInt and the other an integer
+func bigOperands(a, b interface{}) (*PyInt, *PyInt, bool) {
+	if !isPyInt(a) && !isPyInt(b) {
+		return nil, nil, false
+	}
+	ab, aok := asPyInt(a)
+	bb, bok := asPyInt(b)
+	return ab, bb, aok && bok
+}
+
+// bigBinOp applies a Python operator to two integers of which at least one is a PyInt
+func bigBinOp(op string, a, b *PyInt) interface{} {
+	switch op {
+	case "+":
+		return a.Add(b)
+	case "-":
+		return a.Sub(b)
+	case "*":
+		return a.Mul(b)
+	case "/":
+		return a.TrueDiv(b)
+	case "//":
+		return a.FloorDiv(b)
+	case "%":
+		return a.Mod(b)
+	case "**":
+		if b.value.Sign() < 0 {
+			return floatBinOp("**", a.Float(), b.Float())
+		}
+		return a.Pow(b)
+	case "<<":
+		return a.Lsh(b.Int())
+	case ">>":
+		return a.Rsh(b.Int())
+	case "&":
+		return a.And(b)
+	case "|":
+		return a.Or(b)
+	case "^":
+		return a.Xor(b)
+	}
+	Raise("TypeError", "unsupported operand type(s) for %s: 'int' and 'int'", op)
+	return nil
+}
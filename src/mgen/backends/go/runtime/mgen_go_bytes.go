@@ -0,0 +1,495 @@
+package mgen
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// PyBytes is Python's immutable bytes type. It is a distinct slice type so
+// that repr() prints b'...' instead of a list of ints.
+type PyBytes []byte
+
+// String renders the bytes like Python's repr: b'ab\x00', b"it's"
+func (b PyBytes) String() string {
+	quote := byte('\'')
+	if strings.IndexByte(string(b), '\'') >= 0 && strings.IndexByte(string(b), '"') < 0 {
+		quote = '"'
+	}
+	var sb strings.Builder
+	sb.WriteByte('b')
+	sb.WriteByte(quote)
+	for _, c := range b {
+		switch {
+		case c == quote || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c == '\t':
+			sb.WriteString(`\t`)
+		case c == '\n':
+			sb.WriteString(`\n`)
+		case c == '\r':
+			sb.WriteString(`\r`)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&sb, `\x%02x`, c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte(quote)
+	return sb.String()
+}
+
+// bytesLike returns data as bytes, raising TypeError for anything that is
+// not bytes-like (including str, which Python requires to be encoded first)
+func bytesLike(data interface{}) PyBytes {
+	switch v := data.(type) {
+	case PyBytes:
+		return v
+	case []byte:
+		return PyBytes(v)
+	case *PyByteArray:
+		return v.Data
+	}
+	Raise("TypeError", "a bytes-like object is required, not '%s'", pyTypeName(data))
+	return nil
+}
+
+// asciiBytes returns the argument of a decoding function, which like
+// binascii's accepts bytes or a str of ASCII characters
+func asciiBytes(data interface{}) PyBytes {
+	s, ok := data.(string)
+	if !ok {
+		return bytesLike(data)
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			Raise("ValueError", "string argument should contain only ASCII characters")
+		}
+	}
+	return PyBytes(s)
+}
+
+// bytesOperands returns a and b as bytes when both are bytes or bytearray,
+// which compare with each other by content as in Python
+func bytesOperands(a, b interface{}) (PyBytes, PyBytes, bool) {
+	switch a.(type) {
+	case PyBytes, *PyByteArray:
+		switch b.(type) {
+		case PyBytes, *PyByteArray:
+			return bytesLike(a), bytesLike(b), true
+		}
+	}
+	return nil, nil, false
+}
+
+// NewBytes implements bytes(source[, encoding[, errors]]): bytes(3) is three
+// zero bytes, bytes([65, 66]) takes ints in range(0, 256), bytes(s, "utf-8")
+// encodes a str and a bytes-like source is copied
+func NewBytes(source interface{}, encoding ...string) PyBytes {
+	return makeBytes("bytes", source, encoding)
+}
+
+// makeBytes builds the contents of a new bytes or bytearray (kind)
+func makeBytes(kind string, source interface{}, encoding []string) PyBytes {
+	if s, ok := source.(string); ok {
+		if len(encoding) == 0 {
+			Raise("TypeError", "string argument without an encoding")
+		}
+		errors := "strict"
+		if len(encoding) > 1 {
+			errors = encoding[1]
+		}
+		return StrOps.Encode(s, encoding[0], errors)
+	}
+	if len(encoding) > 0 {
+		Raise("TypeError", "encoding without a string argument")
+	}
+	switch v := source.(type) {
+	case nil:
+		return PyBytes{}
+	case int:
+		if v < 0 {
+			Raise("ValueError", "negative count")
+		}
+		return make(PyBytes, v)
+	case PyBytes, []byte, *PyByteArray:
+		return append(PyBytes{}, bytesLike(v)...)
+	}
+	items := iterValues(source)
+	result := make(PyBytes, len(items))
+	for i, item := range items {
+		result[i] = byteValue(item, kind)
+	}
+	return result
+}
+
+// byteValue checks that x is an int in range(0, 256); kind names the type
+// in the ValueError as Python does ("bytes must be ...", "byte must be ...")
+func byteValue(x interface{}, kind string) byte {
+	n, ok := asInt(x)
+	if !ok {
+		Raise("TypeError", "'%s' object cannot be interpreted as an integer", pyTypeName(x))
+	}
+	if n < 0 || n > 255 {
+		Raise("ValueError", "%s must be in range(0, 256)", kind)
+	}
+	return byte(n)
+}
+
+// BytesFromHex implements bytes.fromhex(s); whitespace may separate the
+// two-digit bytes
+func BytesFromHex(s string) PyBytes {
+	result := PyBytes{}
+	for i := 0; i < len(s); {
+		if strings.IndexByte(" \t\n\r\v\f", s[i]) >= 0 {
+			i++
+			continue
+		}
+		if i+1 >= len(s) {
+			Raise("ValueError", "non-hexadecimal number found in fromhex() arg at position %d", i+1)
+		}
+		digits, err := hex.DecodeString(s[i : i+2])
+		if err != nil {
+			position := i
+			if _, err := hex.DecodeString(s[i:i+1] + "0"); err == nil {
+				position++
+			}
+			Raise("ValueError", "non-hexadecimal number found in fromhex() arg at position %d", position)
+		}
+		result = append(result, digits[0])
+		i += 2
+	}
+	return result
+}
+
+// At implements b[i], which is an int; negative indices count from the end
+func (b PyBytes) At(i int) int {
+	return int(b[byteIndex(len(b), i, "index out of range")])
+}
+
+// byteIndex resolves a possibly negative index into a sequence of n bytes
+func byteIndex(n, i int, message string) int {
+	if i < 0 {
+		i += n
+	}
+	if i < 0 || i >= n {
+		Raise("IndexError", message)
+	}
+	return i
+}
+
+// Slice implements b[start:stop:step], a new bytes
+func (b PyBytes) Slice(s PySlice) PyBytes {
+	return PyBytes(SliceSlice([]byte(b), s))
+}
+
+// Concat implements b + other for any bytes-like other, giving bytes
+func (b PyBytes) Concat(other interface{}) PyBytes {
+	return append(append(PyBytes{}, b...), bytesLike(other)...)
+}
+
+// Repeat implements b * n; a count below one gives empty bytes
+func (b PyBytes) Repeat(n int) PyBytes {
+	if n < 0 {
+		n = 0
+	}
+	return PyBytes(bytes.Repeat(b, n))
+}
+
+// Cmp orders b against a bytes or bytearray like Python's comparison
+// operators, returning -1, 0 or 1
+func (b PyBytes) Cmp(other interface{}) int {
+	return bytes.Compare(b, bytesLike(other))
+}
+
+// Contains implements x in b for an int byte value or a bytes-like
+// subsequence
+func (b PyBytes) Contains(x interface{}) bool {
+	switch x.(type) {
+	case PyBytes, []byte, *PyByteArray:
+		return bytes.Contains(b, bytesLike(x))
+	}
+	if _, ok := asInt(x); !ok {
+		Raise("TypeError", "a bytes-like object is required, not '%s'", pyTypeName(x))
+	}
+	return bytes.IndexByte(b, byteValue(x, "byte")) >= 0
+}
+
+// Ints returns the byte values as ints, which is what iterating b yields
+func (b PyBytes) Ints() []int {
+	result := make([]int, len(b))
+	for i, c := range b {
+		result[i] = int(c)
+	}
+	return result
+}
+
+// Hex implements b.hex(): two lowercase hex digits per byte
+func (b PyBytes) Hex() string {
+	return hex.EncodeToString(b)
+}
+
+// PyByteArray is Python's mutable bytearray. Like PyList it is used through
+// a pointer, so every name bound to it sees its changes.
+type PyByteArray struct {
+	Data PyBytes
+}
+
+// NewByteArray implements bytearray(source[, encoding[, errors]]), taking
+// the same sources as NewBytes; a nil source is an empty bytearray
+func NewByteArray(source interface{}, encoding ...string) *PyByteArray {
+	return &PyByteArray{Data: makeBytes("byte", source, encoding)}
+}
+
+// String renders the bytearray like Python's repr: bytearray(b'ab')
+func (b *PyByteArray) String() string {
+	return "bytearray(" + b.Data.String() + ")"
+}
+
+// Len implements len(b)
+func (b *PyByteArray) Len() int {
+	return len(b.Data)
+}
+
+// At implements b[i]
+func (b *PyByteArray) At(i int) int {
+	return int(b.Data[byteIndex(len(b.Data), i, "bytearray index out of range")])
+}
+
+// SetItem implements b[i] = x for an int x in range(0, 256)
+func (b *PyByteArray) SetItem(i, x int) {
+	b.Data[byteIndex(len(b.Data), i, "bytearray index out of range")] = byteValue(x, "byte")
+}
+
+// Slice implements b[start:stop:step], a new bytearray
+func (b *PyByteArray) Slice(s PySlice) *PyByteArray {
+	return &PyByteArray{Data: b.Data.Slice(s)}
+}
+
+// Append implements b.append(x) for an int x in range(0, 256)
+func (b *PyByteArray) Append(x int) {
+	b.Data = append(b.Data, byteValue(x, "byte"))
+}
+
+// Extend implements b.extend(iterable) for bytes-like values and iterables
+// of ints, and b += other
+func (b *PyByteArray) Extend(iterable interface{}) {
+	if _, ok := iterable.(string); ok {
+		Raise("TypeError", "expected iterable of integers; got: 'str'")
+	}
+	b.Data = append(b.Data, makeBytes("byte", iterable, nil)...)
+}
+
+// Pop implements b.pop([i]), removing and returning the byte at i (the
+// last byte by default)
+func (b *PyByteArray) Pop(index ...int) int {
+	if len(b.Data) == 0 {
+		Raise("IndexError", "pop from empty bytearray")
+	}
+	i := len(b.Data) - 1
+	if len(index) > 0 {
+		i = byteIndex(len(b.Data), index[0], "pop index out of range")
+	}
+	x := b.Data[i]
+	b.Data = append(b.Data[:i], b.Data[i+1:]...)
+	return int(x)
+}
+
+// Clear implements b.clear()
+func (b *PyByteArray) Clear() {
+	b.Data = PyBytes{}
+}
+
+// Concat implements b + other, giving a new bytearray
+func (b *PyByteArray) Concat(other interface{}) *PyByteArray {
+	return &PyByteArray{Data: b.Data.Concat(other)}
+}
+
+// Repeat implements b * n, giving a new bytearray
+func (b *PyByteArray) Repeat(n int) *PyByteArray {
+	return &PyByteArray{Data: b.Data.Repeat(n)}
+}
+
+// Cmp orders b against a bytes or bytearray, returning -1, 0 or 1
+func (b *PyByteArray) Cmp(other interface{}) int {
+	return b.Data.Cmp(other)
+}
+
+// Contains implements x in b
+func (b *PyByteArray) Contains(x interface{}) bool {
+	return b.Data.Contains(x)
+}
+
+// Ints returns the byte values as ints, which is what iterating b yields
+func (b *PyByteArray) Ints() []int {
+	return b.Data.Ints()
+}
+
+// Decode implements b.decode(encoding, errors)
+func (b *PyByteArray) Decode(encoding, errors string) string {
+	return b.Data.Decode(encoding, errors)
+}
+
+// Hex implements b.hex()
+func (b *PyByteArray) Hex() string {
+	return b.Data.Hex()
+}
+
+// Codecs
+//
+// str.encode() and bytes.decode() support the UTF-8, ASCII and Latin-1
+// codecs with the strict, ignore and replace error handlers, raising
+// UnicodeEncodeError and UnicodeDecodeError with Python's messages.
+
+// codecName returns the canonical name of a supported encoding, raising
+// LookupError for any other
+func codecName(encoding string) string {
+	switch strings.ReplaceAll(strings.ToLower(encoding), "_", "-") {
+	case "utf-8", "utf8", "u8", "utf":
+		return "utf-8"
+	case "ascii", "us-ascii", "646":
+		return "ascii"
+	case "latin-1", "latin1", "latin", "l1", "iso-8859-1", "iso8859-1", "8859", "cp819":
+		return "latin-1"
+	}
+	Raise("LookupError", "unknown encoding: %s", encoding)
+	return ""
+}
+
+// checkErrorHandler raises LookupError for an unsupported errors argument
+func checkErrorHandler(errors string) {
+	switch errors {
+	case "strict", "ignore", "replace":
+		return
+	}
+	Raise("LookupError", "unknown error handler name '%s'", errors)
+}
+
+// Encode implements str.encode(encoding, errors)
+func (s StringOps) Encode(str, encoding, errors string) PyBytes {
+	codec := codecName(encoding)
+	checkErrorHandler(errors)
+	if codec == "utf-8" {
+		return PyBytes(str)
+	}
+	limit := rune(0x7f)
+	if codec == "latin-1" {
+		limit = 0xff
+	}
+	runes := []rune(str)
+	result := make(PyBytes, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		if runes[i] <= limit {
+			result = append(result, byte(runes[i]))
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && runes[end] > limit {
+			end++
+		}
+		switch errors {
+		case "strict":
+			what := fmt.Sprintf("character '%s' in position %d", escapeRune(runes[i]), i)
+			if end-i > 1 {
+				what = fmt.Sprintf("characters in position %d-%d", i, end-1)
+			}
+			Raise("UnicodeEncodeError", "'%s' codec can't encode %s: ordinal not in range(%d)", codec, what, limit+1)
+		case "replace":
+			result = append(result, bytes.Repeat([]byte{'?'}, end-i)...)
+		}
+		i = end - 1
+	}
+	return result
+}
+
+// escapeRune renders a code point as Python's repr escapes it: \xe9, \u20ac
+func escapeRune(r rune) string {
+	switch {
+	case r < 0x100:
+		return fmt.Sprintf(`\x%02x`, r)
+	case r < 0x10000:
+		return fmt.Sprintf(`\u%04x`, r)
+	}
+	return fmt.Sprintf(`\U%08x`, r)
+}
+
+// Decode implements bytes.decode(encoding, errors)
+func (b PyBytes) Decode(encoding, errors string) string {
+	codec := codecName(encoding)
+	checkErrorHandler(errors)
+	var out strings.Builder
+	for i := 0; i < len(b); {
+		end, reason := i+1, ""
+		switch {
+		case codec == "latin-1":
+			out.WriteRune(rune(b[i]))
+		case codec == "ascii" && b[i] >= 0x80:
+			reason = "ordinal not in range(128)"
+		case codec == "ascii":
+			out.WriteByte(b[i])
+		default:
+			r, size := utf8.DecodeRune(b[i:])
+			if r == utf8.RuneError && size <= 1 {
+				end, reason = utf8ErrorEnd(b, i)
+			} else {
+				out.WriteRune(r)
+				end = i + size
+			}
+		}
+		if reason != "" {
+			switch errors {
+			case "strict":
+				what := fmt.Sprintf("byte 0x%02x in position %d", b[i], i)
+				if end-i > 1 {
+					what = fmt.Sprintf("bytes in position %d-%d", i, end-1)
+				}
+				Raise("UnicodeDecodeError", "'%s' codec can't decode %s: %s", codec, what, reason)
+			case "replace":
+				out.WriteRune(utf8.RuneError)
+			}
+		}
+		i = end
+	}
+	return out.String()
+}
+
+// utf8ErrorEnd returns the end of the invalid UTF-8 sequence starting at i
+// and why it is invalid, grouping a truncated sequence into one error as
+// Python does
+func utf8ErrorEnd(b PyBytes, i int) (int, string) {
+	c := b[i]
+	need, lo, hi := 0, byte(0x80), byte(0xbf)
+	switch {
+	case c >= 0xc2 && c <= 0xdf:
+		need = 1
+	case c == 0xe0:
+		need, lo = 2, 0xa0
+	case c == 0xed:
+		need, hi = 2, 0x9f
+	case c >= 0xe1 && c <= 0xef:
+		need = 2
+	case c == 0xf0:
+		need, lo = 3, 0x90
+	case c == 0xf4:
+		need, hi = 3, 0x8f
+	case c >= 0xf1 && c <= 0xf3:
+		need = 3
+	default:
+		return i + 1, "invalid start byte"
+	}
+	j := i + 1
+	for k := 0; k < need; k++ {
+		if j >= len(b) {
+			return j, "unexpected end of data"
+		}
+		if b[j] < lo || b[j] > hi {
+			return j, "invalid continuation byte"
+		}
+		lo, hi = 0x80, 0xbf
+		j++
+	}
+	return j, "invalid continuation byte"
+}
@@ -0,0 +1,93 @@
+package mgen
+
+import "reflect"
+
+// Class hierarchies
+//
+// A subclass embeds its base class's struct, and a class other classes
+// derive from also gets an interface, <Class>Interface, holding its methods
+// and As<Class>() *<Class>, which reaches the embedded base. Pointers to the
+// class and to each class derived from it implement the interface, so values
+// of the base class's type are stored as the interface and their method
+// calls dispatch to the overriding methods, as in Python.
+
+// asProtocol returns value as the protocol interface T (such as PySized),
+// finding the methods generated with a pointer receiver on a copy when value
+// is a struct passed by value
+func asProtocol[T any](value interface{}) (T, bool) {
+	if p, ok := value.(T); ok {
+		return p, true
+	}
+	var zero T
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return zero, false
+	}
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	p, ok := ptr.Interface().(T)
+	return p, ok
+}
+
+// PySized is implemented by classes with a Python __len__ method
+type PySized interface {
+	PyLen() int
+}
+
+// PyEquatable is implemented by classes with a Python __eq__ method: Equals
+// calls it when other is an instance of the class, and is false otherwise
+type PyEquatable interface {
+	Equals(other interface{}) bool
+}
+
+// PyHashable is implemented by classes with Python __eq__ and __hash__
+// methods; dicts and sets bucket such keys by Hash and match them with Equals
+type PyHashable interface {
+	PyEquatable
+	Hash() int
+}
+
+// classKey reports whether keys of type K are instances of a class defining
+// __eq__, which dicts and sets must compare with Equals rather than ==
+func classKey[K any]() bool {
+	t := reflect.TypeOf((*K)(nil)).Elem()
+	equatable := reflect.TypeOf((*PyEquatable)(nil)).Elem()
+	return t.Implements(equatable) || reflect.PointerTo(t).Implements(equatable)
+}
+
+// classHash returns hash(key) for an instance of a class defining __eq__,
+// raising TypeError when the class does not define __hash__ as well
+func classHash(key interface{}) int {
+	h, ok := asProtocol[PyHashable](key)
+	if !ok {
+		Raise("TypeError", "unhashable type: '%s'", pyTypeName(key))
+	}
+	return h.Hash()
+}
+
+// AsInstance returns x as the class type T when it holds a T or a *T, for
+// the Equals method wrapping a class's __eq__
+func AsInstance[T any](x interface{}) (T, bool) {
+	if v, ok := x.(T); ok {
+		return v, true
+	}
+	if p, ok := x.(*T); ok && p != nil {
+		return *p, true
+	}
+	var zero T
+	return zero, false
+}
+
+// Ref returns a pointer to a copy of v, to store a struct value returned by a
+// call where the interface of its class is expected, or a list several
+// variables share
+func Ref[T any](v T) *T {
+	return &v
+}
+
+// InstanceOf reports whether x holds a T: for isinstance(x, Class), T is the
+// class's interface when classes derive from it, else *Class
+func InstanceOf[T any](x interface{}) bool {
+	_, ok := x.(T)
+	return ok
+}
@@ -0,0 +1,132 @@
+package mgen
+
+import "sort"
+
+// defaultdict and Counter
+//
+// collections.defaultdict and collections.Counter are dicts that supply a
+// value for a missing key, so both are Dicts with a missingValues record and
+// every dict operation (iteration, len, in, items, ==) works on them
+// unchanged. A defaultdict calls its factory and stores the result, as
+// d[k].append(x) relies on; a Counter reads a missing count as 0 without
+// storing it. Counter's own methods need int values, which a method on the
+// generic Dict cannot require, so they are functions taking a Dict[K, int].
+
+// missingValues is what a defaultdict or Counter yields for a missing key
+type missingValues[V any] struct {
+	kind    string   // "defaultdict" or "Counter", for repr and type(d).__name__
+	factory func() V // defaultdict's default_factory; nil raises KeyError
+	name    string   // the factory as repr shows it: <class 'list'>
+}
+
+// NewDefaultDict builds a defaultdict (defaultdict(factory, entries)); name is
+// the factory as repr shows it, such as "<class 'list'>", and a nil factory
+// ("None") makes missing keys raise KeyError like a plain dict
+func NewDefaultDict[K comparable, V any](factory func() V, name string, entries ...KV[K, V]) *Dict[K, V] {
+	d := NewDict(entries...)
+	d.missing = &missingValues[V]{kind: "defaultdict", factory: factory, name: name}
+	return d
+}
+
+// NewCounter counts the items of an iterable (Counter(items))
+func NewCounter[K comparable](items []K) *Dict[K, int] {
+	c := NewDict[K, int]()
+	c.missing = &missingValues[int]{kind: "Counter"}
+	CounterUpdate(c, items)
+	return c
+}
+
+// missingValue returns d[key] for a key d does not hold: the defaultdict
+// factory's value, which is stored, a Counter's 0, or KeyError for a plain dict
+func (d *Dict[K, V]) missingValue(key K) V {
+	var zero V
+	switch {
+	case d.missing != nil && d.missing.factory != nil:
+		value := d.missing.factory()
+		d.Set(key, value)
+		return value
+	case d.isCounter():
+		return zero
+	}
+	Raise("KeyError", "%s", Repr(key))
+	return zero
+}
+
+// isCounter reports whether d is a Counter
+func (d *Dict[K, V]) isCounter() bool {
+	return d.missing != nil && d.missing.kind == "Counter"
+}
+
+// kind returns "dict", "defaultdict" or "Counter"
+func (d *Dict[K, V]) kind() string {
+	if d.missing == nil {
+		return "dict"
+	}
+	return d.missing.kind
+}
+
+// wrapRepr wraps the {...} repr of the entries the way d's kind prints:
+// defaultdict(<class 'int'>, {'a': 1}) or Counter({'a': 2, 'b': 1})
+func (d *Dict[K, V]) wrapRepr(body string) string {
+	switch d.kind() {
+	case "defaultdict":
+		return "defaultdict(" + d.missing.name + ", " + body + ")"
+	case "Counter":
+		if d.Len() == 0 {
+			return "Counter()"
+		}
+		return "Counter(" + body + ")"
+	}
+	return body
+}
+
+// CounterUpdate adds one to the count of each item (c.update(items))
+func CounterUpdate[K comparable](c *Dict[K, int], items []K) {
+	for _, item := range items {
+		c.Set(item, c.GetOr(item, 0)+1)
+	}
+}
+
+// CounterSubtract takes one from the count of each item, letting counts go
+// to zero or below (c.subtract(items))
+func CounterSubtract[K comparable](c *Dict[K, int], items []K) {
+	for _, item := range items {
+		c.Set(item, c.GetOr(item, 0)-1)
+	}
+}
+
+// MostCommon returns the n most common items and their counts, most common
+// first and ties in insertion order; a negative n returns them all
+// (c.most_common(n))
+func MostCommon[K comparable](c *Dict[K, int], n int) []Pair[K, int] {
+	result := make([]Pair[K, int], len(c.entries))
+	for i, e := range c.entries {
+		result[i] = Pair[K, int]{First: e.Key, Second: e.Value}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Second > result[j].Second })
+	if n >= 0 && n < len(result) {
+		result = result[:n]
+	}
+	return result
+}
+
+// CounterTotal returns the sum of the counts (c.total())
+func CounterTotal[K comparable](c *Dict[K, int]) int {
+	total := 0
+	for _, e := range c.entries {
+		total += e.Value
+	}
+	return total
+}
+
+// CounterElements repeats each item as many times as its count, skipping
+// counts below one (list(c.elements()))
+func CounterElements[K comparable](c *Dict[K, int]) []K {
+	var result []K
+	for _, e := range c.entries {
+		for i := 0; i < e.Value; i++ {
+			result = append(result, e.Key)
+		}
+	}
+	return result
+}
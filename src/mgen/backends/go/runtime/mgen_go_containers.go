@@ -0,0 +1,557 @@
+package mgen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// PySlice mirrors Python's slice object. Nil bounds are omitted bounds,
+// so s[1:] is NewSlice(1, nil, nil) and s[::-1] is NewSlice(nil, nil, -1).
+type PySlice struct {
+	Start interface{}
+	Stop  interface{}
+	Step  interface{}
+}
+
+// NewSlice creates a slice object from optional (nil) integer bounds
+func NewSlice(start, stop, step interface{}) PySlice {
+	return PySlice{Start: start, Stop: stop, Step: step}
+}
+
+// Indices resolves the slice against a sequence length using Python's
+// clamping rules, like slice.indices(length) in Python
+func (s PySlice) Indices(length int) (start, stop, step int) {
+	step = 1
+	if s.Step != nil {
+		step = sliceBound(s.Step)
+		if step == 0 {
+			Raise("ValueError", "slice step cannot be zero")
+		}
+	}
+
+	lower, upper := 0, length
+	if step < 0 {
+		lower, upper = -1, length-1
+	}
+
+	clamp := func(bound interface{}, def int) int {
+		if bound == nil {
+			return def
+		}
+		i := sliceBound(bound)
+		if i < 0 {
+			i += length
+			if i < lower {
+				i = lower
+			}
+		} else if i > upper {
+			i = upper
+		}
+		return i
+	}
+
+	if step > 0 {
+		return clamp(s.Start, lower), clamp(s.Stop, upper), step
+	}
+	return clamp(s.Start, upper), clamp(s.Stop, lower), step
+}
+
+// positions returns the sequence positions selected by the slice in iteration order
+func (s PySlice) positions(length int) []int {
+	start, stop, step := s.Indices(length)
+	result := []int{}
+	if step > 0 {
+		for i := start; i < stop; i += step {
+			result = append(result, i)
+		}
+	} else {
+		for i := start; i > stop; i += step {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// sliceBound converts a slice bound to int
+func sliceBound(bound interface{}) int {
+	v := reflect.ValueOf(bound)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint())
+	case reflect.Bool:
+		if v.Bool() {
+			return 1
+		}
+		return 0
+	}
+	Raise("TypeError", "slice indices must be integers or None or have an __index__ method")
+	return 0
+}
+
+// PyList is a Python list with reference semantics for dynamically typed code.
+// Unlike a Go slice header, every reference to a *PyList observes in-place
+// mutation such as `xs += ys` or `xs.append(x)`.
+type PyList struct {
+	Items []interface{}
+}
+
+// NewPyList creates a list holding the given items
+func NewPyList(items ...interface{}) *PyList {
+	return &PyList{Items: append([]interface{}{}, items...)}
+}
+
+// Len returns the number of items in the list
+func (l *PyList) Len() int {
+	return len(l.Items)
+}
+
+// Append adds an item to the end of the list
+func (l *PyList) Append(item interface{}) {
+	l.Items = append(l.Items, item)
+}
+
+// Extend appends every item of an iterable to the list in place
+func (l *PyList) Extend(iterable interface{}) {
+	l.Items = append(l.Items, iterValues(iterable)...)
+}
+
+// iterValues materializes the items produced by iterating a Python iterable:
+// list items, string characters, range values, or map keys (in sorted order
+// for native Go maps, which have no insertion order)
+func iterValues(x interface{}) []interface{} {
+	switch v := x.(type) {
+	case *PyList:
+		return append([]interface{}{}, v.Items...)
+	case []interface{}:
+		return append([]interface{}{}, v...)
+	case PyTuple:
+		return append([]interface{}{}, v...)
+	case string:
+		result := make([]interface{}, 0, len(v))
+		for _, r := range v {
+			result = append(result, string(r))
+		}
+		return result
+	case PyBytes, *PyByteArray:
+		// Iterating bytes yields the byte values as ints
+		ints := bytesLike(v).Ints()
+		result := make([]interface{}, len(ints))
+		for i, n := range ints {
+			result[i] = n
+		}
+		return result
+	case Range:
+		result := []interface{}{}
+		v.ForEach(func(i int) { result = append(result, i) })
+		return result
+	case *PyDict:
+		return v.Keys()
+	case setLike:
+		return v.pyItems()
+	case tupleLike:
+		return v.tupleItems()
+	case dequeLike:
+		return v.dequeItems()
+	case dictLike:
+		entries := v.pyEntries()
+		keys := make([]interface{}, len(entries))
+		for i, e := range entries {
+			keys[i] = e.Key
+		}
+		return keys
+	}
+
+	v := reflect.ValueOf(x)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		result := make([]interface{}, v.Len())
+		for i := range result {
+			result[i] = v.Index(i).Interface()
+		}
+		return result
+	case reflect.Map:
+		keys := v.MapKeys()
+		result := make([]interface{}, len(keys))
+		for i, k := range keys {
+			result[i] = k.Interface()
+		}
+		sortValues(result)
+		return result
+	}
+	Raise("TypeError", "'%s' object is not iterable", pyTypeName(x))
+	return nil
+}
+
+// ToList implements list(iterable): a new list holding the items of any
+// iterable (list items, string characters, range values, or map/set keys)
+func ToList(iterable interface{}) []interface{} {
+	return iterValues(iterable)
+}
+
+// Concat joins argument lists into one, for calls that unpack iterables
+// between other arguments: print(a, *xs, b)
+func Concat(parts ...[]interface{}) []interface{} {
+	result := []interface{}{}
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result
+}
+
+// PyTuple is a tuple display or tuple(iterable). It holds its items like a
+// []interface{} list, which generated code never mutates, and prints with
+// parentheses: (1, 2) and (1,).
+type PyTuple []interface{}
+
+// ToTuple implements tuple(iterable)
+func ToTuple(iterable interface{}) PyTuple {
+	return iterValues(iterable)
+}
+
+// ToSet implements set(iterable), raising TypeError for unhashable items;
+// members are hashed by value, so 1, 1.0 and True are one member
+func ToSet(iterable interface{}) *PySet {
+	result := NewPySet()
+	for _, item := range iterValues(iterable) {
+		checkHashable(item)
+		result.Add(item)
+	}
+	return result
+}
+
+// ToDict implements dict(x): a copy of a dict or map, or a dict built from an
+// iterable of key/value pairs. Items that are not pairs raise TypeError or
+// ValueError with Python's messages.
+func ToDict(x interface{}) *Dict[interface{}, interface{}] {
+	result := NewDict[interface{}, interface{}]()
+	for _, e := range updateEntries(x) {
+		result.Set(e.Key, e.Value)
+	}
+	return result
+}
+
+// updateEntries returns the key/value pairs dict(x) and d.update(x) read
+// from x: the entries of a dict or map, or the items of an iterable of pairs
+func updateEntries(x interface{}) []PyDictEntry {
+	switch d := x.(type) {
+	case *PyDict:
+		return d.Items()
+	case dictLike:
+		return d.pyEntries()
+	}
+	var entries []PyDictEntry
+	if v := reflect.ValueOf(x); v.Kind() == reflect.Map {
+		for _, k := range iterValues(x) {
+			entries = append(entries, PyDictEntry{Key: k, Value: v.MapIndex(reflect.ValueOf(k)).Interface()})
+		}
+		return entries
+	}
+	for i, item := range iterValues(x) {
+		if !isSequence(item) {
+			if _, ok := item.(string); !ok {
+				Raise("TypeError", "cannot convert dictionary update sequence element #%d to a sequence", i)
+			}
+		}
+		pair := iterValues(item)
+		if len(pair) != 2 {
+			Raise("ValueError", "dictionary update sequence element #%d has length %d; 2 is required", i, len(pair))
+		}
+		checkHashable(pair[0])
+		entries = append(entries, PyDictEntry{Key: pair[0], Value: pair[1]})
+	}
+	return entries
+}
+
+// checkHashable raises TypeError for values Python cannot use as dict keys or
+// set members (lists, dicts and sets), which would also panic as Go map keys
+func checkHashable(x interface{}) {
+	switch x.(type) {
+	case nil, PyBytes:
+		return
+	case *PyByteArray:
+		Raise("TypeError", "unhashable type: 'bytearray'")
+	}
+	switch reflect.ValueOf(x).Kind() {
+	case reflect.Slice, reflect.Map:
+		Raise("TypeError", "unhashable type: '%s'", pyTypeName(x))
+	}
+	switch x.(type) {
+	case *PyList:
+		Raise("TypeError", "unhashable type: 'list'")
+	case dictLike:
+		Raise("TypeError", "unhashable type: 'dict'")
+	case setLike:
+		Raise("TypeError", "unhashable type: 'set'")
+	}
+}
+
+// GetItem implements container[key] for a container whose static type is
+// unknown, such as an item of a list of tuples. Dicts look the key up, raising
+// KeyError when it is missing; strs, bytes and sequences take an int index,
+// which counts from the end when negative and raises IndexError out of range.
+func GetItem(container, key interface{}) interface{} {
+	switch c := container.(type) {
+	case *PyDict:
+		return c.Get(key)
+	case dictLike:
+		value, ok := c.pyLookup(key)
+		if !ok {
+			Raise("KeyError", "%s", reprKey(key))
+		}
+		return value
+	case setLike:
+		Raise("TypeError", "'%s' object is not subscriptable", pyTypeName(container))
+	}
+	if rv := reflect.ValueOf(container); rv.Kind() == reflect.Map {
+		return mapItem(rv, key)
+	}
+	i, ok := asInt(key)
+	if !ok {
+		Raise("TypeError", "%s indices must be integers, not %s", pyTypeName(container), pyTypeName(key))
+	}
+	switch c := container.(type) {
+	case string:
+		return StrAt(c, int(i))
+	case PyBytes:
+		return c.At(int(i))
+	}
+	items := iterValues(container)
+	return items[byteIndex(len(items), int(i), pyTypeName(container)+" index out of range")]
+}
+
+// mapItem looks key up in a native Go map, raising KeyError when it is missing
+func mapItem(m reflect.Value, key interface{}) interface{} {
+	k := reflect.ValueOf(key)
+	if !k.IsValid() || !k.Type().AssignableTo(m.Type().Key()) {
+		Raise("KeyError", "%s", pyQuoteValue(key))
+	}
+	value := m.MapIndex(k)
+	if !value.IsValid() {
+		Raise("KeyError", "%s", pyQuoteValue(key))
+	}
+	return value.Interface()
+}
+
+// DelItem implements Python's `del container[key]`.
+// Maps are modified directly; slices must be passed by pointer (&xs) so the
+// shortened slice is visible to the caller. A missing map key raises KeyError
+// and an out-of-range index raises IndexError.
+func DelItem(container interface{}, key interface{}) {
+	switch d := container.(type) {
+	case *PyDict:
+		d.Delete(key)
+		return
+	case dictLike:
+		d.pyDelete(key)
+		return
+	}
+	v := reflect.ValueOf(container)
+	switch v.Kind() {
+	case reflect.Map:
+		k := reflect.ValueOf(key)
+		if !k.IsValid() {
+			k = reflect.Zero(v.Type().Key())
+		}
+		if !k.Type().AssignableTo(v.Type().Key()) {
+			if !k.Type().ConvertibleTo(v.Type().Key()) {
+				Raise("KeyError", "%s", pyQuoteValue(key))
+			}
+			k = k.Convert(v.Type().Key())
+		}
+		if !v.MapIndex(k).IsValid() {
+			Raise("KeyError", "%s", pyQuoteValue(key))
+		}
+		v.SetMapIndex(k, reflect.Value{})
+		return
+	case reflect.Ptr:
+		elem := v.Elem()
+		if elem.Kind() == reflect.Slice {
+			index := sliceBound(key)
+			length := elem.Len()
+			if index < 0 {
+				index += length
+			}
+			if index < 0 || index >= length {
+				Raise("IndexError", "list assignment index out of range")
+			}
+			elem.Set(reflect.AppendSlice(elem.Slice(0, index), elem.Slice(index+1, length)))
+			return
+		}
+	}
+	Raise("TypeError", "'%s' object doesn't support item deletion", pyTypeName(container))
+}
+
+// DelSlice implements Python's `del xs[start:stop:step]` on a slice passed by pointer
+func DelSlice(container interface{}, s PySlice) {
+	v := reflect.ValueOf(container)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		Raise("TypeError", "'%s' object does not support slice deletion", pyTypeName(container))
+	}
+	elem := v.Elem()
+	length := elem.Len()
+
+	remove := make(map[int]bool)
+	for _, pos := range s.positions(length) {
+		remove[pos] = true
+	}
+	if len(remove) == 0 {
+		return
+	}
+
+	result := reflect.MakeSlice(elem.Type(), 0, length-len(remove))
+	for i := 0; i < length; i++ {
+		if !remove[i] {
+			result = reflect.Append(result, elem.Index(i))
+		}
+	}
+	elem.Set(result)
+}
+
+// Slicing
+//
+// Python slicing always returns a new sequence. A Go reslice xs[a:b] shares
+// the backing array of xs, so assigning into it or appending to it would
+// write through to the source. These helpers copy the selected items, so
+// mutating the result never changes the sequence it was sliced from.
+
+// SliceContains implements item in xs for a slice of the item's own type
+func SliceContains[T comparable](xs []T, item T) bool {
+	for _, x := range xs {
+		if x == item {
+			return true
+		}
+	}
+	return false
+}
+
+// SliceSlice implements xs[start:stop:step] for a typed slice, returning an
+// independent copy
+func SliceSlice[T any](xs []T, s PySlice) []T {
+	positions := s.positions(len(xs))
+	result := make([]T, len(positions))
+	for i, pos := range positions {
+		result[i] = xs[pos]
+	}
+	return result
+}
+
+// Slice implements l[start:stop:step], returning a new list
+func (l *PyList) Slice(s PySlice) *PyList {
+	return NewPyList(SliceSlice(l.Items, s)...)
+}
+
+// SliceString implements s[start:stop:step]; indices count code points
+func SliceString(str string, s PySlice) string {
+	return string(SliceSlice([]rune(str), s))
+}
+
+// SliceStringBytes implements s[start:stop:step] with indices counting bytes
+func SliceStringBytes(str string, s PySlice) string {
+	return string(SliceSlice([]byte(str), s))
+}
+
+// pyTypeName returns the Python type name for a Go value, used in error messages
+func pyTypeName(x interface{}) string {
+	if x == nil {
+		return "NoneType"
+	}
+	if _, ok := x.(*PyList); ok {
+		return "list"
+	}
+	if _, ok := x.(PyBytes); ok {
+		return "bytes"
+	}
+	if _, ok := x.(*PyByteArray); ok {
+		return "bytearray"
+	}
+	if _, ok := x.(*PyInt); ok {
+		return "int"
+	}
+	if d, ok := x.(dictLike); ok {
+		return d.kind()
+	}
+	if _, ok := x.(setLike); ok {
+		return "set"
+	}
+	if _, ok := x.(tupleLike); ok {
+		return "tuple"
+	}
+	if _, ok := x.(PyTuple); ok {
+		return "tuple"
+	}
+	v := reflect.ValueOf(x)
+	switch v.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.String:
+		return "str"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map:
+		if v.Type().Elem().Kind() == reflect.Bool {
+			return "set"
+		}
+		return "dict"
+	case reflect.Func:
+		return "function"
+	case reflect.Ptr:
+		if v.IsNil() {
+			return "NoneType"
+		}
+		return pyTypeName(v.Elem().Interface())
+	}
+	return v.Type().Name()
+}
+
+// pyQuote returns a Python-style repr of a string ('abc', "it's")
+func pyQuote(s string) string {
+	quote := "'"
+	if strings.Contains(s, "'") && !strings.Contains(s, "\"") {
+		quote = "\""
+	}
+
+	var b strings.Builder
+	b.WriteString(quote)
+	for _, r := range s {
+		switch {
+		case string(r) == quote || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString("\\n")
+		case r == '\r':
+			b.WriteString("\\r")
+		case r == '\t':
+			b.WriteString("\\t")
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, "\\x%02x", r)
+		case unicode.IsPrint(r):
+			b.WriteRune(r)
+		case r <= 0xff:
+			fmt.Fprintf(&b, "\\x%02x", r)
+		case r <= 0xffff:
+			fmt.Fprintf(&b, "\\u%04x", r)
+		default:
+			fmt.Fprintf(&b, "\\U%08x", r)
+		}
+	}
+	b.WriteString(quote)
+	return b.String()
+}
+
+// pyQuoteValue quotes strings Python-style and renders other values with ToStr
+func pyQuoteValue(x interface{}) string {
+	if s, ok := x.(string); ok {
+		return pyQuote(s)
+	}
+	return ToStr(x)
+}
@@ -0,0 +1,11 @@
+package mgen
+
+// Debug mirrors Python's __debug__. Generated asserts are guarded by it, so
+// when it is false neither the test nor the message is evaluated, matching
+// python -O. Build with -tags mgen_optimize to start with it off.
+var Debug = !optimizedBuild
+
+// SetOptimized turns assert checking off (true) or back on (false) at run time
+func SetOptimized(optimized bool) {
+	Debug = !optimized
+}
@@ -0,0 +1,5 @@
+//go:build !mgen_optimize
+
+package mgen
+
+const optimizedBuild = false
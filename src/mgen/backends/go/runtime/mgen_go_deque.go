@@ -0,0 +1,133 @@
+package mgen
+
+// Deques
+//
+// Deque is collections.deque: a ring buffer, so appending and popping at
+// either end is O(1). Removing the first element of a slice means shifting
+// every other element, which is what list.pop(0) costs in Python too; the
+// converter lowers lists used only as FIFO queues (append + pop(0), the usual
+// BFS pattern) to a Deque.
+
+// Deque is a double-ended queue of T
+type Deque[T any] struct {
+	buf  []T // ring buffer; len(buf) is the capacity
+	head int // index of the first item in buf
+	size int
+}
+
+// NewDeque creates a deque holding items (deque([a, b, c]))
+func NewDeque[T any](items ...T) *Deque[T] {
+	d := &Deque[T]{buf: make([]T, max(len(items), 8))}
+	copy(d.buf, items)
+	d.size = len(items)
+	return d
+}
+
+// Len returns the number of items (len(d))
+func (d *Deque[T]) Len() int {
+	return d.size
+}
+
+// grow doubles the capacity when the buffer is full
+func (d *Deque[T]) grow() {
+	if d.size < len(d.buf) {
+		return
+	}
+	buf := make([]T, 2*len(d.buf))
+	n := copy(buf, d.buf[d.head:])
+	copy(buf[n:], d.buf[:d.head])
+	d.buf, d.head = buf, 0
+}
+
+// Append adds x at the right end (d.append(x))
+func (d *Deque[T]) Append(x T) {
+	d.grow()
+	d.buf[(d.head+d.size)%len(d.buf)] = x
+	d.size++
+}
+
+// AppendLeft adds x at the left end (d.appendleft(x))
+func (d *Deque[T]) AppendLeft(x T) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.buf)) % len(d.buf)
+	d.buf[d.head] = x
+	d.size++
+}
+
+// Extend appends each of items at the right end (d.extend(items))
+func (d *Deque[T]) Extend(items []T) {
+	for _, x := range items {
+		d.Append(x)
+	}
+}
+
+// Pop removes and returns the rightmost item (d.pop()), raising IndexError when empty
+func (d *Deque[T]) Pop() T {
+	if d.size == 0 {
+		Raise("IndexError", "pop from an empty deque")
+	}
+	d.size--
+	i := (d.head + d.size) % len(d.buf)
+	x := d.buf[i]
+	var zero T
+	d.buf[i] = zero // let the garbage collector reclaim popped values
+	return x
+}
+
+// PopLeft removes and returns the leftmost item (d.popleft()), raising IndexError when empty
+func (d *Deque[T]) PopLeft() T {
+	if d.size == 0 {
+		Raise("IndexError", "pop from an empty deque")
+	}
+	x := d.buf[d.head]
+	var zero T
+	d.buf[d.head] = zero
+	d.head = (d.head + 1) % len(d.buf)
+	d.size--
+	return x
+}
+
+// Get returns d[i], counting from the right for negative i
+func (d *Deque[T]) Get(i int) T {
+	if i < 0 {
+		i += d.size
+	}
+	if i < 0 || i >= d.size {
+		Raise("IndexError", "deque index out of range")
+	}
+	return d.buf[(d.head+i)%len(d.buf)]
+}
+
+// Clear removes all items (d.clear())
+func (d *Deque[T]) Clear() {
+	*d = *NewDeque[T]()
+}
+
+// Items returns the items from left to right as a new slice
+func (d *Deque[T]) Items() []T {
+	items := make([]T, d.size)
+	for i := range items {
+		items[i] = d.buf[(d.head+i)%len(d.buf)]
+	}
+	return items
+}
+
+// String renders the deque like Python's repr: deque([1, 2, 3])
+func (d *Deque[T]) String() string {
+	return "deque(" + Repr(d.dequeItems()) + ")"
+}
+
+// dequeLike is a Deque of any item type, which iteration and the in
+// operator see through its boxed items
+type dequeLike interface {
+	dequeItems() []interface{}
+}
+
+// dequeItems returns the items boxed, from left to right
+func (d *Deque[T]) dequeItems() []interface{} {
+	items := make([]interface{}, d.size)
+	for i, x := range d.Items() {
+		items[i] = x
+	}
+	return items
+}
@@ -0,0 +1,409 @@
+package mgen
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Value-hashed containers
+//
+// Tuples are []interface{} at runtime, which Go cannot use as map keys.
+// PyDict and PySet key their entries by HashKey, a string encoding of the
+// key's value, so equal tuples find the same entry. HashKey follows Python's
+// equality: 1, 1.0 and True are the same key. Instances of a class defining
+// __hash__ encode only their hash, so keys holding them are kept in buckets
+// and matched with Eq, which calls the class's __eq__. Entries keep insertion
+// order, as Python dicts do; sets use the same ordering so their output is stable.
+
+// HashKey returns a string that is equal for keys Python considers equal,
+// raising TypeError for unhashable values. A []interface{} is a tuple here,
+// and hashes like the Tuple2 or Tuple3 with the same items.
+func HashKey(x interface{}) string {
+	switch v := x.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v)
+	case float32:
+		return hashFloat(float64(v))
+	case float64:
+		return hashFloat(v)
+	case string:
+		return pyQuote(v)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = HashKey(item)
+		}
+		return "(" + strings.Join(parts, ", ") + ")"
+	case PyTuple:
+		return HashKey([]interface{}(v))
+	case tupleLike:
+		return HashKey(v.tupleItems())
+	case PyBytes:
+		return v.String()
+	}
+	if _, ok := asProtocol[PyEquatable](x); ok {
+		return "#" + strconv.Itoa(classHash(x))
+	}
+	checkHashable(x)
+	return fmt.Sprintf("%T:%v", x, x)
+}
+
+// bucketed reports whether keys with this HashKey may hold class instances,
+// which can share a hash without being equal; a string key containing '#'
+// is bucketed too, which costs only an Eq per lookup
+func bucketed(hash string) bool {
+	return strings.ContainsRune(hash, '#')
+}
+
+// HashValue implements hash(x): a class's __hash__, the value of an int or
+// integral float, and otherwise a hash of x's HashKey, so values Python
+// considers equal hash alike
+func HashValue(x interface{}) int {
+	switch v := x.(type) {
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	case int:
+		return v
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) && math.Abs(v) < 1<<62 {
+			return int(v)
+		}
+	}
+	if h, ok := asProtocol[PyHashable](x); ok {
+		return h.Hash()
+	}
+	hasher := fnv.New64a()
+	hasher.Write([]byte(HashKey(x)))
+	return int(hasher.Sum64() >> 1)
+}
+
+// hashFloat encodes integral floats like the equal int so 2.0 and 2 collide
+func hashFloat(f float64) string {
+	if f == math.Trunc(f) && !math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return FloatRepr(f)
+}
+
+// reprKey renders a dict key or set member; []interface{} keys are tuples
+func reprKey(x interface{}) string {
+	tuple, ok := x.([]interface{})
+	if t, isTuple := x.(PyTuple); isTuple {
+		tuple, ok = t, true
+	}
+	if !ok {
+		return Repr(x)
+	}
+	if len(tuple) == 1 {
+		return "(" + reprKey(tuple[0]) + ",)"
+	}
+	parts := make([]string, len(tuple))
+	for i, item := range tuple {
+		parts[i] = reprKey(item)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// PyDictEntry is one key/value pair of a PyDict (an item of dict.items())
+type PyDictEntry struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// String renders the entry like the tuple dict.items() yields
+func (e PyDictEntry) String() string {
+	return Repr(e)
+}
+
+// tupleItems returns the key and value as the items of a two-element tuple
+func (e PyDictEntry) tupleItems() []interface{} {
+	return []interface{}{e.Key, e.Value}
+}
+
+// PyDict is an insertion-ordered dict whose keys may be tuples
+type PyDict struct {
+	index   map[string]int
+	buckets map[string][]int // positions of the bucketed keys (see bucketed)
+	entries []PyDictEntry
+}
+
+// NewPyDict builds a dict from entries; later duplicates overwrite earlier values
+func NewPyDict(entries ...PyDictEntry) *PyDict {
+	d := &PyDict{index: make(map[string]int)}
+	for _, e := range entries {
+		d.Set(e.Key, e.Value)
+	}
+	return d
+}
+
+// find returns the position of key's entry and whether the key is present
+func (d *PyDict) find(key interface{}) (int, bool) {
+	hash := HashKey(key)
+	if !bucketed(hash) {
+		i, ok := d.index[hash]
+		return i, ok
+	}
+	for _, i := range d.buckets[hash] {
+		if Eq(key, d.entries[i].Key) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// record indexes the entry at position i
+func (d *PyDict) record(i int) {
+	hash := HashKey(d.entries[i].Key)
+	if !bucketed(hash) {
+		d.index[hash] = i
+		return
+	}
+	if d.buckets == nil {
+		d.buckets = make(map[string][]int)
+	}
+	d.buckets[hash] = append(d.buckets[hash], i)
+}
+
+// Get returns d[key], raising KeyError when the key is missing
+func (d *PyDict) Get(key interface{}) interface{} {
+	i, ok := d.find(key)
+	if !ok {
+		Raise("KeyError", "%s", reprKey(key))
+	}
+	return d.entries[i].Value
+}
+
+// GetOr returns d.get(key, def)
+func (d *PyDict) GetOr(key interface{}, def interface{}) interface{} {
+	if i, ok := d.find(key); ok {
+		return d.entries[i].Value
+	}
+	return def
+}
+
+// Set assigns d[key] = value; an existing key keeps its position
+func (d *PyDict) Set(key interface{}, value interface{}) {
+	if i, ok := d.find(key); ok {
+		d.entries[i].Value = value
+		return
+	}
+	d.entries = append(d.entries, PyDictEntry{Key: key, Value: value})
+	d.record(len(d.entries) - 1)
+}
+
+// Contains reports whether key is in the dict (key in d)
+func (d *PyDict) Contains(key interface{}) bool {
+	_, ok := d.find(key)
+	return ok
+}
+
+// Delete removes key, raising KeyError when it is missing (del d[key])
+func (d *PyDict) Delete(key interface{}) {
+	i, ok := d.find(key)
+	if !ok {
+		Raise("KeyError", "%s", reprKey(key))
+	}
+	d.removeAt(i)
+}
+
+// removeAt deletes the entry at position i
+func (d *PyDict) removeAt(i int) {
+	if hash := HashKey(d.entries[i].Key); !bucketed(hash) {
+		delete(d.index, hash)
+	}
+	d.entries = append(d.entries[:i], d.entries[i+1:]...)
+	d.reindex(i)
+}
+
+// SetDefault returns d[key], first inserting def when the key is missing
+// (d.setdefault(key, def))
+func (d *PyDict) SetDefault(key interface{}, def interface{}) interface{} {
+	if i, ok := d.find(key); ok {
+		return d.entries[i].Value
+	}
+	d.Set(key, def)
+	return def
+}
+
+// Pop removes key and returns its value (d.pop(key)), raising KeyError
+// when the key is missing
+func (d *PyDict) Pop(key interface{}) interface{} {
+	value := d.Get(key)
+	d.Delete(key)
+	return value
+}
+
+// PopOr removes key and returns its value, or returns def when the key is
+// missing (d.pop(key, def))
+func (d *PyDict) PopOr(key interface{}, def interface{}) interface{} {
+	if !d.Contains(key) {
+		return def
+	}
+	return d.Pop(key)
+}
+
+// PopItem removes and returns the most recently inserted entry
+// (d.popitem()), raising KeyError when the dict is empty
+func (d *PyDict) PopItem() PyDictEntry {
+	if len(d.entries) == 0 {
+		Raise("KeyError", "'popitem(): dictionary is empty'")
+	}
+	last := d.entries[len(d.entries)-1]
+	d.removeAt(len(d.entries) - 1)
+	return last
+}
+
+// Update copies the entries of a dict, map or iterable of pairs into d (d.update(other))
+func (d *PyDict) Update(other interface{}) {
+	for _, e := range updateEntries(other) {
+		d.Set(e.Key, e.Value)
+	}
+}
+
+// Clear removes all entries (d.clear())
+func (d *PyDict) Clear() {
+	d.index = make(map[string]int)
+	d.buckets = nil
+	d.entries = nil
+}
+
+// Copy returns a shallow copy (d.copy())
+func (d *PyDict) Copy() *PyDict {
+	return NewPyDict(d.entries...)
+}
+
+// reindex records the positions of the entries from position i onwards
+// after entries have been removed or moved; buckets are rebuilt whole
+func (d *PyDict) reindex(i int) {
+	if len(d.buckets) > 0 {
+		d.buckets = nil
+		i = 0
+	}
+	for j := i; j < len(d.entries); j++ {
+		d.record(j)
+	}
+}
+
+// Len returns the number of entries (len(d))
+func (d *PyDict) Len() int {
+	return len(d.entries)
+}
+
+// Keys returns the keys in insertion order (list(d))
+func (d *PyDict) Keys() []interface{} {
+	keys := make([]interface{}, len(d.entries))
+	for i, e := range d.entries {
+		keys[i] = e.Key
+	}
+	return keys
+}
+
+// Values returns the values in insertion order (d.values())
+func (d *PyDict) Values() []interface{} {
+	values := make([]interface{}, len(d.entries))
+	for i, e := range d.entries {
+		values[i] = e.Value
+	}
+	return values
+}
+
+// Items returns the entries in insertion order (d.items())
+func (d *PyDict) Items() []PyDictEntry {
+	return append([]PyDictEntry{}, d.entries...)
+}
+
+// Equal reports whether the dicts hold equal keys mapped to equal values
+// (d == other); like Python's dict comparison it ignores order
+func (d *PyDict) Equal(other *PyDict) bool {
+	if d.Len() != other.Len() {
+		return false
+	}
+	for _, e := range d.entries {
+		i, ok := other.find(e.Key)
+		if !ok || !Eq(e.Value, other.entries[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the dict like Python's repr: {(1, 2): 'a'}
+func (d *PyDict) String() string {
+	return Repr(d)
+}
+
+// PySet is an insertion-ordered set whose members may be tuples
+type PySet struct {
+	dict *PyDict
+}
+
+// NewPySet builds a set from items, dropping duplicates
+func NewPySet(items ...interface{}) *PySet {
+	s := &PySet{dict: NewPyDict()}
+	for _, item := range items {
+		s.Add(item)
+	}
+	return s
+}
+
+// Add inserts item (s.add(item))
+func (s *PySet) Add(item interface{}) {
+	if !s.dict.Contains(item) {
+		s.dict.Set(item, true)
+	}
+}
+
+// Contains reports whether item is in the set (item in s)
+func (s *PySet) Contains(item interface{}) bool {
+	return s.dict.Contains(item)
+}
+
+// Remove deletes item, raising KeyError when it is missing (s.remove(item))
+func (s *PySet) Remove(item interface{}) {
+	s.dict.Delete(item)
+}
+
+// Discard deletes item if present (s.discard(item))
+func (s *PySet) Discard(item interface{}) {
+	if s.dict.Contains(item) {
+		s.dict.Delete(item)
+	}
+}
+
+// Len returns the number of members (len(s))
+func (s *PySet) Len() int {
+	return s.dict.Len()
+}
+
+// Items returns the members in insertion order (for x in s)
+func (s *PySet) Items() []interface{} {
+	return s.dict.Keys()
+}
+
+// String renders the set like Python's repr: {(1, 2), (3, 4)} or set()
+func (s *PySet) String() string {
+	return Repr(s)
+}
+
+// pyItems returns the members in insertion order (see setLike)
+func (s *PySet) pyItems() []interface{} {
+	return s.Items()
+}
+
+// pyContains reports whether item is a member (see setLike)
+func (s *PySet) pyContains(item interface{}) bool {
+	return s.Contains(item)
+}
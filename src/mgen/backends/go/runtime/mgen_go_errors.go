@@ -0,0 +1,230 @@
+package mgen
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// PyError represents a Python exception raised by the runtime.
+// Runtime helpers raise it with panic so generated code behaves like Python
+// when an operation fails (e.g. a missing dict key raises KeyError).
+// Generated exception classes embed PyError and add their own fields.
+type PyError struct {
+	Type    string
+	Message string
+	Args    []interface{}
+}
+
+// PyException is implemented by *PyError and, through embedding, by
+// pointers to generated exception classes
+type PyException interface {
+	error
+	PyErr() *PyError
+}
+
+// PyErr returns the exception record itself
+func (e *PyError) PyErr() *PyError {
+	return e
+}
+
+// Error implements the error interface using Python's "Type: message" format
+func (e *PyError) Error() string {
+	if e.Message == "" {
+		return e.Type
+	}
+	return e.Type + ": " + e.Message
+}
+
+// NewPyError creates a Python exception of the given type
+func NewPyError(excType string, format string, args ...interface{}) *PyError {
+	return &PyError{Type: excType, Message: fmt.Sprintf(format, args...)}
+}
+
+// Raise panics with a Python exception of the given type
+func Raise(excType string, format string, args ...interface{}) {
+	panic(NewPyError(excType, format, args...))
+}
+
+// ExceptionArgs builds the exception record BaseException.__init__(*args)
+// stores: str() of the exception is "" for no arguments, str(arg) for one,
+// and the repr of the args tuple for several
+func ExceptionArgs(excType string, args ...interface{}) PyError {
+	message := ""
+	switch len(args) {
+	case 0:
+	case 1:
+		message = ToStr(args[0])
+	default:
+		message = reprItems("(", args, ")", nil)
+	}
+	return PyError{Type: excType, Message: message, Args: args}
+}
+
+// NewException returns the pointer an instance of a generated exception
+// class is raised as, such as panic(NewException(NewParseError("bad", 3))),
+// so that handlers receive a pointer to it
+func NewException[T any, P interface {
+	*T
+	PyException
+}](exc T) P {
+	return P(&exc)
+}
+
+// Exception hierarchy
+//
+// PyError carries its type as a name, so class relationships live in a
+// parent table: every exception type maps to its base class, ending at
+// BaseException. An except clause naming a class matches that class and all
+// of its subclasses, so "except LookupError" catches KeyError and IndexError.
+// RegisterException adds user-defined exception classes to the table.
+var (
+	exceptionParentsMu sync.RWMutex
+	exceptionParents   = map[string]string{
+		"SystemExit":             "BaseException",
+		"KeyboardInterrupt":      "BaseException",
+		"GeneratorExit":          "BaseException",
+		"Exception":              "BaseException",
+		"ArithmeticError":        "Exception",
+		"FloatingPointError":     "ArithmeticError",
+		"OverflowError":          "ArithmeticError",
+		"ZeroDivisionError":      "ArithmeticError",
+		"AssertionError":         "Exception",
+		"AttributeError":         "Exception",
+		"BufferError":            "Exception",
+		"EOFError":               "Exception",
+		"ImportError":            "Exception",
+		"ModuleNotFoundError":    "ImportError",
+		"LookupError":            "Exception",
+		"IndexError":             "LookupError",
+		"KeyError":               "LookupError",
+		"MemoryError":            "Exception",
+		"NameError":              "Exception",
+		"UnboundLocalError":      "NameError",
+		"OSError":                "Exception",
+		"FileExistsError":        "OSError",
+		"FileNotFoundError":      "OSError",
+		"IsADirectoryError":      "OSError",
+		"NotADirectoryError":     "OSError",
+		"PermissionError":        "OSError",
+		"TimeoutError":           "OSError",
+		"ReferenceError":         "Exception",
+		"RuntimeError":           "Exception",
+		"NotImplementedError":    "RuntimeError",
+		"RecursionError":         "RuntimeError",
+		"StopIteration":          "Exception",
+		"SyntaxError":            "Exception",
+		"SystemError":            "Exception",
+		"TypeError":              "Exception",
+		"ValueError":             "Exception",
+		"UnicodeError":           "ValueError",
+		"UnicodeDecodeError":     "UnicodeError",
+		"UnicodeEncodeError":     "UnicodeError",
+		"binascii.Error":         "ValueError",
+		"argparse.ArgumentError": "Exception",
+	}
+)
+
+// RegisterException records a user-defined exception class and its base class
+func RegisterException(excType, parent string) {
+	exceptionParentsMu.Lock()
+	defer exceptionParentsMu.Unlock()
+	exceptionParents[excType] = parent
+}
+
+// IsSubclass reports whether exception type excType is base or derives from it
+func IsSubclass(excType, base string) bool {
+	exceptionParentsMu.RLock()
+	defer exceptionParentsMu.RUnlock()
+	for current := excType; current != ""; current = exceptionParents[current] {
+		if current == base {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInstance reports whether err is a Python exception of type excType or of
+// one of its subclasses: IsInstance(valueErr, "Exception") is true
+func IsInstance(err interface{}, excType string) bool {
+	exc, ok := asException(err)
+	return ok && IsSubclass(exc.PyErr().Type, excType)
+}
+
+// asException returns x as a PyException, including generated exception
+// values (whose PyErr method has a pointer receiver) by taking a copy's address
+func asException(x interface{}) (PyException, bool) {
+	if exc, ok := x.(PyException); ok {
+		return exc, true
+	}
+	if x == nil || reflect.TypeOf(x).Kind() != reflect.Struct {
+		return nil, false
+	}
+	ptr := reflect.New(reflect.TypeOf(x))
+	ptr.Elem().Set(reflect.ValueOf(x))
+	exc, ok := ptr.Interface().(PyException)
+	return exc, ok
+}
+
+// ExceptionMatches returns the exception recovered from a panic when it
+// matches any of the except clause's types, and nil otherwise (including for
+// panics that are not Python exceptions), in which case the caller re-panics.
+// Panics of the Go runtime itself match as the Python exception the same
+// operation raises (see runtimeException).
+func ExceptionMatches(recovered interface{}, excTypes ...string) *PyError {
+	recovered = runtimeException(recovered)
+	for _, excType := range excTypes {
+		if IsInstance(recovered, excType) {
+			return recovered.(PyException).PyErr()
+		}
+	}
+	return nil
+}
+
+// runtimeException converts a panic raised by Go itself, rather than by
+// Raise or a raise statement, to the Python exception of the failing operation: xs[i]
+// out of range is an IndexError, n / 0 and n % 0 on ints a ZeroDivisionError,
+// a failed type assertion a TypeError and a nil dereference (an attribute of
+// None) an AttributeError. Other panics are returned unchanged.
+func runtimeException(recovered interface{}) interface{} {
+	err, ok := recovered.(runtime.Error)
+	if !ok {
+		return recovered
+	}
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "index out of range"):
+		return NewPyError("IndexError", "list index out of range")
+	case strings.Contains(message, "integer divide by zero"):
+		return NewPyError("ZeroDivisionError", "integer division or modulo by zero")
+	case strings.Contains(message, "nil pointer dereference"):
+		return NewPyError("AttributeError", "'NoneType' object has no attribute")
+	}
+	if _, ok := err.(*runtime.TypeAssertionError); ok {
+		return NewPyError("TypeError", "%s", strings.TrimPrefix(message, "interface conversion: "))
+	}
+	return recovered
+}
+
+// TypeName returns the Python class name of x (type(x).__name__); exceptions
+// report their exception type, such as KeyError or a generated class name
+func TypeName(x interface{}) string {
+	if exc, ok := asException(x); ok {
+		return exc.PyErr().Type
+	}
+	return pyTypeName(x)
+}
+
+// ExitArgs returns the arguments a with statement passes to __exit__ for the
+// value recovered when its body ends: three nils when the body finished, and
+// otherwise the exception's type name and the exception itself. Tracebacks
+// are not recorded, so the third argument is always nil.
+func ExitArgs(recovered interface{}) (interface{}, interface{}, interface{}) {
+	if recovered == nil {
+		return nil, nil, nil
+	}
+	exc := runtimeException(recovered)
+	return TypeName(exc), exc, nil
+}
@@ -0,0 +1,829 @@
+package mgen
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Formatting protocol
+//
+// Format implements Python's format(value, spec), which f-string fields also
+// lower to. Generated classes that define __format__(self, spec) get a
+// PyFormat method and take over their own formatting; other values use the
+// built-in format spec handling, where an empty spec means str(value).
+
+// PyFormattable is implemented by types with a Python __format__ method
+type PyFormattable interface {
+	PyFormat(spec string) string
+}
+
+// Format formats value according to a Python format spec (format(value, spec))
+func Format(value interface{}, spec string) string {
+	if f, ok := asFormattable(value); ok {
+		return f.PyFormat(spec)
+	}
+	if spec == "" {
+		return ToStr(value)
+	}
+	return formatBuiltin(value, spec)
+}
+
+// asFormattable finds a PyFormat method on value, including methods declared
+// on the pointer receiver of a struct passed by value
+func asFormattable(value interface{}) (PyFormattable, bool) {
+	return asProtocol[PyFormattable](value)
+}
+
+// FloatRepr formats a float the way Python's repr()/str() does: the shortest
+// digits that round-trip, fixed notation with a trailing ".0" for exponents in
+// [-4, 16), and otherwise scientific notation with a signed two-digit minimum
+// exponent (1e+16, 1e-05, 1.5e+300)
+func FloatRepr(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "nan"
+	}
+
+	// Shortest round-trip digits in the form [-]d[.ddd]e±XX
+	sci := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, expPart, _ := strings.Cut(sci, "e")
+	exp, _ := strconv.Atoi(expPart)
+	if exp < -4 || exp >= 16 {
+		// Go already writes at least two exponent digits with an explicit sign
+		return sci
+	}
+
+	sign := ""
+	if strings.HasPrefix(mantissa, "-") {
+		sign, mantissa = "-", mantissa[1:]
+	}
+	digits := strings.Replace(mantissa, ".", "", 1)
+	point := exp + 1 // position of the decimal point within digits
+	switch {
+	case point <= 0:
+		return sign + "0." + strings.Repeat("0", -point) + digits
+	case point >= len(digits):
+		return sign + digits + strings.Repeat("0", point-len(digits)) + ".0"
+	}
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Format spec mini-language
+//
+// A spec is [[fill]align][sign][z][#][0][width][grouping][.precision][type],
+// as in Python. The value is first rendered without its sign (the body), then
+// the sign and any 0b/0o/0x prefix are put in front and the result is padded
+// to the width. With '=' alignment the padding goes between the sign and the
+// digits; when that padding is zeros and a grouping separator is set, the
+// zeros are grouped too, so format(1234, "010,") is "00,001,234".
+
+// formatSpec is a parsed format spec
+type formatSpec struct {
+	fill      string
+	align     byte // 0 when the spec has none
+	sign      byte // '+', '-', ' ', or 0 when the spec has none
+	zeroNeg   bool // z: negative zero formats as zero
+	alternate bool // #
+	zeroPad   bool // a 0 before the width
+	width     int
+	grouping  byte // ',' or '_', or 0
+	precision int  // -1 when the spec has none
+	kind      byte // presentation type, or 0 when the spec has none
+}
+
+// parseFormatSpec parses a format spec, raising ValueError for anything
+// outside the mini-language
+func parseFormatSpec(spec string) formatSpec {
+	fs := formatSpec{fill: " ", precision: -1}
+	rest := spec
+
+	isAlign := func(b byte) bool { return b == '<' || b == '>' || b == '^' || b == '=' }
+	if r, size := utf8.DecodeRuneInString(rest); size > 0 && len(rest) > size && isAlign(rest[size]) {
+		fs.fill, fs.align = string(r), rest[size]
+		rest = rest[size+1:]
+	} else if len(rest) > 0 && isAlign(rest[0]) {
+		fs.align = rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) > 0 && strings.IndexByte("+- ", rest[0]) >= 0 {
+		fs.sign, rest = rest[0], rest[1:]
+	}
+	if strings.HasPrefix(rest, "z") {
+		fs.zeroNeg, rest = true, rest[1:]
+	}
+	if strings.HasPrefix(rest, "#") {
+		fs.alternate, rest = true, rest[1:]
+	}
+	if strings.HasPrefix(rest, "0") {
+		fs.zeroPad, rest = true, rest[1:]
+	}
+
+	digits := func() (int, bool) {
+		i := 0
+		for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+			i++
+		}
+		if i == 0 {
+			return 0, false
+		}
+		n, _ := strconv.Atoi(rest[:i])
+		rest = rest[i:]
+		return n, true
+	}
+	fs.width, _ = digits()
+	if len(rest) > 0 && (rest[0] == ',' || rest[0] == '_') {
+		fs.grouping, rest = rest[0], rest[1:]
+		if len(rest) > 0 && (rest[0] == ',' || rest[0] == '_') {
+			Raise("ValueError", "Cannot specify both ',' and '_'.")
+		}
+	}
+	if strings.HasPrefix(rest, ".") {
+		rest = rest[1:]
+		precision, ok := digits()
+		if !ok {
+			Raise("ValueError", "Format specifier missing precision")
+		}
+		fs.precision = precision
+	}
+	if len(rest) == 1 {
+		fs.kind, rest = rest[0], ""
+	}
+	if rest != "" {
+		Raise("ValueError", "Invalid format specifier '%s'", spec)
+	}
+	if fs.zeroPad && fs.align == 0 {
+		// A leading zero pads with zeros after the sign, unless an explicit
+		// alignment says otherwise; strings keep their left alignment
+		fs.fill = "0"
+	}
+	return fs
+}
+
+// formatBuiltin formats strings, integers and floats with a non-empty spec
+func formatBuiltin(value interface{}, spec string) string {
+	fs := parseFormatSpec(spec)
+	if s, ok := value.(string); ok {
+		return formatString(s, fs)
+	}
+	if b, ok := value.(*PyInt); ok && b.value.IsInt64() {
+		// Only the digits beyond int64 need the math/big rendering
+		value = b.Int()
+	}
+	if _, ok := asInt(value); !ok && !isFloat(value) && !isPyInt(value) {
+		Raise("TypeError", "unsupported format string passed to %s.__format__", pyTypeName(value))
+	}
+	if fs.align == 0 && fs.zeroPad {
+		fs.align = '='
+	}
+
+	var negative bool
+	var prefix, body string
+	if isIntFormat(value, fs.kind) {
+		negative, prefix, body = formatIntBody(value, fs)
+	} else {
+		negative, body = formatFloatBody(value, fs)
+	}
+	sign := ""
+	switch {
+	case negative:
+		sign = "-"
+	case fs.sign == '+' || fs.sign == ' ':
+		sign = string(fs.sign)
+	}
+	if fs.grouping != 0 {
+		interval := 3
+		if strings.IndexByte("boxX", fs.kind) >= 0 {
+			interval = 4
+		}
+		minDigits := 0
+		if fs.align == '=' && fs.fill == "0" {
+			// Zero padding is part of the grouped digits
+			minDigits = fs.width - len(sign) - len(prefix)
+		}
+		body = groupDigits(body, string(fs.grouping), interval, minDigits)
+	}
+	return padFormatted(sign+prefix, body, fs, '>')
+}
+
+// formatString applies a spec to a string, which takes only fill, alignment,
+// width and precision
+func formatString(s string, fs formatSpec) string {
+	switch {
+	case fs.kind != 0 && fs.kind != 's':
+		Raise("ValueError", "Unknown format code '%c' for object of type 'str'", fs.kind)
+	case fs.sign != 0:
+		Raise("ValueError", "Sign not allowed in string format specifier")
+	case fs.alternate:
+		Raise("ValueError", "Alternate form (#) not allowed in string format specifier")
+	case fs.grouping != 0:
+		Raise("ValueError", "Cannot specify '%c' with 's'.", fs.grouping)
+	case fs.align == '=':
+		Raise("ValueError", "'=' alignment not allowed in string format specifier")
+	}
+	if fs.precision >= 0 && utf8.RuneCountInString(s) > fs.precision {
+		s = string([]rune(s)[:fs.precision])
+	}
+	return padFormatted("", s, fs, '<')
+}
+
+// isIntFormat reports whether value is formatted as an integer: an int (or
+// bool) with an integer presentation type or none. Floats only take the float
+// types, and ints take both; any other type raises ValueError.
+func isIntFormat(value interface{}, kind byte) bool {
+	intKinds, floatKinds := "bcdoxXn", "eEfFgGn%"
+	if isFloat(value) {
+		intKinds = ""
+	}
+	if kind != 0 && strings.IndexByte(intKinds, kind) < 0 && strings.IndexByte(floatKinds, kind) < 0 {
+		Raise("ValueError", "Unknown format code '%c' for object of type '%s'", kind, pyTypeName(value))
+	}
+	return intKinds != "" && (kind == 0 || strings.IndexByte(intKinds, kind) >= 0)
+}
+
+// formatIntBody renders the digits of an integer without its sign, returning
+// the base prefix the alternate form adds separately
+func formatIntBody(value interface{}, fs formatSpec) (negative bool, prefix, body string) {
+	i, _ := asInt(value)
+	switch {
+	case fs.precision >= 0:
+		Raise("ValueError", "Precision not allowed in integer format specifier")
+	case fs.grouping == ',' && strings.IndexByte("boxXnc", fs.kind) >= 0:
+		Raise("ValueError", "Cannot specify ',' with '%c'.", fs.kind)
+	case fs.grouping != 0 && strings.IndexByte("nc", fs.kind) >= 0:
+		Raise("ValueError", "Cannot specify '%c' with '%c'.", fs.grouping, fs.kind)
+	}
+	if fs.kind == 'c' {
+		if fs.sign != 0 {
+			Raise("ValueError", "Sign not allowed with integer format specifier 'c'")
+		}
+		if fs.alternate {
+			Raise("ValueError", "Alternate form (#) not allowed with integer format specifier 'c'")
+		}
+		if i < 0 || i > unicode.MaxRune || isPyInt(value) {
+			Raise("OverflowError", "%%c arg not in range(0x110000)")
+		}
+		return false, "", string(rune(i))
+	}
+
+	base := 10
+	switch fs.kind {
+	case 'b':
+		base = 2
+	case 'o':
+		base = 8
+	case 'x', 'X':
+		base = 16
+	}
+	if b, ok := value.(*PyInt); ok {
+		negative, body = b.value.Sign() < 0, new(big.Int).Abs(&b.value).Text(base)
+	} else {
+		magnitude := uint64(i)
+		if i < 0 {
+			negative, magnitude = true, -magnitude
+		}
+		body = strconv.FormatUint(magnitude, base)
+	}
+	if fs.kind == 'X' {
+		body = strings.ToUpper(body)
+	}
+	if fs.alternate && base != 10 {
+		prefix = "0" + string(fs.kind)
+	}
+	return negative, prefix, body
+}
+
+// formatFloatBody renders a number with a float presentation type, or a float
+// with none, without its sign
+func formatFloatBody(value interface{}, fs formatSpec) (negative bool, body string) {
+	f, _ := asFloat(value)
+	negative = math.Signbit(f) && !math.IsNaN(f)
+	f = math.Abs(f)
+	precision := fs.precision
+	if precision < 0 && fs.kind != 0 && fs.kind != 'g' && fs.kind != 'G' && fs.kind != 'n' {
+		precision = 6
+	}
+
+	switch {
+	case math.IsInf(f, 0):
+		body = "inf"
+	case math.IsNaN(f):
+		body = "nan"
+	case fs.kind == 'e' || fs.kind == 'E':
+		body = strconv.FormatFloat(f, 'e', precision, 64)
+	case fs.kind == 'f' || fs.kind == 'F':
+		body = strconv.FormatFloat(f, 'f', precision, 64)
+	case fs.kind == '%':
+		body = strconv.FormatFloat(f*100, 'f', precision, 64)
+	case fs.kind == 0 && precision < 0:
+		body = FloatRepr(f)
+	default:
+		if precision < 0 {
+			precision = 6
+		}
+		body = formatGeneral(f, precision, fs.alternate, fs.kind == 0)
+	}
+	if fs.alternate && !strings.Contains(body, ".") && body != "inf" && body != "nan" {
+		// The alternate form always has a decimal point
+		mantissa, exponent, hasExp := strings.Cut(body, "e")
+		body = mantissa + "."
+		if hasExp {
+			body += "e" + exponent
+		}
+	}
+	if fs.kind == '%' {
+		body += "%"
+	}
+	if fs.kind == 'E' || fs.kind == 'F' || fs.kind == 'G' {
+		body = strings.ToUpper(body)
+	}
+	if negative && fs.zeroNeg {
+		mantissa, _, _ := strings.Cut(body, "e")
+		negative = strings.ContainsAny(mantissa, "123456789") || body == "inf"
+	}
+	return negative, body
+}
+
+// formatGeneral implements the g presentation type: precision significant
+// digits in fixed or scientific notation, whichever suits the exponent, with
+// trailing zeros removed unless alternate is set. Without a presentation type
+// (dot0) fixed results keep at least one decimal and the switch to scientific
+// notation happens one digit earlier, so format(123.0, ".3") is "1.23e+02".
+func formatGeneral(f float64, precision int, alternate, dot0 bool) string {
+	if precision == 0 {
+		precision = 1
+	}
+	sci := strconv.FormatFloat(f, 'e', precision-1, 64)
+	_, expPart, _ := strings.Cut(sci, "e")
+	exp, _ := strconv.Atoi(expPart)
+	threshold := precision
+	if dot0 {
+		threshold--
+	}
+
+	body := sci
+	if exp >= -4 && exp < threshold {
+		body = strconv.FormatFloat(f, 'f', precision-1-exp, 64)
+	}
+	if alternate {
+		return body
+	}
+	mantissa, exponent, hasExp := strings.Cut(body, "e")
+	if strings.Contains(mantissa, ".") {
+		mantissa = strings.TrimRight(strings.TrimRight(mantissa, "0"), ".")
+	}
+	if hasExp {
+		return mantissa + "e" + exponent
+	}
+	if dot0 && !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	return mantissa
+}
+
+// groupDigits inserts sep every interval digits into the leading run of
+// digits in body, first padding that run with zeros until the grouped result
+// is at least minDigits wide
+func groupDigits(body, sep string, interval, minDigits int) string {
+	end := 0
+	for end < len(body) && strings.IndexByte("0123456789abcdefABCDEF", body[end]) >= 0 && !isExpMarker(body, end) {
+		end++
+	}
+	digits, tail := body[:end], body[end:]
+	minDigits -= utf8.RuneCountInString(tail)
+	group := func(d string) string {
+		var parts []string
+		for len(d) > interval {
+			parts = append([]string{d[len(d)-interval:]}, parts...)
+			d = d[:len(d)-interval]
+		}
+		return strings.Join(append([]string{d}, parts...), sep)
+	}
+	grouped := group(digits)
+	for end > 0 && len(grouped) < minDigits {
+		digits = "0" + digits
+		grouped = group(digits)
+	}
+	return grouped + tail
+}
+
+// isExpMarker reports whether body[i] is the e of a float's exponent rather
+// than a hex digit
+func isExpMarker(body string, i int) bool {
+	return (body[i] == 'e' || body[i] == 'E') && i+1 < len(body) && (body[i+1] == '+' || body[i+1] == '-')
+}
+
+// padFormatted pads head+body to the spec's width; '=' alignment puts the
+// padding between them
+func padFormatted(head, body string, fs formatSpec, defaultAlign byte) string {
+	align := fs.align
+	if align == 0 {
+		align = defaultAlign
+	}
+	margin := fs.width - utf8.RuneCountInString(head+body)
+	if margin <= 0 {
+		return head + body
+	}
+	switch align {
+	case '<':
+		return head + body + strings.Repeat(fs.fill, margin)
+	case '^':
+		// format() puts the extra fill on the right, unlike str.center
+		return strings.Repeat(fs.fill, margin/2) + head + body + strings.Repeat(fs.fill, margin-margin/2)
+	case '=':
+		return head + strings.Repeat(fs.fill, margin) + body
+	}
+	return strings.Repeat(fs.fill, margin) + head + body
+}
+
+// printf-style formatting
+//
+// PercentFormat implements str % args. Like CPython, it picks the argument
+// source from the type of args alone: a tuple ([]interface{}) supplies the
+// positional arguments, any other value is a single argument (so "%s" % (1,)
+// is "1" but "%s" % ((1,),) is "(1,)"), and a mapping additionally serves
+// %(key) specs. A mapping is still a single positional value, so "%s" % d
+// prints the dict while "%s %s" % d raises TypeError.
+
+// percentArgs tracks the arguments consumed by a format string
+type percentArgs struct {
+	values  []interface{}
+	next    int
+	mapping interface{}
+}
+
+// pop returns the next positional argument
+func (a *percentArgs) pop() interface{} {
+	if a.next >= len(a.values) {
+		Raise("TypeError", "not enough arguments for format string")
+	}
+	a.next++
+	return a.values[a.next-1]
+}
+
+// lookup returns mapping[key] for a %(key) spec
+func (a *percentArgs) lookup(key string) interface{} {
+	switch m := a.mapping.(type) {
+	case nil:
+		Raise("TypeError", "format requires a mapping")
+	case *PyDict:
+		return m.Get(key)
+	case dictLike:
+		if value, ok := m.pyLookup(key); ok {
+			return value
+		}
+		Raise("KeyError", "%s", pyQuote(key))
+	}
+	rv := reflect.ValueOf(a.mapping)
+	if rv.Type().Key().Kind() == reflect.String {
+		if v := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())); v.IsValid() {
+			return v.Interface()
+		}
+	}
+	Raise("KeyError", "%s", pyQuote(key))
+	return nil
+}
+
+// isMapping reports whether x is a dict (sets are map[T]bool and are not)
+func isMapping(x interface{}) bool {
+	switch x.(type) {
+	case *PyDict, dictLike:
+		return true
+	}
+	rv := reflect.ValueOf(x)
+	return rv.Kind() == reflect.Map && rv.Type().Elem().Kind() != reflect.Bool
+}
+
+// PercentFormat implements Python's printf-style str % args
+func PercentFormat(format string, args interface{}) string {
+	state := &percentArgs{}
+	if tuple, ok := args.(PyTuple); ok {
+		state.values = tuple
+	} else if tuple, ok := args.([]interface{}); ok {
+		state.values = tuple
+	} else {
+		state.values = []interface{}{args}
+		if isMapping(args) {
+			state.mapping = args
+		}
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			out.WriteByte(format[i])
+			continue
+		}
+		i++
+		if i < len(format) && format[i] == '(' {
+			end := strings.IndexByte(format[i:], ')')
+			if end < 0 {
+				Raise("ValueError", "incomplete format key")
+			}
+			key := format[i+1 : i+end]
+			i += end + 1
+			state.values, state.next = []interface{}{state.lookup(key)}, 0
+		}
+		flags := ""
+		for i < len(format) && strings.IndexByte("-+ #0", format[i]) >= 0 {
+			flags += string(format[i])
+			i++
+		}
+		width := percentNumber(format, &i, state)
+		precision := ""
+		if i < len(format) && format[i] == '.' {
+			i++
+			precision = "." + percentNumber(format, &i, state)
+			if precision == "." {
+				precision = ".0"
+			}
+		}
+		for i < len(format) && strings.IndexByte("hlL", format[i]) >= 0 {
+			i++
+		}
+		if i >= len(format) {
+			Raise("ValueError", "incomplete format")
+		}
+		if format[i] == '%' {
+			out.WriteByte('%')
+			continue
+		}
+		out.WriteString(percentConvert(format[i], flags, width, precision, state.pop(), i))
+	}
+	if state.mapping == nil && state.next < len(state.values) {
+		Raise("TypeError", "not all arguments converted during string formatting")
+	}
+	return out.String()
+}
+
+// percentNumber reads a width or precision, taking it from the arguments for *
+func percentNumber(format string, i *int, state *percentArgs) string {
+	if *i < len(format) && format[*i] == '*' {
+		*i++
+		n, ok := state.pop().(int)
+		if !ok {
+			Raise("TypeError", "* wants int")
+		}
+		return strconv.Itoa(n)
+	}
+	start := *i
+	for *i < len(format) && format[*i] >= '0' && format[*i] <= '9' {
+		*i++
+	}
+	return format[start:*i]
+}
+
+// percentConvert renders one argument for the conversion character at index
+func percentConvert(conv byte, flags, width, precision string, arg interface{}, index int) string {
+	// Strings are padded with spaces only
+	text := func(s string) string {
+		return fmt.Sprintf("%"+strings.ReplaceAll(flags, "0", "")+width+precision+"s", s)
+	}
+	switch conv {
+	case 's':
+		return text(ToStr(arg))
+	case 'r', 'a':
+		return text(Repr(arg))
+	case 'c':
+		if s, ok := arg.(string); ok && utf8.RuneCountInString(s) == 1 {
+			return text(s)
+		}
+		if n, ok := arg.(int); ok {
+			return text(string(rune(n)))
+		}
+		Raise("TypeError", "%%c requires int or char")
+	case 'd', 'i', 'u':
+		if f, ok := arg.(float64); ok {
+			arg = int64(f)
+		}
+		n, ok := asInt(arg)
+		if !ok {
+			Raise("TypeError", "%%%c format: a real number is required, not %s", conv, pyTypeName(arg))
+		}
+		return fmt.Sprintf("%"+flags+width+precision+"d", n)
+	case 'x', 'X', 'o':
+		n, ok := asInt(arg)
+		if !ok {
+			Raise("TypeError", "%%%c format: an integer is required, not %s", conv, pyTypeName(arg))
+		}
+		if conv == 'o' && strings.Contains(flags, "#") {
+			// Python's alternate octal form is 0o10, which Go spells %O
+			return fmt.Sprintf("%"+strings.ReplaceAll(flags, "#", "")+width+"O", n)
+		}
+		return fmt.Sprintf("%"+flags+width+string(conv), n)
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		f, ok := asFloat(arg)
+		if !ok {
+			Raise("TypeError", "must be real number, not %s", pyTypeName(arg))
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return text(FloatRepr(f))
+		}
+		if precision == "" {
+			precision = ".6"
+		}
+		return fmt.Sprintf("%"+flags+width+precision+string(conv), f)
+	default:
+		Raise("ValueError", "unsupported format character '%c' (0x%x) at index %d", conv, conv, index)
+	}
+	return ""
+}
+
+// str.format
+//
+// StrFormat implements str.format(*args, **kwargs) and str.format_map(mapping).
+// Each {field!conv:spec} is resolved to a value (the next positional argument
+// for an empty field, args[n] for a number, mapping[name] for a name, then any
+// .attr and [key] lookups), converted by !r, !s or !a, and rendered by
+// Format(value, spec), so it follows the same rules as f-string fields. A
+// spec may itself contain fields: "{:{width}}".
+
+// StrFormat formats format with the positional args and named values from
+// kwargs, a mapping or nil ("{} {name}".format(a, name=b))
+func StrFormat(format string, kwargs interface{}, args ...interface{}) string {
+	state := &strFormatArgs{args: args, kwargs: &percentArgs{mapping: kwargs}}
+	return state.render(format, 2)
+}
+
+// strFormatArgs tracks the argument numbering of a str.format call
+type strFormatArgs struct {
+	args   []interface{}
+	kwargs *percentArgs
+	next   int  // the next automatically numbered argument
+	manual bool // a field gave an explicit number
+	auto   bool // a field was numbered automatically
+}
+
+// render expands the fields of format; depth bounds the nesting of fields
+// inside specs, which Python allows one level deep
+func (a *strFormatArgs) render(format string, depth int) string {
+	if depth == 0 {
+		Raise("ValueError", "Max string recursion exceeded")
+	}
+	var out strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c == '}' {
+			if i+1 < len(format) && format[i+1] == '}' {
+				out.WriteByte('}')
+				i++
+				continue
+			}
+			Raise("ValueError", "Single '}' encountered in format string")
+		}
+		if c != '{' {
+			out.WriteByte(c)
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == '{' {
+			out.WriteByte('{')
+			i++
+			continue
+		}
+		// Find the matching close brace, allowing nested fields in the spec
+		end, nesting := -1, 0
+		for j := i + 1; j < len(format) && end < 0; j++ {
+			switch format[j] {
+			case '{':
+				nesting++
+			case '}':
+				if nesting == 0 {
+					end = j
+				}
+				nesting--
+			}
+		}
+		if end < 0 {
+			if i+1 == len(format) {
+				Raise("ValueError", "Single '{' encountered in format string")
+			}
+			Raise("ValueError", "expected '}' before end of string")
+		}
+		out.WriteString(a.field(format[i+1:end], depth))
+		i = end
+	}
+	return out.String()
+}
+
+// field renders one replacement field: name!conv:spec
+func (a *strFormatArgs) field(field string, depth int) string {
+	name, spec := field, ""
+	if colon := strings.IndexByte(field, ':'); colon >= 0 {
+		name, spec = field[:colon], a.render(field[colon+1:], depth-1)
+	}
+	conv := byte(0)
+	if bang := strings.IndexByte(name, '!'); bang >= 0 {
+		if len(name) != bang+2 {
+			Raise("ValueError", "expected ':' after conversion specifier")
+		}
+		name, conv = name[:bang], name[bang+1]
+	}
+	value := a.value(name)
+	switch conv {
+	case 0:
+	case 'r':
+		value = Repr(value)
+	case 's':
+		value = ToStr(value)
+	case 'a':
+		value = Ascii(value)
+	default:
+		Raise("ValueError", "Unknown conversion specifier %c", conv)
+	}
+	return Format(value, spec)
+}
+
+// value resolves a field name such as "", "0", "name", "0.x" or "items[2]"
+func (a *strFormatArgs) value(name string) interface{} {
+	first := len(name)
+	if i := strings.IndexAny(name, ".["); i >= 0 {
+		first = i
+	}
+	key, rest := name[:first], name[first:]
+	var value interface{}
+	if key == "" {
+		if a.manual {
+			Raise("ValueError", "cannot switch from manual field specification to automatic field numbering")
+		}
+		a.auto = true
+		value = a.positional(a.next)
+		a.next++
+	} else if n, err := strconv.Atoi(key); err == nil {
+		if a.auto {
+			Raise("ValueError", "cannot switch from automatic field numbering to manual field specification")
+		}
+		a.manual = true
+		value = a.positional(n)
+	} else {
+		value = a.kwargs.lookup(key)
+	}
+	for rest != "" {
+		if rest[0] == '.' {
+			end := strings.IndexAny(rest[1:], ".[") + 1
+			if end == 0 {
+				end = len(rest)
+			}
+			value, rest = formatAttr(value, rest[1:end]), rest[end:]
+			continue
+		}
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			Raise("ValueError", "Missing ']' in format string")
+		}
+		value, rest = formatItem(value, rest[1:end]), rest[end+1:]
+		if rest != "" && rest[0] != '.' && rest[0] != '[' {
+			Raise("ValueError", "Only '.' or '[' may follow ']' in format field specifier")
+		}
+	}
+	return value
+}
+
+// positional returns args[n]
+func (a *strFormatArgs) positional(n int) interface{} {
+	if n >= len(a.args) {
+		Raise("IndexError", "Replacement index %d out of range for positional args tuple", n)
+	}
+	return a.args[n]
+}
+
+// formatAttr implements the .attr lookup of a field. Generated classes are
+// often held by value, which GetAttr reads through an addressable copy.
+func formatAttr(value interface{}, name string) interface{} {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Struct {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		value = ptr.Interface()
+	}
+	return GetAttr(value, name)
+}
+
+// formatItem implements the [key] lookup of a field: an integer key indexes
+// a sequence, any other key is a string key of a mapping
+func formatItem(value interface{}, key string) interface{} {
+	n, err := strconv.Atoi(key)
+	if err != nil || isMapping(value) {
+		if err == nil {
+			if d, ok := value.(dictLike); ok {
+				if item, found := d.pyLookup(n); found {
+					return item
+				}
+				Raise("KeyError", "%d", n)
+			}
+		}
+		return (&percentArgs{mapping: value}).lookup(key)
+	}
+	items := iterValues(value)
+	if n >= len(items) {
+		Raise("IndexError", "%s index out of range", pyTypeName(value))
+	}
+	return items[n]
+}
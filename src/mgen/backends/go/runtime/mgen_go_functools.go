@@ -0,0 +1,184 @@
+package mgen
+
+import (
+	"container/list"
+	"strconv"
+)
+
+// Folds
+//
+// Reduce implements functools.reduce over dynamically typed values. Fold,
+// FoldRight and ReduceSlice are its typed counterparts for when the element
+// and accumulator types are known, so numeric accumulation needs no
+// interface{} boxing.
+
+// Reduce implements functools.reduce(fn, iterable[, initial]), raising
+// TypeError for an empty iterable without an initial value
+func Reduce(fn func(acc, item interface{}) interface{}, iterable interface{}, initial ...interface{}) interface{} {
+	items := iterValues(iterable)
+	var acc interface{}
+	switch {
+	case len(initial) > 0:
+		acc = initial[0]
+	case len(items) == 0:
+		Raise("TypeError", "reduce() of empty iterable with no initial value")
+	default:
+		acc, items = items[0], items[1:]
+	}
+	for _, item := range items {
+		acc = fn(acc, item)
+	}
+	return acc
+}
+
+// Fold combines xs from left to right: fn(fn(fn(init, xs[0]), xs[1]), ...)
+func Fold[T, A any](xs []T, init A, fn func(A, T) A) A {
+	acc := init
+	for _, x := range xs {
+		acc = fn(acc, x)
+	}
+	return acc
+}
+
+// ReduceSlice implements functools.reduce(fn, xs) over a typed slice, raising
+// TypeError for an empty slice (reduce with an initial value is Fold)
+func ReduceSlice[T any](fn func(T, T) T, xs []T) T {
+	if len(xs) == 0 {
+		Raise("TypeError", "reduce() of empty iterable with no initial value")
+	}
+	return Fold(xs[1:], xs[0], fn)
+}
+
+// FoldRight combines xs from right to left: fn(xs[0], fn(xs[1], ... fn(xs[n-1], init)))
+func FoldRight[T, A any](xs []T, init A, fn func(T, A) A) A {
+	acc := init
+	for i := len(xs) - 1; i >= 0; i-- {
+		acc = fn(xs[i], acc)
+	}
+	return acc
+}
+
+// Map and filter
+//
+// map() and filter() over a typed slice apply a func literal (usually a
+// transpiled lambda) eagerly, so list(map(f, xs)) and iterating the result
+// both see a plain slice.
+
+// MapSlice returns fn applied to every element of xs (list(map(fn, xs)))
+func MapSlice[T, R any](fn func(T) R, xs []T) []R {
+	result := make([]R, len(xs))
+	for i, x := range xs {
+		result[i] = fn(x)
+	}
+	return result
+}
+
+// FilterSlice returns the elements of xs for which fn is true (list(filter(fn, xs)))
+func FilterSlice[T any](fn func(T) bool, xs []T) []T {
+	result := []T{}
+	for _, x := range xs {
+		if fn(x) {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// Memoization
+//
+// A function decorated with @functools.lru_cache or @functools.cache gets an
+// LRUCache declared next to it, and its body runs through Memoize, which
+// returns the stored result for arguments seen before. Arguments are keyed by
+// HashKey, so like Python f(1) and f(1.0) share an entry unless the cache is
+// typed, and an unhashable argument raises TypeError.
+
+// LRUCache holds the results of a memoized function, evicting the least
+// recently used once it holds maxsize of them; a maxsize of -1 (None) never
+// evicts and 0 stores nothing
+type LRUCache struct {
+	maxsize int
+	typed   bool
+	entries map[string]*list.Element
+	order   *list.List // of *cacheEntry, most recently used first
+	hits    int
+	misses  int
+}
+
+// cacheEntry is one stored result of an LRUCache
+type cacheEntry struct {
+	key   string
+	value interface{}
+}
+
+// NewLRUCache returns an empty cache (lru_cache(maxsize, typed))
+func NewLRUCache(maxsize int, typed bool) *LRUCache {
+	return &LRUCache{maxsize: maxsize, typed: typed, entries: map[string]*list.Element{}, order: list.New()}
+}
+
+// Key returns the cache key of a call's arguments; a typed cache tells 1 and 1.0 apart
+func (c *LRUCache) Key(args ...interface{}) string {
+	key := HashKey(args)
+	if c.typed {
+		for _, arg := range args {
+			key += " " + pyTypeName(arg)
+		}
+	}
+	return key
+}
+
+// Memoize returns the result c holds for key, or stores and returns compute()
+func Memoize[V any](c *LRUCache, key string, compute func() V) V {
+	if element, ok := c.entries[key]; ok {
+		c.hits++
+		c.order.MoveToFront(element)
+		return element.Value.(*cacheEntry).value.(V)
+	}
+	c.misses++
+	value := compute()
+	if c.maxsize == 0 {
+		return value
+	}
+	if element, ok := c.entries[key]; ok {
+		// A recursive call with the same arguments stored it while compute ran
+		element.Value.(*cacheEntry).value = value
+		c.order.MoveToFront(element)
+		return value
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, value: value})
+	if c.maxsize > 0 && c.order.Len() > c.maxsize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+	return value
+}
+
+// CacheInfo is what a memoized function's cache_info() reports
+type CacheInfo struct {
+	Hits     int
+	Misses   int
+	Maxsize  interface{} // nil for an unbounded cache
+	Currsize int
+}
+
+// String renders the info like Python: CacheInfo(hits=3, misses=5, maxsize=128, currsize=5)
+func (info CacheInfo) String() string {
+	return "CacheInfo(hits=" + strconv.Itoa(info.Hits) + ", misses=" + strconv.Itoa(info.Misses) +
+		", maxsize=" + Repr(info.Maxsize) + ", currsize=" + strconv.Itoa(info.Currsize) + ")"
+}
+
+// Info returns the cache's statistics (f.cache_info())
+func (c *LRUCache) Info() CacheInfo {
+	var maxsize interface{}
+	if c.maxsize >= 0 {
+		maxsize = c.maxsize
+	}
+	return CacheInfo{Hits: c.hits, Misses: c.misses, Maxsize: maxsize, Currsize: c.order.Len()}
+}
+
+// Clear empties the cache and resets its statistics (f.cache_clear())
+func (c *LRUCache) Clear() {
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+	c.hits, c.misses = 0, 0
+}
@@ -0,0 +1,175 @@
+package mgen
+
+// Generators
+//
+// A generator function compiles to a function returning an Iterator built by
+// NewGenerator: the body runs on its own goroutine, and each yield hands one
+// value to Next and then waits until the next value is asked for, so the
+// body runs lazily and infinite generators work. Generator expressions that
+// are not consumed at once lower the same way. An exception raised in the
+// body is re-raised by the Next call that resumed it. A for loop that leaves
+// a generator before it is exhausted closes it (see CloseIterator), which
+// raises GeneratorExit at the paused yield so the goroutine unwinds and ends;
+// a generator abandoned any other way keeps its goroutine parked until the
+// program exits.
+//
+// With the Go backend's generators preference set to "eager", generators
+// are built by NewEagerGenerator instead: the first Next runs the whole body
+// on the calling goroutine and buffers what it yields. No goroutine is
+// started, so nothing can leak, but infinite generators never return.
+
+// Generator is the Iterator of a generator function or expression
+type Generator[T any] struct {
+	body   func(yield func(T))
+	steps  chan generatorStep[T]
+	resume chan struct{}
+	done   bool
+}
+
+// generatorStep is what the body's goroutine reports to Next: a yielded
+// value, the end of the body, or the exception it raised
+type generatorStep[T any] struct {
+	value   T
+	done    bool
+	failure interface{}
+}
+
+// NewGenerator returns a generator running body, which calls yield for each value
+func NewGenerator[T any](body func(yield func(T))) Iterator[T] {
+	return &Generator[T]{body: body}
+}
+
+// Next runs the body up to its next yield and returns the yielded value;
+// it reports false once the body has finished
+func (g *Generator[T]) Next() (T, bool) {
+	var zero T
+	if g.done {
+		return zero, false
+	}
+	if g.steps == nil {
+		g.steps = make(chan generatorStep[T])
+		g.resume = make(chan struct{})
+		go g.run()
+	} else {
+		g.resume <- struct{}{}
+	}
+	step := <-g.steps
+	if step.failure != nil {
+		g.done = true
+		panic(step.failure)
+	}
+	if step.done {
+		g.done = true
+		return zero, false
+	}
+	return step.value, true
+}
+
+// Close ends a generator that has not finished (generator.close()): the
+// paused yield raises GeneratorExit, and Close waits for the body to unwind
+// so its goroutine exits. An exception other than GeneratorExit raised while
+// unwinding is re-raised, and a body that yields again raises RuntimeError.
+func (g *Generator[T]) Close() {
+	if g.done {
+		return
+	}
+	g.done = true
+	if g.steps == nil {
+		return
+	}
+	close(g.resume)
+	step := <-g.steps
+	if step.failure != nil && !IsInstance(step.failure, "GeneratorExit") {
+		panic(step.failure)
+	}
+	if step.failure == nil && !step.done {
+		Raise("RuntimeError", "generator ignored GeneratorExit")
+	}
+}
+
+// run executes the body on the generator's goroutine
+func (g *Generator[T]) run() {
+	defer func() {
+		if r := recover(); r != nil {
+			g.steps <- generatorStep[T]{failure: r}
+			return
+		}
+		g.steps <- generatorStep[T]{done: true}
+	}()
+	g.body(func(value T) {
+		g.steps <- generatorStep[T]{value: value}
+		if _, ok := <-g.resume; !ok {
+			Raise("GeneratorExit", "")
+		}
+	})
+}
+
+// NewEagerGenerator returns a generator that runs all of body on its first
+// Next, buffering the values it yields; an exception the body raises is
+// re-raised once the values yielded before it are used up
+func NewEagerGenerator[T any](body func(yield func(T))) Iterator[T] {
+	return &eagerGenerator[T]{body: body}
+}
+
+// eagerGenerator is the Iterator of a generator when generators are eager
+type eagerGenerator[T any] struct {
+	body    func(yield func(T))
+	values  []T
+	next    int
+	failure interface{}
+	started bool
+}
+
+// Next returns the next buffered value, running the body first if it has not run
+func (g *eagerGenerator[T]) Next() (T, bool) {
+	if !g.started {
+		g.started = true
+		g.run()
+	}
+	if g.next < len(g.values) {
+		g.next++
+		return g.values[g.next-1], true
+	}
+	if failure := g.failure; failure != nil {
+		g.failure = nil
+		panic(failure)
+	}
+	var zero T
+	return zero, false
+}
+
+// run executes the whole body, recording the exception it raises, if any
+func (g *eagerGenerator[T]) run() {
+	defer func() {
+		g.failure = recover()
+		g.body = nil
+	}()
+	g.body(func(value T) {
+		g.values = append(g.values, value)
+	})
+}
+
+// CloseIterator closes it if it is a generator, so a for loop that stops
+// early ends the goroutine of the generator it was reading
+func CloseIterator[T any](it Iterator[T]) {
+	if g, ok := it.(*Generator[T]); ok {
+		g.Close()
+	}
+}
+
+// Next returns the next value of it, raising StopIteration when it is exhausted (next(it))
+func Next[T any](it Iterator[T]) T {
+	value, ok := it.Next()
+	if !ok {
+		Raise("StopIteration", "")
+	}
+	return value
+}
+
+// NextOr returns the next value of it, or fallback when it is exhausted (next(it, fallback))
+func NextOr[T any](it Iterator[T], fallback T) T {
+	if value, ok := it.Next(); ok {
+		return value
+	}
+	return fallback
+}
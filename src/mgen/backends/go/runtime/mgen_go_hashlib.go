@@ -0,0 +1,102 @@
+package mgen
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+)
+
+// Message digests
+//
+// Hash is a hashlib hash object backed by Go's crypto packages. As in
+// Python 3 the data must be bytes: passing a str raises TypeError, since the
+// generated code has to encode it first (s.encode() in the source).
+
+// hashConstructors maps hashlib algorithm names to their Go implementations
+var hashConstructors = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// Hash is a hashlib hash object (hashlib.sha256(), ...)
+type Hash struct {
+	name string
+	h    hash.Hash
+}
+
+// NewHash implements hashlib.new(name, data), raising ValueError for an
+// unsupported algorithm
+func NewHash(name string, data ...interface{}) *Hash {
+	constructor, ok := hashConstructors[name]
+	if !ok {
+		Raise("ValueError", "unsupported hash type %s", name)
+	}
+	obj := &Hash{name: name, h: constructor()}
+	for _, d := range data {
+		obj.Update(d)
+	}
+	return obj
+}
+
+// NewMD5 implements hashlib.md5(data)
+func NewMD5(data ...interface{}) *Hash {
+	return NewHash("md5", data...)
+}
+
+// NewSHA1 implements hashlib.sha1(data)
+func NewSHA1(data ...interface{}) *Hash {
+	return NewHash("sha1", data...)
+}
+
+// NewSHA256 implements hashlib.sha256(data)
+func NewSHA256(data ...interface{}) *Hash {
+	return NewHash("sha256", data...)
+}
+
+// NewSHA512 implements hashlib.sha512(data)
+func NewSHA512(data ...interface{}) *Hash {
+	return NewHash("sha512", data...)
+}
+
+// Update feeds data into the hash (h.update(data))
+func (obj *Hash) Update(data interface{}) {
+	switch data.(type) {
+	case string:
+		Raise("TypeError", "Strings must be encoded before hashing")
+	case PyBytes, []byte:
+		obj.h.Write(bytesLike(data))
+	default:
+		Raise("TypeError", "object supporting the buffer API required")
+	}
+}
+
+// Digest returns the digest of the data so far (h.digest()); the hash can
+// keep being updated afterwards
+func (obj *Hash) Digest() PyBytes {
+	return PyBytes(obj.h.Sum(nil))
+}
+
+// HexDigest returns the digest as lowercase hex digits (h.hexdigest())
+func (obj *Hash) HexDigest() string {
+	return hex.EncodeToString(obj.h.Sum(nil))
+}
+
+// Name returns the algorithm name (h.name)
+func (obj *Hash) Name() string {
+	return obj.name
+}
+
+// DigestSize returns the digest length in bytes (h.digest_size)
+func (obj *Hash) DigestSize() int {
+	return obj.h.Size()
+}
+
+// BlockSize returns the internal block size in bytes (h.block_size)
+func (obj *Hash) BlockSize() int {
+	return obj.h.BlockSize()
+}
@@ -0,0 +1,363 @@
+package mgen
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Standard output
+//
+// Print and PrintOpts write to a package-level stdout target instead of
+// os.Stdout directly, so contextlib.redirect_stdout and tests can capture
+// output. The target is guarded by a mutex that is also held while printing,
+// so goroutines may print and swap the target concurrently.
+
+var (
+	stdoutMu sync.RWMutex
+	stdout   io.Writer = os.Stdout
+)
+
+// GetStdout returns the writer print() currently writes to (sys.stdout)
+func GetStdout() io.Writer {
+	stdoutMu.RLock()
+	defer stdoutMu.RUnlock()
+	return stdout
+}
+
+// SetStdout replaces the writer print() writes to and returns the previous one
+func SetStdout(w io.Writer) io.Writer {
+	stdoutMu.Lock()
+	defer stdoutMu.Unlock()
+	previous := stdout
+	stdout = w
+	return previous
+}
+
+// RedirectStdout sends print() output to w until the returned restore function
+// is called (contextlib.redirect_stdout; generated code defers the restore)
+func RedirectStdout(w io.Writer) (restore func()) {
+	previous := SetStdout(w)
+	return func() { SetStdout(previous) }
+}
+
+// GetStderr returns the writer for sys.stderr
+func GetStderr() io.Writer {
+	return os.Stderr
+}
+
+// PrintOptions holds print()'s keyword arguments. A nil File means the
+// current stdout. Print writes straight through to files and the standard
+// streams, so Flush only matters for a buffered writer such as a bufio.Writer.
+type PrintOptions struct {
+	Sep   string
+	End   string
+	File  io.Writer
+	Flush bool
+}
+
+// flusher is implemented by buffered writers (bufio.Writer)
+type flusher interface {
+	Flush() error
+}
+
+// PrintOpts implements print(*args, sep=..., end=..., file=..., flush=...)
+func PrintOpts(opts PrintOptions, args ...interface{}) {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		strs[i] = ToStr(arg)
+	}
+	line := strings.Join(strs, opts.Sep) + opts.End
+
+	var err error
+	if opts.File != nil {
+		err = writeLine(opts.File, line, opts.Flush)
+	} else {
+		// Holding the lock for the write keeps concurrent prints from interleaving
+		stdoutMu.Lock()
+		err = writeLine(stdout, line, opts.Flush)
+		stdoutMu.Unlock()
+	}
+	if err != nil {
+		Raise("OSError", "%s", err.Error())
+	}
+}
+
+// writeLine writes a printed line to w, flushing w afterwards when asked to and it is buffered
+func writeLine(w io.Writer, line string, flush bool) error {
+	_, err := io.WriteString(w, line)
+	if f, ok := w.(flusher); ok && flush && err == nil {
+		err = f.Flush()
+	}
+	return err
+}
+
+// Standard input
+//
+// input() and the typed line readers share one buffered reader over stdin,
+// so mixing them never drops buffered input. ReadInts and ReadFloats parse
+// the words of a line straight into a typed slice, which is what
+// list(map(int, input().split())) lowers to. SetStdin swaps the source, as
+// SetStdout does for output, so tests can feed input.
+
+var (
+	stdinMu sync.Mutex
+	stdin   = bufio.NewReader(os.Stdin)
+)
+
+// SetStdin makes input() and the Read* helpers read from r
+func SetStdin(r io.Reader) {
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+	stdin = bufio.NewReader(r)
+}
+
+// ReadLine implements input(): the next line of stdin without its line
+// ending, raising EOFError when no input is left
+func ReadLine() string {
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+	line, err := stdin.ReadString('\n')
+	if err == io.EOF && line == "" {
+		Raise("EOFError", "EOF when reading a line")
+	} else if err != nil && err != io.EOF {
+		Raise("OSError", "%s", err.Error())
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+}
+
+// Input implements input(prompt), writing the prompt to stdout first
+func Input(prompt string) string {
+	PrintOpts(PrintOptions{End: ""}, prompt)
+	return ReadLine()
+}
+
+// ReadWords implements input().split()
+func ReadWords() []string {
+	return strings.FieldsFunc(ReadLine(), isPySpace)
+}
+
+// ReadInts implements list(map(int, input().split())), raising ValueError
+// for a word int() would reject
+func ReadInts() []int {
+	words := ReadWords()
+	result := make([]int, len(words))
+	for i, word := range words {
+		result[i] = ToIntBase(word, 10)
+	}
+	return result
+}
+
+// ReadFloats implements list(map(float, input().split())), raising
+// ValueError for a word float() would reject
+func ReadFloats() []float64 {
+	words := ReadWords()
+	result := make([]float64, len(words))
+	for i, word := range words {
+		result[i] = parseFloat(word)
+	}
+	return result
+}
+
+// Text files
+//
+// PyFile is a text-mode file opened with Python's default newline handling:
+// reading translates "\r\n" and "\r" to "\n" (universal newlines), so every
+// line returned by ReadLine/ReadLines/Lines ends in "\n" except possibly the
+// last. StringOps.Splitlines uses the same splitting code, so a file's lines
+// and the lines of its text agree. As in Python, str.splitlines additionally
+// breaks on \v, \f, \x1c-\x1e, \x85, \u2028 and \u2029, which files do not.
+
+// PyFile is an open text file (Python's open() result)
+type PyFile struct {
+	Name   string
+	Mode   string
+	file   *os.File
+	buffer string
+	loaded bool
+	closed bool
+}
+
+// Open opens a text file like Python's open(path, mode) for modes "r", "w",
+// "a" and "x", raising FileNotFoundError/FileExistsError/OSError on failure
+func Open(path string, mode string) *PyFile {
+	if mode == "" {
+		mode = "r"
+	}
+	flags := 0
+	switch strings.TrimSuffix(strings.TrimSuffix(mode, "t"), "+") {
+	case "r":
+		flags = os.O_RDONLY
+	case "w":
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	case "a":
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	case "x":
+		flags = os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	default:
+		Raise("ValueError", "invalid mode: '%s'", mode)
+	}
+	if strings.HasSuffix(strings.TrimSuffix(mode, "t"), "+") {
+		flags = flags&^(os.O_RDONLY|os.O_WRONLY) | os.O_RDWR
+	}
+
+	f, err := os.OpenFile(path, flags, 0o666)
+	if err != nil {
+		switch {
+		case os.IsNotExist(err):
+			Raise("FileNotFoundError", "[Errno 2] No such file or directory: %s", pyQuote(path))
+		case os.IsExist(err):
+			Raise("FileExistsError", "[Errno 17] File exists: %s", pyQuote(path))
+		default:
+			Raise("OSError", "%s", err.Error())
+		}
+	}
+	return &PyFile{Name: path, Mode: mode, file: f}
+}
+
+// load reads the rest of the file into the line buffer with newlines translated
+func (f *PyFile) load() {
+	f.checkOpen()
+	if f.loaded {
+		return
+	}
+	data, err := io.ReadAll(f.file)
+	if err != nil {
+		Raise("UnsupportedOperation", "not readable")
+	}
+	f.buffer = translateNewlines(string(data))
+	f.loaded = true
+}
+
+// checkOpen raises ValueError for operations on a closed file
+func (f *PyFile) checkOpen() {
+	if f.closed {
+		Raise("ValueError", "I/O operation on closed file.")
+	}
+}
+
+// Read returns the rest of the file (f.read())
+func (f *PyFile) Read() string {
+	f.load()
+	text := f.buffer
+	f.buffer = ""
+	return text
+}
+
+// ReadLine returns the next line including its "\n", or "" at end of file (f.readline())
+func (f *PyFile) ReadLine() string {
+	f.load()
+	if f.buffer == "" {
+		return ""
+	}
+	end := nextLineEnd(f.buffer, isUniversalNewline)
+	line := f.buffer[:end]
+	f.buffer = f.buffer[end:]
+	return line
+}
+
+// ReadLines returns the remaining lines, each keeping its "\n" (f.readlines())
+func (f *PyFile) ReadLines() []string {
+	return splitLines(f.Read(), true, isUniversalNewline)
+}
+
+// Lines returns the remaining lines for iteration (for line in f)
+func (f *PyFile) Lines() []string {
+	return f.ReadLines()
+}
+
+// Write writes s to the file and returns the number of characters written (f.write(s))
+func (f *PyFile) Write(s string) int {
+	f.checkOpen()
+	if _, err := f.file.WriteString(s); err != nil {
+		Raise("OSError", "%s", err.Error())
+	}
+	return utf8.RuneCountInString(s)
+}
+
+// Writer adapts the file to io.Writer so print(..., file=f) can write to it
+func (f *PyFile) Writer() io.Writer {
+	return pyFileWriter{f}
+}
+
+// pyFileWriter writes byte slices through PyFile.Write
+type pyFileWriter struct {
+	file *PyFile
+}
+
+// Write implements io.Writer
+func (w pyFileWriter) Write(p []byte) (int, error) {
+	w.file.Write(string(p))
+	return len(p), nil
+}
+
+// Close closes the file; closing twice is allowed as in Python (f.close())
+func (f *PyFile) Close() {
+	if f.closed {
+		return
+	}
+	f.closed = true
+	f.file.Close()
+}
+
+// Splitlines splits str at Python's line boundaries, keeping the line endings
+// when keepends is true (str.splitlines(keepends))
+func (s StringOps) Splitlines(str string, keepends bool) []string {
+	return splitLines(str, keepends, isLineBoundary)
+}
+
+// splitLines is the line splitter shared by files and str.splitlines: a final
+// line ending does not produce an extra empty line, and "\r\n" is one boundary
+func splitLines(text string, keepends bool, isBreak func(rune) bool) []string {
+	lines := []string{}
+	for text != "" {
+		end := nextLineEnd(text, isBreak)
+		line := text[:end]
+		if !keepends {
+			if strings.HasSuffix(line, "\r\n") {
+				line = line[:len(line)-2]
+			} else if r, size := utf8.DecodeLastRuneInString(line); isBreak(r) {
+				line = line[:len(line)-size]
+			}
+		}
+		lines = append(lines, line)
+		text = text[end:]
+	}
+	return lines
+}
+
+// nextLineEnd returns the byte offset just past the first line ending in text,
+// or len(text) when the text has no line ending
+func nextLineEnd(text string, isBreak func(rune) bool) int {
+	for i, r := range text {
+		if isBreak(r) {
+			if r == '\r' && strings.HasPrefix(text[i+1:], "\n") {
+				return i + 2
+			}
+			return i + utf8.RuneLen(r)
+		}
+	}
+	return len(text)
+}
+
+// translateNewlines converts "\r\n" and "\r" to "\n" (universal newlines mode)
+func translateNewlines(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+}
+
+// isUniversalNewline reports the line endings recognized when reading files
+func isUniversalNewline(r rune) bool {
+	return r == '\n' || r == '\r'
+}
+
+// isLineBoundary reports the line boundaries recognized by str.splitlines
+func isLineBoundary(r rune) bool {
+	switch r {
+	case '\n', '\r', '\v', '\f', '\x1c', '\x1d', '\x1e', '\u0085', '\u2028', '\u2029':
+		return true
+	}
+	return false
+}
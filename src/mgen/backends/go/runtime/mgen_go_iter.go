@@ -0,0 +1,333 @@
+package mgen
+
+import "sort"
+
+// Iteration sources
+//
+// Comprehension ops take plain slices (or a Range). enumerate() and zip()
+// produce slices of Pair (Triple for zip() of three), and an Iterator is
+// collected into a slice first, so every source reaches ListComprehension
+// and friends the same way and tuple targets unpack the record's fields.
+// A for loop directly over enumerate(xs) or zip(xs, ys) ranges over the
+// sources instead and never builds these slices.
+//
+// The builtins are plain slice-to-slice functions, so they compose like
+// Python's: Enumerate(Sorted(xs, false), 0), Enumerate(Reversed(xs), 1),
+// Reversed(Enumerate(xs, 0)) (Python needs list() around the enumerate),
+// Zip(Sorted(a, false), Reversed(b)) and SortedBy(Enumerate(xs, 0), key, true).
+// Sorted needs an ordered element type; Pair elements sort through SortedBy.
+// Each has an in-place counterpart for list.sort(): Sort, SortBy, SortByValue
+// and SortValues.
+
+// Pair is a two-element tuple produced by enumerate() and zip()
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// String renders the pair like a Python tuple: (0, 'a')
+func (p Pair[A, B]) String() string {
+	return "(" + Repr(p.First) + ", " + Repr(p.Second) + ")"
+}
+
+// tupleItems returns the fields as the items of a two-element tuple
+func (p Pair[A, B]) tupleItems() []interface{} {
+	return []interface{}{p.First, p.Second}
+}
+
+// Enumerate pairs each element with its index, counting from start (enumerate(xs, start))
+func Enumerate[T any](xs []T, start int) []Pair[int, T] {
+	result := make([]Pair[int, T], len(xs))
+	for i, x := range xs {
+		result[i] = Pair[int, T]{First: start + i, Second: x}
+	}
+	return result
+}
+
+// Zip pairs up elements of a and b, stopping at the shorter one (zip(a, b))
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	result := make([]Pair[A, B], n)
+	for i := 0; i < n; i++ {
+		result[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return result
+}
+
+// Triple is a three-element tuple produced by zip() of three iterables
+type Triple[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// String renders the triple like a Python tuple: (1, 'a', 2.5)
+func (t Triple[A, B, C]) String() string {
+	return "(" + Repr(t.First) + ", " + Repr(t.Second) + ", " + Repr(t.Third) + ")"
+}
+
+// tupleItems returns the fields as the items of a three-element tuple
+func (t Triple[A, B, C]) tupleItems() []interface{} {
+	return []interface{}{t.First, t.Second, t.Third}
+}
+
+// Zip3 groups elements of a, b and c, stopping at the shortest (zip(a, b, c))
+func Zip3[A, B, C any](a []A, b []B, c []C) []Triple[A, B, C] {
+	n := min(len(a), len(b), len(c))
+	result := make([]Triple[A, B, C], n)
+	for i := 0; i < n; i++ {
+		result[i] = Triple[A, B, C]{First: a[i], Second: b[i], Third: c[i]}
+	}
+	return result
+}
+
+// Sorted returns a sorted copy of xs (sorted(xs, reverse=reverse)). The sort is
+// stable in both directions, so equal elements keep their original order.
+func Sorted[T Ordered](xs []T, reverse bool) []T {
+	return SortedBy(xs, func(x T) T { return x }, reverse)
+}
+
+// SortedBy returns a copy of xs sorted by key (sorted(xs, key=key, reverse=reverse))
+func SortedBy[T any, K Ordered](xs []T, key func(T) K, reverse bool) []T {
+	result := append([]T{}, xs...)
+	SortBy(result, key, reverse)
+	return result
+}
+
+// SortedByValue is SortedBy for keys that are not a single ordered type, such
+// as the tuple in sorted(xs, key=lambda p: (p.age, p.name)): keys compare with
+// Python's rules and raise TypeError when they cannot be ordered
+func SortedByValue[T any](xs []T, key func(T) interface{}, reverse bool) []T {
+	result := append([]T{}, xs...)
+	SortByValue(result, key, reverse)
+	return result
+}
+
+// Sort sorts xs in place (xs.sort(reverse=reverse)), stably like Sorted
+func Sort[T Ordered](xs []T, reverse bool) {
+	SortBy(xs, func(x T) T { return x }, reverse)
+}
+
+// SortBy sorts xs in place by key (xs.sort(key=key, reverse=reverse)); the
+// key function is called once per element
+func SortBy[T any, K Ordered](xs []T, key func(T) K, reverse bool) {
+	keys := make([]K, len(xs))
+	for i, x := range xs {
+		keys[i] = key(x)
+	}
+	sort.Stable(keyedSort[T, K]{items: xs, keys: keys, reverse: reverse})
+}
+
+// SortByValue is SortBy for keys compared with Python's rules, like SortedByValue
+func SortByValue[T any](xs []T, key func(T) interface{}, reverse bool) {
+	keys := make([]interface{}, len(xs))
+	for i, x := range xs {
+		keys[i] = key(x)
+	}
+	sort.Stable(valueKeyedSort[T]{items: xs, keys: keys, reverse: reverse})
+}
+
+// valueKeyedSort sorts items by precomputed dynamically typed keys
+type valueKeyedSort[T any] struct {
+	items   []T
+	keys    []interface{}
+	reverse bool
+}
+
+func (s valueKeyedSort[T]) Len() int { return len(s.items) }
+
+func (s valueKeyedSort[T]) Less(i, j int) bool {
+	if s.reverse {
+		return compareValues(s.keys[j], s.keys[i]) < 0
+	}
+	return compareValues(s.keys[i], s.keys[j]) < 0
+}
+
+func (s valueKeyedSort[T]) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+// keyedSort sorts items by precomputed keys, calling each key function once
+type keyedSort[T any, K Ordered] struct {
+	items   []T
+	keys    []K
+	reverse bool
+}
+
+func (s keyedSort[T, K]) Len() int { return len(s.items) }
+
+func (s keyedSort[T, K]) Less(i, j int) bool {
+	if s.reverse {
+		return s.keys[j] < s.keys[i]
+	}
+	return s.keys[i] < s.keys[j]
+}
+
+func (s keyedSort[T, K]) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+// SortedValues sorts dynamically typed values with Python's comparison rules,
+// raising TypeError for values that cannot be ordered (sorted(iterable))
+func SortedValues(iterable interface{}, reverse bool) []interface{} {
+	result := iterValues(iterable)
+	SortValues(result, reverse)
+	return result
+}
+
+// SortValues sorts dynamically typed values in place, like SortedValues
+func SortValues(xs []interface{}, reverse bool) {
+	sort.SliceStable(xs, func(i, j int) bool {
+		if reverse {
+			return compareValues(xs[j], xs[i]) < 0
+		}
+		return compareValues(xs[i], xs[j]) < 0
+	})
+}
+
+// Reversed returns a reversed copy of xs (list(reversed(xs)))
+func Reversed[T any](xs []T) []T {
+	result := make([]T, len(xs))
+	for i, x := range xs {
+		result[len(xs)-1-i] = x
+	}
+	return result
+}
+
+// Iterator is a lazy source of values; Next reports false once exhausted
+type Iterator[T any] interface {
+	Next() (T, bool)
+}
+
+// Collect drains an iterator into a slice (list(it))
+func Collect[T any](it Iterator[T]) []T {
+	result := []T{}
+	for {
+		item, ok := it.Next()
+		if !ok {
+			return result
+		}
+		result = append(result, item)
+	}
+}
+
+// Iter returns an iterator over the elements of xs (iter(xs))
+func Iter[T any](xs []T) Iterator[T] {
+	return &sliceIterator[T]{items: xs}
+}
+
+// sliceIterator yields a slice's elements one at a time
+type sliceIterator[T any] struct {
+	items []T
+	next  int
+}
+
+// Next returns the next element of the slice
+func (it *sliceIterator[T]) Next() (T, bool) {
+	if it.next >= len(it.items) {
+		var zero T
+		return zero, false
+	}
+	it.next++
+	return it.items[it.next-1], true
+}
+
+// Iter returns an iterator over the range's values
+func (r Range) Iter() Iterator[int] {
+	if r.Step == 0 {
+		Raise("ValueError", "range() arg 3 must not be zero")
+	}
+	return &rangeIterator{next: r.Start, r: r}
+}
+
+// rangeIterator yields a Range's values one at a time
+type rangeIterator struct {
+	next int
+	r    Range
+}
+
+// Next returns the next value in the range
+func (it *rangeIterator) Next() (int, bool) {
+	if (it.r.Step > 0 && it.next >= it.r.Stop) || (it.r.Step < 0 && it.next <= it.r.Stop) {
+		return 0, false
+	}
+	value := it.next
+	it.next += it.r.Step
+	return value, true
+}
+
+// Unpacking
+//
+// A for loop whose target is a nested or starred tuple, such as
+// for (a, b), *rest in rows, unpacks each item with UnpackN or UnpackStar.
+// Both accept any iterable, and the Pair, Triple, KV and PyDictEntry records
+// that enumerate(), zip() and items() yield count as tuples. A wrong
+// number of items raises ValueError with Python's message.
+
+// tupleLike is implemented by the runtime's tuple records (Pair, Triple, KV, PyDictEntry, Tuple2, Tuple3)
+type tupleLike interface {
+	tupleItems() []interface{}
+}
+
+// unpackItems returns the items a target list receives from x
+func unpackItems(x interface{}) []interface{} {
+	if t, ok := x.(tupleLike); ok {
+		return t.tupleItems()
+	}
+	return iterValues(x)
+}
+
+// UnpackN returns the n items of x for a target list of n names (a, b = x),
+// raising ValueError when x has fewer or more items
+func UnpackN(x interface{}, n int) []interface{} {
+	items := unpackItems(x)
+	if len(items) < n {
+		Raise("ValueError", "not enough values to unpack (expected %d, got %d)", n, len(items))
+	}
+	if len(items) > n {
+		Raise("ValueError", "too many values to unpack (expected %d)", n)
+	}
+	return items
+}
+
+// UnpackSlice returns xs for a target list of n names (a, b = xs), raising
+// ValueError like UnpackN when xs has fewer or more items
+func UnpackSlice[T any](xs []T, n int) []T {
+	if len(xs) < n {
+		Raise("ValueError", "not enough values to unpack (expected %d, got %d)", n, len(xs))
+	}
+	if len(xs) > n {
+		Raise("ValueError", "too many values to unpack (expected %d)", n)
+	}
+	return xs
+}
+
+// UnpackSliceStar returns xs for a target list with a starred name
+// (first, *rest = xs), raising ValueError like UnpackStar when xs has fewer
+// than before+after items
+func UnpackSliceStar[T any](xs []T, before, after int) []T {
+	if len(xs) < before+after {
+		Raise("ValueError", "not enough values to unpack (expected at least %d, got %d)", before+after, len(xs))
+	}
+	return xs
+}
+
+// UnpackStar unpacks x for a target list with a starred name (a, *rest, b = x):
+// the result holds the before leading items, a []interface{} list of the
+// middle items, then the after trailing items. It raises ValueError when x has
+// fewer than before+after items.
+func UnpackStar(x interface{}, before, after int) []interface{} {
+	items := unpackItems(x)
+	if len(items) < before+after {
+		Raise("ValueError", "not enough values to unpack (expected at least %d, got %d)", before+after, len(items))
+	}
+	result := make([]interface{}, 0, before+after+1)
+	result = append(result, items[:before]...)
+	result = append(result, append([]interface{}{}, items[before:len(items)-after]...))
+	return append(result, items[len(items)-after:]...)
+}
@@ -0,0 +1,111 @@
+package mgen
+
+// List methods
+//
+// Python lists become Go slices of their item type. The methods that change a
+// list's length take the slice by pointer (mgen.ListPop(&xs)), as ExtendSlice
+// does for xs += ys, so the caller's variable sees the new length; the others
+// take the slice itself. Items are compared with Eq, so searching a
+// []interface{} for 1.0 finds 1 as in Python.
+
+// ListInsert implements xs.insert(i, x); like Python it clamps i to the list
+func ListInsert[T any](xs *[]T, i int, x T) {
+	n := len(*xs)
+	i = clampIndex(i, n)
+	var zero T
+	*xs = append(*xs, zero)
+	copy((*xs)[i+1:], (*xs)[i:n])
+	(*xs)[i] = x
+}
+
+// ListRemove implements xs.remove(x), removing the first item equal to x and
+// raising ValueError when there is none
+func ListRemove[T any](xs *[]T, x T) {
+	for i, item := range *xs {
+		if Eq(item, x) {
+			*xs = append((*xs)[:i], (*xs)[i+1:]...)
+			return
+		}
+	}
+	Raise("ValueError", "list.remove(x): x not in list")
+}
+
+// ListPop implements xs.pop() and xs.pop(i), where a negative i counts from
+// the end; an empty list or an index outside it raises IndexError
+func ListPop[T any](xs *[]T, index ...int) T {
+	n := len(*xs)
+	if n == 0 {
+		Raise("IndexError", "pop from empty list")
+	}
+	i := n - 1
+	if len(index) > 0 {
+		i = index[0]
+		if i < 0 {
+			i += n
+		}
+		if i < 0 || i >= n {
+			Raise("IndexError", "pop index out of range")
+		}
+	}
+	item := (*xs)[i]
+	*xs = append((*xs)[:i], (*xs)[i+1:]...)
+	return item
+}
+
+// ListIndex implements xs.index(x[, start[, stop]]): the position of the first
+// item equal to x, with start and stop clamped like slice bounds. A missing
+// item raises ValueError.
+func ListIndex[T any](xs []T, x T, bounds ...int) int {
+	start, stop := 0, len(xs)
+	if len(bounds) > 0 {
+		start = clampIndex(bounds[0], len(xs))
+	}
+	if len(bounds) > 1 {
+		stop = clampIndex(bounds[1], len(xs))
+	}
+	for i := start; i < stop; i++ {
+		if Eq(xs[i], x) {
+			return i
+		}
+	}
+	Raise("ValueError", "%s is not in list", Repr(x))
+	return -1
+}
+
+// clampIndex turns a possibly negative slice bound into a position within a
+// sequence of length n
+func clampIndex(i, n int) int {
+	if i < 0 {
+		return max(i+n, 0)
+	}
+	return min(i, n)
+}
+
+// ListCount implements xs.count(x), the number of items equal to x
+func ListCount[T any](xs []T, x T) int {
+	count := 0
+	for _, item := range xs {
+		if Eq(item, x) {
+			count++
+		}
+	}
+	return count
+}
+
+// ListReverse implements xs.reverse(), reversing the items in place
+func ListReverse[T any](xs []T) {
+	for i, j := 0, len(xs)-1; i < j; i, j = i+1, j-1 {
+		xs[i], xs[j] = xs[j], xs[i]
+	}
+}
+
+// ListClear implements xs.clear()
+func ListClear[T any](xs *[]T) {
+	*xs = (*xs)[:0]
+}
+
+// ConcatSlices implements xs + ys for lists of one type, returning a new list
+func ConcatSlices[T any](xs, ys []T) []T {
+	result := make([]T, 0, len(xs)+len(ys))
+	return append(append(result, xs...), ys...)
+}
@@ -0,0 +1,126 @@
+package mgen
+
+// Pattern matching
+//
+// A match statement is lowered to a chain of ifs whose conditions test each
+// pattern's shape, so subpatterns only look at values already known to fit.
+// These helpers cover the checks that need a value's dynamic type: whether it
+// can match a sequence or mapping pattern, and class patterns of the builtin
+// types and of generated classes. Captured items are then read with UnpackN
+// and UnpackStar, mapping values with MappingValue and attributes through
+// Instance.
+
+// MatchSequence reports whether x matches a sequence pattern of n items, or of
+// at least n items when the pattern has a starred name. As in Python, lists
+// and tuples can match but strings, bytes, dicts and sets never do.
+func MatchSequence(x interface{}, n int, star bool) bool {
+	switch x.(type) {
+	case nil, string, PyBytes, *PyByteArray:
+		return false
+	}
+	if _, ok := x.(tupleLike); !ok && !isSequence(x) {
+		return false
+	}
+	length := len(unpackItems(x))
+	return length == n || star && length > n
+}
+
+// MatchClass implements a builtin class pattern such as case int(): like
+// isinstance(x, int), it accepts bools for int, and object accepts anything
+func MatchClass(x interface{}, class string) bool {
+	name := pyTypeName(x)
+	switch x.(type) {
+	case tupleLike:
+		name = "tuple"
+	case *PyDict:
+		name = "dict"
+	case dictLike:
+		// defaultdict, Counter and OrderedDict are dict subclasses
+		if class == "dict" {
+			name = "dict"
+		}
+	case *PySet:
+		name = "set"
+	}
+	return name == class || class == "int" && name == "bool" || class == "object"
+}
+
+// MatchMapping reports whether x matches a mapping pattern with the given
+// keys: a dict of any kind holding all of them, whatever else it holds
+func MatchMapping(x interface{}, keys ...interface{}) bool {
+	switch x.(type) {
+	case *PyDict, dictLike:
+	default:
+		return false
+	}
+	for _, key := range keys {
+		if _, ok := matchLookup(x, key); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// MappingValue returns the value x holds for key, once MatchMapping has
+// checked it is there
+func MappingValue(x interface{}, key interface{}) interface{} {
+	value, _ := matchLookup(x, key)
+	return value
+}
+
+// MappingRest returns a new dict of the entries of x except keys, which a
+// mapping pattern's **rest captures
+func MappingRest(x interface{}, keys ...interface{}) *PyDict {
+	rest := NewPyDict()
+	for _, e := range updateEntries(x) {
+		matched := false
+		for _, key := range keys {
+			matched = matched || Eq(e.Key, key)
+		}
+		if !matched {
+			rest.Set(e.Key, e.Value)
+		}
+	}
+	return rest
+}
+
+// matchLookup looks key up in x without a missing value's default, as
+// mapping patterns do, reporting false for a missing key or a non-dict x
+func matchLookup(x interface{}, key interface{}) (interface{}, bool) {
+	switch d := x.(type) {
+	case *PyDict:
+		if d.Contains(key) {
+			return d.Get(key), true
+		}
+	case dictLike:
+		return d.pyLookup(key)
+	}
+	return nil, false
+}
+
+// DictWithout returns a copy of d without keys, for the **rest of a mapping
+// pattern matching a typed dict
+func DictWithout[K comparable, V any](d *Dict[K, V], keys ...K) *Dict[K, V] {
+	rest := d.Copy()
+	for _, key := range keys {
+		rest.Delete(key)
+	}
+	return rest
+}
+
+// MatchInstance reports whether x holds a T or a *T, for the class pattern of
+// a class without subclasses such as case Point(x=0)
+func MatchInstance[T any](x interface{}) bool {
+	_, ok := AsInstance[T](x)
+	return ok
+}
+
+// Instance returns a pointer to the T that x holds, once MatchInstance has
+// checked it, so the class pattern's subpatterns can read its attributes
+func Instance[T any](x interface{}) *T {
+	if p, ok := x.(*T); ok {
+		return p
+	}
+	v, _ := AsInstance[T](x)
+	return &v
+}
@@ -0,0 +1,125 @@
+package mgen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Nested lists as matrices
+//
+// Grid and numeric code builds matrices as nested lists ([][]int,
+// [][]float64, or []interface{} holding lists). Shape reports their
+// dimensions and FormatMatrix lays them out with aligned columns, in the
+// style of NumPy's array printing:
+//
+//	[[ 1  2  3]
+//	 [10 20 30]]
+
+// nestedItems returns the items of a list value; anything else is a leaf
+func nestedItems(x interface{}) ([]interface{}, bool) {
+	if list, ok := x.(*PyList); ok {
+		return list.Items, true
+	}
+	switch reflect.ValueOf(x).Kind() {
+	case reflect.Slice, reflect.Array:
+		return iterValues(x), true
+	}
+	return nil, false
+}
+
+// Shape returns the dimensions of a rectangular nested list: [2, 3] for a
+// 2x3 matrix and [] for a scalar. Rows of different lengths, or a mix of
+// lists and scalars at one level, raise ValueError.
+func Shape(x interface{}) []int {
+	return shapeOf(x, nil)
+}
+
+// shapeOf returns the shape of x, nested inside lists with the outer shape
+func shapeOf(x interface{}, outer []int) []int {
+	items, ok := nestedItems(x)
+	if !ok {
+		return []int{}
+	}
+	if len(items) == 0 {
+		return []int{0}
+	}
+	detected := append(append([]int{}, outer...), len(items))
+	inner := shapeOf(items[0], detected)
+	for _, item := range items[1:] {
+		if !equalShapes(shapeOf(item, detected), inner) {
+			Raise("ValueError", "inhomogeneous shape after %d dimensions; the detected shape was %s + inhomogeneous part",
+				len(detected), formatShape(detected))
+		}
+	}
+	return append([]int{len(items)}, inner...)
+}
+
+// equalShapes reports whether two shapes have the same dimensions
+func equalShapes(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// formatShape renders a shape as a Python tuple: (2, 3) or (4,)
+func formatShape(shape []int) string {
+	if len(shape) == 1 {
+		return fmt.Sprintf("(%d,)", shape[0])
+	}
+	parts := make([]string, len(shape))
+	for i, n := range shape {
+		parts[i] = fmt.Sprintf("%d", n)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// FormatMatrix renders a rectangular nested list with every element
+// right-aligned to the widest repr. Rows of the innermost lists are separated
+// by a newline, and each further dimension adds a blank line between blocks.
+// Ragged nesting raises ValueError (see Shape).
+func FormatMatrix(x interface{}) string {
+	dims := len(Shape(x))
+	width := 0
+	var measure func(v interface{})
+	measure = func(v interface{}) {
+		if items, ok := nestedItems(v); ok {
+			for _, item := range items {
+				measure(item)
+			}
+		} else if n := len([]rune(Repr(v))); n > width {
+			width = n
+		}
+	}
+	measure(x)
+	return formatMatrixLevel(x, 0, dims, width)
+}
+
+// formatMatrixLevel renders the list at depth, with dims dimensions in total
+func formatMatrixLevel(x interface{}, depth, dims, width int) string {
+	items, ok := nestedItems(x)
+	if !ok {
+		repr := Repr(x)
+		return strings.Repeat(" ", width-len([]rune(repr))) + repr
+	}
+	sep := " "
+	if remaining := dims - depth; remaining > 1 {
+		sep = strings.Repeat("\n", remaining-1) + strings.Repeat(" ", depth+1)
+	}
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = formatMatrixLevel(item, depth+1, dims, width)
+	}
+	return "[" + strings.Join(parts, sep) + "]"
+}
+
+// PrintMatrix prints a nested list with aligned columns (see FormatMatrix)
+func PrintMatrix(x interface{}) {
+	Print(FormatMatrix(x))
+}
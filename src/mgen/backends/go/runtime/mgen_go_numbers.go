@@ -0,0 +1,255 @@
+package mgen
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Numeric conversions
+//
+// ToInt and ToFloat implement int() and float(). Strings are parsed by hand
+// rather than handed to strconv, which accepts forms Python rejects (Go's
+// "0x1f" and "0x1p3", "Inf" without a sign rule, underscores next to a base
+// prefix) and rejects forms Python accepts (surrounding whitespace, digit
+// separators such as "1_000", non-ASCII decimal digits such as "١٢").
+
+// ToInt implements int(x): numbers truncate toward zero, bools give 0 or 1,
+// and strings are parsed as base-10 literals (raising ValueError otherwise)
+func ToInt(x interface{}) int {
+	switch v := x.(type) {
+	case int:
+		return v
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		n, _ := asInt(v)
+		return int(n)
+	case bool:
+		return BoolToInt(v)
+	case float32:
+		return floatToInt(float64(v))
+	case float64:
+		return floatToInt(v)
+	case *PyInt:
+		return v.Int()
+	case string:
+		return ToIntBase(v, 10)
+	}
+	Raise("TypeError", "int() argument must be a string, a bytes-like object or a real number, not '%s'", pyTypeName(x))
+	return 0
+}
+
+// floatToInt truncates like int(f), rejecting infinities and NaN
+func floatToInt(f float64) int {
+	switch {
+	case math.IsInf(f, 0):
+		Raise("OverflowError", "cannot convert float infinity to integer")
+	case math.IsNaN(f):
+		Raise("ValueError", "cannot convert float NaN to integer")
+	case f >= math.MaxInt64 || f < math.MinInt64:
+		Raise("OverflowError", "Python int too large to convert to C long")
+	}
+	return int(f)
+}
+
+// ToIntBase implements int(s, base) for bases 2-36, or 0 to read the base
+// from a 0b/0o/0x prefix. Surrounding whitespace, a sign, the prefix matching
+// the base, and single underscores between digits are allowed.
+func ToIntBase(s string, base int) int {
+	if base != 0 && (base < 2 || base > 36) {
+		Raise("ValueError", "int() base must be >= 2 and <= 36, or 0")
+	}
+	invalid := func() {
+		Raise("ValueError", "invalid literal for int() with base %d: %s", base, pyQuote(s))
+	}
+
+	text := strings.TrimFunc(s, isPySpace)
+	negative := false
+	if text != "" && (text[0] == '+' || text[0] == '-') {
+		negative = text[0] == '-'
+		text = text[1:]
+	}
+
+	digitsBase := base
+	if prefixBase := basePrefix(text); prefixBase != 0 && (base == 0 || base == prefixBase) {
+		digitsBase = prefixBase
+		text = text[2:]
+		// An underscore may follow the prefix: 0x_1f
+		text = strings.TrimPrefix(text, "_")
+	} else if base == 0 {
+		digitsBase = 10
+		// Base 0 follows literal rules: no leading zeros on non-zero numbers
+		if len(text) > 1 && text[0] == '0' && strings.Trim(text, "0_") != "" {
+			invalid()
+		}
+	}
+
+	digits, ok := stripDigitSeparators(text, digitsBase > 10)
+	if !ok || digits[0] == '+' || digits[0] == '-' {
+		invalid()
+	}
+	value, err := strconv.ParseInt(digits, digitsBase, 64)
+	if err != nil {
+		if numErr, isNum := err.(*strconv.NumError); isNum && numErr.Err == strconv.ErrRange {
+			Raise("OverflowError", "Python int too large to convert to C long")
+		}
+		invalid()
+	}
+	if negative {
+		value = -value
+	}
+	return int(value)
+}
+
+// basePrefix returns 2, 8 or 16 for a 0b/0o/0x prefix, or 0
+func basePrefix(text string) int {
+	if len(text) < 2 || text[0] != '0' {
+		return 0
+	}
+	switch text[1] {
+	case 'b', 'B':
+		return 2
+	case 'o', 'O':
+		return 8
+	case 'x', 'X':
+		return 16
+	}
+	return 0
+}
+
+// stripDigitSeparators removes underscores that sit between two digits and
+// converts non-ASCII decimal digits to ASCII. ASCII letters count as digits
+// when letters is set (bases above 10). It reports false for an empty string
+// or a misplaced underscore ("_1", "1__0", "1_", "1_.5").
+func stripDigitSeparators(text string, letters bool) (string, bool) {
+	var b strings.Builder
+	previousDigit, pendingUnderscore := false, false
+	for _, r := range text {
+		if r == '_' {
+			if !previousDigit {
+				return "", false
+			}
+			previousDigit, pendingUnderscore = false, true
+			continue
+		}
+		isDigit := unicode.IsDigit(r) || (letters && r < unicode.MaxASCII && unicode.IsLetter(r))
+		if pendingUnderscore && !isDigit {
+			return "", false
+		}
+		if r > unicode.MaxASCII && unicode.IsDigit(r) {
+			r = '0' + rune(decimalValue(r))
+		}
+		b.WriteRune(r)
+		previousDigit, pendingUnderscore = isDigit, false
+	}
+	return b.String(), b.Len() > 0 && !pendingUnderscore
+}
+
+// decimalValue returns the value of a Unicode decimal digit. Decimal digits
+// are encoded in runs of ten starting at zero, so the value is the distance
+// from the start of the run, modulo ten for adjacent runs.
+func decimalValue(r rune) int {
+	start := r
+	for unicode.IsDigit(start - 1) {
+		start--
+	}
+	return int(r-start) % 10
+}
+
+// ToFloat implements float(x): numbers convert, bools give 0.0 or 1.0, and
+// strings accept decimal and exponent forms, "inf"/"infinity"/"nan" in any
+// case, surrounding whitespace and digit separators (raising ValueError otherwise)
+func ToFloat(x interface{}) float64 {
+	switch v := x.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case bool:
+		return float64(BoolToInt(v))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		n, _ := asInt(v)
+		return float64(n)
+	case *PyInt:
+		return v.Float()
+	case string:
+		return parseFloat(v)
+	}
+	Raise("TypeError", "float() argument must be a string or a real number, not '%s'", pyTypeName(x))
+	return 0
+}
+
+// parseFloat parses a Python float() string
+func parseFloat(s string) float64 {
+	invalid := func() {
+		Raise("ValueError", "could not convert string to float: %s", pyQuote(s))
+	}
+
+	text := strings.TrimFunc(s, isPySpace)
+	sign := 1.0
+	body := text
+	if body != "" && (body[0] == '+' || body[0] == '-') {
+		if body[0] == '-' {
+			sign = -1
+		}
+		body = body[1:]
+	}
+	switch strings.ToLower(body) {
+	case "inf", "infinity":
+		return math.Inf(int(sign))
+	case "nan":
+		return math.NaN()
+	}
+
+	if !isDecimalFloat(body) {
+		invalid()
+	}
+	digits, ok := stripDigitSeparators(body, false)
+	if !ok {
+		invalid()
+	}
+	value, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		// Out-of-range values round to ±inf or 0 as in Python; anything else is invalid
+		if numErr, isNum := err.(*strconv.NumError); !isNum || numErr.Err != strconv.ErrRange {
+			invalid()
+		}
+	}
+	return sign * value
+}
+
+// isDecimalFloat checks the shape digits[.digits][e[sign]digits] (either side
+// of the point may be empty, not both), where digits may contain underscores
+// and non-ASCII decimal digits; separator placement is checked separately
+func isDecimalFloat(body string) bool {
+	mantissa, exponent, hasExponent := strings.Cut(strings.ToLower(body), "e")
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+	if !isDigitRun(whole, true) || !isDigitRun(fraction, true) || whole+fraction == "" {
+		return false
+	}
+	if hasExponent {
+		if exponent != "" && (exponent[0] == '+' || exponent[0] == '-') {
+			exponent = exponent[1:]
+		}
+		return isDigitRun(exponent, false)
+	}
+	return true
+}
+
+// isDigitRun reports whether s is only decimal digits and underscores
+func isDigitRun(s string, allowEmpty bool) bool {
+	if s == "" {
+		return allowEmpty
+	}
+	for _, r := range s {
+		if r != '_' && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPySpace reports the whitespace str.strip() and int()/float() remove
+func isPySpace(r rune) bool {
+	return unicode.IsSpace(r) || (r >= 0x1c && r <= 0x1f)
+}
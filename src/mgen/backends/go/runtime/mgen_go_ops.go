@@ -0,0 +1,766 @@
+package mgen
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Dynamic operators for values whose static type is interface{}.
+// These follow Python's operator semantics rather than Go's.
+
+// asInt returns x as an int64 when it is a Go integer type or a bool
+// (Python's bool is an int subclass, so True + True == 2)
+func asInt(x interface{}) (int64, bool) {
+	switch v := x.(type) {
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case uint:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// asFloat returns x as a float64 when it is a Go float, integer or bool
+func asFloat(x interface{}) (float64, bool) {
+	switch v := x.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case *PyInt:
+		return v.Float(), true
+	}
+	if i, ok := asInt(x); ok {
+		return float64(i), true
+	}
+	return 0, false
+}
+
+// isFloat reports whether x is a Go float type
+func isFloat(x interface{}) bool {
+	switch x.(type) {
+	case float32, float64:
+		return true
+	}
+	return false
+}
+
+// sortValues sorts values in a deterministic order: numbers numerically,
+// strings lexicographically, and anything else by its printed form
+func sortValues(items []interface{}) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if a, ok := asFloat(items[i]); ok {
+			if b, ok := asFloat(items[j]); ok {
+				return a < b
+			}
+		}
+		if a, ok := items[i].(string); ok {
+			if b, ok := items[j].(string); ok {
+				return a < b
+			}
+		}
+		return fmt.Sprint(items[i]) < fmt.Sprint(items[j])
+	})
+}
+
+// compareValues orders two values like Python's < operator and returns -1, 0
+// or 1: numbers (including bools) compare numerically, strings
+// lexicographically, and lists item by item. Other combinations raise
+// TypeError as in Python. Sequences skip the pairs that are == and order only
+// the first pair that differs, so (None, 1) < (None, 2) holds and
+// (1, "a") < (2, 2) never compares "a" with 2.
+func compareValues(a, b interface{}) int {
+	return compareWith("<", a, b)
+}
+
+// compareWith is compareValues naming op in the TypeError it raises
+func compareWith(op string, a, b interface{}) int {
+	if ab, bb, ok := bigOperands(a, b); ok {
+		return ab.Cmp(bb)
+	}
+	if af, ok := asFloat(a); ok {
+		if bf, ok := asFloat(b); ok {
+			switch {
+			case af < bf:
+				return -1
+			case af > bf:
+				return 1
+			}
+			return 0
+		}
+	}
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			return strings.Compare(as, bs)
+		}
+	}
+	if ab, bb, ok := bytesOperands(a, b); ok {
+		return ab.Cmp(bb)
+	}
+	ai, bi, ok := tupleOperands(a, b)
+	if !ok && isSequence(a) && isSequence(b) && pyTypeName(a) == pyTypeName(b) {
+		ai, bi, ok = iterValues(a), iterValues(b), true
+	}
+	if ok {
+		for i := 0; i < len(ai) && i < len(bi); i++ {
+			if !Eq(ai[i], bi[i]) {
+				return compareWith(op, ai[i], bi[i])
+			}
+		}
+		return compareValues(len(ai), len(bi))
+	}
+	Raise("TypeError", "'%s' not supported between instances of '%s' and '%s'", op, pyTypeName(a), pyTypeName(b))
+	return 0
+}
+
+// Eq implements Python's == for dynamically typed values: numbers compare
+// numerically with bools counting as 0 and 1 (True == 1, 1.0 == 1), dicts
+// by their entries, sets by their members, lists and tuples item by item and
+// instances of a class defining __eq__ by it; anything else must be deeply equal
+func Eq(a, b interface{}) bool {
+	if ab, bb, ok := bigOperands(a, b); ok {
+		return ab.Cmp(bb) == 0
+	}
+	if af, ok := asFloat(a); ok {
+		if bf, ok := asFloat(b); ok {
+			return af == bf
+		}
+	}
+	switch av := a.(type) {
+	case string:
+		bs, ok := b.(string)
+		return ok && av == bs
+	case PyBytes, *PyByteArray:
+		ab, bb, ok := bytesOperands(av, b)
+		return ok && ab.Cmp(bb) == 0
+	case *PyDict:
+		bv, ok := b.(*PyDict)
+		return ok && av.Equal(bv)
+	case setLike:
+		bv, ok := b.(setLike)
+		return ok && compareSets("==", av, bv)
+	case dictLike:
+		bv, ok := b.(dictLike)
+		if !ok || av.Len() != bv.Len() {
+			return false
+		}
+		if av.kind() == "OrderedDict" && bv.kind() == "OrderedDict" {
+			return orderedEqual(av, bv)
+		}
+		for _, e := range av.pyEntries() {
+			value, ok := bv.pyLookup(e.Key)
+			if !ok || !Eq(e.Value, value) {
+				return false
+			}
+		}
+		return true
+	}
+	ai, bi, ok := tupleOperands(a, b)
+	if !ok && isSequence(a) && isSequence(b) && pyTypeName(a) == pyTypeName(b) {
+		ai, bi, ok = iterValues(a), iterValues(b), true
+	}
+	if ok {
+		if len(ai) != len(bi) {
+			return false
+		}
+		for i := range ai {
+			if !Eq(ai[i], bi[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if e, ok := asProtocol[PyEquatable](a); ok {
+		return e.Equals(b)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// tupleOperands returns the items of a and b when both are tuples and at
+// least one is a record such as Pair or Tuple2 or a PyTuple; the other may
+// also be a []interface{}, which tuples are typed as where their static
+// type is a slice
+func tupleOperands(a, b interface{}) ([]interface{}, []interface{}, bool) {
+	isTuple := func(x interface{}) bool {
+		switch x.(type) {
+		case tupleLike, PyTuple:
+			return true
+		}
+		return false
+	}
+	items := func(x interface{}) ([]interface{}, bool) {
+		switch v := x.(type) {
+		case tupleLike:
+			return v.tupleItems(), true
+		case PyTuple:
+			return v, true
+		case []interface{}:
+			return v, true
+		}
+		return nil, false
+	}
+	ai, aok := items(a)
+	bi, bok := items(b)
+	return ai, bi, (isTuple(a) || isTuple(b)) && aok && bok
+}
+
+// Compare implements a Python comparison operator ("<", "<=", ">", ">=",
+// "==", "!=") between dynamically typed values. Orderings follow
+// compareValues, so a bool compares as an int (False < 1) and mismatched
+// types raise TypeError; sets are ordered by inclusion (a <= b is a subset).
+func Compare(op string, a, b interface{}) bool {
+	switch op {
+	case "==":
+		return Eq(a, b)
+	case "!=":
+		return !Eq(a, b)
+	}
+	if as, ok := a.(setLike); ok {
+		if bs, ok := b.(setLike); ok {
+			return compareSets(op, as, bs)
+		}
+	}
+	c := compareWith(op, a, b)
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	Raise("TypeError", "unsupported comparison operator %s", op)
+	return false
+}
+
+// Contains implements item in container: substrings of a string, keys of a
+// dict or map, and items of a list, tuple or set compared with Eq, so
+// 1 in [True] holds as in Python. Ranges are checked arithmetically, slices
+// of int, float64 and string are scanned directly, and map keys of the item's own type are looked up rather
+// than copied out by reflection.
+func Contains(container, item interface{}) bool {
+	switch c := container.(type) {
+	case string:
+		s, ok := item.(string)
+		if !ok {
+			Raise("TypeError", "'in <string>' requires string as left operand, not %s", pyTypeName(item))
+		}
+		return strings.Contains(c, s)
+	case *PyDict:
+		return c.Contains(item)
+	case PyBytes:
+		return c.Contains(item)
+	case *PyByteArray:
+		return c.Contains(item)
+	case Range:
+		// Only ints (and floats or bools equal to one) can be a range's values
+		if f, ok := asFloat(item); ok && f == math.Trunc(f) && math.Abs(f) < 1<<62 {
+			return c.Contains(int(f))
+		}
+		return false
+	case setLike:
+		return c.pyContains(item)
+	case dictLike:
+		_, ok := c.pyLookup(item)
+		return ok
+	case []int:
+		if f, ok := asFloat(item); ok {
+			for _, x := range c {
+				if float64(x) == f {
+					return true
+				}
+			}
+			return false
+		}
+	case []float64:
+		if f, ok := asFloat(item); ok {
+			for _, x := range c {
+				if x == f {
+					return true
+				}
+			}
+			return false
+		}
+	case []string:
+		s, ok := item.(string)
+		if !ok {
+			return false
+		}
+		for _, x := range c {
+			if x == s {
+				return true
+			}
+		}
+		return false
+	}
+	if rv := reflect.ValueOf(container); rv.Kind() == reflect.Map && item != nil {
+		// Scalar keys compare with == exactly as Eq would compare them
+		key := reflect.ValueOf(item)
+		if kind := key.Kind(); key.Type() == rv.Type().Key() && (kind <= reflect.Complex128 || kind == reflect.String) {
+			return rv.MapIndex(key).IsValid()
+		}
+	}
+	for _, x := range iterValues(container) {
+		if Eq(x, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// LenValue implements len(x) for a value whose static type is unknown. The
+// runtime containers, tuples, strings and ranges are matched by type before
+// falling back to reflection, so len() in a hot loop avoids reflect.
+func LenValue(x interface{}) int {
+	switch v := x.(type) {
+	case *PyList:
+		return len(v.Items)
+	case *PyDict:
+		return v.Len()
+	case setLike:
+		return v.Len()
+	case dictLike:
+		return v.Len()
+	case []interface{}:
+		return len(v)
+	case PyTuple:
+		return len(v)
+	case string:
+		return utf8.RuneCountInString(v)
+	case Range:
+		return v.Len()
+	case *PyByteArray:
+		return v.Len()
+	}
+	if sized, ok := asProtocol[PySized](x); ok {
+		return sized.PyLen()
+	}
+	rv := reflect.ValueOf(x)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len()
+	case reflect.String:
+		return utf8.RuneCountInString(rv.String())
+	}
+	Raise("TypeError", "object of type '%s' has no len()", pyTypeName(x))
+	return 0
+}
+
+// ToBool implements bool(x) with Python's truthiness: None, False, zero
+// numbers, and empty strings and containers are false; everything else is
+// true. Like LenValue it checks the common types before using reflection.
+func ToBool(x interface{}) bool {
+	switch v := x.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case *PyList:
+		return len(v.Items) > 0
+	case *PyDict:
+		return v.Len() > 0
+	case setLike:
+		return v.Len() > 0
+	case dictLike:
+		return v.Len() > 0
+	case []interface{}:
+		return len(v) > 0
+	case PyTuple:
+		return len(v) > 0
+	case Range:
+		return v.Len() > 0
+	case *PyByteArray:
+		return v.Len() > 0
+	}
+	if sized, ok := asProtocol[PySized](x); ok {
+		// Without __bool__, Python takes an object with __len__ as true when it is non-empty
+		return sized.PyLen() > 0
+	}
+	rv := reflect.ValueOf(x)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() != 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint() != 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() != 0
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len() > 0
+	case reflect.Ptr, reflect.Interface, reflect.Func:
+		return !rv.IsNil()
+	}
+	return true
+}
+
+// isSequence reports whether x is a list-like value (slice, array or *PyList)
+func isSequence(x interface{}) bool {
+	switch x.(type) {
+	case *PyList, []interface{}, PyTuple:
+		return true
+	case nil, bool, int, float64, string:
+		return false
+	}
+	kind := reflect.ValueOf(x).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// MinBy implements min(iterable, key=key) for dynamically typed values; a nil
+// key compares the items themselves. Iterating a map uses its keys, like
+// Python's min(d), and the first of several equal items wins.
+func MinBy(iterable interface{}, key func(interface{}) interface{}) interface{} {
+	return bestValue(iterable, key, "min", -1)
+}
+
+// MaxBy implements max(iterable, key=key) for dynamically typed values
+func MaxBy(iterable interface{}, key func(interface{}) interface{}) interface{} {
+	return bestValue(iterable, key, "max", 1)
+}
+
+// bestValue returns the first item whose key compares as want (-1 or 1)
+// against every other item
+func bestValue(iterable interface{}, key func(interface{}) interface{}, name string, want int) interface{} {
+	items := iterValues(iterable)
+	if len(items) == 0 {
+		Raise("ValueError", "%s() arg is an empty sequence", name)
+	}
+	if key == nil {
+		key = func(x interface{}) interface{} { return x }
+	}
+	best, bestKey := items[0], key(items[0])
+	for _, item := range items[1:] {
+		if k := key(item); compareValues(k, bestKey) == want {
+			best, bestKey = item, k
+		}
+	}
+	return best
+}
+
+// BinOp applies a Python binary operator ("+", "-", "*", "/", "//", "%", "**",
+// "&", "|", "^", "<<", ">>") to dynamically typed operands. Integer operands
+// produce int, true division and float operands produce float64, and sequences
+// support concatenation and repetition.
+func BinOp(op string, a, b interface{}) interface{} {
+	if ab, bb, ok := bigOperands(a, b); ok {
+		return bigBinOp(op, ab, bb)
+	}
+	if ai, ok := asInt(a); ok {
+		if bi, ok := asInt(b); ok {
+			return intBinOp(op, ai, bi)
+		}
+	}
+	if isFloat(a) || isFloat(b) {
+		af, aok := asFloat(a)
+		bf, bok := asFloat(b)
+		if aok && bok {
+			return floatBinOp(op, af, bf)
+		}
+	}
+
+	switch op {
+	case "+":
+		if as, ok := a.(string); ok {
+			if bs, ok := b.(string); ok {
+				return as + bs
+			}
+		}
+		if al, ok := a.(*PyList); ok {
+			if bl, ok := b.(*PyList); ok {
+				result := NewPyList(al.Items...)
+				result.Items = append(result.Items, bl.Items...)
+				return result
+			}
+		}
+		if av, bv := reflect.ValueOf(a), reflect.ValueOf(b); av.Kind() == reflect.Slice && av.Type() == bv.Type() {
+			result := reflect.MakeSlice(av.Type(), 0, av.Len()+bv.Len())
+			return reflect.AppendSlice(reflect.AppendSlice(result, av), bv).Interface()
+		}
+	case "%":
+		if format, ok := a.(string); ok {
+			return PercentFormat(format, b)
+		}
+	case "*":
+		if n, ok := asInt(b); ok {
+			if repeated, ok := repeatValue(a, int(n)); ok {
+				return repeated
+			}
+		}
+		if n, ok := asInt(a); ok {
+			if repeated, ok := repeatValue(b, int(n)); ok {
+				return repeated
+			}
+		}
+	}
+
+	Raise("TypeError", "unsupported operand type(s) for %s: '%s' and '%s'", op, pyTypeName(a), pyTypeName(b))
+	return nil
+}
+
+// repeatValue implements sequence repetition (seq * n) for strings, lists and slices
+func repeatValue(seq interface{}, n int) (interface{}, bool) {
+	if n < 0 {
+		n = 0
+	}
+	switch s := seq.(type) {
+	case string:
+		return strings.Repeat(s, n), true
+	case *PyList:
+		return NewPyList(RepeatSlice(s.Items, n)...), true
+	}
+	v := reflect.ValueOf(seq)
+	if v.Kind() != reflect.Slice {
+		return nil, false
+	}
+	result := reflect.MakeSlice(v.Type(), 0, v.Len()*n)
+	for i := 0; i < n; i++ {
+		result = reflect.AppendSlice(result, v)
+	}
+	return result.Interface(), true
+}
+
+// FloorDivInt divides two ints rounding toward negative infinity, as Python's
+// // does (and / on ints in Python 2 mode), where Go's / truncates toward zero
+func FloorDivInt(a, b int) int {
+	return FloorDiv(a, b)
+}
+
+// TrueDivInt implements a / b on ints in Python 3, which gives a float
+// (1 / 3 == 0.3333333333333333) where Go's / truncates toward zero
+func TrueDivInt(a, b int) float64 {
+	return intBinOp("/", int64(a), int64(b)).(float64)
+}
+
+// PowInt implements a ** b on ints, which Go has no operator for. A negative
+// exponent makes a float in Python, so it raises ValueError here instead
+func PowInt(a, b int) int {
+	if b < 0 {
+		Raise("ValueError", "negative exponent %d in an int power; use a float base", b)
+	}
+	return intBinOp("**", int64(a), int64(b)).(int)
+}
+
+// FloorDiv implements a // b on ints or floats, rounding the quotient toward
+// negative infinity (-7 // 2 == -4) where Go's / truncates toward zero
+func FloorDiv[T Numeric](a, b T) T {
+	if isFloating[T]() {
+		return T(floatBinOp("//", float64(a), float64(b)).(float64))
+	}
+	return T(intBinOp("//", int64(a), int64(b)).(int))
+}
+
+// PyMod implements a % b on ints or floats, giving the result the sign of the
+// divisor (-7 % 2 == 1) where Go's % takes the sign of the dividend
+func PyMod[T Numeric](a, b T) T {
+	if isFloating[T]() {
+		return T(floatBinOp("%", float64(a), float64(b)).(float64))
+	}
+	return T(intBinOp("%", int64(a), int64(b)).(int))
+}
+
+// isFloating reports whether T is a floating-point type, where 1 / 2 is not 0
+func isFloating[T Numeric]() bool {
+	var one T = 1
+	return one/2 != 0
+}
+
+// intBinOp applies a Python operator to two integers
+func intBinOp(op string, a, b int64) interface{} {
+	switch op {
+	case "+":
+		return int(a + b)
+	case "-":
+		return int(a - b)
+	case "*":
+		return int(a * b)
+	case "/":
+		if b == 0 {
+			Raise("ZeroDivisionError", "division by zero")
+		}
+		return float64(a) / float64(b)
+	case "//":
+		if b == 0 {
+			Raise("ZeroDivisionError", "integer division or modulo by zero")
+		}
+		q := a / b
+		if (a%b != 0) && ((a < 0) != (b < 0)) {
+			q--
+		}
+		return int(q)
+	case "%":
+		if b == 0 {
+			Raise("ZeroDivisionError", "integer modulo by zero")
+		}
+		m := a % b
+		if m != 0 && ((m < 0) != (b < 0)) {
+			m += b
+		}
+		return int(m)
+	case "**":
+		if b < 0 {
+			return math.Pow(float64(a), float64(b))
+		}
+		result := int64(1)
+		for base, exp := a, b; exp > 0; exp >>= 1 {
+			if exp&1 == 1 {
+				result *= base
+			}
+			base *= base
+		}
+		return int(result)
+	case "&":
+		return int(a & b)
+	case "|":
+		return int(a | b)
+	case "^":
+		return int(a ^ b)
+	case "<<":
+		if b < 0 {
+			Raise("ValueError", "negative shift count")
+		}
+		return int(a << uint(b))
+	case ">>":
+		if b < 0 {
+			Raise("ValueError", "negative shift count")
+		}
+		return int(a >> uint(b))
+	}
+	Raise("TypeError", "unsupported operand type(s) for %s: 'int' and 'int'", op)
+	return nil
+}
+
+// floatBinOp applies a Python operator to two floats
+func floatBinOp(op string, a, b float64) interface{} {
+	switch op {
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	case "/":
+		if b == 0 {
+			Raise("ZeroDivisionError", "float division by zero")
+		}
+		return a / b
+	case "//":
+		if b == 0 {
+			Raise("ZeroDivisionError", "float floor division by zero")
+		}
+		return math.Floor(a / b)
+	case "%":
+		if b == 0 {
+			Raise("ZeroDivisionError", "float modulo")
+		}
+		m := math.Mod(a, b)
+		if m != 0 && ((m < 0) != (b < 0)) {
+			m += b
+		}
+		return m
+	case "**":
+		return math.Pow(a, b)
+	}
+	Raise("TypeError", "unsupported operand type(s) for %s: 'float' and 'float'", op)
+	return nil
+}
+
+// AugAssign implements Python augmented assignment (target op= value) and returns
+// the value to rebind to the target. Mutable sequences are updated in place, so
+// `xs += ys` on a shared *PyList is visible through every reference, while
+// immutable values (int, float, str) produce a new value as in Python.
+//
+// Generated code: x = mgen.AugAssign("+=", x, y)
+func AugAssign(op string, target, value interface{}) interface{} {
+	base := strings.TrimSuffix(op, "=")
+	if list, ok := target.(*PyList); ok {
+		switch base {
+		case "+":
+			list.Extend(value)
+			return list
+		case "*":
+			n, ok := asInt(value)
+			if !ok {
+				Raise("TypeError", "can't multiply sequence by non-int of type '%s'", pyTypeName(value))
+			}
+			list.Items = RepeatSlice(list.Items, int(n))
+			return list
+		}
+	}
+	return BinOp(base, target, value)
+}
+
+// SumValues implements sum(iterable) for dynamically typed items using BinOp,
+// so bools count as 0/1 and mixed int/float sums promote to float
+func SumValues(iterable interface{}) interface{} {
+	var total interface{} = 0
+	for _, item := range iterValues(iterable) {
+		total = BinOp("+", total, item)
+	}
+	return total
+}
+
+// AbsValue implements abs(x) for a dynamically typed number; bools count as 0/1
+func AbsValue(x interface{}) interface{} {
+	switch v := x.(type) {
+	case float64:
+		return math.Abs(v)
+	case float32:
+		return math.Abs(float64(v))
+	}
+	if i, ok := asInt(x); ok {
+		if i < 0 {
+			i = -i
+		}
+		return int(i)
+	}
+	Raise("TypeError", "bad operand type for abs(): '%s'", pyTypeName(x))
+	return nil
+}
+
+// ExtendSlice appends src to the slice pointed to by dst (list += other for typed slices)
+func ExtendSlice[T any](dst *[]T, src []T) {
+	*dst = append(*dst, src...)
+}
+
+// RepeatSlice returns a new slice holding n copies of s (list * n)
+func RepeatSlice[T any](s []T, n int) []T {
+	if n <= 0 {
+		return []T{}
+	}
+	result := make([]T, 0, len(s)*n)
+	for i := 0; i < n; i++ {
+		result = append(result, s...)
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package mgen
+
+import "reflect"
+
+// Optional values
+//
+// Python's Optional[T] (or T | None) is generated as a Go pointer *T, with nil
+// standing for None. This keeps optional values typed instead of boxing them
+// into interface{}. Dynamically typed code still represents None as a nil
+// interface{}; IsNone understands both forms.
+
+// Some returns an Optional[T] holding v
+func Some[T any](v T) *T {
+	return &v
+}
+
+// IsNone reports whether x is None: a nil interface{} or a nil pointer
+func IsNone(x interface{}) bool {
+	if x == nil {
+		return true
+	}
+	v := reflect.ValueOf(x)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// Unwrap returns the value held by an Optional[T], raising TypeError for None
+func Unwrap[T any](opt *T) T {
+	if opt == nil {
+		Raise("TypeError", "unexpected None value (expected %s)", pyTypeName(*new(T)))
+	}
+	return *opt
+}
+
+// ValueOr returns the value held by an Optional[T], or def when it is None
+// (Python: `x if x is not None else def`)
+func ValueOr[T any](opt *T, def T) T {
+	if opt == nil {
+		return def
+	}
+	return *opt
+}
+
+// NoneIfNil boxes an Optional[T] for dynamically typed code: nil becomes the
+// None sentinel (a nil interface{}), anything else becomes the plain value
+func NoneIfNil[T any](opt *T) interface{} {
+	if opt == nil {
+		return nil
+	}
+	return *opt
+}
+
+// OptionalOf converts a boxed value (None sentinel or T) back to Optional[T]
+func OptionalOf[T any](x interface{}) *T {
+	if IsNone(x) {
+		return nil
+	}
+	if v, ok := x.(T); ok {
+		return &v
+	}
+	if p, ok := x.(*T); ok {
+		return p
+	}
+	Raise("TypeError", "expected %s or None, got '%s'", pyTypeName(*new(T)), pyTypeName(x))
+	return nil
+}
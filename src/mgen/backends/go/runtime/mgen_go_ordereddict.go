@@ -0,0 +1,65 @@
+package mgen
+
+// Ordered dicts
+//
+// OrderedDict is collections.OrderedDict. Like defaultdict and Counter it is
+// a Dict with a kind (see mgen_go_collections.go), so lookups, assignment and
+// iteration behave exactly like a dict, and it adds the reordering
+// operations LRU caches rely on. Two OrderedDicts are equal only when their
+// entries are in the same order; compared with a plain dict the order is
+// ignored, as in Python.
+
+// NewOrderedDict builds an OrderedDict from entries (OrderedDict([(k, v), ...]))
+func NewOrderedDict[K comparable, V any](entries ...KV[K, V]) *Dict[K, V] {
+	d := NewDict(entries...)
+	d.missing = &missingValues[V]{kind: "OrderedDict"}
+	return d
+}
+
+// MoveToEnd moves key to the end, or to the front when last is false
+// (od.move_to_end(key, last)), raising KeyError when the key is missing
+func (d *Dict[K, V]) MoveToEnd(key K, last bool) {
+	i, ok := d.find(key)
+	if !ok {
+		Raise("KeyError", "%s", Repr(key))
+	}
+	entry := d.entries[i]
+	if last {
+		copy(d.entries[i:], d.entries[i+1:])
+		d.entries[len(d.entries)-1] = entry
+		d.reindex(i)
+	} else {
+		copy(d.entries[1:i+1], d.entries[:i])
+		d.entries[0] = entry
+		d.reindex(0)
+	}
+}
+
+// PopItemEnd removes and returns the last entry, or the first when last is
+// false (od.popitem(last)), raising KeyError when the dict is empty
+func (d *Dict[K, V]) PopItemEnd(last bool) KV[K, V] {
+	if len(d.entries) == 0 {
+		Raise("KeyError", "'dictionary is empty'")
+	}
+	i := 0
+	if last {
+		i = len(d.entries) - 1
+	}
+	entry := d.entries[i]
+	d.remove(i)
+	return entry
+}
+
+// orderedEqual reports whether two OrderedDicts hold equal entries in the same order
+func orderedEqual(a, b dictLike) bool {
+	ae, be := a.pyEntries(), b.pyEntries()
+	if len(ae) != len(be) {
+		return false
+	}
+	for i, e := range ae {
+		if !Eq(e.Key, be[i].Key) || !Eq(e.Value, be[i].Value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,239 @@
+package mgen
+
+import (
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// Pretty printing
+//
+// PFormat follows pprint's layout rules. A value whose repr fits in the width
+// that is left on the line is written on one line. Otherwise, a list, tuple,
+// dict or set puts each item on its own line, indented one column past its
+// opening bracket, and lays out every item by the same rule. Long strings are
+// split into adjacent literals at word boundaries. As in pprint, dict keys and
+// set members are sorted, even in the one-line form.
+
+// PPrintOptions holds pprint.pformat()'s keyword arguments; zero values mean
+// the defaults (indent=1, width=80)
+type PPrintOptions struct {
+	Indent int
+	Width  int
+}
+
+// PFormat implements pprint.pformat(x, indent=..., width=...)
+func PFormat(x interface{}, opts PPrintOptions) string {
+	p := &pprinter{indent: opts.Indent, width: opts.Width}
+	if p.indent == 0 {
+		p.indent = 1
+	}
+	if p.width == 0 {
+		p.width = 80
+	}
+	p.format(x, 0, 0, 1)
+	return p.out.String()
+}
+
+// PPrint implements pprint.pprint(x, indent=..., width=...), writing to stdout
+func PPrint(x interface{}, opts PPrintOptions) {
+	Print(PFormat(x, opts))
+}
+
+// pprinter accumulates the output of one PFormat call
+type pprinter struct {
+	indent int // columns added per nesting level
+	width  int
+	out    strings.Builder
+}
+
+// pprintNode is a container broken down for layout: the items of a list,
+// tuple or set, or the keys and values of a dict
+type pprintNode struct {
+	open, close string
+	items       []interface{}
+	values      []interface{} // dict values; nil for other containers
+}
+
+// pprintContainer breaks a non-empty container into its delimiters and items,
+// reporting false for scalars and empty containers, which print as their repr
+func pprintContainer(x interface{}) (pprintNode, bool) {
+	switch v := x.(type) {
+	case *PyList:
+		return pprintNode{open: "[", close: "]", items: v.Items}, len(v.Items) > 0
+	case *PyDict:
+		keys := v.Keys()
+		sortValues(keys)
+		values := make([]interface{}, len(keys))
+		for i, k := range keys {
+			values[i] = v.Get(k)
+		}
+		return pprintNode{open: "{", close: "}", items: keys, values: values}, len(keys) > 0
+	case setLike:
+		items := v.pyItems()
+		sortValues(items)
+		return pprintNode{open: "{", close: "}", items: items}, len(items) > 0
+	case dictLike:
+		if v.kind() == "OrderedDict" {
+			// An OrderedDict prints as its repr, a list of (key, value) tuples
+			return pprintNode{}, false
+		}
+		keys := iterValues(v)
+		sortValues(keys)
+		values := make([]interface{}, len(keys))
+		for i, k := range keys {
+			values[i], _ = v.pyLookup(k)
+		}
+		return pprintNode{open: "{", close: "}", items: keys, values: values}, len(keys) > 0
+	case tupleLike:
+		return pprintNode{open: "(", close: ")", items: v.tupleItems()}, true
+	case PyTuple:
+		// A one-item tuple keeps the trailing comma of its repr: (1,)
+		return pprintNode{open: "(", close: ")", items: v}, len(v) > 1
+	case string, PyBytes:
+		return pprintNode{}, false
+	}
+	rv := reflect.ValueOf(x)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return pprintNode{open: "[", close: "]", items: iterValues(x)}, rv.Len() > 0
+	case reflect.Map:
+		keys := iterValues(x) // sorted
+		if rv.Type().Elem().Kind() == reflect.Bool {
+			return pprintNode{open: "{", close: "}", items: keys}, len(keys) > 0
+		}
+		values := make([]interface{}, len(keys))
+		for i, k := range keys {
+			values[i] = rv.MapIndex(reflect.ValueOf(k)).Interface()
+		}
+		return pprintNode{open: "{", close: "}", items: keys, values: values}, len(keys) > 0
+	}
+	return pprintNode{}, false
+}
+
+// flat renders x on one line: its repr, with dict keys and set members sorted
+func (p *pprinter) flat(x interface{}) string {
+	node, ok := pprintContainer(x)
+	if !ok {
+		return Repr(x)
+	}
+	parts := make([]string, len(node.items))
+	for i, item := range node.items {
+		parts[i] = p.flat(item)
+		if node.values != nil {
+			parts[i] += ": " + p.flat(node.values[i])
+		}
+	}
+	return node.open + strings.Join(parts, ", ") + node.close
+}
+
+// format writes x starting at column indent, leaving allowance columns free
+// for the closing brackets that follow it; level is the nesting depth
+func (p *pprinter) format(x interface{}, indent, allowance, level int) {
+	rep := p.flat(x)
+	if utf8.RuneCountInString(rep) <= p.width-indent-allowance {
+		p.out.WriteString(rep)
+		return
+	}
+	if s, ok := x.(string); ok {
+		p.formatString(s, rep, indent, allowance, level)
+		return
+	}
+	node, ok := pprintContainer(x)
+	if !ok {
+		p.out.WriteString(rep)
+		return
+	}
+	p.out.WriteString(node.open + strings.Repeat(" ", p.indent-1))
+	indent += p.indent
+	allowance += len(node.close)
+	for i, item := range node.items {
+		itemAllowance := 1 // the comma after the item
+		if i == len(node.items)-1 {
+			itemAllowance = allowance
+		}
+		if i > 0 {
+			p.out.WriteString(",\n" + strings.Repeat(" ", indent))
+		}
+		if node.values == nil {
+			p.format(item, indent, itemAllowance, level+1)
+			continue
+		}
+		key := p.flat(item)
+		p.out.WriteString(key + ": ")
+		p.format(node.values[i], indent+utf8.RuneCountInString(key)+2, itemAllowance, level+1)
+	}
+	p.out.WriteString(node.close)
+}
+
+// formatString splits a string that does not fit into adjacent literals, one
+// per line, breaking after whitespace; at the top level they are parenthesized
+func (p *pprinter) formatString(s, rep string, indent, allowance, level int) {
+	if level == 1 {
+		indent++
+		allowance++
+	}
+	var chunks []string
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for i, line := range lines {
+		maxWidth := p.width - indent
+		if i == len(lines)-1 {
+			maxWidth -= allowance
+		}
+		if utf8.RuneCountInString(Repr(line)) <= maxWidth {
+			chunks = append(chunks, Repr(line))
+			continue
+		}
+		parts := splitWords(line)
+		current := ""
+		for j, part := range parts {
+			candidate := current + part
+			limit := p.width - indent
+			if j == len(parts)-1 && i == len(lines)-1 {
+				limit -= allowance
+			}
+			if utf8.RuneCountInString(Repr(candidate)) > limit {
+				if current != "" {
+					chunks = append(chunks, Repr(current))
+				}
+				current = part
+			} else {
+				current = candidate
+			}
+		}
+		if current != "" {
+			chunks = append(chunks, Repr(current))
+		}
+	}
+	if len(chunks) == 1 {
+		p.out.WriteString(rep)
+		return
+	}
+	if level == 1 {
+		p.out.WriteString("(")
+	}
+	p.out.WriteString(strings.Join(chunks, "\n"+strings.Repeat(" ", indent)))
+	if level == 1 {
+		p.out.WriteString(")")
+	}
+}
+
+// splitWords splits s into words that each keep their trailing whitespace,
+// so the parts join back into s
+func splitWords(s string) []string {
+	var parts []string
+	start := 0
+	inSpace := false
+	for i, r := range s {
+		if isPySpace(r) {
+			inSpace = true
+		} else if inSpace {
+			parts = append(parts, s[start:i])
+			start, inSpace = i, false
+		}
+	}
+	return append(parts, s[start:])
+}
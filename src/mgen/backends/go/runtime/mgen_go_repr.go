@@ -0,0 +1,201 @@
+package mgen
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Repr returns the Python repr() of a value: strings are quoted and escaped,
+// floats use the shortest round-trip form, and containers render their items
+// with repr ([1, 'a'], {'k': 2.0}). Native Go maps have no insertion order,
+// so their keys are listed in sorted order; sets print as {1, 2} or set().
+// Dict, Set, PyDict and PySet keep insertion order at every nesting depth, and a
+// container that contains itself prints as [...] or {...} like Python.
+func Repr(x interface{}) string {
+	return reprValue(x, nil)
+}
+
+// PyRepresentable is implemented by classes with a Python __repr__ method
+type PyRepresentable interface {
+	PyRepr() string
+}
+
+// reprRef identifies a container whose repr is in progress; slices are
+// identified by their backing array and length
+type reprRef struct {
+	ptr uintptr
+	n   int
+}
+
+// containerRef returns the identity of a mutable container and the
+// placeholder Python prints when it is reached again while being rendered
+func containerRef(x interface{}) (reprRef, string, bool) {
+	switch v := x.(type) {
+	case *PyList:
+		return reprRef{ptr: reflect.ValueOf(v).Pointer()}, "[...]", v != nil
+	case *PyDict:
+		return reprRef{ptr: reflect.ValueOf(v).Pointer()}, "{...}", v != nil
+	case dictLike:
+		if v.kind() == "OrderedDict" {
+			return reprRef{ptr: reflect.ValueOf(v).Pointer()}, "...", !reflect.ValueOf(v).IsNil()
+		}
+		return reprRef{ptr: reflect.ValueOf(v).Pointer()}, "{...}", !reflect.ValueOf(v).IsNil()
+	case PyBytes, PyTuple:
+		return reprRef{}, "", false
+	}
+	rv := reflect.ValueOf(x)
+	switch rv.Kind() {
+	case reflect.Map:
+		return reprRef{ptr: rv.Pointer()}, "{...}", !rv.IsNil()
+	case reflect.Slice:
+		return reprRef{ptr: rv.Pointer(), n: rv.Len()}, "[...]", rv.Len() > 0
+	}
+	return reprRef{}, "", false
+}
+
+// reprValue renders x, with active holding the containers being rendered
+// further up so that cycles terminate
+func reprValue(x interface{}, active map[reprRef]bool) string {
+	if ref, placeholder, ok := containerRef(x); ok {
+		if active[ref] {
+			return placeholder
+		}
+		if active == nil {
+			active = map[reprRef]bool{}
+		}
+		active[ref] = true
+		defer delete(active, ref)
+	}
+
+	switch v := x.(type) {
+	case nil:
+		return "None"
+	case string:
+		return pyQuote(v)
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return ToStr(v)
+	case float32:
+		return FloatRepr(float64(v))
+	case float64:
+		// Container items recurse through here, so [1.0, 2.5] never falls back to %v's [1 2.5]
+		return FloatRepr(v)
+	case *PyList:
+		return reprItems("[", v.Items, "]", active)
+	case PyTuple:
+		if len(v) == 1 {
+			return "(" + reprValue(v[0], active) + ",)"
+		}
+		return reprItems("(", v, ")", active)
+	case *PyDict:
+		parts := make([]string, len(v.entries))
+		for i, e := range v.entries {
+			parts[i] = reprKey(e.Key) + ": " + reprValue(e.Value, active)
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case setLike:
+		if v.Len() == 0 {
+			return "set()"
+		}
+		parts := make([]string, 0, v.Len())
+		for _, item := range v.pyItems() {
+			parts = append(parts, reprKey(item))
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case dictLike:
+		entries := v.pyEntries()
+		if v.kind() == "OrderedDict" {
+			// An OrderedDict lists its items as (key, value) tuples
+			if len(entries) == 0 {
+				return "OrderedDict()"
+			}
+			items := make([]interface{}, len(entries))
+			for i, e := range entries {
+				items[i] = e
+			}
+			return "OrderedDict(" + reprItems("[", items, "]", active) + ")"
+		}
+		if v.kind() == "Counter" {
+			// A Counter lists its most common items first
+			sort.SliceStable(entries, func(i, j int) bool { return Compare(">", entries[i].Value, entries[j].Value) })
+		}
+		parts := make([]string, len(entries))
+		for i, e := range entries {
+			parts[i] = reprValue(e.Key, active) + ": " + reprValue(e.Value, active)
+		}
+		return v.wrapRepr("{" + strings.Join(parts, ", ") + "}")
+	case PyDictEntry:
+		return "(" + reprKey(v.Key) + ", " + reprValue(v.Value, active) + ")"
+	case PyBytes:
+		return v.String()
+	case *PyByteArray:
+		return v.String()
+	case *PyInt:
+		return v.String()
+	}
+	if r, ok := asProtocol[PyRepresentable](x); ok {
+		return r.PyRepr()
+	}
+	if pyExc, ok := asException(x); ok {
+		exc := pyExc.PyErr()
+		if exc.Args == nil && exc.Message != "" {
+			return exc.Type + "(" + pyQuote(exc.Message) + ")"
+		}
+		return exc.Type + reprItems("(", exc.Args, ")", active)
+	}
+
+	rv := reflect.ValueOf(x)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return reprItems("[", iterValues(x), "]", active)
+	case reflect.Map:
+		keys := iterValues(x)
+		if rv.Type().Elem().Kind() == reflect.Bool {
+			if len(keys) == 0 {
+				return "set()"
+			}
+			return reprItems("{", keys, "}", active)
+		}
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = reprValue(k, active) + ": " + reprValue(rv.MapIndex(reflect.ValueOf(k)).Interface(), active)
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return "None"
+		}
+	}
+	return fmt.Sprintf("%v", x)
+}
+
+// reprItems joins the reprs of items between open and close delimiters
+func reprItems(open string, items []interface{}, close string, active map[reprRef]bool) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = reprValue(item, active)
+	}
+	return open + strings.Join(parts, ", ") + close
+}
+
+// Ascii implements Python's ascii(): the repr with every non-ASCII character
+// escaped as \xNN, \uNNNN or \UNNNNNNNN (astral characters use a single
+// \U escape, never a surrogate pair)
+func Ascii(x interface{}) string {
+	repr := Repr(x)
+	var b strings.Builder
+	for _, r := range repr {
+		switch {
+		case r < 0x80:
+			b.WriteRune(r)
+		case r <= 0xff:
+			fmt.Fprintf(&b, "\\x%02x", r)
+		case r <= 0xffff:
+			fmt.Fprintf(&b, "\\u%04x", r)
+		default:
+			fmt.Fprintf(&b, "\\U%08x", r)
+		}
+	}
+	return b.String()
+}
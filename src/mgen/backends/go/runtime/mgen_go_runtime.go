@@ -6,7 +6,10 @@ package mgen
 import (
 	"fmt"
 	"math"
+	"reflect"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Comparable is a constraint for comparable types
@@ -51,10 +54,15 @@ func (s StringOps) StripChars(str, chars string) string {
 	return strings.Trim(str, chars)
 }
 
-// Find returns the index of the first occurrence of substr in str, or -1 if not found
+// Find returns the code point index of the first occurrence of substr in str,
+// or -1 if not found
 func (s StringOps) Find(str, substr string) int {
-	index := strings.Index(str, substr)
-	return index
+	return s.FindRange(str, substr)
+}
+
+// Contains reports whether substr occurs in str
+func (s StringOps) Contains(str, substr string) bool {
+	return strings.Contains(str, substr)
 }
 
 // Replace replaces all occurrences of old with new in str
@@ -94,18 +102,15 @@ func Len[T any](x []T) int {
 	return len(x)
 }
 
-func LenMap[K comparable, V any](x map[K]V) int {
-	return len(x)
-}
-
+// LenString implements len(str), counting code points rather than bytes
 func LenString(x string) int {
-	return len(x)
+	return utf8.RuneCountInString(x)
 }
 
 // Min returns minimum value from slice
 func Min[T Ordered](slice []T) T {
 	if len(slice) == 0 {
-		panic("min() requires non-empty slice")
+		Raise("ValueError", "min() arg is an empty sequence")
 	}
 	min := slice[0]
 	for _, item := range slice[1:] {
@@ -119,7 +124,7 @@ func Min[T Ordered](slice []T) T {
 // Max returns maximum value from slice
 func Max[T Ordered](slice []T) T {
 	if len(slice) == 0 {
-		panic("max() requires non-empty slice")
+		Raise("ValueError", "max() arg is an empty sequence")
 	}
 	max := slice[0]
 	for _, item := range slice[1:] {
@@ -139,6 +144,25 @@ func Sum[T Numeric](slice []T) T {
 	return total
 }
 
+// BoolToInt converts a bool to 1 or 0 for arithmetic (Python bool is an int)
+func BoolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// SumBool returns the number of true values (sum() of a bool slice)
+func SumBool(slice []bool) int {
+	total := 0
+	for _, v := range slice {
+		if v {
+			total++
+		}
+	}
+	return total
+}
+
 // Any returns true if any element in the slice is true
 func Any(slice []bool) bool {
 	for _, v := range slice {
@@ -205,6 +229,32 @@ func (r Range) ToSlice() []int {
 	return result
 }
 
+// Len returns the number of values in the range without materializing it
+func (r Range) Len() int {
+	switch {
+	case r.Step == 0:
+		panic("range() step cannot be zero")
+	case r.Step > 0 && r.Start < r.Stop:
+		return (r.Stop - r.Start + r.Step - 1) / r.Step
+	case r.Step < 0 && r.Start > r.Stop:
+		return (r.Start - r.Stop - r.Step - 1) / -r.Step
+	}
+	return 0
+}
+
+// Contains reports whether x is one of the range's values without iterating it
+func (r Range) Contains(x int) bool {
+	switch {
+	case r.Step == 0:
+		panic("range() step cannot be zero")
+	case r.Step > 0 && (x < r.Start || x >= r.Stop):
+		return false
+	case r.Step < 0 && (x > r.Start || x <= r.Stop):
+		return false
+	}
+	return (x-r.Start)%r.Step == 0
+}
+
 // ForEach executes function for each value in range
 func (r Range) ForEach(fn func(int)) {
 	if r.Step == 0 {
@@ -264,22 +314,35 @@ func ListComprehensionFromRangeWithFilter[R any](source Range, transform func(in
 	return result
 }
 
-// DictComprehension creates map by applying transform function
-func DictComprehension[T any, K comparable, V any](source []T, transform func(T) (K, V)) map[K]V {
-	result := make(map[K]V)
+// DictComprehension builds a Dict by applying transform to each item; later
+// duplicate keys overwrite earlier values but keep the first position
+func DictComprehension[T any, K comparable, V any](source []T, transform func(T) (K, V)) *Dict[K, V] {
+	result := NewDict[K, V]()
 	for _, item := range source {
 		k, v := transform(item)
-		result[k] = v
+		result.Set(k, v)
 	}
 	return result
 }
 
-// DictComprehensionFromRange creates map from a Range
-func DictComprehensionFromRange[K comparable, V any](source Range, transform func(int) (K, V)) map[K]V {
-	result := make(map[K]V)
+// DictComprehensionFromRange builds a Dict from a Range
+func DictComprehensionFromRange[K comparable, V any](source Range, transform func(int) (K, V)) *Dict[K, V] {
+	result := NewDict[K, V]()
 	source.ForEach(func(i int) {
 		k, v := transform(i)
-		result[k] = v
+		result.Set(k, v)
+	})
+	return result
+}
+
+// DictComprehensionFromRangeWithFilter builds a filtered Dict from a Range
+func DictComprehensionFromRangeWithFilter[K comparable, V any](source Range, transform func(int) (K, V), filter func(int) bool) *Dict[K, V] {
+	result := NewDict[K, V]()
+	source.ForEach(func(i int) {
+		if filter(i) {
+			k, v := transform(i)
+			result.Set(k, v)
+		}
 	})
 	return result
 }
@@ -290,71 +353,65 @@ type KV[K comparable, V any] struct {
 	Value V
 }
 
-// MapItems converts a map to a slice of key-value pairs
-func MapItems[K comparable, V any](m map[K]V) []KV[K, V] {
-	result := make([]KV[K, V], 0, len(m))
-	for k, v := range m {
-		result = append(result, KV[K, V]{Key: k, Value: v})
-	}
-	return result
+// String renders the entry like the tuple dict.items() yields: ('a', 1)
+func (kv KV[K, V]) String() string {
+	return "(" + Repr(kv.Key) + ", " + Repr(kv.Value) + ")"
 }
 
-// MapValues returns a slice of all values from a map
-func MapValues[K comparable, V any](m map[K]V) []V {
-	result := make([]V, 0, len(m))
-	for _, v := range m {
-		result = append(result, v)
-	}
-	return result
+// tupleItems returns the key and value as the items of a two-element tuple
+func (kv KV[K, V]) tupleItems() []interface{} {
+	return []interface{}{kv.Key, kv.Value}
 }
 
-// DictComprehensionWithFilter creates map with filtering
-func DictComprehensionWithFilter[T any, K comparable, V any](source []T, transform func(T) (K, V), filter func(T) bool) map[K]V {
-	result := make(map[K]V)
+// DictComprehensionWithFilter builds a Dict from the items that pass filter
+func DictComprehensionWithFilter[T any, K comparable, V any](source []T, transform func(T) (K, V), filter func(T) bool) *Dict[K, V] {
+	result := NewDict[K, V]()
 	for _, item := range source {
 		if filter(item) {
 			k, v := transform(item)
-			result[k] = v
+			result.Set(k, v)
 		}
 	}
 	return result
 }
 
-// SetComprehension creates map[T]bool set by applying transform function
-func SetComprehension[T any, K comparable](source []T, transform func(T) K) map[K]bool {
-	result := make(map[K]bool)
+// SetComprehension builds a Set by applying transform to each item
+func SetComprehension[T any, K comparable](source []T, transform func(T) K) *Set[K] {
+	result := NewSet[K]()
 	for _, item := range source {
-		result[transform(item)] = true
+		result.Add(transform(item))
 	}
 	return result
 }
 
-// SetComprehensionFromRange creates set from a Range
-func SetComprehensionFromRange[K comparable](source Range, transform func(int) K) map[K]bool {
-	result := make(map[K]bool)
+// SetComprehensionFromRange builds a Set from a Range
+func SetComprehensionFromRange[K comparable](source Range, transform func(int) K) *Set[K] {
+	result := NewSet[K]()
 	source.ForEach(func(i int) {
-		result[transform(i)] = true
+		result.Add(transform(i))
 	})
 	return result
 }
 
-// SetComprehensionFromSet creates a new set by applying transform to elements of an existing set
-func SetComprehensionFromSet[T comparable, K comparable](source map[T]bool, transform func(T) K) map[K]bool {
-	result := make(map[K]bool)
-	for key := range source {
-		result[transform(key)] = true
+// SetComprehensionWithFilter builds a Set from the items that pass filter
+func SetComprehensionWithFilter[T any, K comparable](source []T, transform func(T) K, filter func(T) bool) *Set[K] {
+	result := NewSet[K]()
+	for _, item := range source {
+		if filter(item) {
+			result.Add(transform(item))
+		}
 	}
 	return result
 }
 
-// SetComprehensionFromSetWithFilter creates a new set by applying transform to filtered elements
-func SetComprehensionFromSetWithFilter[T comparable, K comparable](source map[T]bool, filter func(T) bool, transform func(T) K) map[K]bool {
-	result := make(map[K]bool)
-	for key := range source {
-		if filter(key) {
-			result[transform(key)] = true
+// SetComprehensionFromRangeWithFilter builds a Set from the Range values that pass filter
+func SetComprehensionFromRangeWithFilter[K comparable](source Range, transform func(int) K, filter func(int) bool) *Set[K] {
+	result := NewSet[K]()
+	source.ForEach(func(i int) {
+		if filter(i) {
+			result.Add(transform(i))
 		}
-	}
+	})
 	return result
 }
 
@@ -380,20 +437,37 @@ func ToStr(x interface{}) string {
 			return "True"
 		}
 		return "False"
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+	case int:
+		return strconv.Itoa(v)
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
 		return fmt.Sprintf("%d", v)
-	case float32, float64:
-		return fmt.Sprintf("%g", v)
+	case float32:
+		return FloatRepr(float64(v))
+	case float64:
+		return FloatRepr(v)
 	default:
+		if exc, ok := asException(v); ok {
+			// str(exc) is the message, without the "Type: " prefix of Error()
+			return exc.PyErr().Message
+		}
+		if _, ok := v.(fmt.Stringer); !ok && isContainerKind(v) {
+			// str() of a list, map or set is its repr: ['a', 1.0], not %v's [a 1]
+			return Repr(v)
+		}
 		return fmt.Sprintf("%v", v)
 	}
 }
 
-// Print provides Python-like print function
-func Print(args ...interface{}) {
-	strs := make([]string, len(args))
-	for i, arg := range args {
-		strs[i] = ToStr(arg)
+// isContainerKind reports whether x is a Go slice, array or map
+func isContainerKind(x interface{}) bool {
+	switch reflect.ValueOf(x).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
 	}
-	fmt.Println(strings.Join(strs, " "))
-}
\ No newline at end of file
+	return false
+}
+
+// Print provides Python-like print function, writing to the current stdout (see SetStdout)
+func Print(args ...interface{}) {
+	PrintOpts(PrintOptions{Sep: " ", End: "\n"}, args...)
+}
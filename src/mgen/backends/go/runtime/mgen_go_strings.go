@@ -0,0 +1,563 @@
+package mgen
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FindAll returns the start index of every non-overlapping occurrence of substr
+// in str, scanning left to right like repeated str.find(substr, end_of_match).
+// Indices count code points. An empty substr matches at every character
+// boundary, including the end of the string.
+func (s StringOps) FindAll(str, substr string) []int {
+	offsets := findAllOffsets(str, substr)
+	result := make([]int, len(offsets))
+	index, previous := 0, 0
+	for i, offset := range offsets {
+		index += utf8.RuneCountInString(str[previous:offset])
+		result[i], previous = index, offset
+	}
+	return result
+}
+
+// findAllOffsets is FindAll in byte offsets, for callers that slice str
+func findAllOffsets(str, substr string) []int {
+	result := []int{}
+	if substr == "" {
+		for i := range str {
+			result = append(result, i)
+		}
+		return append(result, len(str))
+	}
+	for start := 0; start <= len(str)-len(substr); {
+		index := strings.Index(str[start:], substr)
+		if index < 0 {
+			break
+		}
+		result = append(result, start+index)
+		start += index + len(substr)
+	}
+	return result
+}
+
+// SplitKeepSep splits str on sep but keeps each separator as its own element,
+// like re.split with a capturing group: "a,b" -> ["a", ",", "b"]
+func (s StringOps) SplitKeepSep(str, sep string) []string {
+	if sep == "" {
+		Raise("ValueError", "empty separator")
+	}
+	result := []string{}
+	start := 0
+	for _, index := range findAllOffsets(str, sep) {
+		result = append(result, str[start:index], sep)
+		start = index + len(sep)
+	}
+	return append(result, str[start:])
+}
+
+// Searching
+//
+// FindRange, RFind, Index and RIndex take str.find's optional start and end
+// as trailing arguments and resolve them like slice bounds: negative values
+// count from the end and out-of-range values are clamped. Indices count code
+// points. An empty substring is found at the start of the window, or at its
+// end for RFind and RIndex, unless start is past end: "ab".find("", 3) is -1,
+// as in Python.
+
+// FindRange implements str.find(sub, start, end), returning -1 when sub does
+// not occur in str[start:end]
+func (s StringOps) FindRange(str, sub string, bounds ...int) int {
+	return searchString(str, sub, bounds, strings.Index)
+}
+
+// RFind implements str.rfind(sub, start, end), returning the highest index
+// or -1
+func (s StringOps) RFind(str, sub string, bounds ...int) int {
+	return searchString(str, sub, bounds, strings.LastIndex)
+}
+
+// Index implements str.index(sub, start, end), raising ValueError on a miss
+func (s StringOps) Index(str, sub string, bounds ...int) int {
+	index := s.FindRange(str, sub, bounds...)
+	if index < 0 {
+		Raise("ValueError", "substring not found")
+	}
+	return index
+}
+
+// RIndex implements str.rindex(sub, start, end), raising ValueError on a miss
+func (s StringOps) RIndex(str, sub string, bounds ...int) int {
+	index := s.RFind(str, sub, bounds...)
+	if index < 0 {
+		Raise("ValueError", "substring not found")
+	}
+	return index
+}
+
+// searchString runs a byte-offset search over the window str[start:end] and
+// converts the match back to a code point index
+func searchString(str, sub string, bounds []int, search func(string, string) int) int {
+	start, end := searchWindow(utf8.RuneCountInString(str), bounds)
+	if start > end {
+		return -1
+	}
+	lo, hi := runeOffset(str, start), runeOffset(str, end)
+	window := str[lo:hi]
+	index := search(window, sub)
+	if index < 0 {
+		return -1
+	}
+	return start + utf8.RuneCountInString(window[:index])
+}
+
+// searchWindow resolves the optional start and end of a search over n code
+// points. Unlike a slice, start is not clamped to n, so a start past the end
+// leaves an inverted window in which nothing, not even "", is found.
+func searchWindow(n int, bounds []int) (start, end int) {
+	end = n
+	if len(bounds) > 0 {
+		start = bounds[0]
+	}
+	if len(bounds) > 1 {
+		end = bounds[1]
+	}
+	if end > n {
+		end = n
+	} else if end < 0 {
+		end = max(end+n, 0)
+	}
+	if start < 0 {
+		start = max(start+n, 0)
+	}
+	return start, end
+}
+
+// runeOffset returns the byte offset of code point i in str, or len(str)
+// when i is past the last one
+func runeOffset(str string, i int) int {
+	for offset := range str {
+		if i == 0 {
+			return offset
+		}
+		i--
+	}
+	return len(str)
+}
+
+// Count implements str.count(sub, start, end): the number of non-overlapping
+// occurrences in str[start:end]. An empty sub matches at every boundary, so
+// "abc".count("") is 4.
+func (s StringOps) Count(str, sub string, bounds ...int) int {
+	start, end := searchWindow(utf8.RuneCountInString(str), bounds)
+	if start > end {
+		return 0
+	}
+	return strings.Count(str[runeOffset(str, start):runeOffset(str, end)], sub)
+}
+
+// StartsWith implements str.startswith(prefix); a tuple of prefixes is passed
+// as several arguments and any one of them may match
+func (s StringOps) StartsWith(str string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(str, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// EndsWith implements str.endswith(suffix), taking a tuple of suffixes like StartsWith
+func (s StringOps) EndsWith(str string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(str, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Partition implements str.partition(sep) as a list [before, sep, after];
+// when sep does not occur the result is [str, "", ""]
+func (s StringOps) Partition(str, sep string) []string {
+	if sep == "" {
+		Raise("ValueError", "empty separator")
+	}
+	if before, after, found := strings.Cut(str, sep); found {
+		return []string{before, sep, after}
+	}
+	return []string{str, "", ""}
+}
+
+// RPartition implements str.rpartition(sep), splitting at the last occurrence;
+// when sep does not occur the result is ["", "", str]
+func (s StringOps) RPartition(str, sep string) []string {
+	if sep == "" {
+		Raise("ValueError", "empty separator")
+	}
+	if i := strings.LastIndex(str, sep); i >= 0 {
+		return []string{str[:i], sep, str[i+len(sep):]}
+	}
+	return []string{"", "", str}
+}
+
+// Chars returns the characters of str as one-character strings (list(str))
+func (s StringOps) Chars(str string) []string {
+	result := make([]string, 0, utf8.RuneCountInString(str))
+	for _, r := range str {
+		result = append(result, string(r))
+	}
+	return result
+}
+
+// ByteChars returns the bytes of str as one-byte strings, the characters of
+// str under the byte-based string semantics
+func (s StringOps) ByteChars(str string) []string {
+	result := make([]string, len(str))
+	for i := range result {
+		result[i] = str[i : i+1]
+	}
+	return result
+}
+
+// StrAt implements str[i] counting code points, where a negative i counts
+// from the end and an index outside the string raises IndexError. An index
+// within a leading run of ASCII is found without counting the whole string.
+func StrAt(str string, i int) string {
+	for j := 0; i >= 0 && j < len(str) && str[j] < utf8.RuneSelf; j++ {
+		if j == i {
+			return str[j : j+1]
+		}
+	}
+	n := utf8.RuneCountInString(str)
+	if i < 0 {
+		i += n
+	}
+	if i < 0 || i >= n {
+		Raise("IndexError", "string index out of range")
+	}
+	for _, r := range str {
+		if i == 0 {
+			return string(r)
+		}
+		i--
+	}
+	return ""
+}
+
+// StrByteAt implements str[i] counting bytes, for strings known to be ASCII
+func StrByteAt(str string, i int) string {
+	if i < 0 {
+		i += len(str)
+	}
+	if i < 0 || i >= len(str) {
+		Raise("IndexError", "string index out of range")
+	}
+	return str[i : i+1]
+}
+
+// Ord implements ord(c), the code point of a one-character string
+func Ord(c string) int {
+	r, size := utf8.DecodeRuneInString(c)
+	if size == 0 || size != len(c) {
+		Raise("TypeError", "ord() expected a character, but string of length %d found", utf8.RuneCountInString(c))
+	}
+	return int(r)
+}
+
+// Chr implements chr(i), the one-character string of code point i
+func Chr(i int) string {
+	if i < 0 || i > unicode.MaxRune {
+		Raise("ValueError", "chr() arg not in range(0x110000)")
+	}
+	return string(rune(i))
+}
+
+// Join implements sep.join(items)
+func (s StringOps) Join(sep string, items []string) string {
+	return strings.Join(items, sep)
+}
+
+// JoinValues implements sep.join(items) for dynamically typed items, raising
+// TypeError like Python when one of them is not a str
+func (s StringOps) JoinValues(sep string, items interface{}) string {
+	values := iterValues(items)
+	parts := make([]string, len(values))
+	for i, value := range values {
+		part, ok := value.(string)
+		if !ok {
+			Raise("TypeError", "sequence item %d: expected str instance, %s found", i, pyTypeName(value))
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, sep)
+}
+
+// Case conversion and character classes
+//
+// Python's "cased" characters are the uppercase, lowercase and titlecase
+// letters. Title and Capitalize titlecase rather than uppercase, which only
+// differs for digraphs such as "ǆ". The predicates are false for "", and
+// IsDigit accepts decimal digits plus the superscript and subscript digits.
+
+// Title implements str.title(): each character that follows a cased one is
+// lowercased and any other is titlecased, so "they're" becomes "They'Re"
+func (s StringOps) Title(str string) string {
+	var b strings.Builder
+	previousCased := false
+	for _, r := range str {
+		if previousCased {
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(unicode.ToTitle(r))
+		}
+		previousCased = isCased(r)
+	}
+	return b.String()
+}
+
+// Capitalize implements str.capitalize(): the first character titlecased and
+// the rest lowercased
+func (s StringOps) Capitalize(str string) string {
+	r, size := utf8.DecodeRuneInString(str)
+	if size == 0 {
+		return str
+	}
+	return string(unicode.ToTitle(r)) + strings.ToLower(str[size:])
+}
+
+// Zfill implements str.zfill(width), padding with zeros after any leading
+// sign until str is width code points long
+func (s StringOps) Zfill(str string, width int) string {
+	fill := width - utf8.RuneCountInString(str)
+	if fill <= 0 {
+		return str
+	}
+	zeros := strings.Repeat("0", fill)
+	if strings.HasPrefix(str, "+") || strings.HasPrefix(str, "-") {
+		return str[:1] + zeros + str[1:]
+	}
+	return zeros + str
+}
+
+// IsDigit implements str.isdigit()
+func (s StringOps) IsDigit(str string) bool {
+	return allRunes(str, isPyDigit)
+}
+
+// IsAlpha implements str.isalpha()
+func (s StringOps) IsAlpha(str string) bool {
+	return allRunes(str, unicode.IsLetter)
+}
+
+// IsAlnum implements str.isalnum(): letters and numeric characters
+func (s StringOps) IsAlnum(str string) bool {
+	return allRunes(str, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsNumber(r) })
+}
+
+// IsSpace implements str.isspace()
+func (s StringOps) IsSpace(str string) bool {
+	return allRunes(str, isPySpace)
+}
+
+// IsUpper implements str.isupper(): there is a cased character and none is
+// lowercase or titlecase
+func (s StringOps) IsUpper(str string) bool {
+	return strings.IndexFunc(str, unicode.IsUpper) >= 0 &&
+		strings.IndexFunc(str, func(r rune) bool { return unicode.IsLower(r) || unicode.IsTitle(r) }) < 0
+}
+
+// IsLower implements str.islower(): there is a cased character and none is
+// uppercase or titlecase
+func (s StringOps) IsLower(str string) bool {
+	return strings.IndexFunc(str, unicode.IsLower) >= 0 &&
+		strings.IndexFunc(str, func(r rune) bool { return unicode.IsUpper(r) || unicode.IsTitle(r) }) < 0
+}
+
+// allRunes reports whether str is non-empty and every character satisfies ok
+func allRunes(str string, ok func(rune) bool) bool {
+	return str != "" && strings.IndexFunc(str, func(r rune) bool { return !ok(r) }) < 0
+}
+
+// isCased reports whether r is an uppercase, lowercase or titlecase letter
+func isCased(r rune) bool {
+	return unicode.IsUpper(r) || unicode.IsLower(r) || unicode.IsTitle(r)
+}
+
+// isPyDigit reports whether r is a decimal digit or a superscript or subscript digit
+func isPyDigit(r rune) bool {
+	switch {
+	case unicode.IsDigit(r), r == '²', r == '³', r == '¹', r == '⁰':
+		return true
+	}
+	return r >= '⁴' && r <= '⁹' || r >= '₀' && r <= '₉'
+}
+
+// ExpandTabs replaces each tab with spaces up to the next multiple of tabsize
+// columns (Python str.expandtabs). The column counts code points and restarts
+// after "\n" or "\r"; a tabsize of zero or less removes tabs.
+func (s StringOps) ExpandTabs(str string, tabsize int) string {
+	var b strings.Builder
+	column := 0
+	for _, r := range str {
+		switch r {
+		case '\t':
+			if tabsize > 0 {
+				spaces := tabsize - column%tabsize
+				b.WriteString(strings.Repeat(" ", spaces))
+				column += spaces
+			}
+		case '\n', '\r':
+			b.WriteRune(r)
+			column = 0
+		default:
+			b.WriteRune(r)
+			column++
+		}
+	}
+	return b.String()
+}
+
+// Padding widths
+//
+// Python measures str.ljust/rjust/center widths in code points, so "日本".ljust(4)
+// adds two fill characters even though the result occupies six terminal columns.
+// Ljust/Rjust/Center follow Python exactly. The *Display variants measure the
+// terminal display width instead (East Asian wide characters and emoji count as
+// two columns, combining marks as zero) for callers that need visual alignment.
+
+// Ljust left-justifies str in a field of width code points (Python str.ljust)
+func (s StringOps) Ljust(str string, width int, fill string) string {
+	left, right := padCounts(width-utf8.RuneCountInString(str), fill, 'l', width)
+	return strings.Repeat(fill, left) + str + strings.Repeat(fill, right)
+}
+
+// Rjust right-justifies str in a field of width code points (Python str.rjust)
+func (s StringOps) Rjust(str string, width int, fill string) string {
+	left, right := padCounts(width-utf8.RuneCountInString(str), fill, 'r', width)
+	return strings.Repeat(fill, left) + str + strings.Repeat(fill, right)
+}
+
+// Center centers str in a field of width code points (Python str.center)
+func (s StringOps) Center(str string, width int, fill string) string {
+	left, right := padCounts(width-utf8.RuneCountInString(str), fill, 'c', width)
+	return strings.Repeat(fill, left) + str + strings.Repeat(fill, right)
+}
+
+// LjustDisplay left-justifies str to a terminal display width
+func (s StringOps) LjustDisplay(str string, width int, fill string) string {
+	left, right := padCounts(width-DisplayWidth(str), fill, 'l', width)
+	return strings.Repeat(fill, left) + str + strings.Repeat(fill, right)
+}
+
+// RjustDisplay right-justifies str to a terminal display width
+func (s StringOps) RjustDisplay(str string, width int, fill string) string {
+	left, right := padCounts(width-DisplayWidth(str), fill, 'r', width)
+	return strings.Repeat(fill, left) + str + strings.Repeat(fill, right)
+}
+
+// CenterDisplay centers str to a terminal display width
+func (s StringOps) CenterDisplay(str string, width int, fill string) string {
+	left, right := padCounts(width-DisplayWidth(str), fill, 'c', width)
+	return strings.Repeat(fill, left) + str + strings.Repeat(fill, right)
+}
+
+// padCounts returns how many fill characters go on each side of the content.
+// The fill must be a single character, as in Python.
+func padCounts(margin int, fill string, align byte, width int) (int, int) {
+	if utf8.RuneCountInString(fill) != 1 {
+		Raise("TypeError", "The fill character must be exactly one character long")
+	}
+	if margin <= 0 {
+		return 0, 0
+	}
+	switch align {
+	case 'l':
+		return 0, margin
+	case 'r':
+		return margin, 0
+	}
+	// CPython's centering rule: the extra fill goes left only when both margin and width are odd
+	left := margin/2 + (margin & width & 1)
+	return left, margin - left
+}
+
+// DisplayWidth returns the number of terminal columns str occupies
+func DisplayWidth(str string) int {
+	width := 0
+	for _, r := range str {
+		width += runeDisplayWidth(r)
+	}
+	return width
+}
+
+// runeDisplayWidth returns 0 for combining/format characters, 2 for East Asian
+// wide/fullwidth characters and emoji, and 1 otherwise
+func runeDisplayWidth(r rune) int {
+	if r == 0 || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || (r >= 0x1160 && r <= 0x11FF) {
+		return 0
+	}
+	i := sort.Search(len(wideRanges), func(i int) bool { return wideRanges[i][1] >= r })
+	if i < len(wideRanges) && wideRanges[i][0] <= r {
+		return 2
+	}
+	return 1
+}
+
+// wideRanges lists East Asian Wide (W) and Fullwidth (F) code point ranges, sorted
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC},
+	{0x23F0, 0x23F0}, {0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1},
+	{0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE},
+	{0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B},
+	{0x2728, 0x2728}, {0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27B0, 0x27B0}, {0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x2E80, 0x303E},
+	{0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19},
+	{0xFE30, 0xFE6F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x16FE4},
+	{0x17000, 0x18CFF}, {0x1AFF0, 0x1B2FF}, {0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF},
+	{0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F200, 0x1F251}, {0x1F260, 0x1F265},
+	{0x1F300, 0x1F320}, {0x1F32D, 0x1F335}, {0x1F337, 0x1F37C}, {0x1F37E, 0x1F393},
+	{0x1F3A0, 0x1F3CA}, {0x1F3CF, 0x1F3D3}, {0x1F3E0, 0x1F3F0}, {0x1F3F4, 0x1F3F4},
+	{0x1F3F8, 0x1F43E}, {0x1F440, 0x1F440}, {0x1F442, 0x1F4FC}, {0x1F4FF, 0x1F53D},
+	{0x1F54B, 0x1F54E}, {0x1F550, 0x1F567}, {0x1F57A, 0x1F57A}, {0x1F595, 0x1F596},
+	{0x1F5A4, 0x1F5A4}, {0x1F5FB, 0x1F64F}, {0x1F680, 0x1F6C5}, {0x1F6CC, 0x1F6CC},
+	{0x1F6D0, 0x1F6D2}, {0x1F6D5, 0x1F6D7}, {0x1F6EB, 0x1F6EC}, {0x1F6F4, 0x1F6FC},
+	{0x1F7E0, 0x1F7EB}, {0x1F90C, 0x1F93A}, {0x1F93C, 0x1F945}, {0x1F947, 0x1F9FF},
+	{0x1FA70, 0x1FAFF}, {0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// StringAccumulator backs a string that a loop only appends to. Go strings
+// are immutable, so `s += piece` copies s on every iteration and a loop of n
+// appends is O(n²); the transpiler rewrites such loops to Add pieces to an
+// accumulator seeded with the string's value and assigns String() back to
+// the variable once the loop exits.
+type StringAccumulator struct {
+	builder strings.Builder
+}
+
+// NewStringAccumulator starts an accumulator holding initial
+func NewStringAccumulator(initial string) *StringAccumulator {
+	acc := &StringAccumulator{}
+	acc.builder.WriteString(initial)
+	return acc
+}
+
+// Add appends piece (s += piece)
+func (acc *StringAccumulator) Add(piece string) {
+	acc.builder.WriteString(piece)
+}
+
+// Len returns the accumulated length in bytes
+func (acc *StringAccumulator) Len() int {
+	return acc.builder.Len()
+}
+
+// String returns everything accumulated so far
+func (acc *StringAccumulator) String() string {
+	return acc.builder.String()
+}
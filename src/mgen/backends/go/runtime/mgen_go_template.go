@@ -0,0 +1,139 @@
+package mgen
+
+import (
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// $-substitution
+//
+// Template implements string.Template: "$name" and "${name}" are replaced by
+// str() of the mapping's value and "$$" is a literal "$". Placeholder names
+// are ASCII identifiers, so "$1" or a trailing "$" is an invalid placeholder.
+// Substitute raises KeyError for a missing name and ValueError for an invalid
+// placeholder; SafeSubstitute leaves both in the output unchanged.
+
+// Template is a string containing $-placeholders (string.Template)
+type Template struct {
+	Template string
+}
+
+// NewTemplate creates a template from its text (string.Template(text))
+func NewTemplate(text string) Template {
+	return Template{Template: text}
+}
+
+// Substitute replaces every placeholder with the value mapping holds for it
+// (template.substitute(mapping))
+func (t Template) Substitute(mapping interface{}) string {
+	return t.substitute(mapping, false)
+}
+
+// SafeSubstitute replaces the placeholders mapping has values for, leaving
+// unknown and invalid placeholders intact (template.safe_substitute(mapping))
+func (t Template) SafeSubstitute(mapping interface{}) string {
+	return t.substitute(mapping, true)
+}
+
+// String returns the template text
+func (t Template) String() string {
+	return t.Template
+}
+
+// substitute expands the template, scanning it left to right like the
+// pattern string.Template compiles
+func (t Template) substitute(mapping interface{}, safe bool) string {
+	text := t.Template
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(text, '$')
+		if start < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+		b.WriteString(text[:start])
+		rest := text[start+1:]
+		name, size := placeholderName(rest)
+		switch {
+		case strings.HasPrefix(rest, "$"):
+			b.WriteByte('$')
+			size = 1
+		case size > 0:
+			if value, ok := mappingLookup(mapping, name); ok {
+				b.WriteString(ToStr(value))
+			} else if safe {
+				b.WriteString(text[start : start+1+size])
+			} else {
+				Raise("KeyError", "%s", Repr(name))
+			}
+		case safe:
+			b.WriteByte('$')
+		default:
+			line, col := t.position(len(t.Template) - len(rest))
+			Raise("ValueError", "Invalid placeholder in string: line %d, col %d", line, col)
+		}
+		text = rest[size:]
+	}
+}
+
+// placeholderName parses the name at the start of text ("name" or "{name}"),
+// returning the name and the bytes it spans, or a size of 0 when there is none
+func placeholderName(text string) (string, int) {
+	if strings.HasPrefix(text, "{") {
+		n := identifierLength(text[1:])
+		if n > 0 && strings.HasPrefix(text[1+n:], "}") {
+			return text[1 : 1+n], n + 2
+		}
+		return "", 0
+	}
+	n := identifierLength(text)
+	return text[:n], n
+}
+
+// identifierLength returns the length of the ASCII identifier text starts with
+func identifierLength(text string) int {
+	for i := 0; i < len(text); i++ {
+		c := text[i] | 0x20
+		isLetter := c >= 'a' && c <= 'z' || text[i] == '_'
+		if !isLetter && (i == 0 || text[i] < '0' || text[i] > '9') {
+			return i
+		}
+	}
+	return len(text)
+}
+
+// position returns the 1-based line and column Python reports for the
+// invalid placeholder whose name would start at byte offset
+func (t Template) position(offset int) (int, int) {
+	lines := splitLines(t.Template[:offset], true, isLineBoundary)
+	last := lines[len(lines)-1]
+	return len(lines), utf8.RuneCountInString(last)
+}
+
+// mappingLookup returns mapping[key] for a PyDict, a Dict, or a Go map keyed
+// by strings or interface{} values
+func mappingLookup(mapping interface{}, key string) (interface{}, bool) {
+	if d, ok := mapping.(*PyDict); ok {
+		if !d.Contains(key) {
+			return nil, false
+		}
+		return d.Get(key), true
+	}
+	if d, ok := mapping.(dictLike); ok {
+		return d.pyLookup(key)
+	}
+	v := reflect.ValueOf(mapping)
+	if v.Kind() != reflect.Map {
+		Raise("TypeError", "'%s' object is not subscriptable", pyTypeName(mapping))
+	}
+	k := reflect.ValueOf(key)
+	if !k.Type().AssignableTo(v.Type().Key()) {
+		return nil, false
+	}
+	value := v.MapIndex(k.Convert(v.Type().Key()))
+	if !value.IsValid() {
+		return nil, false
+	}
+	return value.Interface(), true
+}
@@ -0,0 +1,79 @@
+package mgen
+
+// Fixed-arity tuples
+//
+// Tuples are []interface{} at runtime, which Go cannot use as map keys, so
+// a dict keyed by (x, y) would need the value-hashed PyDict. Tuple2 and
+// Tuple3 are comparable structs instead: when every key of a dict or member
+// of a set is a 2- or 3-tuple of scalars, the emitter types it as
+// Dict[Tuple2[int, int], V] or Set[Tuple3[...]] and Go hashes the struct
+// natively. They print, compare and order like Python tuples, item by item.
+
+// Tuple2 is a hashable two-element tuple such as (x, y)
+type Tuple2[A, B comparable] struct {
+	First  A
+	Second B
+}
+
+// String renders the tuple like Python's repr: (1, 'a')
+func (t Tuple2[A, B]) String() string {
+	return "(" + Repr(t.First) + ", " + Repr(t.Second) + ")"
+}
+
+// tupleItems returns the fields as the items of a two-element tuple
+func (t Tuple2[A, B]) tupleItems() []interface{} {
+	return []interface{}{t.First, t.Second}
+}
+
+// Tuple3 is a hashable three-element tuple such as (x, y, z)
+type Tuple3[A, B, C comparable] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// String renders the tuple like Python's repr: (1, 2, 'a')
+func (t Tuple3[A, B, C]) String() string {
+	return "(" + Repr(t.First) + ", " + Repr(t.Second) + ", " + Repr(t.Third) + ")"
+}
+
+// tupleItems returns the fields as the items of a three-element tuple
+func (t Tuple3[A, B, C]) tupleItems() []interface{} {
+	return []interface{}{t.First, t.Second, t.Third}
+}
+
+// ToTuple2 converts a dynamically typed two-item tuple, such as a tuple
+// variable used as a key, raising ValueError for another item count and
+// TypeError for items of other types
+func ToTuple2[A, B comparable](x interface{}) Tuple2[A, B] {
+	if t, ok := x.(Tuple2[A, B]); ok {
+		return t
+	}
+	items := UnpackN(x, 2)
+	return Tuple2[A, B]{First: tupleItem[A](items[0]), Second: tupleItem[B](items[1])}
+}
+
+// ToTuple3 converts a dynamically typed three-item tuple like ToTuple2
+func ToTuple3[A, B, C comparable](x interface{}) Tuple3[A, B, C] {
+	if t, ok := x.(Tuple3[A, B, C]); ok {
+		return t
+	}
+	items := UnpackN(x, 3)
+	return Tuple3[A, B, C]{First: tupleItem[A](items[0]), Second: tupleItem[B](items[1]), Third: tupleItem[C](items[2])}
+}
+
+// tupleItem asserts one tuple item to T; a number is accepted where a float
+// is expected, since Python compares and hashes 1 and 1.0 alike
+func tupleItem[T comparable](x interface{}) T {
+	if item, ok := x.(T); ok {
+		return item
+	}
+	var zero T
+	if _, ok := interface{}(zero).(float64); ok {
+		if f, ok := asFloat(x); ok {
+			return interface{}(f).(T)
+		}
+	}
+	Raise("TypeError", "tuple item must be %s, not %s", pyTypeName(zero), pyTypeName(x))
+	return zero
+}
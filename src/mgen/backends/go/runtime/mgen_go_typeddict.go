@@ -0,0 +1,366 @@
+package mgen
+
+// Typed ordered dicts
+//
+// Dict is the generic counterpart of PyDict for dicts whose key and value
+// types are known, such as dict[str, int]. Keys are looked up in a native Go
+// map, so nothing is boxed or hashed to a string, and entries are kept in a
+// slice so iteration follows insertion order as in Python. Keys must be
+// comparable Go values; tuple keys and mixed numeric keys still need PyDict,
+// and ToPyDict/DictFromPyDict convert between the two. Keys of a class
+// defining __eq__ are bucketed by its __hash__ and matched with __eq__
+// instead (see PyHashable), so equal instances find the same entry. The Go backend uses
+// Dict for every dict whose keys can be Go map keys, including dict literals
+// and comprehensions. defaultdict and Counter are Dicts that supply a value
+// for a missing key (see mgen_go_collections.go).
+
+// Dict is an insertion-ordered dict with keys of type K and values of type V
+type Dict[K comparable, V any] struct {
+	index   map[K]int
+	buckets map[int][]int // positions by hash, used instead of index for class keys
+	entries []KV[K, V]
+	missing *missingValues[V] // set for defaultdict and Counter
+}
+
+// NewDict builds a dict from entries; later duplicates overwrite earlier values
+func NewDict[K comparable, V any](entries ...KV[K, V]) *Dict[K, V] {
+	d := &Dict[K, V]{}
+	d.clearIndex()
+	for _, e := range entries {
+		d.Set(e.Key, e.Value)
+	}
+	return d
+}
+
+// clearIndex empties the index, choosing hash buckets when K is a class
+// defining __eq__ and a native Go map otherwise
+func (d *Dict[K, V]) clearIndex() {
+	if classKey[K]() {
+		d.index, d.buckets = nil, make(map[int][]int)
+	} else {
+		d.index, d.buckets = make(map[K]int), nil
+	}
+}
+
+// find returns the position of key's entry and whether the key is present
+func (d *Dict[K, V]) find(key K) (int, bool) {
+	if d.buckets == nil {
+		i, ok := d.index[key]
+		return i, ok
+	}
+	for _, i := range d.buckets[classHash(key)] {
+		if Eq(key, d.entries[i].Key) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// record indexes the entry at position i
+func (d *Dict[K, V]) record(i int) {
+	key := d.entries[i].Key
+	if d.buckets == nil {
+		d.index[key] = i
+		return
+	}
+	hash := classHash(key)
+	d.buckets[hash] = append(d.buckets[hash], i)
+}
+
+// remove deletes the entry at position i and reindexes the entries after it
+func (d *Dict[K, V]) remove(i int) {
+	key := d.entries[i].Key
+	d.entries = append(d.entries[:i], d.entries[i+1:]...)
+	if d.buckets == nil {
+		delete(d.index, key)
+	}
+	d.reindex(i)
+}
+
+// reindex records the positions of the entries from position i on, after
+// they have moved
+func (d *Dict[K, V]) reindex(i int) {
+	if d.buckets == nil {
+		for j := i; j < len(d.entries); j++ {
+			d.index[d.entries[j].Key] = j
+		}
+		return
+	}
+	d.buckets = make(map[int][]int)
+	for j := range d.entries {
+		d.record(j)
+	}
+}
+
+// Get returns d[key], raising KeyError when the key is missing (a
+// defaultdict or Counter supplies a value instead, see missingValue)
+func (d *Dict[K, V]) Get(key K) V {
+	i, ok := d.find(key)
+	if !ok {
+		return d.missingValue(key)
+	}
+	return d.entries[i].Value
+}
+
+// GetOr returns d.get(key, def)
+func (d *Dict[K, V]) GetOr(key K, def V) V {
+	if i, ok := d.find(key); ok {
+		return d.entries[i].Value
+	}
+	return def
+}
+
+// Lookup returns d[key] and whether the key is present, like a comma-ok map index
+func (d *Dict[K, V]) Lookup(key K) (V, bool) {
+	if i, ok := d.find(key); ok {
+		return d.entries[i].Value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set assigns d[key] = value; an existing key keeps its position
+func (d *Dict[K, V]) Set(key K, value V) {
+	if i, ok := d.find(key); ok {
+		d.entries[i].Value = value
+		return
+	}
+	d.entries = append(d.entries, KV[K, V]{Key: key, Value: value})
+	d.record(len(d.entries) - 1)
+}
+
+// SetDefault returns d[key], first inserting def when the key is missing
+// (d.setdefault(key, def))
+func (d *Dict[K, V]) SetDefault(key K, def V) V {
+	if i, ok := d.find(key); ok {
+		return d.entries[i].Value
+	}
+	d.Set(key, def)
+	return def
+}
+
+// Contains reports whether key is in the dict (key in d)
+func (d *Dict[K, V]) Contains(key K) bool {
+	_, ok := d.find(key)
+	return ok
+}
+
+// Delete removes key, raising KeyError when it is missing (del d[key])
+func (d *Dict[K, V]) Delete(key K) {
+	d.Pop(key)
+}
+
+// Pop removes key and returns its value (d.pop(key)), raising KeyError
+// when the key is missing
+func (d *Dict[K, V]) Pop(key K) V {
+	i, ok := d.find(key)
+	if !ok {
+		Raise("KeyError", "%s", Repr(key))
+	}
+	value := d.entries[i].Value
+	d.remove(i)
+	return value
+}
+
+// PopOr removes key and returns its value, or returns def when the key is
+// missing (d.pop(key, def))
+func (d *Dict[K, V]) PopOr(key K, def V) V {
+	if !d.Contains(key) {
+		return def
+	}
+	return d.Pop(key)
+}
+
+// Update copies the entries of other into d in other's order (d.update(other));
+// a Counter adds other's counts to its own instead
+func (d *Dict[K, V]) Update(other *Dict[K, V]) {
+	var zero V
+	for _, e := range other.entries {
+		if d.isCounter() {
+			e.Value = BinOp("+", d.GetOr(e.Key, zero), e.Value).(V)
+		}
+		d.Set(e.Key, e.Value)
+	}
+}
+
+// UpdateFrom copies the pairs of any other mapping or iterable of pairs, such
+// as a list of tuples, into d (d.update(pairs))
+func (d *Dict[K, V]) UpdateFrom(other interface{}) {
+	for _, e := range updateEntries(other) {
+		key, value := typedEntry[K, V](e)
+		d.Set(key, value)
+	}
+}
+
+// PopItem removes and returns the most recently inserted entry
+// (d.popitem()), raising KeyError when the dict is empty
+func (d *Dict[K, V]) PopItem() KV[K, V] {
+	if d.kind() == "OrderedDict" {
+		return d.PopItemEnd(true)
+	}
+	if len(d.entries) == 0 {
+		Raise("KeyError", "'popitem(): dictionary is empty'")
+	}
+	last := d.entries[len(d.entries)-1]
+	d.remove(len(d.entries) - 1)
+	return last
+}
+
+// Clear removes all entries (d.clear())
+func (d *Dict[K, V]) Clear() {
+	d.clearIndex()
+	d.entries = nil
+}
+
+// Copy returns a shallow copy (d.copy()), of the same kind for a defaultdict or Counter
+func (d *Dict[K, V]) Copy() *Dict[K, V] {
+	c := NewDict(d.entries...)
+	c.missing = d.missing
+	return c
+}
+
+// Len returns the number of entries (len(d))
+func (d *Dict[K, V]) Len() int {
+	return len(d.entries)
+}
+
+// Keys returns the keys in insertion order (list(d))
+func (d *Dict[K, V]) Keys() []K {
+	keys := make([]K, len(d.entries))
+	for i, e := range d.entries {
+		keys[i] = e.Key
+	}
+	return keys
+}
+
+// Values returns the values in insertion order (d.values())
+func (d *Dict[K, V]) Values() []V {
+	values := make([]V, len(d.entries))
+	for i, e := range d.entries {
+		values[i] = e.Value
+	}
+	return values
+}
+
+// Items returns the entries in insertion order (d.items())
+func (d *Dict[K, V]) Items() []KV[K, V] {
+	return append([]KV[K, V]{}, d.entries...)
+}
+
+// Equal reports whether the dicts hold equal keys mapped to equal values
+// (d == other); like Python's dict comparison it ignores order, unless both
+// are OrderedDicts
+func (d *Dict[K, V]) Equal(other *Dict[K, V]) bool {
+	if d.kind() == "OrderedDict" && other.kind() == "OrderedDict" {
+		return orderedEqual(d, other)
+	}
+	if d.Len() != other.Len() {
+		return false
+	}
+	for _, e := range d.entries {
+		i, ok := other.find(e.Key)
+		if !ok || !Eq(e.Value, other.entries[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ToPyDict copies the entries into a dynamically typed PyDict, keeping their order
+func (d *Dict[K, V]) ToPyDict() *PyDict {
+	result := NewPyDict()
+	for _, e := range d.entries {
+		result.Set(e.Key, e.Value)
+	}
+	return result
+}
+
+// DictFromPyDict copies a PyDict into a typed Dict, keeping the entry order
+// and raising TypeError when a key or value does not have the target type.
+// None values are only accepted when V is an interface type.
+func DictFromPyDict[K comparable, V any](d *PyDict) *Dict[K, V] {
+	result := NewDict[K, V]()
+	for _, e := range d.entries {
+		key, value := typedEntry[K, V](e)
+		result.Set(key, value)
+	}
+	return result
+}
+
+// typedEntry returns the key and value of e as K and V, raising TypeError
+// when either has another type; an int value is accepted for a float V
+func typedEntry[K comparable, V any](e PyDictEntry) (K, V) {
+	key, ok := e.Key.(K)
+	if !ok {
+		Raise("TypeError", "unexpected key type '%s'", pyTypeName(e.Key))
+	}
+	var zero V
+	if _, isFloat := any(zero).(float64); isFloat {
+		if i, isInt := e.Value.(int); isInt {
+			return key, any(float64(i)).(V)
+		}
+	}
+	value, ok := e.Value.(V)
+	if !ok && (e.Value != nil || any(zero) != nil) {
+		Raise("TypeError", "unexpected value type '%s'", pyTypeName(e.Value))
+	}
+	return key, value
+}
+
+// String renders the dict like Python's repr: {'a': 1, 'b': 2}
+func (d *Dict[K, V]) String() string {
+	return Repr(d)
+}
+
+// dictLike is implemented by every *Dict[K, V], so the helpers that take
+// values of unknown type (Repr, Contains, LenValue, list(d), ...) can handle
+// typed dicts whatever their key and value types
+type dictLike interface {
+	Len() int
+	kind() string
+	wrapRepr(body string) string
+	pyEntries() []PyDictEntry
+	pyLookup(key interface{}) (interface{}, bool)
+	pyDelete(key interface{})
+}
+
+// pyEntries returns the entries with their keys and values boxed, in order
+func (d *Dict[K, V]) pyEntries() []PyDictEntry {
+	entries := make([]PyDictEntry, len(d.entries))
+	for i, e := range d.entries {
+		entries[i] = PyDictEntry{Key: e.Key, Value: e.Value}
+	}
+	return entries
+}
+
+// pyLookup returns d[key] for a key of any type; a key of another type only
+// matches a key it is equal to under Python's == (1.0 finds the key 1)
+func (d *Dict[K, V]) pyLookup(key interface{}) (interface{}, bool) {
+	if k, ok := key.(K); ok {
+		if i, ok := d.find(k); ok {
+			return d.entries[i].Value, true
+		}
+		return nil, false
+	}
+	for _, e := range d.entries {
+		if Eq(e.Key, key) {
+			return e.Value, true
+		}
+	}
+	return nil, false
+}
+
+// pyDelete removes a key of any type, raising KeyError when it is missing
+func (d *Dict[K, V]) pyDelete(key interface{}) {
+	if k, ok := key.(K); ok {
+		d.Delete(k)
+		return
+	}
+	for _, e := range d.entries {
+		if Eq(e.Key, key) {
+			d.Delete(e.Key)
+			return
+		}
+	}
+	Raise("KeyError", "%s", Repr(key))
+}
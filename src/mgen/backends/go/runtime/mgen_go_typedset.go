@@ -0,0 +1,229 @@
+package mgen
+
+// Typed ordered sets
+//
+// Set is the generic counterpart of PySet for sets whose member type is
+// known, such as set[int] or set[str]. It is a Dict with empty values, so
+// members are looked up in a native Go map and iterate in insertion order,
+// which keeps loops and printed sets deterministic. The set algebra keeps
+// that order too: results list the left operand's members first, then those
+// only the right operand adds.
+
+// Set is an insertion-ordered set with members of type T
+type Set[T comparable] struct {
+	dict *Dict[T, struct{}]
+}
+
+// NewSet builds a set from items, dropping duplicates
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{dict: NewDict[T, struct{}]()}
+	for _, item := range items {
+		s.Add(item)
+	}
+	return s
+}
+
+// Add inserts item (s.add(item))
+func (s *Set[T]) Add(item T) {
+	if !s.dict.Contains(item) {
+		s.dict.Set(item, struct{}{})
+	}
+}
+
+// Contains reports whether item is in the set (item in s)
+func (s *Set[T]) Contains(item T) bool {
+	return s.dict.Contains(item)
+}
+
+// Remove deletes item, raising KeyError when it is missing (s.remove(item))
+func (s *Set[T]) Remove(item T) {
+	s.dict.Delete(item)
+}
+
+// Discard deletes item if present (s.discard(item))
+func (s *Set[T]) Discard(item T) {
+	if s.dict.Contains(item) {
+		s.dict.Delete(item)
+	}
+}
+
+// Pop removes and returns the oldest member (s.pop()), raising KeyError
+// when the set is empty
+func (s *Set[T]) Pop() T {
+	if s.Len() == 0 {
+		Raise("KeyError", "'pop from an empty set'")
+	}
+	item := s.dict.entries[0].Key
+	s.dict.Delete(item)
+	return item
+}
+
+// Clear removes all members (s.clear())
+func (s *Set[T]) Clear() {
+	s.dict.Clear()
+}
+
+// Copy returns a shallow copy (s.copy())
+func (s *Set[T]) Copy() *Set[T] {
+	return &Set[T]{dict: s.dict.Copy()}
+}
+
+// Len returns the number of members (len(s))
+func (s *Set[T]) Len() int {
+	return s.dict.Len()
+}
+
+// Items returns the members in insertion order (for x in s)
+func (s *Set[T]) Items() []T {
+	return s.dict.Keys()
+}
+
+// Union returns the members of either set (s | other)
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	result := s.Copy()
+	result.Update(other)
+	return result
+}
+
+// Intersection returns the members of both sets (s & other)
+func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for _, item := range s.Items() {
+		if other.Contains(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// Difference returns the members of s that are not in other (s - other)
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for _, item := range s.Items() {
+		if !other.Contains(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// SymmetricDifference returns the members of exactly one set (s ^ other)
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	result := s.Difference(other)
+	result.Update(other.Difference(s))
+	return result
+}
+
+// Update adds the members of other (s |= other, s.update(other))
+func (s *Set[T]) Update(other *Set[T]) {
+	for _, item := range other.Items() {
+		s.Add(item)
+	}
+}
+
+// IntersectionUpdate keeps only the members also in other (s &= other)
+func (s *Set[T]) IntersectionUpdate(other *Set[T]) {
+	s.dict = s.Intersection(other).dict
+}
+
+// DifferenceUpdate removes the members of other (s -= other)
+func (s *Set[T]) DifferenceUpdate(other *Set[T]) {
+	for _, item := range other.Items() {
+		s.Discard(item)
+	}
+}
+
+// SymmetricDifferenceUpdate keeps the members of exactly one set (s ^= other)
+func (s *Set[T]) SymmetricDifferenceUpdate(other *Set[T]) {
+	s.dict = s.SymmetricDifference(other).dict
+}
+
+// IsSubset reports whether every member of s is in other (s <= other)
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for _, item := range s.Items() {
+		if !other.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset reports whether every member of other is in s (s >= other)
+func (s *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// IsDisjoint reports whether the sets have no members in common
+func (s *Set[T]) IsDisjoint(other *Set[T]) bool {
+	return s.Intersection(other).Len() == 0
+}
+
+// Equal reports whether the sets have the same members (s == other)
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+// String renders the set like Python's repr: {1, 2} or set()
+func (s *Set[T]) String() string {
+	return Repr(s)
+}
+
+// setLike is implemented by every *Set[T] and by PySet, so the helpers that
+// take values of unknown type (Repr, Eq, Contains, list(s), ...) can handle
+// sets whatever their member type
+type setLike interface {
+	Len() int
+	pyItems() []interface{}
+	pyContains(item interface{}) bool
+}
+
+// pyItems returns the members boxed, in insertion order
+func (s *Set[T]) pyItems() []interface{} {
+	items := make([]interface{}, 0, s.Len())
+	for _, item := range s.Items() {
+		items = append(items, item)
+	}
+	return items
+}
+
+// pyContains reports whether item is a member; an item of another type only
+// matches a member it is equal to under Python's == (1.0 finds the member 1)
+func (s *Set[T]) pyContains(item interface{}) bool {
+	_, ok := s.dict.pyLookup(item)
+	return ok
+}
+
+// compareSets implements the subset orderings of Python sets (a < b is a
+// proper subset); unlike numbers, two sets may be neither <, == nor >
+func compareSets(op string, a, b setLike) bool {
+	subset := func(x, y setLike) bool {
+		if x.Len() > y.Len() {
+			return false
+		}
+		for _, item := range x.pyItems() {
+			if !y.pyContains(item) {
+				return false
+			}
+		}
+		return true
+	}
+	switch op {
+	case "<":
+		return a.Len() < b.Len() && subset(a, b)
+	case "<=":
+		return subset(a, b)
+	case ">":
+		return a.Len() > b.Len() && subset(b, a)
+	case ">=":
+		return subset(b, a)
+	case "==":
+		return a.Len() == b.Len() && subset(a, b)
+	case "!=":
+		return a.Len() != b.Len() || !subset(a, b)
+	}
+	Raise("TypeError", "unsupported comparison operator %s", op)
+	return false
+}
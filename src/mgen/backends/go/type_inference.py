@@ -5,6 +5,7 @@ and special handling for Go idioms like interface{}.
 """
 
 import ast
+import re
 from typing import TYPE_CHECKING, Callable, Optional
 
 from ..type_inference_strategies import (
@@ -14,6 +15,7 @@ from ..type_inference_strategies import (
     InferenceContext,
     ListInferenceStrategy,
     SetInferenceStrategy,
+    TypeInferenceStrategy,
 )
 
 if TYPE_CHECKING:
@@ -34,60 +36,75 @@ class GoListInferenceStrategy(ListInferenceStrategy):
             # Empty list - use default int
             return "[]int"
 
-        # Use parent implementation for element type inference
-        result = super().infer(value, context)
-
-        # If parent returns generic "list", convert to []int default
-        if result == context.type_mapper("list"):
-            return "[]int"
+        assert context.infer_recursively is not None
+        element_types = [context.infer_recursively(elt) for elt in value.elts]
+        if all(element_type == element_types[0] for element_type in element_types):
+            return self._format_list_type(element_types[0], context)
 
-        return result
+        # Mixed element types ([True, 0, 2]) convert to a []interface{} literal
+        return "[]interface{}"
 
 
 class GoDictInferenceStrategy(DictInferenceStrategy):
-    """Go-specific dict type inference with map[K]V formatting."""
+    """Go-specific dict type inference with *mgen.Dict[K, V] formatting.
+
+    Keys and values are typed separately: each side takes the type its items
+    share, or interface{} when they differ ({"a": 1, "b": "x"} is a
+    *mgen.Dict[string, interface{}]).
+    """
 
     def _format_dict_type(self, key_type: str, value_type: str, context: InferenceContext) -> str:
-        """Format as map[K]V."""
-        return f"map[{key_type}]{value_type}"
+        """Format as the insertion-ordered *mgen.Dict[K, V]."""
+        return go_dict_type(key_type, value_type)
 
     def infer(self, value: ast.expr, context: InferenceContext) -> str:
         assert isinstance(value, ast.Dict), "Expected ast.Dict"
 
         if not value.keys or not value.values:
             # Empty dict - use default int keys/values
-            return "map[int]int"
-
-        # Use parent implementation
-        result = super().infer(value, context)
+            return go_dict_type("int", "int")
+        assert context.infer_recursively is not None
+        keys = [key for key in value.keys if key is not None]
+        tuple_type = tuple_key_type(keys, context.infer_recursively)
+        if tuple_type is None and needs_value_hashing(keys, context.infer_recursively):
+            return "*mgen.PyDict"
+        if any(key is None for key in value.keys):
+            # ** unpacking mixes in entries of unknown types
+            return go_dict_type("interface{}", "interface{}")
 
-        # If parent returns generic "dict", convert to map[int]int default
-        if result == context.type_mapper("dict"):
-            return "map[int]int"
-
-        return result
+        key_types = {tuple_type} if tuple_type else {context.infer_recursively(key) for key in keys}
+        value_types = {context.infer_recursively(item) for item in value.values}
+        key_type = key_types.pop() if len(key_types) == 1 else "interface{}"
+        value_type = value_types.pop() if len(value_types) == 1 else "interface{}"
+        return self._format_dict_type(key_type or "interface{}", value_type or "interface{}", context)
 
 
 class GoSetInferenceStrategy(SetInferenceStrategy):
-    """Go-specific set type inference with map[T]bool formatting."""
+    """Go-specific set type inference with *mgen.Set[T] formatting."""
 
     def _format_set_type(self, element_type: str, context: InferenceContext) -> str:
-        """Format as map[T]bool (Go doesn't have native sets)."""
-        return f"map[{element_type}]bool"
+        """Format as *mgen.Set[T] (Go doesn't have native sets)."""
+        return go_set_type(element_type)
 
     def infer(self, value: ast.expr, context: InferenceContext) -> str:
         assert isinstance(value, ast.Set), "Expected ast.Set"
 
         if not value.elts:
             # Empty set - use default int
-            return "map[int]bool"
+            return go_set_type("int")
+        assert context.infer_recursively is not None
+        tuple_type = tuple_key_type(value.elts, context.infer_recursively)
+        if tuple_type is not None:
+            return go_set_type(tuple_type)
+        if needs_value_hashing(value.elts, context.infer_recursively):
+            return "*mgen.PySet"
 
         # Use parent implementation
         result = super().infer(value, context)
 
-        # If parent returns generic "set", convert to map[int]bool default
+        # If parent returns generic "set", convert to *mgen.Set[int] default
         if result == context.type_mapper("set"):
-            return "map[int]bool"
+            return go_set_type("int")
 
         return result
 
@@ -97,22 +114,37 @@ class GoComprehensionInferenceStrategy(ComprehensionInferenceStrategy):
 
     def __init__(
         self,
-        loop_var_type_inferrer: Optional[Callable[[ast.comprehension], dict[str, str]]] = None,
+        loop_var_type_inferrer: Optional[Callable[[list[ast.comprehension]], dict[str, str]]] = None,
         element_type_inferrer: Optional[Callable[[ast.expr, dict[str, str]], str]] = None,
     ) -> None:
         """Initialize with Go-specific inference functions.
 
         Args:
-            loop_var_type_inferrer: Function for inferring loop variable types
+            loop_var_type_inferrer: Function inferring the loop variable types of a comprehension's for clauses
             element_type_inferrer: Function for inferring element types with loop var context
         """
         self.loop_var_type_inferrer = loop_var_type_inferrer
         self.element_type_inferrer = element_type_inferrer
 
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.GeneratorExp) or super().can_infer(value)
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        if isinstance(value, ast.GeneratorExp):
+            return self._infer_generator_exp(value, context)
+        return super().infer(value, context)
+
+    def _infer_generator_exp(self, value: ast.GeneratorExp, context: InferenceContext) -> str:
+        """Infer type from a generator expression, a lazy mgen.Iterator."""
+        if self.loop_var_type_inferrer and self.element_type_inferrer:
+            loop_var_type = self.loop_var_type_inferrer(value.generators)
+            return f"mgen.Iterator[{self.element_type_inferrer(value.elt, loop_var_type)}]"
+        return "mgen.Iterator[interface{}]"
+
     def _infer_list_comp(self, value: ast.ListComp, context: InferenceContext) -> str:
         """Infer type from list comprehension."""
         if self.loop_var_type_inferrer and self.element_type_inferrer:
-            loop_var_type = self.loop_var_type_inferrer(value.generators[0])
+            loop_var_type = self.loop_var_type_inferrer(value.generators)
             element_type = self.element_type_inferrer(value.elt, loop_var_type)
             return f"[]{element_type}"
         return "[]int"
@@ -120,19 +152,310 @@ class GoComprehensionInferenceStrategy(ComprehensionInferenceStrategy):
     def _infer_dict_comp(self, value: ast.DictComp, context: InferenceContext) -> str:
         """Infer type from dict comprehension."""
         if self.loop_var_type_inferrer and self.element_type_inferrer:
-            loop_var_type = self.loop_var_type_inferrer(value.generators[0])
-            key_type = self.element_type_inferrer(value.key, loop_var_type)
+            loop_var_type = self.loop_var_type_inferrer(value.generators)
+            key_type = self._infer_key_type(value.key, loop_var_type)
             value_type = self.element_type_inferrer(value.value, loop_var_type)
-            return f"map[{key_type}]{value_type}"
-        return "map[int]int"
+            return go_dict_type(key_type, value_type)
+        return go_dict_type("int", "int")
 
     def _infer_set_comp(self, value: ast.SetComp, context: InferenceContext) -> str:
         """Infer type from set comprehension."""
         if self.loop_var_type_inferrer and self.element_type_inferrer:
-            loop_var_type = self.loop_var_type_inferrer(value.generators[0])
-            element_type = self.element_type_inferrer(value.elt, loop_var_type)
-            return f"map[{element_type}]bool"
-        return "map[int]bool"
+            loop_var_type = self.loop_var_type_inferrer(value.generators)
+            return go_set_type(self._infer_key_type(value.elt, loop_var_type))
+        return go_set_type("int")
+
+    def _infer_key_type(self, key: ast.expr, loop_var_types: dict[str, str]) -> str:
+        """Infer a dict comprehension key or set comprehension member; (x, y) may be a Tuple2."""
+        assert self.element_type_inferrer is not None
+        infer = self.element_type_inferrer
+        return tuple_key_type([key], lambda item: infer(item, loop_var_types)) or infer(key, loop_var_types)
+
+
+# Go types produced by list()/tuple()/set()/dict() over a dynamically typed source
+CONTAINER_CONSTRUCTOR_TYPES = {
+    "list": "[]interface{}",
+    "tuple": "mgen.PyTuple",
+    "set": "*mgen.PySet",
+    "dict": "*mgen.Dict[interface{}, interface{}]",
+}
+
+# Member types set(xs) keeps typed; other members need the dynamic mgen.ToSet
+SET_MEMBER_TYPES = ("int", "float64", "string", "bool")
+
+# Set methods that return a new set of the receiver's type
+SET_RESULT_METHODS = ("copy", "union", "intersection", "difference", "symmetric_difference")
+
+# Builtins whose result type follows their argument types (see GoCallInferenceStrategy.infer)
+ITERATION_BUILTINS = ("sorted", "reversed", "enumerate", "zip")
+
+# Values a defaultdict factory makes, with the int defaults of unsubscripted containers
+DEFAULT_FACTORY_TYPES = {
+    "int": "int",
+    "float": "float64",
+    "str": "string",
+    "bool": "bool",
+    "list": "[]int",
+    "set": "*mgen.Set[int]",
+    "dict": "*mgen.Dict[int, int]",
+}
+
+# Go type of the ints that may outgrow int64 when int_precision is "auto" (see int_precision.py)
+BIG_INT_TYPE = "*mgen.PyInt"
+
+# Go types of bytes and bytearray, which index and iterate as ints
+BYTES_TYPES = ("mgen.PyBytes", "*mgen.PyByteArray")
+
+# Runtime functions of the base64 and binascii modules, which return bytes, by module and name
+CODEC_FUNCTIONS = {
+    ("base64", "b64encode"): "mgen.Base64Encode",
+    ("base64", "b64decode"): "mgen.Base64Decode",
+    ("base64", "urlsafe_b64encode"): "mgen.URLSafeBase64Encode",
+    ("base64", "urlsafe_b64decode"): "mgen.URLSafeBase64Decode",
+    ("binascii", "hexlify"): "mgen.Hexlify",
+    ("binascii", "unhexlify"): "mgen.Unhexlify",
+}
+
+
+# Runtime constructors of hashlib's hash objects by name; new() is only recognized as hashlib.new()
+HASH_CONSTRUCTORS = {
+    "md5": "mgen.NewMD5",
+    "sha1": "mgen.NewSHA1",
+    "sha256": "mgen.NewSHA256",
+    "sha512": "mgen.NewSHA512",
+    "new": "mgen.NewHash",
+}
+
+# Results of a hash object's methods and attributes, which the runtime reads through methods
+HASH_MEMBER_TYPES = {
+    "digest": "mgen.PyBytes",
+    "hexdigest": "string",
+    "name": "string",
+    "digest_size": "int",
+    "block_size": "int",
+}
+
+
+def hash_constructor(func: ast.expr, is_user_name: Callable[[str], bool]) -> Optional[str]:
+    """Return the runtime constructor a hashlib call lowers to (hashlib.sha256 or an imported sha256), or None."""
+    if isinstance(func, ast.Attribute) and isinstance(func.value, ast.Name) and func.value.id == "hashlib":
+        return HASH_CONSTRUCTORS.get(func.attr)
+    if isinstance(func, ast.Name) and func.id != "new" and not is_user_name(func.id):
+        return HASH_CONSTRUCTORS.get(func.id)
+    return None
+
+
+def codec_function(func: ast.expr, is_user_name: Callable[[str], bool]) -> Optional[str]:
+    """Return the runtime function a base64 or binascii call lowers to, or None.
+
+    The function may be named through its module (base64.b64encode) or
+    imported from it (b64encode), unless the module defines that name itself.
+    """
+    if isinstance(func, ast.Attribute) and isinstance(func.value, ast.Name):
+        return CODEC_FUNCTIONS.get((func.value.id, func.attr))
+    if isinstance(func, ast.Name) and not is_user_name(func.id):
+        return next((go_name for (_, name), go_name in CODEC_FUNCTIONS.items() if name == func.id), None)
+    return None
+
+
+class GoSliceInferenceStrategy(TypeInferenceStrategy):
+    """Slicing (xs[a:b], s[::-1]) produces a value of the sliced type."""
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.Subscript) and isinstance(value.slice, ast.Slice)
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        assert isinstance(value, ast.Subscript), "Expected ast.Subscript"
+        assert context.infer_recursively is not None
+        value_type = context.infer_recursively(value.value)
+        if value_type == "string" or value_type.startswith("[]") or value_type in ("*mgen.PyList", *BYTES_TYPES):
+            return value_type
+        return "interface{}"
+
+
+class GoTupleIndexInferenceStrategy(TypeInferenceStrategy):
+    """Indexing a tuple record or a namedtuple with a constant (kv[1], pos[0]) produces that item's type.
+
+    Tuple records are the runtime structs that stand for tuples, such as a
+    Tuple2/Tuple3 key or an mgen.KV pair of dict.items(). Indexing bytes or a
+    bytearray with any index produces an int, a str a str, and a slice its
+    element type.
+    """
+
+    def __init__(self, record_field_types: Optional[Callable[[str], Optional[list[str]]]] = None) -> None:
+        """Initialize with the converter's lookup of a tuple record's or namedtuple struct's field types."""
+        self.record_field_types = record_field_types
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.Subscript) and not isinstance(value.slice, ast.Slice)
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        assert isinstance(value, ast.Subscript), "Expected ast.Subscript"
+        assert context.infer_recursively is not None
+        value_type = context.infer_recursively(value.value)
+        if value_type in BYTES_TYPES:
+            return "int"
+        if value_type == "string":
+            return "string"
+        if value_type.startswith("[]"):
+            return value_type[2:]
+        item_types = tuple_type_args(value_type)
+        if item_types is None and self.record_field_types is not None:
+            item_types = self.record_field_types(value_type)
+        index = constant_index(value.slice)
+        if item_types is None or index is None or not -len(item_types) <= index < len(item_types):
+            return context.type_mapper("Any")
+        return item_types[index]
+
+
+class GoBigIntInferenceStrategy(TypeInferenceStrategy):
+    """Arithmetic on a big int, an int power and an int literal beyond int64 produce a big int."""
+
+    def __init__(self, big_int_inferrer: Callable[[ast.expr], bool]) -> None:
+        """Initialize with the converter's check, which knows the int_precision mode and variable types."""
+        self.big_int_inferrer = big_int_inferrer
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, (ast.BinOp, ast.UnaryOp, ast.Constant, ast.Call)) and self.big_int_inferrer(value)
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        return BIG_INT_TYPE
+
+
+class GoOperatorMethodInferenceStrategy(TypeInferenceStrategy):
+    """An operator a class implements with a dunder method (a + b, v[i]) produces the method's result."""
+
+    def __init__(self, operator_method_type: Callable[[ast.expr], Optional[str]]) -> None:
+        """Initialize with the converter's lookup, which knows the classes and their methods."""
+        self.operator_method_type = operator_method_type
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, (ast.BinOp, ast.Subscript)) and self.operator_method_type(value) is not None
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        result_type = self.operator_method_type(value)
+        assert result_type is not None
+        return result_type
+
+
+class GoLambdaInferenceStrategy(TypeInferenceStrategy):
+    """A lambda bound to a Callable (a parameter, variable or container item) has the func type it converts to."""
+
+    def __init__(self, lambda_types: dict[int, str]) -> None:
+        """Initialize with the converter's func types expected of lambdas, keyed by node id."""
+        self.lambda_types = lambda_types
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.Lambda) and id(value) in self.lambda_types
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        return self.lambda_types[id(value)]
+
+
+class GoPercentFormatInferenceStrategy(TypeInferenceStrategy):
+    """printf-style formatting ("%d items" % n) produces a string; % on numbers a number."""
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.BinOp) and isinstance(value.op, ast.Mod)
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        assert isinstance(value, ast.BinOp), "Expected ast.BinOp"
+        assert context.infer_recursively is not None
+        if context.infer_recursively(value.left) == "string":
+            return "string"
+        return floored_operator_type(value, context)
+
+
+class GoFloorDivInferenceStrategy(TypeInferenceStrategy):
+    """Floor division (a // b) produces an int on ints and a float when either operand is a float."""
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.BinOp) and isinstance(value.op, ast.FloorDiv)
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        assert isinstance(value, ast.BinOp), "Expected ast.BinOp"
+        return floored_operator_type(value, context)
+
+
+class GoTrueDivInferenceStrategy(TypeInferenceStrategy):
+    """True division (a / b) produces a float on numbers, and an int on ints in Python 2 mode."""
+
+    def __init__(self, python_version: int) -> None:
+        """Initialize with the source dialect, 2 or 3."""
+        self.python_version = python_version
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.BinOp) and isinstance(value.op, ast.Div)
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        assert isinstance(value, ast.BinOp), "Expected ast.BinOp"
+        if self.python_version == 2:
+            return floored_operator_type(value, context)
+        assert context.infer_recursively is not None
+        operand_types = {context.infer_recursively(operand) for operand in (value.left, value.right)}
+        if operand_types <= {"int", "bool", "float64"}:
+            return "float64"
+        return context.type_mapper("Any")
+
+
+class GoPowInferenceStrategy(TypeInferenceStrategy):
+    """A power (a ** b) produces an int on ints and a float when either operand is a float."""
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.BinOp) and isinstance(value.op, ast.Pow)
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        assert isinstance(value, ast.BinOp), "Expected ast.BinOp"
+        return floored_operator_type(value, context)
+
+
+class GoUnaryOpInferenceStrategy(TypeInferenceStrategy):
+    """-x and +x keep a number's type (a bool becomes an int), ~x on an int is an int and not x a bool."""
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.UnaryOp)
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        assert isinstance(value, ast.UnaryOp), "Expected ast.UnaryOp"
+        assert context.infer_recursively is not None
+        if isinstance(value.op, ast.Not):
+            return "bool"
+        operand_type = context.infer_recursively(value.operand)
+        if operand_type in ("int", "bool"):
+            return "int"
+        if operand_type == "float64" and not isinstance(value.op, ast.Invert):
+            return "float64"
+        return context.type_mapper("Any")
+
+
+def floored_operator_type(value: ast.BinOp, context: InferenceContext) -> str:
+    """Return the type of a // b or a % b on numbers, which the runtime's FloorDiv and PyMod keep.
+
+    Bools count as ints, and an int beside a float is widened to float64;
+    other operands give the dynamic type.
+    """
+    assert context.infer_recursively is not None
+    operand_types = {context.infer_recursively(operand) for operand in (value.left, value.right)}
+    if operand_types <= {"int", "bool"}:
+        return "int"
+    if operand_types <= {"int", "bool", "float64"}:
+        return "float64"
+    return context.type_mapper("Any")
+
+
+class GoSetOperatorInferenceStrategy(TypeInferenceStrategy):
+    """Set algebra (a | b, a & b, a - b, a ^ b) on a typed set produces a set of the same type."""
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.BinOp) and isinstance(value.op, (ast.BitOr, ast.BitAnd, ast.Sub, ast.BitXor))
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        assert isinstance(value, ast.BinOp), "Expected ast.BinOp"
+        assert context.infer_recursively is not None
+        left_type = context.infer_recursively(value.left)
+        if set_type_arg(left_type) is not None:
+            return left_type
+        return context.type_mapper("Any")
 
 
 class GoCallInferenceStrategy(CallInferenceStrategy):
@@ -142,15 +465,315 @@ class GoCallInferenceStrategy(CallInferenceStrategy):
         self,
         function_return_types: Optional[dict[str, str]] = None,
         struct_info: Optional[dict[str, dict]] = None,
+        class_aliases: Optional[dict[str, str]] = None,
+        map_filter_inferrer: Optional[Callable[[ast.Call], str]] = None,
+        functools_inferrer: Optional[Callable[[ast.Call], Optional[str]]] = None,
+        generic_inferrer: Optional[Callable[[ast.Call], Optional[str]]] = None,
     ) -> None:
         """Initialize with Go converter context.
 
         Args:
             function_return_types: Mapping of function names to return types
             struct_info: Struct definitions for class types
+            class_aliases: Names (cls) standing for a class inside a classmethod
+            map_filter_inferrer: Result type of a map()/filter() call, which depends on its function argument
+            functools_inferrer: Result type of reduce() or a cached function's cache_info(), None for other calls
+            generic_inferrer: Result type of a call to a generic function, None for other calls
         """
-        self.function_return_types = function_return_types or {}
-        self.struct_info = struct_info or {}
+        # Keep references to the converter's (initially empty) tables so later updates are visible
+        self.function_return_types = function_return_types if function_return_types is not None else {}
+        self.struct_info = struct_info if struct_info is not None else {}
+        self.class_aliases = class_aliases if class_aliases is not None else {}
+        self.map_filter_inferrer = map_filter_inferrer
+        self.functools_inferrer = functools_inferrer
+        self.generic_inferrer = generic_inferrer
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        assert isinstance(value, ast.Call), "Expected ast.Call"
+
+        # dict() and set() are empty containers like {}, typed later by the converter's pre-passes
+        if isinstance(value.func, ast.Name) and value.func.id in ("dict", "set") and not value.args:
+            if not value.keywords:
+                return go_dict_type("int", "int") if value.func.id == "dict" else go_set_type("int")
+
+        # Container constructors with an argument follow the converter's lowering
+        if isinstance(value.func, ast.Name) and value.func.id in CONTAINER_CONSTRUCTOR_TYPES and len(value.args) == 1:
+            if value.func.id in ("list", "dict", "set") and context.infer_recursively is not None:
+                arg_type = context.infer_recursively(value.args[0])
+                iterated = iterated_slice_type(arg_type)
+                if value.func.id == "list" and iterated.startswith("[]"):
+                    # list(d) copies the keys, list(s) the members
+                    return iterated
+                if value.func.id == "dict" and dict_type_args(arg_type) is not None:
+                    return arg_type
+                if value.func.id == "set" and set_type_arg(arg_type) is not None:
+                    return arg_type
+                if value.func.id == "set" and iterated.startswith("[]") and iterated[2:] in SET_MEMBER_TYPES:
+                    return go_set_type(iterated[2:])
+            return CONTAINER_CONSTRUCTOR_TYPES[value.func.id]
+
+        # Iteration builtins keep element types so they compose: enumerate(sorted(xs))
+        if (
+            isinstance(value.func, ast.Name)
+            and value.func.id in ITERATION_BUILTINS
+            and value.func.id not in self.function_return_types
+            and value.args
+            and context.infer_recursively is not None
+        ):
+            arg_types = [iterated_slice_type(context.infer_recursively(arg)) for arg in value.args]
+            elem_types = [t[2:] if t.startswith("[]") else "interface{}" for t in arg_types]
+            if value.func.id in ("sorted", "reversed"):
+                return arg_types[0] if arg_types[0].startswith("[]") else "[]interface{}"
+            if value.func.id == "enumerate":
+                return f"[]mgen.Pair[int, {elem_types[0]}]"
+            if len(elem_types) == 2:
+                return f"[]mgen.Pair[{elem_types[0]}, {elem_types[1]}]"
+            if len(elem_types) == 3:
+                return f"[]mgen.Triple[{', '.join(elem_types)}]"
+
+        # next(it) returns one of the iterator's items; iter(xs) walks a list lazily
+        if (
+            isinstance(value.func, ast.Name)
+            and value.func.id in ("next", "iter")
+            and value.func.id not in self.function_return_types
+            and value.args
+            and context.infer_recursively is not None
+        ):
+            source_type = iterated_slice_type(context.infer_recursively(value.args[0]))
+            item_type = source_type[2:] if source_type.startswith("[]") else "interface{}"
+            return item_type if value.func.id == "next" else f"mgen.Iterator[{item_type}]"
+
+        # min/max/abs/sum keep the type of their arguments
+        if (
+            isinstance(value.func, ast.Name)
+            and value.func.id in ("min", "max", "abs", "sum")
+            and value.func.id not in self.function_return_types
+            and value.args
+            and context.infer_recursively is not None
+        ):
+            return numeric_builtin_type(value.func.id, [context.infer_recursively(arg) for arg in value.args])
+
+        # deque(xs) holds the items of xs; deque() alone is typed by its annotation
+        if (
+            isinstance(value.func, ast.Name)
+            and value.func.id == "deque"
+            and value.func.id not in self.function_return_types
+            and context.infer_recursively is not None
+        ):
+            arg_type = context.infer_recursively(value.args[0]) if value.args else ""
+            return f"*mgen.Deque[{arg_type[2:] if arg_type.startswith('[]') else 'interface{}'}]"
+
+        # defaultdict(factory) holds what the factory makes and Counter(xs) counts the items of xs;
+        # int keys are the default that later subscripts and annotations refine
+        if (
+            isinstance(value.func, ast.Name)
+            and value.func.id in ("defaultdict", "Counter")
+            and value.func.id not in self.function_return_types
+            and value.func.id not in self.struct_info
+            and context.infer_recursively is not None
+        ):
+            if value.func.id == "Counter":
+                items_type = iterated_slice_type(context.infer_recursively(value.args[0])) if value.args else ""
+                if items_type == "string":
+                    # A string counts its characters
+                    return go_dict_type("string", "int")
+                return go_dict_type(items_type[2:] if items_type.startswith("[]") else "int", "int")
+            factory = value.args[0] if value.args else None
+            if isinstance(factory, ast.Lambda):
+                return go_dict_type("int", context.infer_recursively(factory.body))
+            if isinstance(factory, ast.Name) and factory.id in DEFAULT_FACTORY_TYPES:
+                return go_dict_type("int", DEFAULT_FACTORY_TYPES[factory.id])
+            return go_dict_type("int", "interface{}")
+
+        # OrderedDict(d) holds the entries of a dict, OrderedDict(pairs) those of a list of (key, value) tuples
+        if (
+            isinstance(value.func, ast.Name)
+            and value.func.id == "OrderedDict"
+            and value.func.id not in self.function_return_types
+            and value.func.id not in self.struct_info
+            or ast.unparse(value.func) == "collections.OrderedDict"
+        ) and context.infer_recursively is not None:
+            source = value.args[0] if value.args else None
+            if isinstance(source, ast.List) and source.elts and isinstance(source.elts[0], ast.Tuple):
+                pair = source.elts[0].elts
+                if len(pair) == 2:
+                    return go_dict_type(context.infer_recursively(pair[0]), context.infer_recursively(pair[1]))
+            source_type = context.infer_recursively(source) if source is not None else ""
+            return source_type if dict_type_args(source_type) is not None else go_dict_type("int", "int")
+
+        # bytes(...), bytearray(...) and bytes.fromhex(s)
+        if (
+            isinstance(value.func, ast.Name)
+            and value.func.id in ("bytes", "bytearray")
+            and value.func.id not in self.function_return_types
+            and value.func.id not in self.struct_info
+        ):
+            return BYTES_TYPES[value.func.id == "bytearray"]
+        if ast.unparse(value.func) == "bytes.fromhex":
+            return BYTES_TYPES[0]
+        if codec_function(value.func, lambda name: name in self.function_return_types or name in self.struct_info):
+            return BYTES_TYPES[0]
+
+        # hashlib: the hash object and its digests
+        if hash_constructor(value.func, lambda name: name in self.function_return_types or name in self.struct_info):
+            return "*mgen.Hash"
+        if (
+            isinstance(value.func, ast.Attribute)
+            and value.func.attr in ("digest", "hexdigest")
+            and context.infer_recursively is not None
+            and context.infer_recursively(value.func.value) == "*mgen.Hash"
+        ):
+            return HASH_MEMBER_TYPES[value.func.attr]
+
+        # pprint.pformat(x) renders a string
+        if (
+            isinstance(value.func, ast.Attribute)
+            and value.func.attr == "pformat"
+            and isinstance(value.func.value, ast.Name)
+            and value.func.value.id == "pprint"
+        ):
+            return "string"
+
+        # d.items(), d.keys() and d.values() return slices in the dict's order
+        if (
+            isinstance(value.func, ast.Attribute)
+            and value.func.attr in ("items", "keys", "values")
+            and not value.args
+            and context.infer_recursively is not None
+        ):
+            receiver_type = context.infer_recursively(value.func.value)
+            dict_types = dict_type_args(receiver_type)
+            if receiver_type == "*mgen.PyDict":
+                return "[]mgen.PyDictEntry" if value.func.attr == "items" else "[]interface{}"
+            if dict_types is not None:
+                key_type, value_type = dict_types
+                return {
+                    "items": f"[]mgen.KV[{key_type}, {value_type}]",
+                    "keys": f"[]{key_type}",
+                    "values": f"[]{value_type}",
+                }[value.func.attr]
+
+        # d.get(k, default), d.pop(k), d.setdefault(k, v), d.popitem() and d.copy() on a typed dict
+        if isinstance(value.func, ast.Attribute) and context.infer_recursively is not None:
+            receiver_type = context.infer_recursively(value.func.value)
+            if receiver_type == "*mgen.PyDict" and value.func.attr in ("popitem", "copy"):
+                return "mgen.PyDictEntry" if value.func.attr == "popitem" else receiver_type
+            dict_types = dict_type_args(receiver_type)
+            if dict_types is not None and value.func.attr in ("get", "pop", "setdefault"):
+                return dict_types[1]
+            if dict_types is not None and value.func.attr == "copy":
+                return go_dict_type(*dict_types)
+            if dict_types is not None and value.func.attr == "popitem":
+                return f"mgen.KV[{dict_types[0]}, {dict_types[1]}]"
+            # Counter methods
+            if dict_types is not None and value.func.attr == "most_common":
+                return f"[]mgen.Pair[{dict_types[0]}, int]"
+            if dict_types is not None and value.func.attr == "elements":
+                return f"[]{dict_types[0]}"
+            if dict_types is not None and value.func.attr == "total":
+                return "int"
+
+        # Set methods on a typed set: the algebra returns a set, the relations a bool
+        if isinstance(value.func, ast.Attribute) and context.infer_recursively is not None:
+            receiver_type = context.infer_recursively(value.func.value)
+            element_type = set_type_arg(receiver_type)
+            if element_type is not None and value.func.attr in SET_RESULT_METHODS:
+                return receiver_type
+            if element_type is not None and value.func.attr in ("issubset", "issuperset", "isdisjoint"):
+                return "bool"
+            if element_type is not None and value.func.attr == "pop":
+                return element_type
+
+        # xs.pop(...) and d.popleft() return an element of the container
+        if (
+            isinstance(value.func, ast.Attribute)
+            and value.func.attr in ("pop", "popleft")
+            and context.infer_recursively is not None
+        ):
+            receiver_type = context.infer_recursively(value.func.value)
+            if receiver_type.startswith("[]"):
+                return receiver_type[2:]
+            if receiver_type.startswith("*mgen.Deque["):
+                return receiver_type[len("*mgen.Deque[") : -1]
+            if receiver_type == "*mgen.PyByteArray":
+                return "int"
+
+        # xs.copy() is a list of the same type
+        if (
+            isinstance(value.func, ast.Attribute)
+            and value.func.attr == "copy"
+            and not value.args
+            and context.infer_recursively is not None
+        ):
+            receiver_type = context.infer_recursively(value.func.value)
+            if receiver_type.startswith("[]"):
+                return receiver_type
+
+        # argparse: the parser and the namespace parse_args() returns
+        if (
+            isinstance(value.func, ast.Attribute)
+            and value.func.attr == "ArgumentParser"
+            and isinstance(value.func.value, ast.Name)
+            and value.func.value.id == "argparse"
+        ) or (
+            isinstance(value.func, ast.Name)
+            and value.func.id == "ArgumentParser"
+            and value.func.id not in self.function_return_types
+            and value.func.id not in self.struct_info
+        ):
+            return "*mgen.ArgumentParser"
+        if (
+            isinstance(value.func, ast.Attribute)
+            and value.func.attr == "parse_args"
+            and context.infer_recursively is not None
+            and context.infer_recursively(value.func.value) == "*mgen.ArgumentParser"
+        ):
+            return "*mgen.Namespace"
+
+        # string.Template and the text its substitutions produce
+        if (
+            isinstance(value.func, ast.Attribute)
+            and value.func.attr == "Template"
+            and isinstance(value.func.value, ast.Name)
+            and value.func.value.id == "string"
+        ) or (
+            isinstance(value.func, ast.Name)
+            and value.func.id == "Template"
+            and value.func.id not in self.function_return_types
+            and value.func.id not in self.struct_info
+        ):
+            return "mgen.Template"
+        if (
+            isinstance(value.func, ast.Attribute)
+            and value.func.attr in ("substitute", "safe_substitute")
+            and context.infer_recursively is not None
+            and context.infer_recursively(value.func.value) == "mgen.Template"
+        ):
+            return "string"
+
+        if (
+            isinstance(value.func, ast.Name)
+            and value.func.id in ("map", "filter")
+            and value.func.id not in self.function_return_types
+            and self.map_filter_inferrer is not None
+        ):
+            return self.map_filter_inferrer(value)
+
+        functools_type = self.functools_inferrer(value) if self.functools_inferrer is not None else None
+        if functools_type is not None:
+            return functools_type
+        generic_type = self.generic_inferrer(value) if self.generic_inferrer is not None else None
+        if generic_type is not None:
+            return generic_type
+
+        # Class.f() / cls.f() for classmethods and staticmethods
+        if isinstance(value.func, ast.Attribute) and isinstance(value.func.value, ast.Name):
+            owner = self.class_aliases.get(value.func.value.id, value.func.value.id)
+            function = self.struct_info.get(owner, {}).get("class_functions", {}).get(value.func.attr)
+            if function is not None:
+                return function["return_type"]
+
+        return super().infer(value, context)
 
     def _infer_from_function(self, func_name: str, context: InferenceContext) -> str:
         """Infer return type from function name (Go specific)."""
@@ -158,31 +781,349 @@ class GoCallInferenceStrategy(CallInferenceStrategy):
         if func_name in self.function_return_types:
             return self.function_return_types[func_name]
 
-        # Check struct constructors
+        # Calls through a func-typed variable, such as a nested function or a returned closure
+        func_type = context.variable_types.get(func_name, "")
+        if func_type.startswith("func("):
+            return func_result_type(func_type)
+
+        # Check struct constructors, including cls(...) in a classmethod
+        func_name = self.class_aliases.get(func_name, func_name)
         if func_name in self.struct_info:
             return func_name
 
         # Standard built-ins
-        if func_name == "sum":
+        if func_name in ("sum", "int", "ord", "hash"):
             return "int"
+        elif func_name == "float":
+            return "float64"
+        elif func_name in ("repr", "ascii", "format", "input", "pformat", "chr"):
+            return "string"
+        elif func_name == "open":
+            return "*mgen.PyFile"
         else:
             return "interface{}"  # Go's default for unknown types
 
     def _infer_from_method(self, method_name: str, context: InferenceContext) -> str:
         """Infer return type from method name (Go specific)."""
         # String methods
-        if method_name in ["upper", "lower", "strip", "replace", "join"]:
+        if method_name in [
+            "upper", "lower", "strip", "replace", "join", "ljust", "rjust", "center", "expandtabs",
+            "title", "capitalize", "zfill", "format", "format_map", "decode", "hex",
+        ]:
             return "string"
-        # String split
-        elif method_name == "split":
+        # Encoding a string makes bytes
+        elif method_name == "encode":
+            return BYTES_TYPES[0]
+        # String predicates
+        elif method_name in [
+            "startswith", "endswith", "isdigit", "isalpha", "isalnum", "isspace", "isupper", "islower",
+        ]:
+            return "bool"
+        # String split and line splitting (str.splitlines / file.readlines)
+        elif method_name in ["split", "splitlines", "readlines", "partition", "rpartition"]:
             return "[]string"
+        # File reads
+        elif method_name in ["read", "readline"]:
+            return "string"
         # Search methods
-        elif method_name == "find":
+        elif method_name in ["find", "rfind", "index", "rindex", "count"]:
             return "int"
         else:
             return "interface{}"  # Go's default
 
 
+def is_type_name(value: ast.expr) -> bool:
+    """Return whether value is type(x).__name__, the class name of x."""
+    return (
+        isinstance(value, ast.Attribute)
+        and value.attr == "__name__"
+        and isinstance(value.value, ast.Call)
+        and isinstance(value.value.func, ast.Name)
+        and value.value.func.id == "type"
+        and len(value.value.args) == 1
+        and not value.value.keywords
+    )
+
+
+class GoTypeNameInferenceStrategy(TypeInferenceStrategy):
+    """Type of type(x).__name__, a class name such as "KeyError"."""
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return is_type_name(value)
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        return "string"
+
+
+class GoFieldInferenceStrategy(TypeInferenceStrategy):
+    """A field or property of a generated class (self.items, p.x) has the type its class declares."""
+
+    def __init__(self, field_type: Callable[[ast.Attribute], Optional[str]]) -> None:
+        """Initialize with the converter's lookup of a class field's Go type."""
+        self.field_type = field_type
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.Attribute) and self.field_type(value) is not None
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        assert isinstance(value, ast.Attribute), "Expected ast.Attribute"
+        return self.field_type(value) or context.type_mapper("Any")
+
+
+class GoNamespaceInferenceStrategy(TypeInferenceStrategy):
+    """Type of args.name on the namespace argparse's parse_args() returns, and of a hash object's attributes."""
+
+    def __init__(self, argument_types: Optional[dict[str, str]] = None) -> None:
+        """Initialize with the converter's argparse dest -> Go type table."""
+        self.argument_types = argument_types if argument_types is not None else {}
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.Attribute)
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        assert isinstance(value, ast.Attribute), "Expected ast.Attribute"
+        if context.infer_recursively is not None and context.infer_recursively(value.value) == "*mgen.Namespace":
+            return self.argument_types.get(value.attr, "interface{}")
+        if context.infer_recursively is not None and context.infer_recursively(value.value) == "*mgen.Hash":
+            return HASH_MEMBER_TYPES.get(value.attr, "interface{}")
+        return context.type_mapper("Any")
+
+
+# Item types of the comparable mgen.Tuple2/Tuple3 used for tuple keys
+TUPLE_ITEM_TYPES = ("int", "float64", "string", "bool")
+
+
+def go_tuple_type(item_types: list[str]) -> Optional[str]:
+    """Return the mgen.Tuple2/Tuple3 type of a tuple with the given item types, or None.
+
+    Only 2- and 3-tuples of scalars have a comparable struct type; other
+    tuples stay []interface{}.
+    """
+    if len(item_types) not in (2, 3) or any(item_type not in TUPLE_ITEM_TYPES for item_type in item_types):
+        return None
+    return f"mgen.Tuple{len(item_types)}[{', '.join(item_types)}]"
+
+
+def tuple_type_args(go_type: str) -> Optional[list[str]]:
+    """Return the item types of a "mgen.Tuple2[A, B]" or "mgen.Tuple3[A, B, C]" type, or None for other types."""
+    for arity in (2, 3):
+        prefix = f"mgen.Tuple{arity}["
+        if go_type.startswith(prefix) and go_type.endswith("]"):
+            return [item_type.strip() for item_type in go_type[len(prefix) : -1].split(",")]
+    return None
+
+
+def constant_index(index: ast.expr) -> Optional[int]:
+    """Return the value of an integer index written as a literal (1 or -1), or None."""
+    sign = 1
+    if isinstance(index, ast.UnaryOp) and isinstance(index.op, ast.USub):
+        sign, index = -1, index.operand
+    if isinstance(index, ast.Constant) and isinstance(index.value, int) and not isinstance(index.value, bool):
+        return sign * index.value
+    return None
+
+
+def tuple_key_type(keys: list[ast.expr], infer: Callable[[ast.expr], str]) -> Optional[str]:
+    """Return the Tuple2/Tuple3 type shared by dict keys or set members, or None.
+
+    Each key must be a tuple display such as (x, y) or a value of that type,
+    and the item types must agree position by position: {(0, 0), (1, 2)}
+    gives mgen.Tuple2[int, int], while {(1, 2), (1.5, 2)} needs a PySet.
+    """
+    key_types = {
+        go_tuple_type([infer(item) for item in key.elts]) if isinstance(key, ast.Tuple) else infer(key) for key in keys
+    }
+    if len(key_types) != 1:
+        return None
+    key_type = key_types.pop()
+    return key_type if key_type is not None and tuple_type_args(key_type) is not None else None
+
+
+def needs_value_hashing(keys: list[ast.expr], infer: Callable[[ast.expr], str]) -> bool:
+    """Whether dict keys or set members need a value-hashed PyDict/PySet rather than a Go map.
+
+    Tuples that tuple_key_type cannot type and bytes are slices in Go, which
+    cannot be map keys. Mixed numeric kinds ({1: "a", 1.0: "b", True: "c"}) are one
+    key in Python but distinct interface{} keys in Go, so they go through
+    HashKey as well.
+    """
+    if any(isinstance(key, ast.Tuple) or infer(key) in BYTES_TYPES for key in keys):
+        return True
+    numeric_kinds = {infer(key) for key in keys} & {"int", "float64", "bool"}
+    return len(numeric_kinds) > 1
+
+
+def numeric_builtin_type(func_name: str, arg_types: list[str]) -> str:
+    """Return the result type of min/max/abs/sum for the given argument types.
+
+    This follows the converter's lowering: typed arguments give a typed
+    result, and anything the dynamic fallbacks handle gives interface{}.
+    """
+    if func_name == "abs":
+        return arg_types[0] if arg_types[0] in ("int", "float64") else "interface{}"
+    if func_name == "sum":
+        iterated = iterated_slice_type(arg_types[0])
+        elem_type = iterated[2:] if iterated.startswith("[]") else "int"
+        elem_type = "int" if elem_type == "bool" else elem_type
+        if elem_type not in ("int", "float64") or arg_types[1:] not in ([], [elem_type]):
+            return "interface{}"
+        return elem_type
+    if len(arg_types) > 1:
+        # min(a, b, ...) of one ordered type uses Go's builtin min/max
+        same_type = all(arg_type == arg_types[0] for arg_type in arg_types)
+        return arg_types[0] if same_type and arg_types[0] in ("int", "float64", "string") else "interface{}"
+    # min(d) and max(d) compare the keys, min(s) the members
+    iterated = iterated_slice_type(arg_types[0])
+    return iterated[2:] if iterated.startswith("[]") else "int"
+
+
+def go_dict_type(key_type: str, value_type: str) -> str:
+    """Return the Go type of a dict with the given key and value types."""
+    return f"*mgen.Dict[{key_type}, {value_type}]"
+
+
+def dict_type_args(go_type: str) -> Optional[tuple[str, str]]:
+    """Split a typed dict type "*mgen.Dict[K, V]" into (K, V), or return None for other types."""
+    if not (go_type.startswith("*mgen.Dict[") and go_type.endswith("]")):
+        return None
+    type_args = go_type[len("*mgen.Dict[") : -1]
+    depth = 0
+    for i, char in enumerate(type_args):
+        if char in "[({":
+            depth += 1
+        elif char in "])}":
+            depth -= 1
+        elif char == "," and depth == 0:
+            return type_args[:i].strip(), type_args[i + 1 :].strip()
+    return None
+
+
+def go_set_type(element_type: str) -> str:
+    """Return the Go type of a set with the given member type."""
+    return f"*mgen.Set[{element_type}]"
+
+
+def set_type_arg(go_type: str) -> Optional[str]:
+    """Return the member type T of a typed set type "*mgen.Set[T]", or None for other types."""
+    if go_type.startswith("*mgen.Set[") and go_type.endswith("]"):
+        return go_type[len("*mgen.Set[") : -1]
+    return None
+
+
+def iterated_slice_type(go_type: str) -> str:
+    """Return the type of the slice a loop over go_type walks.
+
+    Dicts iterate their keys (d.Keys()), sets and deques their items
+    (s.Items()), iterators their collected items (mgen.Collect(it)), bytes their byte
+    values (b.Ints()) and strs their characters (mgen.StrOps.Chars(s)); any
+    other type is returned unchanged.
+    """
+    if go_type in BYTES_TYPES:
+        return "[]int"
+    if go_type == "string":
+        return "[]string"
+    dict_types = dict_type_args(go_type)
+    if dict_types is not None:
+        return f"[]{dict_types[0]}"
+    element_type = set_type_arg(go_type) or deque_type_arg(go_type) or iterator_type_arg(go_type)
+    if element_type is not None:
+        return f"[]{element_type}"
+    return go_type
+
+
+def deque_type_arg(go_type: str) -> Optional[str]:
+    """Return the item type of "*mgen.Deque[T]", or None for other types."""
+    if go_type.startswith("*mgen.Deque[") and go_type.endswith("]"):
+        return go_type[len("*mgen.Deque[") : -1]
+    return None
+
+
+def iterator_type_arg(go_type: str) -> Optional[str]:
+    """Return the item type of "mgen.Iterator[T]" (generators, iter()), or None for other types."""
+    if go_type.startswith("mgen.Iterator[") and go_type.endswith("]"):
+        return go_type[len("mgen.Iterator[") : -1]
+    return None
+
+
+def func_result_type(func_type: str) -> str:
+    """Return the result type of a Go func type: int for func(a int, f func() int) int."""
+    depth = 0
+    for i, char in enumerate(func_type):
+        if char == "(":
+            depth += 1
+        elif char == ")":
+            depth -= 1
+            if depth == 0:
+                return func_type[i + 1 :].strip() or "interface{}"
+    return "interface{}"
+
+
+def _type_tokens(go_type: str) -> list[re.Match[str]]:
+    """Split a Go type into names and punctuation: []func(T) *mgen.Set[T] -> [, ], func, (, T, ), *, mgen.Set, ..."""
+    return list(re.finditer(r"interface\{\}|[\w.]+|\S", go_type))
+
+
+def bind_type_params(pattern: str, actual: str, type_params: list[str], bindings: dict[str, str]) -> bool:
+    """Match the Go type actual against pattern, a type mentioning type_params, adding what each stands for.
+
+    Returns False when the types do not match or a type parameter would
+    stand for two types. For example binding []T to []int adds T: int and
+    *mgen.Dict[K, V] to *mgen.Dict[string, []int] adds K: string, V: []int.
+    """
+    given = _type_tokens(actual)
+    position = 0
+    for token in (match.group() for match in _type_tokens(pattern)):
+        if token not in type_params:
+            if position >= len(given) or given[position].group() != token:
+                return False
+            position += 1
+            continue
+        # A type parameter stands for the whole type up to the next , ] or ) outside brackets
+        start = position
+        depth = 0
+        while position < len(given) and not (depth == 0 and given[position].group() in ",])"):
+            depth += {"[": 1, "(": 1, "]": -1, ")": -1}.get(given[position].group(), 0)
+            position += 1
+        if position == start:
+            return False
+        bound = actual[given[start].start() : given[position - 1].end()]
+        if bindings.setdefault(token, bound) != bound:
+            return False
+    return position == len(given)
+
+
+def substitute_type_params(go_type: str, bindings: dict[str, str]) -> str:
+    """Replace the type parameters in go_type by the types bindings gives them: []T -> []int for T: int."""
+    if not bindings:
+        return go_type
+    names = "|".join(re.escape(name) for name in bindings)
+    return re.sub(rf"(?<![\w.])({names})(?![\w.])", lambda match: bindings[match.group(1)], go_type)
+
+
+class GoSequenceOperatorInferenceStrategy(TypeInferenceStrategy):
+    """Concatenating (b + other) or repeating (b * n) bytes, a bytearray or a list keeps its type."""
+
+    def can_infer(self, value: ast.expr) -> bool:
+        return isinstance(value, ast.BinOp) and isinstance(value.op, (ast.Add, ast.Mult))
+
+    def infer(self, value: ast.expr, context: InferenceContext) -> str:
+        assert isinstance(value, ast.BinOp), "Expected ast.BinOp"
+        assert context.infer_recursively is not None
+        left_type = context.infer_recursively(value.left)
+        right_type = context.infer_recursively(value.right)
+        if left_type in BYTES_TYPES:
+            return left_type
+        if isinstance(value.op, ast.Mult) and right_type in BYTES_TYPES:
+            return right_type
+        if isinstance(value.op, ast.Add) and left_type.startswith("[]") and left_type == right_type:
+            return left_type
+        if isinstance(value.op, ast.Mult):
+            for sequence_type, count_type in ((left_type, right_type), (right_type, left_type)):
+                if sequence_type.startswith("[]") and count_type in ("int", "bool"):
+                    return sequence_type
+        return context.type_mapper("Any")
+
+
 def create_go_type_inference_engine(
     converter: "MGenPythonToGoConverter",  # type: ignore[name-defined]
 ) -> "TypeInferenceEngine":
@@ -197,29 +1138,63 @@ def create_go_type_inference_engine(
     from ..type_inference_strategies import ConstantInferenceStrategy, NameInferenceStrategy, TypeInferenceEngine
 
     strategies = [
+        GoBigIntInferenceStrategy(big_int_inferrer=converter._is_big_int_expr),
+        GoOperatorMethodInferenceStrategy(operator_method_type=converter._operator_method_type),
         ConstantInferenceStrategy(),
         NameInferenceStrategy(),
         GoListInferenceStrategy(),
         GoDictInferenceStrategy(),
         GoSetInferenceStrategy(),
+        GoSliceInferenceStrategy(),
+        GoTupleIndexInferenceStrategy(record_field_types=converter._record_field_types),
+        GoLambdaInferenceStrategy(lambda_types=converter.lambda_types),
+        GoPercentFormatInferenceStrategy(),
+        GoFloorDivInferenceStrategy(),
+        GoTrueDivInferenceStrategy(python_version=converter.python_version),
+        GoPowInferenceStrategy(),
+        GoUnaryOpInferenceStrategy(),
+        GoSetOperatorInferenceStrategy(),
+        GoSequenceOperatorInferenceStrategy(),
         GoComprehensionInferenceStrategy(
-            loop_var_type_inferrer=converter._infer_loop_variable_type,
+            loop_var_type_inferrer=converter._comprehension_loop_types,
             element_type_inferrer=converter._infer_comprehension_element_type,
         ),
         GoCallInferenceStrategy(
             function_return_types=converter.function_return_types,
             struct_info=converter.struct_info,
+            class_aliases=converter.class_aliases,
+            map_filter_inferrer=converter._map_filter_type,
+            functools_inferrer=converter._functools_type,
+            generic_inferrer=converter._generic_call_type,
         ),
+        GoTypeNameInferenceStrategy(),
+        GoFieldInferenceStrategy(field_type=converter._field_type),
+        GoNamespaceInferenceStrategy(argument_types=converter.argument_types),
     ]
 
     return TypeInferenceEngine(strategies)
 
 
 __all__ = [
+    "GoBigIntInferenceStrategy",
+    "GoOperatorMethodInferenceStrategy",
     "GoListInferenceStrategy",
     "GoDictInferenceStrategy",
     "GoSetInferenceStrategy",
+    "GoSliceInferenceStrategy",
+    "GoTupleIndexInferenceStrategy",
+    "GoLambdaInferenceStrategy",
+    "GoPercentFormatInferenceStrategy",
+    "GoFloorDivInferenceStrategy",
+    "GoTrueDivInferenceStrategy",
+    "GoPowInferenceStrategy",
+    "GoUnaryOpInferenceStrategy",
+    "GoSetOperatorInferenceStrategy",
+    "GoSequenceOperatorInferenceStrategy",
     "GoComprehensionInferenceStrategy",
     "GoCallInferenceStrategy",
+    "GoTypeNameInferenceStrategy",
+    "GoFieldInferenceStrategy",
+    "GoNamespaceInferenceStrategy",
     "create_go_type_inference_engine",
 ]
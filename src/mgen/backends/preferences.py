@@ -160,13 +160,19 @@ class GoPreferences(BackendPreferences):
             {
                 # Language version preferences
                 "go_version": "1.21",  # Minimum Go version
+                "python_version": 3,  # Source dialect: 2 accepts print statements and floors int / int
+                "int_precision": "native",  # native: wrapping Go int; auto: mgen.PyInt where ints may outgrow int64
+                "string_semantics": "codepoint",  # codepoint: len/index/slice/iterate like Python; bytes: by byte
                 "use_generics": True,  # Go 1.18+ generics
                 # Package and module preferences
+                "module_path": "mgenproject",  # go.mod module path; the runtime is imported as <module_path>/mgen
+                "package_name": "main",  # main: a program; any other name: a library package
                 "module_structure": "single",  # single, multi-package
                 "package_naming": "lowercase",  # lowercase, descriptive
                 "use_internal_packages": False,  # internal/ directory structure
                 # Concurrency preferences
                 "use_goroutines": False,  # Concurrent processing
+                "generators": "goroutine",  # goroutine: lazy, a goroutine each; eager: body runs on the first next()
                 "channel_strategy": "minimal",  # minimal, explicit, buffered
                 "context_usage": "explicit",  # explicit, implicit, none
                 # Error handling preferences
@@ -181,6 +187,7 @@ class GoPreferences(BackendPreferences):
                 "naming_convention": "go_style",  # go_style, camelCase, preserved
                 "use_short_names": True,  # Go-style short variable names
                 "explicit_interfaces": False,  # Explicit interface definitions
+                "format_code": True,  # Drop unused imports and run goimports (or gofmt) over the output
                 # Standard library preferences
                 "prefer_standard_lib": True,  # Use standard library over runtime
                 "use_fmt_package": True,  # fmt package for output
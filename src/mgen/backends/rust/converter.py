@@ -165,6 +165,8 @@ class MGenPythonToRustConverter:
     def _convert_class(self, node: ast.ClassDef) -> str:
         """Convert Python class to Rust struct with associated functions."""
         class_name = node.name
+        if any(ast.unparse(d).startswith(("dataclass", "dataclasses.dataclass")) for d in node.decorator_list):
+            raise UnsupportedFeatureError(f"Dataclasses are not supported in the Rust backend: {class_name}")
 
         # Find __init__ method and other methods
         init_method = None
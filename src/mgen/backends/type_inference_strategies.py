@@ -92,6 +92,8 @@ class ConstantInferenceStrategy(TypeInferenceStrategy):
             return context.type_mapper("float")
         elif isinstance(value.value, str):
             return context.type_mapper("str")
+        elif isinstance(value.value, bytes):
+            return context.type_mapper("bytes")
         elif value.value is None:
             return context.type_mapper("None")
         else:
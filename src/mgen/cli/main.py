@@ -19,6 +19,7 @@ import sys
 from pathlib import Path
 from typing import Optional, Union
 
+from ..backends.go.packages import package_directory
 from ..backends.preferences import BackendPreferences, PreferencesRegistry
 from ..backends.registry import registry
 from ..common import log
@@ -65,6 +66,9 @@ Examples:
   # With backend preferences
   mgen convert -t haskell app.py --prefer use_native_comprehensions=true
 
+  # As a library other code imports (Go: package shapes, without a main function)
+  mgen convert -t go shapes.py --lib
+
   # With progress and verbose output
   mgen convert -t rust app.py --progress   # Show progress bar during conversion
   mgen convert -t rust app.py -v           # Verbose output (detailed logging)
@@ -126,7 +130,7 @@ For more information, visit: https://github.com/your-org/mgen
         convert_parser.add_argument(
             "-t", "--to", type=str, default="c", help=f"Target language (default: c, available: {backends_str})"
         )
-        convert_parser.add_argument("input_files", nargs="+", help="Python file(s) to convert")
+        convert_parser.add_argument("input_files", nargs="+", help="Python file(s) or project directories to convert")
         convert_parser.add_argument(
             "-O",
             "--optimization",
@@ -141,6 +145,11 @@ For more information, visit: https://github.com/your-org/mgen
             metavar="KEY=VALUE",
             help="Set backend preferences (e.g., --prefer use_native_comprehensions=true)",
         )
+        convert_parser.add_argument(
+            "--lib",
+            action="store_true",
+            help="Convert each file to a library package named after it, without a main function (go only)",
+        )
         convert_parser.add_argument(
             "--dry-run", action="store_true", help="Show what would be generated without actually writing files"
         )
@@ -351,7 +360,8 @@ For more information, visit: https://github.com/your-org/mgen
                         self.log.debug(f"Copied C runtime file: {filename}")
 
         # For other languages, runtime libraries are typically handled by the language ecosystem
-        # (e.g., Cargo for Rust, go mod for Go, standard library for C++)
+        # (e.g., Cargo for Rust, standard library for C++); the Go builder writes go.mod and
+        # copies its runtime beside the generated source itself
 
     def convert_command(self, args: argparse.Namespace) -> int:
         """Execute convert command."""
@@ -370,6 +380,10 @@ For more information, visit: https://github.com/your-org/mgen
 
         # Parse backend preferences
         preferences = self.parse_preferences(target, getattr(args, "prefer", None))
+        library = getattr(args, "lib", False)
+        if library and target != "go":
+            self.log.error(f"--lib is not supported for target '{target}'")
+            return 1
 
         if self.verbose and preferences:
             self.log.info(f"Backend preferences: {preferences}")
@@ -431,6 +445,9 @@ For more information, visit: https://github.com/your-org/mgen
 
                     # Configure pipeline with progress callback
                     config.progress_callback = progress_callback
+                    if library:
+                        # A library package is named after its file: shapes.py -> package shapes
+                        preferences.set("package_name", package_directory(input_path.stem))
 
                     # Run multi-language pipeline
                     pipeline = MGenPipeline(config)
@@ -5,19 +5,21 @@ in the Python-to-multi-language translation system.
 
 Current Support:
 - Local module imports (import mymodule, from mymodule import function)
+- Packages: dotted imports (import pkg.mod), relative imports (from .mod import f) and
+  discovery of every module under a package directory
 - Basic standard library modules (math)
 - Cross-module function resolution
+- Module dependency analysis: compilation order, with circular imports reported
 
 Future Enhancements:
 - Full standard library support
-- Module dependency analysis
-- Circular dependency detection
 """
 
 import ast
 from pathlib import Path
 from typing import Optional
 
+from ..errors import CircularImportError, SourceLocation
 from . import log
 
 
@@ -27,10 +29,13 @@ class ModuleInfo:
     def __init__(self, name: str, path: Path):
         self.name = name
         self.path = path
-        self.functions: dict[str, ast.FunctionDef] = {}
+        self.is_package = path.name == "__init__.py"  # Relative imports resolve against the package itself
+        self.functions: dict[str, ast.FunctionDef] = {}  # Top-level functions
+        self.classes: dict[str, ast.ClassDef] = {}  # Top-level classes
         self.imports: list[str] = []
         self.ast_module: Optional[ast.Module] = None
-        self.dependencies: set[str] = set()
+        self.dependencies: set[str] = set()  # Project modules this module imports
+        self.import_nodes: dict[str, ast.stmt] = {}  # Dependency -> first import statement naming it
 
 
 class StandardLibraryModule:
@@ -103,55 +108,122 @@ class ModuleResolver:
             self.log.warning(f"Module not found: {module_name}")
             return None
 
-        # Parse and analyze the module
+        # Parse and analyze the module (a SyntaxError is left for the caller to report)
         try:
             with open(module_file, encoding="utf-8") as f:
                 source_code = f.read()
+        except OSError as e:
+            self.log.error(f"Failed to analyze module {module_name}: {e}")
+            return None
 
-            ast_module = ast.parse(source_code, filename=str(module_file))
-            module_info = ModuleInfo(module_name, module_file)
-            module_info.ast_module = ast_module
+        ast_module = ast.parse(source_code, filename=str(module_file))
+        module_info = ModuleInfo(module_name, module_file)
+        module_info.ast_module = ast_module
 
-            # Extract functions and imports
-            self._analyze_module(module_info, ast_module)
+        # Registered before its imports are followed, so a module importing it back finds it
+        self.discovered_modules[module_name] = module_info
 
-            self.discovered_modules[module_name] = module_info
-            self.log.info(f"Discovered module: {module_name} with {len(module_info.functions)} functions")
-            return module_info
+        # Extract functions and imports
+        self._analyze_module(module_info, ast_module)
 
-        except Exception as e:
-            self.log.error(f"Failed to analyze module {module_name}: {e}")
-            return None
+        self.log.info(f"Discovered module: {module_name} with {len(module_info.functions)} functions")
+        return module_info
+
+    def discover_package(self, root: Path) -> list[str]:
+        """Discover every module under a project directory, returning their names.
+
+        A directory with an __init__.py is a package importable by its own name
+        (its modules are root.name.x); any other directory is a source root
+        whose modules are named by their path below it, as when running a
+        script from it.
+        """
+        root = root.resolve()
+        base = root.parent if (root / "__init__.py").exists() else root
+        self.add_search_path(base)
+        names = []
+        for path in sorted(root.rglob("*.py")):
+            parts = list(path.relative_to(base).with_suffix("").parts)
+            if parts[-1] == "__init__":
+                parts.pop()
+            if not parts or "__pycache__" in parts or not all(part.isidentifier() for part in parts):
+                continue  # Not a project module (e.g. in __pycache__, or in a my-scripts directory)
+            name = ".".join(parts)
+            if self.discover_module(name) is not None:
+                names.append(name)
+        return names
 
     def _find_module_file(self, module_name: str) -> Optional[Path]:
-        """Find the Python file for a given module name."""
+        """Find the Python file for a given (possibly dotted) module name."""
+        relative = Path(*module_name.split("."))
         # Try each search path
         for search_path in self.current_search_paths:
             # Try module_name.py
-            module_file = search_path / f"{module_name}.py"
+            module_file = search_path / relative.with_suffix(".py")
             if module_file.exists():
                 return module_file
 
             # Try module_name/__init__.py
-            package_init = search_path / module_name / "__init__.py"
+            package_init = search_path / relative / "__init__.py"
             if package_init.exists():
                 return package_init
 
         return None
 
     def _analyze_module(self, module_info: ModuleInfo, ast_module: ast.Module) -> None:
-        """Analyze a module's AST to extract functions and imports."""
+        """Analyze a module's AST to extract its top-level definitions and its imports.
+
+        Imports are recorded by absolute module name; those naming project
+        modules are its dependencies, which are discovered in turn. Importing
+        pkg.mod also imports pkg, and from pkg import mod imports pkg.mod.
+        """
+        for stmt in ast_module.body:
+            if isinstance(stmt, ast.FunctionDef):
+                module_info.functions[stmt.name] = stmt
+            elif isinstance(stmt, ast.ClassDef):
+                module_info.classes[stmt.name] = stmt
+
         for node in ast.walk(ast_module):
-            if isinstance(node, ast.FunctionDef):
-                module_info.functions[node.name] = node
-            elif isinstance(node, ast.Import):
+            imported: list[str] = []
+            if isinstance(node, ast.Import):
                 for alias in node.names:
                     module_info.imports.append(alias.name)
-                    module_info.dependencies.add(alias.name)
+                    parts = alias.name.split(".")
+                    imported.extend(".".join(parts[: index + 1]) for index in range(len(parts)))
             elif isinstance(node, ast.ImportFrom):
-                if node.module:
-                    module_info.imports.append(node.module)
-                    module_info.dependencies.add(node.module)
+                module_name = self.resolve_from_module(module_info, node)
+                if module_name:
+                    module_info.imports.append(module_name)
+                    parts = module_name.split(".")
+                    imported.extend(".".join(parts[: index + 1]) for index in range(len(parts)))
+                    imported.extend(f"{module_name}.{alias.name}" for alias in node.names if alias.name != "*")
+            for name in imported:
+                if name != module_info.name and self._is_project_module(name):
+                    module_info.dependencies.add(name)
+                    module_info.import_nodes.setdefault(name, node)  # type: ignore[arg-type]
+
+        for dependency in sorted(module_info.dependencies):
+            self.discover_module(dependency)
+
+    def _is_project_module(self, module_name: str) -> bool:
+        """Report whether module_name is a module on the search paths rather than the standard library."""
+        return module_name in self.discovered_modules or self._find_module_file(module_name) is not None
+
+    def resolve_from_module(self, module_info: ModuleInfo, node: ast.ImportFrom) -> Optional[str]:
+        """Return the absolute name of the module a from-import in module_info names.
+
+        Relative imports resolve against the importing module's package:
+        from .shapes import area in pkg.geometry names pkg.shapes. A relative
+        import reaching above the top-level package names nothing (None).
+        """
+        if node.level == 0:
+            return node.module
+        package = module_info.name.split(".")
+        if not module_info.is_package:
+            package.pop()
+        if len(package) < node.level:
+            return None
+        package = package[: len(package) - node.level + 1]
+        return ".".join([*package, node.module] if node.module else package)
 
     def resolve_import(self, import_node: ast.Import) -> list[tuple[str, Optional[ModuleInfo]]]:
         """Resolve an import statement to module information."""
@@ -192,31 +264,52 @@ class ModuleResolver:
         return None
 
     def get_compilation_order(self) -> list[str]:
-        """Get the order in which modules should be compiled (dependency-first)."""
-        # Simple topological sort for module dependencies
-        visited = set()
-        result = []
+        """Get the order in which modules should be compiled (dependency-first).
+
+        Modules are visited by name, so the order is deterministic.
+
+        Raises:
+            CircularImportError: if modules import each other in a cycle, naming the
+                modules in it and the import statement that closes it
+        """
+        visited: set[str] = set()
+        result: list[str] = []
+        path: list[str] = []  # Modules being visited, each importing the next
 
         def visit(module_name: str) -> None:
+            if module_name in path:
+                self._raise_circular_import(path[path.index(module_name) :] + [module_name])
             if module_name in visited or module_name not in self.discovered_modules:
                 return
 
-            visited.add(module_name)
+            path.append(module_name)
             module_info = self.discovered_modules[module_name]
 
             # Visit dependencies first
-            for dep in module_info.dependencies:
-                if dep in self.discovered_modules:
-                    visit(dep)
+            for dep in sorted(module_info.dependencies):
+                visit(dep)
 
+            path.pop()
+            visited.add(module_name)
             result.append(module_name)
 
         # Visit all discovered modules
-        for module_name in self.discovered_modules:
+        for module_name in sorted(self.discovered_modules):
             visit(module_name)
 
         return result
 
+    def _raise_circular_import(self, cycle: list[str]) -> None:
+        """Raise CircularImportError for cycle (a, b, ..., a), located at the import closing it."""
+        importer = self.discovered_modules[cycle[-2]]
+        node = importer.import_nodes[cycle[-1]]
+        location = SourceLocation.from_ast_node(node, str(importer.path))
+        raise CircularImportError(
+            f"Circular import: {' -> '.join(cycle)} ({location} imports {cycle[-1]})",
+            location=location,
+            suggestion="Move the definitions both modules need into a module that imports neither",
+        )
+
 
 class ImportHandler:
     """Handles import statement processing during Python-to-C conversion."""
@@ -208,6 +208,29 @@ class TypeMappingError(MGenError):
         )
 
 
+class CircularImportError(MGenError):
+    """Raised when project modules import each other in a cycle."""
+
+    def __init__(
+        self,
+        message: str,
+        location: Optional[SourceLocation] = None,
+        suggestion: Optional[str] = None,
+        help_text: Optional[str] = None,
+        error_code: Optional[ErrorCode] = None,
+        source_line: Optional[str] = None,
+    ):
+        """Initialize with default error code."""
+        super().__init__(
+            message=message,
+            location=location,
+            suggestion=suggestion,
+            help_text=help_text,
+            error_code=error_code or ErrorCode.E4002,
+            source_line=source_line,
+        )
+
+
 # Suggestions database for common errors
 ERROR_SUGGESTIONS = {
     "generator": "MGen does not support generator expressions yet. Try using a list comprehension instead.",
@@ -124,6 +124,9 @@ from .subset_validator import FeatureRule, FeatureStatus, StaticPythonSubsetVali
 # Type Inference System
 from .type_inference import InferenceMethod, InferenceResult, TypeConstraint, TypeInferenceEngine
 
+# TypeVar Tracking
+from .type_vars import TypeVarInfo, collect_type_vars, function_type_vars
+
 # Formal Verification
 from .verifiers import (
     AlgorithmProof,
@@ -150,6 +153,10 @@ __all__ = [
     "InferenceResult",
     "InferenceMethod",
     "TypeConstraint",
+    # TypeVar Tracking
+    "TypeVarInfo",
+    "collect_type_vars",
+    "function_type_vars",
     # Immutability Analysis
     "ImmutabilityAnalyzer",
     "MutabilityClass",
@@ -118,6 +118,7 @@ class ASTAnalyzer(ast.NodeVisitor):
         self.result = AnalysisResult()
         self.current_function: Optional[str] = None
         self.current_scope = "global"
+        self.in_main_guard = False  # In the module's if __name__ == "__main__" block
         self.type_hints: dict[str, TypeInfo] = {}
         self.node_types: dict[ast.AST, NodeType] = {}
 
@@ -225,6 +226,12 @@ class ASTAnalyzer(ast.NodeVisitor):
                         var_info.is_modified = True
                         var_info.usage_count = 1
                         self.result.functions[self.current_function].local_variables[var_name] = var_info
+                    elif self.in_main_guard:
+                        # The guarded block runs as the program's main function, so its variables are inferred too
+                        var_info = VariableInfo(name=var_name, type_info=None, scope="__main__")
+                        var_info.is_modified = True
+                        var_info.usage_count = 1
+                        self.result.global_variables[var_name] = var_info
                     else:
                         # Global variables still require explicit annotation
                         self.result.errors.append(
@@ -256,6 +263,13 @@ class ASTAnalyzer(ast.NodeVisitor):
     def visit_If(self, node: ast.If) -> None:
         """Analyze if statements."""
         self.node_types[node] = NodeType.IF_STMT
+        if self.current_function is None and self._is_main_guard(node.test):
+            self.in_main_guard = True
+            try:
+                self.generic_visit(node)
+            finally:
+                self.in_main_guard = False
+            return
         self.generic_visit(node)
 
     def visit_While(self, node: ast.While) -> None:
@@ -288,6 +302,17 @@ class ASTAnalyzer(ast.NodeVisitor):
 
         self.generic_visit(node)
 
+    def _is_main_guard(self, test: ast.expr) -> bool:
+        """Check whether test is __name__ == "__main__"."""
+        return (
+            isinstance(test, ast.Compare)
+            and isinstance(test.left, ast.Name)
+            and test.left.id == "__name__"
+            and len(test.comparators) == 1
+            and isinstance(test.comparators[0], ast.Constant)
+            and test.comparators[0].value == "__main__"
+        )
+
     def _extract_type_info(self, annotation: ast.expr) -> TypeInfo:
         """Extract type information from type annotations."""
         if isinstance(annotation, ast.Name):
@@ -381,6 +381,8 @@ class PythonConstraintChecker:
                 return "float"
             elif isinstance(node.value, str):
                 return "str"
+            elif isinstance(node.value, bytes):
+                return "bytes"
             elif isinstance(node.value, bool):
                 return "bool"
         elif isinstance(node, ast.List):
@@ -142,6 +142,8 @@ class TypeInferenceEngine:
             type_info = TypeInfo("float")
         elif isinstance(value, str):
             type_info = TypeInfo("str")
+        elif isinstance(value, bytes):
+            type_info = TypeInfo("bytes")
         elif value is None:
             type_info = TypeInfo("NoneType")
         else:
@@ -260,6 +262,8 @@ class TypeInferenceEngine:
                 "int": TypeInfo("int"),
                 "float": TypeInfo("float"),
                 "bool": TypeInfo("bool"),
+                "bytes": TypeInfo("bytes"),
+                "bytearray": TypeInfo("bytearray"),
                 "abs": None,  # Depends on argument
                 "max": None,  # Depends on arguments
                 "min": None,  # Depends on arguments
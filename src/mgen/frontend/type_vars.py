@@ -0,0 +1,75 @@
+"""TypeVar tracking for Python code.
+
+Collects the type variables a module declares (T = TypeVar("T")) and finds
+the ones each function is generic over. This is a backend-agnostic analysis -
+backends decide how to express them (e.g. Go uses type parameters with
+constraints, where C would have to monomorphize).
+"""
+
+import ast
+from dataclasses import dataclass
+from typing import Optional
+
+
+@dataclass(frozen=True)
+class TypeVarInfo:
+    """A type variable and the types it may stand for."""
+
+    name: str
+    constraints: tuple[ast.expr, ...] = ()  # TypeVar("N", int, float): exactly one of these
+    bound: Optional[ast.expr] = None  # TypeVar("T", bound=X): X or a subtype of it
+
+
+def collect_type_vars(module: ast.Module) -> dict[str, TypeVarInfo]:
+    """Return the type variables declared at module level, by name.
+
+    Both T = TypeVar("T", ...) and T = typing.TypeVar("T", ...) are seen.
+    """
+    type_vars: dict[str, TypeVarInfo] = {}
+    for stmt in module.body:
+        if not (isinstance(stmt, ast.Assign) and len(stmt.targets) == 1 and isinstance(stmt.targets[0], ast.Name)):
+            continue
+        call = stmt.value
+        if not (isinstance(call, ast.Call) and _is_typing_name(call.func, "TypeVar")):
+            continue
+        name = stmt.targets[0].id
+        bound = next((keyword.value for keyword in call.keywords if keyword.arg == "bound"), None)
+        type_vars[name] = TypeVarInfo(name, tuple(call.args[1:]), bound)
+    return type_vars
+
+
+def function_type_vars(func: ast.FunctionDef, type_vars: dict[str, TypeVarInfo]) -> list[TypeVarInfo]:
+    """Return the type variables func is generic over, in the order its signature first uses them.
+
+    These are its PEP 695 type parameters (def f[T](x: T)) and the module's
+    type variables its parameter and return annotations mention.
+    """
+    found: dict[str, TypeVarInfo] = {}
+    for param in getattr(func, "type_params", []):
+        if type(param).__name__ != "TypeVar":
+            continue
+        bound = param.bound
+        if isinstance(bound, ast.Tuple):
+            found[param.name] = TypeVarInfo(param.name, tuple(bound.elts))
+        else:
+            found[param.name] = TypeVarInfo(param.name, bound=bound)
+
+    args = func.args
+    params = [*args.posonlyargs, *args.args, *args.kwonlyargs]
+    params += [arg for arg in (args.vararg, args.kwarg) if arg is not None]
+    annotations = [param.annotation for param in params if param.annotation is not None]
+    if func.returns is not None:
+        annotations.append(func.returns)
+    for annotation in annotations:
+        names = [node for node in ast.walk(annotation) if isinstance(node, ast.Name) and node.id in type_vars]
+        for node in sorted(names, key=lambda name: (name.lineno, name.col_offset)):
+            found.setdefault(node.id, type_vars[node.id])
+    return list(found.values())
+
+
+def _is_typing_name(func: ast.expr, name: str) -> bool:
+    """Report whether func names the typing module's name, imported (name) or not (typing.name)."""
+    if isinstance(func, ast.Attribute):
+        module = func.value
+        return func.attr == name and isinstance(module, ast.Name) and module.id in ("typing", "typing_extensions")
+    return isinstance(func, ast.Name) and func.id == name
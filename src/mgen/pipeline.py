@@ -19,6 +19,9 @@ Usage:
     pipeline = MGenPipeline(target_language="rust")
     result = pipeline.convert("my_module.py")
 
+    # A whole project: every module under the directory, each after the modules it imports
+    result = pipeline.convert("my_package/")
+
     # With build
     result = pipeline.convert("my_module.py", build_mode=BuildMode.DIRECT)
 """
@@ -33,6 +36,8 @@ from typing import Any, Optional, Union
 from .backends.preferences import BackendPreferences
 from .backends.registry import registry
 from .common import log
+from .common.module_system import ModuleResolver
+from .errors import CircularImportError
 
 # Import frontend analysis components
 try:
@@ -270,7 +275,7 @@ class MGenPipeline:
         """Convert Python module through complete pipeline.
 
         Args:
-            input_path: Path to Python file or module
+            input_path: Path to Python file, or to a project directory (see _convert_package)
             output_path: Output directory or file path
 
         Returns:
@@ -305,46 +310,14 @@ class MGenPipeline:
         try:
             self.log.info(f"Starting pipeline conversion for: {input_path} -> {self.config.target_language}")
 
+            if input_path.is_dir():
+                return self._convert_package(input_path, output_dir, result)
+
             # Read input file
             source_code = input_path.read_text()
 
-            # Phase 1: Validation
-            self.log.debug("Starting validation phase")
-            self._report_progress(PipelinePhase.VALIDATION, "Validating Python code")
-            if not self._validation_phase(source_code, input_path, result):
-                self.log.error("Validation phase failed")
-                return result
-
-            # Phase 2: Analysis
-            self.log.debug("Starting analysis phase")
-            self._report_progress(PipelinePhase.ANALYSIS, "Analyzing AST and types")
-            analysis_result = self._analysis_phase(source_code, result)
-            if analysis_result is None:
-                self.log.error("Analysis phase failed")
-                return result
-
-            # Phase 3: Python Optimization
-            self.log.debug("Starting Python optimization phase")
-            self._report_progress(PipelinePhase.PYTHON_OPTIMIZATION, "Optimizing Python IR")
-            optimized_analysis = self._python_optimization_phase(source_code, analysis_result, result)
-
-            # Phase 4: Mapping (language-agnostic to target-specific)
-            self.log.debug("Starting mapping phase")
-            self._report_progress(PipelinePhase.MAPPING, f"Mapping to {self.config.target_language.upper()}")
-            mapped_result = self._mapping_phase(optimized_analysis, result)
-
-            # Phase 5: Target Optimization
-            self.log.debug("Starting target optimization phase")
-            self._report_progress(
-                PipelinePhase.TARGET_OPTIMIZATION, f"Optimizing {self.config.target_language.upper()} code"
-            )
-            target_optimized = self._target_optimization_phase(mapped_result, result)
-
-            # Phase 6: Generation
-            self.log.debug("Starting generation phase")
-            self._report_progress(PipelinePhase.GENERATION, f"Generating {self.config.target_language.upper()} source")
-            if not self._generation_phase(source_code, target_optimized, output_dir, result):
-                self.log.error("Generation phase failed")
+            # Phases 1-6: Validation through generation
+            if not self._convert_source(source_code, input_path, output_dir, result):
                 return result
 
             # Phase 7: Build
@@ -365,6 +338,119 @@ class MGenPipeline:
             result.errors.append(f"Pipeline error: {str(e)}")
             return result
 
+    def _convert_source(
+        self,
+        source_code: str,
+        input_path: Path,
+        output_dir: Path,
+        result: PipelineResult,
+        module_name: Optional[str] = None,
+        resolver: Optional[ModuleResolver] = None,
+    ) -> bool:
+        """Run phases 1-6 on one Python source, writing the generated code to output_dir.
+
+        module_name and resolver are given when the source is a module of a
+        project converted from a directory (see _convert_package).
+
+        Returns:
+            True if the code was generated
+        """
+        # Phase 1: Validation
+        self.log.debug("Starting validation phase")
+        self._report_progress(PipelinePhase.VALIDATION, "Validating Python code")
+        if not self._validation_phase(source_code, input_path, result):
+            self.log.error("Validation phase failed")
+            return False
+
+        # Phase 2: Analysis
+        self.log.debug("Starting analysis phase")
+        self._report_progress(PipelinePhase.ANALYSIS, "Analyzing AST and types")
+        analysis_result = self._analysis_phase(source_code, result)
+        if analysis_result is None:
+            self.log.error("Analysis phase failed")
+            return False
+
+        # Phase 3: Python Optimization
+        self.log.debug("Starting Python optimization phase")
+        self._report_progress(PipelinePhase.PYTHON_OPTIMIZATION, "Optimizing Python IR")
+        optimized_analysis = self._python_optimization_phase(source_code, analysis_result, result)
+
+        # Phase 4: Mapping (language-agnostic to target-specific)
+        self.log.debug("Starting mapping phase")
+        self._report_progress(PipelinePhase.MAPPING, f"Mapping to {self.config.target_language.upper()}")
+        mapped_result = self._mapping_phase(optimized_analysis, result)
+
+        # Phase 5: Target Optimization
+        self.log.debug("Starting target optimization phase")
+        self._report_progress(
+            PipelinePhase.TARGET_OPTIMIZATION, f"Optimizing {self.config.target_language.upper()} code"
+        )
+        target_optimized = self._target_optimization_phase(mapped_result, result)
+
+        # Phase 6: Generation
+        self.log.debug("Starting generation phase")
+        self._report_progress(PipelinePhase.GENERATION, f"Generating {self.config.target_language.upper()} source")
+        if not self._generation_phase(source_code, target_optimized, output_dir, result, module_name, resolver):
+            self.log.error("Generation phase failed")
+            return False
+        return True
+
+    def _convert_package(self, package_dir: Path, output_dir: Path, result: PipelineResult) -> PipelineResult:
+        """Convert every module of a project directory, each after the project modules it imports.
+
+        The backend decides where each module's source goes (see
+        LanguageBackend.get_module_filename) and how modules refer to each
+        other (see AbstractEmitter.emit_project_module). Circular imports
+        between project modules are reported as errors.
+        """
+        resolver = ModuleResolver()
+        try:
+            resolver.discover_package(package_dir)
+            order = resolver.get_compilation_order()
+        except (CircularImportError, SyntaxError) as e:
+            result.success = False
+            result.errors.append(str(e))
+            return result
+        if not order:
+            result.success = False
+            result.errors.append(f"No Python modules found in {package_dir}")
+            return result
+        if self.config.build_mode == BuildMode.DIRECT:
+            result.success = False
+            result.errors.append("Direct compilation of a package is not supported: convert it, then build the output")
+            return result
+
+        existing = set(output_dir.rglob("*"))
+        for module_name in order:
+            module_path = resolver.discovered_modules[module_name].path
+            self.log.debug(f"Converting module {module_name} ({module_path})")
+            source_code = module_path.read_text()
+            if not self._convert_source(source_code, module_path, output_dir, result, module_name, resolver):
+                result.errors.append(f"Conversion of module {module_name} failed")
+                self._remove_partial_output(output_dir, existing, result)
+                return result
+
+        if self.config.build_mode == BuildMode.MAKEFILE and not self._build_phase(output_dir, result):
+            return result
+
+        self.log.info(f"Pipeline conversion completed successfully for package: {package_dir}")
+        return result
+
+    def _remove_partial_output(self, output_dir: Path, existing: set[Path], result: PipelineResult) -> None:
+        """Remove the files and directories a failed package conversion added to output_dir.
+
+        The modules converted before the failing one are deleted, so no half
+        converted project is left behind; what output_dir held before is kept.
+        """
+        # Reverse order visits a directory's contents before the directory
+        for path in sorted(set(output_dir.rglob("*")) - existing, reverse=True):
+            if path.is_dir():
+                path.rmdir()
+            else:
+                path.unlink()
+        result.output_files.clear()
+        result.generated_files.clear()
+
     def _validation_phase(self, source_code: str, input_path: Path, result: PipelineResult) -> bool:
         """Phase 1: Validate static-python style and translatability."""
         try:
@@ -683,21 +769,35 @@ class MGenPipeline:
             return analysis_result
 
     def _generation_phase(
-        self, source_code: str, analysis_result: Any, output_dir: Path, result: PipelineResult
+        self,
+        source_code: str,
+        analysis_result: Any,
+        output_dir: Path,
+        result: PipelineResult,
+        module_name: Optional[str] = None,
+        resolver: Optional[ModuleResolver] = None,
     ) -> bool:
         """Phase 6: Target language code generation."""
         try:
-            # Generate code using selected backend
-            generated_code = self.emitter.emit_module(source_code, analysis_result)
+            if module_name is None or resolver is None:
+                # Generate code using selected backend
+                generated_code = self.emitter.emit_module(source_code, analysis_result)
 
-            # Write source file with correct extension
-            file_extension = self.backend.get_file_extension()
-            source_file_path = output_dir / (Path(result.input_file).stem + file_extension)
+                # Write source file with correct extension
+                file_extension = self.backend.get_file_extension()
+                source_file_path = output_dir / (Path(result.input_file).stem + file_extension)
+            else:
+                # A module of a project, written where the backend maps it
+                generated_code = self.emitter.emit_project_module(source_code, analysis_result, module_name, resolver)
+                source_file_path = output_dir / self.backend.get_module_filename(module_name)
+                source_file_path.parent.mkdir(parents=True, exist_ok=True)
             source_file_path.write_text(generated_code)
+            support_files = self.builder.generate_support_files(str(source_file_path), str(output_dir))
 
             result.generated_code = generated_code
             result.output_files[f"{self.config.target_language}_source"] = str(source_file_path)
             result.generated_files.append(str(source_file_path))
+            result.generated_files.extend(support_files)
             result.phase_results[PipelinePhase.GENERATION] = {
                 "source_file": str(source_file_path),
                 "backend": self.backend.get_name(),
@@ -0,0 +1,19 @@
+"""String building benchmark - Repeated string concatenation performance."""
+
+
+def build_text(count: int) -> str:
+    """Build a string from count appended pieces."""
+    text: str = ""
+    for i in range(count):
+        text += "ab"
+    return text
+
+
+def main() -> int:
+    """Run string building benchmark."""
+    # 100k appends: quadratic if every += copies the whole string
+    text: str = build_text(100000)
+
+    print(len(text))
+
+    return 0
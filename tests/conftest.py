@@ -0,0 +1,134 @@
+"""Shared pytest fixtures for MGen tests."""
+
+import contextlib
+import io
+import shutil
+import subprocess
+import tempfile
+from pathlib import Path
+from typing import Callable, Optional
+
+import pytest
+
+import mgen.backends.go
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.backends.go.emitter import GoEmitter
+from mgen.backends.preferences import BackendPreferences
+
+GO_RUNTIME_DIR = Path(mgen.backends.go.__file__).parent / "runtime"
+
+
+# Defined beside main() in every go_run program: check(func() { ... }) prints the
+# error a runtime call raises, and check(func() interface{} { return ... }) prints
+# the repr of what the call returns or the error it raises
+GO_CHECK_HELPER = """
+func check(f interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			mgen.Print(r.(error).Error())
+		}
+	}()
+	switch f := f.(type) {
+	case func():
+		f()
+	case func() interface{}:
+		mgen.Print(mgen.Repr(f()))
+	default:
+		panic("check takes a func() or a func() interface{}")
+	}
+}
+"""
+
+
+def python_output(python_code: str) -> str:
+    """Return what CPython prints running python_code as a script, so that __name__ is "__main__"."""
+    expected = io.StringIO()
+    with contextlib.redirect_stdout(expected):
+        exec(python_code, {"__name__": "__main__"})
+    return expected.getvalue()
+
+
+def _run_go_module(main_source: str, stdin: str = "", timeout: int = 120) -> str:
+    """Build and run a Go main package against the mgen runtime, returning stdout."""
+    with tempfile.TemporaryDirectory() as tmpdir:
+        build_dir = Path(tmpdir)
+        (build_dir / "go.mod").write_text("module mgenproject\n\ngo 1.21\n")
+        (build_dir / "main.go").write_text(main_source)
+
+        runtime_dir = build_dir / "mgen"
+        runtime_dir.mkdir()
+        for runtime_file in GO_RUNTIME_DIR.glob("*.go"):
+            if not runtime_file.name.endswith("_test.go"):
+                shutil.copy2(runtime_file, runtime_dir / runtime_file.name)
+
+        result = subprocess.run(
+            ["go", "run", "."],
+            cwd=build_dir,
+            input=stdin,
+            capture_output=True,
+            text=True,
+            timeout=timeout,
+        )
+        if result.returncode != 0:
+            raise AssertionError(f"Go program failed:\n{result.stderr}\n--- source ---\n{main_source}")
+        return result.stdout
+
+
+@pytest.fixture
+def go_run() -> Callable[..., str]:
+    """Run a Go snippet as the body of main() with the mgen runtime available.
+
+    Usage: ``go_run('mgen.Print(1)')`` or ``go_run(body, imports=["fmt"])``. The
+    body can call check() (see GO_CHECK_HELPER) to print the errors calls raise.
+    """
+    if shutil.which("go") is None:
+        pytest.skip("Go toolchain not available")
+
+    def run(body: str, imports: tuple[str, ...] = (), stdin: str = "") -> str:
+        import_lines = "\n".join(f'\t"{imp}"' for imp in ("mgenproject/mgen", *imports))
+        source = f"package main\n\nimport (\n{import_lines}\n)\n\nfunc main() {{\n{body}\n}}\n{GO_CHECK_HELPER}"
+        return _run_go_module(source, stdin=stdin)
+
+    return run
+
+
+@pytest.fixture
+def go_run_python() -> Callable[..., str]:
+    """Convert Python source with the Go backend, then build and run it."""
+    if shutil.which("go") is None:
+        pytest.skip("Go toolchain not available")
+
+    def run(python_code: str, stdin: str = "", preferences: Optional[BackendPreferences] = None) -> str:
+        go_code = MGenPythonToGoConverter(preferences).convert_code(python_code)
+        return _run_go_module(go_code, stdin=stdin)
+
+    return run
+
+
+@pytest.fixture
+def go_run_emitted() -> Callable[..., str]:
+    """Emit Python source as the Go backend does, formatting included, then build and run it."""
+    if shutil.which("go") is None:
+        pytest.skip("Go toolchain not available")
+
+    def run(python_code: str, stdin: str = "", preferences: Optional[BackendPreferences] = None) -> str:
+        go_code = GoEmitter(preferences).emit_module(python_code, None)
+        return _run_go_module(go_code, stdin=stdin)
+
+    return run
+
+
+@pytest.fixture
+def write_project() -> Callable[[Path, dict[str, str]], None]:
+    """Write a project's files (relative path -> source) under a root directory.
+
+    Usage: ``write_project(root, {"app/__init__.py": "", "app/main.py": source})``.
+    """
+
+    def write(root: Path, files: dict[str, str]) -> None:
+        for relative, source in files.items():
+            path = root / relative
+            path.parent.mkdir(parents=True, exist_ok=True)
+            path.write_text(source)
+
+    return write
@@ -0,0 +1,169 @@
+"""Tests for the Go backend's argparse support (mgen.ArgumentParser)."""
+
+import re
+
+import pytest
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+TOOL = """
+import argparse
+
+
+def run(argv: list[str]) -> None:
+    parser = argparse.ArgumentParser(prog="tool", description="Repeat a word.")
+    parser.add_argument("word", help="the word to repeat")
+    parser.add_argument("-n", "--count", type=int, default=2, help="how many times")
+    parser.add_argument("--scale", type=float, default=1.5)
+    parser.add_argument("-u", "--upper", action="store_true", help="shout")
+    parser.add_argument("--sep")
+    args = parser.parse_args(argv)
+    word = args.word
+    if args.upper:
+        word = word.upper()
+    print(word, args.count * 2, args.scale * 2, args.sep)
+    print(args)
+
+
+def main() -> None:
+    run(ARGV)
+"""
+
+
+def tool(argv: list[str]) -> str:
+    """The TOOL program parsing argv."""
+    return TOOL.replace("ARGV", repr(argv))
+
+
+class TestGoArgparseCodegen:
+    """Test argparse calls lower to the runtime ArgumentParser."""
+
+    def test_parser_codegen(self):
+        """Test add_argument keywords become ArgOpts and args.name reads a typed value."""
+        go_code = MGenPythonToGoConverter().convert_code(tool(["hi"]))
+        assert 'parser := mgen.NewArgumentParser("tool", "Repeat a word.")' in go_code
+        assert 'parser.AddArgument("word", mgen.ArgOpts{Help: "the word to repeat"})' in go_code
+        assert (
+            'parser.AddArgument("-n", mgen.ArgOpts{Aliases: []string{"--count"}, Type: "int", Default: 2, '
+            'Help: "how many times"})'
+        ) in go_code
+        assert "args := parser.ParseArgs(argv)" in go_code
+        assert 'var word string = args.GetStr("word")' in go_code
+        assert 'if args.GetBool("upper") {' in go_code
+        assert '(args.GetInt("count") * 2)' in go_code
+        assert '(args.GetFloat("scale") * 2)' in go_code
+        # --sep may be left out, so it is None or a str
+        assert 'args.Get("sep")' in go_code
+
+    def test_parse_args_without_argv_reads_the_command_line(self):
+        """Test parse_args() passes nil, which the runtime reads as os.Args[1:]."""
+        python_code = """
+from argparse import ArgumentParser
+
+
+def main() -> None:
+    parser = ArgumentParser()
+    parser.add_argument("path")
+    args = parser.parse_args()
+    print(args.path)
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        assert 'parser := mgen.NewArgumentParser("", "")' in go_code
+        assert "args := parser.ParseArgs(nil)" in go_code
+
+    def test_unsupported_keyword_is_rejected(self):
+        """Test add_argument keywords the runtime does not implement fail at transpile time."""
+        python_code = """
+import argparse
+
+
+def main() -> None:
+    parser = argparse.ArgumentParser()
+    parser.add_argument("files", nargs="+")
+"""
+        with pytest.raises(TypeMappingError, match="unsupported keyword argument 'nargs'"):
+            MGenPythonToGoConverter().convert_code(python_code)
+
+
+class TestGoArgparseRuntime:
+    """Test parsing matches Python's argparse, including its errors."""
+
+    @pytest.mark.parametrize(
+        "argv, expected",
+        [
+            (["hi"], ["hi 4 3.0 None", "Namespace(word='hi', count=2, scale=1.5, upper=False, sep=None)"]),
+            (
+                ["hi", "-n", "3", "--upper", "--scale=2"],
+                ["HI 6 4.0 None", "Namespace(word='hi', count=3, scale=2.0, upper=True, sep=None)"],
+            ),
+            (
+                ["--count=4", "-u", "hi", "--sep", ","],
+                ["HI 8 3.0 ,", "Namespace(word='hi', count=4, scale=1.5, upper=True, sep=',')"],
+            ),
+            (
+                ["-n5", "--up", "--", "-x"],
+                ["-X 10 3.0 None", "Namespace(word='-x', count=5, scale=1.5, upper=True, sep=None)"],
+            ),
+            (
+                ["--sca", "-0.5", "hi"],
+                ["hi 4 -1.0 None", "Namespace(word='hi', count=2, scale=-0.5, upper=False, sep=None)"],
+            ),
+        ],
+    )
+    def test_flags_and_positionals(self, go_run_python, argv, expected):
+        """Test a mix of options, flags and positionals parses like argparse."""
+        assert go_run_python(tool(argv)).splitlines() == expected
+
+    @pytest.mark.parametrize(
+        "argv, error",
+        [
+            (["-u"], "the following arguments are required: word"),
+            (["hi", "extra", "--bad"], "unrecognized arguments: extra --bad"),
+            (["hi", "-n", "many"], "argument -n/--count: invalid int value: 'many'"),
+            (["hi", "--count"], "argument -n/--count: expected one argument"),
+        ],
+    )
+    def test_errors_print_usage_and_exit(self, go_run_python, argv, error):
+        """Test bad command lines print the usage and error to stderr and exit with status 2."""
+        usage = "usage: tool [-h] [-n COUNT] [--scale SCALE] [-u] [--sep SEP] word"
+        with pytest.raises(AssertionError, match=re.escape(f"{usage}\ntool: error: {error}\n")):
+            go_run_python(tool(argv))
+
+    def test_help(self, go_run_python):
+        """Test -h prints argparse's help layout and exits."""
+        assert go_run_python(tool(["hi", "-h"])) == (
+            "usage: tool [-h] [-n COUNT] [--scale SCALE] [-u] [--sep SEP] word\n"
+            "\n"
+            "Repeat a word.\n"
+            "\n"
+            "positional arguments:\n"
+            "  word                  the word to repeat\n"
+            "\n"
+            "options:\n"
+            "  -h, --help            show this help message and exit\n"
+            "  -n COUNT, --count COUNT\n"
+            "                        how many times\n"
+            "  --scale SCALE\n"
+            "  -u, --upper           shout\n"
+            "  --sep SEP\n"
+        )
+
+    def test_namespace_runtime(self, go_run):
+        """Test getattr, unknown attributes and ArgumentError without exiting."""
+        output = go_run(
+            """
+    p := mgen.NewArgumentParser("prog", "")
+    p.ExitOnError = false
+    p.AddArgument("--level", mgen.ArgOpts{Type: "int", Default: 1})
+    ns := p.ParseArgs([]string{"--level", "-3"})
+    mgen.Print(mgen.GetAttr(ns, "level"), ns)
+    check(func() { ns.Get("missing") })
+    check(func() { p.ParseArgs([]string{"--level", "x"}) })
+"""
+        )
+        assert output.splitlines() == [
+            "-3 Namespace(level=-3)",
+            "AttributeError: 'Namespace' object has no attribute 'missing'",
+            "argparse.ArgumentError: argument --level: invalid int value: 'x'",
+        ]
@@ -0,0 +1,136 @@
+"""Tests for default, keyword, keyword-only, *args and **kwargs arguments in the Go backend."""
+
+import re
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+ARGUMENTS_PROGRAM = """
+class Counter:
+    def __init__(self, start: int = 0) -> None:
+        self.count = start
+
+    def add(self, n: int = 1, label: str = "") -> int:
+        self.count += n
+        return self.count
+
+    def bump(self) -> int:
+        return self.add(label="x") + self.add(n=5)
+
+
+def greet(name: str, greeting: str = "Hello", punct: str = "!") -> str:
+    return greeting + ", " + name + punct
+
+
+def total(*nums: int) -> int:
+    s = 0
+    for n in nums:
+        s += n
+    return s
+
+
+def join(*parts: str, sep: str = "-") -> str:
+    return sep.join(parts)
+
+
+def scale(factor: int, *, offset: int = 0, clamp: bool = False) -> int:
+    v = factor * 10 + offset
+    if clamp:
+        if v > 50:
+            return 50
+    return v
+
+
+def tag(name: str, /, value: int = 0, **extra: str) -> str:
+    parts = [name + ":" + str(value)]
+    for k, v in extra.items():
+        parts.append(k + "=" + v)
+    return " ".join(parts)
+
+
+def main() -> None:
+    print(greet("Ann"), greet("Bob", "Hi"), greet("Cy", punct="?"), greet(greeting="Yo", name="Di"))
+    print(total(), total(1, 2, 3))
+    xs = [4, 5]
+    print(total(*xs), total(1, *xs, 6))
+    print(join("a", "b"), join("a", "b", sep="+"), repr(join()))
+    print(scale(3), scale(3, offset=2), scale(9, clamp=True))
+    print(tag("t"), tag("t", 2, name="n", color="red"))
+    c = Counter()
+    print(c.add(), c.add(2), c.add(n=3), c.bump())
+"""
+
+
+class TestGoArguments:
+    """Test calls bind their arguments to parameters like Python."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_calling_convention_codegen(self):
+        """Test defaults are passed at the call site, *args as a slice, keyword-only arguments as options."""
+        go_code = self.converter.convert_code(ARGUMENTS_PROGRAM)
+
+        assert 'greet("Cy", "Hello", "?"), greet("Di", "Yo", "!")' in go_code
+        assert "func total(nums []int) int {" in go_code
+        assert "total([]int{}), total([]int{1, 2, 3})" in go_code
+        assert "total(append(append([]int{1}, xs...), 6))" in go_code
+        assert "type scaleOptions struct {\n    Offset int\n    Clamp bool\n}" in go_code
+        assert "func scale(factor int, opts scaleOptions) int {\n    offset, clamp := opts.Offset, opts.Clamp" in (
+            go_code
+        )
+        assert "scale(9, scaleOptions{Offset: 0, Clamp: true})" in go_code
+        assert "func tag(name string, value int, extra *mgen.Dict[string, string]) string {" in go_code
+        assert 'tag("t", 0, mgen.NewDict[string, string]())' in go_code
+        assert 'mgen.KV[string, string]{Key: "color", Value: "red"}' in go_code
+        assert 'return (obj.Add(1, "x") + obj.Add(5, ""))' in go_code
+
+    def test_calling_convention_end_to_end(self, go_run_python):
+        """Test the bound calls produce Python's results."""
+        assert go_run_python(ARGUMENTS_PROGRAM) == python_output(ARGUMENTS_PROGRAM + "\nmain()\n")
+
+    @pytest.mark.parametrize(
+        "definition, call, message",
+        [
+            ("def f(a: int, *, b: int) -> int:", "f(1)", "f() missing 1 required keyword-only argument: 'b'"),
+            ("def f(a: int, b: int = 0) -> int:", "f(1, 2, 3)", "f() takes from 1 to 2 positional arguments"),
+            ("def f(a: int) -> int:", "f(1, c=2)", "f() got an unexpected keyword argument 'c'"),
+            ("def f(a: int, /) -> int:", "f(a=2)", "f() got some positional-only arguments passed as keyword"),
+            ("def f(a: int, b: int = 0) -> int:", "f(1, a=2)", "f() got multiple values for argument 'a'"),
+        ],
+    )
+    def test_invalid_calls(self, definition, call, message):
+        """Test calls Python rejects are rejected at transpile time with Python's TypeError."""
+        python_code = f"{definition}\n    return a\n\n\ndef main() -> None:\n    print({call})\n"
+        with pytest.raises(TypeMappingError, match=re.escape(f"TypeError: {message}")):
+            self.converter.convert_code(python_code)
+
+    @pytest.mark.parametrize(
+        "python_code, message",
+        [
+            (
+                "def f(xs: list[int] = []) -> int:\n    return len(xs)\n\n\ndef main() -> None:\n    print(f())\n",
+                "The default value of parameter xs of f() is not an immutable literal",
+            ),
+            (
+                "def f(**kw: int) -> int:\n    return 0\n\n\ndef main() -> None:\n    print(f(**{'a': 1}))\n",
+                "**mapping unpacking is not supported in f() calls",
+            ),
+            (
+                "def f(a: int, *xs: int) -> int:\n    return a\n\n\ndef main() -> None:\n    print(f(*[1]))\n",
+                "*iterable unpacking is only supported into *args in f() calls",
+            ),
+            (
+                "class A:\n    def f(self, *xs: int) -> int:\n        return 0\n",
+                "*args, keyword-only and **kwargs parameters are not supported in methods: A.f()",
+            ),
+        ],
+    )
+    def test_unsupported_calls(self, python_code, message):
+        """Test arguments only known at run time are rejected."""
+        with pytest.raises(TypeMappingError, match=re.escape(message)):
+            self.converter.convert_code(python_code)
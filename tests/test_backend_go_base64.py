@@ -0,0 +1,200 @@
+"""Tests for the Go backend's base64/binascii support."""
+
+import base64
+import binascii
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+BINARY = bytes(range(256))
+
+# Inputs for b64decode: padding variations, skipped characters and errors
+DECODE_CASES = [
+    "aGVsbG8=",
+    "aGVsbG8",
+    "aGVsbA==",
+    "aGVsbA=",
+    "aGVsbA",
+    "aGVs\nbG8=\n",
+    "aGVs*bG8=",
+    "aGVsbA==aGVsbA==",
+    "YWJj",
+    "YWJjZ",
+    "",
+    "====",
+]
+
+HEX_CASES = ["00ff10", "DEADbeef", "abc", "zz", ""]
+
+
+def python_result(fn):
+    """Return repr(fn()) or the raised error as the Go runtime formats it."""
+    try:
+        return repr(fn())
+    except binascii.Error as e:
+        return f"binascii.Error: {e}"
+    except (TypeError, ValueError) as e:
+        return f"{type(e).__name__}: {e}"
+
+
+def go_bytes(data: bytes) -> str:
+    """Return a Go expression for data as mgen.PyBytes."""
+    return "mgen.PyBytes{" + ", ".join(str(b) for b in data) + "}"
+
+
+def go_str(text: str) -> str:
+    """Return a Go string literal for ASCII text."""
+    return '"' + text.replace("\\", "\\\\").replace("\n", "\\n").replace('"', '\\"') + '"'
+
+
+def go_check(call: str) -> str:
+    """Return a statement printing the call's repr or the error it raises."""
+    return f"    check(func() interface{{}} {{ return {call} }})"
+
+
+class TestGoBase64:
+    """Test base64 encoding and decoding match Python's base64 module."""
+
+    @pytest.mark.parametrize(
+        "encode,decode,python_encode",
+        [
+            ("Base64Encode", "Base64Decode", base64.b64encode),
+            ("URLSafeBase64Encode", "URLSafeBase64Decode", base64.urlsafe_b64encode),
+        ],
+    )
+    def test_round_trip_binary(self, go_run, encode, decode, python_encode):
+        """Test every byte value survives an encode/decode round trip."""
+        output = go_run(
+            f"""
+    data := {go_bytes(BINARY)}
+    encoded := mgen.{encode}(data)
+    mgen.Print(encoded)
+    mgen.Print(string(mgen.{decode}(encoded)) == string(data), mgen.Repr(mgen.{encode}(data[:4])))
+"""
+        )
+        assert output.splitlines() == [
+            repr(python_encode(BINARY)),
+            f"True {python_encode(BINARY[:4])!r}",
+        ]
+
+    def test_decode_padding_matches_python(self, go_run):
+        """Test missing, extra and excess padding and the errors for bad lengths."""
+        calls = [f"mgen.Base64Decode({go_str(text)})" for text in DECODE_CASES]
+        output = go_run("\n".join(go_check(call) for call in calls))
+
+        assert output.splitlines() == [python_result(lambda t=text: base64.b64decode(t)) for text in DECODE_CASES]
+
+    def test_urlsafe_alphabet(self, go_run):
+        """Test urlsafe decoding accepts '-' and '_' as well as the standard alphabet."""
+        texts = ["-_8=", "+/8=", "_-_-"]
+        calls = [f"mgen.URLSafeBase64Decode({go_str(text)})" for text in texts]
+        output = go_run("\n".join(go_check(call) for call in calls))
+
+        assert output.splitlines() == [python_result(lambda t=text: base64.urlsafe_b64decode(t)) for text in texts]
+
+    def test_argument_types(self, go_run):
+        """Test encoders require bytes while decoders also take ASCII str."""
+        calls = [
+            'mgen.Base64Encode("text")',
+            'mgen.Base64Decode(mgen.PyBytes("dGV4dA=="))',
+            'mgen.Base64Decode("dGV4dA==é")',
+        ]
+        output = go_run("\n".join(go_check(call) for call in calls))
+
+        assert output.splitlines() == [
+            python_result(lambda: base64.b64encode("text")),
+            python_result(lambda: base64.b64decode(b"dGV4dA==")),
+            python_result(lambda: base64.b64decode("dGV4dA==é")),
+        ]
+
+    def test_binascii_error_is_value_error(self, go_run):
+        """Test binascii.Error is caught by an except clause naming ValueError."""
+        output = go_run(
+            """
+    defer func() {
+        err := mgen.ExceptionMatches(recover(), "ValueError")
+        mgen.Print(err.Type, err.Message)
+    }()
+    mgen.Base64Decode("abc")
+"""
+        )
+        assert output == "binascii.Error Incorrect padding\n"
+
+
+class TestGoHexlify:
+    """Test hexlify/unhexlify match Python's binascii module."""
+
+    def test_hexlify(self, go_run):
+        """Test every byte value renders as two lowercase hex digits."""
+        output = go_run(f"    mgen.Print(mgen.Hexlify({go_bytes(BINARY)}))")
+
+        assert output == f"{binascii.hexlify(BINARY)!r}\n"
+
+    def test_unhexlify_matches_python(self, go_run):
+        """Test mixed case, odd lengths and non-hex digits."""
+        calls = [f"mgen.Unhexlify({go_str(text)})" for text in HEX_CASES]
+        output = go_run("\n".join(go_check(call) for call in calls))
+
+        assert output.splitlines() == [python_result(lambda t=text: binascii.unhexlify(t)) for text in HEX_CASES]
+
+    def test_bytes_repr(self, go_run):
+        """Test bytes print like Python's repr, choosing quotes as Python does."""
+        samples = [b"it's", b'say "hi"', b"both ' and \"", b"\x00\t\n\r\\\x7f\x80\xff"]
+        output = go_run("\n".join(f"    mgen.Print(mgen.Repr({go_bytes(s)}))" for s in samples))
+
+        assert output.splitlines() == [repr(s) for s in samples]
+
+
+class TestGoBase64Conversion:
+    """Test base64 and binascii calls lower to the runtime encoders."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_codec_codegen(self):
+        """Test module-qualified and imported functions return bytes."""
+        python_code = """
+import base64
+import binascii
+from base64 import b64decode
+
+
+def roundtrip(data: bytes) -> bytes:
+    encoded = base64.b64encode(data)
+    return b64decode(encoded) + binascii.hexlify(encoded)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "encoded := mgen.Base64Encode(data)" in go_code
+        assert "return mgen.Base64Decode(encoded).Concat(mgen.Hexlify(encoded))" in go_code
+
+    def test_codecs_end_to_end(self, go_run_python):
+        """Test encoding str and bytes, both alphabets, hexlify and a decoding error against CPython."""
+        python_code = """
+import base64
+import binascii
+from base64 import b64decode, urlsafe_b64encode
+
+
+def encode(text: str) -> str:
+    return base64.b64encode(text.encode()).decode()
+
+
+def main() -> None:
+    token = encode("hello, world")
+    print(token, b64decode(token), b64decode(token).decode())
+    raw = bytes([250, 251, 252, 253, 254, 255])
+    print(base64.b64encode(raw), urlsafe_b64encode(raw), base64.urlsafe_b64decode(urlsafe_b64encode(raw)) == raw)
+    print(binascii.hexlify(raw), binascii.unhexlify("00ff10"), len(base64.b64decode(b"aGVsbG8=")))
+    try:
+        binascii.unhexlify("abc")
+    except ValueError as e:
+        print("ValueError", e)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
@@ -1,6 +1,7 @@
 """Tests for basic Go backend functionality."""
 
 import pytest
+from conftest import python_output
 
 from mgen.backends.go.converter import MGenPythonToGoConverter
 from mgen.backends.errors import UnsupportedFeatureError
@@ -268,6 +269,21 @@ def test_abs(x: int) -> int:
 
         assert "return mgen.AbsInt(x)" in go_code
 
+    def test_abs_follows_arithmetic(self, go_run_python):
+        """Test abs() of a negated or computed float stays a float, as Python's does."""
+        python_code = """
+def main() -> None:
+    y = 2.5
+    n = 3
+    print(abs(-2.5), abs(-y), abs(y - 5), abs(y * -2))
+    print(abs(-n), abs(n - 10), abs(n / -2))
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.AbsFloat((-2.5)), mgen.AbsFloat((-y)), mgen.AbsFloat((y - 5))" in go_code
+        assert "mgen.AbsInt((-n)), mgen.AbsInt((n - 10))" in go_code
+        assert go_run_python(python_code).splitlines() == ["2.5 2.5 2.5 5.0", "3 7 1.5"]
+
     def test_range_function(self):
         """Test range() function conversion."""
         python_code = """
@@ -290,4 +306,623 @@ def test_min_max(items: list) -> int:
 
         # list annotation defaults to []int now
         assert "mgen.Min[int](items)" in go_code
-        assert "mgen.Max[int](items)" in go_code
\ No newline at end of file
+        assert "mgen.Max[int](items)" in go_code
+    def test_sum_of_bools(self):
+        """Test sum() over bools and bool arithmetic count True as 1."""
+        python_code = """
+def count_true(flags: list[bool]) -> int:
+    return sum(flags) + (True + True)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.SumBool(flags)" in go_code
+        assert "(mgen.BoolToInt(true) + mgen.BoolToInt(true))" in go_code
+
+
+class TestGoBoolArithmeticRuntime:
+    """Test bools behave as 0/1 in arithmetic but print as True/False."""
+
+    def test_bool_arithmetic(self, go_run):
+        """Test BinOp, SumValues and SumBool coerce bools while ToStr keeps their names."""
+        output = go_run(
+            """
+    mgen.Print(mgen.SumBool([]bool{true, false, true}), mgen.SumValues([]interface{}{true, false, true}))
+    mgen.Print(mgen.BinOp("+", true, true), mgen.BinOp("*", true, 2.5), mgen.SumValues([]interface{}{1, true, 0.5}))
+    mgen.Print(mgen.ToStr(true), mgen.SumBool([]bool{true, false, true}) == 2)
+"""
+        )
+        assert output.splitlines() == ["2 2", "2 2.5 2.5", "True True"]
+
+
+class TestGoBoolComparisons:
+    """Test bools compare as the ints 0 and 1, as in Python."""
+
+    def test_bool_int_comparison_codegen(self):
+        """Test mixed bool/number comparisons and list membership use the runtime helpers."""
+        python_code = """
+def check(flag: bool, n: int, flags: list[bool]) -> bool:
+    if flag == n:
+        return n in flags
+    return False
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+
+        assert 'mgen.Compare("==", flag, n)' in go_code
+        assert "mgen.Contains(flags, n)" in go_code
+
+    def test_compare_eq_contains(self, go_run):
+        """Test Eq, Compare and Contains coerce bools and raise TypeError for unordered types."""
+        output = go_run(
+            """
+    mgen.Print(mgen.Eq(true, 1), mgen.Eq(1, 1.0), mgen.Eq([]interface{}{1, true}, []interface{}{true, 1.0}))
+    mgen.Print(mgen.Compare("<", false, 1), mgen.Compare(">=", true, 1), mgen.Compare("!=", false, 0))
+    mgen.Print(mgen.Contains([]interface{}{true}, 1), mgen.Contains([]bool{true}, 0), mgen.Contains("cat", "a"))
+    check(func() { mgen.Compare("<", true, "a") })
+    check(func() { mgen.Compare("<=", []interface{}{1}, []interface{}{"a"}) })
+"""
+        )
+        assert output.splitlines() == [
+            "True True True",
+            "True True False",
+            "True False True",
+            "TypeError: '<' not supported between instances of 'bool' and 'str'",
+            "TypeError: '<=' not supported between instances of 'int' and 'str'",
+        ]
+
+    def test_bool_int_end_to_end(self, go_run_python):
+        """Test True == 1, sorted([True, 0, 2]) and 1 in [True] match Python."""
+        python_code = """
+def main() -> None:
+    print(True == 1, False < 1, True > 0.5, 1 == True)
+    mixed = [True, 0, 2]
+    print(repr(sorted(mixed)))
+    flags = [True]
+    print(1 in flags, 0 in flags, 2 in [False, 2], 2 not in flags)
+    print(max(mixed), min(mixed))
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "True True True True",
+            "[0, True, 2]",
+            "True False True True",
+            "2 0",
+        ]
+
+
+class TestGoSequenceComparisons:
+    """Test tuples and lists compare lexicographically, ordering only the first unequal pair."""
+
+    def test_tuple_comparison_codegen(self):
+        """Test tuple and list operands compare through mgen.Compare, as Go slices have no operators."""
+        python_code = """
+def check(xs: list[int], ys: list[int]) -> bool:
+    if (1, "a") < (2, 2):
+        return xs <= ys
+    return False
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+
+        assert 'mgen.Compare("<", mgen.PyTuple{1, "a"}, mgen.PyTuple{2, 2})' in go_code
+        assert 'mgen.Compare("<=", xs, ys)' in go_code
+
+    def test_short_circuit_runtime(self, go_run):
+        """Test equal pairs are skipped even when unorderable, and only the deciding pair must be ordered."""
+        output = go_run(
+            """
+    mgen.Print(mgen.Compare("<", []interface{}{1, "a"}, []interface{}{2, 2}), mgen.Compare(">", []interface{}{2, "a"}, []interface{}{1, 2}))
+    mgen.Print(mgen.Compare("<", []interface{}{nil, 1}, []interface{}{nil, 2}), mgen.Compare(">=", []interface{}{mgen.NewPyDict(), 0}, []interface{}{mgen.NewPyDict()}))
+    mgen.Print(mgen.Compare("<", []interface{}{1, 2}, []interface{}{1.0, 2, 0}), mgen.Compare("<=", []interface{}{true, "b"}, []interface{}{1, "a"}))
+    check(func() { mgen.Compare("<", []interface{}{1, "a"}, []interface{}{1, 2}) })
+    check(func() { mgen.Compare("<", []interface{}{nil, 1}, []interface{}{0, 1}) })
+"""
+        )
+        assert output.splitlines() == [
+            "True True",
+            "True True",
+            "True False",
+            "TypeError: '<' not supported between instances of 'str' and 'int'",
+            "TypeError: '<' not supported between instances of 'NoneType' and 'int'",
+        ]
+
+    def test_tuple_comparison_end_to_end(self, go_run_python):
+        """Test tuple literals and typed lists compare like Python."""
+        python_code = """
+def main() -> None:
+    print((1, "a") < (2, 2), (2, "a") > (1, 2), (1, "x") < (1, "y"), (1, 2) == (1.0, 2))
+    xs: list[int] = [1, 2, 3]
+    ys: list[int] = [1, 2]
+    print(xs < ys, xs > ys, ys <= xs, xs == [1, 2, 3])
+"""
+        assert go_run_python(python_code).splitlines() == ["True True True True", "False True True True"]
+
+
+class TestGoMinMaxOverDicts:
+    """Test min()/max() over dicts iterate keys, as in Python."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_max_dict_and_key_get(self):
+        """Test max(d) and max(d, key=d.get) code generation."""
+        python_code = """
+def best(scores: dict[str, int]) -> str:
+    print(min(scores))
+    return max(scores, key=scores.get)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.Min[string](scores.Keys())" in go_code
+        assert "return mgen.MaxBy(scores.Keys(), " in go_code
+        assert "return scores.Get(item)" in go_code
+
+    def test_max_dict_runtime(self, go_run_python):
+        """Test max(d, key=d.get) returns the key with the largest value."""
+        python_code = """
+def main() -> None:
+    scores: dict[str, int] = {}
+    scores["bob"] = 7
+    scores["amy"] = 9
+    scores["cat"] = 3
+    print(max(scores), min(scores), max(scores, key=scores.get), min(scores, key=scores.get))
+"""
+        assert go_run_python(python_code).strip() == "cat amy amy cat"
+
+    def test_items_key_lambdas(self, go_run_python):
+        """Test kv[0] and kv[1] in a key lambda over d.items() read the pair's key and value."""
+        python_code = """
+def main() -> None:
+    counts = {"a": 3, "b": 7, "c": 5, "d": 7}
+    print(max(counts.items(), key=lambda kv: kv[1]), min(counts.items(), key=lambda kv: (kv[1], kv[0])))
+    ranked = sorted(counts.items(), key=lambda kv: -kv[1])
+    print(ranked, ranked[0][0])
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "return kv.Value })" in go_code
+        assert "return mgen.PyTuple{kv.Value, kv.Key} })" in go_code
+        assert go_run_python(python_code).splitlines() == [
+            "('b', 7) ('a', 3)",
+            "[('b', 7), ('d', 7), ('c', 5), ('a', 3)] b",
+        ]
+
+    def test_dynamic_min_max(self, go_run):
+        """Test MinBy/MaxBy iterate map keys and raise on empty input."""
+        output = go_run(
+            """
+    counts := map[string]int{"x": 1, "y": 5, "z": 2}
+    byCount := func(k interface{}) interface{} { return counts[k.(string)] }
+    mgen.Print(mgen.MaxBy(counts, nil), mgen.MaxBy(counts, byCount), mgen.MinBy([]interface{}{3, 1.5, 2}, nil))
+    mgen.Print(mgen.MaxBy([]interface{}{[]int{1, 2}, []int{1, 3}, []int{1}}, nil))
+    defer func() { mgen.Print(recover().(error).Error()) }()
+    mgen.MaxBy(map[string]int{}, nil)
+"""
+        )
+        assert output.splitlines() == ["z y 1.5", "[1, 3]", "ValueError: max() arg is an empty sequence"]
+
+
+class TestGoNumericBuiltins:
+    """Test min/max/abs/sum use typed helpers when argument types are known."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_typed_and_dynamic_lowering(self):
+        """Test several arguments of one type use Go's builtin min/max and mixed ones go dynamic."""
+        python_code = """
+def f(a: int, b: int, x: float, v: object, xs: list[int]) -> None:
+    print(min(a, b), max(a, b, 3), max(a - b, -1))
+    print(min(a, x), max(v, a))
+    print(abs(v), abs(x), sum(xs, 10), sum(xs, 0.5))
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.Print(min(a, b), max(a, b, 3), max((a - b), (-1)))" in go_code
+        assert "mgen.MinBy([]interface{}{a, x}, nil), mgen.MaxBy([]interface{}{v, a}, nil)" in go_code
+        assert "mgen.AbsValue(v), mgen.AbsFloat(x)" in go_code
+        assert '(10 + mgen.Sum[int](xs)), mgen.BinOp("+", 0.5, mgen.Sum[int](xs))' in go_code
+
+    def test_results_stay_typed(self):
+        """Test results of typed calls can be used in typed arithmetic."""
+        python_code = """
+def spread(xs: list[float], lo: int, hi: int) -> float:
+    width = max(lo, hi) - min(lo, hi)
+    return max(xs) - min(xs) + width
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "width := (max(lo, hi) - min(lo, hi))" in go_code
+        assert "mgen.Max[float64](xs)" in go_code
+
+    def test_numeric_builtins_match_python(self, go_run_python):
+        """Test typed and dynamic paths print what CPython prints."""
+        python_code = """
+def main() -> None:
+    a: int = 3
+    x: float = 2.5
+    xs: list[int] = [4, 1, 9]
+    flags: list[bool] = [True, False, True]
+    v: object = -4
+    print(min(a, 7), max(a, 7, 5), min(a, x), max(a, x), min("b", "a"))
+    print(sum(xs, 10), sum(flags, 1), sum(xs, 0.5))
+    print(abs(v), abs(a - 9), min(v, 2))
+    try:
+        print(max(xs[:0]))
+    except ValueError as e:
+        print(e)
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "3 7 2.5 3 a",
+            "24 3 14.5",
+            "4 6 -4",
+            "max() arg is an empty sequence",
+        ]
+
+
+class TestGoFloorDivisionAndModulo:
+    """Test // and % floor toward negative infinity as in Python, not truncate as in Go."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_floored_operator_lowering(self):
+        """Test // and % lower to mgen.FloorDiv and mgen.PyMod, widening an int beside a float."""
+        python_code = """
+def f(a: int, b: int, x: float) -> float:
+    q = a // b
+    q //= 2
+    r = x % a
+    r %= 1.5
+    label = "%d" % q
+    return q + r
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "var q int = mgen.FloorDiv(a, b)" in go_code
+        assert "q = mgen.FloorDiv(q, 2)" in go_code
+        assert "var r float64 = mgen.PyMod(x, float64(a))" in go_code
+        assert "r = mgen.PyMod(r, 1.5)" in go_code
+        assert 'mgen.PercentFormat("%d", q)' in go_code
+
+    def test_floored_operators_match_python(self, go_run_python):
+        """Test negative operands and zero divisors give CPython's results."""
+        python_code = """
+def main() -> None:
+    a: int = -7
+    b: int = 2
+    x: float = -7.5
+    print(a // b, a % b, -a // -b, -a % -b, 7 // -2, 7 % -2)
+    print(x // b, x % b, 7.5 // -2.0, 7.5 % -2.0, 6 % 3, 0 // b)
+    zero: int = 0
+    try:
+        print(b // zero)
+    except ZeroDivisionError as e:
+        print(e)
+    try:
+        print(x % 0.0)
+    except ZeroDivisionError as e:
+        print(e)
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "-4 1 -4 -1 -4 -1",
+            "-4.0 0.5 -4.0 -0.5 0 0",
+            "integer division or modulo by zero",
+            "float modulo",
+        ]
+
+
+class TestGoLoopElse:
+    """Test break/continue and the else clause on loops."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_break_and_continue(self):
+        """Test plain break and continue statements."""
+        python_code = """
+def first_even(xs: list[int]) -> int:
+    result: int = -1
+    for x in xs:
+        if x % 2 == 1:
+            continue
+        result = x
+        break
+    return result
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "    continue" in go_code
+        assert "    break" in go_code
+        assert "loopBroke" not in go_code
+
+    def test_for_else_flag(self):
+        """Test for...else runs the else block only when no break happened."""
+        python_code = """
+def find(xs: list[int], target: int) -> None:
+    for x in xs:
+        if x == target:
+            break
+    else:
+        print("not found")
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "loopBroke1 := false" in go_code
+        assert "loopBroke1 = true\n    break" in go_code
+        assert 'if !loopBroke1 {\n    mgen.Print("not found")' in go_code
+
+    def test_loop_else_paths(self, go_run_python):
+        """Test break and no-break paths for for/while loops, including nesting."""
+        python_code = """
+def find(xs: list[int], target: int) -> None:
+    for x in xs:
+        if x == target:
+            print("found", x)
+            break
+    else:
+        print("not found")
+
+def countdown(n: int) -> None:
+    while n > 0:
+        if n == 3:
+            break
+        n -= 1
+    else:
+        print("reached zero")
+    print("n =", n)
+
+def main() -> None:
+    find([1, 2, 3], 2)
+    find([1, 2, 3], 7)
+    countdown(5)
+    countdown(2)
+    for i in range(2):
+        for j in range(3):
+            if j == 1:
+                break
+        else:
+            print("inner else")
+    else:
+        print("outer else")
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "found 2",
+            "not found",
+            "n = 3",
+            "reached zero",
+            "n = 0",
+            "outer else",
+        ]
+
+
+class TestGoDynamicLen:
+    """Test len() and truthiness of values whose static type is interface{}."""
+
+    def test_untyped_len_codegen(self):
+        """Test len() of an unannotated value lowers to the dynamic LenValue."""
+        go_code = MGenPythonToGoConverter().convert_code("def size(x) -> int:\n    return len(x)\n")
+
+        assert "    return mgen.LenValue(x)" in go_code
+
+    def test_len_and_bool_values(self, go_run):
+        """Test the runtime containers, tuples, strings and ranges match Python's len() and bool()."""
+        output = go_run(
+            """
+    values := []interface{}{
+        mgen.NewPyList(1, 2, 3), mgen.NewPyDict(), []interface{}{1, "a"}, "héllo", map[string]int{"a": 1},
+    }
+    for _, v := range values {
+        mgen.Print(mgen.LenValue(v), mgen.ToBool(v))
+    }
+    ranges := []mgen.Range{
+        mgen.NewRange(10), mgen.NewRange(0), mgen.NewRange(2, 11, 3), mgen.NewRange(10, 0, -3),
+        mgen.NewRange(5, 5), mgen.NewRange(0, -5), mgen.NewRange(-5, 0, 2),
+    }
+    for _, r := range ranges {
+        mgen.Print(mgen.LenValue(r), len(r.ToSlice()), mgen.ToBool(r))
+    }
+    mgen.Print(mgen.ToBool(0), mgen.ToBool(0.0), mgen.ToBool(""), mgen.ToBool(nil), mgen.ToBool([]int{}))
+    defer func() { mgen.Print(recover().(error).Error()) }()
+    mgen.LenValue(5)
+"""
+        )
+        assert output.splitlines() == [
+            "3 True",
+            "0 False",
+            "2 True",
+            "5 True",
+            "1 True",
+            "10 10 True",
+            "0 0 False",
+            "3 3 True",
+            "4 4 True",
+            "0 0 False",
+            "0 0 False",
+            "3 3 True",
+            "False False False False False",
+            "TypeError: object of type 'int' has no len()",
+        ]
+
+    def test_len_agrees_with_reflection(self, go_run):
+        """Test len() and truthiness of a boxed PyList through the fast path give what reflection gives.
+
+        BenchmarkLenValue in the runtime's mgen_go_bench_test.go times the
+        same checks (make benchmark-go-runtime).
+        """
+        output = go_run(
+            """
+    list := mgen.NewPyList()
+    for i := 0; i < 1000; i++ {
+        list.Append(i)
+    }
+    var x, empty interface{} = list, mgen.NewPyList()
+    items := reflect.ValueOf(x).Elem().FieldByName("Items")
+    mgen.Print(mgen.LenValue(x), mgen.LenValue(x) == items.Len(), mgen.ToBool(x), mgen.ToBool(empty))
+""",
+            imports=("reflect",),
+        )
+        assert output.strip() == "1000 True True False"
+
+
+class TestGoDynamicFastPaths:
+    """Test the type-switch fast paths of ToStr, Eq and Contains keep Python semantics."""
+
+    def test_fast_paths_match_python(self, go_run):
+        """Test typed slices and maps answer `in` like the generic scan, including 1.0 and True."""
+        output = go_run(
+            """
+    mgen.Print(mgen.Contains([]int{1, 2}, 1.0), mgen.Contains([]int{0, 2}, false), mgen.Contains([]int{1}, "1"))
+    mgen.Print(mgen.Contains([]float64{0.5, 2}, 2), mgen.Contains([]string{"a", "b"}, "b"), mgen.Contains([]string{"1"}, 1))
+    mgen.Print(mgen.Contains(map[string]int{"a": 1}, "a"), mgen.Contains(map[int]bool{2: true}, 2.0), mgen.Contains(map[int]bool{}, 3))
+    mgen.Print(mgen.Eq("a", "a"), mgen.Eq("a", "b"), mgen.Eq("1", 1), mgen.Eq(1, "1"))
+    mgen.Print(mgen.ToStr(-42), mgen.ToStr(int64(7)), mgen.ToStr(uint8(3)), mgen.ToStr(true))
+"""
+        )
+        assert output.splitlines() == [
+            "True True False",
+            "True True False",
+            "True True False",
+            "True False False False",
+            "-42 7 3 True",
+        ]
+
+    def test_dynamic_ops_agree_with_reflection(self, go_run):
+        """Test summing, printing and searching interface{} values gives what reflection gives.
+
+        BenchmarkDynamicOps in the runtime's mgen_go_bench_test.go times the
+        same work (make benchmark-go-runtime).
+        """
+        output = go_run(
+            """
+    const n = 5000
+    values := make([]interface{}, n)
+    for i := range values {
+        values[i] = i
+    }
+    haystack := make([]int, 1000)
+    for i := range haystack {
+        haystack[i] = i
+    }
+    var sum interface{} = 0
+    chars, found := 0, 0
+    for _, v := range values {
+        sum = mgen.BinOp("+", sum, v)
+        chars += len(mgen.ToStr(v))
+        if mgen.Contains(haystack, v.(int)%2000) {
+            found++
+        }
+    }
+    slowSum, slowChars, slowFound := int64(0), 0, 0
+    for _, v := range values {
+        slowSum += reflect.ValueOf(v).Int()
+        slowChars += len(fmt.Sprintf("%v", v))
+        items := reflect.ValueOf(haystack)
+        for j := 0; j < items.Len(); j++ {
+            if items.Index(j).Interface() == interface{}(v.(int)%2000) {
+                slowFound++
+                break
+            }
+        }
+    }
+    mgen.Print(sum, chars, found, mgen.Eq(sum, slowSum), chars == slowChars, found == slowFound)
+""",
+            imports=("fmt", "reflect"),
+        )
+        assert output.strip() == "12497500 18890 3000 True True True"
+
+
+class TestGoChainedAssignment:
+    """Test a = b = value evaluates value once and assigns the targets left to right."""
+
+    def test_call_into_list_indices_codegen(self):
+        """Test the call is stored in a temporary before either index is assigned."""
+        python_code = """
+def total(xs: list[int]) -> int:
+    return sum(xs)
+
+
+def pick(xs: list[int], ys: list[int]) -> None:
+    xs[0] = ys[1] = total(ys)
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+
+        assert (
+            "    chainValue1 := total(ys)\n"
+            "    xs[0] = chainValue1\n"
+            "    ys[1] = chainValue1"
+        ) in go_code
+
+    def test_constant_needs_no_temporary(self):
+        """Test constants and names are assigned to each target directly."""
+        go_code = MGenPythonToGoConverter().convert_code("def f() -> None:\n    a = b = 0\n    print(a, b)\n")
+
+        assert "chainValue" not in go_code
+        assert "    var a int = 0\n    var b int = 0" in go_code
+
+    def test_side_effects_run_once_end_to_end(self, go_run_python):
+        """Test a side-effecting call runs once for list indices, locals and self fields."""
+        python_code = """
+def next_value(counter: list[int]) -> int:
+    counter[0] = counter[0] + 1
+    print("call", counter[0])
+    return counter[0] * 10
+
+
+class Point:
+    def __init__(self, counter: list[int]) -> None:
+        self.x = self.y = next_value(counter)
+
+
+def main() -> None:
+    calls: list[int] = [0]
+    xs: list[int] = [0, 0, 0]
+    ys: list[int] = [0, 0]
+    xs[1] = ys[0] = next_value(calls)
+    print(xs[0], xs[1], ys[0], ys[1], calls[0])
+    a = b = next_value(calls) + 1
+    print(a, b)
+    c = d = 5
+    c += 1
+    print(c, d)
+    h = 21.0
+    f = g = 2.5 * h
+    print(f, g)
+    p = Point(calls)
+    print(p.x, p.y, calls[0])
+    m: dict[str, int] = {}
+    m["k"] = xs[2] = len(xs)
+    print(m["k"], xs[2])
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "call 1",
+            "0 10 10 0 1",
+            "call 2",
+            "21 21",
+            "6 5",
+            "52.5 52.5",
+            "call 3",
+            "30 30 3",
+            "3 3",
+        ]
+
+    def test_chained_names_share_one_list(self, go_run_python):
+        """Test a list bound by a = b = [...] is one object: mutating it through a name shows through the other."""
+        python_code = """
+def main() -> None:
+    a = b = [1]
+    a.append(2)
+    print(a, b)
+    x = y = z = [0]
+    z += [5]
+    y.extend([6])
+    print(x, y, z)
+
+
+main()
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+
+        assert "var b *[]int = a" in go_code
+        assert go_run_python(python_code) == python_output(python_code)
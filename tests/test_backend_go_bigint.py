@@ -0,0 +1,180 @@
+"""Tests for the Go backend's arbitrary-precision int mode (int_precision="auto")."""
+
+import ast
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.backends.go.int_precision import analyze_int_ranges
+from mgen.backends.preferences import GoPreferences
+
+FACTORIAL = """
+def fact(n: int) -> int:
+    result = 1
+    for i in range(2, n + 1):
+        result *= i
+    return result
+"""
+
+
+def auto_preferences() -> GoPreferences:
+    """Return Go preferences selecting arbitrary-precision ints where needed."""
+    preferences = GoPreferences()
+    preferences.set("int_precision", "auto")
+    return preferences
+
+
+class TestIntRangeAnalysis:
+    """Test which ints the analysis finds may outgrow int64."""
+
+    def test_growing_values(self):
+        """Test products and Fibonacci-style sums in loops grow, while counters and moduli do not."""
+        ranges = analyze_int_ranges(
+            ast.parse(
+                FACTORIAL
+                + """
+def fib(n: int) -> int:
+    a = 0
+    b = 1
+    for i in range(n):
+        a, b = b, a + b
+    return a
+
+
+def checksum(data: list[int], m: int) -> int:
+    total = 0
+    count = 0
+    for x in data:
+        total = (total * 31 + x) % m
+        count += 1
+    return total + count
+"""
+            )
+        )
+
+        assert ranges.variables["fact"] == {"result"}
+        assert ranges.variables["fib"] == {"a", "b"}
+        assert ranges.variables["checksum"] == set()
+        assert ranges.functions == {"fact", "fib"}
+
+    def test_propagation(self):
+        """Test recursive products, powers and their uses spread to callers and parameters."""
+        ranges = analyze_int_ranges(
+            ast.parse(
+                """
+def rfact(n: int) -> int:
+    if n <= 1:
+        return 1
+    return n * rfact(n - 1)
+
+
+def show(x: int) -> None:
+    print(x)
+
+
+def main() -> None:
+    p = 2 ** 10
+    q = 2 ** len("abc")
+    show(rfact(30))
+"""
+            )
+        )
+
+        assert ranges.functions == {"rfact"}
+        assert ranges.variables["main"] == {"q"}
+        assert ranges.is_unbounded("show", "x")
+        assert not ranges.is_unbounded("rfact", "n")
+
+
+class TestBigIntConversion:
+    """Test the ints that may outgrow int64 lower to mgen.PyInt."""
+
+    def test_native_is_default(self):
+        """Test every int stays a Go int unless int_precision is "auto"."""
+        go_code = MGenPythonToGoConverter().convert_code(FACTORIAL)
+
+        assert "mgen.PyInt" not in go_code
+        assert "result *= i" in go_code
+
+    def test_factorial(self):
+        """Test a running product becomes an mgen.PyInt while the loop counter stays an int."""
+        go_code = MGenPythonToGoConverter(auto_preferences()).convert_code(FACTORIAL)
+
+        assert "func fact(n int) *mgen.PyInt {" in go_code
+        assert "var result *mgen.PyInt = mgen.NewPyInt(1)" in go_code
+        assert "for i := 2; i < (n + 1); i++ {" in go_code
+        assert "result = result.Mul(mgen.NewPyInt(i))" in go_code
+
+    def test_powers_and_literals(self):
+        """Test powers, big literals and comparisons, and that constant powers within int64 fold."""
+        go_code = MGenPythonToGoConverter(auto_preferences()).convert_code(
+            """
+def main() -> None:
+    n = 70
+    x = 2 ** n
+    big = 123456789012345678901234567890
+    print(x > big, 2 ** 10, -x, x // 3)
+"""
+        )
+
+        assert "var x *mgen.PyInt = mgen.NewPyInt(2).Pow(mgen.NewPyInt(n))" in go_code
+        assert 'var big *mgen.PyInt = mgen.ParsePyInt("123456789012345678901234567890")' in go_code
+        assert "(x.Cmp(big) > 0), 1024, x.Neg(), x.FloorDiv(mgen.NewPyInt(3))" in go_code
+
+    def test_invalid_precision(self):
+        """Test int_precision accepts only "native" or "auto"."""
+        preferences = GoPreferences()
+        preferences.set("int_precision", "big")
+        with pytest.raises(ValueError, match="int_precision must be 'native' or 'auto'"):
+            MGenPythonToGoConverter(preferences)
+
+    def test_program(self, go_run_python):
+        """Test big int arithmetic, formatting and conversions print what CPython prints."""
+        python_code = (
+            FACTORIAL
+            + """
+
+def rfact(n: int) -> int:
+    if n <= 1:
+        return 1
+    return n * rfact(n - 1)
+
+
+def fib(n: int) -> int:
+    a: int = 0
+    b: int = 1
+    i = 0
+    while i < n:
+        c = a + b
+        a = b
+        b = c
+        i += 1
+    return a
+
+
+def digits(n: int) -> int:
+    count = 0
+    while n > 0:
+        n //= 10
+        count += 1
+    return count
+
+
+def main() -> None:
+    f = fact(30)
+    print(f, rfact(25), fib(100))
+    print(f // 7, f % 1000007, -f // 3, f > 10, 5 < f, f == fact(30))
+    print(2 ** 70, 2 ** 10, 10 ** 20 // 3, 1 << 65)
+    print(f"{f:,}", str(fib(90)), repr(f), float(f), int(f) - f)
+    print(digits(fact(20)), len(str(f)), fact(20) + 1)
+    big = 123456789012345678901234567890
+    print(big + 1, -big, big * big, big % -7)
+    n = 5
+    print(n ** 3, n * 2, f / fact(28))
+
+
+main()
+"""
+        )
+        assert go_run_python(python_code, preferences=auto_preferences()) == python_output(python_code)
@@ -0,0 +1,184 @@
+"""Tests for the Go backend's bytes and bytearray support."""
+
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+
+class TestGoBytesConversion:
+    """Test bytes and bytearray lower to mgen.PyBytes and *mgen.PyByteArray."""
+
+    def test_bytes_operations(self):
+        """Test literals, indexing, slicing, concatenation, comparison and codecs."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            r"""
+def main(text: str) -> None:
+    data = b"hello\x00\xff"
+    print(data[0], data[1:3], data + b"!", data < b"z")
+    raw = text.encode()
+    print(raw.decode("ascii", errors="replace"), raw.hex())
+    for c in data:
+        print(c)
+"""
+        )
+
+        assert r'var data mgen.PyBytes = mgen.PyBytes("hello\x00\xff")' in go_code
+        assert "data.At(0)" in go_code
+        assert "data.Slice(" in go_code
+        assert 'data.Concat(mgen.PyBytes("!"))' in go_code
+        assert '(data.Cmp(mgen.PyBytes("z")) < 0)' in go_code
+        assert 'mgen.StrOps.Encode(text, "utf-8", "strict")' in go_code
+        assert 'raw.Decode("ascii", "replace")' in go_code
+        assert "range data.Ints()" in go_code
+
+    def test_bytearray_operations(self):
+        """Test bytearray construction, item assignment, in-place methods and +=."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+def main() -> None:
+    ba = bytearray(b"abc")
+    ba[0] = 65
+    ba.append(100)
+    ba += b"ef"
+    print(ba.pop(), bytearray(), bytes(3))
+"""
+        )
+
+        assert 'ba := mgen.NewByteArray(mgen.PyBytes("abc"))' in go_code
+        assert "ba.SetItem(0, 65)" in go_code
+        assert "ba.Append(100)" in go_code
+        assert 'ba.Extend(mgen.PyBytes("ef"))' in go_code
+        assert "ba.Pop()" in go_code
+        assert "mgen.NewByteArray(nil)" in go_code
+        assert "mgen.NewBytes(3)" in go_code
+
+    def test_bytes_keys_use_value_hashing(self):
+        """Test bytes keys route dicts and sets to the value-hashed PyDict and PySet."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+def main() -> None:
+    d = {b"a": 1}
+    typed: dict[bytes, int] = {}
+    s: set[bytes] = set()
+    print(d, typed, s)
+"""
+        )
+
+        assert "var d *mgen.PyDict = mgen.NewPyDict(" in go_code
+        assert "var typed *mgen.PyDict" in go_code
+        assert "var s *mgen.PySet" in go_code
+
+
+class TestGoBytesProgram:
+    """Test programs using bytes and bytearray print what CPython prints."""
+
+    def test_bytes_and_bytearray(self, go_run_python):
+        """Test repr, indexing, slicing, operators, constructors and bytearray mutation."""
+        python_code = r"""
+def main() -> None:
+    data = b"hello\x00\xff"
+    print(data, len(data), data[0], data[-1], data[1:3])
+    text = "héllo"
+    raw = text.encode()
+    print(raw, raw.decode("utf-8"), raw.hex())
+    joined = data + b"!"
+    print(joined == b"hello\x00\xff!", joined < data, b"ell" in data, 104 in data)
+    ba = bytearray(b"abc")
+    ba.append(100)
+    ba[0] = 65
+    ba.extend(b"ef")
+    print(ba, len(ba), bytes(ba), ba[1])
+    for c in b"hi":
+        print(c)
+    z = bytes(3)
+    print(z, bytes([1, 2, 255]), bytearray(2))
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
+
+    def test_codecs_and_errors(self, go_run_python):
+        """Test functions taking bytes, codec error handlers and Python's exception messages."""
+        python_code = r"""
+def body(payload: bytes) -> bytes:
+    return payload[1:] + b"."
+
+
+def text(payload: bytes) -> str:
+    return payload[1:].decode("ascii", errors="replace")
+
+
+def checksum(data: bytes) -> int:
+    total = 0
+    for b in data:
+        total = (total + b) % 256
+    return total
+
+
+def frame(data: bytes) -> bytearray:
+    out = bytearray()
+    out.append(len(data))
+    out += data
+    out.extend([1, 2])
+    return out
+
+
+def main() -> None:
+    payload = b"\x07hi\xff"
+    print(payload[0], body(payload), text(payload), checksum(payload))
+    f = frame(b"abc")
+    print(f, f.pop(), f.pop(0), f, f.hex(), len(f), bool(bytearray()))
+    print(b"ab" * 3, 2 * b"xy", b"a" < b"b", b"abc" > b"ab", b"" == bytes(), bytearray(b"x") == b"x")
+    print(bytes.fromhex("de ad be ef"), list(b"AB"), sorted([b"b", b"a"]), [c + 1 for c in b"\x01\x02"])
+    print(bytes("héllo", "latin-1"), bytearray("hi", encoding="ascii"), "é€".encode("ascii", "ignore"))
+    print(b"caf\xc3\xa9 \xff".decode(errors="replace"), "x€y".encode("latin-1", "replace"))
+    b = b"hello"
+    b += b"!"
+    print(b, b[-1], b[::-1], b"ell" in b, 255 in b)
+    for bad in [b"\xff", b"ok\xe4\xbd", b"\xe4A", b"\xed\xa0\x80"]:
+        try:
+            print(bad.decode())
+        except UnicodeDecodeError as e:
+            print("UnicodeDecodeError", e)
+    try:
+        print("naïve café".encode("ascii"))
+    except UnicodeEncodeError as e:
+        print("UnicodeEncodeError", e)
+    try:
+        print("a€€b".encode("latin-1"))
+    except ValueError as e:
+        print("ValueError", e)
+    try:
+        bytes([1, 300])
+    except ValueError as e:
+        print("ValueError", e)
+    try:
+        print(b[10])
+    except IndexError as e:
+        print("IndexError", e)
+    try:
+        bytes("x")
+    except TypeError as e:
+        print("TypeError", e)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
+
+    def test_bytes_keys(self, go_run_python):
+        """Test bytes as dict keys and set members."""
+        python_code = """
+def main() -> None:
+    d = {b"a": 1}
+    d[b"b"] = 2
+    s = {b"x", b"y"}
+    typed: dict[bytes, int] = {}
+    typed[b"k"] = 3
+    print(d, b"x" in s, len(s), typed, b"k" in typed)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
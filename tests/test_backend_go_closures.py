@@ -0,0 +1,230 @@
+"""Tests for Go backend nested functions and nonlocal closures."""
+
+import pytest
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+MAKE_COUNTER = """
+from typing import Callable
+
+
+def make_counter() -> Callable[[], int]:
+    count = 0
+
+    def increment() -> int:
+        nonlocal count
+        count += 1
+        return count
+
+    return increment
+"""
+
+
+class TestGoClosureConversion:
+    """Test nested functions become Go closures over the enclosing variables."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_nonlocal_assigns_captured_variable(self):
+        """Test a nonlocal name is assigned, not redeclared, inside the closure."""
+        go_code = self.converter.convert_code(MAKE_COUNTER)
+
+        assert "func make_counter() func() int {" in go_code
+        assert "    increment := func() int {" in go_code
+        assert "    count += 1" in go_code
+        assert "count :=" not in go_code.split("increment :=")[1]
+
+    def test_callable_parameter_and_recursive_closure(self):
+        """Test Callable annotations map to func types and recursive closures are predeclared."""
+        python_code = """
+from typing import Callable
+
+
+def apply(f: Callable[[int, str], bool], n: int) -> bool:
+    return f(n, "x")
+
+
+def fact_of(n: int) -> int:
+    def fact(k: int) -> int:
+        if k <= 1:
+            return 1
+        return k * fact(k - 1)
+
+    return fact(n)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func apply(f func(int, string) bool, n int) bool {" in go_code
+        assert "    var fact func(k int) int\n    fact = func(k int) int {" in go_code
+
+    def test_nonlocal_without_binding(self):
+        """Test nonlocal names must be bound in an enclosing function, as Python requires."""
+        python_code = """
+def outer() -> int:
+    def inner() -> int:
+        nonlocal missing
+        missing = 1
+        return missing
+
+    return inner()
+"""
+        with pytest.raises(TypeMappingError, match="SyntaxError: no binding for nonlocal 'missing' found"):
+            self.converter.convert_code(python_code)
+
+    def test_global_without_module_variable(self):
+        """Test global names need a module-level assignment to declare their package-level var."""
+        python_code = """
+def bump() -> None:
+    global counter
+    counter = 1
+"""
+        with pytest.raises(TypeMappingError, match="global counter is not assigned at module level"):
+            self.converter.convert_code(python_code)
+
+    def test_nonlocal_bound_after_definition(self):
+        """Test a variable the enclosing function binds after defining the closure is declared before it."""
+        python_code = """
+def outer() -> int:
+    def inc() -> None:
+        nonlocal n
+        n += 1
+
+    n = 0
+    inc()
+    return n
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "    var n int\n    inc := func() {" in go_code
+        assert "    n = 0\n    inc()" in go_code
+
+
+class TestGoClosureRuntime:
+    """Test closures observe and mutate enclosing variables like Python's."""
+
+    def test_make_counter(self, go_run_python):
+        """Test each counter keeps its own count across calls."""
+        python_code = (
+            MAKE_COUNTER
+            + """
+
+def main() -> None:
+    first = make_counter()
+    second = make_counter()
+    first()
+    first()
+    print(first(), second(), first())
+"""
+        )
+        assert go_run_python(python_code) == "3 1 4\n"
+
+    def test_mutations_visible_to_enclosing_function(self, go_run_python):
+        """Test nonlocal writes through two levels of nesting and local shadowing."""
+        python_code = """
+def outer() -> int:
+    x = 1
+    calls = 0
+
+    def middle() -> int:
+        nonlocal calls
+        y = 10
+
+        def inner() -> int:
+            nonlocal x, y, calls
+            x = x * 2
+            y += 1
+            calls += 1
+            return x + y
+
+        inner()
+        return inner()
+
+    r = middle()
+    print("x:", x, "calls:", calls, "r:", r)
+    return r
+
+
+def shadow() -> None:
+    n = 5
+
+    def local_only() -> int:
+        n = 100
+        return n
+
+    def reader(k: int) -> int:
+        return n + k
+
+    print(local_only(), reader(1), n)
+
+
+def main() -> None:
+    outer()
+    shadow()
+"""
+        assert go_run_python(python_code).splitlines() == ["x: 4 calls: 2 r: 16", "100 6 5"]
+
+    def test_nonlocal_in_method(self, go_run_python):
+        """Test a closure defined in a method rebinds the method's locals and reads self."""
+        python_code = """
+class Stats:
+    def __init__(self) -> None:
+        self.seen = 0
+
+    def longest(self, words: list[str]) -> int:
+        best = 0
+
+        def visit(w: str) -> None:
+            nonlocal best
+            self.seen += 1
+            if len(w) > best:
+                best = len(w)
+
+        for w in words:
+            visit(w)
+        return best
+
+
+def main() -> None:
+    s = Stats()
+    print(s.longest(["a", "abc", "ab"]), s.seen)
+"""
+        assert go_run_python(python_code) == "3 3\n"
+
+    def test_closures_bound_before_variables(self, go_run_python):
+        """Test closures defined before the variables they read and rebind, in functions and methods."""
+        python_code = """
+class Tally:
+    def run(self) -> int:
+        def hit() -> None:
+            nonlocal hits
+            hits = hits + 1
+
+        hits = 0
+        hit()
+        hit()
+        return hits
+
+
+def outer() -> int:
+    def inc(k: int) -> None:
+        nonlocal n
+        n += k
+
+    def show() -> str:
+        return "n=" + str(n)
+
+    n = 1
+    inc(2)
+    inc(3)
+    print(show())
+    return n
+
+
+def main() -> None:
+    t = Tally()
+    print(outer(), t.run())
+"""
+        assert go_run_python(python_code) == "n=6\n6 2\n"
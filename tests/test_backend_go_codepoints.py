@@ -0,0 +1,138 @@
+"""Tests for the Go backend's code point and byte string semantics."""
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.backends.preferences import GoPreferences
+
+
+def byte_preferences() -> GoPreferences:
+    """Return Go preferences selecting the byte-based string semantics."""
+    preferences = GoPreferences()
+    preferences.set("string_semantics", "bytes")
+    return preferences
+
+
+STRING_OPS = """
+def check(s: str) -> None:
+    print(len(s), s[1], s[-1], s[1:3], len("abc"), "abc"[0], "héllo"[1])
+    for ch in s:
+        print(ch)
+    print(list(s), ord(s[0]), chr(233))
+"""
+
+
+class TestGoCodePointConversion:
+    """Test str operations count code points unless they may count bytes."""
+
+    def test_codepoint_semantics(self):
+        """Test len, indexing, slicing and iteration count code points by default."""
+        go_code = MGenPythonToGoConverter().convert_code(STRING_OPS)
+
+        assert "mgen.LenString(s), mgen.StrAt(s, 1), mgen.StrAt(s, (-1))" in go_code
+        assert "mgen.SliceString(s, mgen.NewSlice(1, 3, nil))" in go_code
+        assert "for _, ch := range mgen.StrOps.Chars(s) {" in go_code
+        assert "mgen.Print(mgen.StrOps.Chars(s), mgen.Ord(mgen.StrAt(s, 0)), mgen.Chr(233))" in go_code
+
+    def test_ascii_literals_count_bytes(self):
+        """Test ASCII literals, whose bytes are their code points, use Go's byte operations."""
+        go_code = MGenPythonToGoConverter().convert_code(STRING_OPS)
+
+        assert 'len("abc"), mgen.StrByteAt("abc", 0), mgen.StrAt("héllo", 1)' in go_code
+
+    def test_byte_semantics(self):
+        """Test the "bytes" string_semantics counts bytes everywhere."""
+        go_code = MGenPythonToGoConverter(byte_preferences()).convert_code(STRING_OPS)
+
+        assert "len(s), mgen.StrByteAt(s, 1), mgen.StrByteAt(s, (-1))" in go_code
+        assert "mgen.SliceStringBytes(s, mgen.NewSlice(1, 3, nil))" in go_code
+        assert "for _, ch := range mgen.StrOps.ByteChars(s) {" in go_code
+        assert 'mgen.StrByteAt("héllo", 1)' in go_code
+
+    def test_invalid_semantics(self):
+        """Test string_semantics accepts only "codepoint" or "bytes"."""
+        preferences = GoPreferences()
+        preferences.set("string_semantics", "runes")
+        with pytest.raises(ValueError, match="string_semantics must be 'codepoint' or 'bytes'"):
+            MGenPythonToGoConverter(preferences)
+
+
+class TestGoCodePointRuntime:
+    """Test the runtime str helpers raise Python's errors."""
+
+    def test_errors(self, go_run):
+        """Test out-of-range indices, ord() of a non-character and chr() out of range."""
+        output = go_run(
+            """
+    for _, f := range []func(){
+        func() { mgen.StrAt("héllo", 5) },
+        func() { mgen.StrAt("ab", -3) },
+        func() { mgen.StrByteAt("", 0) },
+        func() { mgen.Ord("ab") },
+        func() { mgen.Ord("") },
+        func() { mgen.Chr(0x110000) },
+    } {
+        func() {
+            defer func() {
+                if r := recover(); r != nil {
+                    mgen.Print(r)
+                }
+            }()
+            f()
+        }()
+    }
+    mgen.Print(mgen.StrAt("ab日本", 3), mgen.StrAt("日本", -2), mgen.Ord("語"), mgen.StrOps.ByteChars("ab"))
+"""
+        )
+        assert output.splitlines() == [
+            "string index out of range",
+            "string index out of range",
+            "string index out of range",
+            "ord() expected a character, but string of length 2 found",
+            "ord() expected a character, but string of length 0 found",
+            "chr() arg not in range(0x110000)",
+            "本 日 35486 ['a', 'b']",
+        ]
+
+
+class TestGoCodePointProgram:
+    """Test programs using non-ASCII strings print what CPython prints."""
+
+    def test_codepoints(self, go_run_python):
+        """Test len, indexing, slicing, iteration and the builtins over them count code points."""
+        python_code = """
+def main() -> None:
+    s = "héllo wörld"
+    print(len(s), s[1], s[-1], s[1:4], s[::-1])
+    for ch in s:
+        print(ch, end="")
+    print()
+    for i, ch in enumerate(s):
+        if ch == "ö":
+            print(i, ch)
+    print(list(s), sorted("bäa"), "".join(reversed(s)), s.find("w"), ord(s[1]), chr(233))
+    n = 0
+    for c in "日本語":
+        n += 1
+    print(n, len("日本語"), "日本語"[2], len("abc"), "abc"[0], [c for c in "añb" if c != "b"])
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
+
+    def test_byte_semantics_on_ascii(self, go_run_python):
+        """Test the "bytes" string_semantics agrees with Python on ASCII strings."""
+        python_code = """
+def main() -> None:
+    s = "hello world"
+    print(len(s), s[1], s[-1], s[1:4], s[::-1], list(s[:3]), ord(s[0]))
+    for i, ch in enumerate(s):
+        if ch == "w":
+            print(i, ch)
+
+
+main()
+"""
+        assert go_run_python(python_code, preferences=byte_preferences()) == python_output(python_code)
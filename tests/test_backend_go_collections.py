@@ -0,0 +1,247 @@
+"""Tests for the Go backend's defaultdict, Counter and namedtuple support."""
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+
+class TestGoDefaultDictConversion:
+    """Test defaultdict() lowers to a typed mgen.Dict with a default factory."""
+
+    def test_factories(self):
+        """Test builtin factories make the value type's zero value and lambdas are called."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+from collections import defaultdict
+
+
+def main() -> None:
+    groups: defaultdict[str, list[int]] = defaultdict(list)
+    counts = defaultdict(int)
+    counts["a"] += 1
+    fallback = defaultdict(lambda: 42)
+    print(groups, counts, fallback[3])
+"""
+        )
+
+        assert "groups := mgen.NewDefaultDict[string, []int](func() []int { return []int{} }" in go_code
+        assert '"<class \'list\'>")' in go_code
+        assert 'mgen.NewDefaultDict[string, int](func() int { return 0 }, "<class \'int\'>")' in go_code
+        assert 'mgen.NewDefaultDict[int, int](func() int { return 42 }, "<function <lambda>>")' in go_code
+
+    def test_item_append(self):
+        """Test d[k].append(x) stores the grown slice back and string keys retype defaultdict(list)."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+from collections import defaultdict
+
+
+def group(words: list[str]) -> None:
+    lengths = defaultdict(list)
+    for word in words:
+        lengths[word].append(len(word))
+    print(lengths)
+"""
+        )
+
+        assert "mgen.NewDefaultDict[string, []int]" in go_code
+        assert "lengths.Set(word, append(lengths.Get(word), mgen.LenString(word)))" in go_code
+
+    def test_unsupported_factory(self):
+        """Test a factory taking arguments is rejected."""
+        with pytest.raises(TypeMappingError, match="Unsupported defaultdict factory"):
+            MGenPythonToGoConverter().convert_code(
+                "from collections import defaultdict\n\n\ndef f() -> None:\n    d = defaultdict(lambda x: x)\n"
+            )
+
+
+class TestGoCounterConversion:
+    """Test Counter() and its methods lower to the runtime's counter functions."""
+
+    def test_counter_methods(self):
+        """Test counting lists and strings and the Counter-only methods."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+from collections import Counter
+
+
+def main() -> None:
+    words = ["a", "b", "a"]
+    c = Counter(words)
+    letters = Counter("hello")
+    c.update(["c"])
+    c.subtract("a")
+    print(c.most_common(1), letters.most_common(), c.total(), c.elements())
+"""
+        )
+
+        assert "c := mgen.NewCounter[string](words)" in go_code
+        assert 'letters := mgen.NewCounter[string](mgen.StrOps.Chars("hello"))' in go_code
+        assert 'mgen.CounterUpdate(c, []string{"c"})' in go_code
+        assert 'mgen.CounterSubtract(c, mgen.StrOps.Chars("a"))' in go_code
+        assert "mgen.MostCommon(c, 1), mgen.MostCommon(letters, -1), mgen.CounterTotal(c)" in go_code
+        assert "mgen.CounterElements(c)" in go_code
+
+
+class TestGoNamedTupleConversion:
+    """Test both namedtuple spellings become structs that print like Python."""
+
+    def test_typing_namedtuple(self):
+        """Test NamedTuple fields become constructor parameters with their defaults."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+from typing import NamedTuple
+
+
+class Card(NamedTuple):
+    rank: int
+    suit: str = "hearts"
+
+
+def main() -> None:
+    card = Card(7)
+    print(card, card[1])
+"""
+        )
+
+        assert "type Card struct {\n    Rank int\n    Suit string\n}" in go_code
+        assert "func NewCard(rank int, suit string) Card {" in go_code
+        assert 'return "Card(rank=" + mgen.Repr(obj.Rank) + ", suit=" + mgen.Repr(obj.Suit) + ")"' in go_code
+        assert 'card := NewCard(7, "hearts")' in go_code
+        assert "mgen.Print(card, card.Suit)" in go_code
+
+    def test_collections_namedtuple_field_types(self):
+        """Test fields take the type of the constants passed for them, else interface{}."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+from collections import namedtuple
+
+Point = namedtuple("Point", "x, y label")
+
+
+def main() -> None:
+    p = Point(1, 2.5, "a")
+    q = Point(3, 4, None)
+    print(p, q)
+"""
+        )
+
+        assert "type Point struct {\n    X int\n    Y float64\n    Label interface{}\n}" in go_code
+
+
+class TestGoCollectionsProgram:
+    """Test programs using the collections types print what CPython prints."""
+
+    def test_defaultdict_and_counter(self, go_run_python):
+        """Test missing keys, repr, counting and most_common ordering."""
+        python_code = """
+from collections import Counter, defaultdict
+
+
+def main() -> None:
+    words = "the cat and the hat and the bat".split()
+    by_len: defaultdict[int, list[str]] = defaultdict(list)
+    for word in words:
+        by_len[len(word)].append(word)
+    print(by_len, len(by_len))
+    seen: defaultdict[str, int] = defaultdict(int)
+    for word in words:
+        seen[word] += 1
+    print(seen["the"], seen["dog"], len(seen), "dog" in seen)
+    strict: defaultdict[str, int] = defaultdict()
+    strict["a"] = 1
+    print(strict)
+    c = Counter(words)
+    print(c)
+    print(c.most_common(2), c["the"], c["dog"], "dog" in c, c.total())
+    c.update(["cat", "cow"])
+    c.subtract(["the", "the"])
+    print(c, sorted(c.elements()))
+    letters = Counter("mississippi")
+    print(letters.most_common(), Counter())
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
+
+    def test_missing_key_without_factory(self, go_run_python):
+        """Test a defaultdict without a factory raises KeyError like a dict."""
+        python_code = """
+from collections import defaultdict
+
+
+def main() -> None:
+    d: defaultdict[str, int] = defaultdict()
+    try:
+        print(d["x"])
+    except KeyError as e:
+        print("KeyError", e)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
+
+    def test_dict_of_defaultdict_is_plain(self, go_run_python):
+        """Test dict() of a defaultdict or Counter drops the missing-key default."""
+        python_code = """
+from collections import Counter, defaultdict
+
+
+def main() -> None:
+    dd: defaultdict[str, int] = defaultdict(int)
+    dd["a"] += 1
+    plain = dict(dd)
+    counts = dict(Counter("aab"))
+    print(plain, dd["z"], len(plain), len(dd), counts)
+    print("q" in plain, plain.get("q", 5))
+    try:
+        print(plain["q"])
+    except KeyError as e:
+        print("KeyError", e)
+    try:
+        print(counts["q"])
+    except KeyError as e:
+        print("KeyError", e)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
+
+    def test_namedtuples(self, go_run_python):
+        """Test construction, field access, indexing, equality, loops and repr of namedtuples."""
+        python_code = """
+import collections
+from collections import namedtuple
+from typing import NamedTuple
+
+Point = namedtuple("Point", ["x", "y"])
+Score = collections.namedtuple("Score", "name points", defaults=[0])
+
+
+class Card(NamedTuple):
+    rank: int
+    suit: str = "hearts"
+
+    def label(self) -> str:
+        return str(self.rank) + " of " + self.suit
+
+
+def main() -> None:
+    p = Point(1, 2)
+    q = Point(y=5, x=4)
+    print(p.x + p.y, p, p[0] * 10, q[-1], q == Point(4, 5), [p, q])
+    print(Score("ann", 3), Score("bob"), Score("cy").points)
+    cards = [Card(9, "clubs"), Card(2)]
+    for rank, suit in cards:
+        print(rank, suit)
+    print(cards[1].label(), sorted(cards, key=lambda card: card.rank))
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
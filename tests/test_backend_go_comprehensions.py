@@ -21,9 +21,9 @@ def test_list_comp() -> list:
 """
         go_code = self.converter.convert_code(python_code)
 
-        # Should contain comprehension call with Range
-        assert "mgen.ListComprehension" in go_code
-        assert "mgen.NewRange(5)" in go_code
+        # A typed comprehension over a range counts with an int instead of calling a closure per element
+        assert "for x := 0; x < 5; x++ {" in go_code
+        assert "comprehension = append(comprehension, x)" in go_code
 
     def test_list_comprehension_with_expression(self):
         """Test list comprehension with expression transformation."""
@@ -33,11 +33,8 @@ def test_list_comp_expr() -> list:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.ListComprehension" in go_code
-        assert "mgen.NewRange(3)" in go_code
-        # Should contain lambda expression
-        # Lambda signature updated for generics
-        assert "(x * 2)" in go_code
+        assert "for x := 0; x < 3; x++ {" in go_code
+        assert "comprehension = append(comprehension, (x * 2))" in go_code
 
     def test_list_comprehension_with_condition(self):
         """Test list comprehension with if condition."""
@@ -47,10 +44,9 @@ def test_list_comp_if() -> list:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.ListComprehension" in go_code  # Either ListComprehension or ListComprehensionFromRangeWithFilter
-        assert "mgen.NewRange(10)" in go_code
-        # Should contain condition lambda
-        assert "(x % 2) == 0" in go_code
+        assert "for x := 0; x < 10; x++ {" in go_code
+        # The condition skips the element inside the loop
+        assert "if !((mgen.PyMod(x, 2) == 0)) { continue }" in go_code
 
     def test_list_comprehension_range_start_stop(self):
         """Test list comprehension with range(start, stop)."""
@@ -60,7 +56,7 @@ def test_range_start_stop() -> list:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.NewRange(2, 8)" in go_code
+        assert "for x := 2; x < 8; x++ {" in go_code
 
     def test_list_comprehension_range_step(self):
         """Test list comprehension with range(start, stop, step)."""
@@ -70,7 +66,7 @@ def test_range_step() -> list:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.NewRange(0, 10, 2)" in go_code
+        assert "for x := 0; x < 10; x += 2 {" in go_code
 
     def test_list_comprehension_complex_expression(self):
         """Test list comprehension with complex expression."""
@@ -80,8 +76,7 @@ def test_complex_expr() -> list:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.ListComprehension" in go_code
-        assert "((x * x) + 1)" in go_code
+        assert "comprehension = append(comprehension, ((x * x) + 1))" in go_code
 
     def test_list_comprehension_with_variable(self):
         """Test list comprehension using variable in range."""
@@ -91,7 +86,7 @@ def test_with_var(n: int) -> list:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.NewRange(n)" in go_code
+        assert "for x := 0; x < n; x++ {" in go_code
 
 
 class TestGoDictComprehensions:
@@ -109,9 +104,9 @@ def test_dict_comp() -> dict:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.DictComprehension" in go_code
-        assert "mgen.NewRange(3)" in go_code
-        # Dict comprehension now uses generics with specific types
+        assert "comprehension := mgen.NewDict[int, int]()" in go_code
+        assert "for x := 0; x < 3; x++ {" in go_code
+        assert "comprehension.Set(x, (x * 2))" in go_code
 
     def test_dict_comprehension_with_condition(self):
         """Test dictionary comprehension with condition."""
@@ -121,10 +116,9 @@ def test_dict_comp_if() -> dict:
 """
         go_code = self.converter.convert_code(python_code)
 
-        # Note: Dict comprehensions with filters would be complex in Go
-        # For now, just check basic structure
-        assert "mgen.DictComprehension" in go_code
-        assert "mgen.NewRange(5)" in go_code
+        assert "for x := 0; x < 5; x++ {" in go_code
+        assert "if !((x > 2)) { continue }" in go_code
+        assert "comprehension.Set(x, (x * x))" in go_code
 
     def test_dict_comprehension_string_keys(self):
         """Test dictionary comprehension with string keys."""
@@ -134,8 +128,8 @@ def test_dict_str_keys() -> dict:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.DictComprehension" in go_code
-        assert "mgen.NewRange(3)" in go_code
+        assert "comprehension := mgen.NewDict[string, int]()" in go_code
+        assert "comprehension.Set(mgen.ToStr(x), x)" in go_code
 
     def test_dict_comprehension_complex_values(self):
         """Test dictionary comprehension with complex value expressions."""
@@ -145,8 +139,7 @@ def test_dict_complex() -> dict:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.DictComprehension" in go_code
-        assert "((x * x) + x)" in go_code
+        assert "comprehension.Set(x, ((x * x) + x))" in go_code
 
 
 class TestGoSetComprehensions:
@@ -164,8 +157,9 @@ def test_set_comp() -> set:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.SetComprehension" in go_code
-        assert "mgen.NewRange(5)" in go_code
+        assert "comprehension := mgen.NewSet[int]()" in go_code
+        assert "for x := 0; x < 5; x++ {" in go_code
+        assert "comprehension.Add(x)" in go_code
 
     def test_set_comprehension_with_condition(self):
         """Test set comprehension with condition."""
@@ -175,9 +169,8 @@ def test_set_comp_if() -> set:
 """
         go_code = self.converter.convert_code(python_code)
 
-        # Note: Set comprehensions with filters would need complex handling
-        assert "mgen.SetComprehension" in go_code
-        assert "mgen.NewRange(10)" in go_code
+        assert "for x := 0; x < 10; x++ {" in go_code
+        assert "if !((mgen.PyMod(x, 3) == 0)) { continue }" in go_code
 
     def test_set_comprehension_with_expression(self):
         """Test set comprehension with expression transformation."""
@@ -187,8 +180,7 @@ def test_set_comp_expr() -> set:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.SetComprehension" in go_code
-        assert "(x * x)" in go_code
+        assert "comprehension.Add((x * x))" in go_code
 
     def test_set_comprehension_deduplication(self):
         """Test set comprehension that would naturally deduplicate."""
@@ -198,8 +190,7 @@ def test_set_dedup() -> set:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.SetComprehension" in go_code
-        assert "(x % 3)" in go_code
+        assert "comprehension.Add(mgen.PyMod(x, 3))" in go_code
 
 
 class TestGoComprehensionsAdvanced:
@@ -217,9 +208,7 @@ def test_func_call() -> list:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.ListComprehension" in go_code
-        # Should contain abs function call
-        assert "mgen.Abs" in go_code
+        assert "comprehension = append(comprehension, mgen.AbsInt((x - 3)))" in go_code
 
     def test_comprehension_in_class_method(self):
         """Test comprehension used within a class method."""
@@ -233,7 +222,7 @@ class NumberProcessor:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.ListComprehension" in go_code
+        assert "for x := 0; x < n; x++ {" in go_code
         assert "obj.Multiplier" in go_code
 
     def test_nested_comprehensions_simple(self):
@@ -247,7 +236,8 @@ def test_nested() -> list:
         go_code = self.converter.convert_code(python_code)
 
         # Should have two separate comprehensions
-        assert go_code.count("mgen.ListComprehension") >= 1
+        assert go_code.count("comprehension := []int{}") == 2
+        assert "for _, y := range inner {" in go_code
 
     def test_comprehension_return_types(self):
         """Test that comprehensions generate appropriate return types."""
@@ -255,23 +245,261 @@ def test_nested() -> list:
 def test_types() -> None:
     nums = [x for x in range(3)]           # Should be slice
     squares = {x: x*x for x in range(3)}   # Should be map
-    unique = {x for x in range(3)}         # Should be set (map[T]bool)
+    unique = {x for x in range(3)}         # Should be set (*mgen.Set[T])
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.ListComprehension" in go_code
-        assert "mgen.DictComprehension" in go_code
-        assert "mgen.SetComprehension" in go_code
+        assert "var nums []int = func() []int {" in go_code
+        assert "var squares *mgen.Dict[int, int] = func() *mgen.Dict[int, int] {" in go_code
+        assert "var unique *mgen.Set[int] = func() *mgen.Set[int] {" in go_code
 
     def test_comprehension_with_multiple_variables(self):
-        """Test comprehension with multiple loop variables (simplified)."""
+        """Test comprehension with multiple loop variables."""
         python_code = """
 def test_multi_vars() -> list:
-    # Simplified - Go doesn't have tuple unpacking like Python
     return [x + y for x in range(3) for y in range(2)]
 """
         go_code = self.converter.convert_code(python_code)
 
-        # For now, just check that we get basic comprehension structure
-        # Full nested comprehensions would be complex in Go
-        assert "mgen.ListComprehension" in go_code
\ No newline at end of file
+        # Each for clause becomes its own loop, innermost last
+        assert "for x := 0; x < 3; x++ {" in go_code
+        assert "for y := 0; y < 2; y++ {" in go_code
+
+class TestGoComprehensionScoping:
+    """Test comprehension loop variables have their own scope, as in Python 3."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_loop_variable_shadows_outer_types(self):
+        """Test outer Optional/bool variables don't change how the loop variable is used."""
+        python_code = """
+from typing import Optional
+
+def shadow() -> None:
+    x: Optional[int] = None
+    flag: bool = True
+    squares = [x * x for x in range(3)]
+    shifted = [flag + 1 for flag in range(3)]
+    print(x, flag)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "for x := 0; x < 3; x++ {\n    comprehension = append(comprehension, (x * x))" in go_code
+        assert "for flag := 0; flag < 3; flag++ {\n    comprehension = append(comprehension, (flag + 1))" in go_code
+        # Outside the comprehension the outer variables keep their types
+        assert "mgen.Print(mgen.NoneIfNil(x), flag)" in go_code
+
+    def test_outer_variable_unchanged(self, go_run_python):
+        """Test an outer x keeps its value after [x for x in range(3)]."""
+        python_code = """
+def main() -> None:
+    x: int = 10
+    values = [x for x in range(3)]
+    doubled = {x: x * 2 for x in range(3)}
+    print(x, len(values), len(doubled))
+"""
+        assert go_run_python(python_code).strip() == "10 3 3"
+
+
+class TestGoComprehensionSources:
+    """Test enumerate()/zip() sources and tuple targets in comprehensions."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_enumerate_with_filter(self):
+        """Test a filtered comprehension over enumerate() unpacks mgen.Pair fields."""
+        python_code = """
+def long_words(xs: list[str]) -> list:
+    return [(i, x) for i, x in enumerate(xs) if len(x) > 1]
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "    for _, pair := range mgen.Enumerate(xs, 0) {\n    i, x := pair.First, pair.Second\n" in go_code
+        assert "    if !((mgen.LenString(x) > 1)) { continue }" in go_code
+        assert "    comprehension = append(comprehension, mgen.PyTuple{i, x})" in go_code
+
+    def test_zip_source(self):
+        """Test zip() pairs two slices for a tuple target."""
+        python_code = """
+def add(a: list[int], b: list[int]) -> list[int]:
+    return [x + y for x, y in zip(a, b)]
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "for _, pair := range mgen.Zip(a, b) {\n    x, y := pair.First, pair.Second" in go_code
+
+    def test_enumerate_mixed_end_to_end(self, go_run_python):
+        """Test enumerate/zip sources inside filtered, nested and dict comprehensions."""
+        python_code = """
+def main() -> None:
+    xs: list[str] = ["a", "bb", "ccc", "dd"]
+    picked = [(i, x) for i, x in enumerate(xs) if len(x) > 1]
+    weighted = [len(x) * i for i, x in enumerate(xs, 1) if i % 2 == 0]
+    ns: list[int] = [1, 2, 3]
+    sums = [a + b for a, b in zip(ns, [10, 20, 30, 40])]
+    grid: list[list[int]] = [[1, 2], [3, 4]]
+    scaled = [[v * i for v in row] for i, row in enumerate(grid)]
+    index = {x: i for i, x in enumerate(xs)}
+    print(len(picked), weighted[1], sums[2], len(sums), scaled[1][1], index["ccc"])
+"""
+        assert go_run_python(python_code).strip() == "3 8 33 3 4 2"
+
+    def test_iterator_sources_runtime(self, go_run):
+        """Test Range iterators collect into slices and pairs print as tuples."""
+        output = go_run(
+            """
+    evens := mgen.ListComprehension[int, int](mgen.Collect(mgen.NewRange(10, 0, -3).Iter()), func(x int) int { return x * 2 })
+    mgen.Print(len(evens), evens[0], evens[3])
+    mgen.Print(mgen.Enumerate([]string{"a"}, 5)[0], len(mgen.Zip([]int{1, 2}, []bool{true})))
+"""
+        )
+        assert output.splitlines() == ["4 20 2", "(5, 'a') 1"]
+
+
+class TestGoComprehensionClauses:
+    """Test comprehensions with several if clauses and several for clauses."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_multiple_ifs_are_anded(self):
+        """Test every if clause reaches the filter, not just the first."""
+        python_code = """
+def f(xs: list[int], tag: object) -> list:
+    return [tag for x in xs if x % 2 == 0 if x % 3 == 0]
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "return (mgen.PyMod(x, 2) == 0) && (mgen.PyMod(x, 3) == 0)" in go_code
+
+    def test_nested_fors_lower_to_loops(self):
+        """Test several for clauses become nested loops in a closure."""
+        python_code = """
+def f(xs: list[int], ys: list[int]) -> list[int]:
+    return [x * y for x in xs for y in ys if x != y]
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "for _, x := range xs {" in go_code
+        assert "for _, y := range ys {" in go_code
+        assert "if !((x != y)) { continue }" in go_code
+
+    def test_clauses_end_to_end(self, go_run_python):
+        """Test filtered and nested list, dict and set comprehensions match Python."""
+        python_code = """
+def main() -> None:
+    xs: list[int] = [1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12]
+    ys: list[int] = [1, 2, 3]
+    grid: list[list[int]] = [[1, 2], [0, 3], [4]]
+    a = [x for x in xs if x % 2 == 0 if x % 3 == 0]
+    b = [x * y for x in xs for y in ys if x + y == 5]
+    c = {x: y for x in ys for y in ys if x != y}
+    d = {x * y for x in ys for y in ys}
+    e = [v for row in grid for v in row if v > 1]
+    f = {i: i * i for i in range(10) if i % 2 == 1 if i > 3}
+    g = {x % 4 for x in xs if x > 2 if x < 9}
+    h = [(i, j) for i in range(3) for j in range(i)]
+    print(len(a), a[1], b[2], len(c), c[3], len(d), e[0], len(e))
+    print(len(f), f[7], len(g), len(h), h[2][1])
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "2 12 4 3 2 6 2 3",
+            "3 49 4 3 1",
+        ]
+
+
+class TestGoTypedComprehensionLoops:
+    """Test comprehensions with known types run as inline loops and dynamic ones keep the helpers."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_range_clauses(self):
+        """Test literal steps count with an int and other ranges iterate the materialized slice."""
+        python_code = """
+def f(n: int, k: int, xs: list[int]) -> None:
+    down = [i for i in range(n, 0, -1)]
+    zeros = [0 for _ in range(n)]
+    stepped = [i for i in range(0, n, k)]
+    indices = [i for i in range(len(xs))]
+    print(down, zeros, stepped, indices)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "for i := n; i > 0; i-- {" in go_code
+        assert "for index1 := 0; index1 < n; index1++ {" in go_code
+        assert "for _, i := range mgen.NewRange(0, n, k).ToSlice() {" in go_code
+        assert "for _, i := range mgen.NewRange(mgen.Len[int](xs)).ToSlice() {" in go_code
+
+    def test_list_elements(self):
+        """Test elements built by list displays and list repetition give a slice of slices."""
+        python_code = """
+def f(n: int) -> None:
+    grid = [[0] * n for _ in range(n)]
+    rows = [[i, i] for i in range(n)]
+    print(grid, rows)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "var grid [][]int = func() [][]int {\n    comprehension := [][]int{}" in go_code
+        assert "comprehension = append(comprehension, mgen.RepeatSlice([]int{0}, n))" in go_code
+        assert "var rows [][]int = func() [][]int {" in go_code
+
+    def test_dynamic_comprehensions_keep_helpers(self):
+        """Test comprehensions producing or iterating interface{} values use the generic helpers."""
+        python_code = """
+def f(n: int, tag: object) -> None:
+    tags = [tag for x in range(n)]
+    print(tags)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.ListComprehensionFromRange[interface{}](mgen.NewRange(n), " in go_code
+
+    def test_unused_markers_stay_outside_closures(self):
+        """Test unused variables are marked before the final return, not inside a comprehension's closure."""
+        python_code = """
+def f(n: int) -> list[int]:
+    unused = [x for x in range(n)]
+    return [x * 2 for x in range(n)]
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "    _ = unused\n    return func() []int {" in go_code
+
+    def test_typed_loops_end_to_end(self, go_run_python):
+        """Test typed comprehensions over ranges, slices, strings, dicts and pairs match Python."""
+        python_code = """
+def squares(n: int) -> list[int]:
+    unused = {x for x in range(n)}
+    return [x * x for x in range(n)]
+
+
+def main() -> None:
+    words: list[str] = ["bb", "a", "ccc"]
+    ages: dict[str, int] = {"ann": 3, "bob": 40}
+    print(squares(4), [i for i in range(10, 0, -3)], [0 for _ in range(3)], [i for i in range(5, 1)])
+    print({w: len(w) for w in words if w != "a"}, {len(w) % 2 for w in words}, [c for c in "héllo" if c != "l"])
+    print([k for k, v in ages.items() if v > 10], [i * len(w) for i, w in enumerate(words, 1)])
+    print({a: b for a, b in zip(words, [1.5, 2.5])}, [x * 2 for x in [1, 2, 3] if x > 1])
+    grid = [[0] * 2 for _ in range(2)]
+    grid[0][1] = 5
+    print(grid, [[i] * i for i in range(3)], [[w, w] for w in words[:2]])
+
+
+main()
+"""
+        expected = [
+            "[0, 1, 4, 9] [10, 7, 4, 1] [0, 0, 0] []",
+            "{'bb': 2, 'ccc': 3} {0, 1} ['h', 'é', 'o']",
+            "['bob'] [2, 2, 9]",
+            "{'bb': 1.5, 'a': 2.5} [4, 6]",
+            "[[0, 5], [0, 0]] [[], [1], [2, 2]] [['bb', 'bb'], ['a', 'a']]",
+        ]
+        assert go_run_python(python_code).splitlines() == expected
@@ -0,0 +1,91 @@
+"""Tests for Go backend list()/tuple()/set()/dict() conversions."""
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+
+class TestGoContainerConstructors:
+    """Test container constructor code generation."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_list_copy_stays_typed(self):
+        """Test list() of a typed slice copies it without boxing."""
+        python_code = """
+def clone(nums: list[int]) -> list[int]:
+    copy = list(nums)
+    return copy
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "copy := append([]int{}, nums...)" in go_code
+
+    def test_dynamic_constructors(self):
+        """Test other sources use the runtime conversions."""
+        python_code = """
+def convert(text: str, nums: list[int]) -> None:
+    chars = list(text)
+    unique = set(nums)
+    pairs = dict([("a", 1), ("b", 2)])
+    t = tuple(range(3))
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "chars := mgen.StrOps.Chars(text)" in go_code
+        assert "unique := mgen.NewSet(nums...)" in go_code
+        assert 'pairs := mgen.ToDict([]interface{}{mgen.PyTuple{"a", 1}, mgen.PyTuple{"b", 2}})' in go_code
+        assert "t := mgen.ToTuple(mgen.NewRange(3))" in go_code
+
+
+class TestGoContainerConversionRuntime:
+    """Test runtime conversions follow Python's constructor semantics."""
+
+    def test_conversions(self, go_run):
+        """Test list("abc"), set([1, 1, 2]), dict(pairs) and tuple(range)."""
+        output = go_run(
+            """
+    mgen.Print(mgen.Repr(mgen.ToList("abc")), mgen.ToSet([]int{1, 1, 2}).Len())
+    mgen.Print(mgen.Repr(mgen.ToDict([]interface{}{[]interface{}{"a", 1}, "bc"})))
+    mgen.Print(mgen.Repr(mgen.ToTuple(mgen.NewRange(3))), mgen.Repr(mgen.ToList(map[string]int{"y": 1, "x": 2})))
+"""
+        )
+        assert output.splitlines() == ["['a', 'b', 'c'] 2", "{'a': 1, 'b': 'c'}", "(0, 1, 2) ['x', 'y']"]
+
+    def test_tuples_print_as_tuples(self, go_run_python):
+        """Test tuple displays and tuple() print with parentheses, one-item tuples with a trailing comma."""
+        python_code = """
+def main() -> None:
+    t = (1, 2)
+    words = tuple("ab")
+    print(t, (1.5,), (), words, words[::-1], tuple(range(1)))
+    pairs = [(i, str(i)) for i in range(2)]
+    print(pairs, sorted([(2, "b"), (1, "a")]), {"k": (3,)})
+    print(str(t), repr(("x",)), f"{t}", "%s" % (t,), t == (1, 2), words.count("a"))
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "(1, 2) (1.5,) () ('a', 'b') ('b', 'a') (0,)",
+            "[(0, '0'), (1, '1')] [(1, 'a'), (2, 'b')] {'k': (3,)}",
+            "(1, 2) ('x',) (1, 2) (1, 2) True 1",
+        ]
+
+    def test_invalid_conversions(self, go_run):
+        """Test invalid dict/set sources raise Python's errors."""
+        output = go_run(
+            """
+    try := func(fn func()) {
+        defer func() { mgen.Print(recover().(error).Error()) }()
+        fn()
+    }
+    try(func() { mgen.ToDict([]interface{}{[]int{1, 2, 3}}) })
+    try(func() { mgen.ToDict([]int{1}) })
+    try(func() { mgen.ToSet([]interface{}{[]int{1}}) })
+    try(func() { mgen.ToList(5) })
+"""
+        )
+        assert output.splitlines() == [
+            "ValueError: dictionary update sequence element #0 has length 3; 2 is required",
+            "TypeError: cannot convert dictionary update sequence element #0 to a sequence",
+            "TypeError: unhashable type: 'list'",
+            "TypeError: 'int' object is not iterable",
+        ]
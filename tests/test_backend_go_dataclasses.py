@@ -0,0 +1,139 @@
+"""Tests for dataclasses in the Go backend."""
+
+import re
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+DATACLASS_PROGRAM = """
+from dataclasses import dataclass, field
+
+
+@dataclass
+class Point:
+    x: int
+    y: int = 0
+
+
+@dataclass(order=True)
+class Version:
+    major: int
+    minor: int = 0
+    label: str = field(default="", compare=False)
+
+
+@dataclass
+class Inventory:
+    owner: str
+    items: list[str] = field(default_factory=list)
+    counts: dict[str, int] = field(default_factory=dict)
+    total: int = field(default=0, init=False, repr=False)
+
+    def __post_init__(self) -> None:
+        self.total = len(self.items)
+
+    def add(self, item: str) -> None:
+        self.items.append(item)
+        self.total += 1
+
+
+@dataclass(eq=False)
+class Node:
+    name: str
+
+    def __repr__(self) -> str:
+        return "Node " + self.name
+
+
+def main() -> None:
+    p = Point(1, 2)
+    print(p, Point(3), p == Point(1, 2), p != Point(1))
+    print(repr(Point(y=5, x=4)))
+    a = Version(1, 2, "beta")
+    b = Version(1, 10)
+    print(a < b, a <= b, a > b, a >= b, a == Version(1, 2, "rc"))
+    inv = Inventory("ann")
+    other = Inventory("bob", ["x"])
+    inv.add("pen")
+    print(inv, other, inv.total, other.total)
+    print(Inventory("cy").items)
+    print(Node("n"))
+"""
+
+
+class TestGoDataclasses:
+    """Test @dataclass classes become structs with a constructor, __eq__, ordering and repr."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_dataclass_codegen(self):
+        """Test the generated constructor, repr and tuple comparisons of the fields."""
+        go_code = self.converter.convert_code(DATACLASS_PROGRAM)
+
+        assert "type Point struct {\n    X int\n    Y int\n}" in go_code
+        assert (
+            "func NewInventory(owner string, items []string, counts *mgen.Dict[string, int]) Inventory {\n"
+            "    obj := Inventory{}\n"
+            "    obj.Owner = owner\n"
+            "    obj.Items = items\n"
+            "    obj.Counts = counts\n"
+            "    obj.Total = 0\n"
+            "    obj.PostInit()\n"
+            "    return obj\n"
+            "}"
+        ) in go_code
+        assert 'return (((("Point(x=" + mgen.Repr(obj.X)) + ", y=") + mgen.Repr(obj.Y)) + ")")' in go_code
+        # The label is left out of comparisons
+        assert (
+            "func (obj *Version) PyLt(other Version) bool {\n"
+            '    return mgen.Compare("<", mgen.PyTuple{obj.Major, obj.Minor}, mgen.PyTuple{other.Major, other.Minor})'
+        ) in go_code
+        # Defaults are bound at the call site, and each default_factory makes a new value
+        assert 'b := NewVersion(1, 10, "")' in go_code
+        assert 'inv := NewInventory("ann", []string{}, mgen.NewDict[string, int]())' in go_code
+        # The user's __repr__ is kept, and eq=False generates no __eq__
+        assert 'return ("Node " + obj.Name)' in go_code
+        assert "func (obj *Node) PyEq(" not in go_code
+
+    def test_dataclass_end_to_end(self, go_run_python):
+        """Test construction, equality, ordering and repr match Python."""
+        assert go_run_python(DATACLASS_PROGRAM) == python_output(DATACLASS_PROGRAM + "\nmain()\n")
+
+    @pytest.mark.parametrize(
+        "fields, message",
+        [
+            ("    x: int = 0\n    y: int\n", "TypeError: non-default argument 'y' follows default argument"),
+            ("    xs: list[int] = []\n", "ValueError: mutable default <class 'list'> for field xs is not allowed"),
+            (
+                "    x: int = field(default=0, default_factory=int)\n",
+                "ValueError: cannot specify both default and default_factory",
+            ),
+        ],
+    )
+    def test_invalid_fields(self, fields, message):
+        """Test fields Python rejects when the class is defined are rejected at transpile time."""
+        python_code = "from dataclasses import dataclass, field\n\n@dataclass\nclass A:\n" + fields
+        with pytest.raises(TypeMappingError, match=re.escape(message)):
+            self.converter.convert_code(python_code)
+
+    def test_order_overwrite(self):
+        """Test order=True with a user-defined ordering method is rejected like Python's TypeError."""
+        python_code = """
+from dataclasses import dataclass
+
+
+@dataclass(order=True)
+class A:
+    x: int
+
+    def __lt__(self, other: "A") -> bool:
+        return self.x > other.x
+"""
+        message = "TypeError: Cannot overwrite attribute __lt__ in class A"
+        with pytest.raises(TypeMappingError, match=re.escape(message)):
+            self.converter.convert_code(python_code)
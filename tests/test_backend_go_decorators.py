@@ -0,0 +1,140 @@
+"""Tests for user-defined decorators in the Go backend."""
+
+import re
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+DECORATED_PROGRAM = """
+import functools
+
+
+def logged(func):
+    print("decorating", func.__name__)
+
+    @functools.wraps(func)
+    def wrapper(*args, **kwargs):
+        print("calling", func.__name__)
+        result = func(*args, **kwargs)
+        print("returned", result)
+        return result
+
+    return wrapper
+
+
+def twice(func):
+    def wrapper(*args):
+        func(*args)
+        func(*args)
+
+    return wrapper
+
+
+def non_negative(func):
+    def check(n: int) -> int:
+        if n < 0:
+            raise ValueError("negative: " + str(n))
+        return func(n)
+
+    return check
+
+
+@logged
+def add(a: int, b: int) -> int:
+    return a + b
+
+
+@twice
+@twice
+def greet(name: str) -> None:
+    print("hello", name)
+
+
+@non_negative
+@logged
+def fact(n: int) -> int:
+    if n <= 1:
+        return 1
+    return n * fact(n - 1)
+
+
+def main() -> None:
+    print(add(2, 3))
+    greet("bob")
+    print(fact(3))
+    try:
+        fact(-1)
+    except ValueError as e:
+        print("error", e)
+"""
+
+
+class TestGoDecorators:
+    """Test decorators specialized to the signatures of the functions they wrap."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_decorated_function_codegen(self):
+        """Test the decorator is typed for the function, and applied bottom-up in init()."""
+        go_code = self.converter.convert_code(DECORATED_PROGRAM)
+
+        assert "func addWrapped(a int, b int) int {" in go_code
+        assert (
+            "func loggedAdd(func_ func(int, int) int) func(int, int) int {\n"
+            '    mgen.Print("decorating", "add")\n'
+            "    wrapper := func(a int, b int) int {\n"
+            '    mgen.Print("calling", "add")\n'
+            "    result := func_(a, b)\n"
+        ) in go_code
+        assert "var add func(int, int) int\n\nfunc init() {\n    add = loggedAdd(addWrapped)\n}" in go_code
+        assert "fact = non_negativeFact(loggedFact(factWrapped))" in go_code
+        # The recursive call goes through the decorated function
+        assert "return (n * fact((n - 1)))" in go_code
+        assert go_code.count("func twiceGreet(") == 1
+        assert "func logged(" not in go_code
+
+    def test_decorated_function_end_to_end(self, go_run_python):
+        """Test decoration time, wrapper order and recursion through the wrappers match Python."""
+        assert go_run_python(DECORATED_PROGRAM) == python_output(DECORATED_PROGRAM + "\nmain()\n")
+
+    @pytest.mark.parametrize(
+        "python_code, message",
+        [
+            (
+                "def repeat(n):\n    return n\n\n@repeat(2)\ndef f(x: int) -> int:\n    return x\n",
+                "Decorator factories are not supported: @repeat(2)",
+            ),
+            ("@property\ndef f(x: int) -> int:\n    return x\n", "Unsupported decorator: @property"),
+            (
+                "def logged(func):\n    return func\n\n@logged\ndef f(x: int) -> int:\n    return x\n\ng = logged(f)\n",
+                "Decorator logged can only be applied with @",
+            ),
+            (
+                "def logged(func):\n"
+                "    def wrapper(*args):\n"
+                "        result = func(*args)\n"
+                "        return result\n"
+                "    return wrapper\n\n"
+                "@logged\ndef f(x: int) -> None:\n    print(x)\n",
+                "f returns None, so its result cannot be used: func(*args)",
+            ),
+            (
+                "def twice(func):\n    return func\n\n@twice\ndef f(x) -> None:\n    print(x)\n",
+                "Decorated function f needs annotated positional parameters",
+            ),
+            (
+                "def twice(func):\n    return func\n\n"
+                "class A:\n    @twice\n    def f(self) -> None:\n        print(1)\n",
+                "Decorators on methods are not supported: @twice on A.f",
+            ),
+        ],
+    )
+    def test_unsupported_decorators(self, python_code, message):
+        """Test decorators that cannot be specialized are rejected at transpile time."""
+        with pytest.raises(TypeMappingError, match=re.escape(message)):
+            self.converter.convert_code(python_code)
@@ -0,0 +1,141 @@
+"""Tests for Go backend del statement support."""
+
+import pytest
+
+from mgen.backends.errors import TypeMappingError
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+
+class TestGoDelConversion:
+    """Test del statement code generation."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_del_list_item(self):
+        """Test del on a list index passes the slice by pointer."""
+        python_code = """
+def drop_first(items: list[int]) -> list[int]:
+    del items[0]
+    return items
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.DelItem(items, 0)" in go_code
+
+    def test_del_dict_key(self):
+        """Test del on a typed dict key deletes through the dict."""
+        python_code = """
+def forget(counts: dict[str, int]) -> None:
+    del counts["a"]
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'counts.Delete("a")' in go_code
+
+    def test_del_slice(self):
+        """Test del on a slice with omitted bounds."""
+        python_code = """
+def trim(items: list[int]) -> None:
+    del items[1:3]
+    del items[::2]
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.DelSlice(items, mgen.NewSlice(1, 3, nil))" in go_code
+        assert "mgen.DelSlice(items, mgen.NewSlice(nil, nil, 2))" in go_code
+
+    def test_del_attribute_in_method(self):
+        """Test del self.attr inside a method uses the receiver."""
+        python_code = """
+class Cache:
+    def __init__(self, size: int):
+        self.size = size
+
+    def reset(self) -> None:
+        del self.size
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'mgen.DelAttr(obj, "size")' in go_code
+
+    def test_del_multiple_targets(self):
+        """Test del with several comma-separated targets."""
+        python_code = """
+def clear_two(counts: dict[str, int]) -> None:
+    del counts["a"], counts["b"]
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'counts.Delete("a")' in go_code
+        assert 'counts.Delete("b")' in go_code
+
+    def test_del_name_unsupported(self):
+        """Test del of a bare local name is rejected."""
+        python_code = """
+def unbind() -> None:
+    x: int = 1
+    del x
+"""
+        with pytest.raises(TypeMappingError):
+            self.converter.convert_code(python_code)
+
+
+class TestGoDelRuntime:
+    """Test runtime deletion helpers against Python semantics."""
+
+    def test_del_item_and_slice(self, go_run):
+        """Test DelItem and DelSlice on slices, including negative indices."""
+        output = go_run(
+            """
+    xs := []int{0, 1, 2, 3, 4, 5}
+    mgen.DelItem(&xs, -1)
+    mgen.Print(xs)
+    mgen.DelSlice(&xs, mgen.NewSlice(1, 3, nil))
+    mgen.Print(xs)
+    ys := []int{0, 1, 2, 3, 4, 5}
+    mgen.DelSlice(&ys, mgen.NewSlice(nil, nil, -2))
+    mgen.Print(ys)
+"""
+        )
+        assert output.splitlines() == ["[0, 1, 2, 3, 4]", "[0, 3, 4]", "[0, 2, 4]"]
+
+    def test_del_errors(self, go_run):
+        """Test KeyError, IndexError and AttributeError are raised like Python."""
+        output = go_run(
+            """
+    try := func(fn func()) {
+        defer func() { mgen.Print(recover().(error).Error()) }()
+        fn()
+    }
+    try(func() { mgen.DelItem(map[string]int{"a": 1}, "b") })
+    try(func() { xs := []int{1}; mgen.DelItem(&xs, 3) })
+    type point struct{ X int }
+    try(func() { mgen.DelAttr(&point{X: 1}, "y") })
+"""
+        )
+        assert output.splitlines() == [
+            "KeyError: 'b'",
+            "IndexError: list assignment index out of range",
+            "AttributeError: 'point' object has no attribute 'y'",
+        ]
+
+    def test_del_attr_resets_field(self, go_run_python):
+        """Test del obj.attr end to end on a generated struct."""
+        python_code = """
+class Box:
+    def __init__(self, width: int):
+        self.width: int = width
+
+def main() -> None:
+    b = Box(5)
+    del b.width
+    print(b.width)
+    counts: dict[str, int] = {}
+    counts["a"] = 1
+    counts["b"] = 2
+    del counts["a"]
+    print(len(counts))
+"""
+        assert go_run_python(python_code).splitlines() == ["0", "1"]
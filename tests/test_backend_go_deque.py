@@ -0,0 +1,205 @@
+"""Tests for the Go backend's Deque (collections.deque) and list-queue lowering."""
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+BFS = """
+def bfs(graph: dict[int, list[int]], start: int) -> int:
+    seen: dict[int, bool] = {start: True}
+    queue: list[int] = [start]
+    while queue:
+        node = queue.pop(0)
+        print(node)
+        for nxt in graph[node]:
+            if nxt not in seen:
+                seen[nxt] = True
+                queue.append(nxt)
+    return len(seen)
+
+
+def main() -> None:
+    graph: dict[int, list[int]] = {0: [1, 2], 1: [3], 2: [3, 4], 3: [], 4: [0]}
+    print(bfs(graph, 0))
+"""
+
+
+class TestGoListQueueLowering:
+    """Test lists used as FIFO queues are lowered to mgen.Deque."""
+
+    def test_bfs_queue_codegen(self):
+        """Test the BFS queue becomes a Deque and pop(0) becomes PopLeft."""
+        go_code = MGenPythonToGoConverter().convert_code(BFS)
+        assert "var queue *mgen.Deque[int] = mgen.NewDeque[int](start)" in go_code
+        assert "for queue.Len() > 0 {" in go_code
+        assert "node := queue.PopLeft()" in go_code
+        assert "queue.Append(nxt)" in go_code
+
+    def test_bfs_end_to_end(self, go_run_python):
+        """Test the lowered BFS visits nodes in the same order as Python."""
+        assert go_run_python(BFS).splitlines() == ["0", "1", "2", "3", "4", "5"]
+
+    @pytest.mark.parametrize(
+        "use",
+        [
+            "print(queue[0])",
+            "other = queue",
+            "queue = list(range(3))",
+            "queue.pop()",
+        ],
+    )
+    def test_other_uses_keep_the_list(self, use):
+        """Test a list used other than as a queue stays a slice."""
+        python_code = f"""
+def f() -> None:
+    queue: list[int] = [1, 2]
+    queue.append(3)
+    x = queue.pop(0)
+    {use}
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        if use == "other = queue":
+            # A list an alias shares while it grows is held by pointer
+            assert "var queue *[]int = mgen.Ref([]int{1, 2})" in go_code
+        else:
+            assert "var queue []int = []int{1, 2}" in go_code
+        assert "mgen.Deque" not in go_code
+
+    def test_list_without_pop_front_stays_a_slice(self):
+        """Test append-only lists are not lowered."""
+        python_code = """
+def f() -> int:
+    stack: list[int] = [1]
+    stack.append(2)
+    return len(stack)
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        assert "var stack []int = []int{1}" in go_code
+        assert "mgen.Deque" not in go_code
+
+
+class TestGoDeque:
+    """Test collections.deque lowers to the runtime Deque."""
+
+    def test_deque_end_to_end(self, go_run_python):
+        """Test construction, both ends, indexing, iteration and truthiness."""
+        python_code = """
+from collections import deque
+
+
+def window(values: list[int]) -> None:
+    d: deque[int] = deque()
+    for v in values:
+        d.append(v)
+        if len(d) > 3:
+            d.popleft()
+    d.appendleft(0)
+    print(d, d[0], d[-1], len(d))
+    total = 0
+    for x in d:
+        total += x
+    while d:
+        total -= d.pop()
+    print(total, not d)
+
+
+def main() -> None:
+    window([1, 2, 3, 4, 5])
+    e = deque([7, 8])
+    e.extend([9])
+    print(e.popleft(), e)
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        assert "d := mgen.NewDeque[int]()" in go_code
+        assert "e := mgen.NewDeque[int](7, 8)" in go_code
+        assert go_run_python(python_code).splitlines() == [
+            "deque([0, 3, 4, 5]) 0 5 4",
+            "0 True",
+            "7 deque([8, 9])",
+        ]
+
+    def test_deque_as_iterable_end_to_end(self, go_run_python):
+        """Test list(), in, sum(), min(), max() and sorted() iterate a deque's items."""
+        python_code = """
+from collections import deque
+
+
+def main() -> None:
+    q: deque[int] = deque([3, 1, 2])
+    q.append(5)
+    print(list(q), 2 in q, 7 in q, 7 not in q)
+    print(sum(q), min(q), max(q), sorted(q))
+    words = deque(["b", "a"])
+    print("a" in words, list(words), min(words))
+
+
+main()
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        assert "mgen.Sum[int](q.Items())" in go_code
+        assert go_run_python(python_code) == python_output(python_code)
+
+    def test_ring_buffer_runtime(self, go_run):
+        """Test wraparound and growth keep order, and empty pops raise IndexError."""
+        output = go_run(
+            """
+    d := mgen.NewDeque[int]()
+    for i := 0; i < 20; i++ {
+        if i%3 == 0 {
+            d.AppendLeft(i)
+        } else {
+            d.Append(i)
+        }
+        if i%4 == 0 {
+            d.PopLeft()
+        }
+    }
+    mgen.Print(d, d.Len())
+    d.Clear()
+    check(func() { d.Pop() })
+    check(func() { d.PopLeft() })
+    check(func() { d.Get(0) })
+"""
+        )
+        d: list[int] = []
+        for i in range(20):
+            if i % 3 == 0:
+                d.insert(0, i)
+            else:
+                d.append(i)
+            if i % 4 == 0:
+                d.pop(0)
+        assert output.splitlines() == [
+            f"deque({d!r}) {len(d)}",
+            "IndexError: pop from an empty deque",
+            "IndexError: pop from an empty deque",
+            "IndexError: deque index out of range",
+        ]
+
+    def test_pop_front_agrees_with_slice(self, go_run):
+        """Test draining a queue with Deque.PopLeft gives the items a slice pop(0) gives, in order.
+
+        BenchmarkDequePopLeft in the runtime's mgen_go_bench_test.go times the
+        two (make benchmark-go-runtime).
+        """
+        output = go_run(
+            """
+    const n = 1000
+    xs := make([]int, 0, n)
+    d := mgen.NewDeque[int]()
+    for i := 0; i < n; i++ {
+        xs = append(xs, i*i%97)
+        d.Append(i * i % 97)
+    }
+    same := true
+    for len(xs) > 0 {
+        // list.pop(0): shift the remaining items down
+        same = same && d.PopLeft() == xs[0]
+        xs = slices.Delete(xs, 0, 1)
+    }
+    mgen.Print(same, d.Len())
+""",
+            imports=("slices",),
+        )
+        assert output.strip() == "True 0"
@@ -0,0 +1,643 @@
+"""Tests for the Go backend exception hierarchy."""
+
+import builtins
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+
+class TestGoExceptionHierarchy:
+    """Test exception types match their base classes like Python's except clauses."""
+
+    @pytest.mark.parametrize(
+        "exc_type,base,expected",
+        [
+            ("ValueError", "ValueError", True),
+            ("ValueError", "Exception", True),
+            ("ValueError", "BaseException", True),
+            ("KeyError", "LookupError", True),
+            ("IndexError", "LookupError", True),
+            ("ZeroDivisionError", "ArithmeticError", True),
+            ("FileNotFoundError", "OSError", True),
+            ("UnicodeDecodeError", "ValueError", True),
+            ("NotImplementedError", "RuntimeError", True),
+            ("KeyError", "IndexError", False),
+            ("Exception", "ValueError", False),
+            ("KeyboardInterrupt", "Exception", False),
+        ],
+    )
+    def test_is_instance(self, go_run, exc_type, base, expected):
+        """Test IsInstance follows the parent chain, as issubclass() does in Python."""
+        output = go_run(f'    mgen.Print(mgen.IsInstance(mgen.NewPyError("{exc_type}", "x"), "{base}"))')
+
+        # The table mirrors Python's own class hierarchy
+        assert issubclass(getattr(builtins, exc_type), getattr(builtins, base)) == expected
+        assert output == f"{expected}\n"
+
+    def test_catch_subclass_via_base_class(self, go_run):
+        """Test an except clause naming a base class catches its subclasses and re-raises others."""
+        output = go_run(
+            """
+    try := func(body func(), excTypes ...string) {
+        defer func() {
+            if r := recover(); r != nil {
+                if err := mgen.ExceptionMatches(r, excTypes...); err != nil {
+                    mgen.Print("caught", err.Type, err.Message)
+                } else {
+                    mgen.Print("not caught:", mgen.Repr(r))
+                }
+            }
+        }()
+        body()
+    }
+    try(func() { mgen.Raise("KeyError", "'missing'") }, "LookupError")
+    try(func() { mgen.Raise("ValueError", "bad value") }, "Exception")
+    try(func() { mgen.Raise("ZeroDivisionError", "division by zero") }, "TypeError", "ArithmeticError")
+    try(func() { mgen.Raise("ValueError", "bad value") }, "LookupError")
+    try(func() { panic("not a Python exception") }, "BaseException")
+"""
+        )
+        assert output.splitlines() == [
+            "caught KeyError 'missing'",
+            "caught ValueError bad value",
+            "caught ZeroDivisionError division by zero",
+            "not caught: ValueError('bad value')",
+            "not caught: 'not a Python exception'",
+        ]
+
+    def test_register_exception(self, go_run):
+        """Test user-defined exception classes join the hierarchy."""
+        output = go_run(
+            """
+    mgen.RegisterException("AppError", "Exception")
+    mgen.RegisterException("ConfigError", "AppError")
+    err := mgen.NewPyError("ConfigError", "no such key")
+    mgen.Print(mgen.IsInstance(err, "AppError"), mgen.IsInstance(err, "Exception"), mgen.IsInstance(err, "ValueError"))
+    mgen.Print(mgen.IsSubclass("AppError", "ConfigError"), mgen.IsInstance(nil, "Exception"))
+"""
+        )
+        assert output.splitlines() == ["True True False", "False False"]
+
+
+class TestGoCustomExceptions:
+    """Test user exception classes raise, match and bind like Python's."""
+
+    EXCEPTIONS = """
+class ValidationError(Exception):
+    def __init__(self, field: str, message: str):
+        super().__init__(message)
+        self.field = field
+
+
+class RangeError(ValidationError):
+    def __init__(self, field: str, low: int, high: int):
+        super().__init__(field, f"must be between {low} and {high}")
+        self.low = low
+        self.high = high
+
+
+class AppError(Exception):
+    pass
+
+
+def check_age(age: int) -> int:
+    if age < 0:
+        raise RangeError("age", 0, 150)
+    if age > 150:
+        raise ValidationError("age", "too large")
+    return age
+"""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_exception_class_codegen(self):
+        """Test exception classes embed mgen.PyError and register their base class."""
+        go_code = self.converter.convert_code(self.EXCEPTIONS)
+
+        assert "type ValidationError struct {\n    mgen.PyError\n    Field string\n}" in go_code
+        assert "type RangeError struct {\n    ValidationError\n    Low int\n    High int\n}" in go_code
+        # super().__init__(message) replaces the args taken from the call
+        assert '    obj.PyError = mgen.ExceptionArgs("ValidationError", message)' in go_code
+        assert '    obj.ValidationError = NewValidationError(field, ' in go_code
+        assert '    obj.Type = "RangeError"' in go_code
+        assert 'mgen.RegisterException("RangeError", "ValidationError")' in go_code
+        assert 'func NewAppError(args ...interface{}) AppError {' in go_code
+        assert '    panic(mgen.NewException(NewRangeError("age", 0, 150)))' in go_code
+
+    def test_except_clause_codegen(self):
+        """Test except clauses bind the raised instance and returns leave through the closure."""
+        python_code = (
+            self.EXCEPTIONS
+            + """
+def parse(text: str) -> int:
+    try:
+        return check_age(int(text))
+    except RangeError as e:
+        return e.low
+    except (ValueError, AppError):
+        return -1
+"""
+        )
+        go_code = self.converter.convert_code(python_code)
+
+        assert "if tryDone1, tryResult1 := func() (tryDone1 bool, tryResult1 int) {" in go_code
+        assert "e := *_exc.(interface{ AsRangeError() *RangeError }).AsRangeError()" in go_code
+        assert "tryDone1, tryResult1 = true, e.Low\n    return" in go_code
+        assert 'else if mgen.ExceptionMatches(_exc, "ValueError", "AppError") != nil {' in go_code
+        assert "    return true, check_age(mgen.ToInt(text))" in go_code
+        assert "}(); tryDone1 {\n    return tryResult1\n    }" in go_code
+
+    def test_custom_exception_end_to_end(self, go_run_python):
+        """Test raising across functions and catching by class, base class and Exception."""
+        python_code = (
+            self.EXCEPTIONS
+            + """
+def parse(text: str) -> int:
+    try:
+        return check_age(int(text))
+    except RangeError as e:
+        print("range:", e.field, e.low, e.high, e)
+        return -1
+    except ValidationError as e:
+        print("invalid:", e.field, str(e))
+        return -2
+    except ValueError as e:
+        print("not a number:", e)
+        return -3
+
+
+def main() -> None:
+    print(parse("42"), parse("-5"), parse("200"), parse("abc"))
+    try:
+        raise AppError("boom", 3)
+    except Exception as e:
+        print("caught", e, repr(e))
+    total = 0
+    try:
+        total = check_age(10)
+        check_age(-1)
+    except ValidationError as err:
+        print("base class:", err.field, repr(err))
+    print(total)
+    try:
+        try:
+            raise AppError()
+        except AppError:
+            print("inner")
+            raise
+    except AppError as e:
+        print("outer", repr(e))
+"""
+        )
+        assert go_run_python(python_code).splitlines() == [
+            "range: age 0 150 must be between 0 and 150",
+            "invalid: age too large",
+            "not a number: invalid literal for int() with base 10: 'abc'",
+            "42 -1 -2 -3",
+            "caught ('boom', 3) AppError('boom', 3)",
+            "base class: age RangeError('must be between 0 and 150')",
+            "10",
+            "inner",
+            "outer AppError()",
+        ]
+
+    def test_unmatched_exception_propagates(self, go_run_python):
+        """Test an exception no clause matches keeps propagating."""
+        python_code = (
+            self.EXCEPTIONS
+            + """
+def main() -> None:
+    try:
+        check_age(500)
+    except AppError:
+        print("wrong handler")
+"""
+        )
+        with pytest.raises(AssertionError, match="ValidationError: too large"):
+            go_run_python(python_code)
+
+    def test_function_ending_in_raise(self, go_run_python):
+        """Test a raise ends a function like a return, so none is needed after it."""
+        python_code = (
+            self.EXCEPTIONS
+            + """
+def positive(x: int) -> int:
+    if x > 0:
+        return x
+    raise ValueError("not positive")
+
+
+def find(key: str) -> int:
+    raise AppError(key)
+
+
+def main() -> None:
+    print(positive(3))
+    try:
+        positive(-1)
+    except ValueError as e:
+        print("caught", e)
+    try:
+        find("k")
+    except AppError as e:
+        print("caught", e)
+"""
+        )
+        assert go_run_python(python_code) == "3\ncaught not positive\ncaught k\n"
+
+    def test_handler_reads_variable_named_r(self, go_run_python):
+        """Test the recovered value does not shadow a variable r the handlers use."""
+        python_code = """
+def parse(s: str) -> int:
+    r = 5
+    try:
+        return int(s)
+    except ValueError as e:
+        print(e, r)
+    return r
+
+
+def main() -> None:
+    r = 1
+    print(parse("x"), parse("7"))
+    try:
+        try:
+            print(int("y"))
+        except ValueError:
+            r += 1
+            raise
+    except ValueError as e:
+        print("again", e, r)
+"""
+        assert go_run_python(python_code) == (
+            "invalid literal for int() with base 10: 'x' 5\n"
+            "5 7\n"
+            "again invalid literal for int() with base 10: 'y' 2\n"
+        )
+
+
+
+class TestGoTryElseFinally:
+    """Test try statements with else and finally clauses."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_else_finally_codegen(self):
+        """Test finally is deferred first and the handlers skip exceptions from else."""
+        python_code = """
+def f(s: str) -> None:
+    try:
+        n = int(s)
+    except ValueError:
+        print("bad")
+    else:
+        print(n)
+    finally:
+        print("done")
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert (
+            "    tryOk1 := false\n"
+            "    defer func() {\n"
+            '    mgen.Print("done")\n'
+            "    }()\n"
+            "    defer func() {\n"
+            "    if tryOk1 {\n"
+            "    return\n"
+            "    }\n"
+            "    if _exc := recover(); _exc != nil {"
+        ) in go_code
+        assert "    n = mgen.ToInt(s)\n    tryOk1 = true\n    mgen.Print(n)\n    }()" in go_code
+
+    def test_return_in_finally_unsupported(self):
+        """Test a return inside finally is rejected rather than silently reordered."""
+        python_code = """
+def f() -> int:
+    try:
+        print("body")
+    finally:
+        return 1
+"""
+        with pytest.raises(TypeMappingError, match="return inside a finally clause"):
+            self.converter.convert_code(python_code)
+
+    def test_else_and_finally_end_to_end(self, go_run_python):
+        """Test else runs only after a successful body, and finally runs on every path."""
+        python_code = """
+def parse(s: str) -> int:
+    try:
+        n = int(s)
+    except ValueError:
+        print("bad", s)
+        return -1
+    else:
+        print("ok", n)
+        n = n * 2
+    finally:
+        print("finally", s)
+    return n
+
+
+def safe_div(a: int, b: int) -> int:
+    q = 0
+    try:
+        if b == 0:
+            raise ZeroDivisionError("division by zero")
+        q = a // b
+        if b == 1:
+            return q
+    except ZeroDivisionError as e:
+        print(e)
+        q = 0
+    else:
+        q += 100
+    return q
+
+
+def nested(x: int) -> None:
+    try:
+        try:
+            print("body", x)
+        except ValueError:
+            print("never")
+        else:
+            if x > 0:
+                raise ValueError("from else")
+            print("else done")
+        finally:
+            print("inner finally")
+    except ValueError as e:
+        print("outer caught", e)
+
+
+def main() -> None:
+    print(parse("21"))
+    print(parse("x"))
+    print(safe_div(7, 2), safe_div(7, 0), safe_div(7, 1))
+    nested(1)
+    nested(0)
+    try:
+        print("plain")
+    finally:
+        print("cleanup")
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "ok 21",
+            "finally 21",
+            "42",
+            "bad x",
+            "finally x",
+            "-1",
+            "division by zero",
+            "103 0 7",
+            "body 1",
+            "inner finally",
+            "outer caught from else",
+            "body 0",
+            "else done",
+            "inner finally",
+            "plain",
+            "cleanup",
+        ]
+
+
+RUNTIME_ERRORS_PROGRAM = """
+class AppError(Exception):
+    pass
+
+
+def main() -> None:
+    xs = [1, 2, 3]
+    n = 0
+    try:
+        print(xs[5])
+    except IndexError as e:
+        print(type(e).__name__, e)
+    try:
+        print(10 // n)
+    except ArithmeticError as e:
+        print(type(e).__name__, e)
+    try:
+        print(xs[n % n])
+    except ZeroDivisionError:
+        print("modulo")
+    try:
+        raise AppError("boom")
+    except Exception as e:
+        print(type(e).__name__ + ":", e)
+    print(type(n).__name__, type("s").__name__, type(xs).__name__)
+"""
+
+
+class TestGoRuntimeErrors:
+    """Test panics of the Go runtime itself are caught as the Python exceptions they stand for."""
+
+    def test_type_name_codegen(self):
+        """Test type(x).__name__ lowers to mgen.TypeName."""
+        python_code = """
+def name(e: Exception) -> str:
+    return type(e).__name__
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+
+        assert "return mgen.TypeName(e)" in go_code
+
+    def test_runtime_errors_match_python(self, go_run_python):
+        """Test out-of-range indexes and integer division by zero raise the Python exceptions."""
+        assert go_run_python(RUNTIME_ERRORS_PROGRAM) == python_output(RUNTIME_ERRORS_PROGRAM + "\nmain()\n")
+
+    def test_runtime_exception_mapping(self, go_run):
+        """Test ExceptionMatches converts Go runtime panics and leaves other panics alone."""
+        output = go_run(
+            """
+    catch := func(body func(), excType string) {
+        defer func() {
+            r := recover()
+            if err := mgen.ExceptionMatches(r, excType); err != nil {
+                mgen.Print(err.Error())
+            } else {
+                mgen.Print("not caught:", mgen.Repr(r))
+            }
+        }()
+        body()
+    }
+    var none *mgen.PyError
+    var value interface{} = "text"
+    catch(func() { _ = []int{}[mgen.LenString("ab")] }, "LookupError")
+    catch(func() { mgen.Print(none.Type) }, "AttributeError")
+    catch(func() { mgen.Print(value.(int)) }, "TypeError")
+    catch(func() { panic("plain") }, "Exception")
+    mgen.Print(mgen.TypeName(mgen.NewPyError("KeyError", "'k'")), mgen.TypeName(nil), mgen.TypeName(2.5))
+"""
+        )
+        assert output.splitlines() == [
+            "IndexError: list index out of range",
+            "AttributeError: 'NoneType' object has no attribute",
+            "TypeError: interface {} is string, not int",
+            "not caught: 'plain'",
+            "KeyError NoneType float",
+        ]
+
+
+class TestGoAssert:
+    """Test assert raises AssertionError and honours __debug__ (mgen.Debug)."""
+
+    ASSERTS = """
+def message(text: str) -> str:
+    print("building", text)
+    return text
+
+def main() -> None:
+    assert 1 < 2, message("unused")
+    try:
+        assert 2 < 1, message("boom")
+    except AssertionError as e:
+        print(e)
+    try:
+        assert len("ab") == 3
+    except AssertionError as e:
+        print("bare", repr(e))
+    try:
+        assert False, 42
+    except AssertionError as e:
+        print(e)
+"""
+
+    def test_assert_codegen(self):
+        """Test the check is guarded by mgen.Debug and raises AssertionError."""
+        go_code = MGenPythonToGoConverter().convert_code(self.ASSERTS)
+        assert 'if mgen.Debug && !((1 < 2)) { mgen.Raise("AssertionError", "%s", message("unused")) }' in go_code
+        assert 'mgen.Raise("AssertionError", "%s", mgen.ToStr(42))' in go_code
+        assert "panic(" not in go_code.split("func main")[1].split("defer")[0]
+
+    def test_message_evaluated_only_on_failure(self, go_run_python):
+        """Test a passing assert never builds its message, like Python."""
+        assert go_run_python(self.ASSERTS).splitlines() == [
+            "building boom",
+            "boom",
+            "bare AssertionError()",
+            "42",
+        ]
+
+    def test_set_optimized_skips_asserts(self, go_run):
+        """Test SetOptimized(true) turns asserts off, as python -O does."""
+        output = go_run(
+            """
+    calls := 0
+    test := func() bool { calls++; return false }
+    mgen.SetOptimized(true)
+    if mgen.Debug && !(test()) { mgen.Raise("AssertionError", "") }
+    mgen.Print(mgen.Debug, calls)
+    mgen.SetOptimized(false)
+    defer func() { mgen.Print(recover().(error).Error(), calls) }()
+    if mgen.Debug && !(test()) { mgen.Raise("AssertionError", "%s", "checked") }
+"""
+        )
+        assert output.splitlines() == ["False 0", "AssertionError: checked 1"]
+
+
+class TestGoMethodTry:
+    """Test try statements in methods, where handlers and loop bodies still see the receiver."""
+
+    PARSER = """
+class Parser:
+    def __init__(self, text: str):
+        self.text = text
+        self.errors = 0
+
+    def parse(self) -> int:
+        total = 0
+        for part in self.text.split(","):
+            try:
+                value = int(part)
+            except ValueError as e:
+                self.errors += 1
+                print("bad", e)
+            else:
+                total = total + value
+            finally:
+                print("saw", part)
+        return total
+
+    def first(self) -> int:
+        try:
+            return int(self.text.split(",")[0])
+        except (ValueError, IndexError):
+            return -1
+
+    def strict(self) -> int:
+        try:
+            return int(self.text)
+        except Exception:
+            print("reraise")
+            raise
+"""
+
+    def test_method_try_codegen(self):
+        """Test a returning try in a method leaves through the closure's named results."""
+        go_code = MGenPythonToGoConverter().convert_code(self.PARSER)
+        assert "func (obj *Parser) First() int {\n    if tryDone" in go_code
+        assert 'mgen.ExceptionMatches(_exc, "ValueError", "IndexError") != nil' in go_code
+        assert "obj.Errors += 1" in go_code
+        assert '    panic("unreachable")\n}' in go_code
+
+    def test_method_try_end_to_end(self, go_run_python):
+        """Test except, else, finally and re-raise in methods match Python."""
+        python_code = (
+            self.PARSER
+            + """
+
+def main() -> None:
+    p = Parser("1,x,3")
+    print(p.parse(), p.errors, p.first())
+    q = Parser("y")
+    print(q.first())
+    try:
+        q.strict()
+    except ValueError:
+        print("strict failed")
+    print(q.errors)
+"""
+        )
+        assert go_run_python(python_code).splitlines() == [
+            "saw 1",
+            "bad invalid literal for int() with base 10: 'x'",
+            "saw x",
+            "saw 3",
+            "4 1 1",
+            "-1",
+            "reraise",
+            "strict failed",
+            "0",
+        ]
+
+    def test_method_locals_reassigned(self, go_run_python):
+        """Test a method local reassigned at top level and in a loop is declared once."""
+        python_code = """
+class Counter:
+    def __init__(self):
+        self.seen = 0
+
+    def run(self, xs: list[int]) -> int:
+        best = 0
+        best = -1
+        for x in xs:
+            self.seen = self.seen + 1
+            if x > best:
+                best = x
+        return best
+
+
+def main() -> None:
+    c = Counter()
+    print(c.run([3, 9, 4]), c.seen)
+"""
+        assert go_run_python(python_code).splitlines() == ["9 3"]
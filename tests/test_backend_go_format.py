@@ -0,0 +1,394 @@
+"""Tests for Go backend format() and __format__ support."""
+
+import json
+import math
+
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+# Boundary values where Python's float repr switches between fixed and scientific notation
+FLOAT_REPR_CASES = [
+    0.0, -0.0, 1.0, -1.5, 0.1, 0.2 + 0.1, 1 / 3, 2.5, 100.0, 123456.789,
+    1e15, 9999999999999998.0, 1e16, 1.5e16, 12345678901234567890.0, 1e22, 1e100, 1.7976931348623157e308,
+    1e-4, 0.00012, 1e-5, 1.5e-5, 9.99e-5, 5e-324, 2.2250738585072014e-308,
+    -1e16, -1e-5, 123e-7, math.inf, -math.inf, math.nan,
+]
+
+
+def _go_float_literal(value: float) -> str:
+    """Return a Go expression for an exact float value."""
+    if math.isinf(value):
+        return "math.Inf(1)" if value > 0 else "math.Inf(-1)"
+    if math.isnan(value):
+        return "math.NaN()"
+    if value == 0 and math.copysign(1, value) < 0:
+        return "math.Copysign(0, -1)"
+    return value.hex()
+
+
+class TestGoFormatConversion:
+    """Test format()/f-string code generation."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_f_string_fields_use_format(self):
+        """Test f-string fields lower to mgen.Format with their spec."""
+        python_code = """
+def label(name: str, price: float) -> str:
+    return f"{name}: {price:.2f}"
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'mgen.Format(name, "") + ": " + mgen.Format(price, ".2f")' in go_code
+
+    def test_nested_spec_field(self):
+        """Test a spec containing a field is built before formatting."""
+        python_code = """
+def pad(x: int, width: int) -> str:
+    return f"{x:>{width}}"
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'mgen.Format(x, ">" + mgen.Format(width, ""))' in go_code
+
+    def test_format_builtin(self):
+        """Test format(value, spec) calls the runtime formatter."""
+        python_code = """
+def show(x: int) -> str:
+    return format(x, "04d")
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'mgen.Format(x, "04d")' in go_code
+
+    def test_dunder_format_method(self):
+        """Test __format__ becomes the PyFormattable method."""
+        python_code = """
+class Money:
+    def __init__(self, cents: int):
+        self.cents: int = cents
+
+    def __format__(self, spec: str) -> str:
+        return f"{self.cents}c"
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func (obj *Money) PyFormat(spec string) string {" in go_code
+        assert 'return mgen.Format(obj.Cents, "") + "c"' in go_code
+
+
+class TestGoFormatRuntime:
+    """Test the runtime formatter against Python output."""
+
+    def test_builtin_specs(self, go_run):
+        """Test width, fill, alignment and precision for built-in values."""
+        output = go_run(
+            """
+    mgen.Print(mgen.Format(3.14159, ".2f"), mgen.Format(-5, "04d"), mgen.Format(true, ""))
+    mgen.Print("[" + mgen.Format("ab", "^5") + "][" + mgen.Format(42, "*<6") + "][" + mgen.Format("abc", ".2") + "]")
+"""
+        )
+        assert output.splitlines() == ["3.14 -005 True", "[ ab  ][42****][ab]"]
+
+    def test_user_format_end_to_end(self, go_run_python):
+        """Test a class defining __format__ controls f-string and format() output."""
+        python_code = """
+class Money:
+    def __init__(self, cents: int):
+        self.cents: int = cents
+
+    def __format__(self, spec: str) -> str:
+        if spec == "USD":
+            return f"${self.cents // 100}.{self.cents % 100:02d}"
+        return f"{self.cents}c"
+
+def main() -> None:
+    m = Money(1205)
+    print(f"total={m:USD} raw={m}")
+    print(format(m, "USD"))
+"""
+        assert go_run_python(python_code).splitlines() == ["total=$12.05 raw=1205c", "$12.05"]
+
+    def test_float_repr_matches_python(self, go_run):
+        """Test ToStr on floats reproduces Python's repr across exponent boundaries."""
+        body = "\n".join(f"    mgen.Print({_go_float_literal(v)})" for v in FLOAT_REPR_CASES)
+        output = go_run(body, imports=("math",))
+        assert output.splitlines() == [repr(v) for v in FLOAT_REPR_CASES]
+
+
+# (value, spec) pairs covering the format spec mini-language, compared against CPython
+FORMAT_SPEC_CASES = [
+    (3.14159, ">10.3f"), (3.14159, "<10.3f"), (3.14159, "^10.3f"), (-3.14159, "=10.3f"), (2.5, ".0f"), (0.125, ".2f"),
+    (1234567, ","), (1234567, "_"), (-1234567, "+,"), (1234, "08,"), (1234, "010,"), (1234.5, "012,.1f"),
+    (1234567.0, ","), (1234567.891, ",.2f"), (12345.678, ",e"), (0xABCDEF, "_X"), (255, "#_b"),
+    (255, "b"), (255, "o"), (255, "x"), (255, "X"), (255, "#x"), (255, "#010x"), (-255, "#x"), (-5, "x"), (255, "#o"),
+    (42, "+"), (42, " "), (-42, " "), (42, "-"), (3, "=+5"), (-5, "04d"), (5, "n"), (65, "c"), (65, "3c"),
+    (12, "e"), (3.14159, "e"), (3.14159, ".2E"), (1e-7, "e"), (1.0, "#.0e"), (1.0, "#.0f"),
+    (3.14159, "g"), (100000.0, "g"), (1e6, "g"), (1e-5, "g"), (0.0001, "g"), (1.0, "#g"), (1e6, "G"), (1.5, "n"),
+    (1.0, ".1"), (0.0, ".1"), (1.25, ".3"), (2.0, ".3"), (0.000123456, ".2"), (123.0, ".3"), (123.0, ".3g"),
+    (1.0, "#.3"), (1e16, "#"), (1e-5, ".3"), (1.5, "10"),
+    (0.5, "%"), (1, ".0%"), (0.1234, ".1%"), (True, ">5"), (True, ".2f"), (False, "d"),
+    (-0.0, ".1f"), (-0.0, "z.1f"), (-0.001, "z.1f"), (-0.5, "z.0f"),
+    (math.inf, "08"), (-math.inf, "f"), (math.inf, "F"), (math.nan, "+"), (math.nan, "E"), (math.inf, "%"),
+    ("ab", "^6"), ("ab", "*^7"), ("ab", "05"), ("abc", ".2"), ("héllo", "~>8.3"), ("x", "s"),
+]
+
+# Specs that raise, compared against CPython's error messages
+FORMAT_SPEC_ERRORS = [
+    ("a", "+"), ("a", "#"), ("a", ","), ("a", "="), ("a", "x"), (1.5, "d"), (1.5, "x"), (1, ".2"), (1, ",x"),
+    (1, ",n"), (65, "+c"), (1, "s"), (None, ">3"), (1, ",_"), (1.5, "5.q"),
+]
+
+
+def _go_value(value: object) -> str:
+    """Return a Go expression for a format test value."""
+    if value is None:
+        return "nil"
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, str):
+        return json.dumps(value, ensure_ascii=False)
+    if isinstance(value, float):
+        return _go_float_literal(value)
+    return str(value)
+
+
+class TestGoFormatSpec:
+    """Test the format spec mini-language against CPython."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_conversion_flags(self):
+        """Test !r, !s and !a apply before the field's spec."""
+        python_code = """
+def show(name: str, n: int) -> str:
+    return f"{name!r:>10}{n!s}{name!a}"
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'mgen.Format(mgen.Repr(name), ">10") + mgen.Format(mgen.ToStr(n), "")' in go_code
+        assert 'mgen.Format(mgen.Ascii(name), "")' in go_code
+
+    def test_specs_match_python(self, go_run):
+        """Test every spec formats byte-for-byte like CPython's format()."""
+        calls = [f"mgen.Format({_go_value(v)}, {json.dumps(spec)})" for v, spec in FORMAT_SPEC_CASES]
+        body = "\n".join(f'    mgen.Print("[" + {call} + "]")' for call in calls)
+        output = go_run(body, imports=("math",))
+        assert output.splitlines() == [f"[{format(v, spec)}]" for v, spec in FORMAT_SPEC_CASES]
+
+    def test_spec_errors_match_python(self, go_run):
+        """Test invalid specs raise the same exception and message as CPython."""
+        body, expected = [], []
+        for value, spec in FORMAT_SPEC_ERRORS:
+            body.append(
+                f"""    func() {{
+        defer func() {{ mgen.Print(recover().(error).Error()) }}()
+        mgen.Format({_go_value(value)}, {json.dumps(spec)})
+    }}()"""
+            )
+            try:
+                format(value, spec)
+            except (TypeError, ValueError) as e:
+                expected.append(f"{type(e).__name__}: {e}")
+        output = go_run("\n".join(body))
+        assert output.splitlines() == expected
+
+    def test_f_strings_end_to_end(self, go_run_python):
+        """Test f-strings with specs, conversions and = match CPython."""
+        python_code = """
+def main() -> None:
+    x: float = 3.14159
+    n: int = 1234567
+    name: str = "mgen"
+    width: int = 12
+    print(f"{x:>10.3f}|{n:,}|{n:_x}|{n:#o}|{-n:+012,d}|{x:e}|{x:.3g}|{0.25:.1%}")
+    print(f"{name!r:^10}|{name=}|{x:*^{width}.2f}|{format(n, ',')}")
+"""
+        expected = [
+            f"{3.14159:>10.3f}|{1234567:,}|{1234567:_x}|{1234567:#o}|{-1234567:+012,d}|"
+            f"{3.14159:e}|{3.14159:.3g}|{0.25:.1%}",
+            f"{'mgen'!r:^10}|name='mgen'|{3.14159:*^12.2f}|{1234567:,}",
+        ]
+        assert go_run_python(python_code).splitlines() == expected
+
+
+# (format, Go argument expression, Python argument) for printf-style formatting
+PERCENT_CASES = [
+    ("%s", '"x"', "x"),
+    ("%s", "[]interface{}{1}", (1,)),
+    ("%d %s", '[]interface{}{3, "widgets"}', (3, "widgets")),
+    ("%5.2f|%-4d|%x|%r|%c|%%|%05d|%+d", '[]interface{}{3.14159, 7, 255, "a", 65, -42, 5}',
+     (3.14159, 7, 255, "a", 65, -42, 5)),
+    ("%.3s|%e|%g|%o|%X|%i|%u", '[]interface{}{"abcdef", 12345.678, 0.0001, 8, 255, 3, 4}',
+     ("abcdef", 12345.678, 0.0001, 8, 255, 3, 4)),
+    ("%#x %#o|% d|%*d|%.3d", "[]interface{}{255, 8, 5, 5, 3, 7}", (255, 8, 5, 5, 3, 7)),
+    ("%d|%s|%d", "[]interface{}{3.7, true, true}", (3.7, True, True)),
+    ("%(a)s and %(a)r", 'map[string]string{"a": "x"}', {"a": "x"}),
+    ("%(n)05.1f", 'mgen.NewPyDict(mgen.PyDictEntry{Key: "n", Value: 2.25})', {"n": 2.25}),
+    ("no args", "[]interface{}{}", ()),
+    ("no args", 'map[string]int{"a": 1}', {"a": 1}),
+]
+
+# (format, Go argument expression, Python argument) that raise
+PERCENT_ERRORS = [
+    ("%s %s", 'map[string]int{"a": 1}', {"a": 1}),
+    ("%(a)s %s", 'map[string]int{"a": 1}', {"a": 1}),
+    ("%(a)s", "[]interface{}{1}", (1,)),
+    ("%s", "[]interface{}{1, 2}", (1, 2)),
+    ("%s %s", "[]interface{}{1}", (1,)),
+    ("%d", '"x"', "x"),
+    ("%x", "2.5", 2.5),
+    ("%(b)s", 'map[string]int{"a": 1}', {"a": 1}),
+    ("%", "1", 1),
+    ("%y", "1", 1),
+]
+
+
+def _python_error(fmt: str, args: object) -> str:
+    """Return the "Type: message" Python raises for fmt % args."""
+    try:
+        fmt % args
+    except Exception as e:  # noqa: BLE001
+        return f"{type(e).__name__}: {e}"
+    raise AssertionError(f"{fmt!r} % {args!r} did not raise")
+
+
+class TestGoPercentFormat:
+    """Test str % args picks single values, tuples and mappings like Python."""
+
+    def test_percent_codegen(self):
+        """Test % on a string lowers to mgen.PercentFormat and yields a string."""
+        python_code = """
+def label(n: int, name: str) -> str:
+    s = "%d %s" % (n, name)
+    return s.upper() + "%s" % [n] + "%s" % n
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        assert 'var s string = mgen.PercentFormat("%d %s", mgen.PyTuple{n, name})' in go_code
+        assert 'mgen.PercentFormat("%s", []interface{}{[]int{n}})' in go_code
+        assert 'mgen.PercentFormat("%s", n)' in go_code
+
+    def test_percent_runtime_matches_python(self, go_run):
+        """Test conversions, flags, width, precision and %(key) lookups."""
+        body = "\n".join(f"    mgen.Print(mgen.PercentFormat({fmt!r}, {go_args}))" for fmt, go_args, _ in PERCENT_CASES)
+        output = go_run(body.replace("'", '"'))
+        assert output.splitlines() == [fmt % args for fmt, _, args in PERCENT_CASES]
+
+    def test_percent_errors_match_python(self, go_run):
+        """Test a mapping with positional specs, argument count and type errors."""
+        body = "\n".join(
+            f"    check(func() {{ mgen.PercentFormat({fmt!r}, {go_args}) }})".replace("'", '"')
+            for fmt, go_args, _ in PERCENT_ERRORS
+        )
+        output = go_run(body)
+        assert output.splitlines() == [_python_error(fmt, args) for fmt, _, args in PERCENT_ERRORS]
+
+    def test_single_tuple_gotcha(self, go_run_python):
+        """Test "%s" % (1,) formats 1 while "%s" % ((1,),) formats the tuple."""
+        python_code = """
+def main() -> None:
+    print("%s" % (1,))
+    print("%s" % ((1,),))
+    print("%s" % "%d" % 5)
+    try:
+        print("%s" % (1, 2))
+    except TypeError as e:
+        print(e)
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "1",
+            "(1,)",
+            "5",
+            "not all arguments converted during string formatting",
+        ]
+
+
+# (format, Go arguments after the format, Python args, Python kwargs) for str.format that raise
+STR_FORMAT_ERRORS = [
+    ("{} {}", "nil, 1", (1,), {}),
+    ("{0} {}", "nil, 1, 2", (1, 2), {}),
+    ("{} {1}", "nil, 1, 2", (1, 2), {}),
+    ("{k}", 'map[string]interface{}{"j": 1}', (), {"j": 1}),
+    ("a } b", "nil", (), {}),
+    ("a { b", "nil", (), {}),
+    ("{0[3]}", "nil, []int{1}", ([1],), {}),
+    ("{!x}", "nil, 1", (1,), {}),
+]
+
+
+def _python_format_error(fmt: str, args: tuple, kwargs: dict) -> str:
+    """Return the "Type: message" Python raises for fmt.format(*args, **kwargs)."""
+    try:
+        fmt.format(*args, **kwargs)
+    except Exception as e:  # noqa: BLE001
+        return f"{type(e).__name__}: {e}"
+    raise AssertionError(f"{fmt!r}.format() did not raise")
+
+
+class TestGoStrFormat:
+    """Test str.format() and str.format_map() lower to mgen.StrFormat."""
+
+    def test_str_format_codegen(self):
+        """Test positional, keyword, **mapping, *args and format_map calls."""
+        python_code = """
+def label(n: int, name: str, d: dict[str, int], xs: list[int]) -> str:
+    s = "{} {who}".format(n, who=name)
+    t = "{a}".format(**d) + "{}{}".format(*xs) + s.format_map(d)
+    return t.upper()
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        assert 's := mgen.StrFormat("{} {who}", map[string]interface{}{"who": name}, n)' in go_code
+        assert 'mgen.StrFormat("{a}", d)' in go_code
+        assert 'mgen.StrFormat("{}{}", nil, mgen.ToList(xs)...)' in go_code
+        assert "mgen.StrFormat(s, d)" in go_code
+        assert "mgen.StrOps.Upper(t)" in go_code
+
+    def test_str_format_errors_match_python(self, go_run):
+        """Test missing arguments, mixed numbering and unbalanced braces."""
+        body = "\n".join(
+            f"    check(func() {{ mgen.StrFormat({json.dumps(fmt)}, {go_args}) }})"
+            for fmt, go_args, _, _ in STR_FORMAT_ERRORS
+        )
+        output = go_run(body)
+        assert output.splitlines() == [
+            _python_format_error(fmt, args, kwargs) for fmt, _, args, kwargs in STR_FORMAT_ERRORS
+        ]
+
+    def test_str_format_end_to_end(self, go_run_python):
+        """Test fields, specs, nested specs, conversions, indexing and attributes print like Python."""
+        python_code = """
+class Point:
+    def __init__(self, x: int) -> None:
+        self.x = x
+
+
+def main() -> None:
+    name = "ann"
+    n = 3
+    f = 2.5
+    print("{} has {} items".format(name, n))
+    print("{1} {0} {1}".format("a", "b"))
+    print("{who}: {v:.2f} {v!r:>8}|".format(who=name, v=f))
+    print("{:>{w}}|{:^7}|{{x}}".format(n, "mid", w=6))
+    d = {"a": 1, "b": 2}
+    print("{a}+{b}".format(**d), "{a}-{b}".format_map(d))
+    xs = [10, 20, 30]
+    print("{0[1]} {0[2]:04d} {m[a]}".format(xs, m=d), "{} {} {}".format(*xs))
+    print("{!r} {!s} {!a}".format(name, name, "é"))
+    print("{:,} {:x} {:e} {:%}".format(1234567, 255, 12345.678, 0.25))
+    p = Point(4)
+    print("x={0.x} {p.x:>3}".format(p, p=p))
+    try:
+        print("{k}".format(j=1))
+    except KeyError as e:
+        print("KeyError", e)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
@@ -0,0 +1,120 @@
+"""Tests for the Go backend's post-emit formatting and import pruning."""
+
+import shutil
+
+import pytest
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.backends.go.emitter import GoEmitter
+from mgen.backends.go.formatter import format_go_code, prune_imports
+from mgen.backends.preferences import GoPreferences
+
+UNUSED_RUNTIME = """
+def main() -> None:
+    x = 1
+    y = x + 2
+"""
+
+PRINTS = """
+def main() -> None:
+    for i in range(2):
+        if i > 0:
+            print(i)
+"""
+
+POWERS = """
+def main() -> None:
+    f = 1.5
+    f **= 2
+    n = 3
+    print(f, 2.0 ** n)
+"""
+
+
+class TestGoImportPruning:
+    """Test imports the code never refers to are dropped."""
+
+    def test_unused_imports(self):
+        """Test a package is used only through a qualified identifier outside literals and comments."""
+        go_code = prune_imports(
+            """package main
+
+import (
+\t"fmt"
+\tr "math/rand"
+\t"strings"
+\t"github.com/go-chi/chi/v5"
+\t_ "embed"
+)
+
+func main() {
+\tx := "strings.Join" // chi.NewRouter
+\t_ = x
+\tfmt.Println(r.Intn(1))
+}
+"""
+        )
+
+        assert '\t"fmt"\n\tr "math/rand"\n\t_ "embed"\n)' in go_code
+        assert "strings" not in go_code.split("func")[0]
+        assert "chi/v5" not in go_code
+
+    def test_emptied_import(self):
+        """Test the runtime import goes when the program never uses it, and an emptied block with it."""
+        single = prune_imports('package main\n\nimport "mgenproject/mgen"\n\nfunc main() {\n}\n')
+        block = prune_imports('package main\n\nimport (\n\t"mgenproject/mgen"\n)\n\nfunc main() {\n}\n')
+
+        assert single == "package main\n\n\nfunc main() {\n}\n"
+        assert block == "package main\n\n\nfunc main() {\n}\n"
+
+    def test_standard_library_imports(self):
+        """Test the converter imports the standard library packages its code calls, and only those."""
+        assert 'import "math"' in MGenPythonToGoConverter().convert_code(POWERS)
+        assert 'import "math"' not in MGenPythonToGoConverter().convert_code(PRINTS)
+
+    def test_used_imports_unchanged(self):
+        """Test code using every import is returned as it is."""
+        go_code = MGenPythonToGoConverter().convert_code(PRINTS)
+
+        assert prune_imports(go_code) == go_code
+
+
+@pytest.mark.skipif(shutil.which("gofmt") is None, reason="gofmt not available")
+class TestGoFormatting:
+    """Test the emitter formats its output unless the format_code preference is off."""
+
+    def test_emitted_code_is_formatted(self):
+        """Test emitted code is what gofmt prints, indented with tabs by nesting."""
+        go_code = GoEmitter().emit_module(PRINTS, None)
+
+        assert "\tfor i := 0; i < 2; i++ {\n\t\tif i > 0 {\n\t\t\tmgen.Print(i)\n" in go_code
+        assert format_go_code(go_code) == go_code
+
+    def test_unparsable_code_unchanged(self):
+        """Test code the formatter cannot parse is left for go build to report."""
+        go_code = 'package main\n\nfunc main() {\n    x := (\n}\n'
+
+        assert format_go_code(go_code) == go_code
+
+    def test_formatting_disabled(self):
+        """Test format_code off emits the converter's code as it is."""
+        preferences = GoPreferences()
+        preferences.set("format_code", False)
+
+        assert GoEmitter(preferences).emit_module(PRINTS, None) == MGenPythonToGoConverter().convert_code(PRINTS)
+
+
+class TestGoFormattedProgram:
+    """Test emitted programs build and print what CPython prints."""
+
+    def test_program_without_runtime_use(self, go_run_emitted):
+        """Test a program that never calls the runtime builds once its import is pruned."""
+        assert go_run_emitted(UNUSED_RUNTIME) == ""
+
+    def test_formatted_program(self, go_run_emitted):
+        """Test a formatted program runs as the converter's output does."""
+        assert go_run_emitted(PRINTS) == "1\n"
+
+    def test_standard_library_calls(self, go_run_emitted):
+        """Test a program calling math.Pow builds whether or not goimports would have added the import."""
+        assert go_run_emitted(POWERS) == "2.25 8.0\n"
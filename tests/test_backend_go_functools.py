@@ -0,0 +1,214 @@
+"""Tests for Go backend reduce/fold and lru_cache support."""
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+
+class TestGoFoldRuntime:
+    """Test typed and dynamic folds."""
+
+    def test_typed_running_product(self, go_run):
+        """Test Fold with an int64 accumulator over an int slice."""
+        output = go_run(
+            """
+    product := mgen.Fold([]int{1, 2, 3, 4, 5}, int64(1), func(acc int64, x int) int64 { return acc * int64(x) })
+    mgen.Print(product, mgen.Fold([]int{}, 7, func(acc int, x int) int { return acc + x }))
+"""
+        )
+        assert output.strip() == "120 7"
+
+    def test_string_concatenation_folds(self, go_run):
+        """Test Fold and FoldRight combine in opposite orders."""
+        output = go_run(
+            """
+    words := []string{"a", "b", "c"}
+    left := mgen.Fold(words, "<", func(acc string, w string) string { return "(" + acc + w + ")" })
+    right := mgen.FoldRight(words, ">", func(w string, acc string) string { return "(" + w + acc + ")" })
+    mgen.Print(left, right)
+"""
+        )
+        assert output.strip() == "(((<a)b)c) (a(b(c>)))"
+
+    def test_reduce(self, go_run):
+        """Test Reduce follows functools.reduce, including the empty-iterable error."""
+        output = go_run(
+            """
+    add := func(acc, x interface{}) interface{} { return mgen.BinOp("+", acc, x) }
+    mgen.Print(mgen.Reduce(add, []int{1, 2, 3}), mgen.Reduce(add, []int{}, 10), mgen.Reduce(add, "abc"))
+    defer func() { mgen.Print(recover().(error).Error()) }()
+    mgen.Reduce(add, []int{})
+"""
+        )
+        assert output.splitlines() == ["6 10 abc", "TypeError: reduce() of empty iterable with no initial value"]
+
+    def test_reduce_slice(self, go_run):
+        """Test ReduceSlice starts from the first element and rejects an empty slice."""
+        output = go_run(
+            """
+    mgen.Print(mgen.ReduceSlice(func(a int, b int) int { return a*10 + b }, []int{1, 2, 3}))
+    defer func() { mgen.Print(recover().(error).Error()) }()
+    mgen.ReduceSlice(func(a string, b string) string { return a + b }, []string{})
+"""
+        )
+        assert output.splitlines() == ["123", "TypeError: reduce() of empty iterable with no initial value"]
+
+
+class TestGoLRUCacheRuntime:
+    """Test the LRUCache behind @functools.lru_cache."""
+
+    def test_eviction_and_info(self, go_run):
+        """Test hits move entries to the front and the least recently used one is evicted."""
+        output = go_run(
+            """
+    cache := mgen.NewLRUCache(2, false)
+    square := func(x int) int {
+        return mgen.Memoize(cache, cache.Key(x), func() int {
+            mgen.Print("computing", x)
+            return x * x
+        })
+    }
+    mgen.Print(square(2), square(3), square(2), square(4), square(3))
+    mgen.Print(cache.Info(), cache.Key(1) == cache.Key(1.0))
+    cache.Clear()
+    mgen.Print(cache.Info(), mgen.NewLRUCache(-1, true).Info())
+"""
+        )
+        assert output.splitlines() == [
+            "computing 2",
+            "computing 3",
+            "computing 4",
+            "computing 3",
+            "4 9 4 16 9",
+            "CacheInfo(hits=1, misses=4, maxsize=2, currsize=2) True",
+            "CacheInfo(hits=0, misses=0, maxsize=2, currsize=0) CacheInfo(hits=0, misses=0, maxsize=None, currsize=0)",
+        ]
+
+
+class TestGoFunctoolsConversion:
+    """Test functools.reduce and lru_cache translate to the runtime helpers."""
+
+    def test_reduce(self):
+        """Test typed slices fold with typed funcs and other iterables use the dynamic Reduce."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+import functools
+from functools import reduce
+
+
+def add(a: int, b: int) -> int:
+    return a + b
+
+
+def main() -> None:
+    nums = [1, 2, 3]
+    words = ["a", "bb"]
+    mixed = [1, "a"]
+    print(reduce(lambda a, b: a * b, nums), functools.reduce(add, nums, 10))
+    print(reduce(lambda n, w: n + len(w), words, 0), reduce(add, mixed))
+"""
+        )
+
+        assert "mgen.ReduceSlice(func(a int, b int) int { return (a * b) }, nums)" in go_code
+        assert "mgen.Fold(nums, 10, add)" in go_code
+        assert "mgen.Fold(words, 0, func(n int, w string) int { return (n + mgen.LenString(w)) })" in go_code
+        assert (
+            "mgen.Reduce(func(acc, item interface{}) interface{} { return add(acc.(int), item.(int)) }, mixed)"
+            in go_code
+        )
+
+    def test_lru_cache(self):
+        """Test a cached function's body runs through Memoize over its package-level cache."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+from functools import lru_cache
+
+
+@lru_cache(maxsize=None)
+def fib(n: int) -> int:
+    if n < 2:
+        return n
+    return fib(n - 1) + fib(n - 2)
+
+
+def main() -> None:
+    print(fib(30), fib.cache_info())
+    fib.cache_clear()
+"""
+        )
+
+        assert "var fibCache = mgen.NewLRUCache(-1, false)" in go_code
+        assert "return mgen.Memoize(fibCache, fibCache.Key(n), func() int {" in go_code
+        assert "mgen.Print(fib(30), fibCache.Info())" in go_code
+        assert "fibCache.Clear()" in go_code
+
+    @pytest.mark.parametrize(
+        "decorator, cache",
+        [
+            ("@lru_cache", "mgen.NewLRUCache(128, false)"),
+            ("@lru_cache()", "mgen.NewLRUCache(128, false)"),
+            ("@lru_cache(32, typed=True)", "mgen.NewLRUCache(32, true)"),
+            ("@lru_cache(maxsize=-5)", "mgen.NewLRUCache(0, false)"),
+            ("@functools.cache", "mgen.NewLRUCache(-1, false)"),
+        ],
+    )
+    def test_decorator_forms(self, decorator, cache):
+        """Test each spelling of the decorator sets the cache's maxsize and typed flag."""
+        go_code = MGenPythonToGoConverter().convert_code(f"{decorator}\ndef f(x: int) -> int:\n    return x + 1\n")
+
+        assert f"var fCache = {cache}" in go_code
+
+    def test_cached_function_without_result(self):
+        """Test caching a function that returns nothing is rejected."""
+        with pytest.raises(TypeMappingError, match="without a return value"):
+            MGenPythonToGoConverter().convert_code("@lru_cache\ndef show(x: int) -> None:\n    print(x)\n")
+
+
+class TestGoFunctoolsProgram:
+    """Test reduce and lru_cache programs print what CPython prints."""
+
+    def test_program(self, go_run_python):
+        """Test memoized recursion, eviction statistics and typed folds."""
+        python_code = """
+import functools
+from functools import lru_cache, reduce
+
+
+@lru_cache(maxsize=None)
+def fib(n: int) -> int:
+    if n < 2:
+        return n
+    return fib(n - 1) + fib(n - 2)
+
+
+@functools.lru_cache(maxsize=2)
+def square(x: float) -> float:
+    print("computing", x)
+    return x * x
+
+
+def add(a: int, b: int) -> int:
+    return a + b
+
+
+def main() -> None:
+    print(fib(80), fib.cache_info())
+    print(square(2.0), square(3.5), square(2.0), square(4.0), square(3.5))
+    info = square.cache_info()
+    print(info, info.hits, info.currsize)
+    nums = [1, 2, 3, 4, 5]
+    words = ["a", "bb", "ccc"]
+    print(reduce(lambda a, b: a * b, nums), functools.reduce(add, nums, 10))
+    print(reduce(lambda n, w: n + len(w), words, 0), reduce(lambda a, b: a + b, words))
+    print(reduce(lambda a, b: max(a, b), {4, 9, 2}), reduce(add, [7]))
+    try:
+        reduce(add, [])
+    except TypeError as e:
+        print("TypeError:", e)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
@@ -0,0 +1,338 @@
+"""Tests for Go backend generator functions, generator expressions and next()."""
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.backends.preferences import GoPreferences
+from mgen.errors import TypeMappingError
+
+
+class TestGoGeneratorConversion:
+    """Test generator functions and expressions lower to mgen.NewGenerator."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_generator_function(self):
+        """Test a function that yields returns an mgen.Iterator fed by its body."""
+        python_code = """
+from typing import Iterator
+
+
+def countdown(n: int) -> Iterator[int]:
+    while n > 0:
+        yield n
+        n -= 1
+
+
+def evens(limit: int):
+    for i in range(limit):
+        yield i * 2
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func countdown(n int) mgen.Iterator[int] {" in go_code
+        assert "    return mgen.NewGenerator(func(yield func(int)) {\n    for (n > 0) {" in go_code
+        assert "    yield(n)\n    n -= 1" in go_code
+        # Without an annotation the item type comes from the yielded values
+        assert "func evens(limit int) mgen.Iterator[int] {" in go_code
+        assert "    yield((i * 2))" in go_code
+
+    def test_yield_from_and_iterator_loops(self):
+        """Test yield from loops over its source and for loops call Next until it reports false."""
+        python_code = """
+from typing import Iterator
+
+
+def letters() -> Iterator[str]:
+    yield from ["a", "b"]
+
+
+def shout() -> Iterator[str]:
+    for s in letters():
+        yield s.upper()
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'for _, item := range []string{"a", "b"} {\n    yield(item)\n    }' in go_code
+        assert "iterator1 := letters()\n    for s, ok1 := iterator1.Next(); ok1; s, ok1 = iterator1.Next() {" in go_code
+
+    def test_early_exits_close_generators(self):
+        """Test a loop that can leave a fresh generator unfinished closes it, and one over a name does not."""
+        python_code = """
+from typing import Iterator
+
+
+def naturals() -> Iterator[int]:
+    n = 0
+    while True:
+        yield n
+        n += 1
+
+
+def first_square_over(limit: int) -> int:
+    for n in naturals():
+        if n * n > limit:
+            return n
+    return -1
+
+
+def f() -> None:
+    for n in naturals():
+        if n > 3:
+            break
+    it = naturals()
+    for n in it:
+        break
+    print(next(it))
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "iterator1 := naturals()\n    defer mgen.CloseIterator(iterator1)\n    for n, ok1" in go_code
+        assert "    break\n    }\n    }\n    mgen.CloseIterator(iterator2)\n" in go_code
+        assert "CloseIterator(iterator3)" not in go_code
+
+    def test_eager_generators_preference(self):
+        """Test generators="eager" builds generators with mgen.NewEagerGenerator and other values are rejected."""
+        preferences = GoPreferences()
+        preferences.set("generators", "eager")
+        go_code = MGenPythonToGoConverter(preferences).convert_code(
+            "def evens(limit: int):\n    for i in range(limit):\n        yield i * 2\n"
+        )
+
+        assert "    return mgen.NewEagerGenerator(func(yield func(int)) {" in go_code
+        preferences.set("generators", "threads")
+        with pytest.raises(ValueError, match="generators must be 'goroutine' or 'eager'"):
+            MGenPythonToGoConverter(preferences)
+
+    def test_generator_expressions(self):
+        """Test consumed generator expressions build slices and the others stay lazy."""
+        python_code = """
+def f(xs: list[int]) -> int:
+    squares = (x * x for x in xs if x > 1)
+    first = next(squares, 0)
+    return first + sum(x * 2 for x in xs)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "var squares mgen.Iterator[int] = mgen.NewGenerator(func(yield func(int)) {" in go_code
+        assert "    if !((x > 1)) { continue }\n    yield((x * x))\n    }\n})" in go_code
+        assert "first := mgen.NextOr(squares, 0)" in go_code
+        assert "mgen.NewGenerator" not in go_code.split("first :=")[1]
+
+    def test_next_and_iter(self):
+        """Test next() and iter() lower to the runtime's iterator helpers."""
+        python_code = """
+def f(xs: list[str]) -> str:
+    it = iter(xs)
+    return next(it)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "it := mgen.Iter(xs)" in go_code
+        assert "return mgen.Next(it)" in go_code
+
+    @pytest.mark.parametrize(
+        "python_code,message",
+        [
+            ("def f(n: int):\n    yield n\n    return n\n", "return with a value inside a generator"),
+            ("def f(n: int):\n    yield\n", "yield without a value"),
+            ("def f(xs: list[int]) -> int:\n    return next(xs)\n", "next\\(\\) needs an iterator"),
+        ],
+    )
+    def test_unsupported_generators(self, python_code, message):
+        """Test generator forms without a Go lowering are rejected."""
+        with pytest.raises(TypeMappingError, match=message):
+            self.converter.convert_code(python_code)
+
+
+GENERATOR_PROGRAM = """
+from typing import Iterator
+
+
+def countdown(n: int) -> Iterator[int]:
+    while n > 0:
+        yield n
+        n -= 1
+
+
+def words() -> Iterator[str]:
+    yield "a"
+    yield from ["b", "c"]
+    for k in countdown(2):
+        yield str(k)
+
+
+def naturals() -> Iterator[int]:
+    n = 0
+    while True:
+        yield n
+        n += 1
+
+
+def first_over(limit: int) -> Iterator[int]:
+    for n in naturals():
+        if n > limit:
+            return
+        yield n
+
+
+def fragile(n: int) -> Iterator[int]:
+    yield 1
+    if n == 0:
+        raise ValueError("no more")
+    yield 2
+
+
+def main() -> None:
+    for n in countdown(3):
+        print(n)
+    print(repr(list(words())), repr(sorted(first_over(3), reverse=True)))
+    it = naturals()
+    print(next(it), next(it), next(it))
+    squares = (x * x for x in range(5) if x != 2)
+    print(next(squares), repr(list(squares)))
+    xs = [3, 1, 4]
+    print(sum(x * 2 for x in xs), any(x > 3 for x in xs), max(x for x in xs), ",".join(str(x) for x in xs))
+    ys = iter(xs)
+    print(next(ys), next(ys), next(ys), next(ys, -1))
+    try:
+        next(ys)
+    except StopIteration:
+        print("stop")
+    gen = fragile(0)
+    print(next(gen))
+    try:
+        next(gen)
+    except ValueError as e:
+        print("error", e)
+    print(next(gen, 99))
+    for i, w in enumerate(words()):
+        print(i, w)
+"""
+
+
+class TestGoGeneratorRuntime:
+    """Test generators run lazily and agree with CPython."""
+
+    def test_generators_match_python(self, go_run_python):
+        """Test generator functions, expressions and next() print as in CPython."""
+        assert go_run_python(GENERATOR_PROGRAM) == python_output(GENERATOR_PROGRAM + "\nmain()\n")
+
+    def test_generator_runs_lazily(self, go_run):
+        """Test the body runs only as far as the values asked for, and stays exhausted."""
+        output = go_run(
+            """
+    gen := mgen.NewGenerator(func(yield func(int)) {
+        for i := 1; i <= 3; i++ {
+            mgen.Print("produce", i)
+            yield(i * 10)
+        }
+    })
+    mgen.Print("first", mgen.Next[int](gen))
+    mgen.Print("rest", mgen.Repr(mgen.Collect[int](gen)))
+    _, ok := gen.Next()
+    mgen.Print(ok, mgen.NextOr[int](gen, -1))
+    check(func() { mgen.Next[int](gen) })
+"""
+        )
+        assert output.splitlines() == [
+            "produce 1",
+            "first 10",
+            "produce 2",
+            "produce 3",
+            "rest [20, 30]",
+            "False -1",
+            "StopIteration",
+        ]
+
+    def test_close_ends_the_goroutine(self, go_run):
+        """Test closing a paused generator runs its deferred cleanup and ends its goroutine."""
+        output = go_run(
+            """
+    before := runtime.NumGoroutine()
+    gen := mgen.NewGenerator(func(yield func(int)) {
+        defer mgen.Print("cleanup")
+        for i := 0; ; i++ {
+            yield(i)
+        }
+    })
+    mgen.Print(mgen.Next[int](gen), runtime.NumGoroutine()-before)
+    mgen.CloseIterator(gen)
+    _, ok := gen.Next()
+    mgen.Print(runtime.NumGoroutine()-before, ok)
+""",
+            imports=("runtime",),
+        )
+        assert output.splitlines() == ["0 1", "cleanup", "0 False"]
+
+    def test_loops_close_generators_they_leave(self, go_run_python):
+        """Test break and return close the generator, so its finally runs when CPython's does."""
+        python_code = """
+from typing import Iterator
+
+
+def naturals() -> Iterator[int]:
+    n = 0
+    try:
+        while True:
+            yield n
+            n += 1
+    finally:
+        print("closed at", n)
+
+
+def first_square_over(limit: int) -> int:
+    for n in naturals():
+        if n * n > limit:
+            return n
+    return -1
+
+
+def main() -> None:
+    for n in naturals():
+        if n == 2:
+            break
+    print("after break")
+    print(first_square_over(10))
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
+
+    def test_eager_generators_match_python(self, go_run_python):
+        """Test eager generators yield the same values and raise at the same point as lazy ones."""
+        python_code = """
+from typing import Iterator
+
+
+def fragile(n: int) -> Iterator[int]:
+    yield 1
+    if n == 0:
+        raise ValueError("no more")
+    yield 2
+
+
+def main() -> None:
+    print(list(fragile(1)), sum(x * x for x in range(4)))
+    gen = fragile(0)
+    print(next(gen))
+    try:
+        next(gen)
+    except ValueError as e:
+        print("error", e)
+    print(next(gen, 99))
+    for n in fragile(1):
+        if n == 1:
+            break
+    print("done")
+
+
+main()
+"""
+        preferences = GoPreferences()
+        preferences.set("generators", "eager")
+        assert go_run_python(python_code, preferences=preferences) == python_output(python_code)
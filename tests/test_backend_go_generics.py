@@ -0,0 +1,156 @@
+"""Tests for the Go backend's generic functions from Python TypeVars."""
+
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.backends.preferences import GoPreferences
+
+
+GENERIC_FUNCTIONS = """
+from typing import Callable, TypeVar
+
+T = TypeVar("T")
+U = TypeVar("U")
+K = TypeVar("K")
+V = TypeVar("V")
+N = TypeVar("N", int, float)
+B = TypeVar("B", bound=float)
+
+
+def first(xs: list[T]) -> T:
+    return xs[0]
+
+
+def smallest(xs: list[T]) -> T:
+    best = xs[0]
+    for x in xs:
+        if x < best:
+            best = x
+    return best
+
+
+def count(xs: list[T], target: T) -> int:
+    n = 0
+    for x in xs:
+        if x == target:
+            n += 1
+    return n
+
+
+def apply(f: Callable[[T], U], x: T) -> U:
+    return f(x)
+
+
+def invert(d: dict[K, V]) -> dict[V, K]:
+    result: dict[V, K] = {}
+    for k, v in d.items():
+        result[v] = k
+    return result
+
+
+def total(xs: list[N], start: N) -> N:
+    acc = start
+    for x in xs:
+        acc += x
+    return acc
+
+
+def clamp(x: B, hi: B) -> B:
+    if x < hi:
+        return x
+    return hi
+
+
+def unique(xs: list[T]) -> set[T]:
+    return set(xs)
+"""
+
+
+class TestGoGenericConversion:
+    """Test functions using TypeVars become Go generic functions."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_type_parameters(self):
+        """Test each TypeVar the signature uses becomes a type parameter, in order of first use."""
+        go_code = self.converter.convert_code(GENERIC_FUNCTIONS)
+
+        assert "func first[T any](xs []T) T {" in go_code
+        assert "func apply[T any, U any](f func(T) U, x T) U {" in go_code
+        assert "func total[N int | float64](xs []N, start N) N {" in go_code
+        assert "func clamp[B float64](x B, hi B) B {" in go_code
+        assert "acc += x" in go_code
+
+    def test_constraints_from_usage(self):
+        """Test unconstrained TypeVars whose values are ordered, compared or hashed get the constraint Go needs."""
+        go_code = self.converter.convert_code(GENERIC_FUNCTIONS)
+
+        assert "func smallest[T mgen.Ordered](xs []T) T {" in go_code
+        assert "func count[T comparable](xs []T, target T) int {" in go_code
+        assert "func invert[K comparable, V comparable](d *mgen.Dict[K, V]) *mgen.Dict[V, K] {" in go_code
+        assert "func unique[T comparable](xs []T) *mgen.Set[T] {" in go_code
+
+    def test_call_sites(self):
+        """Test calls take the result type their arguments instantiate, and lambdas the parameter types."""
+        go_code = self.converter.convert_code(
+            GENERIC_FUNCTIONS
+            + """
+
+def main() -> None:
+    nums = [4, 2, 7]
+    y = first(nums)
+    names = invert({"a": 1})
+    print(y + 1, names[1] + "!", apply(lambda s: len(s), "abc"))
+"""
+        )
+
+        assert "y := first(nums)" in go_code
+        assert '(names.Get(1) + "!")' in go_code
+        assert 'apply(func(s string) int { return mgen.LenString(s) }, "abc")' in go_code
+
+    def test_functions_without_type_vars(self):
+        """Test functions whose signatures use no TypeVar stay non-generic."""
+        go_code = self.converter.convert_code(
+            GENERIC_FUNCTIONS
+            + """
+
+def size(xs: list[int]) -> int:
+    return len(xs)
+"""
+        )
+
+        assert "func size(xs []int) int {" in go_code
+
+    def test_generics_disabled(self):
+        """Test the use_generics preference off leaves TypeVars as interface{}."""
+        preferences = GoPreferences()
+        preferences.set("use_generics", False)
+        go_code = MGenPythonToGoConverter(preferences).convert_code(GENERIC_FUNCTIONS)
+
+        assert "func first(xs []interface{}) interface{} {" in go_code
+
+
+class TestGoGenericProgram:
+    """Test programs using generic functions print what CPython prints."""
+
+    def test_generic_functions(self, go_run_python):
+        """Test generic functions instantiated with several types."""
+        python_code = (
+            GENERIC_FUNCTIONS
+            + """
+
+def main() -> None:
+    nums = [4, 2, 7]
+    y = first(nums)
+    print(y + 1, first(["a"]), smallest(["b", "a"]), smallest([2.5, 1.5]), count(nums, 2), count(["a", "b", "a"], "a"))
+    print(apply(lambda x: x * 2, 3), apply(lambda s: len(s), "abc"), invert({"a": 1, "b": 2}))
+    z = invert({1: "x"})
+    print(z["x"] + 1, total([1, 2, 3], 0), total([0.5, 0.25], 1.0), clamp(3.5, 2.0), sorted(unique([3, 1, 3])))
+
+
+main()
+"""
+        )
+        assert go_run_python(python_code) == python_output(python_code)
@@ -0,0 +1,112 @@
+"""Tests for module-level variables and global declarations in the Go backend."""
+
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+GLOBALS_PROGRAM = """
+from typing import TypeVar
+
+T = TypeVar("T")
+
+__all__ = ["bump"]
+LIMIT = 10
+NAMES: list[str] = []
+SCALE = LIMIT * 2
+counter = 0
+registry: dict[str, int] = {}
+
+
+class Tracker:
+    def __init__(self) -> None:
+        self.seen = 0
+
+    def track(self) -> int:
+        global counter
+        counter += 1
+        self.seen = counter
+        return self.seen + LIMIT
+
+
+def bump(n: int) -> int:
+    global counter
+    counter += n
+    registry["bump"] = counter
+    return counter
+
+
+def over() -> bool:
+    return counter > LIMIT
+
+
+def shadow() -> int:
+    LIMIT = 3
+    return LIMIT
+
+
+def reset() -> None:
+    global counter, SCALE
+    counter = 0
+    SCALE = -1
+
+
+def first(xs: list[T]) -> T:
+    return xs[0]
+
+
+LIMIT += 5
+DOUBLE = LIMIT * 2
+NAMES.append("x")
+
+
+def main() -> None:
+    print(LIMIT, SCALE, DOUBLE, NAMES)
+    print(bump(3), bump(4), over())
+    t = Tracker()
+    print(t.track(), counter)
+    print(bump(20), over(), shadow(), LIMIT)
+    reset()
+    print(counter, SCALE, registry["bump"], first([counter, 2]))
+"""
+
+
+class TestGoGlobals:
+    """Test module-level variables become package-level vars functions and methods share."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_package_level_vars(self):
+        """Test variables are declared in order, and updates after the first run in func init()."""
+        go_code = self.converter.convert_code(GLOBALS_PROGRAM)
+
+        assert (
+            "var LIMIT int = 10\n"
+            "var NAMES []string = []string{}\n"
+            "var SCALE int = (LIMIT * 2)\n"
+            "var counter int = 0\n"
+            "var registry *mgen.Dict[string, int] = mgen.NewDict[string, int]()\n"
+            "var DOUBLE int\n"
+            "\n"
+            "func init() {\n"
+            "    LIMIT += 5\n"
+            "    DOUBLE = (LIMIT * 2)\n"
+            '    NAMES = append(NAMES, "x")\n'
+            "}"
+        ) in go_code
+        assert "var T" not in go_code and "__all__" not in go_code
+
+    def test_global_declarations(self):
+        """Test global names assign the package-level var while other assignments declare locals."""
+        go_code = self.converter.convert_code(GLOBALS_PROGRAM)
+
+        assert "    // global counter\n    counter += n" in go_code
+        assert "    // global counter\n    counter += 1\n    obj.Seen = counter" in go_code
+        assert "    counter = 0\n    SCALE = (-1)\n" in go_code
+        assert "    var LIMIT int = 3" in go_code
+        assert "_ = counter" not in go_code
+
+    def test_globals_end_to_end(self, go_run_python):
+        """Test functions and methods observe each other's updates like Python."""
+        assert go_run_python(GLOBALS_PROGRAM) == python_output(GLOBALS_PROGRAM + "\nmain()\n")
@@ -0,0 +1,137 @@
+"""Tests for the Go backend's hashlib support."""
+
+import hashlib
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+# (algorithm, data) pairs, including the empty string and multi-block inputs
+VECTORS = [
+    ("md5", b""),
+    ("md5", b"abc"),
+    ("sha1", b"abc"),
+    ("sha1", b"The quick brown fox jumps over the lazy dog"),
+    ("sha256", b""),
+    ("sha256", b"abc"),
+    ("sha256", b"a" * 1000),
+    ("sha512", b"abc"),
+    ("sha512", bytes(range(256))),
+]
+
+CONSTRUCTORS = {"md5": "NewMD5", "sha1": "NewSHA1", "sha256": "NewSHA256", "sha512": "NewSHA512"}
+
+
+def go_bytes(data: bytes) -> str:
+    """Return a Go expression for data as mgen.PyBytes."""
+    return "mgen.PyBytes{" + ", ".join(str(b) for b in data) + "}"
+
+
+class TestGoHashlib:
+    """Test hash objects match Python's hashlib digests."""
+
+    def test_known_vectors(self, go_run):
+        """Test hexdigest() of known inputs for every supported algorithm."""
+        body = "\n".join(
+            f"    mgen.Print(mgen.{CONSTRUCTORS[name]}({go_bytes(data)}).HexDigest())" for name, data in VECTORS
+        )
+        output = go_run(body)
+
+        assert "a9993e364706816aba3e25717850c26c9cd0d89d" in output
+        assert output.splitlines() == [hashlib.new(name, data).hexdigest() for name, data in VECTORS]
+
+    def test_update_and_digest(self, go_run):
+        """Test incremental updates equal hashing the concatenation, and digest() bytes."""
+        output = go_run(
+            """
+    h := mgen.NewHash("sha256")
+    h.Update(mgen.PyBytes("hello "))
+    mgen.Print(mgen.Repr(h.Digest()[:4]), h.Name(), h.DigestSize(), h.BlockSize())
+    h.Update([]byte("world"))
+    mgen.Print(h.HexDigest() == mgen.NewSHA256(mgen.PyBytes("hello world")).HexDigest())
+    mgen.Print(mgen.Repr(mgen.NewMD5(mgen.PyBytes("abc")).Digest()))
+"""
+        )
+        h = hashlib.sha256(b"hello ")
+        assert output.splitlines() == [
+            f"{h.digest()[:4]!r} sha256 32 64",
+            "True",
+            repr(hashlib.md5(b"abc").digest()),
+        ]
+
+    @pytest.mark.parametrize(
+        "call,python_call",
+        [
+            ('mgen.NewSHA1("text")', lambda: hashlib.sha1("text")),
+            ("mgen.NewMD5(42)", lambda: hashlib.md5(42)),
+            ('mgen.NewHash("sha3")', lambda: hashlib.new("sha3")),
+        ],
+    )
+    def test_errors(self, go_run, call, python_call):
+        """Test str data must be encoded first and unknown algorithms are rejected."""
+        output = go_run(
+            f"""
+    defer func() {{ mgen.Print(recover().(error).Error()) }}()
+    {call}
+"""
+        )
+        try:
+            python_call()
+        except (TypeError, ValueError) as e:
+            expected = f"{type(e).__name__}: {e}"
+        assert output == expected + "\n"
+
+
+class TestGoHashlibConversion:
+    """Test hashlib constructors, methods and attributes lower to mgen.Hash."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_hashlib_codegen(self):
+        """Test module-qualified and imported constructors, hexdigest() and digest_size."""
+        python_code = """
+import hashlib
+from hashlib import md5
+
+
+def fingerprint(data: bytes) -> str:
+    h = md5(data)
+    h.update(b"!")
+    return hashlib.sha256(data).hexdigest() + h.hexdigest() + str(h.digest_size)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "h := mgen.NewMD5(data)" in go_code
+        assert 'h.Update(mgen.PyBytes("!"))' in go_code
+        assert "mgen.NewSHA256(data).HexDigest() + h.HexDigest()) + mgen.ToStr(h.DigestSize())" in go_code
+
+    def test_hashlib_end_to_end(self, go_run_python):
+        """Test digests, incremental updates, attributes and an unknown algorithm against CPython."""
+        python_code = """
+import hashlib
+from hashlib import md5, sha256
+
+
+def checksum(text: str) -> str:
+    return hashlib.sha256(text.encode()).hexdigest()
+
+
+def main() -> None:
+    print(checksum("abc"), md5(b"abc").hexdigest())
+    h = sha256()
+    for chunk in [b"a", b"b", b"c"]:
+        h.update(chunk)
+    print(h.hexdigest() == checksum("abc"), h.name, h.digest_size * 8, h.block_size, len(h.digest()))
+    print(hashlib.new("sha1", b"abc").hexdigest(), hashlib.sha512(b"").hexdigest()[:16], hashlib.md5().digest())
+    try:
+        hashlib.new("nope")
+    except ValueError as e:
+        print("ValueError", e)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
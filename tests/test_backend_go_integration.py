@@ -195,9 +195,9 @@ def filter_data() -> list:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "mgen.ListComprehension" in go_code
-        assert "mgen.DictComprehension" in go_code
-        assert "mgen.NewRange(" in go_code
+        assert "comprehension := []int{}" in go_code
+        assert "comprehension := mgen.NewDict[int, int]()" in go_code
+        assert "for x := 0; x < n; x++ {" in go_code
 
     def test_mixed_features_program(self):
         """Test program combining multiple advanced features."""
@@ -227,8 +227,8 @@ def analyze_text(text: str) -> dict:
         assert "mgen.StrOps.Upper" in go_code
 
         # Check comprehensions
-        assert "mgen.DictComprehension" in go_code
-        assert "mgen.ListComprehension" in go_code
+        assert "comprehension.Set(word, mgen.LenString(word))" in go_code
+        assert "comprehension = append(comprehension, (obj.Prefix + mgen.StrOps.Upper(word)))" in go_code
 
     def test_control_flow_with_functions(self):
         """Test complex control flow with function calls."""
@@ -257,7 +257,7 @@ def count_primes_up_to(n: int) -> int:
         assert "func is_prime(n int) bool" in go_code
         assert "if (n < 2)" in go_code
         assert "for i := 2; i < n; i++" in go_code
-        assert "if ((n % i) == 0)" in go_code
+        assert "if (mgen.PyMod(n, i) == 0)" in go_code
 
         # Note: Complex list operations would need more sophisticated handling
         assert "find_primes(n)" in go_code
@@ -338,7 +338,7 @@ def main() -> None:
         # Verify method functionality
         assert "obj.Count +=" in go_code
         assert "mgen.Len" in go_code  # Generic len function
-        assert "mgen.ListComprehension" in go_code
+        assert "comprehension = append(comprehension, mgen.StrOps.Upper(item))" in go_code
         assert "mgen.DictComprehension" in go_code
 
         # Verify main function
@@ -0,0 +1,337 @@
+"""Tests for Go backend file reading, line splitting and stdin."""
+
+import pytest
+
+from mgen.backends.errors import TypeMappingError
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+
+class TestGoFileConversion:
+    """Test open()/readlines()/splitlines() code generation."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_open_and_readlines(self):
+        """Test open() and file methods map to the PyFile runtime type."""
+        python_code = """
+def count_lines(path: str) -> int:
+    f = open(path)
+    lines = f.readlines()
+    f.close()
+    return len(lines)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'f := mgen.Open(path, "r")' in go_code
+        assert "lines := f.ReadLines()" in go_code
+        assert "f.Close()" in go_code
+        assert "mgen.Len[string](lines)" in go_code
+
+    def test_iterate_file_and_splitlines(self):
+        """Test iterating a file and str.splitlines()."""
+        python_code = """
+def show(path: str, text: str) -> None:
+    f = open(path, "r")
+    for line in f:
+        print(line)
+    print(text.splitlines(), text.splitlines(True))
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "for _, line := range f.Lines() {" in go_code
+        assert "mgen.StrOps.Splitlines(text, false)" in go_code
+        assert "mgen.StrOps.Splitlines(text, true)" in go_code
+
+    def test_string_constant_escapes(self):
+        """Test escapes in string constants stay valid Go literals."""
+        python_code = """
+def text() -> str:
+    return "a\\r\\nb\\t\\"c\\""
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'return "a\\r\\nb\\t\\"c\\""' in go_code
+
+
+class TestGoLineSplittingRuntime:
+    """Test files and str.splitlines agree on line boundaries."""
+
+    def test_readlines_matches_splitlines(self, go_run):
+        """Test the same content gives the same line count through both paths."""
+        output = go_run(
+            """
+    content := "alpha\\r\\nbeta\\rgamma\\n\\ndelta"
+    out := mgen.Open("lines.txt", "w")
+    out.Write(content)
+    out.Close()
+
+    f := mgen.Open("lines.txt", "r")
+    fileLines := f.ReadLines()
+    f.Close()
+    textLines := mgen.StrOps.Splitlines(content, false)
+    mgen.Print(len(fileLines), len(textLines))
+    for i := range fileLines {
+        mgen.Print(mgen.StrOps.Strip(fileLines[i]) == textLines[i])
+    }
+"""
+        )
+        assert output.splitlines() == ["5 5", "True", "True", "True", "True", "True"]
+
+    def test_keepends(self, go_run):
+        """Test keepends keeps each original ending, with no empty line after a final newline."""
+        output = go_run(
+            """
+    for _, line := range mgen.StrOps.Splitlines("a\\r\\nb\\fc\\n", true) {
+        mgen.Print(len(line))
+    }
+    mgen.Print(len(mgen.StrOps.Splitlines("", false)), len(mgen.StrOps.Splitlines("\\n", false)))
+
+    out := mgen.Open("ends.txt", "w")
+    out.Write("a\\r\\nb\\fc\\n")
+    out.Close()
+    f := mgen.Open("ends.txt", "r")
+    mgen.Print(len(f.ReadLine()), len(f.ReadLines()))
+"""
+        )
+        # Python: [len(l) for l in "a\\r\\nb\\fc\\n".splitlines(True)] == [3, 2, 2];
+        # files only break on universal newlines and translate "\\r\\n" to "\\n"
+        assert output.splitlines() == ["3", "2", "2", "0 1", "2 1"]
+
+    def test_missing_file(self, go_run):
+        """Test opening a missing file raises FileNotFoundError."""
+        output = go_run(
+            """
+    defer func() { mgen.Print(recover().(error).Error()) }()
+    mgen.Open("missing.txt", "r")
+"""
+        )
+        assert output.strip() == "FileNotFoundError: [Errno 2] No such file or directory: 'missing.txt'"
+
+
+class TestGoStdoutRuntime:
+    """Test print output can be redirected and captured."""
+
+    def test_capture_print_into_buffer(self, go_run):
+        """Test SetStdout/RedirectStdout capture Print and PrintOpts output."""
+        output = go_run(
+            """
+    var buf bytes.Buffer
+    previous := mgen.SetStdout(&buf)
+    mgen.Print("captured", 1, true)
+    mgen.PrintOpts(mgen.PrintOptions{Sep: "|", End: "!"}, "a", "b")
+    mgen.SetStdout(previous)
+    mgen.Print(mgen.GetStdout() == os.Stdout, strings.ReplaceAll(buf.String(), "\\n", "/"))
+
+    var inner bytes.Buffer
+    restore := mgen.RedirectStdout(&inner)
+    mgen.Print("inside")
+    restore()
+    mgen.Print("outside", inner.String() == "inside\\n")
+""",
+            imports=("bytes", "os", "strings"),
+        )
+        assert output.splitlines() == ["True captured 1 True/a|b!", "outside True"]
+
+    def test_concurrent_prints(self, go_run):
+        """Test concurrent prints into a captured buffer keep whole lines."""
+        output = go_run(
+            """
+    var buf bytes.Buffer
+    restore := mgen.RedirectStdout(&buf)
+    var wg sync.WaitGroup
+    for i := 0; i < 50; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            mgen.Print("line", "of", "output")
+        }()
+    }
+    wg.Wait()
+    restore()
+    lines := strings.Split(strings.TrimSuffix(buf.String(), "\\n"), "\\n")
+    intact := 0
+    for _, line := range lines {
+        if line == "line of output" {
+            intact++
+        }
+    }
+    mgen.Print(len(lines), intact)
+""",
+            imports=("bytes", "strings", "sync"),
+        )
+        assert output.strip() == "50 50"
+
+
+class TestGoPrintKeywords:
+    """Test print() keyword arguments and builtin keyword checking."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_sep_and_end(self):
+        """Test sep/end keywords select PrintOpts with the remaining defaults filled in."""
+        python_code = """
+def show(sep: str) -> None:
+    print("a", "b", sep="|", end="!")
+    print(1, 2, sep=sep + sep)
+    print("plain", end=None)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'mgen.PrintOpts(mgen.PrintOptions{Sep: "|", End: "!"}, "a", "b")' in go_code
+        assert 'mgen.PrintOpts(mgen.PrintOptions{Sep: (sep + sep), End: "\\n"}, 1, 2)' in go_code
+        assert 'mgen.Print("plain")' in go_code
+
+    def test_file_keyword(self):
+        """Test file= targets a PyFile writer or sys.stderr."""
+        python_code = """
+import sys
+
+def log(path: str) -> None:
+    f = open(path, mode="a")
+    print("entry", file=f)
+    print("warning", file=sys.stderr)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'f := mgen.Open(path, "a")' in go_code
+        assert 'mgen.PrintOpts(mgen.PrintOptions{Sep: " ", End: "\\n", File: f.Writer()}, "entry")' in go_code
+        assert "File: mgen.GetStderr()}" in go_code
+
+    def test_flush_and_unpacked_arguments(self):
+        """Test flush= is passed through and *iterables spread into the print arguments."""
+        python_code = """
+import sys
+
+def show(xs: list[int], verbose: bool) -> None:
+    print(*xs, sep=", ", flush=True)
+    print("n:", *xs, "end", file=sys.stderr, flush=verbose)
+    print(*xs, flush=False)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'mgen.PrintOpts(mgen.PrintOptions{Sep: ", ", End: "\\n", Flush: true}, mgen.ToList(xs)...)' in go_code
+        assert (
+            'mgen.PrintOpts(mgen.PrintOptions{Sep: " ", End: "\\n", File: mgen.GetStderr(), Flush: verbose}, '
+            'mgen.Concat([]interface{}{"n:"}, mgen.ToList(xs), []interface{}{"end"})...)'
+        ) in go_code
+        assert "mgen.Print(mgen.ToList(xs)...)" in go_code
+
+    def test_print_in_method(self):
+        """Test print() inside a method uses the runtime rather than Go's builtin print."""
+        python_code = """
+class Greeter:
+    def __init__(self, name: str):
+        self.name: str = name
+
+    def greet(self) -> None:
+        print("hello", self.name, sep=", ")
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'mgen.PrintOpts(mgen.PrintOptions{Sep: ", ", End: "\\n"}, "hello", obj.Name)' in go_code
+
+    def test_unknown_builtin_keyword_rejected(self):
+        """Test keywords a builtin does not accept fail at transpile time."""
+        for call in ('print("x", color="red")', 'format(3, format_spec="d")', "len(x=[1])"):
+            with pytest.raises(TypeMappingError, match="unexpected keyword argument"):
+                self.converter.convert_code(f"def f() -> None:\n    {call}\n")
+
+    def test_print_keywords_end_to_end(self, go_run_python):
+        """Test sep/end/file output matches Python."""
+        python_code = """
+def main() -> None:
+    sep: str = "-"
+    print("a", "b", sep="|", end="!\\n")
+    print(1, 2, 3, sep=sep + sep, end=None)
+    f = open("print_out.txt", mode="w")
+    print("to file", 42, sep=":", file=f)
+    f.close()
+    g = open("print_out.txt")
+    print(g.read(), end="")
+"""
+        assert go_run_python(python_code).splitlines() == ["a|b!", "1--2--3", "to file:42"]
+
+    def test_flush_buffered_writer(self, go_run):
+        """Test Flush pushes a buffered file's output through, and is skipped when false."""
+        output = go_run(
+            """
+    var buf bytes.Buffer
+    w := bufio.NewWriter(&buf)
+    mgen.PrintOpts(mgen.PrintOptions{Sep: " ", End: "\\n", File: w}, "held")
+    mgen.Print(buf.Len())
+    args := mgen.Concat([]interface{}{0}, mgen.ToList("ab"))
+    mgen.PrintOpts(mgen.PrintOptions{Sep: ",", End: "\\n", File: w, Flush: true}, args...)
+    mgen.Print(strings.ReplaceAll(buf.String(), "\\n", "/"))
+""",
+            imports=("bufio", "bytes", "strings"),
+        )
+        assert output.splitlines() == ["0", "held/0,a,b/"]
+
+
+class TestGoStdin:
+    """Test input() and the typed stdin line readers."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_read_patterns_codegen(self):
+        """Test the usual input() parsing chains lower to typed readers instead of boxing each word."""
+        python_code = """
+def main() -> None:
+    n = int(input())
+    xs = list(map(int, input().split()))
+    fs = list(map(float, input().split()))
+    words = input().split()
+    ys = [int(w) for w in input().split()]
+    name = input("name? ")
+    print(n, xs, fs, words, ys, name)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "n := mgen.ToInt(mgen.ReadLine())" in go_code
+        assert "xs := mgen.ReadInts()" in go_code
+        assert "fs := mgen.ReadFloats()" in go_code
+        assert "words := mgen.ReadWords()" in go_code
+        assert "var ys []int = mgen.ReadInts()" in go_code
+        assert 'name := mgen.Input("name? ")' in go_code
+
+    def test_stdin_end_to_end(self, go_run_python):
+        """Test typed results from piped stdin match Python, including a \\r\\n line ending."""
+        python_code = """
+def main() -> None:
+    n = int(input())
+    xs = list(map(int, input().split()))
+    fs = list(map(float, input().split()))
+    words = input().split()
+    name = input("name? ")
+    total = 0
+    for v in map(int, input().split()):
+        total += v
+    print(n, sum(xs) * n, fs[0] + fs[1], words[1], len(words), name, total)
+"""
+        output = go_run_python(python_code, stdin="3\n1 2 3\n0.5 2.5e1\nfoo  bar baz\nbob\r\n 4 -5 \n")
+        assert output.splitlines() == ["name? 3 18 25.5 bar 3 bob -1"]
+
+    def test_parse_errors_and_eof(self, go_run):
+        """Test a bad word raises ValueError and reading past the end raises EOFError."""
+        output = go_run(
+            """
+    mgen.SetStdin(strings.NewReader("1 x 3\\n2.5 nope\\n  \\nlast"))
+    check(func() { mgen.ReadInts() })
+    check(func() { mgen.ReadFloats() })
+    mgen.Print(len(mgen.ReadWords()), mgen.ReadLine())
+    check(func() { mgen.ReadLine() })
+""",
+            imports=("strings",),
+        )
+        assert output.splitlines() == [
+            "ValueError: invalid literal for int() with base 10: 'x'",
+            "ValueError: could not convert string to float: 'nope'",
+            "0 last",
+            "EOFError: EOF when reading a line",
+        ]
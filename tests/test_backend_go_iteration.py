@@ -0,0 +1,372 @@
+"""Tests for Go backend sorted/reversed/enumerate/zip composition."""
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.backends.errors import TypeMappingError
+
+
+class TestGoIterationBuiltinsConversion:
+    """Test the iteration builtins lower to nestable runtime calls."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_enumerate_sorted_loop(self):
+        """Test for i, x in enumerate(sorted(xs)) ranges over the sorted slice without Pairs."""
+        python_code = """
+def show(xs: list[str]) -> None:
+    for i, x in enumerate(sorted(xs), start=1):
+        print(i, x)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "for i, x := range mgen.Sorted(xs, false) {\n    i += 1\n" in go_code
+        assert "mgen.Enumerate" not in go_code
+
+    def test_lazy_zip_loops(self):
+        """Test for loops over zip() of list names index the sources up to the shortest."""
+        python_code = """
+def show(xs: list[str], ns: list[int], fs: list[float]) -> None:
+    for a, b in zip(xs, ns):
+        print(a, b)
+    for a, _, c in zip(ns, xs, fs):
+        print(a, c)
+    for a, b in zip(sorted(ns), xs):
+        print(a, b)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "for index := 0; index < min(len(xs), len(ns)); index++ {\n    a, b := xs[index], ns[index]\n" in go_code
+        assert "index < min(len(ns), len(xs), len(fs)); index++ {\n    a, c := ns[index], fs[index]\n" in go_code
+        # Computed sources keep the composable slice helper
+        assert "for _, pair := range mgen.Zip(mgen.Sorted(ns, false), xs) {" in go_code
+
+    def test_zip3_values(self):
+        """Test zip() of three iterables builds mgen.Triple records outside for loops."""
+        python_code = """
+def show(xs: list[str], ns: list[int], fs: list[float]) -> list:
+    return [f"{x}{n}" for x, n, f in zip(xs, ns, fs) if f > 0]
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "for _, triple := range mgen.Zip3(xs, ns, fs) {" in go_code
+        assert "x, n, f := triple.First, triple.Second, triple.Third" in go_code
+
+    def test_reversed_enumerate_and_sort_keys(self):
+        """Test reversed(list(enumerate(xs))) and sorted() with key/reverse."""
+        python_code = """
+def show(xs: list[str], ns: list[int]) -> None:
+    for i, x in reversed(list(enumerate(xs))):
+        print(x)
+    longest = sorted(xs, key=len, reverse=True)
+    print(sorted(ns, reverse=True), longest)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "range mgen.Reversed(append([]mgen.Pair[int, string]{}, mgen.Enumerate(xs, 0)...))" in go_code
+        # i is unused in the loop body
+        assert "_, x := pair.First, pair.Second" in go_code
+        assert "mgen.SortedBy(xs, func(item string) int { return mgen.LenString(item) }, true)" in go_code
+        assert "mgen.Sorted(ns, true)" in go_code
+
+
+ZIP_PROGRAM = """
+def main() -> None:
+    xs = ["pear", "fig", "apple"]
+    ns = [3, 1, 2, 7]
+    fs = [0.5, 1.5]
+    for i, x in enumerate(xs, 1):
+        print(i, x)
+    start = 10
+    for i, _ in enumerate(ns, start=start):
+        print(i)
+    for a, b in zip(xs, ns):
+        print(a, b)
+    for a, b, c in zip(xs, ns, fs):
+        print(a, b, c)
+    total = 0
+    for a, _, c in zip(ns, xs, ns):
+        total += a * c
+    print(total)
+    print(repr(list(zip(xs, ns, fs))), repr([a + c for a, b, c in zip(ns, xs, ns) if b != "fig"]))
+    for t in zip(xs, ns, fs):
+        print(repr(t))
+"""
+
+
+class TestGoIterationBuiltinsRuntime:
+    """Test composed iteration builtins against Python output."""
+
+    def test_compositions_end_to_end(self, go_run_python):
+        """Test enumerate/sorted/reversed/zip nest like Python's builtins."""
+        python_code = """
+def main() -> None:
+    xs: list[str] = ["pear", "fig", "apple", "kiwi"]
+    for i, x in enumerate(sorted(xs)):
+        print(i, x)
+    for i, x in reversed(list(enumerate(xs))):
+        print(i, x)
+    ranked = [f"{i}:{x}" for i, x in enumerate(sorted(xs, key=len, reverse=True))]
+    print(ranked[0], ranked[3])
+    ns: list[int] = [3, 1, 2]
+    for a, b in zip(sorted(ns), reversed(xs)):
+        print(a, b)
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "0 apple",
+            "1 fig",
+            "2 kiwi",
+            "3 pear",
+            "3 kiwi",
+            "2 apple",
+            "1 fig",
+            "0 pear",
+            "0:apple 3:fig",
+            "1 kiwi",
+            "2 apple",
+            "3 fig",
+        ]
+
+    def test_lazy_loops_match_python(self, go_run_python):
+        """Test loops over enumerate()/zip() and Zip3 values print as in CPython."""
+        assert go_run_python(ZIP_PROGRAM) == python_output(ZIP_PROGRAM + "\nmain()\n")
+
+    def test_zip3_runtime(self, go_run):
+        """Test Zip3 stops at the shortest slice and Triples behave as tuples."""
+        output = go_run(
+            """
+    triples := mgen.Zip3([]int{1, 2, 3}, []string{"a", "b"}, []float64{0.5, 1.5, 2.5})
+    mgen.Print(len(triples), triples[1], mgen.Repr(triples))
+    mgen.Print(mgen.UnpackN(triples[0], 3)...)
+"""
+        )
+        assert output.splitlines() == ["2 (2, 'b', 1.5) [(1, 'a', 0.5), (2, 'b', 1.5)]", "1 a 0.5"]
+
+    def test_sort_stability(self, go_run):
+        """Test reverse sorts keep equal elements in their original order, as in Python."""
+        output = go_run(
+            """
+    words := []string{"bb", "a", "cc", "d", "ee"}
+    byLen := func(s string) int { return len(s) }
+    mgen.Print(mgen.SortedBy(words, byLen, true))
+    mgen.Print(mgen.SortedBy(words, byLen, false))
+    mgen.Print(mgen.Repr(mgen.SortedValues([]interface{}{2.5, 1, true}, false)), words[0])
+"""
+        )
+        # sorted(words, key=len, reverse=True) == ['bb', 'cc', 'ee', 'a', 'd']
+        assert output.splitlines() == [
+            "['bb', 'cc', 'ee', 'a', 'd']",
+            "['a', 'd', 'bb', 'cc', 'ee']",
+            "[1, True, 2.5] bb",
+        ]
+
+
+class TestGoListSortConversion:
+    """Test list.sort() lowers to the in-place mgen.Sort helpers."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_sort_keywords(self):
+        """Test xs.sort() with key= and reverse= sorts the slice in place."""
+        python_code = """
+def f(xs: list[int], ws: list[str], grid: list[list[int]]) -> None:
+    xs.sort(reverse=True)
+    ws.sort(key=len)
+    ws.sort(key=lambda w: (len(w), w), reverse=True)
+    grid.sort(key=None)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.Sort(xs, true)" in go_code
+        assert "mgen.SortBy(ws, func(item string) int { return mgen.LenString(item) }, false)" in go_code
+        tuple_key = "func(w string) interface{} { return mgen.PyTuple{mgen.LenString(w), w} }"
+        assert f"mgen.SortByValue(ws, {tuple_key}, true)" in go_code
+        assert "mgen.SortByValue(grid, func(item []int) interface{} { return item }, false)" in go_code
+
+    def test_sort_on_fields(self):
+        """Test self.items.sort() in a method resolves the field's list type."""
+        python_code = """
+class Board:
+    def __init__(self) -> None:
+        self.scores: list[int] = []
+
+    def rank(self) -> None:
+        self.scores.sort(reverse=True)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.Sort(obj.Scores, true)" in go_code
+
+    def test_positional_sort_arguments_rejected(self):
+        """Test list.sort() only accepts its keyword arguments, as in Python."""
+        python_code = """
+def f(ws: list[str]) -> None:
+    ws.sort(len)
+"""
+        with pytest.raises(TypeMappingError, match="list.sort\\(\\) takes only the key and reverse keywords"):
+            self.converter.convert_code(python_code)
+
+
+SORT_PROGRAM = """
+class Person:
+    def __init__(self, name: str, age: int) -> None:
+        self.name = name
+        self.age = age
+
+
+def main() -> None:
+    xs = [3, 1, 2, 1]
+    xs.sort()
+    print(repr(xs))
+    xs.sort(reverse=True)
+    print(repr(xs))
+    ws = ["pear", "fig", "apple", "kiwi", "date"]
+    ws.sort(key=len)
+    print(repr(ws))
+    ws.sort(key=len, reverse=True)
+    print(repr(ws))
+    ws.sort(key=lambda w: (len(w), w), reverse=True)
+    print(repr(ws))
+    print(repr(sorted(ws, key=lambda w: w[::-1])), repr(sorted([True, False, True])))
+    people = [Person("ann", 30), Person("bob", 25), Person("cy", 30)]
+    people.sort(key=lambda p: p.age)
+    print([p.name for p in people][0], people[2].name)
+    grid = [[2, 1], [1, 5], [1, 2]]
+    grid.sort()
+    print(repr(grid), repr(sorted(grid, reverse=True)))
+    mixed = [2.5, 1, True, 0.5]
+    mixed.sort(key=None)
+    print(repr(mixed))
+"""
+
+
+class TestGoListSortRuntime:
+    """Test list.sort() and sorted() agree with CPython, including stability."""
+
+    def test_sort_matches_python(self, go_run_python):
+        """Test in-place and copying sorts with keys and reverse print as in CPython."""
+        assert go_run_python(SORT_PROGRAM) == python_output(SORT_PROGRAM + "\nmain()\n")
+
+    def test_tuple_item_and_method_keys_match_python(self, go_run_python):
+        """Test key lambdas indexing tuple items, and str.lower as a key, sort as in CPython."""
+        python_code = """
+def main() -> None:
+    people: list[tuple[str, int]] = [("bob", 30), ("al", 25), ("cy", 30)]
+    print(sorted(people, key=lambda p: p[1]))
+    people.sort(key=lambda p: (-p[1], p[0]))
+    print(people)
+    pairs = [("a", 3), ("b", 1)]
+    print(min(pairs, key=lambda t: t[1]), max(pairs, key=lambda t: t[1]))
+    names = ["b", "A", "c"]
+    print(sorted(names, key=str.lower))
+    names.sort(key=str.lower, reverse=True)
+    print(names)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
+
+    def test_in_place_sort_stability(self, go_run):
+        """Test the in-place helpers keep equal keys in order and mutate the slice."""
+        output = go_run(
+            """
+    words := []string{"bb", "a", "cc", "d", "ee"}
+    mgen.SortBy(words, func(s string) int { return len(s) }, true)
+    mgen.Print(words)
+    ns := []int{3, 1, 2}
+    mgen.Sort(ns, false)
+    values := []interface{}{2.5, 1, true, 0}
+    mgen.SortValues(values, true)
+    mgen.Print(ns, mgen.Repr(values))
+"""
+        )
+        assert output.splitlines() == ["['bb', 'cc', 'ee', 'a', 'd']", "[1, 2, 3] [2.5, 1, True, 0]"]
+
+
+class TestGoForTargetUnpacking:
+    """Test nested and starred for targets unpack each item like Python."""
+
+    def test_nested_targets_use_typed_fields(self):
+        """Test enumerate(d.items()) unpacks the Pair and KV fields without boxing."""
+        python_code = """
+def f(d: dict[str, int]) -> int:
+    total = 0
+    for i, (k, v) in enumerate(d.items()):
+        total += i * v
+    return total
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        assert "for _, item := range mgen.Enumerate(d.Items(), 0) {" in go_code
+        assert "i := item.First" in go_code
+        assert "v := item.Second.Value" in go_code
+        assert "mgen.Unpack" not in go_code
+
+    def test_nested_and_starred_codegen(self):
+        """Test other tuple items go through UnpackN and UnpackStar."""
+        python_code = """
+def f() -> None:
+    for (a, b), *rest in [((1, 2), 3, 4)]:
+        print(a, rest)
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        assert "unpack1 := mgen.UnpackStar(item, 1, 0)" in go_code
+        assert "unpack2 := mgen.UnpackN(unpack1[0], 2)" in go_code
+        assert "a := unpack2[0]" in go_code
+        assert "b :=" not in go_code
+        assert "rest := unpack1[1].([]interface{})" in go_code
+
+    def test_unpacking_end_to_end(self, go_run_python):
+        """Test nested, starred and zipped targets match Python."""
+        python_code = """
+def main() -> None:
+    rows = [((1, 2), "a"), ((3, 4), "b")]
+    for (a, b), c in rows:
+        print(a, b, c)
+    for first, *middle, last in [[1, 2, 3, 4], [5, 6]]:
+        print(first, len(middle), last)
+    for *init, (x, y) in [(0, (1, 2)), ("z", (3, 4))]:
+        print(len(init), x, y)
+    scores: dict[str, int] = {"ann": 3}
+    for i, (name, score) in enumerate(scores.items(), start=1):
+        print(i, name, score * 2)
+    for n, (p, q) in zip([1, 2], ["ab", "cd"]):
+        print(n, p, q)
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "1 2 a",
+            "3 4 b",
+            "1 2 4",
+            "5 0 6",
+            "1 1 2",
+            "1 3 4",
+            "1 ann 6",
+            "1 a b",
+            "2 c d",
+        ]
+
+    def test_arity_errors(self, go_run):
+        """Test UnpackN and UnpackStar raise ValueError with Python's messages."""
+        output = go_run(
+            """
+    check(func() { mgen.UnpackN([]int{1}, 2) })
+    check(func() { mgen.UnpackN("abc", 2) })
+    check(func() { mgen.UnpackStar([]interface{}{1, 2}, 2, 1) })
+    check(func() { mgen.UnpackN(mgen.Pair[int, string]{First: 1, Second: "a"}, 3) })
+    mgen.Print(mgen.UnpackN(mgen.KV[string, int]{Key: "k", Value: 1}, 2)...)
+    mgen.Print(mgen.Repr(mgen.UnpackStar("abcd", 1, 1)))
+"""
+        )
+        assert output.splitlines() == [
+            "ValueError: not enough values to unpack (expected 2, got 1)",
+            "ValueError: too many values to unpack (expected 2)",
+            "ValueError: not enough values to unpack (expected at least 3, got 2)",
+            "ValueError: not enough values to unpack (expected 3, got 2)",
+            "k 1",
+            "['a', ['b', 'c'], 'd']",
+        ]
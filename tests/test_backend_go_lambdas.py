@@ -0,0 +1,201 @@
+"""Tests for Go backend lambda expressions, key functions, map and filter."""
+
+import pytest
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+
+class TestGoLambdaConversion:
+    """Test lambdas become Go func literals typed from their context."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_sort_key_takes_element_type(self):
+        """Test a key= lambda takes the element type and returns its inferred result type."""
+        python_code = """
+def by_length(words: list[str]) -> list[str]:
+    return sorted(words, key=lambda w: len(w))
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.SortedBy(words, func(w string) int { return mgen.LenString(w) }, false)" in go_code
+
+    def test_tuple_key_sorts_by_value(self):
+        """Test a tuple key is compared with Python's rules instead of Go's ordered types."""
+        python_code = """
+def by_length(words: list[str]) -> list[str]:
+    return sorted(words, key=lambda w: (len(w), w), reverse=True)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.SortedByValue(words, func(w string) interface{} {" in go_code
+
+    def test_map_and_filter(self):
+        """Test map/filter lower to MapSlice/FilterSlice and non-bool predicates test truthiness."""
+        python_code = """
+def process(nums: list[int]) -> list[int]:
+    scaled = list(map(lambda n: n * 10, nums))
+    return list(filter(lambda n: n % 3, scaled))
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.MapSlice(func(n int) int { return (n * 10) }, nums)" in go_code
+        assert "mgen.ToBool((func(n int) int { return mgen.PyMod(n, 3) })(item))" in go_code
+
+    def test_callable_binding(self):
+        """Test lambdas passed to or returned as a Callable take its parameter types."""
+        python_code = """
+from typing import Callable
+
+
+def apply(f: Callable[[int], int], x: int) -> int:
+    return f(x)
+
+
+def make_adder(n: int) -> Callable[[int], int]:
+    return lambda x: x + n
+
+
+def square(v: int) -> int:
+    return apply(lambda k: k * k, v)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "    return func(x int) int { return (x + n) }" in go_code
+        assert "apply(func(k int) int { return (k * k) }, v)" in go_code
+
+    def test_method_and_container_binding(self):
+        """Test lambdas passed to methods and constructors, or stored in lists and dicts, take the func type."""
+        python_code = """
+from typing import Callable
+
+
+class Button:
+    def __init__(self, label: str, handler: Callable[[str], str]) -> None:
+        self.label = label
+        self.handlers: list[Callable[[str], str]] = [handler]
+
+    def on_click(self, handler: Callable[[str], str]) -> None:
+        self.handlers.append(handler)
+
+
+def main() -> None:
+    b = Button("ok", lambda s: s + "!")
+    b.on_click(lambda s: s.upper())
+    ops: dict[str, Callable[[int, int], int]] = {"add": lambda a, b: a + b}
+    print(ops["add"](2, 3), (lambda a, b: a - b)(5, 3))
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'b := NewButton("ok", func(s string) string { return (s + "!") })' in go_code
+        assert "obj.Handlers = []func(string) string{handler}" in go_code
+        assert "b.OnClick(func(s string) string { return mgen.StrOps.Upper(s) })" in go_code
+        assert 'Value: func(a int, b int) int { return (a + b) }}' in go_code
+        assert 'mgen.Print(ops.Get("add")(2, 3), func(a int, b int) int { return (a - b) }(5, 3))' in go_code
+
+    def test_default_parameters_unsupported(self):
+        """Test lambda parameter defaults are rejected."""
+        python_code = """
+def f() -> int:
+    g = lambda a=1: a
+    return g()
+"""
+        with pytest.raises(TypeMappingError, match="lambda default"):
+            self.converter.convert_code(python_code)
+
+
+class TestGoLambdaRuntime:
+    """Test lambdas produce the same results as Python."""
+
+    def test_lambdas_end_to_end(self, go_run_python):
+        """Test key functions, map/filter, Callable arguments and assigned lambdas."""
+        python_code = """
+from typing import Callable
+
+
+def apply(f: Callable[[int], int], x: int) -> int:
+    return f(x)
+
+
+def make_adder(n: int) -> Callable[[int], int]:
+    return lambda x: x + n
+
+
+def main() -> None:
+    words: list[str] = ["banana", "kiwi", "apple", "fig"]
+    print(repr(sorted(words, key=lambda w: len(w))))
+    print(repr(sorted(words, key=lambda w: (len(w), w), reverse=True)))
+    nums: list[int] = [1, 2, 3, 4, 5, 6]
+    scale = 10
+    print(repr(list(map(lambda n: n * scale, nums))))
+    print(repr(list(filter(lambda n: n % 2 == 0, nums))))
+    print(repr(list(filter(lambda n: n % 3, nums))))
+    print(repr(list(map(str, nums))))
+    print(max(words, key=lambda w: len(w)), min(nums, key=lambda n: abs(n - 4)))
+    print(apply(lambda v: v * v, 7))
+    add3 = make_adder(3)
+    print(add3(4))
+    double = lambda v: v * 2
+    print(double(21))
+    total = 0
+    for m in map(lambda n: n + 1, nums):
+        total += m
+    print(total)
+    counts: dict[str, int] = {"a": 3, "b": 7, "c": 1}
+    print(max(counts, key=lambda k: counts[k]))
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "['fig', 'kiwi', 'apple', 'banana']",
+            "['banana', 'apple', 'kiwi', 'fig']",
+            "[10, 20, 30, 40, 50, 60]",
+            "[2, 4, 6]",
+            "[1, 2, 4, 5]",
+            "['1', '2', '3', '4', '5', '6']",
+            "banana 4",
+            "49",
+            "7",
+            "42",
+            "27",
+            "b",
+        ]
+
+    def test_lambdas_in_classes_and_containers_end_to_end(self, go_run_python):
+        """Test lambdas stored in fields, lists and dicts, passed to methods and called immediately."""
+        python_code = """
+from typing import Callable
+
+
+class Button:
+    def __init__(self, label: str) -> None:
+        self.label = label
+        self.handlers: list[Callable[[str], str]] = []
+
+    def on_click(self, handler: Callable[[str], str]) -> None:
+        self.handlers.append(handler)
+
+    def click(self) -> list[str]:
+        results: list[str] = []
+        for handler in self.handlers:
+            results.append(handler(self.label))
+        return results
+
+
+def main() -> None:
+    b = Button("ok")
+    prefix = ">"
+    b.on_click(lambda s: prefix + s)
+    b.on_click(lambda s: s.upper())
+    print(b.click())
+    fs: list[Callable[[int], int]] = [lambda x: x + 1, lambda x: x * 2]
+    ops: dict[str, Callable[[int, int], int]] = {"add": lambda a, b: a + b, "sub": lambda a, b: a - b}
+    print([f(10) for f in fs], fs[1](4), ops["add"](2, 3), ops["sub"](2, 3))
+    print((lambda a, b: a * b)(6, 7), (lambda: "done")())
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "['>ok', 'OK']",
+            "[11, 20] 8 5 -1",
+            "42 done",
+        ]
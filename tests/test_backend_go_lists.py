@@ -0,0 +1,191 @@
+"""Tests for the Go backend's list methods and list operators."""
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+
+class TestGoListConversion:
+    """Test list methods and operators lower to the mgen.List* helpers."""
+
+    def test_list_methods(self):
+        """Test methods that change the length take the slice by pointer and the others take it by value."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+def check(grid: list[list[int]]) -> None:
+    nums: list[int] = [1, 2, 3]
+    words: list[str] = []
+    nums.insert(0, 5)
+    nums.remove(5)
+    last = nums.pop()
+    first = nums.pop(0)
+    print(last, first, nums.index(3), nums.index(3, 1, -1), nums.count(2))
+    nums.reverse()
+    grid[1].reverse()
+    words.extend("ab")
+    nums.extend(range(3))
+    backup = nums.copy()
+    nums.clear()
+    print(backup)
+"""
+        )
+
+        assert "mgen.ListInsert(&nums, 0, 5)" in go_code
+        assert "mgen.ListRemove(&nums, 5)" in go_code
+        assert "mgen.ListPop(&nums)" in go_code
+        assert "mgen.ListPop(&nums, 0)" in go_code
+        assert "mgen.ListIndex(nums, 3), mgen.ListIndex(nums, 3, 1, (-1)), mgen.ListCount(nums, 2)" in go_code
+        assert "mgen.ListReverse(nums)" in go_code
+        assert "mgen.ListReverse(grid[1])" in go_code
+        assert 'mgen.ExtendSlice(&words, mgen.StrOps.Chars("ab"))' in go_code
+        assert "mgen.ExtendSlice(&nums, mgen.NewRange(3).ToSlice())" in go_code
+        assert "append([]int{}, nums...)" in go_code
+        assert "mgen.ListClear(&nums)" in go_code
+
+    def test_list_operators(self):
+        """Test + and * on lists build new slices of the list's type."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+def check(a: list[int], b: list[int], n: int) -> None:
+    print(a + b, a * n, 3 * b)
+"""
+        )
+
+        assert "mgen.ConcatSlices(a, b), mgen.RepeatSlice(a, n), mgen.RepeatSlice(b, 3)" in go_code
+
+    def test_unsupported_list_calls(self):
+        """Test unknown methods, wrong arities and items of another type are rejected."""
+        for call, message in (
+            ("xs.insert(1)", r"Unsupported list method call"),
+            ("xs.frobnicate()", r"Unsupported list method call"),
+            ("xs.extend(['a'])", r"Cannot extend a \[\]int with \[\]string items"),
+        ):
+            python_code = f"def f(xs: list[int]) -> None:\n    {call}\n"
+            with pytest.raises(TypeMappingError, match=message):
+                MGenPythonToGoConverter().convert_code(python_code)
+
+
+class TestGoListRuntime:
+    """Test the runtime list helpers raise Python's errors."""
+
+    def test_list_errors(self, go_run):
+        """Test remove, pop and index raise ValueError and IndexError with CPython's messages."""
+        output = go_run(
+            """
+    xs := []int{1, 2}
+    for _, f := range []func(){
+        func() { mgen.ListRemove(&xs, 9) },
+        func() { mgen.ListPop(&[]string{}) },
+        func() { mgen.ListPop(&xs, 5) },
+        func() { mgen.ListIndex([]string{"a"}, "b") },
+        func() { mgen.ListIndex(xs, 1, 1) },
+    } {
+        func() {
+            defer func() {
+                if r := recover(); r != nil {
+                    mgen.Print(r)
+                }
+            }()
+            f()
+        }()
+    }
+    mgen.ListInsert(&xs, -10, 0)
+    mgen.ListInsert(&xs, 10, 3)
+    mgen.Print(xs)
+    mgen.Print(mgen.ListPop(&xs, -1), xs)
+"""
+        )
+        assert output.splitlines() == [
+            "list.remove(x): x not in list",
+            "pop from empty list",
+            "pop index out of range",
+            "'b' is not in list",
+            "1 is not in list",
+            "[0, 1, 2, 3]",
+            "3 [0, 1, 2]",
+        ]
+
+
+class TestGoListProgram:
+    """Test programs using list methods print what CPython prints."""
+
+    def test_list_methods(self, go_run_python):
+        """Test list methods on typed, mixed and nested lists."""
+        python_code = """
+def grow(xs: list[str]) -> list[str]:
+    xs.extend("ab")
+    xs.extend({"k": 1})
+    xs.insert(1, "z")
+    return xs
+
+
+def main() -> None:
+    mixed = [1, "a", 2.5]
+    mixed.extend((3, 4))
+    mixed.remove(1.0)
+    print(mixed, mixed.index("a"), mixed.count(4))
+    nums = [5, 1, 4]
+    nums.extend(range(3))
+    nums.extend([7, 8])
+    nums.pop()
+    print(nums, nums.index(1, 1, -1), nums * 0, 2 * nums[:2] + [9])
+    grid = [[1], [2, 3]]
+    grid[1].reverse()
+    grid[0].insert(0, 0)
+    print(grid, grid.count([2, 3]), grid.index([3, 2]))
+    print(grow(["q"]))
+    fl = [1.5, 2.0]
+    fl.remove(2)
+    backup = fl.copy()
+    fl.clear()
+    print(fl, backup, backup.index(1.5), True * [3])
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
+
+    def test_list_methods_on_a_field(self, go_run_python):
+        """List methods on self.items lower like they do on a local list."""
+        python_code = """
+class Stack:
+    def __init__(self) -> None:
+        self.items: list[int] = []
+
+    def push(self, x: int) -> None:
+        self.items.append(x)
+
+    def pop(self) -> int:
+        return self.items.pop()
+
+    def drop(self, x: int) -> None:
+        self.items.remove(x)
+
+    def push_all(self, xs: list[int]) -> None:
+        self.items.extend(xs)
+
+    def push_bottom(self, x: int) -> None:
+        self.items.insert(0, x)
+
+    def find(self, x: int) -> int:
+        return self.items.index(x)
+
+    def count(self, x: int) -> int:
+        return self.items.count(x)
+
+
+def main() -> None:
+    s = Stack()
+    s.push(1)
+    s.push_all([2, 3, 2])
+    s.push_bottom(0)
+    print(s.items, s.pop(), s.find(3), s.count(2))
+    s.drop(3)
+    print(s.items)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
@@ -0,0 +1,394 @@
+"""Tests for the Go backend's match statement support."""
+
+import re
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+COMMANDS_PROGRAM = """
+def run(lines: list[str]) -> int:
+    total: int = 0
+    for line in lines:
+        match line.split():
+            case ["add", n]:
+                total += int(n)
+            case ["sub", n]:
+                total -= int(n)
+            case ["stop"]:
+                break
+            case []:
+                continue
+            case [cmd, *_] if cmd == "#":
+                print("comment")
+            case other:
+                print("bad", len(other))
+    return total
+
+
+def grade(score: int) -> str:
+    match score:
+        case 100:
+            return "perfect"
+        case 0 | 1 | 2:
+            return "none"
+        case s if s >= 50:
+            return "pass"
+        case _:
+            return "fail"
+
+
+def main() -> None:
+    print(run(["add 5", "", "# note", "sub 2", "add 10", "what now is", "stop", "add 100"]))
+    for score in [100, 1, 70, 20]:
+        print(grade(score))
+
+
+if __name__ == "__main__":
+    main()
+"""
+
+SHAPES_PROGRAM = """
+def kind(value: object) -> str:
+    match value:
+        case None:
+            return "none"
+        case True:
+            return "true"
+        case int(v) if v > 100:
+            return "big int"
+        case int():
+            return "int"
+        case str(s):
+            return "str " + s
+        case [[a, b], [c, d]]:
+            return "2x2 " + str(a) + str(d)
+        case [0, *middle, 0]:
+            return "zero-framed " + str(len(middle))
+        case [first, *rest]:
+            return "seq " + str(first) + " +" + str(len(rest))
+    return "other"
+
+
+def main() -> None:
+    print(kind(None))
+    print(kind(True))
+    print(kind(500))
+    print(kind(5))
+    print(kind("hi"))
+    print(kind([[1, 2], [3, 4]]))
+    print(kind([0, 1, 2, 0]))
+    print(kind([0, 0]))
+    print(kind([7, 8, 9]))
+    print(kind([]))
+    print(kind(2.5))
+
+
+if __name__ == "__main__":
+    main()
+"""
+
+STRUCTURES_PROGRAM = """from dataclasses import dataclass
+
+
+@dataclass
+class Point:
+    x: int
+    y: int
+
+
+class Shape:
+    def area(self) -> float:
+        return 0.0
+
+
+class Circle(Shape):
+    __match_args__ = ("r",)
+
+    def __init__(self, r: float):
+        self.r = r
+
+    def area(self) -> float:
+        return 3.0 * self.r * self.r
+
+
+class Rect(Shape):
+    def __init__(self, w: float, h: float):
+        self.w = w
+        self.h = h
+
+    @property
+    def square(self) -> bool:
+        return self.w == self.h
+
+
+def where(p: Point) -> str:
+    match p:
+        case Point(0, 0):
+            return "origin"
+        case Point(x=0, y=y):
+            return "on y at " + str(y)
+        case Point(x, 0):
+            return "on x at " + str(x)
+        case Point(x, y) if x == y:
+            return "diagonal"
+        case _:
+            return "elsewhere"
+
+
+def shape(s: Shape) -> str:
+    match s:
+        case Circle(r) if r > 10:
+            return "big circle"
+        case Circle(r=r):
+            return "circle " + str(r)
+        case Rect(square=True, w=w):
+            return "square " + str(w)
+        case Rect(w=w, h=h):
+            return "rect " + str(w * h)
+        case Shape():
+            return "shape"
+    return "none"
+
+
+def handle(event: object) -> str:
+    match event:
+        case {"type": "click", "pos": [x, y]}:
+            return "click " + str(x) + "," + str(y)
+        case {"type": "key", "key": str(k), **rest}:
+            return "key " + k + " " + str(len(rest))
+        case {"type": t}:
+            return "other " + str(t)
+        case Point(x=x):
+            return "point " + str(x)
+        case {}:
+            return "empty-ish"
+        case _:
+            return "not a mapping"
+
+
+def lookup(config: dict[str, int]) -> int:
+    match config:
+        case {"width": w, "height": h, **others}:
+            return w * h + len(others)
+        case {"width": w}:
+            return w
+    return -1
+
+
+class Machine:
+    def __init__(self) -> None:
+        self.state = "idle"
+
+    def step(self, command: list[str]) -> str:
+        match command:
+            case ["start"] if self.state == "idle":
+                self.state = "running"
+            case ["stop"]:
+                self.state = "idle"
+            case [other, *_]:
+                return "ignored " + other
+        return self.state
+
+
+def main() -> None:
+    for p in [Point(0, 0), Point(0, 3), Point(4, 0), Point(2, 2), Point(1, 5)]:
+        print(where(p))
+    print(shape(Circle(12.0)), shape(Circle(1.0)), shape(Rect(2.0, 2.0)), shape(Rect(2.0, 3.0)), shape(Shape()))
+    print(handle({"type": "click", "pos": [1, 2]}))
+    print(handle({"type": "key", "key": "a", "mod": "ctrl"}))
+    print(handle({"type": 5}))
+    print(handle({"kind": 1}))
+    print(handle(Point(7, 8)))
+    print(handle([1]))
+    print(lookup({"width": 2, "height": 3, "depth": 4}))
+    print(lookup({"width": 5}))
+    print(lookup({"height": 1}))
+    m = Machine()
+    print(m.step(["start"]), m.step(["start"]), m.step(["jump", "x"]), m.step(["stop"]))
+
+
+if __name__ == "__main__":
+    main()
+"""
+
+NARROWING_PROGRAM = """
+def describe(v: object) -> str:
+    match v:
+        case bool():
+            return "bool"
+        case str() as s:
+            return s.upper() + "!"
+        case int() as n:
+            return str(n + 1)
+        case float() as f:
+            return str(f * 2)
+        case list() as xs:
+            return str(len(xs)) + " items, first " + str(xs[0])
+        case _:
+            return "other"
+
+
+def main() -> None:
+    print(describe("hi"), describe(3), describe(True), describe(1.5), describe([7, 2]), describe(None))
+
+
+if __name__ == "__main__":
+    main()
+"""
+
+
+class TestGoMatchCodegen:
+    """Test match statements lower to chains of pattern checks."""
+
+    def test_literal_and_capture_chain(self):
+        """Test cases without capture-reading guards become an if/else chain."""
+        python_code = """
+def describe(n: int) -> str:
+    match n:
+        case 0:
+            return "zero"
+        case 1 | 2:
+            return "few"
+        case other:
+            return "many " + str(other)
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        assert "if n == 0 {" in go_code
+        assert "} else if (n == 1 || n == 2) {" in go_code
+        assert "} else {\n    other := n\n" in go_code
+        assert "matched" not in go_code
+        assert 'panic("unreachable")' not in go_code
+
+    def test_sequence_patterns(self):
+        """Test typed slices are indexed directly and other values go through MatchSequence."""
+        python_code = """
+def first_two(words: list[str], value: object) -> None:
+    match words:
+        case [a, b, *rest]:
+            print(a, b, len(rest))
+    match value:
+        case [x, 0]:
+            print(x)
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        assert "if len(words) >= 2 {" in go_code
+        assert "rest := words[2:]" in go_code
+        assert "if mgen.MatchSequence(value, 2, false) && mgen.Eq(mgen.UnpackN(value, 2)[1], 0) {" in go_code
+        assert "x := mgen.UnpackN(value, 2)[0]" in go_code
+
+    def test_guard_reading_capture_uses_flag(self):
+        """Test a guard on a capture runs after binding, falling through via a matched flag."""
+        python_code = """
+def sign(n: int) -> str:
+    match n:
+        case x if x < 0:
+            return "negative"
+        case _:
+            return "non-negative"
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        assert "matched1 := false" in go_code
+        assert "    x := n\n    if (x < 0) {\n    matched1 = true\n" in go_code
+        assert "if !matched1 {" in go_code
+
+    def test_class_patterns(self):
+        """Test fields are read through the subject's class, __match_args__ giving positional order."""
+        go_code = MGenPythonToGoConverter().convert_code(STRUCTURES_PROGRAM)
+        assert "    if p.X == 0 && p.Y == 0 {" in go_code
+        assert "    if !matched1 && p.Y == 0 {\n    x := p.X" in go_code
+        assert "mgen.MatchInstance[Rect](s) && mgen.Instance[Rect](s).GetSquare() {" in go_code
+        assert "} else if mgen.MatchInstance[Point](event) {\n    x := mgen.Instance[Point](event).X" in go_code
+
+    def test_mapping_patterns(self):
+        """Test typed dicts are looked up directly and other values through MatchMapping."""
+        go_code = MGenPythonToGoConverter().convert_code(STRUCTURES_PROGRAM)
+        assert 'if config.Contains("width") && config.Contains("height") {' in go_code
+        assert 'others := mgen.DictWithout(config, "width", "height")' in go_code
+        assert (
+            'mgen.MatchMapping(event, "type", "key") && mgen.Eq(mgen.MappingValue(event, "type"), "key")'
+        ) in go_code
+        assert 'rest := mgen.MappingRest(event, "type", "key")' in go_code
+
+    def test_builtin_class_capture_is_narrowed(self):
+        """Test str() as s binds s as a string, and int/float/list captures as their Go types."""
+        go_code = MGenPythonToGoConverter().convert_code(NARROWING_PROGRAM)
+        assert '} else if mgen.MatchClass(v, "str") {\n    s := v.(string)\n' in go_code
+        assert "    n := mgen.ToInt(v)\n" in go_code
+        assert "    f := v.(float64)\n" in go_code
+        assert "    xs := mgen.ToList(v)\n" in go_code
+
+    def test_positional_subpattern_count(self):
+        """Test more positional subpatterns than __match_args__ names are rejected like Python's TypeError."""
+        python_code = """
+from dataclasses import dataclass
+
+
+@dataclass
+class Point:
+    x: int
+    y: int
+
+
+def f(p: Point) -> None:
+    match p:
+        case Point(1, 2, 3):
+            print(1)
+"""
+        message = "TypeError: Point() accepts 2 positional sub-patterns (3 given)"
+        with pytest.raises(TypeMappingError, match=re.escape(message)):
+            MGenPythonToGoConverter().convert_code(python_code)
+
+    @pytest.mark.parametrize(
+        "case, message",
+        [
+            ("Point(x=0)", "Class patterns need a builtin type or a generated class"),
+            ("[a] | (a, _)", "or-patterns that capture names are not supported"),
+        ],
+    )
+    def test_unsupported_patterns(self, case, message):
+        """Test pattern kinds the lowering does not cover fail at transpile time."""
+        python_code = f"""
+def f(value: object) -> None:
+    match value:
+        case {case}:
+            print(1)
+"""
+        with pytest.raises(TypeMappingError, match=message):
+            MGenPythonToGoConverter().convert_code(python_code)
+
+
+class TestGoMatchRuntime:
+    """Test transpiled match statements behave like CPython."""
+
+    def test_literal_capture_and_guard_cases(self, go_run_python):
+        """Test literals, or-patterns, guards, break and continue inside a loop."""
+        assert go_run_python(COMMANDS_PROGRAM) == python_output(COMMANDS_PROGRAM)
+
+    def test_class_singleton_and_sequence_cases(self, go_run_python):
+        """Test None/True, builtin class patterns and nested and starred sequences."""
+        assert go_run_python(SHAPES_PROGRAM) == python_output(SHAPES_PROGRAM)
+
+    def test_class_mapping_and_method_cases(self, go_run_python):
+        """Test class patterns over a hierarchy, mapping patterns with **rest, and a match in a method."""
+        assert go_run_python(STRUCTURES_PROGRAM) == python_output(STRUCTURES_PROGRAM)
+
+    def test_builtin_class_captures(self, go_run_python):
+        """Test captures of builtin class patterns are used as the types they matched."""
+        assert go_run_python(NARROWING_PROGRAM) == python_output(NARROWING_PROGRAM)
+
+    def test_sequence_helpers(self, go_run):
+        """Test MatchSequence accepts lists and tuples but not strings, bytes or dicts."""
+        output = go_run(
+            """
+    mgen.Print(mgen.MatchSequence([]int{1, 2}, 2, false), mgen.MatchSequence([]int{1, 2, 3}, 2, false))
+    mgen.Print(mgen.MatchSequence([]int{1, 2, 3}, 2, true), mgen.MatchSequence([]int{1}, 2, true))
+    mgen.Print(mgen.MatchSequence(mgen.Pair[int, string]{First: 1, Second: "a"}, 2, false))
+    mgen.Print(mgen.MatchSequence("ab", 2, false), mgen.MatchSequence(mgen.PyBytes("ab"), 2, false))
+    mgen.Print(mgen.MatchSequence(mgen.NewPyDict(), 0, false), mgen.MatchSequence(nil, 0, true))
+    mgen.Print(mgen.MatchClass(true, "int"), mgen.MatchClass(1, "float"), mgen.MatchClass(nil, "object"))
+"""
+        )
+        assert output.splitlines() == ["True False", "True False", "True", "False False", "False False", "True False True"]
@@ -0,0 +1,69 @@
+"""Tests for Go backend nested-list shape and matrix printing helpers."""
+
+
+class TestGoMatrixRuntime:
+    """Test Shape and PrintMatrix on nested slices."""
+
+    def test_shape(self, go_run):
+        """Test Shape of scalars, 1D, 2D, 3D and empty nested slices."""
+        output = go_run(
+            """
+    mgen.Print(mgen.Repr(mgen.Shape(7)), mgen.Repr(mgen.Shape([]int{1, 2, 3})))
+    mgen.Print(mgen.Repr(mgen.Shape([][]float64{{1, 2}, {3, 4}, {5, 6}})))
+    mgen.Print(mgen.Repr(mgen.Shape([][][]int{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}}, {{9, 10}, {11, 12}}})))
+    mgen.Print(mgen.Repr(mgen.Shape([]interface{}{[]int{1, 2}, []interface{}{3, "x"}})))
+    mgen.Print(mgen.Repr(mgen.Shape([][]int{})), mgen.Repr(mgen.Shape([][]int{{}, {}})))
+"""
+        )
+        assert output.splitlines() == ["[] [3]", "[3, 2]", "[3, 2, 2]", "[2, 2]", "[0] [2, 0]"]
+
+    def test_ragged_shape_raises(self, go_run):
+        """Test rows of different lengths or depths raise ValueError."""
+        output = go_run(
+            """
+    check := func(x interface{}) {
+        defer func() { mgen.Print(recover().(error).Error()) }()
+        mgen.Shape(x)
+    }
+    check([][]int{{1, 2}, {3}})
+    check([][][]int{{{1}, {2}}, {{3}, {4, 5}}})
+    check([]interface{}{1, []int{2}})
+"""
+        )
+        assert output.splitlines() == [
+            "ValueError: inhomogeneous shape after 1 dimensions; the detected shape was (2,) + inhomogeneous part",
+            "ValueError: inhomogeneous shape after 2 dimensions; the detected shape was (2, 2) + inhomogeneous part",
+            "ValueError: inhomogeneous shape after 1 dimensions; the detected shape was (2,) + inhomogeneous part",
+        ]
+
+    def test_print_matrix_2d(self, go_run):
+        """Test 2D matrices right-align every column to the widest element."""
+        output = go_run(
+            """
+    mgen.PrintMatrix([][]int{{1, 2, 3}, {10, 20, -300}})
+    mgen.PrintMatrix([][]float64{{1, 0.5}, {-2.25, 3}})
+    mgen.PrintMatrix([]int{4, 5})
+"""
+        )
+        assert output.splitlines() == [
+            "[[   1    2    3]",
+            " [  10   20 -300]]",
+            "[[  1.0   0.5]",
+            " [-2.25   3.0]]",
+            "[4 5]",
+        ]
+
+    def test_print_matrix_3d(self, go_run):
+        """Test 3D matrices separate their 2D blocks with a blank line."""
+        output = go_run(
+            """
+    mgen.PrintMatrix([][][]int{{{1, 2}, {3, 4}}, {{5, 6}, {7, 80}}})
+"""
+        )
+        assert output.splitlines() == [
+            "[[[ 1  2]",
+            "  [ 3  4]]",
+            "",
+            " [[ 5  6]",
+            "  [ 7 80]]]",
+        ]
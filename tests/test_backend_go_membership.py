@@ -0,0 +1,128 @@
+"""Tests for the Go backend's lowering of the in and not in operators."""
+
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+
+class TestGoMembershipConversion:
+    """Test membership tests pick the container type's helper."""
+
+    def test_typed_fast_paths(self):
+        """Test strings, ranges, typed slices, dicts and sets avoid the dynamic mgen.Contains."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+def check(n: int, word: str, nums: list[int], words: list[str], d: dict[str, int], s: set[int]) -> None:
+    print("ab" in word, n in range(1, 10, 2), n not in nums, word in words, word in d, n not in s)
+"""
+        )
+
+        assert 'mgen.StrOps.Contains(word, "ab")' in go_code
+        assert "mgen.NewRange(1, 10, 2).Contains(n)" in go_code
+        assert "!mgen.SliceContains(nums, n)" in go_code
+        assert "mgen.SliceContains(words, word)" in go_code
+        assert "d.Contains(word)" in go_code
+        assert "!s.Contains(n)" in go_code
+
+    def test_mixed_types_use_contains(self):
+        """Test items of another type than the slice's elements keep Python's == through mgen.Contains."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+def check(x: float, nums: list[int], flags: list[bool], items: list) -> None:
+    print(x in nums, 1 in flags, x in items)
+"""
+        )
+
+        assert "mgen.Contains(nums, x)" in go_code
+        assert "mgen.Contains(flags, 1)" in go_code
+        assert "mgen.Contains(items, x)" in go_code
+
+    def test_method_membership(self):
+        """Test in and not in convert inside methods."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+class Bag:
+    def __init__(self) -> None:
+        self.items: list[int] = []
+
+    def lacks(self, x: int) -> bool:
+        return x not in self.items
+"""
+        )
+
+        assert "return !mgen.SliceContains(obj.Items, x)" in go_code
+
+
+class TestGoMembershipRuntime:
+    """Test the runtime membership helpers."""
+
+    def test_range_contains(self, go_run):
+        """Test Range.Contains and mgen.Contains on a range agree with Python without iterating."""
+        output = go_run(
+            """
+    r := mgen.NewRange(2, 20, 3)
+    mgen.Print(r.Contains(2), r.Contains(17), r.Contains(20), r.Contains(6), r.Contains(-1))
+    down := mgen.NewRange(10, 0, -4)
+    mgen.Print(down.Contains(10), down.Contains(2), down.Contains(0), down.Contains(4))
+    mgen.Print(mgen.Contains(r, 5.0), mgen.Contains(r, 5.5), mgen.Contains(mgen.NewRange(2), true))
+    mgen.Print(mgen.Contains(r, "5"), mgen.NewRange(1 << 62).Contains(1 << 61), mgen.SliceContains([]string{"a"}, "a"))
+"""
+        )
+        assert output.splitlines() == [
+            "True True False False False",
+            "True True False False",
+            "True False True",
+            "False True True",
+        ]
+
+
+class TestGoMembershipProgram:
+    """Test programs using in and not in print what CPython prints."""
+
+    def test_membership(self, go_run_python):
+        """Test membership in lists, strings, ranges, dicts, sets, tuples and from methods."""
+        python_code = """
+def main() -> None:
+    nums = [1, 2, 3]
+    words = ["a", "b"]
+    text = "hello"
+    fl = [1.5, 2.0]
+    print(2 in nums, 5 not in nums, "b" in words, "ell" in text, "z" not in text, 2.0 in fl)
+    print(3 in range(10), 4 in range(1, 10, 2), -3 in range(0, -10, -3), 10 in range(10))
+    r = range(2, 20, 3)
+    print(5 in r, 6 in r, 2.0 in nums, True in nums)
+    d = {"a": 1}
+    s = {1, 2}
+    print("a" in d, "b" not in d, 1 in s, 3 not in s)
+    t = (1, 2)
+    print(1 in t, (1, 2) in [(1, 2)], [1] in [[1], [2]])
+    c = 0
+    for i in range(3):
+        if i in nums:
+            c += 1
+    print(c)
+    more()
+
+
+class Bag:
+    def __init__(self) -> None:
+        self.items: list[int] = [1, 2]
+
+    def has(self, x: int) -> bool:
+        return x in self.items
+
+    def lacks(self, x: int) -> bool:
+        return x not in [7]
+
+
+def more() -> None:
+    bag = Bag()
+    print(bag.has(2), bag.has(7), bag.lacks(7), 2.0 in range(3), 2.5 in range(3), True in range(2), "a" in range(3))
+    print(9 in range(10, 0, -1), 0 in range(10, 0, -1), 4 in range(0, 10, 3), 6 in range(0, 10, 3))
+    flags = [True, False]
+    print(False in flags, "" in "abc", "abc" in ["abc"])
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
@@ -0,0 +1,198 @@
+"""Tests for the Go backend's module layout: go.mod, the runtime copy and package declarations."""
+
+import shutil
+import subprocess
+import tempfile
+from pathlib import Path
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.builder import GoBuilder
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.backends.go.emitter import GoEmitter
+from mgen.backends.preferences import GoPreferences
+from mgen.cli.main import MGenCLI
+from mgen.pipeline import MGenPipeline, PipelineConfig
+
+PROGRAM = """
+def area(w: int, h: int) -> int:
+    return w * h
+
+
+def main() -> None:
+    print(area(3, 4))
+
+
+main()
+"""
+
+LIBRARY = """
+def area(w: int, h: int) -> int:
+    return w * h
+"""
+
+GUARDED = """
+def area(w: int, h: int) -> int:
+    return w * h
+
+
+def main() -> int:
+    print("main", area(2, 5))
+    return 0
+
+
+if __name__ == "__main__":
+    total = 0
+    sizes = [1, 2, 3]
+    for size in sizes:
+        total += area(size, size)
+    print("total", total)
+    main()
+"""
+
+
+def go_preferences(**values: object) -> GoPreferences:
+    """Return Go preferences with values set."""
+    preferences = GoPreferences()
+    for name, value in values.items():
+        preferences.set(name, value)
+    return preferences
+
+
+def convert(python_code: str, output_dir: Path, preferences: GoPreferences) -> list[str]:
+    """Convert python_code as app.py into output_dir with the pipeline, returning the files it wrote."""
+    input_file = output_dir.parent / "app.py"
+    input_file.write_text(python_code)
+    config = PipelineConfig(target_language="go", backend_preferences=preferences)
+    result = MGenPipeline(config).convert(input_file, output_dir)
+    assert result.success, result.errors
+    return result.generated_files
+
+
+class TestGoModuleFiles:
+    """Test the files and declarations the module preferences control."""
+
+    def test_default_module(self):
+        """Test a program is package main importing the runtime from the default module."""
+        go_code = MGenPythonToGoConverter().convert_code(LIBRARY)
+
+        assert go_code.startswith('package main\n\nimport "mgenproject/mgen"\n')
+        assert "func main() {" in go_code
+        assert GoBuilder().generate_build_file(["app.go"], "app") == "module mgenproject\n\ngo 1.21\n"
+
+    def test_module_path_and_go_version(self):
+        """Test module_path is the runtime's import prefix and go.mod's module, and go_version its go directive."""
+        preferences = go_preferences(module_path="example.com/shapes", go_version="1.22")
+        go_code = MGenPythonToGoConverter(preferences).convert_code(PROGRAM)
+
+        assert 'import "example.com/shapes/mgen"' in go_code
+        assert GoBuilder(preferences).generate_build_file(["app.go"], "app") == "module example.com/shapes\n\ngo 1.22\n"
+
+    def test_library_package(self):
+        """Test a package_name other than main declares that package without a placeholder main."""
+        go_code = MGenPythonToGoConverter(go_preferences(package_name="shapes")).convert_code(LIBRARY)
+
+        assert go_code.startswith("package shapes\n")
+        assert "func main()" not in go_code
+
+    def test_guarded_block_is_main(self):
+        """Test a program's main function runs its __main__ block, which calls main() renamed pyMain."""
+        go_code = MGenPythonToGoConverter().convert_code(GUARDED)
+
+        assert "func pyMain() int {" in go_code
+        assert "func main() {\n    var total int = 0\n" in go_code
+        assert '    mgen.Print("total", total)\n    pyMain()\n}' in go_code
+
+    def test_guard_calling_main(self):
+        """Test a __main__ block that only calls main() leaves main() the entry point."""
+        python_code = LIBRARY + (
+            '\n\ndef main() -> None:\n    print(area(1, 2))\n\n\nif __name__ == "__main__":\n    main()\n'
+        )
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+
+        assert "func main() {\n    mgen.Print(area(1, 2))\n}" in go_code
+        assert go_code.count("func main()") == 1 and "pyMain" not in go_code
+
+    def test_library_exports(self):
+        """Test a library package exports its functions and ignores the __main__ block."""
+        go_code = GoEmitter(go_preferences(package_name="shapes")).emit_module(GUARDED, None)
+
+        assert go_code.startswith("package shapes\n")
+        assert "func Area(w int, h int) int {" in go_code
+        assert "func Main() int {" in go_code
+        assert "total" not in go_code
+
+    def test_cli_lib(self):
+        """Test convert --lib names the package after the file and gives it no main function."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            input_file = Path(tmpdir) / "shapes.py"
+            input_file.write_text(LIBRARY)
+            build_dir = Path(tmpdir) / "build"
+
+            assert MGenCLI().run(["--build-dir", str(build_dir), "convert", "-t", "go", "--lib", str(input_file)]) == 0
+            go_code = (build_dir / "src" / "shapes.go").read_text()
+            assert go_code.startswith("package shapes\n")
+            assert "func Area(" in go_code and "func main()" not in go_code
+            assert MGenCLI().run(["--build-dir", str(build_dir), "convert", "-t", "c", "--lib", str(input_file)]) == 1
+
+    def test_guarded_end_to_end(self, go_run_python):
+        """Test the program prints what running the module as a script does."""
+        assert go_run_python(GUARDED) == python_output(GUARDED)
+
+    def test_invalid_preferences(self):
+        """Test module paths and package names Go would reject are reported when the converter is made."""
+        with pytest.raises(ValueError, match="module_path"):
+            MGenPythonToGoConverter(go_preferences(module_path="bad path/"))
+        with pytest.raises(ValueError, match="package_name"):
+            MGenPythonToGoConverter(go_preferences(package_name="my-lib"))
+
+    def test_support_files(self):
+        """Test conversion writes go.mod and the runtime, without its tests, beside the source."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            output_dir = Path(tmpdir) / "src"
+            output_dir.mkdir()
+            (output_dir / "mgen").mkdir()
+            (output_dir / "mgen" / "mgen_go_removed.go").write_text("package mgen\n")
+            files = convert(PROGRAM, output_dir, GoPreferences())
+            runtime = sorted(path.name for path in (output_dir / "mgen").iterdir())
+
+            assert files[:2] == [str(output_dir / "app.go"), str(output_dir / "go.mod")]
+            assert (output_dir / "go.mod").read_text() == "module mgenproject\n\ngo 1.21\n"
+            assert "mgen_go_runtime.go" in runtime
+            assert "mgen_go_removed.go" not in runtime
+            assert not any(name.endswith("_test.go") for name in runtime)
+
+
+@pytest.mark.skipif(shutil.which("go") is None, reason="Go toolchain not available")
+class TestGoModuleBuild:
+    """Test converted output builds with go build ./... and nothing else."""
+
+    def test_program(self):
+        """Test a converted program builds and runs."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            output_dir = Path(tmpdir) / "src"
+            convert(PROGRAM, output_dir, go_preferences(module_path="example.com/shapes"))
+            build = subprocess.run(["go", "build", "./..."], capture_output=True, text=True, cwd=output_dir)
+            assert build.returncode == 0, build.stderr
+            run = subprocess.run(["go", "run", "."], capture_output=True, text=True, cwd=output_dir)
+
+            assert run.stdout == "12\n"
+
+    def test_guarded_program(self):
+        """Test the pipeline converts a __main__ block assigning variables to a program that runs it."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            output_dir = Path(tmpdir) / "src"
+            convert(GUARDED, output_dir, GoPreferences())
+            run = subprocess.run(["go", "run", "."], capture_output=True, text=True, cwd=output_dir)
+
+            assert run.stdout == "total 14\nmain 10\n", run.stderr
+
+    def test_library(self):
+        """Test a converted library package builds."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            output_dir = Path(tmpdir) / "src"
+            convert(LIBRARY, output_dir, go_preferences(package_name="shapes"))
+            build = subprocess.run(["go", "build", "./..."], capture_output=True, text=True, cwd=output_dir)
+
+            assert build.returncode == 0, build.stderr
@@ -0,0 +1,135 @@
+"""Tests for Go backend int()/float() conversions."""
+
+import json
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+# (text, base) pairs for int(); base None means int(text)
+INT_CASES = [
+    (" 42 ", None),
+    ("-1_000", None),
+    ("+7\n", None),
+    ("0x1f", None),
+    ("1__0", None),
+    ("_1", None),
+    ("1_", None),
+    ("", None),
+    ("- 1", None),
+    ("١٢", None),
+    ("3.0", None),
+    ("ff", 16),
+    ("0x_1f", 16),
+    ("0x1f", 0),
+    ("0b1_01", 0),
+    ("010", 0),
+    ("0_0", 0),
+    ("z", 36),
+    ("12", 2),
+    ("9", 1),
+]
+
+FLOAT_CASES = [
+    "1e5",
+    " -inf ",
+    "nan",
+    "+Infinity",
+    "1_0.5",
+    "1_.5",
+    "0x1p3",
+    ".",
+    "1.",
+    ".5",
+    "1e400",
+    "1e",
+    "-1.5E-3",
+    "infinit",
+    "١.٥",
+]
+
+
+def python_result(fn):
+    """Return repr(fn()) or the raised error as the Go runtime formats it."""
+    try:
+        return repr(fn())
+    except (ValueError, TypeError, OverflowError) as e:
+        return f"{type(e).__name__}: {e}"
+
+
+def go_check(call: str) -> str:
+    """Return a statement printing the call's repr or the error it raises."""
+    return f"    check(func() interface{{}} {{ return {call} }})"
+
+
+class TestGoNumberConversion:
+    """Test int()/float() lower to exact Go conversions or the parsing runtime."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_int_float_calls(self):
+        """Test string arguments parse at runtime while numeric ones stay Go conversions."""
+        python_code = """
+def parse(s: str, n: int, x: float) -> float:
+    a = int(s)
+    b = int(s, 16)
+    c = int(s, base=0)
+    d = int(x) + int(n)
+    return float(s) + float(n) + float(x) + a + b + c + d
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "a := mgen.ToInt(s)" in go_code
+        assert "b := mgen.ToIntBase(s, 16)" in go_code
+        assert "c := mgen.ToIntBase(s, 0)" in go_code
+        assert "d := (mgen.ToInt(x) + n)" in go_code
+        assert "mgen.ToFloat(s) + float64(n)) + x)" in go_code
+
+
+class TestGoNumberParsing:
+    """Test the runtime accepts exactly the strings CPython accepts."""
+
+    def test_int_matches_python(self, go_run):
+        """Test int() whitespace, signs, separators, prefixes and bases."""
+        calls = [
+            f"mgen.ToInt({json.dumps(text, ensure_ascii=False)})"
+            if base is None
+            else f"mgen.ToIntBase({json.dumps(text, ensure_ascii=False)}, {base})"
+            for text, base in INT_CASES
+        ]
+        output = go_run("\n".join(go_check(call) for call in calls))
+
+        expected = [
+            python_result(lambda t=text, b=base: int(t) if b is None else int(t, b)) for text, base in INT_CASES
+        ]
+        assert output.splitlines() == expected
+
+    def test_float_matches_python(self, go_run):
+        """Test float() exponents, inf/nan spellings and separators."""
+        calls = [f"mgen.ToFloat({json.dumps(text, ensure_ascii=False)})" for text in FLOAT_CASES]
+        output = go_run("\n".join(go_check(call) for call in calls))
+
+        assert output.splitlines() == [python_result(lambda t=text: float(t)) for text in FLOAT_CASES]
+
+    def test_numeric_arguments(self, go_run):
+        """Test int() truncation and the errors for inf, nan and non-numbers."""
+        calls = [
+            "mgen.ToInt(-3.9)",
+            "mgen.ToInt(true)",
+            "mgen.ToFloat(false)",
+            "mgen.ToInt(mgen.ToFloat(\"inf\"))",
+            "mgen.ToInt(mgen.ToFloat(\"nan\"))",
+            "mgen.ToInt([]int{1})",
+            "mgen.ToFloat(nil)",
+        ]
+        output = go_run("\n".join(go_check(call) for call in calls))
+
+        assert output.splitlines() == [
+            python_result(lambda: int(-3.9)),
+            python_result(lambda: int(True)),
+            python_result(lambda: float(False)),
+            python_result(lambda: int(float("inf"))),
+            python_result(lambda: int(float("nan"))),
+            python_result(lambda: int([1])),
+            python_result(lambda: float(None)),
+        ]
@@ -1,9 +1,12 @@
 """Tests for Go backend object-oriented programming support."""
 
+import re
+
 import pytest
+from conftest import python_output
 
 from mgen.backends.go.converter import MGenPythonToGoConverter
-from mgen.backends.errors import UnsupportedFeatureError
+from mgen.backends.errors import TypeMappingError, UnsupportedFeatureError
 
 
 class TestGoOOPBasics:
@@ -177,7 +180,7 @@ def create_account() -> BankAccount:
 """
         go_code = self.converter.convert_code(python_code)
 
-        assert "return NewBankAccount(1000)" in go_code
+        assert "return NewBankAccount(1000.0)" in go_code
         assert "func NewBankAccount(balance float64) BankAccount" in go_code
 
     def test_method_calls_on_objects(self):
@@ -220,4 +223,798 @@ class Accumulator:
         go_code = self.converter.convert_code(python_code)
 
         assert "obj.Total += value" in go_code
-        assert "obj.Total *= factor" in go_code
\ No newline at end of file
+        assert "obj.Total *= factor" in go_code
+
+class TestGoConstructorArguments:
+    """Test constructors honor __init__ defaults and keyword arguments."""
+
+    POINT = """
+from typing import Optional
+
+class Point:
+    def __init__(self, x: int, y: int = 0, *, label: str = "p", tag: Optional[int] = None):
+        self.x = x
+        self.y = y
+        self.label = label
+        self.tag = tag
+"""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_keyword_only_options_struct(self):
+        """Test keyword-only parameters move into a {Class}Options struct."""
+        go_code = self.converter.convert_code(self.POINT)
+
+        assert "type PointOptions struct {\n    Label string\n    Tag *int\n}" in go_code
+        assert "func NewPoint(x int, y int, opts PointOptions) Point {" in go_code
+        assert "    label, tag := opts.Label, opts.Tag" in go_code
+        # Fields take the types of the parameters they are assigned from
+        assert "    X int\n    Y int\n    Label string\n    Tag *int" in go_code
+
+    def test_default_and_keyword_instantiation(self):
+        """Test call sites bind keywords and fill in defaults."""
+        python_code = (
+            self.POINT
+            + """
+def make() -> None:
+    a = Point(1)
+    b = Point(y=2, x=1)
+    c = Point(1, 2, tag=7, label="c")
+"""
+        )
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'a := NewPoint(1, 0, PointOptions{Label: "p", Tag: nil})' in go_code
+        assert 'b := NewPoint(1, 2, PointOptions{Label: "p", Tag: nil})' in go_code
+        assert 'c := NewPoint(1, 2, PointOptions{Label: "c", Tag: mgen.Some[int](7)})' in go_code
+
+    def test_unannotated_default_parameter_type(self):
+        """Test an unannotated parameter takes its default's type."""
+        python_code = """
+class Counter:
+    def __init__(self, start=10, step=1):
+        self.value = start
+        self.step = step
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func NewCounter(start int, step int) Counter {" in go_code
+
+    @pytest.mark.parametrize(
+        "call, message",
+        [
+            ("Point()", "Point.__init__() missing 1 required positional argument: 'x'"),
+            ("Point(1, 2, 3)", "Point.__init__() takes from 2 to 3 positional arguments but 4 were given"),
+            ("Point(1, x=2)", "Point.__init__() got multiple values for argument 'x'"),
+            ("Point(1, z=2)", "Point.__init__() got an unexpected keyword argument 'z'"),
+        ],
+    )
+    def test_invalid_calls_raise_type_error(self, call, message):
+        """Test calls Python rejects fail with Python's TypeError message."""
+        python_code = self.POINT + f"\ndef make() -> None:\n    p = {call}\n"
+
+        with pytest.raises(TypeMappingError, match=re.escape(f"TypeError: {message}")):
+            self.converter.convert_code(python_code)
+
+    def test_constructor_end_to_end(self, go_run_python):
+        """Test default and keyword instantiation compile and run."""
+        python_code = (
+            self.POINT
+            + """
+    def describe(self) -> str:
+        return self.label + ":" + str(self.x + self.y)
+
+def main() -> None:
+    p = Point(1)
+    q = Point(y=5, x=2, label="q")
+    print(p.describe(), q.describe())
+"""
+        )
+        assert go_run_python(python_code).strip() == "p:1 q:7"
+
+
+class TestGoProperties:
+    """Test @property getters/setters become methods that attribute access routes through."""
+
+    RECT = """
+class Rect:
+    def __init__(self, width: float, height: float):
+        self.width = width
+        self.height = height
+
+    @property
+    def area(self) -> float:
+        return self._width * self.height
+
+    @property
+    def width(self) -> float:
+        return self._width
+
+    @width.setter
+    def width(self, value: float) -> None:
+        if value < 0:
+            raise ValueError("width must be non-negative")
+        self._width = value
+
+    def grow(self, amount: float) -> None:
+        self.width += amount
+"""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_property_methods_and_routing(self):
+        """Test properties become Get/Set methods and attribute access calls them."""
+        python_code = (
+            self.RECT
+            + """
+def resize() -> float:
+    r = Rect(2.0, 3.0)
+    r.width = 4.0
+    return r.area
+"""
+        )
+        go_code = self.converter.convert_code(python_code)
+
+        # The setter's backing field is a struct field; the properties are not
+        assert "type Rect struct {\n    Height float64\n    Width float64\n}" in go_code
+        assert "func (obj *Rect) GetArea() float64 {" in go_code
+        assert "func (obj *Rect) SetWidth(value float64) {" in go_code
+        assert '    panic(mgen.NewPyError("ValueError", "%s", "width must be non-negative"))' in go_code
+        # __init__ assigns through the setter, so validation applies to construction too
+        assert "    obj.SetWidth(width)" in go_code
+        assert "    obj.SetWidth((obj.GetWidth() + amount))" in go_code
+        assert "    r.SetWidth(4.0)\n    return r.GetArea()" in go_code
+        assert 'mgen.RegisterProperties(&Rect{}, map[string]mgen.Property{' in go_code
+
+    def test_read_only_property_assignment(self):
+        """Test assigning to a property without a setter raises AttributeError."""
+        python_code = self.RECT + "\ndef bad() -> None:\n    r = Rect(2.0, 3.0)\n    r.area = 1.0\n"
+
+        with pytest.raises(TypeMappingError, match="AttributeError: property 'area' of 'Rect' object has no setter"):
+            self.converter.convert_code(python_code)
+
+    def test_properties_end_to_end(self, go_run_python):
+        """Test computed and validated properties, including getattr/setattr routing."""
+        python_code = (
+            self.RECT
+            + """
+def main() -> None:
+    r = Rect(2.0, 3.0)
+    print(r.area, r.width)
+    r.grow(1.0)
+    setattr(r, "height", 2.0)
+    print(getattr(r, "area"), r.height)
+"""
+        )
+        assert go_run_python(python_code).splitlines() == ["6.0 2.0", "6.0 2.0"]
+
+    def test_setter_validation_raises(self, go_run_python):
+        """Test the setter's ValueError propagates from an assignment."""
+        python_code = self.RECT + "\ndef main() -> None:\n    r = Rect(2.0, 3.0)\n    r.width = -1.0\n"
+
+        with pytest.raises(AssertionError, match="ValueError: width must be non-negative"):
+            go_run_python(python_code)
+
+    def test_property_attr_runtime(self, go_run):
+        """Test SetAttr rejects read-only properties and DelAttr rejects properties."""
+        output = go_run(
+            """
+    type Box struct{ Size int }
+    box := &Box{Size: 2}
+    mgen.RegisterProperties(box, map[string]mgen.Property{
+        "double": {Get: func(obj interface{}) interface{} { return obj.(*Box).Size * 2 }},
+    })
+    mgen.Print(mgen.GetAttr(box, "double"), mgen.GetAttr(box, "size"))
+    mgen.SetAttr(box, "size", 5)
+    mgen.Print(mgen.GetAttr(box, "double"))
+    check(func() { mgen.SetAttr(box, "double", 1) })
+    check(func() { mgen.DelAttr(box, "double") })
+    check(func() { mgen.SetAttr(box, "size", "big") })
+"""
+        )
+        assert output.splitlines() == [
+            "4 2",
+            "10",
+            "AttributeError: property 'double' of 'Box' object has no setter",
+            "AttributeError: property 'double' of 'Box' object has no deleter",
+            "TypeError: cannot assign 'str' to attribute 'size'",
+        ]
+
+
+class TestGoClassFunctions:
+    """Test @classmethod/@staticmethod become package-level functions."""
+
+    DATE = """
+class Date:
+    def __init__(self, year: int, month: int, day: int):
+        self.year = year
+        self.month = month
+        self.day = day
+
+    @classmethod
+    def from_string(cls, text: str) -> "Date":
+        parts = text.split("-")
+        return cls(int(parts[0]), int(parts[1]), int(parts[2]))
+
+    @classmethod
+    def epoch(cls) -> Date:
+        return cls.from_string("1970-01-01")
+
+    @staticmethod
+    def days_in_year(leap: bool) -> int:
+        if leap:
+            return 366
+        return 365
+
+    def describe(self) -> str:
+        return f"{self.year}-{self.month}-{self.day}"
+
+    def length(self) -> int:
+        return self.days_in_year(self.year % 4 == 0)
+"""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_class_function_codegen(self):
+        """Test classmethods take cls mgen.ClassRef and staticmethods take no receiver."""
+        python_code = (
+            self.DATE
+            + """
+def parse(text: str) -> str:
+    d = Date.from_string(text)
+    return d.describe()
+"""
+        )
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'var DateClass = mgen.ClassRef{Name: "Date"}' in go_code
+        assert "func DateFromString(cls mgen.ClassRef, text string) Date {" in go_code
+        # cls(...) constructs the class; cls.f(...) passes cls on
+        assert "return NewDate(mgen.ToInt(parts[0]), mgen.ToInt(parts[1]), mgen.ToInt(parts[2]))" in go_code
+        assert "func DateEpoch(cls mgen.ClassRef) Date {\n    return DateFromString(cls, \"1970-01-01\")" in go_code
+        assert "func DateDaysInYear(leap bool) int {" in go_code
+        assert "return DateDaysInYear((mgen.PyMod(obj.Year, 4) == 0))" in go_code
+        assert "d := DateFromString(DateClass, text)" in go_code
+
+    def test_alternative_constructor_end_to_end(self, go_run_python):
+        """Test from_string called through the class, an instance and cls."""
+        python_code = (
+            self.DATE
+            + """
+def main() -> None:
+    d = Date.from_string("2024-02-29")
+    print(d.describe(), d.length())
+    e = d.from_string("2023-05-01")
+    print(e.describe(), e.length(), Date.days_in_year(False))
+    z = Date.epoch()
+    print(z.describe(), z.days_in_year(True))
+"""
+        )
+        assert go_run_python(python_code).splitlines() == ["2024-2-29 366", "2023-5-1 365 365", "1970-1-1 366"]
+
+    def test_class_ref_runtime(self, go_run):
+        """Test the class descriptor prints like a Python class object."""
+        output = go_run(
+            """
+    cls := mgen.ClassRef{Name: "Date"}
+    mgen.Print(cls, cls.Name)
+"""
+        )
+        assert output == "<class '__main__.Date'> Date\n"
+
+
+class TestGoInheritance:
+    """Test single inheritance via struct embedding and super() calls."""
+
+    ANIMALS = """
+class Animal:
+    def __init__(self, name: str, sound: str = "..."):
+        self.name = name
+        self.sound = sound
+
+    def speak(self) -> str:
+        return f"{self.name} says {self.sound}"
+
+    def describe(self) -> str:
+        return f"<{self.speak()}>"
+
+    @classmethod
+    def create(cls, name: str) -> "Animal":
+        return cls(name)
+
+
+class Dog(Animal):
+    def __init__(self, name: str, breed: str):
+        super().__init__(name, "woof")
+        self.breed = breed
+
+    def speak(self) -> str:
+        return super().speak() + "!"
+
+
+class Puppy(Dog):
+    def __init__(self, name: str):
+        super().__init__(name, "mutt")
+        self.age = 1
+
+    def speak(self) -> str:
+        return super().speak() + " (tiny)"
+
+
+class Cat(Animal):
+    pass
+"""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_embedding_and_super_codegen(self):
+        """Test subclasses embed their base and super() targets the embedded struct."""
+        go_code = self.converter.convert_code(self.ANIMALS)
+
+        # Inherited fields are promoted rather than redeclared
+        assert "type Dog struct {\n    Animal\n    Breed string\n}" in go_code
+        assert "type Puppy struct {\n    Dog\n    Age int\n}" in go_code
+        assert '    obj.Animal = NewAnimal(name, "woof")\n    obj.Breed = breed' in go_code
+        assert '    obj.Dog = NewDog(name, "mutt")' in go_code
+        assert 'return (obj.Animal.Speak() + "!")' in go_code
+        assert 'return (obj.Dog.Speak() + " (tiny)")' in go_code
+        # describe() calls the overridden speak(), so each subclass gets its own copy
+        assert "func (obj *Dog) Describe() string {" in go_code
+        assert "func (obj *Puppy) Describe() string {" in go_code
+        # A class without __init__ forwards to its base's constructor
+        assert "func NewCat(name string, sound string) Cat {\n    obj := Cat{}\n    obj.Animal = NewAnimal(name, sound)" in go_code
+        assert 'func CatCreate(cls mgen.ClassRef, name string) Cat {\n    return NewCat(name, "...")' in go_code
+
+    def test_hierarchy_end_to_end(self, go_run_python):
+        """Test overrides, super() chains and inherited methods across two levels."""
+        python_code = (
+            self.ANIMALS
+            + """
+def main() -> None:
+    a = Animal("generic")
+    d = Dog("rex", "lab")
+    p = Puppy("bit")
+    c = Cat("tom", "meow")
+    print(a.speak(), d.speak(), p.speak(), c.speak(), sep=" | ")
+    print(d.describe(), p.describe(), c.describe())
+    print(d.breed, p.name, p.breed, p.age)
+    f = Cat.create("fido")
+    print(f.speak())
+"""
+        )
+        assert go_run_python(python_code).splitlines() == [
+            "generic says ... | rex says woof! | bit says woof! (tiny) | tom says meow",
+            "<rex says woof!> <bit says woof! (tiny)> <tom says meow>",
+            "lab bit mutt 1",
+            "fido says ...",
+        ]
+
+    def test_inherited_classmethod_binding_error(self):
+        """Test an inherited classmethod whose cls(...) call Python would reject."""
+        python_code = self.ANIMALS + '\ndef make() -> None:\n    d = Dog.create("fido")\n'
+
+        with pytest.raises(TypeMappingError, match=re.escape("Dog.__init__() missing 1 required positional argument: 'breed'")):
+            self.converter.convert_code(python_code)
+
+    def test_super_without_base_method(self):
+        """Test super().method() raises AttributeError when no base defines it."""
+        python_code = """
+class Base:
+    def __init__(self, x: int):
+        self.x = x
+
+
+class Child(Base):
+    def show(self) -> int:
+        return super().show()
+"""
+        with pytest.raises(TypeMappingError, match="AttributeError: 'super' object has no attribute 'show'"):
+            self.converter.convert_code(python_code)
+
+    def test_multiple_inheritance_unsupported(self):
+        """Test multiple inheritance is rejected rather than approximated."""
+        python_code = """
+class A:
+    def __init__(self):
+        self.a = 1
+
+
+class B:
+    def __init__(self):
+        self.b = 2
+
+
+class C(A, B):
+    pass
+"""
+        with pytest.raises(TypeMappingError, match=r"Multiple inheritance is not supported: class C\(A, B\)"):
+            self.converter.convert_code(python_code)
+
+
+class TestGoClassInterfaces:
+    """Test interfaces generated for subclassed classes, for dynamic dispatch."""
+
+    SHAPES = """
+class Shape:
+    def __init__(self, name: str):
+        self.name = name
+
+    def area(self) -> float:
+        return 0.0
+
+    def label(self) -> str:
+        return self.name + ": " + str(self.area())
+
+
+class Square(Shape):
+    def __init__(self, side: float):
+        super().__init__("square")
+        self.side = side
+
+    def area(self) -> float:
+        return self.side * self.side
+
+    def label(self) -> str:
+        return "[" + super().label() + "]"
+
+
+class Circle(Shape):
+    def __init__(self, r: float):
+        super().__init__("circle")
+        self.r = r
+
+    def area(self) -> float:
+        return 3.0 * self.r * self.r
+"""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_interface_codegen(self):
+        """Test the base class gets an interface its subclasses' pointers implement."""
+        python_code = (
+            self.SHAPES
+            + """
+def total(shapes: list[Shape]) -> float:
+    result = 0.0
+    for s in shapes:
+        result += s.area()
+    return result
+
+
+def describe(s: Shape) -> str:
+    return s.name
+"""
+        )
+        go_code = self.converter.convert_code(python_code)
+
+        assert "type ShapeInterface interface {\n    AsShape() *Shape\n    Area() float64\n    Label() string\n}" in go_code
+        assert "func (obj *Shape) AsShape() *Shape {\n    return obj\n}" in go_code
+        assert "func total(shapes []ShapeInterface) float64 {" in go_code
+        assert "return s.AsShape().Name" in go_code
+        # Leaf classes are used as plain structs
+        assert "SquareInterface" not in go_code
+        # super().label() reaches Square.area through a copy of Shape.label with a Square receiver
+        assert 'return (("[" + obj.superShapeLabel()) + "]")' in go_code
+        assert "func (obj *Square) superShapeLabel() string {" in go_code
+
+    def test_dispatch_end_to_end(self, go_run_python):
+        """Test base-typed values, lists and parameters dispatch to overrides, and isinstance."""
+        python_code = (
+            self.SHAPES
+            + """
+def largest(shapes: list[Shape]) -> Shape:
+    best = shapes[0]
+    for s in shapes:
+        if s.area() > best.area():
+            best = s
+    return best
+
+
+def main() -> None:
+    shapes: list[Shape] = [Shape("dot"), Square(2.0)]
+    shapes.append(Circle(1.0))
+    for s in shapes:
+        print(s.label(), isinstance(s, Square), isinstance(s, Shape))
+    print(largest(shapes).name)
+    sq = Square(1.5)
+    print(sq.label(), isinstance(sq, (Circle, Square)))
+    first: Shape = Square(3.0)
+    print(first.area(), first.name)
+"""
+        )
+        assert go_run_python(python_code).splitlines() == [
+            "dot: 0.0 False True",
+            "[square: 4.0] True True",
+            "circle: 3.0 False True",
+            "square",
+            "[square: 2.25] True",
+            "9.0 square",
+        ]
+
+    def test_isinstance_on_method_parameter(self, go_run_python):
+        """Test isinstance() on a class-typed method parameter tests its address, as in functions."""
+        python_code = """
+class Point:
+    def __init__(self, x: int, y: int):
+        self.x = x
+        self.y = y
+
+    def same_x(self, other: Point) -> bool:
+        if isinstance(other, Point):
+            return self.x == other.x
+        return False
+
+
+def main() -> None:
+    p = Point(1, 2)
+    print(p.same_x(Point(1, 3)), p.same_x(Point(2, 2)))
+"""
+        assert "mgen.InstanceOf[*Point](&other)" in self.converter.convert_code(python_code)
+        assert go_run_python(python_code).strip() == "True False"
+
+    def test_override_signature_mismatch(self):
+        """Test an override Go could not put behind the base's interface is rejected."""
+        python_code = """
+class Base:
+    def size(self) -> int:
+        return 1
+
+
+class Child(Base):
+    def size(self, scale: int) -> int:
+        return scale
+"""
+        with pytest.raises(TypeMappingError, match=r"Child.size cannot override Base.size with another signature"):
+            self.converter.convert_code(python_code)
+
+
+class TestGoOperatorMethods:
+    """Test operators dispatching to the dunder methods of user classes."""
+
+    MONEY = """
+class Money:
+    def __init__(self, cents: int):
+        self.cents = cents
+
+    def __add__(self, other: "Money") -> "Money":
+        return Money(self.cents + other.cents)
+
+    def __sub__(self, other: "Money") -> "Money":
+        return Money(self.cents - other.cents)
+
+    def __mul__(self, factor: int) -> "Money":
+        return Money(self.cents * factor)
+
+    def __lt__(self, other: "Money") -> bool:
+        return self.cents < other.cents
+
+
+class Bag:
+    def __init__(self, items: list[int]):
+        self.items = items
+
+    def __len__(self) -> int:
+        return len(self.items)
+
+    def __getitem__(self, i: int) -> int:
+        return self.items[i]
+
+    def __setitem__(self, i: int, v: int) -> None:
+        self.items[i] = v
+
+    def __contains__(self, v: int) -> bool:
+        return v in self.items
+
+    def first_two(self) -> int:
+        return self[0] + self[1]
+"""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_operator_codegen(self):
+        """Test dunders become Py-prefixed methods that operators call."""
+        python_code = (
+            self.MONEY
+            + """
+def main() -> None:
+    a = Money(150)
+    total = (a + Money(75)) * 2
+    bag = Bag([3, 9])
+    bag[1] = 10
+    print(total.cents, a < total, a > total, len(bag), bag[0], 10 in bag, 9 not in bag)
+"""
+        )
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func (obj *Money) PyAdd(other Money) Money {" in go_code
+        assert "func (obj *Bag) PySetItem(i int, v int) {\n    obj.Items[i] = v\n}" in go_code
+        assert "return (obj.PyGetItem(0) + obj.PyGetItem(1))" in go_code
+        # Results of calls are not addressable, so they are copied to call a pointer method
+        assert "var total Money = mgen.Ref(a.PyAdd(NewMoney(75))).PyMul(2)" in go_code
+        assert "bag.PySetItem(1, 10)" in go_code
+        # a > b falls back to the reflected b.__lt__(a)
+        assert "a.PyLt(total), total.PyLt(a), bag.PyLen(), bag.PyGetItem(0), bag.PyContains(10), !bag.PyContains(9)" in go_code
+
+    def test_operators_end_to_end(self, go_run_python):
+        """Test operator results, including len() of a class instance held as interface{}."""
+        python_code = (
+            self.MONEY
+            + """
+def size(x) -> int:
+    return len(x)
+
+
+def main() -> None:
+    a = Money(150)
+    b = Money(75)
+    total = (a + b) * 2 - Money(50)
+    print(total.cents, a < b, a > b, Money(1) + Money(2) < a)
+    bag = Bag([3, 9])
+    bag[1] = 10
+    print(len(bag), bag[0], bag[1], 10 in bag, 9 not in bag, bag.first_two(), size(bag))
+"""
+        )
+        assert go_run_python(python_code).splitlines() == ["400 False True True", "2 3 10 True True 13 2"]
+
+    def test_operator_arity_error(self):
+        """Test a dunder taking the wrong number of operands is rejected like Python's TypeError."""
+        python_code = """
+class Odd:
+    def __add__(self) -> int:
+        return 1
+
+
+def f(a: Odd, b: Odd) -> int:
+    return a + b
+"""
+        message = "TypeError: __add__() takes 1 positional argument but 2 were given"
+        with pytest.raises(TypeMappingError, match=re.escape(message)):
+            self.converter.convert_code(python_code)
+
+
+class TestGoClassKeys:
+    """Test classes defining __eq__ and __hash__ as dict keys and set members."""
+
+    TAG = """
+class Tag:
+    def __init__(self, name: str, hits: int):
+        self.name = name
+        self.hits = hits
+
+    def __eq__(self, other: "Tag") -> bool:
+        return self.name.lower() == other.name.lower()
+
+    def __hash__(self) -> int:
+        return hash((len(self.name), self.name.lower()))
+
+
+class Loose:
+    def __init__(self, v: int):
+        self.v = v
+
+    def __eq__(self, other: object) -> bool:
+        return True
+"""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_class_key_codegen(self):
+        """Test __eq__ and __hash__ implement mgen.PyHashable, and == and != call __eq__."""
+        python_code = (
+            self.TAG
+            + """
+def same(a: Tag, b: Tag) -> bool:
+    return a == b
+
+
+def different(a: Tag, b: Tag) -> bool:
+    return a != b
+"""
+        )
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func (obj *Tag) PyEq(other Tag) bool {" in go_code
+        assert (
+            "func (obj *Tag) Hash() int {\n    return mgen.HashValue(mgen.PyTuple{mgen.LenString(obj.Name),"
+        ) in go_code
+        assert (
+            "func (obj *Tag) Equals(other interface{}) bool {\n"
+            "    if o, ok := mgen.AsInstance[Tag](other); ok {\n"
+            "        return obj.PyEq(o)\n"
+            "    }\n"
+            "    return false\n"
+            "}"
+        ) in go_code
+        assert "func (obj *Loose) Equals(other interface{}) bool {\n    return obj.PyEq(other)\n}" in go_code
+        assert "return a.PyEq(b)" in go_code
+        assert "return !a.PyEq(b)" in go_code
+
+    def test_class_keys_end_to_end(self, go_run_python):
+        """Test keys equal under __eq__ find one entry, unlike Go's field-by-field struct equality."""
+        python_code = (
+            self.TAG
+            + """
+def main() -> None:
+    tags: dict[Tag, int] = {}
+    tags[Tag("Go", 1)] = 1
+    tags[Tag("GO", 2)] += 1
+    tags[Tag("py", 3)] = 3
+    print(len(tags), tags[Tag("go", 0)], tags[Tag("Py", 0)])
+    del tags[Tag("gO", 9)]
+    print(len(tags), Tag("py", 1) in tags, Tag("go", 1) in tags, list(tags.values()))
+    names: set[Tag] = {Tag("a", 1), Tag("A", 2), Tag("b", 3)}
+    print(len(names), Tag("a", 1) != Tag("b", 1), hash(Tag("x", 0)) == hash(Tag("X", 1)))
+    pairs = {(Tag("x", 1), 1): "one"}
+    print(pairs[(Tag("X", 5), 1)])
+    loose: set[Loose] = set()
+    try:
+        loose.add(Loose(1))
+    except TypeError as e:
+        print(e)
+"""
+        )
+        assert go_run_python(python_code).splitlines() == [
+            "2 2 3",
+            "1 True False [3]",
+            "2 True True",
+            "one",
+            "unhashable type: 'Loose'",
+        ]
+
+    def test_isinstance_guarded_eq_end_to_end(self, go_run_python):
+        """Test __eq__ guarded by isinstance(), on a class-typed and an object-typed operand, keys dicts."""
+        python_code = """
+class Vec:
+    def __init__(self, x: int, y: int):
+        self.x = x
+        self.y = y
+
+    def __eq__(self, other: "Vec") -> bool:
+        if not isinstance(other, Vec):
+            return False
+        return (self.x, self.y) == (other.x, other.y)
+
+    def __hash__(self) -> int:
+        return hash((self.x, self.y))
+
+
+class Key:
+    def __init__(self, name: str):
+        self.name = name
+
+    def __eq__(self, other: object) -> bool:
+        if not isinstance(other, Key):
+            return False
+        return self.name == other.name
+
+    def __hash__(self) -> int:
+        return hash(self.name)
+
+
+def main() -> None:
+    vecs = {Vec(1, 1): "one"}
+    print(vecs[Vec(1, 1)], Vec(1, 2) == Vec(1, 2), Vec(1, 2) == Vec(2, 1))
+    keys = {Key("a"): 1}
+    print(keys[Key("a")], Key("a") in keys, Key("b") in keys)
+
+
+main()
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func (obj *Key) PyEq(other Key) bool {" in go_code
+        assert go_run_python(python_code) == python_output(python_code)
@@ -0,0 +1,115 @@
+"""Tests for Go backend Optional[T] support."""
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+
+class TestGoOptionalConversion:
+    """Test Optional[T] code generation."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_optional_return_type(self):
+        """Test Optional[T] return values map to *T with nil for None."""
+        python_code = """
+from typing import Optional
+
+def find(xs: list[int], target: int) -> Optional[int]:
+    for i in range(len(xs)):
+        if xs[i] == target:
+            return i
+    return None
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func find(xs []int, target int) *int {" in go_code
+        assert "return mgen.Some[int](i)" in go_code
+        assert "return nil" in go_code
+
+    def test_union_none_parameter(self):
+        """Test T | None parameters and is None checks."""
+        python_code = """
+def describe(value: int | None) -> str:
+    if value is None:
+        return "missing"
+    return "found"
+
+def main() -> None:
+    print(describe(None), describe(3))
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func describe(value *int) string {" in go_code
+        assert "(value == nil)" in go_code
+        assert "describe(nil)" in go_code
+        assert "describe(mgen.Some[int](3))" in go_code
+
+    def test_optional_variable_use(self):
+        """Test optional locals are unwrapped in expressions and boxed for print."""
+        python_code = """
+from typing import Optional
+
+def main() -> None:
+    count: Optional[int] = 2
+    if count is not None:
+        print(count + 1)
+    count = None
+    print(count)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "var count *int = mgen.Some[int](2)" in go_code
+        assert "(count != nil)" in go_code
+        assert "(mgen.Unwrap(count) + 1)" in go_code
+        assert "count = nil" in go_code
+        assert "mgen.Print(mgen.NoneIfNil(count))" in go_code
+
+
+class TestGoOptionalRuntime:
+    """Test runtime Optional helpers."""
+
+    def test_optional_helpers(self, go_run):
+        """Test Some, IsNone, ValueOr, NoneIfNil and OptionalOf round trips."""
+        output = go_run(
+            """
+    var missing *int
+    present := mgen.Some(7)
+    mgen.Print(mgen.IsNone(missing), mgen.IsNone(present), mgen.IsNone(nil))
+    mgen.Print(mgen.ValueOr(missing, -1), mgen.ValueOr(present, -1))
+    mgen.Print(mgen.NoneIfNil(missing), mgen.NoneIfNil(present))
+    back := mgen.OptionalOf[int](mgen.NoneIfNil(present))
+    mgen.Print(*back, mgen.OptionalOf[int](nil) == nil)
+"""
+        )
+        assert output.splitlines() == ["True False True", "-1 7", "None 7", "7 True"]
+
+    def test_unwrap_none_raises(self, go_run):
+        """Test unwrapping None raises TypeError."""
+        output = go_run(
+            """
+    defer func() { mgen.Print(recover().(error).Error()) }()
+    var missing *string
+    mgen.Unwrap(missing)
+"""
+        )
+        assert output.strip() == "TypeError: unexpected None value (expected str)"
+
+    def test_optional_end_to_end(self, go_run_python):
+        """Test a generated program passing and returning optional values."""
+        python_code = """
+from typing import Optional
+
+def find(xs: list[int], target: int) -> Optional[int]:
+    for i in range(len(xs)):
+        if xs[i] == target:
+            return i
+    return None
+
+def main() -> None:
+    xs: list[int] = [4, 5, 6]
+    idx = find(xs, 5)
+    missing: Optional[int] = find(xs, 9)
+    print(idx, missing)
+"""
+        assert go_run_python(python_code).strip() == "1 None"
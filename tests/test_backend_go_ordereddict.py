@@ -0,0 +1,158 @@
+"""Tests for the Go backend's OrderedDict (collections.OrderedDict)."""
+
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+
+class TestGoOrderedDictConversion:
+    """Test OrderedDict() and its methods lower to the typed mgen.Dict."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_ordered_dict_codegen(self):
+        """Test constructors, move_to_end() and popitem(last) calls."""
+        python_code = """
+import collections
+from collections import OrderedDict
+
+
+def main() -> None:
+    cache: OrderedDict[str, int] = OrderedDict()
+    pairs = OrderedDict([("a", 1)])
+    copied = collections.OrderedDict(pairs)
+    cache.move_to_end("a", last=False)
+    print(cache.popitem(last=False), copied.popitem())
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "cache := mgen.NewOrderedDict[string, int]()" in go_code
+        assert 'pairs := mgen.NewOrderedDict[string, int](mgen.KV[string, int]{Key: "a", Value: 1})' in go_code
+        assert "copied := mgen.NewOrderedDict[string, int](pairs.Items()...)" in go_code
+        assert 'cache.MoveToEnd("a", false)' in go_code
+        assert "mgen.Print(cache.PopItemEnd(false), copied.PopItem())" in go_code
+
+
+class TestGoOrderedDictRuntime:
+    """Test OrderedDict reordering, equality and repr match collections.OrderedDict."""
+
+    def test_lru_cache(self, go_run):
+        """Test an LRU cache built on move_to_end and popitem(last=False)."""
+        output = go_run(
+            """
+    capacity := 2
+    cache := mgen.NewOrderedDict[int, int]()
+    get := func(key int) int {
+        if !cache.Contains(key) {
+            return -1
+        }
+        cache.MoveToEnd(key, true)
+        return cache.Get(key)
+    }
+    put := func(key int, value int) {
+        if cache.Contains(key) {
+            cache.MoveToEnd(key, true)
+        }
+        cache.Set(key, value)
+        if cache.Len() > capacity {
+            mgen.Print("evict", cache.PopItemEnd(false))
+        }
+    }
+    put(1, 1)
+    put(2, 2)
+    mgen.Print(get(1))
+    put(3, 3)
+    mgen.Print(get(2))
+    put(4, 4)
+    mgen.Print(get(1), get(3), get(4))
+    mgen.Print(mgen.Repr(cache), mgen.Repr(cache.Keys()))
+"""
+        )
+        assert output.splitlines() == [
+            "1",
+            "evict (2, 2)",
+            "-1",
+            "evict (1, 1)",
+            "-1 3 4",
+            "OrderedDict([(3, 3), (4, 4)]) [3, 4]",
+        ]
+
+    def test_order_sensitive_equality(self, go_run):
+        """Test OrderedDicts compare by order with each other but not with a plain dict."""
+        output = go_run(
+            """
+    type entry = mgen.KV[string, interface{}]
+    a := mgen.NewOrderedDict(entry{Key: "a", Value: 1}, entry{Key: "b", Value: 2})
+    b := mgen.NewOrderedDict(entry{Key: "b", Value: 2}, entry{Key: "a", Value: 1})
+    plain := mgen.NewDict(entry{Key: "b", Value: 2}, entry{Key: "a", Value: 1})
+    c := mgen.NewOrderedDict(entry{Key: "a", Value: 1.0}, entry{Key: "b", Value: 2})
+    mgen.Print(a.Equal(b), a.Equal(plain), plain.Equal(b), a.Equal(c), mgen.Eq(a, b), mgen.Eq(plain, a))
+    a.MoveToEnd("b", false)
+    mgen.Print(mgen.Repr(a), a.Equal(b), a.PopItemEnd(true), a.PopItem())
+    mgen.Print(mgen.Repr(mgen.NewOrderedDict[int, int]()))
+    check(func() { mgen.NewOrderedDict[int, int]().PopItemEnd(true) })
+    check(func() { a.MoveToEnd("zz", true) })
+"""
+        )
+        assert output.splitlines() == [
+            "False True True True False True",
+            "OrderedDict([('b', 2), ('a', 1)]) True ('a', 1) ('b', 2)",
+            "OrderedDict()",
+            "KeyError: 'dictionary is empty'",
+            "KeyError: 'zz'",
+        ]
+
+    def test_lru_cache_end_to_end(self, go_run_python):
+        """Test a transpiled LRU cache, order-sensitive equality and repr against CPython."""
+        python_code = """
+import collections
+from collections import OrderedDict
+
+
+def get(cache: OrderedDict[int, int], key: int) -> int:
+    if key not in cache:
+        return -1
+    cache.move_to_end(key)
+    return cache[key]
+
+
+def put(cache: OrderedDict[int, int], key: int, value: int, capacity: int) -> None:
+    if key in cache:
+        cache.move_to_end(key)
+    cache[key] = value
+    if len(cache) > capacity:
+        print("evict", cache.popitem(last=False))
+
+
+def main() -> None:
+    cache: OrderedDict[int, int] = OrderedDict()
+    put(cache, 1, 1, 2)
+    put(cache, 2, 2, 2)
+    print(get(cache, 1))
+    put(cache, 3, 3, 2)
+    print(get(cache, 2))
+    put(cache, 4, 4, 2)
+    print(get(cache, 1), get(cache, 3), get(cache, 4), cache, list(cache))
+    a = OrderedDict([("a", 1), ("b", 2)])
+    b = collections.OrderedDict({"b": 2, "a": 1})
+    plain = {"a": 1, "b": 2}
+    print(a == b, a == plain, b == plain, dict(a) == dict(b), a)
+    b.move_to_end("b", last=False)
+    b.move_to_end("a", False)
+    print(b)
+    print(b.popitem(), b.popitem(False), OrderedDict())
+    counts: OrderedDict[str, int] = OrderedDict()
+    for word in ["x", "y", "x"]:
+        counts[word] = counts.get(word, 0) + 1
+    print(counts, len(counts), counts.copy() == counts, dict(counts))
+    try:
+        counts.move_to_end("zz")
+    except KeyError as e:
+        print("KeyError", e)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
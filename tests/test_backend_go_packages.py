@@ -0,0 +1,213 @@
+"""Tests for the Go backend's conversion of multi-module projects into Go packages."""
+
+import shutil
+import subprocess
+import sys
+import tempfile
+from pathlib import Path
+from typing import Callable
+
+import pytest
+
+from mgen.backends.go.packages import exported_name, package_directory
+from mgen.pipeline import MGenPipeline, PipelineConfig
+
+PROJECT = {
+    "app/__init__.py": "",
+    "app/util.py": """
+def square(x: int) -> int:
+    return x * x
+
+
+def _offset() -> int:
+    return 1
+""",
+    "app/geo/__init__.py": "",
+    "app/geo/shapes.py": """
+from ..util import square
+
+
+def area(w: int, h: int) -> int:
+    return w * h
+
+
+def square_area(side: int) -> int:
+    return square(side)
+
+
+def scaled(xs: list[float], k: float) -> list[float]:
+    return [x * k for x in xs]
+""",
+    "app/main.py": """
+from app.geo.shapes import area, scaled
+from app.geo import shapes
+from . import util
+import app.util as u
+
+
+def main() -> None:
+    a = area(3, 4)
+    print(a + 1, shapes.square_area(5), util.square(2), u.square(3))
+    print(scaled([1.5, 2.0], 2.0))
+
+
+if __name__ == "__main__":
+    main()
+""",
+}
+
+
+@pytest.fixture
+def convert_project(write_project: Callable[[Path, dict[str, str]], None]) -> Callable[..., Path]:
+    """Write files under root/src and convert the project directory root/src/<package> to Go in root/out."""
+
+    def convert(root: Path, files: dict[str, str], package: str = "app") -> Path:
+        write_project(root / "src", files)
+        result = MGenPipeline(PipelineConfig(target_language="go")).convert(root / "src" / package, root / "out")
+        assert result.success, result.errors
+        return root / "out"
+
+    return convert
+
+
+class TestGoPackageNames:
+    """Test how module and function names map to Go."""
+
+    def test_package_directory(self):
+        """Test each element of a module name becomes a directory the go tool builds."""
+        assert package_directory("app.geo.shapes") == "app/geo/shapes"
+        assert package_directory("app.__main__") == "app/main"
+        assert package_directory("app._helpers") == "app/helpers"
+        assert package_directory("select") == "selectpkg"
+
+    def test_exported_name(self):
+        """Test library functions are exported in Go style, and private ones stay unexported."""
+        assert exported_name("area") == "Area"
+        assert exported_name("square_area") == "SquareArea"
+        assert exported_name("toHTML") == "ToHTML"
+        assert exported_name("_offset") == "_offset"
+
+
+class TestGoPackageConversion:
+    """Test each module becomes a Go package calling the others through their exported functions."""
+
+    def test_layout(self, convert_project):
+        """Test modules land in package directories, programs as package main and libraries named after them."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            out = convert_project(Path(tmpdir), PROJECT)
+            sources = sorted(
+                path.relative_to(out).as_posix() for path in out.rglob("*.go") if path.parent.name != "mgen"
+            )
+
+            assert sources == [
+                "app/app.go",
+                "app/geo/geo.go",
+                "app/geo/shapes/shapes.go",
+                "app/main/main.go",
+                "app/util/util.go",
+            ]
+            assert (out / "app/main/main.go").read_text().startswith("package main\n")
+            assert (out / "app/geo/shapes/shapes.go").read_text().startswith("package shapes\n")
+            assert (out / "go.mod").exists() and (out / "mgen").is_dir()
+
+    def test_exported_functions(self, convert_project):
+        """Test library functions are exported, calls within the package included, and private ones are not."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            out = convert_project(Path(tmpdir), PROJECT)
+            shapes = (out / "app/geo/shapes/shapes.go").read_text()
+            util = (out / "app/util/util.go").read_text()
+
+            assert "func SquareArea(side int) int {\n\treturn util.Square(side)\n}" in shapes
+            assert 'import "mgenproject/app/util"' in shapes
+            assert "func _offset() int {" in util
+
+    def test_qualified_references(self, convert_project):
+        """Test every way of importing a function calls it through its package."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            out = convert_project(Path(tmpdir), PROJECT)
+            main = (out / "app/main/main.go").read_text()
+
+            assert 'import "mgenproject/app/geo/shapes"\nimport "mgenproject/app/util"\n' in main
+            assert "a := shapes.Area(3, 4)" in main
+            assert "mgen.Print((a + 1), shapes.SquareArea(5), util.Square(2), util.Square(3))" in main
+            assert "shapes.Scaled([]float64{1.5, 2.0}, 2.0)" in main
+
+    def test_shared_package_names(self, write_project):
+        """Test packages sharing a name are imported under their directory, and locals shadowing imports stay."""
+        files = {
+            "main.py": """
+from a.util import f
+from b import util
+
+
+def main() -> None:
+    g = f
+    print(f(1), util.f(2))
+
+
+def shadow(f: int) -> int:
+    return f
+""",
+            "a/util.py": "def f(x: int) -> int:\n    return x\n",
+            "b/util.py": "def f(x: int) -> int:\n    return -x\n",
+        }
+        with tempfile.TemporaryDirectory() as tmpdir:
+            root = Path(tmpdir)
+            write_project(root / "src", files)
+            result = MGenPipeline(PipelineConfig(target_language="go")).convert(root / "src", root / "out")
+            assert result.success, result.errors
+            main = (root / "out/main/main.go").read_text()
+
+            assert 'import "mgenproject/a/util"\nimport b_util "mgenproject/b/util"\n' in main
+            assert "mgen.Print(util.F(1), b_util.F(2))" in main
+            assert "func shadow(f int) int {\n\treturn f\n}" in main
+
+    def test_class_import_unsupported(self, write_project):
+        """Test importing a class from another module is reported rather than mistranslated."""
+        files = {
+            "main.py": "from shapes import Point\n\n\ndef main() -> None:\n    print(1)\n",
+            "shapes.py": "class Point:\n    pass\n",
+        }
+        with tempfile.TemporaryDirectory() as tmpdir:
+            root = Path(tmpdir)
+            write_project(root / "src", files)
+            result = MGenPipeline(PipelineConfig(target_language="go")).convert(root / "src", root / "out")
+
+            assert not result.success
+            assert "Cannot import Point from shapes: the Go backend imports only functions" in result.errors[0]
+
+    def test_failed_conversion_leaves_no_output(self, write_project):
+        """Test modules converted before the one that fails are removed, and files already there are kept."""
+        files = {
+            "shapes.py": "class Point:\n    pass\n",
+            "main.py": "from shapes import Point\n\n\ndef main() -> None:\n    print(1)\n",
+        }
+        with tempfile.TemporaryDirectory() as tmpdir:
+            root = Path(tmpdir)
+            write_project(root / "src", files)
+            write_project(root / "out", {"notes/keep.txt": "kept"})
+            result = MGenPipeline(PipelineConfig(target_language="go")).convert(root / "src", root / "out")
+
+            assert not result.success
+            assert result.generated_files == []
+            remaining = sorted(path.relative_to(root / "out").as_posix() for path in (root / "out").rglob("*"))
+            assert remaining == ["notes", "notes/keep.txt"]
+
+
+@pytest.mark.skipif(shutil.which("go") is None, reason="Go toolchain not available")
+class TestGoPackageProgram:
+    """Test converted projects build with go build ./... and print what CPython prints."""
+
+    def test_project(self, convert_project):
+        """Test the program of a package with nested and relative imports."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            root = Path(tmpdir)
+            out = convert_project(root, PROJECT)
+            build = subprocess.run(["go", "build", "./..."], capture_output=True, text=True, cwd=out)
+            assert build.returncode == 0, build.stderr
+            go_run = subprocess.run(["go", "run", "./app/main"], capture_output=True, text=True, cwd=out)
+            python_run = subprocess.run(
+                [sys.executable, "-m", "app.main"], capture_output=True, text=True, cwd=root / "src"
+            )
+
+            assert go_run.stdout == python_run.stdout == "13 25 4 9\n[3.0, 4.0]\n"
@@ -0,0 +1,129 @@
+"""Tests for the Go backend's pprint support (mgen.PPrint / mgen.PFormat)."""
+
+import pytest
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+
+class TestGoPPrintCodegen:
+    """Test pprint calls lower to the runtime pretty printer."""
+
+    def test_pprint_and_pformat_codegen(self):
+        """Test indent and width become PPrintOptions fields."""
+        python_code = """
+import pprint
+from pprint import pformat
+
+
+def f(grid: list[list[int]]) -> str:
+    pprint.pprint(grid)
+    pprint.pprint(grid, width=14)
+    return pformat(grid, indent=2, width=20)
+"""
+        go_code = MGenPythonToGoConverter().convert_code(python_code)
+        assert "mgen.PPrint(grid, mgen.PPrintOptions{})" in go_code
+        assert "mgen.PPrint(grid, mgen.PPrintOptions{Width: 14})" in go_code
+        assert "return mgen.PFormat(grid, mgen.PPrintOptions{Indent: 2, Width: 20})" in go_code
+
+    def test_unsupported_keyword_is_rejected(self):
+        """Test pprint keywords the runtime does not implement fail at transpile time."""
+        python_code = """
+import pprint
+
+
+def f(xs: list[int]) -> None:
+    pprint.pprint(xs, compact=True)
+"""
+        with pytest.raises(TypeMappingError, match="unsupported keyword argument 'compact'"):
+            MGenPythonToGoConverter().convert_code(python_code)
+
+
+class TestGoPPrintRuntime:
+    """Test the layout matches Python's pprint at different widths."""
+
+    def test_compact_and_expanded_layouts(self, go_run):
+        """Test one structure stays on one line when it fits and breaks recursively when it does not."""
+        output = go_run(
+            """
+    data := mgen.NewPyDict(
+        mgen.PyDictEntry{Key: "zeta", Value: []int{1, 2, 3, 4, 5, 6, 7, 8}},
+        mgen.PyDictEntry{Key: "alpha", Value: map[string][]string{"names": {"ann", "bob"}, "empty": {}}},
+        mgen.PyDictEntry{Key: "pair", Value: mgen.Pair[int, string]{First: 1, Second: "one"}},
+        mgen.PyDictEntry{Key: "set", Value: map[int]bool{3: true, 1: true, 2: true}},
+    )
+    for _, width := range []int{140, 60, 30} {
+        mgen.PPrint(data, mgen.PPrintOptions{Width: width})
+        mgen.Print("--")
+    }
+    mgen.PPrint([][]int{{1, 2, 3}, {4, 5, 6}}, mgen.PPrintOptions{Indent: 4, Width: 12})
+"""
+        )
+        # pprint.pprint(data, width=...) for each width, then the nested list with indent=4
+        assert output == (
+            "{'alpha': {'empty': [], 'names': ['ann', 'bob']}, 'pair': (1, 'one'), 'set': {1, 2, 3}, "
+            "'zeta': [1, 2, 3, 4, 5, 6, 7, 8]}\n"
+            "--\n"
+            "{'alpha': {'empty': [], 'names': ['ann', 'bob']},\n"
+            " 'pair': (1, 'one'),\n"
+            " 'set': {1, 2, 3},\n"
+            " 'zeta': [1, 2, 3, 4, 5, 6, 7, 8]}\n"
+            "--\n"
+            "{'alpha': {'empty': [],\n"
+            "           'names': ['ann',\n"
+            "                     'bob']},\n"
+            " 'pair': (1, 'one'),\n"
+            " 'set': {1, 2, 3},\n"
+            " 'zeta': [1,\n"
+            "          2,\n"
+            "          3,\n"
+            "          4,\n"
+            "          5,\n"
+            "          6,\n"
+            "          7,\n"
+            "          8]}\n"
+            "--\n"
+            "[   [   1,\n"
+            "        2,\n"
+            "        3],\n"
+            "    [   4,\n"
+            "        5,\n"
+            "        6]]\n"
+        )
+
+    def test_long_strings_split_at_words(self, go_run):
+        """Test strings wider than the line break into adjacent literals."""
+        output = go_run(
+            """
+    text := "the quick brown fox jumps over the lazy dog"
+    mgen.PPrint(text, mgen.PPrintOptions{Width: 20})
+    mgen.PPrint([]string{text}, mgen.PPrintOptions{Width: 30})
+"""
+        )
+        assert output == (
+            "('the quick brown '\n"
+            " 'fox jumps over '\n"
+            " 'the lazy dog')\n"
+            "['the quick brown fox jumps '\n"
+            " 'over the lazy dog']\n"
+        )
+
+    def test_pformat_end_to_end(self, go_run_python):
+        """Test a transpiled program prints what Python's pprint prints."""
+        python_code = """
+import pprint
+
+
+def main() -> None:
+    scores: dict[str, list[int]] = {"bob": [70, 85, 90], "ann": [88, 92]}
+    pprint.pprint(scores, width=20)
+    text = pprint.pformat(scores)
+    print(len(text.splitlines()))
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "{'ann': [88, 92],",
+            " 'bob': [70,",
+            "         85,",
+            "         90]}",
+            "1",
+        ]
@@ -0,0 +1,132 @@
+"""Tests for the Go backend's Python 2 compatibility mode (python_version=2)."""
+
+import pytest
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.backends.go.emitter import GoEmitter
+from mgen.backends.go.py2compat import rewrite_print_statements
+from mgen.backends.preferences import GoPreferences
+from mgen.errors import TypeMappingError
+
+DIVIDE = """
+def divide(a: int, b: int) -> int:
+    return a / b
+"""
+
+
+def py2_preferences() -> GoPreferences:
+    """Return Go preferences selecting Python 2 sources."""
+    preferences = GoPreferences()
+    preferences.set("python_version", 2)
+    return preferences
+
+
+class TestPrintStatementRewrite:
+    """Test print statements are rewritten to equivalent print() calls."""
+
+    @pytest.mark.parametrize(
+        "source,expected",
+        [
+            ("print x, y + 1\n", "print(x, y + 1)\n"),
+            ("print\n", "print()\n"),
+            ("print x,\n", 'print(x, end=" ")\n'),
+            ("print >>sys.stderr, 'oops'\n", "print('oops', file=sys.stderr)\n"),
+            ("print(x)\n", "print(x)\n"),
+            ("if x: print x  # note\n", "if x: print(x)  # note\n"),
+            ("print [1,\n       2]; y = 1\n", "print([1, 2]); y = 1\n"),
+            ("d = {1: print}\n", "d = {1: print}\n"),
+        ],
+    )
+    def test_rewrite(self, source, expected):
+        """Test each print statement form and that print used as a name is left alone."""
+        assert rewrite_print_statements(source) == expected
+
+
+class TestPython2Mode:
+    """Test python_version=2 switches print and integer division semantics."""
+
+    def test_python3_is_default(self):
+        """Test int / int divides as float64 and print statements are rejected by default."""
+        go_code = MGenPythonToGoConverter().convert_code(DIVIDE.replace("-> int", "-> float"))
+
+        assert "func divide(a int, b int) float64 {\n    return mgen.TrueDivInt(a, b)" in go_code
+        assert "mgen.FloorDivInt" not in go_code
+        with pytest.raises(TypeMappingError):
+            MGenPythonToGoConverter().convert_code("def f() -> None:\n    print 1\n")
+
+    def test_int_division_floors(self):
+        """Test / between ints lowers to floor division, while float division is unchanged."""
+        python_code = (
+            DIVIDE
+            + """
+def scale(x: float, y: int) -> float:
+    return x / y
+"""
+        )
+        go_code = MGenPythonToGoConverter(py2_preferences()).convert_code(python_code)
+
+        assert "    return mgen.FloorDivInt(a, b)" in go_code
+        assert "    return (x / y)" in go_code
+
+    def test_emitter_passes_preferences(self):
+        """Test the backend emitter honours the python_version preference."""
+        go_code = GoEmitter(py2_preferences()).emit_module(DIVIDE, None)
+
+        assert "mgen.FloorDivInt(a, b)" in go_code
+
+    def test_invalid_version(self):
+        """Test python_version accepts only 2 or 3."""
+        preferences = GoPreferences()
+        preferences.set("python_version", 4)
+        with pytest.raises(ValueError, match="python_version must be 2 or 3"):
+            MGenPythonToGoConverter(preferences)
+
+    def test_python3_true_division(self, go_run_python):
+        """Test int / int gives a float in Python 3 mode and dividing by zero raises."""
+        python_code = """
+def ratio(a: int, b: int) -> float:
+    return a / b
+
+
+def main() -> None:
+    print(1 / 3, ratio(7, 2), ratio(-7, 2), 6 / 3, 7.5 / 2.5)
+    x = 9
+    half = x / 2
+    print(half + 1)
+    try:
+        print(ratio(1, 0))
+    except ZeroDivisionError as e:
+        print(e)
+
+
+main()
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "0.3333333333333333 3.5 -3.5 2.0 3.0",
+            "5.5",
+            "division by zero",
+        ]
+
+    def test_python2_program(self, go_run_python):
+        """Test a Python 2 program prints what Python 2 prints, including negative int division."""
+        python_code = """
+def mean(total: int, count: int) -> int:
+    return total / count
+
+
+def main():
+    x = 7
+    y = 2
+    print x / y, -x / y, x // y, 7.5 / 2.5
+    print "mean:", mean(10, 4), mean(-10, 4)
+    z: int = x * 3
+    z /= -4
+    print z,
+    print "done"
+    print
+
+
+main()
+"""
+        # Python 2.7 output for the same program
+        assert go_run_python(python_code, preferences=py2_preferences()) == "3 -4 3 3.0\nmean: 2 -3\n-6 done\n\n"
@@ -0,0 +1,260 @@
+"""Tests for Go backend repr() and ascii() support."""
+
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+
+class TestGoReprConversion:
+    """Test repr()/ascii() code generation."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_repr_and_ascii_builtins(self):
+        """Test repr() and ascii() call the runtime and return strings."""
+        python_code = """
+def debug(name: str) -> str:
+    shown = ascii(name)
+    return repr(name) + shown
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "shown := mgen.Ascii(name)" in go_code
+        assert "return (mgen.Repr(name) + shown)" in go_code
+
+    def test_whole_float_literals_stay_floats(self):
+        """Test 2.0 and 1e22 keep Python's float form, so repr() sees a float64."""
+        go_code = self.converter.convert_code("def main() -> None:\n    x = 2.0\n    print(repr(1e22), x)\n")
+
+        assert "var x float64 = 2.0" in go_code
+        assert "mgen.Repr(1e+22)" in go_code
+
+
+class TestGoAsciiRuntime:
+    """Test ascii() escaping against Python output."""
+
+    def test_ascii_escapes_non_ascii(self, go_run):
+        """Test accented, BMP and astral characters use \\x, \\u and \\U escapes."""
+        output = go_run(
+            """
+    mgen.Print(mgen.Ascii("café"))
+    mgen.Print(mgen.Ascii("日本"))
+    mgen.Print(mgen.Ascii("hi 😀"))
+    mgen.Print(mgen.Ascii([]string{"naïve", "plain"}))
+    mgen.Print(mgen.Ascii(42), mgen.Ascii("it's"))
+"""
+        )
+        # Expected values produced by CPython's ascii()
+        assert output.splitlines() == [
+            ascii("café"),
+            ascii("日本"),
+            ascii("hi 😀"),
+            ascii(["naïve", "plain"]),
+            ascii(42) + " " + ascii("it's"),
+        ]
+
+
+class TestGoReprRuntime:
+    """Test repr() of containers holding floats against Python output."""
+
+    def test_float_items_use_python_repr(self, go_run):
+        """Test floats inside lists, dicts and sets keep their Python repr."""
+        output = go_run(
+            """
+    mgen.Print(mgen.Repr([]float64{1.0, 2.0}))
+    mgen.Print(mgen.Repr(map[string]float64{"x": 0.1}))
+    mgen.Print(mgen.Repr([]interface{}{1e16, -0.0 * -1, 1e-5, "a"}))
+    mgen.Print(mgen.Repr(map[float64]bool{2.5: true}), mgen.Repr([][]float64{{0.5}, {}}))
+"""
+        )
+        assert output.splitlines() == [
+            repr([1.0, 2.0]),
+            repr({"x": 0.1}),
+            repr([1e16, 0.0, 1e-5, "a"]),
+            repr({2.5}) + " " + repr([[0.5], []]),
+        ]
+
+    def test_repr_of_float_list_end_to_end(self, go_run_python):
+        """Test repr() on a transpiled list and dict of floats."""
+        python_code = """
+def main() -> None:
+    values: list[float] = [1.0, 2.0]
+    weights: dict[str, float] = {"x": 0.1}
+    print(repr(values), repr(weights))
+"""
+        assert go_run_python(python_code).strip() == "[1.0, 2.0] {'x': 0.1}"
+
+
+class TestGoContainerPrintRuntime:
+    """Test print() and str() render containers and whole floats as Python does."""
+
+    def test_print_and_str_use_repr_for_items(self, go_run_python):
+        """Test print(), str() and f-string !r on lists, dicts and sets, and whole float literals."""
+        python_code = """
+def main() -> None:
+    xs = [1, 2, 3]
+    names = ["a", "b'c"]
+    nested = [[1, 2], [3]]
+    ages = {"amy": 9}
+    s = "it's \\"q\\"\\n"
+    print(xs, names, nested, ages, {"z"}, [])
+    print(str(names) + "!", str(nested), f"{names!r:>14}|{s!r}")
+    print(2.0, repr(1e22), repr(2.0), [0.5, 3.0], repr(s))
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
+
+    def test_tuples_use_parentheses(self, go_run_python):
+        """Test repr(), str() and print() of tuples, one-element tuples and sorted lists of tuples."""
+        python_code = """
+def main() -> None:
+    point = (3, "x")
+    single = (2.0,)
+    pairs = sorted([(2, "b"), (1, "z"), (1, "a")])
+    print(point, single, (), pairs)
+    print(repr(point), str(single), repr(pairs), {"k": (1, 2)})
+    print(sorted([(len(w), w) for w in ["bb", "a", "cc"]], reverse=True))
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
+
+
+class TestGoNestedReprRuntime:
+    """Test repr() of containers nested inside other container types."""
+
+    def test_three_level_nesting(self, go_run):
+        """Test each container keeps its own ordering rule at every depth."""
+        output = go_run(
+            """
+    d := mgen.NewPyDict(
+        mgen.PyDictEntry{Key: "b", Value: 1.0},
+        mgen.PyDictEntry{Key: []interface{}{1, "a"}, Value: mgen.NewPySet(3, 1, 2)},
+        mgen.PyDictEntry{Key: "a", Value: map[string]bool{"z": true, "m": true}},
+    )
+    l := mgen.NewPyList(d, []interface{}{mgen.NewPySet(), 0.5}, map[int]string{2: "x", 1: "y"})
+    s := mgen.NewPySet([]interface{}{1.5, "x", []interface{}{2}}, "k")
+    outer := mgen.NewPyDict(
+        mgen.PyDictEntry{Key: "list", Value: l},
+        mgen.PyDictEntry{Key: "set", Value: s},
+        mgen.PyDictEntry{Key: "items", Value: d.Items()[1:]},
+    )
+    mgen.Print(mgen.Repr(outer))
+    mgen.Print(mgen.Repr(map[string]interface{}{"z": []interface{}{outer}, "a": nil}))
+"""
+        )
+        # Python's own set order is hash-based, so sets are spliced in by placeholder:
+        # PySet keeps insertion order and native map sets list their members sorted
+        inner = {"b": 1.0, (1, "a"): "PYSET", "a": "MAPSET"}
+        nested = [inner, [set(), 0.5], {1: "y", 2: "x"}]
+        outer = {"list": nested, "set": "OUTERSET", "items": list(inner.items())[1:]}
+        expected = (
+            repr(outer)
+            .replace("'PYSET'", "{3, 1, 2}")
+            .replace("'MAPSET'", "{'m', 'z'}")
+            .replace("'OUTERSET'", "{(1.5, 'x', (2,)), 'k'}")
+        )
+        assert output.splitlines() == [expected, "{'a': None, 'z': [" + expected + "]}"]
+
+    def test_self_referencing_containers(self, go_run):
+        """Test a container reached again while rendering prints [...] or {...}."""
+        output = go_run(
+            """
+    l := mgen.NewPyList(1)
+    l.Items = append(l.Items, l)
+    d := mgen.NewPyDict(mgen.PyDictEntry{Key: "self", Value: nil})
+    d.Set("self", d)
+    d.Set("list", mgen.NewPyList(l, d))
+    m := map[string]interface{}{"n": 1}
+    m["m"] = []interface{}{m}
+    shared := mgen.NewPyList(2)
+    mgen.Print(mgen.Repr(l))
+    mgen.Print(mgen.Repr(d))
+    mgen.Print(mgen.Repr(m))
+    mgen.Print(mgen.Repr(mgen.NewPyList(shared, shared)), d.Items()[0])
+"""
+        )
+        l = [1]
+        l.append(l)
+        d = {"self": None}
+        d["self"] = d
+        d["list"] = [l, d]
+        m = {"n": 1}
+        m["m"] = [m]
+        shared = [2]
+        assert output.splitlines() == [
+            repr(l),
+            repr(d),
+            # Native Go maps list their keys in sorted order
+            repr(m).replace("{'n': 1, 'm': [{...}]}", "{'m': [{...}], 'n': 1}"),
+            repr([shared, shared]) + " " + repr(list(d.items())[0]),
+        ]
+
+
+class TestGoUserClassRepr:
+    """Test classes defining __str__ and __repr__ print and format as in Python."""
+
+    POINTS = """
+class Point:
+    def __init__(self, x: int, y: int):
+        self.x = x
+        self.y = y
+
+    def __repr__(self):
+        return f"Point({self.x}, {self.y})"
+
+
+class Animal:
+    def __init__(self, name: str):
+        self.name = name
+
+    def __str__(self) -> str:
+        return "Animal " + self.name
+
+    def __repr__(self) -> str:
+        return "Animal(" + repr(self.name) + ")"
+
+
+class Dog(Animal):
+    def __str__(self) -> str:
+        return "Dog " + self.name + " / " + super().__str__()
+"""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_stringer_codegen(self):
+        """Test __str__/__repr__ become PyStr/PyRepr, with a value-receiver String for fmt.Stringer."""
+        go_code = self.converter.convert_code(self.POINTS)
+
+        assert "func (obj *Point) PyRepr() string {" in go_code
+        assert "func (obj Point) String() string {\n    return obj.PyRepr()\n}" in go_code
+        assert "func (obj Animal) String() string {\n    return obj.PyStr()\n}" in go_code
+        # A promoted String would call Animal's PyStr, so the subclass gets its own
+        assert "func (obj Dog) String() string {\n    return obj.PyStr()\n}" in go_code
+
+    def test_print_str_repr_end_to_end(self, go_run_python):
+        """Test print(), str(), repr(), f-strings and containers use the user representation."""
+        python_code = (
+            self.POINTS
+            + """
+
+def main() -> None:
+    p = Point(1, 2)
+    pets: list[Animal] = [Dog("rex"), Animal("tom")]
+    print(p, str(p), repr(p), f"<{p}>", [p, Point(3, 4)])
+    for pet in pets:
+        print(pet, repr(pet), f"{pet!r}")
+    print(pets, "d=" + str(Dog("fido")))
+
+
+main()
+"""
+        )
+        assert go_run_python(python_code) == python_output(python_code)
@@ -0,0 +1,120 @@
+"""Tests for Go backend slicing, which copies like Python's instead of aliasing."""
+
+import itertools
+
+import pytest
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+
+# Slice bounds tried on a 5-item list and a 5-code-point string; None is an omitted bound
+SLICE_BOUNDS = [None, -7, -5, -2, 0, 1, 3, 5, 9]
+SLICE_STEPS = [None, 1, 2, -1, -3]
+
+
+def go_bound(bound: int | None) -> str:
+    """Render a slice bound as a NewSlice argument."""
+    return "nil" if bound is None else str(bound)
+
+
+class TestGoSliceConversion:
+    """Test slices lower to copying runtime helpers rather than Go reslicing."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_list_and_string_slices(self):
+        """Test list and string slices, and that the result keeps the sliced type."""
+        python_code = """
+def tail(items: list[int], name: str) -> str:
+    rest = items[1:]
+    rest.append(0)
+    return name[::-1]
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "var rest []int = mgen.SliceSlice(items, mgen.NewSlice(1, nil, nil))" in go_code
+        assert "rest = append(rest, 0)" in go_code
+        assert "return mgen.SliceString(name, mgen.NewSlice(nil, nil, (-1)))" in go_code
+
+    def test_untyped_slice_rejected(self):
+        """Test slicing a value of unknown type fails at transpile time."""
+        python_code = """
+def head(items):
+    return items[:2]
+"""
+        with pytest.raises(TypeMappingError, match="Cannot slice a value of unknown type"):
+            self.converter.convert_code(python_code)
+
+
+class TestGoSliceRuntime:
+    """Test slice results are independent copies with Python's index rules."""
+
+    def test_mutating_slice_leaves_source(self, go_run):
+        """Test assigning into and appending to a slice result does not alter the source."""
+        output = go_run(
+            """
+    items := []int{1, 2, 3, 4, 5}
+    head := mgen.SliceSlice(items, mgen.NewSlice(nil, 3, nil))
+    head[0] = 99
+    head = append(head, 100)
+    mgen.Print(mgen.Repr(items), mgen.Repr(head))
+    list := mgen.NewPyList(1, 2, 3)
+    part := list.Slice(mgen.NewSlice(1, nil, nil))
+    part.Items[0] = "x"
+    mgen.Print(mgen.Repr(list), mgen.Repr(part))
+"""
+        )
+        assert output.splitlines() == ["[1, 2, 3, 4, 5] [99, 2, 3, 100]", "[1, 2, 3] ['x', 3]"]
+
+    def test_slices_end_to_end(self, go_run_python):
+        """Test negative indices, steps, out-of-range bounds and code point string slicing."""
+        python_code = """
+def main() -> None:
+    items: list[int] = [1, 2, 3, 4, 5]
+    head = items[:3]
+    head[0] = 99
+    head.append(100)
+    print(repr(items), repr(head))
+    print(repr(items[::-2]), repr(items[-2:]), repr(items[10:]), repr(items[1:4:2]))
+    word = "héllo"
+    print(word[1:3], word[::-1], word[-1:])
+    rest = items[1:]
+    total = 0
+    for x in rest:
+        total += x
+    print(total, len(rest))
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "[1, 2, 3, 4, 5] [99, 2, 3, 100]",
+            "[5, 3, 1] [4, 5] [] [2, 4]",
+            "él olléh o",
+            "14 4",
+        ]
+
+    def test_bounds_match_cpython(self, go_run):
+        """Test every combination of start, stop and step clamps like CPython."""
+        items, word = [10, 11, 12, 13, 14], "aéiöu"
+        cases = list(itertools.product(SLICE_BOUNDS, SLICE_BOUNDS, SLICE_STEPS))
+        body = []
+        for start, stop, step in cases:
+            bounds = f"mgen.NewSlice({go_bound(start)}, {go_bound(stop)}, {go_bound(step)})"
+            sliced = f"mgen.Repr(mgen.SliceSlice(items, {bounds})), mgen.SliceString(word, {bounds})"
+            body.append(f'    mgen.Print({sliced} + "|")')
+        output = go_run(f'    items, word := []int{{10, 11, 12, 13, 14}}, "{word}"\n' + "\n".join(body))
+
+        assert output.splitlines() == [
+            f"{items[start:stop:step]!r} {word[start:stop:step]}|" for start, stop, step in cases
+        ]
+
+    def test_zero_step_raises(self, go_run):
+        """Test a zero step raises ValueError like CPython."""
+        output = go_run(
+            """
+    defer func() { mgen.Print(recover().(error).Error()) }()
+    mgen.SliceSlice([]int{1, 2}, mgen.NewSlice(nil, nil, 0))
+"""
+        )
+        assert output.strip() == "ValueError: slice step cannot be zero"
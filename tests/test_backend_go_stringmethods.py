@@ -1,5 +1,7 @@
 """Tests for Go backend string methods support."""
 
+import json
+
 import pytest
 
 from mgen.backends.go.converter import MGenPythonToGoConverter
@@ -194,4 +196,369 @@ class Validator:
         go_code = self.converter.convert_code(python_code)
 
         assert "cleaned := mgen.StrOps.Lower(mgen.StrOps.Strip(input_text))" in go_code
-        assert "return (cleaned == mgen.StrOps.Lower(obj.Required))" in go_code
\ No newline at end of file
+        assert "return (cleaned == mgen.StrOps.Lower(obj.Required))" in go_code
+
+class TestGoStringPadding:
+    """Test str.ljust/rjust/center conversion and code-point width semantics."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_padding_methods_conversion(self):
+        """Test padding methods map to StrOps with a default space fill."""
+        python_code = """
+def pad(text: str) -> str:
+    left = text.ljust(10)
+    right = text.rjust(10, "*")
+    return text.center(12, "-") + left + right
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'left := mgen.StrOps.Ljust(text, 10, " ")' in go_code
+        assert 'right := mgen.StrOps.Rjust(text, 10, "*")' in go_code
+        assert 'mgen.StrOps.Center(text, 12, "-")' in go_code
+
+    def test_padding_counts_code_points(self, go_run):
+        """Test CJK, emoji and multi-byte fill pad by code point like CPython."""
+        output = go_run(
+            """
+    mgen.Print("[" + mgen.StrOps.Ljust("日本", 5, "*") + "]")
+    mgen.Print("[" + mgen.StrOps.Center("ab", 7, "é") + "]")
+    mgen.Print("[" + mgen.StrOps.Rjust("😀", 3, "中") + "]")
+    mgen.Print("[" + mgen.StrOps.Center("abc", 6, " ") + "]")
+    mgen.Print("[" + mgen.StrOps.Center("ab", 5, " ") + "]")
+    mgen.Print("[" + mgen.StrOps.Ljust("long", 2, " ") + "]")
+"""
+        )
+        # Expected values produced by CPython
+        assert output.splitlines() == ["[日本***]", "[éééabéé]", "[中中😀]", "[ abc  ]", "[  ab ]", "[long]"]
+
+    def test_padding_display_width(self, go_run):
+        """Test the display-width variants count wide characters as two columns."""
+        output = go_run(
+            """
+    mgen.Print(mgen.DisplayWidth("日本"), mgen.DisplayWidth("e\\u0301"), mgen.DisplayWidth("😀a"))
+    mgen.Print("[" + mgen.StrOps.LjustDisplay("日本", 6, ".") + "]")
+    mgen.Print("[" + mgen.StrOps.RjustDisplay("ab", 4, ".") + "]")
+    mgen.Print("[" + mgen.StrOps.CenterDisplay("中", 6, " ") + "]")
+"""
+        )
+        assert output.splitlines() == ["4 1 3", "[日本..]", "[..ab]", "[  中  ]"]
+
+    def test_padding_rejects_long_fill(self, go_run):
+        """Test a multi-character fill raises TypeError."""
+        output = go_run(
+            """
+    defer func() { mgen.Print(recover().(error).Error()) }()
+    mgen.StrOps.Ljust("a", 3, "ab")
+"""
+        )
+        assert output.strip() == "TypeError: The fill character must be exactly one character long"
+
+
+class TestGoStringScanning:
+    """Test runtime helpers used by transpiled tokenizers."""
+
+    def test_find_all_non_overlapping(self, go_run):
+        """Test FindAll skips past each match, including overlapping candidates."""
+        output = go_run(
+            """
+    mgen.Print(mgen.StrOps.FindAll("aaa", "aa"))
+    mgen.Print(mgen.StrOps.FindAll("aaaa", "aa"))
+    mgen.Print(mgen.StrOps.FindAll("abcabc", "c"), mgen.StrOps.FindAll("abc", "x"))
+"""
+        )
+        assert output.splitlines() == ["[0]", "[0, 2]", "[2, 5] []"]
+
+    def test_find_all_empty_substring(self, go_run):
+        """Test an empty substring matches at every boundary without looping forever."""
+        output = go_run(
+            """
+    mgen.Print(mgen.StrOps.FindAll("ab", ""), mgen.StrOps.FindAll("", ""))
+"""
+        )
+        assert output.strip() == "[0, 1, 2] [0]"
+
+    def test_find_all_non_ascii(self, go_run):
+        """Test FindAll reports code point indices, as str.find does, not byte offsets."""
+        output = go_run(
+            """
+    mgen.Print(mgen.StrOps.FindAll("héllo wörld", "l"), mgen.StrOps.FindAll("日本", ""))
+    mgen.Print(mgen.StrOps.SplitKeepSep("α+β", "+"))
+"""
+        )
+        assert output.splitlines() == ["[2, 3, 9] [0, 1, 2]", "['α', '+', 'β']"]
+
+    def test_split_keep_sep(self, go_run):
+        """Test SplitKeepSep retains separators, including at the edges."""
+        output = go_run(
+            """
+    mgen.Print(mgen.StrOps.SplitKeepSep("a+b+c", "+"))
+    mgen.Print(len(mgen.StrOps.SplitKeepSep("+a+", "+")))
+    defer func() { mgen.Print(recover().(error).Error()) }()
+    mgen.StrOps.SplitKeepSep("abc", "")
+"""
+        )
+        assert output.splitlines() == ["['a', '+', 'b', '+', 'c']", "5", "ValueError: empty separator"]
+
+
+# (text, sub, bounds) searches compared against CPython's find/rfind/index/rindex;
+# bounds are the optional start and end arguments
+SEARCH_CASES = [
+    ("hello", "l", ()),
+    ("hello", "z", ()),
+    ("hello", "", ()),
+    ("", "", ()),
+    ("", "a", ()),
+    ("abcabc", "abc", (1,)),
+    ("abcabc", "abc", (0, 5)),
+    ("abcabc", "bc", (-3,)),
+    ("abcabc", "bc", (-100, -3)),
+    ("abcabc", "c", (2, 3)),
+    ("abcabc", "c", (3, 2)),
+    ("ab", "", (2,)),
+    ("ab", "", (3,)),
+    ("ab", "", (1, 1)),
+    ("ab", "", (-1,)),
+    ("ab", "", (0, -5)),
+    ("abc", "abcd", ()),
+    ("aaaa", "aa", (1,)),
+    ("aaaa", "aa", (0, 3)),
+    ("日本語日本", "本", ()),
+    ("日本語日本", "本", (2,)),
+    ("日本語日本", "", (-2,)),
+    ("naïve café", "é", (0, 100)),
+]
+
+
+class TestGoStringSearch:
+    """Test str.find/rfind/index/rindex bounds and errors against CPython."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_search_conversion(self):
+        """Test start and end pass through to the StrOps search family."""
+        python_code = """
+def locate(line: str, word: str) -> int:
+    a: int = line.find(word, 2)
+    b: int = line.rfind(word)
+    c: int = line.index(word, 1, -1)
+    d: int = line.rindex(word, 0)
+    return a + b + c + d
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.StrOps.FindRange(line, word, 2)" in go_code
+        assert "mgen.StrOps.RFind(line, word)" in go_code
+        assert "mgen.StrOps.Index(line, word, 1, (-1))" in go_code
+        assert "mgen.StrOps.RIndex(line, word, 0)" in go_code
+
+    def test_search_matches_python(self, go_run):
+        """Test every method agrees with CPython, including empty substrings and clamped windows."""
+        body = []
+        expected = []
+        for text, sub, bounds in SEARCH_CASES:
+            args = ", ".join([json.dumps(s, ensure_ascii=False) for s in (text, sub)] + [str(b) for b in bounds])
+            for method, go_name in (("find", "FindRange"), ("rfind", "RFind"), ("index", "Index"), ("rindex", "RIndex")):
+                body.append(f"    check(func() interface{{}} {{ return mgen.StrOps.{go_name}({args}) }})")
+                try:
+                    expected.append(str(getattr(text, method)(sub, *bounds)))
+                except ValueError as e:
+                    expected.append(f"ValueError: {e}")
+        output = go_run("\n".join(body))
+
+        assert output.splitlines() == expected
+
+    def test_find_counts_code_points(self, go_run):
+        """Test plain find() reports a code point index, matching slicing."""
+        output = go_run(
+            """
+    mgen.Print(mgen.StrOps.Find("日本語", "語"), mgen.StrOps.Find("naïve", "v"))
+"""
+        )
+        assert output.strip() == "2 3"
+
+    def test_index_miss_is_catchable(self, go_run_python):
+        """Test a transpiled str.index miss raises ValueError that try/except can catch."""
+        output = go_run_python(
+            """
+def position(line: str, word: str) -> int:
+    try:
+        return line.index(word, 1)
+    except ValueError:
+        return -1
+
+
+def main() -> None:
+    print(position("key=value", "="))
+    print(position("=value", "="))
+    print("key=value".rindex("e", 0, 3))
+
+
+if __name__ == "__main__":
+    main()
+"""
+        )
+        assert output.splitlines() == ["3", "-1", "1"]
+
+
+# (method, text, args) calls compared against CPython; partition results are
+# compared as lists, which is how the runtime returns them
+STRING_METHOD_CASES = [
+    ("startswith", "key=value", ("key",)),
+    ("startswith", "key=value", ("",)),
+    ("endswith", "report.txt", (".txt",)),
+    ("endswith", "report.txt", (".md",)),
+    ("count", "banana", ("an",)),
+    ("count", "aaaa", ("aa",)),
+    ("count", "héllo", ("",)),
+    ("count", "banana", ("a", 2, -1)),
+    ("count", "ab", ("", 3)),
+    ("title", "they're bill's friends", ()),
+    ("title", "3rd ÉTAGE", ()),
+    ("capitalize", "hELLO wORLD", ()),
+    ("capitalize", "", ()),
+    ("zfill", "42", (5,)),
+    ("zfill", "-42", (5,)),
+    ("zfill", "+7", (3,)),
+    ("zfill", "abc", (2,)),
+    ("zfill", "", (3,)),
+    ("isdigit", "123", ()),
+    ("isdigit", "12a", ()),
+    ("isdigit", "", ()),
+    ("isdigit", "x²", ()),
+    ("isdigit", "²₃", ()),
+    ("isalpha", "héllo", ()),
+    ("isalpha", "ab1", ()),
+    ("isalnum", "ab12", ()),
+    ("isalnum", "ab 12", ()),
+    ("isspace", " \t\n", ()),
+    ("isspace", "", ()),
+    ("isupper", "ABC1", ()),
+    ("isupper", "AbC", ()),
+    ("isupper", "123", ()),
+    ("islower", "abc1", ()),
+    ("islower", "aBc", ()),
+    ("partition", "key=value=more", ("=",)),
+    ("partition", "novalue", ("=",)),
+    ("rpartition", "key=value=more", ("=",)),
+    ("rpartition", "novalue", ("=",)),
+]
+
+GO_STRING_METHODS = {
+    "startswith": "StartsWith",
+    "endswith": "EndsWith",
+    "rpartition": "RPartition",
+    "isdigit": "IsDigit",
+    "isalpha": "IsAlpha",
+    "isalnum": "IsAlnum",
+    "isspace": "IsSpace",
+    "isupper": "IsUpper",
+    "islower": "IsLower",
+}
+
+
+class TestGoStringMethodCoverage:
+    """Test the common str methods beyond search and padding."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_method_conversion(self):
+        """Test each method lowers to StrOps, with tuples of prefixes passed as variadic arguments."""
+        python_code = """
+def check(name: str, parts: list[str]) -> bool:
+    joined = ", ".join(parts)
+    key = name.partition("=")[0]
+    print(joined, key.title(), name.count("a"), name.zfill(8))
+    if name.isdigit():
+        return False
+    return name.startswith(("http", "ftp"))
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert 'joined := mgen.StrOps.Join(", ", parts)' in go_code
+        assert 'var key string = mgen.StrOps.Partition(name, "=")[0]' in go_code
+        assert 'mgen.StrOps.Title(key), mgen.StrOps.Count(name, "a"), mgen.StrOps.Zfill(name, 8)' in go_code
+        assert 'mgen.StrOps.StartsWith(name, "http", "ftp")' in go_code
+        assert "mgen.StrOps.IsDigit(name)" in go_code
+
+    def test_list_methods_keep_list_meaning(self):
+        """Test count/index on a list are not lowered to the str versions."""
+        python_code = """
+def f(xs: list[int]) -> int:
+    return xs.count(2)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.StrOps.Count" not in go_code
+
+    def test_methods_match_python(self, go_run):
+        """Test every case prints what CPython computes."""
+        body, expected = [], []
+        for method, text, args in STRING_METHOD_CASES:
+            go_args = [json.dumps(arg, ensure_ascii=False) if isinstance(arg, str) else str(arg) for arg in args]
+            go_name = GO_STRING_METHODS.get(method, method.capitalize())
+            call = f"mgen.StrOps.{go_name}({', '.join([json.dumps(text, ensure_ascii=False), *go_args])})"
+            body.append(f"    mgen.Print(mgen.Repr({call}))")
+            result = getattr(text, method)(*args)
+            expected.append(repr(list(result) if isinstance(result, tuple) else result))
+        output = go_run("\n".join(body))
+
+        assert output.splitlines() == expected
+
+    def test_join_of_dynamic_items(self, go_run):
+        """Test JoinValues joins str items and raises TypeError on others like CPython."""
+        output = go_run(
+            """
+    mgen.Print(mgen.StrOps.JoinValues("-", []interface{}{"a", "b"}), mgen.StrOps.JoinValues("-", mgen.NewPyList("c")))
+    defer func() { mgen.Print(recover().(error).Error()) }()
+    mgen.StrOps.JoinValues(", ", []interface{}{"a", 1})
+"""
+        )
+        assert output.splitlines() == ["a-b c", "TypeError: sequence item 1: expected str instance, int found"]
+
+
+# (text, tabsize) pairs compared against CPython's str.expandtabs
+EXPANDTABS_CASES = [
+    ("a\tb", 8),
+    ("ab\t\tc", 4),
+    ("name\tage\nbob\t42\n\tindented", 8),
+    ("x\r\ty", 4),
+    ("日本\tz", 4),
+    ("no tabs", 8),
+    ("a\tb\tc", 0),
+    ("a\tb", 1),
+]
+
+
+class TestGoExpandTabs:
+    """Test str.expandtabs conversion and tab-stop semantics."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_expandtabs_conversion(self):
+        """Test expandtabs maps to StrOps with Python's default tab size of 8."""
+        python_code = """
+def table(row: str) -> str:
+    return row.expandtabs() + row.expandtabs(4)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "mgen.StrOps.ExpandTabs(row, 8) + mgen.StrOps.ExpandTabs(row, 4)" in go_code
+
+    def test_expandtabs_matches_python(self, go_run):
+        """Test tab stops reset at newlines and count code points like CPython."""
+        body = "\n".join(
+            f"    mgen.Print(mgen.Repr(mgen.StrOps.ExpandTabs({json.dumps(text, ensure_ascii=False)}, {size})))"
+            for text, size in EXPANDTABS_CASES
+        )
+        output = go_run(body)
+        # Compare reprs so the "\r" case survives newline translation of the captured output
+        assert output.splitlines() == [repr(text.expandtabs(size)) for text, size in EXPANDTABS_CASES]
@@ -0,0 +1,145 @@
+"""Tests for the Go backend's string.Template support."""
+
+import json
+from string import Template
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+MAPPING = {"name": "world", "who": "Ada", "n": 3, "x_1": 2.5}
+
+TEMPLATES = [
+    "Hello, $name!",
+    "${who} has ${n} items",
+    "$n$n ${x_1}x",
+    "Price: $$10 for $who",
+    "$nameless",
+    "${name}less",
+    "no placeholders",
+    "$",
+    "line one\nsecond $1",
+    "a ${name",
+    "${missing} and $who",
+]
+
+
+def python_result(fn):
+    """Return repr() of the substitution or the raised error as the Go runtime formats it."""
+    try:
+        return repr(fn())
+    except (KeyError, ValueError) as e:
+        return f"{type(e).__name__}: {e}"
+
+
+def go_mapping() -> str:
+    """Return MAPPING as a Go map literal."""
+    items = ", ".join(f"{json.dumps(k)}: {json.dumps(v)}" for k, v in MAPPING.items())
+    return f"map[string]interface{{}}{{{items}}}"
+
+
+class TestGoTemplate:
+    """Test Template matches string.Template's substitute and safe_substitute."""
+
+    @pytest.mark.parametrize("method", ["Substitute", "SafeSubstitute"])
+    def test_substitute_matches_python(self, go_run, method):
+        """Test $name, ${name} and $$ forms, missing names and invalid placeholders."""
+        lines = [f"    mapping := {go_mapping()}"]
+        for text in TEMPLATES:
+            lines.append(
+                f"    check(func() interface{{}} {{ return mgen.NewTemplate({json.dumps(text)}).{method}(mapping) }})"
+            )
+        output = go_run("\n".join(lines))
+
+        python_method = "substitute" if method == "Substitute" else "safe_substitute"
+        expected = [
+            python_result(lambda t=text: getattr(Template(t), python_method)(MAPPING)) for text in TEMPLATES
+        ]
+        assert output.splitlines() == expected
+
+    def test_invalid_placeholder_position(self, go_run):
+        """Test ValueError reports the line and column of an invalid placeholder."""
+        texts = ["ok $who\n\n  $-", "é$ ", "a\r\nb $"]
+        lines = []
+        for text in texts:
+            lines.append(
+                "    check(func() interface{} { return mgen.NewTemplate("
+                f'{json.dumps(text)}).Substitute(map[string]string{{"who": "x"}}) }})'
+            )
+        output = go_run("\n".join(lines))
+
+        expected = [python_result(lambda t=text: Template(t).substitute(who="x")) for text in texts]
+        assert output.splitlines() == expected
+
+    def test_dict_mappings(self, go_run):
+        """Test PyDict mappings and values rendered with str()."""
+        output = go_run(
+            """
+    d := mgen.NewPyDict(mgen.PyDictEntry{Key: "xs", Value: 2.0}, mgen.PyDictEntry{Key: "ok", Value: true})
+    t := mgen.NewTemplate("$xs is ${ok}, $other")
+    mgen.Print(t.SafeSubstitute(d))
+    mgen.Print(t.Template)
+"""
+        )
+        assert output.splitlines() == ["2.0 is True, $other", "$xs is ${ok}, $other"]
+
+
+class TestGoTemplateConversion:
+    """Test Template() and its substitutions lower to mgen.Template."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_template_codegen(self):
+        """Test the constructor, both substitutions and Template annotations."""
+        python_code = """
+import string
+from string import Template
+
+
+def render(t: Template, values: dict[str, str]) -> str:
+    return t.substitute(values) + string.Template("$x").safe_substitute(x=1)
+
+
+def main() -> None:
+    print(render(Template("$who"), {"who": "Ada"}))
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func render(t mgen.Template, values *mgen.Dict[string, string]) string {" in go_code
+        assert (
+            'return (t.Substitute(values) + mgen.NewTemplate("$x").SafeSubstitute('
+            'mgen.NewPyDict(mgen.PyDictEntry{Key: "x", Value: 1})))'
+        ) in go_code
+        assert 'render(mgen.NewTemplate("$who"), ' in go_code
+
+    def test_substitutions_end_to_end(self, go_run_python):
+        """Test mappings, keyword arguments, $$ and a missing name against CPython."""
+        python_code = """
+import string
+from string import Template
+
+
+def greet(t: Template, who: str) -> str:
+    return t.substitute({"who": who})
+
+
+def main() -> None:
+    t = Template("Hello, $who! $$5 for ${item}")
+    values: dict[str, str] = {"who": "Ada", "item": "tea"}
+    print(t.substitute(values), t.template)
+    print(t.safe_substitute(who="Bob"))
+    print(t.substitute(who="Cy", item=3))
+    other = string.Template("$n items")
+    print(other.substitute(n=2), greet(Template("hi $who"), "Di"))
+    try:
+        print(t.substitute({"who": "x"}))
+    except KeyError as e:
+        print("KeyError", e)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
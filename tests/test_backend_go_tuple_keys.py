@@ -0,0 +1,264 @@
+"""Tests for Go backend dicts and sets keyed by tuples or mixed numeric kinds."""
+
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+
+# Grid bookkeeping with (x, y) keys; sets are printed sorted, since their
+# insertion order differs from CPython's hash order
+TUPLE_KEY_PROGRAM = """
+def neighbours(cells: set[tuple[int, int]]) -> dict[tuple[int, int], int]:
+    counts: dict[tuple[int, int], int] = {}
+    for x, y in cells:
+        for dx in range(-1, 2):
+            key = (x + dx, y)
+            counts[key] = counts.get(key, 0) + 1
+    return counts
+
+
+def main() -> None:
+    cells = {(0, 0), (1, 0), (5, 5)}
+    counts = neighbours(cells)
+    print(repr(sorted(counts)), max(counts), min(cells), counts[(1, 0)])
+    for (x, y), n in counts.items():
+        if n > 1:
+            print(x, y, n)
+    labels = {(i, i * 2, "p"): i for i in range(3)}
+    print(labels, labels[(1, 2, "p")], (2, 4, "p") in labels)
+    for a, b, c in labels:
+        print(a + b, c)
+    pairs = {(a, b) for a in range(2) for b in range(2) if a != b}
+    print(repr(sorted(pairs)), (0, 1) in pairs, (1, 1) not in pairs)
+    first = max(counts)
+    print(sorted(counts, reverse=True)[0] == first, first > (6, 4), counts.pop((5, 5)), len(counts))
+    del counts[(0, 0)]
+    seen = set()
+    seen.add((first[1], "a"))
+    print(seen, first[0], first[-1], {v: k for k, v in labels.items()})
+"""
+
+
+class TestGoTupleKeyConversion:
+    """Test tuple-keyed literals lower to Tuple2/Tuple3 keys, or to the value-hashed PyDict/PySet."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_tuple_key_dict_literal(self):
+        """Test {(1, 2): "a"} builds a Dict keyed by the comparable Tuple2."""
+        python_code = """
+def lookup() -> None:
+    grid = {(1, 2): "a"}
+    grid[(3, 4)] = "b"
+    print(grid[(1, 2)], len(grid), (3, 4) in grid)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert (
+            "var grid *mgen.Dict[mgen.Tuple2[int, int], string] = mgen.NewDict("
+            'mgen.KV[mgen.Tuple2[int, int], string]{Key: mgen.Tuple2[int, int]{First: 1, Second: 2}, Value: "a"})'
+        ) in go_code
+        assert 'grid.Set(mgen.Tuple2[int, int]{First: 3, Second: 4}, "b")' in go_code
+        assert (
+            "mgen.Print(grid.Get(mgen.Tuple2[int, int]{First: 1, Second: 2}), grid.Len(), "
+            "grid.Contains(mgen.Tuple2[int, int]{First: 3, Second: 4}))"
+        ) in go_code
+
+    def test_tuple_values_and_empty_containers(self):
+        """Test tuple variables are converted with ToTuple2 and {} / set() take their tuple key type."""
+        python_code = """
+def track(x: int, name: str) -> None:
+    pos = (x, x + 1)
+    dist = {}
+    dist[pos] = 0
+    dist[(x, 2)] += 1
+    seen = set()
+    seen.add((x, name, 1.5))
+    print(dist.get(pos, -1), (x, name, 1.5) in seen)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "var dist *mgen.Dict[mgen.Tuple2[int, int], int] = mgen.NewDict[mgen.Tuple2[int, int], int]()" in go_code
+        assert "dist.Set(mgen.ToTuple2[int, int](pos), 0)" in go_code
+        assert (
+            "dist.Set(mgen.Tuple2[int, int]{First: x, Second: 2}, "
+            "(dist.Get(mgen.Tuple2[int, int]{First: x, Second: 2}) + 1))"
+        ) in go_code
+        assert "seen.Add(mgen.Tuple3[int, string, float64]{First: x, Second: name, Third: 1.5})" in go_code
+        assert "dist.GetOr(mgen.ToTuple2[int, int](pos), (-1))" in go_code
+
+    def test_iterate_tuple_key_dict(self):
+        """Test tuple targets unpack the fields of Tuple2 keys and of KV items."""
+        python_code = """
+def walk() -> None:
+    grid = {(1, 2): "a"}
+    for key in grid:
+        print(key)
+    for x, y in grid:
+        print(y)
+    for (x, y), label in grid.items():
+        print(label)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "for _, key := range grid.Keys() {" in go_code
+        # x is unused in the body, so it is discarded
+        assert "for _, tuple := range grid.Keys() {\n    _, y := tuple.First, tuple.Second" in go_code
+        assert "for _, item := range grid.Items() {\n    label := item.Value" in go_code
+
+    def test_mixed_tuple_keys_use_py_dict(self):
+        """Test tuple keys whose item types differ by position stay value-hashed."""
+        python_code = """
+def f() -> None:
+    d = {(1, "a"): 1, (2.5, "b"): 2}
+    s = {(1, 2), (1, 2, 3)}
+    nested = {((1, 2), 3): "x"}
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert (
+            "var d *mgen.PyDict = mgen.NewPyDict(mgen.PyDictEntry{Key: mgen.PyTuple{1, \"a\"}, Value: 1}, "
+            "mgen.PyDictEntry{Key: mgen.PyTuple{2.5, \"b\"}, Value: 2})"
+        ) in go_code
+        assert "var s *mgen.PySet = mgen.NewPySet(mgen.PyTuple{1, 2}, mgen.PyTuple{1, 2, 3})" in go_code
+        assert "var nested *mgen.PyDict = " in go_code
+
+    def test_mixed_numeric_keys_use_py_dict(self):
+        """Test {1: ..., 1.0: ..., True: ...} is value-hashed and 1.0 stays a float."""
+        python_code = """
+def f() -> None:
+    d = {1: "a", 1.0: "b", True: "c"}
+    s = {1, 1.0, 2}
+    same = {1: "a", 2: "b"}
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert (
+            'var d *mgen.PyDict = mgen.NewPyDict(mgen.PyDictEntry{Key: 1, Value: "a"}, '
+            'mgen.PyDictEntry{Key: 1.0, Value: "b"}, mgen.PyDictEntry{Key: true, Value: "c"})'
+        ) in go_code
+        assert "var s *mgen.PySet = mgen.NewPySet(1, 1.0, 2)" in go_code
+        # A single key kind still lowers to a typed dict
+        assert (
+            'var same *mgen.Dict[int, string] = mgen.NewDict(mgen.KV[int, string]{Key: 1, Value: "a"}, '
+            'mgen.KV[int, string]{Key: 2, Value: "b"})'
+        ) in go_code
+
+    def test_tuple_set_literal_and_annotation(self):
+        """Test tuple sets and tuple-keyed annotations map to Tuple2 keys, or PyDict for tuple[int, ...]."""
+        python_code = """
+def track(moves: dict[tuple[int, int], int], path: dict[tuple[int, ...], str]) -> bool:
+    seen = {(0, 0), (0, 1)}
+    return (0, 1) not in seen
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func track(moves *mgen.Dict[mgen.Tuple2[int, int], int], path *mgen.PyDict) bool {" in go_code
+        assert (
+            "mgen.NewSet[mgen.Tuple2[int, int]](mgen.Tuple2[int, int]{First: 0, Second: 0}, "
+            "mgen.Tuple2[int, int]{First: 0, Second: 1})"
+        ) in go_code
+        assert "return !seen.Contains(mgen.Tuple2[int, int]{First: 0, Second: 1})" in go_code
+
+    def test_sorting_and_indexing_tuples(self):
+        """Test sorted/min/max over tuple keys compare item by item at runtime, and t[i] reads a field."""
+        python_code = """
+def f(cells: set[tuple[int, str]]) -> None:
+    first = min(cells)
+    print(sorted(cells), first[0], first[-1])
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert (
+            "mgen.SortedByValue(cells.Items(), func(item mgen.Tuple2[int, string]) interface{} { return item }, false)"
+        ) in go_code
+        assert "mgen.MinBy(cells.Items(), nil).(mgen.Tuple2[int, string])" in go_code
+        assert "first.First, first.Second)" in go_code
+
+
+class TestGoTupleKeyRuntime:
+    """Test tuple keys and value-based hashing against Python dict/set behavior."""
+
+    def test_tuple_key_program(self, go_run_python):
+        """Test a program keyed by Tuple2/Tuple3 matches CPython."""
+        assert go_run_python(TUPLE_KEY_PROGRAM) == python_output(TUPLE_KEY_PROGRAM + "\nmain()\n")
+
+    def test_tuple_structs(self, go_run):
+        """Test Tuple2/Tuple3 print, compare and hash like Python tuples, and ToTuple2 checks its items."""
+        output = go_run(
+            """
+    p := mgen.Tuple3[int, float64, string]{First: 1, Second: 2.5, Third: "x"}
+    q := mgen.ToTuple2[float64, int]([]interface{}{1, 2})
+    mgen.Print(p, q, mgen.Eq(p, []interface{}{1, 2.5, "x"}), mgen.Eq(q, mgen.Pair[int, int]{First: 1, Second: 2}))
+    mgen.Print(mgen.Compare("<", p, []interface{}{1, 3, "a"}), mgen.Compare(">", q, []interface{}{1, 2}))
+    d := mgen.NewPyDict(mgen.PyDictEntry{Key: []interface{}{1, 2}, Value: "a"})
+    mgen.Print(d.Contains(q), mgen.Contains(mgen.NewSet(q), []interface{}{1, 2.0}), mgen.Repr(mgen.ToList(q)))
+    check(func() { mgen.ToTuple2[int, int]([]interface{}{1, "a"}) })
+    check(func() { mgen.ToTuple3[int, int, int]([]interface{}{1, 2}) })
+    check(func() { mgen.Compare("<", q, 1) })
+"""
+        )
+        assert output.splitlines() == [
+            "(1, 2.5, 'x') (1.0, 2) True True",
+            "True False",
+            "True True [1.0, 2]",
+            "TypeError: tuple item must be int, not str",
+            "ValueError: not enough values to unpack (expected 3, got 2)",
+            "TypeError: '<' not supported between instances of 'tuple' and 'int'",
+        ]
+
+    def test_tuple_dict_end_to_end(self, go_run_python):
+        """Test a tuple-keyed dict keeps insertion order and finds equal tuples."""
+        python_code = """
+def main() -> None:
+    grid = {(1, 2): "a", (0, 0): "origin"}
+    grid[(3, 4)] = "b"
+    grid[(1, 2)] = "c"
+    total = 0
+    for key in grid:
+        total += 1
+    for pos, label in grid.items():
+        print(label)
+    del grid[(0, 0)]
+    print(grid, total, grid.get((5, 5), "none"))
+    seen = {(1, 2), (1, 2), (2, 1)}
+    print(len(seen), (2, 1) in seen, seen)
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "c",
+            "origin",
+            "b",
+            "{(1, 2): 'c', (3, 4): 'b'} 3 none",
+            "2 True {(1, 2), (2, 1)}",
+        ]
+
+    def test_hash_key_equality(self, go_run):
+        """Test 1, 1.0 and True hash alike, and lists are unhashable."""
+        output = go_run(
+            """
+    d := mgen.NewPyDict(mgen.PyDictEntry{Key: []interface{}{1, "x"}, Value: 1})
+    mgen.Print(d.Contains([]interface{}{1.0, "x"}), d.Contains([]interface{}{true, "x"}), d.Contains([]interface{}{1, "y"}))
+    mgen.Print(mgen.NewPySet([]interface{}{1}), mgen.NewPyDict())
+    defer func() { mgen.Print(recover().(error).Error()) }()
+    d.Get([]interface{}{2, 3})
+"""
+        )
+        assert output.splitlines() == ["True True False", "{(1,)} {}", "KeyError: (2, 3)"]
+
+    def test_numeric_keys_collide_end_to_end(self, go_run_python):
+        """Test 1, 1.0 and True are one key, keeping the first key and the last value."""
+        python_code = """
+def main() -> None:
+    d = {1: "a", 1.0: "b", True: "c"}
+    print(d, len(d), d[1.0])
+    f = {1.0: "a", 1: "b", 2.5: "c", False: "d", 0: "e"}
+    print(f, len(f), f[True])
+    s = {1, 1.0, True, 2}
+    print(s, len(s), 2.0 in s)
+"""
+        assert go_run_python(python_code).splitlines() == [
+            "{1: 'c'} 1 c",
+            "{1.0: 'b', 2.5: 'c', False: 'e'} 3 b",
+            "{1, 2} 2 True",
+        ]
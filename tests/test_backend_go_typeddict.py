@@ -0,0 +1,376 @@
+"""Tests for the Go runtime's generic insertion-ordered Dict[K, V]."""
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+# Exercises dict order-sensitive operations; the Go output must match CPython's
+DICT_PROGRAM = """
+def count_words(words: list[str]) -> dict[str, int]:
+    counts: dict[str, int] = {}
+    for w in words:
+        counts[w] = counts.get(w, 0) + 1
+    return counts
+
+
+def main() -> None:
+    d = {"b": 2, "a": 1, "c": 3}
+    d["d"] = 4
+    d["b"] += 10
+    print(d["a"], len(d), "a" in d, "z" not in d, d.get("z", 0))
+    for k in d:
+        print(k, d[k])
+    for k, v in d.items():
+        print(k, v)
+    del d["a"]
+    print(d.pop("c"), d.pop("c", -1), d.setdefault("q", 9), d)
+    print(repr(list(d)), repr(list(d.values())), repr(sorted(d)))
+    counts = count_words(["pear", "fig", "pear", "apple", "fig", "pear"])
+    print(counts, max(counts, key=counts.get), min(counts), repr(sorted(counts, key=counts.get)))
+    squares = {k: v * v for k, v in counts.items() if v > 1}
+    lengths = {w: len(w) for w in counts}
+    print(squares, lengths, repr([k for k in lengths]), squares == {"pear": 9, "fig": 4}, squares != lengths)
+    e = dict()
+    e[3] = 0.5
+    copied = dict(e)
+    copied[1] = 1.5
+    e.update(copied)
+    print(e, copied, e == copied, f"{e}")
+    e.clear()
+    print(e, len(e), bool(e))
+"""
+
+# Every dict method on typed dicts and value-hashed (mixed numeric key) dicts
+DICT_METHODS_PROGRAM = """
+def main() -> None:
+    d = {"a": 1, "b": 2, "c": 3}
+    print(d.get("a", 0), d.get("z", 0), d.setdefault("d", 4), d.setdefault("a", 9))
+    print(d.pop("b"), d.pop("zz", -1), d)
+    item = d.popitem()
+    print(item, d)
+    last = d.popitem()
+    print(last, d)
+    d.update({"x": 10, "a": 5})
+    d.update([("y", 20)])
+    d.update(z=30)
+    e = d.copy()
+    e["a"] = 100
+    print(d, e, len(e))
+    for key in d.keys():
+        print(key, d[key])
+    for val in d.values():
+        print(val)
+    for key, val in d.items():
+        print(key, val)
+    print(list(d.items()), list(d.keys()), list(d.values()))
+    value_hashed()
+    mixed()
+    d.clear()
+    print(d, len(d))
+    try:
+        d.popitem()
+    except KeyError as ex:
+        print("KeyError", ex)
+    try:
+        d.pop("q")
+    except KeyError as ex:
+        print("KeyError", ex)
+
+
+
+
+def value_hashed() -> None:
+    d = {1: "a", 2.5: "b"}
+    print(d.get(1.0), d.get(9), d.setdefault(3, "c"), d.setdefault(4.5))
+    print(d.pop(2.5), d.pop(0, "none"), d.popitem(), d)
+    d.update({1: "z"})
+    d.update([(2, "y")])
+    d.update(k="w")
+    e = d.copy()
+    e.clear()
+    print(d, e, len(d))
+    try:
+        e.popitem()
+    except KeyError as ex:
+        print("KeyError", ex)
+    try:
+        d.pop(0.5)
+    except KeyError as ex:
+        print("KeyError", ex)
+
+
+def mixed() -> None:
+    prices: dict[str, float] = {"a": 1.5}
+    prices.update([("b", 2.0)])
+    prices.update({"c": 3.0})
+    print(prices)
+    try:
+        prices.update([("d", 1, 2)])
+    except ValueError as ex:
+        print("ValueError", ex)
+"""
+
+
+class TestGoDictLowering:
+    """Test dict literals, comprehensions and methods lower to the ordered Dict."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_literals_and_comprehensions(self):
+        """Test dict displays, {} and comprehensions build typed Dicts."""
+        python_code = """
+def f(xs: list[str]) -> dict[str, int]:
+    ages = {"ann": 3, "bob": 4}
+    empty: dict[str, float] = {}
+    lengths = {x: len(x) for x in xs}
+    return ages
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func f(xs []string) *mgen.Dict[string, int] {" in go_code
+        assert (
+            'var ages *mgen.Dict[string, int] = mgen.NewDict(mgen.KV[string, int]{Key: "ann", Value: 3}, '
+            'mgen.KV[string, int]{Key: "bob", Value: 4})'
+        ) in go_code
+        assert "var empty *mgen.Dict[string, float64] = mgen.NewDict[string, float64]()" in go_code
+        assert "comprehension := mgen.NewDict[string, int]()" in go_code
+
+    def test_methods_and_operators(self):
+        """Test subscripts, membership, len and dict methods call the Dict API."""
+        python_code = """
+def f(d: dict[str, int], k: str) -> int:
+    d[k] = 1
+    d[k] += 2
+    if k in d:
+        print(d.get("x", 0), d.pop(k), len(d))
+    for key in d:
+        print(key)
+    for key, value in d.items():
+        print(key, value)
+    return d[k]
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "d.Set(k, 1)" in go_code
+        assert "d.Set(k, (d.Get(k) + 2))" in go_code
+        assert "if d.Contains(k) {" in go_code
+        assert 'mgen.Print(d.GetOr("x", 0), d.Pop(k), d.Len())' in go_code
+        assert "for _, key := range d.Keys() {" in go_code
+        assert "for _, kv := range d.Items() {" in go_code
+        assert "return d.Get(k)" in go_code
+
+    def test_get_without_default_needs_nullable_value(self):
+        """Test d.get(k) is rejected when the value type cannot hold None."""
+        python_code = """
+def f(d: dict[str, int]) -> None:
+    print(d.get("a"))
+"""
+        with pytest.raises(TypeMappingError, match=r"dict.get\(\) without a default"):
+            self.converter.convert_code(python_code)
+
+    def test_insertion_order_runtime(self, go_run_python):
+        """Test iteration, mutation, comprehensions and printing follow CPython's dict order."""
+        assert go_run_python(DICT_PROGRAM) == python_output(DICT_PROGRAM + "\nmain()\n")
+
+
+class TestGoDictMethods:
+    """Test popitem(), update() sources and the value-hashed PyDict's dict methods."""
+
+    def test_update_sources(self):
+        """Test update() merges a dict of its own type directly and converts pairs and keywords."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+def f(d: dict[str, int], other: dict[str, int], pairs: list[tuple[str, int]]) -> None:
+    d.update(other)
+    d.update(pairs)
+    d.update(a=1, b=2)
+    print(d.popitem())
+"""
+        )
+
+        assert "d.Update(other)" in go_code
+        assert "d.UpdateFrom(pairs)" in go_code
+        assert (
+            'd.Update(mgen.NewDict(mgen.KV[string, int]{Key: "a", Value: 1}, mgen.KV[string, int]{Key: "b", Value: 2}))'
+        ) in go_code
+        assert "mgen.Print(d.PopItem())" in go_code
+
+    def test_update_keywords_need_string_keys(self):
+        """Test keyword arguments cannot update a dict whose keys are not strings."""
+        with pytest.raises(TypeMappingError, match=r"dict.update\(\) keywords need string keys"):
+            MGenPythonToGoConverter().convert_code("def f(d: dict[int, int]) -> None:\n    d.update(a=1)\n")
+
+    def test_value_hashed_dict_methods(self):
+        """Test dict methods on a PyDict default a missing value to nil."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+def f() -> None:
+    d = {1: "a", 2.5: "b"}
+    print(d.get(1), d.setdefault(3), d.pop(1, None), d.popitem(), d.copy())
+"""
+        )
+
+        assert "d.GetOr(1, nil), d.SetDefault(3, nil), d.PopOr(1, nil), d.PopItem(), d.Copy()" in go_code
+
+    def test_dict_methods_runtime(self, go_run_python):
+        """Test every dict method, including the KeyErrors, prints what CPython prints."""
+        assert go_run_python(DICT_METHODS_PROGRAM) == python_output(DICT_METHODS_PROGRAM + "\nmain()\n")
+
+    def test_dict_field_methods(self):
+        """Test subscripts and dict methods on self.names lower like they do on a local dict."""
+        go_code = MGenPythonToGoConverter().convert_code(
+            """
+class Registry:
+    def __init__(self) -> None:
+        self.names: dict[str, int] = {}
+
+    def add(self, k: str, v: int) -> int:
+        self.names[k] = v
+        self.names[k] += 1
+        print(self.names.get(k, 0), self.names.setdefault(k, 7))
+        return self.names[k]
+"""
+        )
+
+        assert "obj.Names.Set(k, v)" in go_code
+        assert "obj.Names.Set(k, (obj.Names.Get(k) + 1))" in go_code
+        assert "mgen.Print(obj.Names.GetOr(k, 0), obj.Names.SetDefault(k, 7))" in go_code
+        assert "return obj.Names.Get(k)" in go_code
+
+    def test_dict_field_runtime(self, go_run_python):
+        """Test a class keeping its entries in a dict field prints what CPython prints."""
+        python_code = """
+class Registry:
+    def __init__(self) -> None:
+        self.names: dict[str, int] = {}
+
+    def add(self, k: str, v: int) -> None:
+        self.names[k] = v
+
+    def lookup(self, k: str) -> int:
+        return self.names[k]
+
+    def find(self, k: str) -> int:
+        return self.names.get(k, 0)
+
+    def ensure(self, k: str) -> int:
+        return self.names.setdefault(k, 7)
+
+    def bump(self, k: str) -> None:
+        self.names[k] += 1
+
+    def drop(self, k: str) -> int:
+        return self.names.pop(k)
+
+
+def main() -> None:
+    r = Registry()
+    r.add("a", 1)
+    r.bump("a")
+    print(r.lookup("a"), r.find("a"), r.find("b"), r.ensure("c"), r.ensure("a"))
+    print(r.drop("c"), len(r.names), "a" in r.names)
+
+
+main()
+"""
+        assert go_run_python(python_code) == python_output(python_code)
+
+
+class TestGoTypedDictRuntime:
+    """Test Dict keeps Python's dict semantics with typed keys and values."""
+
+    def test_dict_operations(self, go_run):
+        """Test lookups, defaults, deletion and iteration order match a Python dict."""
+        output = go_run(
+            """
+    d := mgen.NewDict(mgen.KV[string, int]{Key: "b", Value: 2}, mgen.KV[string, int]{Key: "a", Value: 1})
+    d.Set("c", 3)
+    d.Set("b", 20)
+    mgen.Print(d, d.Len(), d.Get("b"), d.GetOr("z", -1), d.Contains("a"), d.Contains("z"))
+    mgen.Print(d.SetDefault("a", 100), d.SetDefault("d", 4), d)
+    mgen.Print(d.Pop("a"), d.PopOr("a", 0), mgen.Repr(d.Keys()), mgen.Repr(d.Values()))
+    d.Set("a", 5)
+    for _, kv := range d.Items() {
+        mgen.Print(kv.Key, kv.Value)
+    }
+    other := d.Copy()
+    other.Delete("b")
+    other.Update(mgen.NewDict(mgen.KV[string, int]{Key: "b", Value: 20}))
+    mgen.Print(other, d.Equal(other), other.Equal(mgen.NewDict[string, int]()))
+    check(func() { d.Get("z") })
+    check(func() { d.Delete("z") })
+    d.Clear()
+    mgen.Print(d, d.Len())
+"""
+        )
+        d = {"b": 2, "a": 1}
+        d["c"] = 3
+        d["b"] = 20
+        lines = [f"{d} 3 20 -1 True False"]
+        lines.append(f"{d.setdefault('a', 100)} {d.setdefault('d', 4)} {d}")
+        lines.append(f"{d.pop('a')} {d.pop('a', 0)} {list(d)} {list(d.values())}")
+        d["a"] = 5
+        lines += [f"{k} {v}" for k, v in d.items()]
+        other = d.copy()
+        del other["b"]
+        other.update({"b": 20})
+        lines.append(f"{other} {d == other} False")
+        lines += ["KeyError: 'z'", "KeyError: 'z'", "{} 0"]
+        assert output.splitlines() == lines
+
+    def test_pydict_conversion(self, go_run):
+        """Test converting to and from the dynamic PyDict keeps order and checks types."""
+        output = go_run(
+            """
+    d := mgen.NewDict[int, string]()
+    for _, k := range []int{3, 1, 2} {
+        d.Set(k, mgen.ToStr(k*k))
+    }
+    boxed := d.ToPyDict()
+    mgen.Print(boxed, boxed.Get(1))
+    back := mgen.DictFromPyDict[int, string](boxed)
+    mgen.Print(back, back.Equal(d))
+    mgen.Print(mgen.DictFromPyDict[string, interface{}](mgen.NewPyDict(mgen.PyDictEntry{Key: "x", Value: nil})))
+    check(func() { mgen.DictFromPyDict[string, string](boxed) })
+    check(func() { mgen.DictFromPyDict[int, int](boxed) })
+"""
+        )
+        assert output.splitlines() == [
+            "{3: '9', 1: '1', 2: '4'} 1",
+            "{3: '9', 1: '1', 2: '4'} True",
+            "{'x': None}",
+            "TypeError: unexpected key type 'int'",
+            "TypeError: unexpected value type 'str'",
+        ]
+
+    def test_ordered_insertion_agrees_with_py_dict(self, go_run):
+        """Test ordered insertion and iteration of Dict give what the boxed PyDict gives.
+
+        BenchmarkDictInsertion in the runtime's mgen_go_bench_test.go times the
+        two (make benchmark-go-runtime).
+        """
+        output = go_run(
+            """
+    const n = 2000
+    typed := mgen.NewDict[int, int]()
+    boxed := mgen.NewPyDict()
+    for i := 0; i < n; i++ {
+        typed.Set(i*7%500, typed.GetOr(i*7%500, 0)+i)
+        boxed.Set(i*7%500, boxed.GetOr(i*7%500, 0).(int)+i)
+    }
+    typedSum := 0
+    for _, kv := range typed.Items() {
+        typedSum += kv.Key * kv.Value
+    }
+    boxedSum := 0
+    for _, e := range boxed.Items() {
+        boxedSum += e.Key.(int) * e.Value.(int)
+    }
+    mgen.Print(typed.Len(), typedSum == boxedSum, mgen.Eq(typed.Keys(), boxed.Keys()))
+"""
+        )
+        assert output.strip() == "500 True True"
@@ -0,0 +1,162 @@
+"""Tests for the Go runtime's generic insertion-ordered Set[T]."""
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+# Exercises set algebra and mutation; members are small ints added in ascending
+# order (or printed sorted), where CPython's hash order matches insertion order
+SET_PROGRAM = """
+def unique_words(words: list[str]) -> set[str]:
+    seen = set()
+    for w in words:
+        seen.add(w)
+    return seen
+
+
+def main() -> None:
+    a = {1, 2, 3}
+    b = {3, 4, 5}
+    print(a | b, a & b, a - b, b - a, a ^ b)
+    print(len(a), 2 in a, 7 not in a, a <= a | b, a < a, a == {3, 2, 1}, a != b)
+    print(a.union([6], {7}), a.intersection(b), a.issubset({0, 1, 2, 3}), a.isdisjoint({6}))
+    c = set()
+    c.add(1)
+    c.add(1)
+    c.update([2, 3])
+    c.discard(9)
+    c.remove(2)
+    print(c, c.pop(), c, sum(a), min(b), max(b))
+    c |= {4}
+    c ^= {3, 5}
+    print(c, repr(sorted(c)), repr(list(c)))
+    if not set():
+        print("empty", bool(a))
+    squares = {x * x for x in range(3)}
+    pairs = {x + y for x in a for y in b if x != y}
+    print(squares, repr(sorted(pairs)), set([1, 2, 1]))
+    words = unique_words(["pear", "fig", "pear"])
+    print(repr(sorted(words)), "fig" in words, len(words), f"{set()}")
+"""
+
+
+class TestGoSetLowering:
+    """Test set literals, comprehensions, methods and operators lower to the ordered Set."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_literals_and_comprehensions(self):
+        """Test set displays, set() and comprehensions build typed Sets."""
+        python_code = """
+def f(xs: list[str]) -> set[str]:
+    primes = {2, 3, 5}
+    empty: set[float] = set()
+    lengths = {len(x) for x in xs}
+    return set(xs)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func f(xs []string) *mgen.Set[string] {" in go_code
+        assert "var primes *mgen.Set[int] = mgen.NewSet[int](2, 3, 5)" in go_code
+        assert "var empty *mgen.Set[float64] = mgen.NewSet[float64]()" in go_code
+        assert "comprehension := mgen.NewSet[int]()" in go_code
+        assert "return mgen.NewSet(xs...)" in go_code
+
+    def test_methods_and_operators(self):
+        """Test membership, len, methods and the set operators call the Set API."""
+        python_code = """
+def f(s: set[int], t: set[int], x: int) -> bool:
+    s.add(x)
+    s.discard(x)
+    s |= t
+    s -= {1}
+    if x in s:
+        print(s | t, s & t, s - t, s ^ t, len(s), s.union([1, 2]))
+    for item in s:
+        print(item, s <= t)
+    return s.issubset(t)
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert "s.Add(x)" in go_code
+        assert "s.Discard(x)" in go_code
+        assert "s.Update(t)" in go_code
+        assert "s.DifferenceUpdate(mgen.NewSet[int](1))" in go_code
+        assert "if s.Contains(x) {" in go_code
+        assert (
+            "mgen.Print(s.Union(t), s.Intersection(t), s.Difference(t), s.SymmetricDifference(t), s.Len(), "
+        ) in go_code
+        assert "s.Union(mgen.NewSet([]int{1, 2}...))" in go_code
+        assert "for _, item := range s.Items() {" in go_code
+        assert 'mgen.Print(item, mgen.Compare("<=", s, t))' in go_code
+        assert "return s.IsSubset(t)" in go_code
+
+    def test_operand_of_another_type_is_rejected(self):
+        """Test a set method operand must hold the set's member type."""
+        python_code = """
+def f(s: set[int]) -> None:
+    s.update(["a"])
+"""
+        with pytest.raises(TypeMappingError, match=r"Set operand must be"):
+            self.converter.convert_code(python_code)
+
+    def test_set_program_runtime(self, go_run_python):
+        """Test set algebra, mutation and printing match CPython."""
+        assert go_run_python(SET_PROGRAM) == python_output(SET_PROGRAM + "\nmain()\n")
+
+
+class TestGoTypedSetRuntime:
+    """Test Set keeps Python's set semantics with typed members."""
+
+    def test_set_operations(self, go_run):
+        """Test membership, mutation and the set algebra keep insertion order."""
+        output = go_run(
+            """
+    s := mgen.NewSet("b", "a", "b")
+    s.Add("c")
+    mgen.Print(s, s.Len(), s.Contains("a"), s.Contains("z"), mgen.Repr(s.Items()))
+    t := mgen.NewSet("c", "d")
+    mgen.Print(s.Union(t), s.Intersection(t), s.Difference(t), s.SymmetricDifference(t))
+    mgen.Print(s.IsSubset(s.Union(t)), s.IsSuperset(t), s.IsDisjoint(mgen.NewSet("x")), s.Equal(s.Copy()))
+    u := s.Copy()
+    u.IntersectionUpdate(t)
+    u.SymmetricDifferenceUpdate(mgen.NewSet("d", "e"))
+    mgen.Print(u, s.Pop(), s)
+    check(func() { s.Remove("z") })
+    s.Clear()
+    check(func() { s.Pop() })
+    mgen.Print(s, mgen.NewSet[int]())
+"""
+        )
+        assert output.splitlines() == [
+            "{'b', 'a', 'c'} 3 True False ['b', 'a', 'c']",
+            "{'b', 'a', 'c', 'd'} {'c'} {'b', 'a'} {'b', 'a', 'd'}",
+            "True False True True",
+            "{'c', 'd', 'e'} b {'a', 'c'}",
+            "KeyError: 'z'",
+            "KeyError: 'pop from an empty set'",
+            "set() set()",
+        ]
+
+    def test_dynamic_helpers_accept_sets(self, go_run):
+        """Test Eq, Compare, Contains, ToBool and list() treat a Set like a Python set."""
+        output = go_run(
+            """
+    a := mgen.NewSet(1, 2)
+    boxed := mgen.NewPySet(2, 1)
+    mgen.Print(mgen.Eq(a, boxed), mgen.Compare("<", mgen.NewSet(1), a), mgen.Compare(">=", a, mgen.NewSet(3)))
+    mgen.Print(mgen.Contains(a, 2.0), mgen.Contains(a, "2"), mgen.ToBool(a), mgen.ToBool(mgen.NewSet[string]()))
+    mgen.Print(mgen.Repr(mgen.ToList(a)), mgen.ToSet([]interface{}{1, 1.0, true, "x"}), mgen.LenValue(a))
+    check(func() { mgen.HashKey(a) })
+"""
+        )
+        assert output.splitlines() == [
+            "True True False",
+            "True False True False",
+            "[1, 2] {1, 'x'} 2",
+            "TypeError: unhashable type: 'set'",
+        ]
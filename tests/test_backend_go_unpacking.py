@@ -0,0 +1,108 @@
+"""Tests for tuple unpacking, swaps, nested and starred assignment targets in the Go backend."""
+
+import pytest
+from conftest import python_output
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+UNPACKING_PROGRAM = """
+class Point:
+    def __init__(self, x: int, y: int) -> None:
+        self.x = x
+        self.y = y
+
+    def swap(self) -> None:
+        self.x, self.y = self.y, self.x
+
+    def total(self) -> int:
+        a, b = self.x, self.y
+        return a + b
+
+
+def stats(xs: list[int]) -> tuple[int, int, float]:
+    lo = xs[0]
+    hi = xs[0]
+    for x in xs:
+        if x < lo:
+            lo = x
+        if x > hi:
+            hi = x
+    return lo, hi, float(hi) * 0.5
+
+
+def fib(n: int) -> int:
+    a, b = 0, 1
+    for i in range(n):
+        a, b = b, a + b
+    return a
+
+
+def main() -> None:
+    p = Point(1, 2)
+    p.swap()
+    print(p.x, p.y, p.total())
+    lo, hi, half = stats([3, 1, 4, 1, 5])
+    print(lo, hi, half, stats([1, 2]))
+    print(fib(10))
+    (x, y), z = (1, 2), 3
+    print(x, y, z)
+    rows = [[1, 2], [3, 4]]
+    (a, b), (c, d) = rows
+    print(a + d, b * c)
+    xs = [1, 2, 3, 4]
+    first, *rest = xs
+    *init, last = xs
+    h, *mid, t = xs
+    rest.append(9)
+    print(first, rest, init, last, h, mid, t, xs)
+    xs[0], xs[1] = xs[1], xs[0]
+    print(xs)
+    m, n = "ab"
+    print(m, n)
+    try:
+        e, f = [1, 2, 3]
+        print(e, f)
+    except ValueError as err:
+        print("error:", err)
+    try:
+        g, h2, *i = [1]
+        print(g, h2, i)
+    except ValueError as err:
+        print("error:", err)
+"""
+
+
+class TestGoUnpacking:
+    """Test assignments to tuple and list targets unpack like Python."""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_unpacking_codegen(self):
+        """Test displays assign in parallel, records unpack through fields and slices keep their item type."""
+        go_code = self.converter.convert_code(UNPACKING_PROGRAM)
+
+        assert "func stats(xs []int) mgen.Tuple3[int, int, float64] {" in go_code
+        assert "return mgen.Tuple3[int, int, float64]{First: lo, Second: hi, Third:" in go_code
+        assert "unpack3 := stats([]int{3, 1, 4, 1, 5})\n    lo := unpack3.First\n    hi := unpack3.Second" in go_code
+        assert "a, b := 0, 1" in go_code
+        assert "a, b = b, (a + b)" in go_code
+        assert "x, y, z := 1, 2, 3" in go_code
+        assert "unpack1 := obj.Y\n    unpack2 := obj.X\n    obj.X = unpack1\n    obj.Y = unpack2" in go_code
+        assert "first := unpack7[0]\n    rest := append([]int{}, unpack7[1:]...)" in go_code
+        assert "mid := append([]int{}, unpack9[1:len(unpack9)-1]...)\n    t := unpack9[len(unpack9)-1]" in go_code
+        assert "unpack5 := mgen.UnpackSlice(unpack4[0], 2)\n    a := unpack5[0]" in go_code
+        assert "unpack10 := xs[1]\n    unpack11 := xs[0]\n    xs[0] = unpack10\n    xs[1] = unpack11" in go_code
+        assert 'mgen.UnpackN("ab", 2)' in go_code
+
+    def test_unpacking_end_to_end(self, go_run_python):
+        """Test the unpacked values and ValueErrors match Python's."""
+        assert go_run_python(UNPACKING_PROGRAM) == python_output(UNPACKING_PROGRAM + "\nmain()\n")
+
+    def test_multiple_starred_targets(self):
+        """Test a target with two starred names is rejected."""
+        python_code = "def main() -> None:\n    xs = [1, 2, 3]\n    *a, *b = xs\n    print(a, b)\n"
+        with pytest.raises(TypeMappingError, match="multiple starred expressions in assignment"):
+            self.converter.convert_code(python_code)
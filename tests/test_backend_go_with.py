@@ -0,0 +1,188 @@
+"""Tests for with statements in the Go backend."""
+
+import re
+
+import pytest
+
+from mgen.backends.go.converter import MGenPythonToGoConverter
+from mgen.errors import TypeMappingError
+
+
+class TestGoWithStatement:
+    """Test with statements over files and classes defining __enter__ and __exit__."""
+
+    TIMER = """
+class Timer:
+    def __init__(self, name: str):
+        self.name = name
+        self.ticks = 0
+
+    def __enter__(self) -> "Timer":
+        print("enter", self.name)
+        return self
+
+    def __exit__(self, exc_type, exc, tb) -> bool:
+        print("exit", self.name, exc_type is None)
+        return isinstance(exc, ValueError)
+
+
+class Resource:
+    def __enter__(self) -> int:
+        return 42
+
+    def __exit__(self, exc_type, exc, tb) -> None:
+        print("released")
+"""
+
+    def setup_method(self):
+        """Set up test fixtures."""
+        self.converter = MGenPythonToGoConverter()
+
+    def test_file_codegen(self):
+        """Test a file is closed by a deferred Close, and a return leaves through the closure."""
+        python_code = """
+def load(path: str) -> str:
+    with open(path) as f:
+        return f.read()
+"""
+        go_code = self.converter.convert_code(python_code)
+
+        assert (
+            "    var f *mgen.PyFile\n"
+            "    if tryDone1, tryResult1 := func() (tryDone1 bool, tryResult1 string) {\n"
+            '    f = mgen.Open(path, "r")\n'
+            "    defer f.Close()\n"
+            "    return true, f.Read()\n"
+            "    }(); tryDone1 {\n"
+            "    return tryResult1\n"
+            "    }"
+        ) in go_code
+
+    def test_class_codegen(self):
+        """Test __exit__ is deferred with the recovered exception, and __enter__'s result is bound."""
+        python_code = (
+            self.TIMER
+            + """
+def main() -> None:
+    with Timer("t") as t, Resource() as n:
+        print(t.name, n)
+"""
+        )
+        go_code = self.converter.convert_code(python_code)
+
+        assert "func (obj *Timer) PyExit(exc_type interface{}, exc interface{}, tb interface{}) bool {" in go_code
+        assert 'return mgen.IsInstance(exc, "ValueError")' in go_code
+        assert (
+            '    t = NewTimer("t")\n'
+            "    t.PyEnter()\n"
+            "    defer func() {\n"
+            "    _exc := recover()\n"
+            "    if !t.PyExit(mgen.ExitArgs(_exc)) && _exc != nil {\n"
+            "    panic(_exc)\n"
+            "    }\n"
+            "    }()\n"
+            "    withManager3 := NewResource()\n"
+            "    n = withManager3.PyEnter()\n"
+            "    defer func() {\n"
+            "    _exc := recover()\n"
+            "    withManager3.PyExit(mgen.ExitArgs(_exc))\n"
+            "    if _exc != nil {\n"
+            "    panic(_exc)\n"
+            "    }\n"
+            "    }()\n"
+        ) in go_code
+
+    def test_with_end_to_end(self, go_run_python):
+        """Test exit order, suppression by __exit__ and propagation of unsuppressed exceptions."""
+        python_code = (
+            self.TIMER
+            + """
+def risky(n: int) -> int:
+    with Timer("risky") as t:
+        t.ticks = t.ticks + n
+        if n > 2:
+            raise ValueError("too big")
+        if n > 1:
+            raise KeyError(n)
+    return t.ticks
+
+
+def main() -> None:
+    with open("notes.txt", "w") as f:
+        f.write("hello\\n")
+    with open("notes.txt") as f:
+        print(f.read().strip())
+    with Timer("outer") as a, Timer("inner") as b:
+        a.ticks = a.ticks + 1
+        b.ticks = b.ticks + 2
+    print(a.ticks, b.ticks)
+    print(risky(1))
+    print(risky(3))
+    try:
+        with Resource() as r:
+            print(risky(2) + r)
+    except KeyError as e:
+        print("caught", e)
+"""
+        )
+        assert go_run_python(python_code).splitlines() == [
+            "hello",
+            "enter outer",
+            "enter inner",
+            "exit inner True",
+            "exit outer True",
+            "1 2",
+            "enter risky",
+            "exit risky True",
+            "1",
+            "enter risky",
+            "exit risky False",
+            "3",
+            "enter risky",
+            "exit risky False",
+            "released",
+            "caught 2",
+        ]
+
+    def test_manager_bound_as_r(self, go_run_python):
+        """Test a manager bound to r is not shadowed by the exception its deferred exit recovers."""
+        python_code = (
+            self.TIMER
+            + """
+def main() -> None:
+    with Timer("r") as r:
+        r.ticks = r.ticks + 1
+        raise ValueError("suppressed")
+    print(r.ticks)
+"""
+        )
+        assert go_run_python(python_code) == "enter r\nexit r False\n1\n"
+
+    def test_unsupported_context_manager(self):
+        """Test a value without __enter__ and __exit__ is rejected at transpile time."""
+        python_code = """
+def f(x: int) -> None:
+    with x:
+        print(x)
+"""
+        with pytest.raises(TypeMappingError, match="Unsupported context manager: x"):
+            self.converter.convert_code(python_code)
+
+    def test_exit_arity_error(self):
+        """Test an __exit__ that cannot take the exception details is rejected like Python's TypeError."""
+        python_code = """
+class Bad:
+    def __enter__(self) -> "Bad":
+        return self
+
+    def __exit__(self) -> None:
+        pass
+
+
+def f() -> None:
+    with Bad():
+        print(1)
+"""
+        message = "TypeError: __exit__() takes 1 positional argument but 4 were given"
+        with pytest.raises(TypeMappingError, match=re.escape(message)):
+            self.converter.convert_code(python_code)
@@ -30,6 +30,8 @@ from mgen.frontend import (
     VectorizationDetector,
     analyze_python_code,
     build_ir_from_code,
+    collect_type_vars,
+    function_type_vars,
 )
 
 
@@ -154,6 +156,58 @@ class TestTypeInference:
         assert result.confidence > 0.8
 
 
+class TestTypeVarTracking:
+    """Test the TypeVars a module declares and the functions generic over them."""
+
+    def test_collect_type_vars(self):
+        """Test TypeVar declarations keep their constraints and bound."""
+        import ast
+
+        module = ast.parse(
+            """
+import typing
+from typing import TypeVar
+
+T = TypeVar("T")
+N = TypeVar("N", int, float)
+B = typing.TypeVar("B", bound="Base")
+limit = 3
+"""
+        )
+        type_vars = collect_type_vars(module)
+
+        assert list(type_vars) == ["T", "N", "B"]
+        assert type_vars["T"].constraints == () and type_vars["T"].bound is None
+        assert [ast.unparse(item) for item in type_vars["N"].constraints] == ["int", "float"]
+        assert ast.unparse(type_vars["B"].bound) == "'Base'"
+
+    def test_function_type_vars(self):
+        """Test functions are generic over the TypeVars their signature uses, in order of first use."""
+        import ast
+
+        module = ast.parse(
+            """
+from typing import Callable, TypeVar
+
+T = TypeVar("T")
+U = TypeVar("U")
+
+
+def apply(f: Callable[[T], U], x: T) -> U:
+    return f(x)
+
+
+def count(xs: list[int]) -> int:
+    return len(xs)
+"""
+        )
+        type_vars = collect_type_vars(module)
+        apply, count = module.body[-2:]
+
+        assert [type_var.name for type_var in function_type_vars(apply, type_vars)] == ["T", "U"]
+        assert function_type_vars(count, type_vars) == []
+
+
 class TestConstraintChecker:
     """Test the static constraint checker."""
 
@@ -0,0 +1,95 @@
+"""Tests for project module discovery, import resolution and compilation order."""
+
+import tempfile
+from pathlib import Path
+
+import pytest
+
+from mgen.common.module_system import ModuleResolver
+from mgen.errors import CircularImportError, ErrorCode
+
+
+PACKAGE = {
+    "app/__init__.py": "",
+    "app/geo/__init__.py": "",
+    "app/geo/shapes.py": "from ..util import square\n\n\ndef area(w: int) -> int:\n    return square(w)\n",
+    "app/util.py": "import math\n\n\ndef square(x: int) -> int:\n    return x * x\n",
+    "app/main.py": "from app.geo.shapes import area\nfrom . import util\n\n\ndef main() -> None:\n    print(area(2))\n",
+    "app/__pycache__/stale.py": "",
+}
+
+
+class TestModuleDiscovery:
+    """Test the modules of a project directory and their dependencies are found."""
+
+    def test_package(self, write_project):
+        """Test a directory with an __init__.py is a package whose modules are named below it."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            write_project(Path(tmpdir), PACKAGE)
+            resolver = ModuleResolver()
+            names = resolver.discover_package(Path(tmpdir) / "app")
+            modules = resolver.discovered_modules
+
+            assert names == ["app", "app.geo", "app.geo.shapes", "app.main", "app.util"]
+            assert modules["app.geo.shapes"].dependencies == {"app", "app.util"}
+            assert modules["app.main"].dependencies == {"app", "app.geo", "app.geo.shapes", "app.util"}
+            assert modules["app.util"].dependencies == set()
+            assert modules["app.util"].imports == ["math"]
+            assert list(modules["app.util"].functions) == ["square"]
+
+    def test_source_directory(self, write_project):
+        """Test a directory without an __init__.py is a source root, as when running a script from it."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            write_project(
+                Path(tmpdir),
+                {"main.py": "import shapes\n", "shapes.py": "from lib.maths import add\n", "lib/maths.py": ""},
+            )
+            resolver = ModuleResolver()
+
+            assert resolver.discover_package(Path(tmpdir)) == ["lib.maths", "main", "shapes"]
+            assert resolver.discovered_modules["shapes"].dependencies == {"lib.maths"}
+
+    def test_relative_imports(self, write_project):
+        """Test relative imports resolve against the importing module's package."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            write_project(Path(tmpdir), PACKAGE)
+            resolver = ModuleResolver()
+            resolver.discover_package(Path(tmpdir) / "app")
+            import_from = next(
+                node for node in resolver.discovered_modules["app.geo.shapes"].ast_module.body if node.level
+            )
+
+            modules = resolver.discovered_modules
+
+            assert resolver.resolve_from_module(modules["app.geo.shapes"], import_from) == "app.util"
+            assert resolver.resolve_from_module(modules["app"], import_from) is None
+
+
+class TestCompilationOrder:
+    """Test modules are ordered after the project modules they import, and cycles are reported."""
+
+    def test_dependencies_first(self, write_project):
+        """Test each module comes after its dependencies."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            write_project(Path(tmpdir), PACKAGE)
+            resolver = ModuleResolver()
+            resolver.discover_package(Path(tmpdir) / "app")
+
+            assert resolver.get_compilation_order() == ["app", "app.geo", "app.util", "app.geo.shapes", "app.main"]
+
+    def test_circular_import(self, write_project):
+        """Test a cycle is reported with the modules in it and the import closing it."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            write_project(
+                Path(tmpdir), {"a.py": "import b\n", "b.py": "\nfrom c import f\n", "c.py": "from a import g\n"}
+            )
+            resolver = ModuleResolver()
+            resolver.discover_package(Path(tmpdir))
+
+            with pytest.raises(CircularImportError) as error:
+                resolver.get_compilation_order()
+
+            assert str(error.value).startswith("Circular import: a -> b -> c -> a (")
+            assert str(error.value).endswith("c.py:1 imports a)")
+            assert error.value.context.error_code == ErrorCode.E4002
+            assert error.value.context.location.line == 1
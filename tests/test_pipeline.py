@@ -200,4 +200,34 @@ class TestPipelineIntegration:
                     assert len(content) > 0
 
             finally:
-                Path(temp_python_file).unlink()
\ No newline at end of file
+                Path(temp_python_file).unlink()
+
+class TestPackageConversion:
+    """Test converting a project directory, one module at a time."""
+
+    def test_module_per_source(self, write_project):
+        """Test each module becomes its own source, in dependency order, where the backend maps it."""
+        with tempfile.TemporaryDirectory() as temp_dir:
+            root = Path(temp_dir)
+            write_project(
+                root / "project",
+                {
+                    "main.py": "from geo.shapes import area\n\n\ndef main() -> int:\n    return area(2)\n",
+                    "geo/shapes.py": "def area(w: int) -> int:\n    return w * w\n",
+                },
+            )
+            result = MGenPipeline(target_language="c").convert(root / "project", root / "out")
+
+            assert result.success, result.errors
+            sources = [Path(path).relative_to(root / "out").as_posix() for path in result.generated_files]
+            assert [source for source in sources if source.endswith(".c")] == ["geo/shapes.c", "main.c"]
+
+    def test_circular_import(self, write_project):
+        """Test modules importing each other in a cycle fail with the cycle named."""
+        with tempfile.TemporaryDirectory() as temp_dir:
+            root = Path(temp_dir)
+            write_project(root / "project", {"a.py": "from b import g\n", "b.py": "from a import f\n"})
+            result = MGenPipeline(target_language="c").convert(root / "project", root / "out")
+
+            assert not result.success
+            assert result.errors[0].startswith("Circular import: a -> b -> a (")